@@ -0,0 +1,88 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+
+	"github.com/enigma-id/go/rest"
+)
+
+// ContextKey of the principal on rest.Context.
+const ContextKey = "principal"
+
+// Principal is the authenticated identity of the request.
+type Principal struct {
+	ID       string                 `json:"id"`
+	Roles    []string               `json:"roles,omitempty"`
+	Scopes   []string               `json:"scopes,omitempty"`
+	TenantID string                 `json:"tenant_id,omitempty"`
+	Claims   map[string]interface{} `json:"-"`
+}
+
+// HasRole returns true when the principal has any of the roles.
+func (p *Principal) HasRole(roles ...string) bool {
+	if p == nil {
+		return false
+	}
+
+	for _, r := range roles {
+		for _, pr := range p.Roles {
+			if r == pr {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// HasScopes returns true when the principal has all of the scopes.
+func (p *Principal) HasScopes(scopes ...string) bool {
+	if p == nil {
+		return len(scopes) == 0
+	}
+
+	for _, s := range scopes {
+		found := false
+		for _, ps := range p.Scopes {
+			if s == ps {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Set places the principal on rest.Context and request context.
+func Set(c *rest.Context, p *Principal) {
+	c.Set(ContextKey, p)
+	req := c.Request()
+	c.SetRequest(req.WithContext(WithPrincipal(req.Context(), p)))
+}
+
+// Get returns principal of the request, nil when not authenticated.
+func Get(c *rest.Context) *Principal {
+	p, _ := rest.GetAs[*Principal](c, ContextKey)
+	return p
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns context holding the principal.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns principal of the context, nil when not set.
+func FromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
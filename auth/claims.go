@@ -0,0 +1,126 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// ErrNoSubject returned by the mapper when the token doesn't have id claim.
+var ErrNoSubject = errors.New("auth: token has no subject")
+
+type (
+	// ClaimsMapper converts claims of the valid token into principal.
+	ClaimsMapper func(*jwt.Token) (*Principal, error)
+
+	// PrincipalClaims can be implemented by custom claims type
+	// to map itself into principal.
+	PrincipalClaims interface {
+		Principal() (*Principal, error)
+	}
+
+	// ClaimNames are the claims read into the principal,
+	// the first claim present in the token is used.
+	ClaimNames struct {
+		ID       []string
+		Roles    []string
+		Scopes   []string
+		TenantID []string
+	}
+)
+
+var (
+	// DefaultClaimNames reads id from "sub" or "id", roles from "roles"
+	// or "role", scopes from "scope" or "scopes" and tenant from "tenant_id".
+	DefaultClaimNames = ClaimNames{
+		ID:       []string{"sub", "id"},
+		Roles:    []string{"roles", "role"},
+		Scopes:   []string{"scope", "scopes"},
+		TenantID: []string{"tenant_id"},
+	}
+
+	// DefaultClaimsMapper maps the claims using DefaultClaimNames.
+	DefaultClaimsMapper = MapClaims(DefaultClaimNames)
+)
+
+// MapClaims returns mapper reading the claim names, roles and scopes can be
+// array or space/comma separated string. Claims implementing PrincipalClaims
+// map themselves, other custom claims are read through their json.
+func MapClaims(names ClaimNames) ClaimsMapper {
+	return func(t *jwt.Token) (*Principal, error) {
+		if pc, ok := t.Claims.(PrincipalClaims); ok {
+			return pc.Principal()
+		}
+
+		claims, err := claimsMap(t.Claims)
+		if err != nil {
+			return nil, err
+		}
+
+		p := &Principal{Claims: claims}
+		if p.ID = claimString(claims, names.ID); p.ID == "" {
+			return nil, ErrNoSubject
+		}
+		p.TenantID = claimString(claims, names.TenantID)
+		p.Roles = claimList(claims, names.Roles)
+		p.Scopes = claimList(claims, names.Scopes)
+
+		return p, nil
+	}
+}
+
+func claimsMap(c jwt.Claims) (map[string]interface{}, error) {
+	if mc, ok := c.(jwt.MapClaims); ok {
+		return mc, nil
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]interface{})
+	return m, json.Unmarshal(b, &m)
+}
+
+func claimList(claims map[string]interface{}, names []string) (list []string) {
+	for _, n := range names {
+		switch v := claims[n].(type) {
+		case string:
+			return strings.FieldsFunc(v, func(r rune) bool { return r == ' ' || r == ',' })
+		case []interface{}:
+			for _, r := range v {
+				if s, ok := r.(string); ok {
+					list = append(list, s)
+				}
+			}
+			return
+		}
+	}
+
+	return
+}
+
+func claimString(claims map[string]interface{}, names []string) string {
+	for _, n := range names {
+		switch v := claims[n].(type) {
+		case string:
+			if v != "" {
+				return v
+			}
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		case json.Number:
+			return v.String()
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/enigma-id/go/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+type customClaims struct {
+	UserID int64  `json:"uid"`
+	Group  string `json:"group"`
+	jwt.StandardClaims
+}
+
+type principalClaims struct {
+	jwt.StandardClaims
+}
+
+func (c principalClaims) Principal() (*Principal, error) {
+	return &Principal{ID: c.Subject, Roles: []string{"service"}}, nil
+}
+
+func TestDefaultClaimsMapper(t *testing.T) {
+	p, err := DefaultClaimsMapper(&jwt.Token{Claims: jwt.MapClaims{
+		"sub":       "42",
+		"roles":     []interface{}{"admin", "staff"},
+		"scope":     "orders:read orders:write",
+		"tenant_id": "acme",
+	}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "42", p.ID)
+		assert.Equal(t, []string{"admin", "staff"}, p.Roles)
+		assert.Equal(t, []string{"orders:read", "orders:write"}, p.Scopes)
+		assert.Equal(t, "acme", p.TenantID)
+		assert.Equal(t, "acme", p.Claims["tenant_id"])
+	}
+
+	// legacy numeric id and single role string
+	p, err = DefaultClaimsMapper(&jwt.Token{Claims: jwt.MapClaims{"id": float64(7), "role": "admin, staff"}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "7", p.ID)
+		assert.Equal(t, []string{"admin", "staff"}, p.Roles)
+		assert.Empty(t, p.TenantID)
+	}
+
+	_, err = DefaultClaimsMapper(&jwt.Token{Claims: jwt.MapClaims{"name": "John"}})
+	assert.Equal(t, ErrNoSubject, err)
+}
+
+func TestMapClaims(t *testing.T) {
+	mapper := MapClaims(ClaimNames{ID: []string{"uid"}, Roles: []string{"group"}})
+
+	p, err := mapper(&jwt.Token{Claims: &customClaims{UserID: 12, Group: "finance"}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "12", p.ID)
+		assert.Equal(t, []string{"finance"}, p.Roles)
+	}
+
+	p, err = mapper(&jwt.Token{Claims: principalClaims{jwt.StandardClaims{Subject: "svc-billing"}}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "svc-billing", p.ID)
+		assert.True(t, p.HasRole("service"))
+	}
+}
+
+func TestPrincipal(t *testing.T) {
+	p := &Principal{ID: "1", Roles: []string{"admin"}}
+	assert.True(t, p.HasRole("staff", "admin"))
+	assert.False(t, p.HasRole("staff"))
+	assert.False(t, (*Principal)(nil).HasRole("admin"))
+
+	p.Scopes = []string{"orders:read", "orders:write"}
+	assert.True(t, p.HasScopes("orders:write", "orders:read"))
+	assert.True(t, p.HasScopes())
+	assert.False(t, p.HasScopes("orders:read", "orders:delete"))
+
+	c := rest.New().NewContext(httptest.NewRequest("GET", "/", nil), httptest.NewRecorder())
+	assert.Nil(t, Get(c))
+	assert.Nil(t, FromContext(context.Background()))
+
+	Set(c, p)
+	assert.Equal(t, p, Get(c))
+	assert.Equal(t, p, FromContext(c.Request().Context()))
+}
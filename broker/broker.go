@@ -0,0 +1,155 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+)
+
+// ErrClosed returned when publishing or subscribing into closed broker.
+var ErrClosed = errors.New("broker: closed")
+
+type (
+	// Publisher publishes the envelope into the topic.
+	Publisher interface {
+		Publish(ctx context.Context, topic string, e *Envelope) error
+		Close() error
+	}
+
+	// Subscriber consumes the topic as part of the consumer group, each message
+	// is delivered into one of the subscribers of the same group. The message
+	// is acknowledged only when the handler returns nil, otherwise it will be
+	// redelivered (at least once). Subscribe doesn't block, consuming is stopped
+	// when the context is done or the subscriber closed.
+	Subscriber interface {
+		Subscribe(ctx context.Context, topic, group string, h Handler) error
+		Close() error
+	}
+
+	// Broker is both publisher and subscriber.
+	Broker interface {
+		Publisher
+		Subscriber
+	}
+
+	// Handler handles the consumed message, the context carries
+	// trace context of the envelope.
+	Handler func(ctx context.Context, e *Envelope) error
+
+	// Envelope wraps the event payload with its metadata.
+	Envelope struct {
+		ID          string          `json:"id"`
+		Type        string          `json:"type"`
+		Schema      string          `json:"schema,omitempty"`
+		Source      string          `json:"source,omitempty"`
+		Key         string          `json:"key,omitempty"`
+		OccurredAt  time.Time       `json:"occurred_at"`
+		Traceparent string          `json:"traceparent,omitempty"`
+		RequestID   string          `json:"request_id,omitempty"`
+		Data        json.RawMessage `json:"data"`
+
+		// Attempt is delivery attempt of the message, when supported by the driver.
+		Attempt int `json:"-"`
+
+		// Error is the last handler error, set on dead letter.
+		Error string `json:"error,omitempty"`
+	}
+
+	traceKey struct{}
+
+	trace struct {
+		traceparent string
+		requestID   string
+	}
+)
+
+// NewEnvelope creates envelope of the event type with the data encoded as json,
+// trace context is taken from the context set by WithTrace.
+func NewEnvelope(ctx context.Context, typ string, data interface{}) (*Envelope, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Envelope{
+		ID:         NewID(),
+		Type:       typ,
+		Source:     os.Getenv("APP_NAME"),
+		OccurredAt: time.Now().UTC(),
+		Data:       b,
+	}
+
+	if t, ok := ctx.Value(traceKey{}).(trace); ok {
+		e.Traceparent = t.traceparent
+		e.RequestID = t.requestID
+	}
+
+	return e, nil
+}
+
+// Decode decodes data of the envelope into v.
+func (e *Envelope) Decode(v interface{}) error {
+	return json.Unmarshal(e.Data, v)
+}
+
+// Marshal encodes the envelope as json.
+func (e *Envelope) Marshal() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+// Unmarshal decodes json envelope.
+func Unmarshal(b []byte) (*Envelope, error) {
+	e := new(Envelope)
+	if err := json.Unmarshal(b, e); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// Context returns the context with trace of the envelope.
+func (e *Envelope) Context(ctx context.Context) context.Context {
+	if e.Traceparent == "" && e.RequestID == "" {
+		return ctx
+	}
+
+	return WithTrace(ctx, e.Traceparent, e.RequestID)
+}
+
+// WithTrace sets trace context of the envelopes created using the context,
+// ex. from Traceparent and X-Request-ID header of the incoming request.
+func WithTrace(ctx context.Context, traceparent, requestID string) context.Context {
+	return context.WithValue(ctx, traceKey{}, trace{traceparent, requestID})
+}
+
+// Trace returns trace context of the context.
+func Trace(ctx context.Context) (traceparent, requestID string) {
+	t, _ := ctx.Value(traceKey{}).(trace)
+	return t.traceparent, t.requestID
+}
+
+// Publish creates the envelope and publishes it into the topic.
+func Publish(ctx context.Context, p Publisher, topic, typ string, data interface{}) error {
+	e, err := NewEnvelope(ctx, typ, data)
+	if err != nil {
+		return err
+	}
+
+	return p.Publish(ctx, topic, e)
+}
+
+// NewID returns random 128 bits id encoded as hex.
+func NewID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
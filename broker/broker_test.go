@@ -0,0 +1,143 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type order struct {
+	ID    int64   `json:"id"`
+	Total float64 `json:"total"`
+}
+
+func TestEnvelope(t *testing.T) {
+	ctx := WithTrace(context.Background(), "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", "req-1")
+
+	e, err := NewEnvelope(ctx, "order.created", order{1, 25000})
+	assert.NoError(t, err)
+	assert.Len(t, e.ID, 32)
+	assert.Equal(t, "req-1", e.RequestID)
+	assert.False(t, e.OccurredAt.IsZero())
+
+	b, err := e.Marshal()
+	assert.NoError(t, err)
+
+	d, err := Unmarshal(b)
+	assert.NoError(t, err)
+	assert.Equal(t, e.ID, d.ID)
+	assert.Equal(t, e.Traceparent, d.Traceparent)
+
+	var o order
+	assert.NoError(t, d.Decode(&o))
+	assert.Equal(t, order{1, 25000}, o)
+
+	tp, rid := Trace(d.Context(context.Background()))
+	assert.Equal(t, e.Traceparent, tp)
+	assert.Equal(t, "req-1", rid)
+}
+
+func TestMemory(t *testing.T) {
+	m := NewMemory()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	got := map[string][]string{}
+	handler := func(group string) Handler {
+		return func(ctx context.Context, e *Envelope) error {
+			mu.Lock()
+			got[group] = append(got[group], e.Type)
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	assert.NoError(t, m.Subscribe(ctx, "order", "billing", handler("billing")))
+	assert.NoError(t, m.Subscribe(ctx, "order", "billing", handler("billing")))
+	assert.NoError(t, m.Subscribe(ctx, "order", "shipping", handler("shipping")))
+
+	assert.NoError(t, Publish(ctx, m, "order", "order.created", order{ID: 1}))
+	assert.NoError(t, Publish(ctx, m, "order", "order.paid", order{ID: 1}))
+	assert.NoError(t, Publish(ctx, m, "payment", "payment.created", nil))
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(got["billing"]) == 2 && len(got["shipping"]) == 2
+	}, time.Second, 5*time.Millisecond)
+
+	assert.NoError(t, m.Close())
+	assert.Equal(t, ErrClosed, Publish(ctx, m, "order", "order.created", nil))
+}
+
+func TestMemoryRedelivery(t *testing.T) {
+	m := NewMemory()
+	m.RedeliveryDelay = time.Millisecond
+	defer m.Close()
+
+	var attempts int32
+	done := make(chan int)
+	m.Subscribe(context.Background(), "order", "billing", func(ctx context.Context, e *Envelope) error {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			return errors.New("failed")
+		}
+		done <- e.Attempt
+		return nil
+	})
+
+	Publish(context.Background(), m, "order", "order.created", nil)
+
+	select {
+	case n := <-done:
+		assert.Equal(t, 3, n)
+	case <-time.After(time.Second):
+		t.Fatal("message is not redelivered")
+	}
+}
+
+func TestRetry(t *testing.T) {
+	m := NewMemory()
+	defer m.Close()
+
+	dead := make(chan *Envelope, 1)
+	m.Subscribe(context.Background(), "order.dlq", "ops", func(ctx context.Context, e *Envelope) error {
+		dead <- e
+		return nil
+	})
+
+	var n int
+	policy := RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+	h := Retry(policy, m, "order", func(ctx context.Context, e *Envelope) error {
+		n++
+		return errors.New("boom")
+	})
+
+	e, _ := NewEnvelope(context.Background(), "order.created", nil)
+	assert.NoError(t, h(context.Background(), e))
+	assert.Equal(t, 3, n)
+
+	select {
+	case d := <-dead:
+		assert.Equal(t, e.ID, d.ID)
+		assert.Equal(t, "boom", d.Error)
+	case <-time.After(time.Second):
+		t.Fatal("message is not dead lettered")
+	}
+
+	// without dead letter the error is returned
+	h = Retry(policy, nil, "order", func(ctx context.Context, e *Envelope) error { return errors.New("boom") })
+	assert.EqualError(t, h(context.Background(), e), "boom")
+}
+
+func TestBackoff(t *testing.T) {
+	p := RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	assert.Equal(t, 100*time.Millisecond, p.backoff(1))
+	assert.Equal(t, 400*time.Millisecond, p.backoff(3))
+	assert.Equal(t, time.Second, p.backoff(10))
+}
@@ -0,0 +1,145 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package kafka
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/enigma-id/go/broker"
+	"github.com/segmentio/kafka-go"
+)
+
+type (
+	// Config of the kafka broker.
+	Config struct {
+		// Brokers address, default KAFKA_BROKERS env (comma separated) or localhost:9092.
+		Brokers []string
+
+		// RetryBackoff is delay before failed message is handled again, default 1s.
+		RetryBackoff time.Duration
+	}
+
+	// Broker publishes into kafka with the envelope key as partition key,
+	// consumer group is kafka consumer group, offset is committed after the
+	// message handled. Since kafka can't redeliver single message, failed
+	// message is retried until succeed, use broker.Retry to dead letter it.
+	Broker struct {
+		Writer *kafka.Writer
+
+		config  Config
+		mu      sync.Mutex
+		readers []*kafka.Reader
+		wg      sync.WaitGroup
+	}
+)
+
+// New creates kafka broker.
+func New(config Config) *Broker {
+	if len(config.Brokers) == 0 {
+		config.Brokers = []string{"localhost:9092"}
+		if s := os.Getenv("KAFKA_BROKERS"); s != "" {
+			config.Brokers = strings.Split(s, ",")
+		}
+	}
+	if config.RetryBackoff == 0 {
+		config.RetryBackoff = time.Second
+	}
+
+	return &Broker{
+		Writer: &kafka.Writer{
+			Addr:         kafka.TCP(config.Brokers...),
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+		},
+		config: config,
+	}
+}
+
+// Publish writes the envelope into the topic, messages with
+// the same key are kept in order on the same partition.
+func (b *Broker) Publish(ctx context.Context, topic string, e *broker.Envelope) error {
+	data, err := e.Marshal()
+	if err != nil {
+		return err
+	}
+
+	key := e.Key
+	if key == "" {
+		key = e.ID
+	}
+
+	return b.Writer.WriteMessages(ctx, kafka.Message{
+		Topic: topic,
+		Key:   []byte(key),
+		Value: data,
+		Headers: []kafka.Header{
+			{Key: "id", Value: []byte(e.ID)},
+			{Key: "type", Value: []byte(e.Type)},
+		},
+	})
+}
+
+// Subscribe consumes the topic as member of the consumer group.
+func (b *Broker) Subscribe(ctx context.Context, topic, group string, h broker.Handler) error {
+	r := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: b.config.Brokers,
+		GroupID: group,
+		Topic:   topic,
+	})
+
+	b.mu.Lock()
+	b.readers = append(b.readers, r)
+	b.mu.Unlock()
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for {
+			m, err := r.FetchMessage(ctx)
+			if err != nil {
+				// context is done or reader is closed
+				return
+			}
+
+			e, err := broker.Unmarshal(m.Value)
+			if err == nil {
+				for e.Attempt = 1; ; e.Attempt++ {
+					if err = h(e.Context(ctx), e); err == nil {
+						break
+					}
+
+					select {
+					case <-ctx.Done():
+						return
+					case <-time.After(b.config.RetryBackoff):
+					}
+				}
+			}
+
+			// invalid payload will never succeed, so it's committed as well
+			if err = r.CommitMessages(ctx, m); err != nil {
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Close closes the readers and the writer.
+func (b *Broker) Close() error {
+	b.mu.Lock()
+	for _, r := range b.readers {
+		r.Close()
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+	return b.Writer.Close()
+}
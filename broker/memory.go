@@ -0,0 +1,126 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type (
+	// Memory is in process broker, useful on development and testing.
+	// Each group receives every message of the topic, failed message is
+	// redelivered after RedeliveryDelay.
+	Memory struct {
+		RedeliveryDelay time.Duration
+
+		mu     sync.RWMutex
+		groups map[string]map[string]*memoryGroup
+		closed bool
+		wg     sync.WaitGroup
+	}
+
+	memoryGroup struct {
+		ch chan *Envelope
+	}
+)
+
+// NewMemory creates in process broker.
+func NewMemory() *Memory {
+	return &Memory{
+		RedeliveryDelay: 10 * time.Millisecond,
+		groups:          make(map[string]map[string]*memoryGroup),
+	}
+}
+
+// Publish delivers the envelope into all groups of the topic.
+func (m *Memory) Publish(ctx context.Context, topic string, e *Envelope) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.closed {
+		return ErrClosed
+	}
+
+	for _, g := range m.groups[topic] {
+		c := *e
+		select {
+		case g.ch <- &c:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// Subscribe consumes the topic, subscribers of the same group share the messages.
+func (m *Memory) Subscribe(ctx context.Context, topic, group string, h Handler) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return ErrClosed
+	}
+
+	if m.groups[topic] == nil {
+		m.groups[topic] = make(map[string]*memoryGroup)
+	}
+
+	g := m.groups[topic][group]
+	if g == nil {
+		g = &memoryGroup{ch: make(chan *Envelope, 1024)}
+		m.groups[topic][group] = g
+	}
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-g.ch:
+				if !ok {
+					return
+				}
+
+				e.Attempt++
+				if err := h(e.Context(ctx), e); err != nil {
+					time.AfterFunc(m.RedeliveryDelay, func() { m.redeliver(g, e) })
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (m *Memory) redeliver(g *memoryGroup, e *Envelope) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if !m.closed {
+		g.ch <- e
+	}
+}
+
+// Close stops all subscribers.
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	if !m.closed {
+		m.closed = true
+		for _, groups := range m.groups {
+			for _, g := range groups {
+				close(g.ch)
+			}
+		}
+	}
+	m.mu.Unlock()
+
+	m.wg.Wait()
+	return nil
+}
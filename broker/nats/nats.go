@@ -0,0 +1,161 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package nats
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/enigma-id/go/broker"
+	"github.com/nats-io/nats.go"
+)
+
+type (
+	// Config of the nats jetstream broker.
+	Config struct {
+		// URL of nats server, default NATS_HOST env or nats://127.0.0.1:4222.
+		URL string
+
+		// Stream is created with the subjects when not exists yet,
+		// ex. Stream "EVENTS" with Subjects ["order.>", "payment.>"].
+		Stream   string
+		Subjects []string
+
+		// AckWait is duration before unacknowledged message redelivered, default 30s.
+		AckWait time.Duration
+
+		// MaxDeliver is max delivery of a message, default unlimited.
+		MaxDeliver int
+
+		// NakDelay is delay before redelivery of failed message, default 1s.
+		NakDelay time.Duration
+	}
+
+	// Broker publishes and consumes through nats jetstream, consumer group is
+	// durable queue consumer, so it resumes from the last acknowledged message.
+	Broker struct {
+		Conn *nats.Conn
+		JS   nats.JetStreamContext
+
+		config Config
+		mu     sync.Mutex
+		subs   []*nats.Subscription
+	}
+)
+
+// New connects into nats server and ensures the stream.
+func New(config Config, opts ...nats.Option) (*Broker, error) {
+	if config.URL == "" {
+		if config.URL = os.Getenv("NATS_HOST"); config.URL == "" {
+			config.URL = nats.DefaultURL
+		}
+	}
+	if config.AckWait == 0 {
+		config.AckWait = 30 * time.Second
+	}
+	if config.MaxDeliver == 0 {
+		config.MaxDeliver = -1
+	}
+	if config.NakDelay == 0 {
+		config.NakDelay = time.Second
+	}
+
+	nc, err := nats.Connect(config.URL, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	js, err := nc.JetStream()
+	if err != nil {
+		nc.Close()
+		return nil, err
+	}
+
+	if config.Stream != "" {
+		if _, err = js.StreamInfo(config.Stream); err == nats.ErrStreamNotFound {
+			_, err = js.AddStream(&nats.StreamConfig{Name: config.Stream, Subjects: config.Subjects})
+		}
+		if err != nil {
+			nc.Close()
+			return nil, err
+		}
+	}
+
+	return &Broker{Conn: nc, JS: js, config: config}, nil
+}
+
+// Publish publishes the envelope, the envelope id is used as message id
+// so the same envelope published twice is deduplicated by the server.
+func (b *Broker) Publish(ctx context.Context, topic string, e *broker.Envelope) error {
+	data, err := e.Marshal()
+	if err != nil {
+		return err
+	}
+
+	m := nats.NewMsg(topic)
+	m.Data = data
+	m.Header.Set("Content-Type", "application/json")
+	m.Header.Set("Type", e.Type)
+
+	_, err = b.JS.PublishMsg(m, nats.MsgId(e.ID), nats.Context(ctx))
+	return err
+}
+
+// Subscribe consumes the topic using durable queue consumer of the group.
+func (b *Broker) Subscribe(ctx context.Context, topic, group string, h broker.Handler) error {
+	sub, err := b.JS.QueueSubscribe(topic, group, func(m *nats.Msg) {
+		e, err := broker.Unmarshal(m.Data)
+		if err != nil {
+			// invalid payload will never succeed, so it's terminated
+			m.Term()
+			return
+		}
+
+		if meta, err := m.Metadata(); err == nil {
+			e.Attempt = int(meta.NumDelivered)
+		}
+
+		if err = h(e.Context(ctx), e); err != nil {
+			m.NakWithDelay(b.config.NakDelay)
+			return
+		}
+
+		m.Ack()
+	},
+		nats.Durable(durable(group, topic)),
+		nats.ManualAck(),
+		nats.AckWait(b.config.AckWait),
+		nats.MaxDeliver(b.config.MaxDeliver),
+		nats.DeliverAll(),
+	)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.subs = append(b.subs, sub)
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		sub.Drain()
+	}()
+
+	return nil
+}
+
+// Close drains the subscriptions and closes the connection.
+func (b *Broker) Close() error {
+	return b.Conn.Drain()
+}
+
+// durable returns consumer name of the group and topic,
+// the name can't contain dot, star or greater than.
+func durable(group, topic string) string {
+	return strings.NewReplacer(".", "_", "*", "_", ">", "_").Replace(group + "_" + topic)
+}
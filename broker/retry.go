@@ -0,0 +1,77 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"context"
+	"time"
+)
+
+// RetryPolicy of the handler, failed message is retried with exponential
+// backoff then published into dead letter topic when still failing.
+type RetryPolicy struct {
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+
+	// DeadLetter returns dead letter topic of the topic,
+	// default is the topic suffixed by ".dlq".
+	DeadLetter func(topic string) string
+}
+
+// DefaultRetry retries 5 times with backoff between 100ms and 10s.
+var DefaultRetry = RetryPolicy{
+	MaxAttempts: 5,
+	MinBackoff:  100 * time.Millisecond,
+	MaxBackoff:  10 * time.Second,
+}
+
+// Retry wraps the handler with the retry policy, after the last attempt the
+// message is published into dead letter topic using dlq and acknowledged.
+// When dlq is nil, the error is returned so the driver redelivers the message.
+func Retry(policy RetryPolicy, dlq Publisher, topic string, h Handler) Handler {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 1
+	}
+	if policy.DeadLetter == nil {
+		policy.DeadLetter = func(t string) string { return t + ".dlq" }
+	}
+
+	return func(ctx context.Context, e *Envelope) (err error) {
+		for i := 0; i < policy.MaxAttempts; i++ {
+			if i > 0 {
+				t := time.NewTimer(policy.backoff(i))
+				select {
+				case <-ctx.Done():
+					t.Stop()
+					return ctx.Err()
+				case <-t.C:
+				}
+			}
+
+			if err = h(ctx, e); err == nil {
+				return nil
+			}
+		}
+
+		if dlq == nil {
+			return err
+		}
+
+		dead := *e
+		dead.Error = err.Error()
+
+		return dlq.Publish(ctx, policy.DeadLetter(topic), &dead)
+	}
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.MinBackoff << uint(attempt-1)
+	if d <= 0 || (p.MaxBackoff > 0 && d > p.MaxBackoff) {
+		d = p.MaxBackoff
+	}
+
+	return d
+}
@@ -22,6 +22,12 @@ type RedisConfig struct {
 	TimeoutRead    int
 	TimeoutWrite   int
 	DefaultExpire  int
+
+	// Prefix namespaces the keys, Flush only deletes the keys of the prefix.
+	Prefix string
+
+	// AllowFlushAll allows Flush without prefix to flush the whole database.
+	AllowFlushAll bool
 }
 
 func init() {
@@ -36,6 +42,8 @@ func init() {
 		TimeoutRead:    env.GetInt("REDIS_TIMEOUT_READ", 5000),
 		TimeoutWrite:   env.GetInt("REDIS_TIMEOUT_WRITE", 5000),
 		DefaultExpire:  env.GetInt("REDIS_DEFAULT_EXPIRE", 10000),
+		Prefix:         env.GetString("REDIS_PREFIX", ""),
+		AllowFlushAll:  env.GetBool("REDIS_ALLOW_FLUSHALL", false),
 	}
 
 	Instance = NewRedisCache()
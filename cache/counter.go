@@ -0,0 +1,74 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Counter is implemented by the cache incrementing the value atomically,
+// ex. to count the attempts shared between the instances.
+type Counter interface {
+	// Increment adds delta to the integer value of the key and returns the
+	// new value, the missing key starts from zero and expires after the
+	// duration. The expiry of the existing key isn't changed.
+	Increment(key string, delta int64, expires time.Duration) (int64, error)
+}
+
+// ErrNotCounter returned by Increment when the cache isn't a Counter.
+var ErrNotCounter = errors.New("cache: increment is not supported")
+
+// incrScript increments the key and sets the expiry of the new key.
+var incrScript = redis.NewScript(1, `
+local n = redis.call("INCRBY", KEYS[1], ARGV[1])
+if tonumber(ARGV[2]) > 0 and redis.call("PTTL", KEYS[1]) == -1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return n
+`)
+
+// Increment adds delta to the value of the key using the Counter of the cache.
+//
+//	n, err := cache.Increment(cache.Instance, "attempts:"+id, 1, time.Hour)
+func Increment(c Cache, key string, delta int64, expires time.Duration) (int64, error) {
+	if ct, ok := c.(Counter); ok {
+		return ct.Increment(key, delta, expires)
+	}
+	return 0, ErrNotCounter
+}
+
+// Increment adds delta to the value of the key using INCRBY, the counter is
+// stored without the version of WithVersion so read it through Increment.
+func (c RedisCache) Increment(key string, delta int64, expires time.Duration) (int64, error) {
+	switch expires {
+	case DefaultExpiryTime:
+		expires = c.defaultExpiration
+	case ForEverNeverExpiry:
+		expires = time.Duration(0)
+	}
+
+	conn := c.pool.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	return redis.Int64(incrScript.Do(conn, c.prefix+key, delta, int64(expires/time.Millisecond)))
+}
+
+// Increment adds delta to the value of the key, the failure is returned
+// regardless of the fallback since the count can't be served stale.
+func (r *Resilient) Increment(key string, delta int64, expires time.Duration) (int64, error) {
+	v, err := r.do("increment", key, func() (interface{}, error) {
+		return Increment(r.cache, key, delta, expires)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return v.(int64), nil
+}
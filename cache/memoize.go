@@ -0,0 +1,117 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type (
+	// KeyFunc returns the cache key of the arguments of the memoized function.
+	KeyFunc func(args ...interface{}) string
+
+	// LoadFunc loads the value of the arguments on cache miss.
+	LoadFunc func(ctx context.Context, args ...interface{}) (interface{}, error)
+
+	// Memo is read-through cache of a function, ex. the read method of
+	// a repository, the writes should call Forget with the same arguments.
+	Memo struct {
+		cache   Cache
+		expires time.Duration
+		key     KeyFunc
+		load    LoadFunc
+	}
+)
+
+// Memoize returns the memo of the function cached with the expiry, nil cache
+// is Instance at the time of the call so the memo can be declared before the
+// cache is configured. The failure of the cache is treated as miss, unless
+// the cache is Resilient which applies its own fallback.
+//
+//	var findProduct = cache.Memoize(nil, time.Hour, cache.Key("product"),
+//		func(ctx context.Context, args ...interface{}) (interface{}, error) {
+//			return repo.Product(ctx, args[0].(int64))
+//		})
+//
+//	var p Product
+//	err := findProduct.Get(ctx, &p, int64(1))
+func Memoize(c Cache, expires time.Duration, key KeyFunc, fn LoadFunc) *Memo {
+	if key == nil || fn == nil {
+		panic("cache: memoize requires key and load function")
+	}
+
+	return &Memo{cache: c, expires: expires, key: key, load: fn}
+}
+
+// Key returns KeyFunc joining the prefix and the arguments with colon,
+// ex. "product:1" of Key("product")(1).
+func Key(prefix string) KeyFunc {
+	return func(args ...interface{}) string {
+		k := make([]string, len(args)+1)
+		k[0] = prefix
+		for i, a := range args {
+			k[i+1] = fmt.Sprint(a)
+		}
+		return strings.Join(k, ":")
+	}
+}
+
+// Get gets the value of the arguments into ptrValue, on miss the value is
+// loaded and stored in the cache. The error of the loader is not cached.
+func (m *Memo) Get(ctx context.Context, ptrValue interface{}, args ...interface{}) error {
+	c, key := m.of(), m.key(args...)
+	load := func() (interface{}, error) {
+		return m.load(ctx, args...)
+	}
+
+	if r, ok := c.(*Resilient); ok {
+		return r.Fetch(key, ptrValue, m.expires, load)
+	}
+
+	if c != nil && c.Get(key, ptrValue) == nil {
+		return nil
+	}
+
+	v, err := load()
+	if err != nil {
+		return err
+	}
+
+	b, err := Serialize(v)
+	if err != nil {
+		return err
+	}
+	if c != nil {
+		// the loaded value is served even when it can't be stored
+		_ = c.Set(key, b, m.expires)
+	}
+
+	return Deserialize(b, ptrValue)
+}
+
+// Forget deletes the cached value of the arguments,
+// the value that is not cached is not an error.
+func (m *Memo) Forget(args ...interface{}) error {
+	c := m.of()
+	if c == nil {
+		return nil
+	}
+
+	if err := c.Delete(m.key(args...)); err != nil && err != ErrCacheMiss {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Memo) of() Cache {
+	if m.cache != nil {
+		return m.cache
+	}
+	return Instance
+}
@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type product struct {
+	ID   int64
+	Name string
+}
+
+func TestMemoize(t *testing.T) {
+	c := NewMemory()
+	loads := 0
+	missing := errors.New("not found")
+	m := Memoize(c, time.Minute, Key("product"), func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		loads++
+		if id := args[0].(int64); id > 0 {
+			return product{ID: id, Name: "p"}, nil
+		}
+		return nil, missing
+	})
+
+	var p product
+	if err := m.Get(context.Background(), &p, int64(1)); err != nil || p.Name != "p" || loads != 1 {
+		t.Fatalf("expected the value to be loaded, got %+v %v (%d loads)", p, err, loads)
+	}
+	if err := c.Get("product:1", new([]byte)); err != nil {
+		t.Errorf("expected the value to be cached by the key")
+	}
+
+	p = product{}
+	if err := m.Get(context.Background(), &p, int64(1)); err != nil || p.ID != 1 || loads != 1 {
+		t.Errorf("expected the cached value, got %+v %v (%d loads)", p, err, loads)
+	}
+
+	if err := m.Forget(int64(1)); err != nil {
+		t.Errorf("expected forget to succeed, got %v", err)
+	}
+	if err := m.Forget(int64(1)); err != nil {
+		t.Errorf("expected forget of the missing key to succeed, got %v", err)
+	}
+	if err := m.Get(context.Background(), &p, int64(1)); err != nil || loads != 2 {
+		t.Errorf("expected the forgotten value to be loaded, got %v (%d loads)", err, loads)
+	}
+
+	if err := m.Get(context.Background(), &p, int64(0)); err != missing {
+		t.Errorf("expected the error of the loader, got %v", err)
+	}
+	if err := c.Get("product:0", new([]byte)); err != ErrCacheMiss {
+		t.Errorf("expected the error not to be cached")
+	}
+}
+
+func TestMemoizeInstance(t *testing.T) {
+	instance := Instance
+	defer func() { Instance = instance }()
+
+	loads := 0
+	m := Memoize(nil, time.Minute, Key("rate"), func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		loads++
+		return "1.5", nil
+	})
+
+	// served by the loader without the cache
+	Instance = nil
+	var v string
+	if err := m.Get(context.Background(), &v, "usd", "idr"); err != nil || v != "1.5" || loads != 1 {
+		t.Errorf("expected the value to be loaded, got %q %v (%d loads)", v, err, loads)
+	}
+	if err := m.Forget("usd", "idr"); err != nil {
+		t.Errorf("expected forget without cache to succeed, got %v", err)
+	}
+
+	// the failed cache is a miss
+	fc := newFlakyCache()
+	fc.fail(errors.New("connection refused"))
+	Instance = fc
+	if err := m.Get(context.Background(), &v, "usd", "idr"); err != nil || loads != 2 {
+		t.Errorf("expected the failure to be a miss, got %v (%d loads)", err, loads)
+	}
+
+	// resilient applies its fallback
+	Instance = NewResilient(fc, Policy{})
+	if err := m.Get(context.Background(), &v, "usd", "idr"); err == nil || loads != 2 {
+		t.Errorf("expected the error of the cache, got %v (%d loads)", err, loads)
+	}
+
+	fc.fail(nil)
+	if err := m.Get(context.Background(), &v, "usd", "idr"); err != nil || loads != 3 {
+		t.Errorf("expected the value to be loaded, got %v (%d loads)", err, loads)
+	}
+	if err := fc.Memory.Get("rate:usd:idr", new([]byte)); err != nil {
+		t.Errorf("expected the value to be cached by the key")
+	}
+}
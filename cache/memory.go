@@ -0,0 +1,178 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+type (
+	// Memory is the in-process Cache, ex. for the tests or a single
+	// instance service. The values are serialized like RedisCache,
+	// so the callers don't share them.
+	//
+	//	cache.Instance = cache.NewMemory()
+	Memory struct {
+		mu    sync.Mutex
+		items map[string]memoryItem
+	}
+
+	memoryItem struct {
+		b       []byte
+		expires time.Time
+	}
+)
+
+// NewMemory returns empty in-process cache.
+func NewMemory() *Memory {
+	return &Memory{items: make(map[string]memoryItem)}
+}
+
+// Get the content associated with the given key.
+func (c *Memory) Get(key string, ptrValue interface{}) error {
+	c.mu.Lock()
+	b, ok := c.lookup(key)
+	c.mu.Unlock()
+	if !ok {
+		return ErrCacheMiss
+	}
+
+	return Deserialize(b, ptrValue)
+}
+
+// GetMulti the content associated multiple keys at once.
+func (c *Memory) GetMulti(keys ...string) (Getter, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	g := make(RedisItemMapGetter, len(keys))
+	for _, k := range keys {
+		if b, ok := c.lookup(k); ok {
+			g[k] = b
+		}
+	}
+
+	return g, nil
+}
+
+// Set the given key/value in the cache.
+func (c *Memory) Set(key string, value interface{}, expires time.Duration) error {
+	return c.store(key, value, expires, func(bool) error { return nil })
+}
+
+// Add the given key/value to the cache ONLY IF the key does not already exist.
+func (c *Memory) Add(key string, value interface{}, expires time.Duration) error {
+	return c.store(key, value, expires, func(existed bool) error {
+		if existed {
+			return ErrNotStored
+		}
+		return nil
+	})
+}
+
+// Replace the given key/value in the cache ONLY IF the key already exists.
+func (c *Memory) Replace(key string, value interface{}, expires time.Duration) error {
+	return c.store(key, value, expires, func(existed bool) error {
+		if !existed {
+			return ErrNotStored
+		}
+		return nil
+	})
+}
+
+// Delete the given key from the cache, ErrCacheMiss when it doesn't exist.
+func (c *Memory) Delete(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.lookup(key); !ok {
+		return ErrCacheMiss
+	}
+	delete(c.items, key)
+
+	return nil
+}
+
+// Flush expires all cache entries.
+func (c *Memory) Flush() error {
+	c.mu.Lock()
+	c.items = make(map[string]memoryItem)
+	c.mu.Unlock()
+
+	return nil
+}
+
+// Increment adds delta to the integer value of the key, see Counter.
+func (c *Memory) Increment(key string, delta int64, expires time.Duration) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	it, ok := c.items[key]
+	if !ok || it.expired() {
+		it = memoryItem{expires: expiry(expires)}
+	}
+
+	var n int64
+	if it.b != nil {
+		var err error
+		if n, err = strconv.ParseInt(string(it.b), 10, 64); err != nil {
+			return 0, ErrInvalidValue
+		}
+	}
+
+	n += delta
+	it.b = []byte(strconv.FormatInt(n, 10))
+	c.items[key] = it
+
+	return n, nil
+}
+
+// store sets the value when check of the existing key passes.
+func (c *Memory) store(key string, value interface{}, expires time.Duration, check func(existed bool) error) error {
+	b, err := Serialize(value)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.lookup(key)
+	if err = check(ok); err != nil {
+		return err
+	}
+	c.items[key] = memoryItem{b: b, expires: expiry(expires)}
+
+	return nil
+}
+
+// lookup returns value of the key that isn't expired, the lock must be held.
+func (c *Memory) lookup(key string) ([]byte, bool) {
+	it, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	if it.expired() {
+		delete(c.items, key)
+		return nil, false
+	}
+
+	return it.b, true
+}
+
+func (it memoryItem) expired() bool {
+	return !it.expires.IsZero() && !time.Now().Before(it.expires)
+}
+
+// expiry returns the expiry time, DefaultExpiryTime and
+// ForEverNeverExpiry never expire.
+func expiry(d time.Duration) time.Time {
+	if d <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(d)
+}
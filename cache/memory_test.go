@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemory(t *testing.T) {
+	c := NewMemory()
+
+	var v string
+	if err := c.Get("k", &v); err != ErrCacheMiss {
+		t.Errorf("expected miss, got %v", err)
+	}
+	if err := c.Replace("k", "v", 0); err != ErrNotStored {
+		t.Errorf("expected replace of missing key to fail, got %v", err)
+	}
+	if err := c.Add("k", "v", 0); err != nil {
+		t.Errorf("expected add to succeed, got %v", err)
+	}
+	if err := c.Add("k", "w", 0); err != ErrNotStored {
+		t.Errorf("expected add of existing key to fail, got %v", err)
+	}
+	if err := c.Get("k", &v); err != nil || v != "v" {
+		t.Errorf("expected v, got %q %v", v, err)
+	}
+
+	g, _ := c.GetMulti("k", "missing")
+	if err := g.Get("k", &v); err != nil || v != "v" {
+		t.Errorf("expected v of get multi, got %q %v", v, err)
+	}
+	if err := g.Get("missing", &v); err != ErrCacheMiss {
+		t.Errorf("expected miss of get multi, got %v", err)
+	}
+
+	if err := c.Delete("k"); err != nil {
+		t.Errorf("expected delete to succeed, got %v", err)
+	}
+	if err := c.Delete("k"); err != ErrCacheMiss {
+		t.Errorf("expected delete of missing key to miss, got %v", err)
+	}
+
+	c.Set("short", "v", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	if err := c.Get("short", &v); err != ErrCacheMiss {
+		t.Errorf("expected expired key to miss, got %v", err)
+	}
+
+	c.Set("k", "v", 0)
+	c.Flush()
+	if err := c.Get("k", &v); err != ErrCacheMiss {
+		t.Errorf("expected flushed key to miss, got %v", err)
+	}
+}
+
+func TestIncrement(t *testing.T) {
+	c := NewMemory()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			Increment(c, "n", 1, time.Minute)
+		}()
+	}
+	wg.Wait()
+
+	var n int64
+	if err := c.Get("n", &n); err != nil || n != 50 {
+		t.Errorf("expected 50, got %d %v", n, err)
+	}
+	if n, err := Increment(c, "n", -10, time.Minute); err != nil || n != 40 {
+		t.Errorf("expected 40, got %d %v", n, err)
+	}
+
+	c.Set("s", "v", 0)
+	if _, err := Increment(c, "s", 1, 0); err != ErrInvalidValue {
+		t.Errorf("expected invalid value, got %v", err)
+	}
+
+	if n, err := Increment(c, "short", 1, 10*time.Millisecond); err != nil || n != 1 {
+		t.Errorf("expected 1, got %d %v", n, err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if n, _ := Increment(c, "short", 1, 10*time.Millisecond); n != 1 {
+		t.Errorf("expected expired counter to restart, got %d", n)
+	}
+
+	if _, err := Increment(newFlakyCache(), "n", 1, 0); err != nil {
+		t.Errorf("expected embedded memory to count, got %v", err)
+	}
+	if _, err := Increment(struct{ Cache }{NewMemory()}, "n", 1, 0); err != ErrNotCounter {
+		t.Errorf("expected not counter, got %v", err)
+	}
+	if n, err := NewResilient(NewMemory(), Policy{}).Increment("n", 2, 0); err != nil || n != 2 {
+		t.Errorf("expected resilient to count, got %d %v", n, err)
+	}
+}
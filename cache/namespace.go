@@ -0,0 +1,95 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ErrFlushNotAllowed returned by Flush without prefix, unless AllowFlushAll is set,
+// so a service doesn't wipe the shared redis.
+var ErrFlushNotAllowed = errors.New("cache: flush of the whole database is not allowed")
+
+// flushBatch is the COUNT of the SCAN and the keys of each DEL.
+var flushBatch = 500
+
+// WithPrefix namespaces the keys of the cache, ex. "orders:",
+// Flush only deletes the keys of the prefix.
+//
+//	cache.Instance = cache.NewRedisCache(cache.WithPrefix("orders:"))
+func WithPrefix(p string) Option {
+	return func(c *RedisCache) {
+		c.prefix = p
+	}
+}
+
+// AllowFlushAll allows Flush without prefix to flush the whole database,
+// ex. on tests using dedicated redis.
+func AllowFlushAll() Option {
+	return func(c *RedisCache) {
+		c.flushAll = true
+	}
+}
+
+// flushPrefix deletes the keys of the prefix using SCAN, so it doesn't block
+// the server, keys written during the flush may be kept.
+func (c RedisCache) flushPrefix() error {
+	conn := c.pool.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	pattern := escapePattern(c.prefix) + "*"
+	cursor := 0
+	for {
+		v, err := redis.Values(conn.Do("SCAN", cursor, "MATCH", pattern, "COUNT", flushBatch))
+		if err != nil {
+			return err
+		}
+
+		var keys []string
+		if cursor, keys, err = parseScan(v); err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if _, err = conn.Do("DEL", generalizeStringSlice(keys)...); err != nil {
+				return err
+			}
+		}
+
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
+
+func parseScan(v []interface{}) (cursor int, keys []string, err error) {
+	if len(v) != 2 {
+		return 0, nil, errors.New("cache: unexpected reply of SCAN")
+	}
+	if cursor, err = redis.Int(v[0], nil); err != nil {
+		return
+	}
+	keys, err = redis.Strings(v[1], nil)
+
+	return
+}
+
+// escapePattern escapes the glob characters of the MATCH pattern.
+func escapePattern(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
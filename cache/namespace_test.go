@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"testing"
+)
+
+func TestFlushNotAllowed(t *testing.T) {
+	c := RedisCache{}
+	if err := c.Flush(); err != ErrFlushNotAllowed {
+		t.Errorf("expected ErrFlushNotAllowed, got %v", err)
+	}
+}
+
+func TestPrefixOptions(t *testing.T) {
+	c := RedisCache{}
+	WithPrefix("orders:")(&c)
+	AllowFlushAll()(&c)
+
+	if c.prefix != "orders:" || !c.flushAll {
+		t.Errorf("unexpected cache %+v", c)
+	}
+}
+
+func TestEscapePattern(t *testing.T) {
+	tests := map[string]string{
+		"orders:":    "orders:",
+		"a*b?[c]\\d": "a\\*b\\?\\[c\\]\\\\d",
+	}
+	for in, out := range tests {
+		if got := escapePattern(in); got != out {
+			t.Errorf("escapePattern(%q) = %q, want %q", in, got, out)
+		}
+	}
+}
+
+func TestParseScan(t *testing.T) {
+	cursor, keys, err := parseScan([]interface{}{[]byte("17"), []interface{}{[]byte("a"), []byte("b")}})
+	if err != nil || cursor != 17 || len(keys) != 2 || keys[1] != "b" {
+		t.Errorf("unexpected scan %d %v %v", cursor, keys, err)
+	}
+
+	if _, _, err = parseScan([]interface{}{}); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -16,11 +16,14 @@ import (
 type RedisCache struct {
 	pool              *redis.Pool
 	defaultExpiration time.Duration
+	version           byte
+	prefix            string
+	flushAll          bool
 }
 
 // NewRedisCache returns a new RedisCache with given parameters
 // until redigo supports sharding/clustering, only one host will be in hostList
-func NewRedisCache() RedisCache {
+func NewRedisCache(opts ...Option) RedisCache {
 	var pool = &redis.Pool{
 		MaxIdle:     Config.MaxIdle,
 		MaxActive:   Config.MaxActive,
@@ -59,7 +62,12 @@ func NewRedisCache() RedisCache {
 
 	defaultExpiration := time.Hour * time.Duration(Config.DefaultExpire)
 
-	return RedisCache{pool, defaultExpiration}
+	c := RedisCache{pool: pool, defaultExpiration: defaultExpiration, prefix: Config.Prefix, flushAll: Config.AllowFlushAll}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return c
 }
 
 func generalizeStringSlice(strs []string) []interface{} {
@@ -90,7 +98,7 @@ func (c RedisCache) Add(key string, value interface{}, expires time.Duration) er
 		_ = conn.Close()
 	}()
 
-	existed, err := exists(conn, key)
+	existed, err := exists(conn, c.prefix+key)
 	if err != nil {
 		return err
 	} else if existed {
@@ -106,7 +114,7 @@ func (c RedisCache) Replace(key string, value interface{}, expires time.Duration
 		_ = conn.Close()
 	}()
 
-	existed, err := exists(conn, key)
+	existed, err := exists(conn, c.prefix+key)
 	if err != nil {
 		return err
 	} else if !existed {
@@ -126,7 +134,7 @@ func (c RedisCache) Get(key string, ptrValue interface{}) error {
 	defer func() {
 		_ = conn.Close()
 	}()
-	raw, err := conn.Do("GET", key)
+	raw, err := conn.Do("GET", c.prefix+key)
 	if err != nil {
 		return err
 	} else if raw == nil {
@@ -136,6 +144,9 @@ func (c RedisCache) Get(key string, ptrValue interface{}) error {
 	if err != nil {
 		return err
 	}
+	if item, err = c.unwrap(item); err != nil {
+		return err
+	}
 	return Deserialize(item, ptrValue)
 }
 
@@ -146,7 +157,12 @@ func (c RedisCache) GetMulti(keys ...string) (Getter, error) {
 		_ = conn.Close()
 	}()
 
-	items, err := redis.Values(conn.Do("MGET", generalizeStringSlice(keys)...))
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.prefix + key
+	}
+
+	items, err := redis.Values(conn.Do("MGET", generalizeStringSlice(prefixed)...))
 	if err != nil {
 		return nil, err
 	} else if items == nil {
@@ -158,9 +174,15 @@ func (c RedisCache) GetMulti(keys ...string) (Getter, error) {
 		m[key] = nil
 		if i < len(items) && items[i] != nil {
 			s, ok := items[i].([]byte)
-			if ok {
-				m[key] = s
+			if !ok {
+				continue
+			}
+			if s, err = c.unwrap(s); err != nil {
+				// stale version is a miss
+				delete(m, key)
+				continue
 			}
+			m[key] = s
 		}
 	}
 	return RedisItemMapGetter(m), nil
@@ -172,20 +194,28 @@ func (c RedisCache) Delete(key string) error {
 	defer func() {
 		_ = conn.Close()
 	}()
-	existed, err := redis.Bool(conn.Do("DEL", key))
+	existed, err := redis.Bool(conn.Do("DEL", c.prefix+key))
 	if err == nil && !existed {
 		err = ErrCacheMiss
 	}
 	return err
 }
 
-// Flush clear all cache data
+// Flush clear all cache data of the prefix, without prefix the whole
+// database is flushed only when AllowFlushAll is set.
 func (c RedisCache) Flush() error {
+	if c.prefix != "" {
+		return c.flushPrefix()
+	}
+	if !c.flushAll {
+		return ErrFlushNotAllowed
+	}
+
 	conn := c.pool.Get()
 	defer func() {
 		_ = conn.Close()
 	}()
-	_, err := conn.Do("FLUSHALL")
+	_, err := conn.Do("FLUSHDB")
 	return err
 }
 
@@ -202,15 +232,16 @@ func (c RedisCache) invoke(f func(string, ...interface{}) (interface{}, error),
 	if err != nil {
 		return err
 	}
+	b = c.wrap(b)
 	conn := c.pool.Get()
 	defer func() {
 		_ = conn.Close()
 	}()
 	if expires > 0 {
-		_, err = f("SETEX", key, int32(expires/time.Second), b)
+		_, err = f("SETEX", c.prefix+key, int32(expires/time.Second), b)
 		return err
 	}
-	_, err = f("SET", key, b)
+	_, err = f("SET", c.prefix+key, b)
 	return err
 }
 
@@ -22,7 +22,7 @@ var newRedisCache = func(t *testing.T, defaultExpiration time.Duration) Cache {
 		}
 		_ = c.Close()
 
-		redisCache := NewRedisCache()
+		redisCache := NewRedisCache(AllowFlushAll())
 		if err = redisCache.Flush(); err != nil {
 			t.Errorf("Flush failed: %s", err)
 		}
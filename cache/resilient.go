@@ -0,0 +1,324 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+type (
+	// Fallback decides what Resilient does when the operation
+	// still fails after the retries.
+	Fallback int
+
+	// Policy defines the timeout and retry of each operation of Resilient.
+	Policy struct {
+		// Timeout of each attempt of the operation, 0 is no timeout.
+		Timeout time.Duration
+
+		// Retries is the number of the attempts after the first one failed,
+		// the miss and not stored results are not retried.
+		Retries int
+
+		// Backoff is the wait before each retry.
+		Backoff time.Duration
+
+		// Fallback when the operation fails, default is OnErrorFail.
+		Fallback Fallback
+
+		// StaleSize is the max number of the values kept in memory
+		// for OnErrorReturnStale, default is 1000.
+		StaleSize int
+
+		// OnError is called with the failure of the operation before
+		// the fallback is applied, ex. to count the degraded calls.
+		OnError func(op, key string, err error)
+	}
+
+	// Resilient wraps the cache with the per operation timeout and retry,
+	// so a slow or down redis degrades into the loader calls instead of
+	// adding its latency to every request. The attempt that timed out
+	// is left running in background until the connection timeout.
+	//
+	//	cache.Instance = cache.NewResilient(cache.NewRedisCache(), cache.Policy{
+	//		Timeout:  50 * time.Millisecond,
+	//		Retries:  1,
+	//		Fallback: cache.OnErrorReturnStale,
+	//	})
+	Resilient struct {
+		cache  Cache
+		policy Policy
+
+		mu    sync.Mutex
+		stale map[string][]byte
+	}
+)
+
+// Fallbacks of the failed operation.
+const (
+	// OnErrorFail returns the error of the operation.
+	OnErrorFail Fallback = iota
+
+	// OnErrorPassthrough reports the failed Get as miss and ignores
+	// the failed writes, so the caller falls back to its loader.
+	OnErrorPassthrough
+
+	// OnErrorReturnStale returns the last value read or written through
+	// this instance for the failed Get, miss when there is none.
+	// The failed writes are ignored.
+	OnErrorReturnStale
+)
+
+// ErrTimeout returned by the operation of Resilient exceeding the timeout.
+var ErrTimeout = errors.New("cache: operation timed out")
+
+// NewResilient returns the cache wrapped with the policy.
+func NewResilient(c Cache, p Policy) *Resilient {
+	if p.StaleSize <= 0 {
+		p.StaleSize = 1000
+	}
+
+	r := &Resilient{cache: c, policy: p}
+	if p.Fallback == OnErrorReturnStale {
+		r.stale = make(map[string][]byte)
+	}
+
+	return r
+}
+
+// Get the content associated with the given key, see Fallback for the
+// result when the cache fails.
+func (r *Resilient) Get(key string, ptrValue interface{}) error {
+	// decoded outside of the attempt, the attempt may outlive the call
+	v, err := r.do("get", key, func() (interface{}, error) {
+		var b []byte
+		err := r.cache.Get(key, &b)
+		return b, err
+	})
+
+	raw, _ := v.([]byte)
+	switch {
+	case err == nil:
+		r.keep(key, raw)
+	case err == ErrCacheMiss:
+		return err
+	case r.policy.Fallback == OnErrorPassthrough:
+		return ErrCacheMiss
+	case r.policy.Fallback == OnErrorReturnStale:
+		var ok bool
+		if raw, ok = r.lookup(key); !ok {
+			return ErrCacheMiss
+		}
+	default:
+		return err
+	}
+
+	return Deserialize(raw, ptrValue)
+}
+
+// Fetch gets the value of the key into ptrValue, on miss or failure of
+// the cache the value is loaded by the loader and stored in the cache.
+//
+//	var p Product
+//	err := c.Fetch("product:1", &p, time.Hour, func() (interface{}, error) {
+//		return repo.Product(1)
+//	})
+func (r *Resilient) Fetch(key string, ptrValue interface{}, expires time.Duration, load func() (interface{}, error)) error {
+	err := r.Get(key, ptrValue)
+	if err == nil || (err != ErrCacheMiss && r.policy.Fallback == OnErrorFail) {
+		return err
+	}
+
+	v, err := load()
+	if err != nil {
+		return err
+	}
+
+	b, err := Serialize(v)
+	if err != nil {
+		return err
+	}
+	// the loaded value is served even when it can't be stored
+	_ = r.Set(key, b, expires)
+
+	return Deserialize(b, ptrValue)
+}
+
+// Set the given key/value in the cache.
+func (r *Resilient) Set(key string, value interface{}, expires time.Duration) error {
+	return r.write("set", key, value, func(b []byte) error {
+		return r.cache.Set(key, b, expires)
+	})
+}
+
+// Add the given key/value to the cache ONLY IF the key does not already exist.
+func (r *Resilient) Add(key string, value interface{}, expires time.Duration) error {
+	return r.write("add", key, value, func(b []byte) error {
+		return r.cache.Add(key, b, expires)
+	})
+}
+
+// Replace the given key/value in the cache ONLY IF the key already exists.
+func (r *Resilient) Replace(key string, value interface{}, expires time.Duration) error {
+	return r.write("replace", key, value, func(b []byte) error {
+		return r.cache.Replace(key, b, expires)
+	})
+}
+
+// GetMulti the content associated multiple keys at once, the failure is
+// a miss of every key unless the fallback is OnErrorFail.
+func (r *Resilient) GetMulti(keys ...string) (Getter, error) {
+	v, err := r.do("get_multi", "", func() (interface{}, error) {
+		return r.cache.GetMulti(keys...)
+	})
+	if err != nil && err != ErrCacheMiss && r.policy.Fallback != OnErrorFail {
+		return RedisItemMapGetter{}, nil
+	}
+
+	g, _ := v.(Getter)
+	return g, err
+}
+
+// Delete the given key from the cache, the stale value is dropped as well.
+func (r *Resilient) Delete(key string) error {
+	r.forget(key)
+	_, err := r.do("delete", key, func() (interface{}, error) {
+		return nil, r.cache.Delete(key)
+	})
+	return r.fail(err)
+}
+
+// Flush expires all cache entries, the stale values are dropped as well.
+func (r *Resilient) Flush() error {
+	if r.stale != nil {
+		r.mu.Lock()
+		for k := range r.stale {
+			delete(r.stale, k)
+		}
+		r.mu.Unlock()
+	}
+
+	_, err := r.do("flush", "", func() (interface{}, error) {
+		return nil, r.cache.Flush()
+	})
+	return err
+}
+
+// write stores the serialized value, so the attempt doesn't
+// read the value after the call returned.
+func (r *Resilient) write(op, key string, value interface{}, fn func(b []byte) error) error {
+	b, err := Serialize(value)
+	if err != nil {
+		return err
+	}
+
+	if _, err = r.do(op, key, func() (interface{}, error) { return nil, fn(b) }); err == nil {
+		r.keep(key, b)
+	}
+
+	return r.fail(err)
+}
+
+// fail returns the error of the failed write by the fallback.
+func (r *Resilient) fail(err error) error {
+	if err == nil || err == ErrCacheMiss || err == ErrNotStored || r.policy.Fallback == OnErrorFail {
+		return err
+	}
+	return nil
+}
+
+// do runs the operation with the timeout and retries.
+func (r *Resilient) do(op, key string, fn func() (interface{}, error)) (v interface{}, err error) {
+	for i := 0; i <= r.policy.Retries; i++ {
+		if i > 0 && r.policy.Backoff > 0 {
+			time.Sleep(r.policy.Backoff)
+		}
+
+		if v, err = r.attempt(fn); !retryable(err) {
+			return v, err
+		}
+	}
+
+	if r.policy.OnError != nil {
+		r.policy.OnError(op, key, err)
+	}
+
+	return nil, err
+}
+
+// attempt runs the operation, the result is passed through the channel
+// so the attempt that timed out doesn't write into the caller.
+func (r *Resilient) attempt(fn func() (interface{}, error)) (interface{}, error) {
+	if r.policy.Timeout <= 0 {
+		return fn()
+	}
+
+	type result struct {
+		v   interface{}
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		v, err := fn()
+		done <- result{v, err}
+	}()
+
+	t := time.NewTimer(r.policy.Timeout)
+	defer t.Stop()
+
+	select {
+	case res := <-done:
+		return res.v, res.err
+	case <-t.C:
+		return nil, ErrTimeout
+	}
+}
+
+func retryable(err error) bool {
+	switch err {
+	case nil, ErrCacheMiss, ErrNotStored, ErrInvalidValue, ErrFlushNotAllowed, ErrNotCounter:
+		return false
+	}
+	return true
+}
+
+// keep stores the value for OnErrorReturnStale, an arbitrary
+// value is dropped when it's full.
+func (r *Resilient) keep(key string, b []byte) {
+	if r.stale == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.stale[key]; !ok && len(r.stale) >= r.policy.StaleSize {
+		for k := range r.stale {
+			delete(r.stale, k)
+			break
+		}
+	}
+	r.stale[key] = b
+}
+
+func (r *Resilient) lookup(key string) ([]byte, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, ok := r.stale[key]
+	return b, ok
+}
+
+func (r *Resilient) forget(key string) {
+	if r.stale == nil {
+		return
+	}
+
+	r.mu.Lock()
+	delete(r.stale, key)
+	r.mu.Unlock()
+}
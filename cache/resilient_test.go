@@ -0,0 +1,178 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyCache is Memory that fails or hangs on demand.
+type flakyCache struct {
+	*Memory
+	delay int64 // time.Duration
+	calls int32
+
+	mu  sync.Mutex
+	err error
+}
+
+func (c *flakyCache) fault() error {
+	atomic.AddInt32(&c.calls, 1)
+	time.Sleep(time.Duration(atomic.LoadInt64(&c.delay)))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+func (c *flakyCache) fail(err error) {
+	c.mu.Lock()
+	c.err = err
+	c.mu.Unlock()
+}
+
+func (c *flakyCache) Get(key string, ptr interface{}) error {
+	if err := c.fault(); err != nil {
+		return err
+	}
+	return c.Memory.Get(key, ptr)
+}
+
+func (c *flakyCache) Set(key string, value interface{}, d time.Duration) error {
+	if err := c.fault(); err != nil {
+		return err
+	}
+	return c.Memory.Set(key, value, d)
+}
+
+func (c *flakyCache) GetMulti(keys ...string) (Getter, error) {
+	if err := c.fault(); err != nil {
+		return nil, err
+	}
+	return c.Memory.GetMulti(keys...)
+}
+
+func newFlakyCache() *flakyCache {
+	return &flakyCache{Memory: NewMemory()}
+}
+
+func TestResilientTimeout(t *testing.T) {
+	fc := newFlakyCache()
+	fc.Set("k", "v", 0)
+	atomic.StoreInt64(&fc.delay, int64(200*time.Millisecond))
+
+	var failed []string
+	r := NewResilient(fc, Policy{
+		Timeout: 10 * time.Millisecond,
+		Retries: 1,
+		OnError: func(op, key string, err error) { failed = append(failed, op+" "+key+" "+err.Error()) },
+	})
+
+	var v string
+	start := time.Now()
+	if err := r.Get("k", &v); err != ErrTimeout {
+		t.Errorf("expected timeout, got %v", err)
+	}
+	if d := time.Since(start); d > 100*time.Millisecond {
+		t.Errorf("expected the timeout to bound the latency, took %s", d)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if n := atomic.LoadInt32(&fc.calls); n != 3 {
+		t.Errorf("expected 2 attempts, got %d", n-1)
+	}
+	if len(failed) != 1 || failed[0] != "get k "+ErrTimeout.Error() {
+		t.Errorf("unexpected OnError calls %v", failed)
+	}
+
+	atomic.StoreInt64(&fc.delay, 0)
+	if err := r.Get("k", &v); err != nil || v != "v" {
+		t.Errorf("got %q (%v)", v, err)
+	}
+	if err := r.Get("missing", &v); err != ErrCacheMiss {
+		t.Errorf("expected cache miss, got %v", err)
+	}
+}
+
+func TestResilientPassthrough(t *testing.T) {
+	fc := newFlakyCache()
+	fc.fail(errors.New("connection refused"))
+	r := NewResilient(fc, Policy{Fallback: OnErrorPassthrough})
+
+	var v string
+	if err := r.Get("k", &v); err != ErrCacheMiss {
+		t.Errorf("expected cache miss, got %v", err)
+	}
+	if err := r.Set("k", "v", 0); err != nil {
+		t.Errorf("expected failed write ignored, got %v", err)
+	}
+	if g, err := r.GetMulti("k"); err != nil || g.Get("k", &v) != ErrCacheMiss {
+		t.Errorf("expected miss of every key, got %v", err)
+	}
+
+	loads := 0
+	load := func() (interface{}, error) {
+		loads++
+		return "loaded", nil
+	}
+	if err := r.Fetch("k", &v, time.Minute, load); err != nil || v != "loaded" || loads != 1 {
+		t.Errorf("got %q (%v), %d loads", v, err, loads)
+	}
+
+	fc.fail(nil)
+	if err := r.Fetch("k", &v, time.Minute, load); err != nil || loads != 2 {
+		t.Errorf("expected loaded on miss, got %v, %d loads", err, loads)
+	}
+	if err := r.Fetch("k", &v, time.Minute, load); err != nil || v != "loaded" || loads != 2 {
+		t.Errorf("expected cached value, got %q (%v), %d loads", v, err, loads)
+	}
+
+	fail := NewResilient(fc, Policy{})
+	refused := errors.New("connection refused")
+	fc.fail(refused)
+	if err := fail.Fetch("k", &v, time.Minute, load); err != refused {
+		t.Errorf("expected the error with OnErrorFail, got %v", err)
+	}
+}
+
+func TestResilientReturnStale(t *testing.T) {
+	fc := newFlakyCache()
+	r := NewResilient(fc, Policy{Fallback: OnErrorReturnStale, StaleSize: 2})
+
+	type product struct{ Name string }
+	if err := r.Set("product:1", product{"kopi"}, 0); err != nil {
+		t.Fatal(err)
+	}
+	fc.Memory.Set("product:2", product{"teh"}, 0)
+
+	var p product
+	if err := r.Get("product:2", &p); err != nil {
+		t.Fatal(err)
+	}
+
+	fc.fail(errors.New("i/o timeout"))
+	for k, want := range map[string]string{"product:1": "kopi", "product:2": "teh"} {
+		p = product{}
+		if err := r.Get(k, &p); err != nil || p.Name != want {
+			t.Errorf("%s: got %q (%v), want stale %q", k, p.Name, err, want)
+		}
+	}
+	if err := r.Get("product:3", &p); err != ErrCacheMiss {
+		t.Errorf("expected miss without stale value, got %v", err)
+	}
+
+	r.Delete("product:1")
+	if err := r.Get("product:1", &p); err != ErrCacheMiss {
+		t.Errorf("expected stale value dropped on delete, got %v", err)
+	}
+
+	fc.fail(nil)
+	r.Set("product:3", product{"susu"}, 0)
+	r.Set("product:4", product{"air"}, 0)
+	r.mu.Lock()
+	n := len(r.stale)
+	r.mu.Unlock()
+	if n != 2 {
+		t.Errorf("expected stale values bounded by StaleSize, got %d", n)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"context"
+
+	"github.com/enigma-id/go/trace"
+)
+
+// GetContext is Get of the cache instance, the hit or miss is recorded
+// on the trace of the context, see mw.DebugTrace.
+func GetContext(ctx context.Context, key string, ptrValue interface{}) error {
+	return getTraced(ctx, Instance, key, ptrValue)
+}
+
+// GetContext is Get that records the hit or miss on the trace of the context.
+func (c RedisCache) GetContext(ctx context.Context, key string, ptrValue interface{}) error {
+	return getTraced(ctx, c, key, ptrValue)
+}
+
+func getTraced(ctx context.Context, g Getter, key string, ptrValue interface{}) error {
+	s := trace.Start(ctx, trace.KindCache, key)
+	err := g.Get(key, ptrValue)
+	s.Set("hit", err == nil)
+	if err != nil && err != ErrCacheMiss {
+		s.End(err)
+	} else {
+		s.End()
+	}
+
+	return err
+}
@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/enigma-id/go/trace"
+)
+
+type getterFunc func(key string, ptrValue interface{}) error
+
+func (f getterFunc) Get(key string, ptrValue interface{}) error { return f(key, ptrValue) }
+
+func TestGetTraced(t *testing.T) {
+	g := getterFunc(func(key string, ptrValue interface{}) error {
+		if key == "hit" {
+			return nil
+		}
+		return ErrCacheMiss
+	})
+
+	tr := trace.New()
+	ctx := trace.WithTrace(context.Background(), tr)
+
+	var v string
+	if err := getTraced(ctx, g, "hit", &v); err != nil {
+		t.Fatal(err)
+	}
+	if err := getTraced(ctx, g, "miss", &v); err != ErrCacheMiss {
+		t.Errorf("expected cache miss, got %v", err)
+	}
+	if err := getTraced(context.Background(), g, "miss", &v); err != ErrCacheMiss {
+		t.Errorf("expected cache miss, got %v", err)
+	}
+
+	spans := tr.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Name != "hit" || spans[0].Attrs["hit"] != true {
+		t.Errorf("unexpected span %+v", spans[0])
+	}
+	if spans[1].Name != "miss" || spans[1].Attrs["hit"] != false || spans[1].Attrs["error"] != nil {
+		t.Errorf("unexpected span %+v", spans[1])
+	}
+}
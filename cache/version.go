@@ -0,0 +1,53 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import "bytes"
+
+// versionMagic starts the versioned entries, it's followed by the version byte.
+var versionMagic = []byte{0x00, 'V'}
+
+// Option configures the RedisCache.
+type Option func(*RedisCache)
+
+// WithVersion stores the entries with the version, entries of the other
+// versions (or without version) are treated as miss, so deploy that changes
+// shape of the cached structs doesn't fail decoding the old entries.
+// Bump the version whenever the cached types change, 0 is unversioned.
+//
+//	cache.Instance = cache.NewRedisCache(cache.WithVersion(3))
+func WithVersion(v byte) Option {
+	return func(c *RedisCache) {
+		c.version = v
+	}
+}
+
+// wrap prepends the version header into serialized value.
+func (c RedisCache) wrap(b []byte) []byte {
+	if c.version == 0 {
+		return b
+	}
+
+	w := make([]byte, 0, len(versionMagic)+1+len(b))
+	w = append(w, versionMagic...)
+	w = append(w, c.version)
+
+	return append(w, b...)
+}
+
+// unwrap returns the serialized value without the version header,
+// ErrCacheMiss is returned when the entry has different version.
+func (c RedisCache) unwrap(b []byte) ([]byte, error) {
+	if c.version == 0 {
+		return b, nil
+	}
+
+	n := len(versionMagic)
+	if len(b) <= n || !bytes.Equal(b[:n], versionMagic) || b[n] != c.version {
+		return nil, ErrCacheMiss
+	}
+
+	return b[n+1:], nil
+}
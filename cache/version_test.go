@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestVersion(t *testing.T) {
+	type product struct {
+		Name  string
+		Price int
+	}
+
+	v1 := RedisCache{}
+	WithVersion(1)(&v1)
+	v2 := RedisCache{version: 2}
+	legacy := RedisCache{}
+
+	b, err := Serialize(product{"Kopi", 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := v1.wrap(b)
+	if bytes.Equal(entry, b) {
+		t.Error("expected versioned entry")
+	}
+
+	raw, err := v1.unwrap(entry)
+	if err != nil {
+		t.Fatalf("unwrap failed: %s", err)
+	}
+	var p product
+	if err = Deserialize(raw, &p); err != nil || p.Name != "Kopi" {
+		t.Errorf("expected decoded product, got %v %s", p, err)
+	}
+
+	if _, err = v2.unwrap(entry); err != ErrCacheMiss {
+		t.Errorf("expected miss of other version, got %v", err)
+	}
+	if _, err = v1.unwrap(b); err != ErrCacheMiss {
+		t.Errorf("expected miss of unversioned entry, got %v", err)
+	}
+	if _, err = v1.unwrap(nil); err != ErrCacheMiss {
+		t.Errorf("expected miss of empty entry, got %v", err)
+	}
+
+	// unversioned cache keeps the entries as is
+	if raw, _ = legacy.unwrap(b); !bytes.Equal(raw, b) || !bytes.Equal(legacy.wrap(b), b) {
+		t.Error("expected unversioned entry as is")
+	}
+}
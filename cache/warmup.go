@@ -0,0 +1,202 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+type (
+	// WarmJob preloads single key, or many keys using LoadMany.
+	WarmJob struct {
+		// Name of the job used in the logs, default is the key.
+		Name string
+
+		// Key and its loader.
+		Key  string
+		Load func(ctx context.Context) (interface{}, error)
+
+		// LoadMany returns the entries keyed by the cache key,
+		// used when the job preloads many keys at once.
+		LoadMany func(ctx context.Context) (map[string]interface{}, error)
+
+		// Expires of the entries.
+		Expires time.Duration
+
+		// SkipExisting doesn't load the key that is already cached.
+		SkipExisting bool
+
+		// Required fails the warmup regardless of the failure threshold.
+		Required bool
+	}
+
+	// WarmupOption configures the warmup.
+	WarmupOption func(*warmup)
+
+	warmup struct {
+		cache       Cache
+		concurrency int
+		maxFailures int
+		logf        func(format string, args ...interface{})
+	}
+)
+
+// ErrWarmupFailed returned when the failed jobs exceed the threshold
+// or any of the required jobs failed.
+var ErrWarmupFailed = errors.New("cache: warmup failed")
+
+// WarmCache sets the cache being warmed, default is Instance.
+func WarmCache(c Cache) WarmupOption {
+	return func(w *warmup) {
+		w.cache = c
+	}
+}
+
+// WarmConcurrency sets number of the jobs running concurrently, default is 4.
+func WarmConcurrency(n int) WarmupOption {
+	return func(w *warmup) {
+		if n > 0 {
+			w.concurrency = n
+		}
+	}
+}
+
+// WarmMaxFailures sets number of the jobs allowed to fail, default is 0.
+func WarmMaxFailures(n int) WarmupOption {
+	return func(w *warmup) {
+		w.maxFailures = n
+	}
+}
+
+// WarmLogf sets the progress logger, default is log.Printf.
+func WarmLogf(fn func(format string, args ...interface{})) WarmupOption {
+	return func(w *warmup) {
+		w.logf = fn
+	}
+}
+
+// Warmup runs the jobs concurrently preloading the cache, the progress is logged
+// per job. It returns ErrWarmupFailed when more jobs than the threshold or any
+// required job failed, and the context error when it's done before finished.
+//
+//	e.OnStart(cache.WarmupHook(jobs, cache.WarmMaxFailures(2)), rest.HookName("cache"))
+func Warmup(ctx context.Context, jobs []WarmJob, opts ...WarmupOption) error {
+	w := &warmup{cache: Instance, concurrency: 4, logf: log.Printf}
+	for _, o := range opts {
+		o(w)
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		done     int
+		failed   []string
+		required bool
+		sem      = make(chan struct{}, w.concurrency)
+		start    = time.Now()
+	)
+
+	for _, job := range jobs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(job WarmJob) {
+			defer func() { <-sem; wg.Done() }()
+
+			t := time.Now()
+			n, err := w.run(ctx, job)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			done++
+			if err != nil {
+				failed = append(failed, job.name())
+				required = required || job.Required
+				w.logf("cache: warmup %s failed (%d/%d): %v", job.name(), done, len(jobs), err)
+				return
+			}
+			w.logf("cache: warmup %s loaded %d keys in %s (%d/%d)", job.name(), n, time.Since(t), done, len(jobs))
+		}(job)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	w.logf("cache: warmup finished in %s, %d of %d jobs failed", time.Since(start), len(failed), len(jobs))
+	if required || len(failed) > w.maxFailures {
+		return fmt.Errorf("%v: %v", ErrWarmupFailed, failed)
+	}
+
+	return nil
+}
+
+// WarmupHook returns Warmup as start hook, see rest.OnStart.
+func WarmupHook(jobs []WarmJob, opts ...WarmupOption) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return Warmup(ctx, jobs, opts...)
+	}
+}
+
+// run loads the job and returns number of the keys stored.
+func (w *warmup) run(ctx context.Context, job WarmJob) (n int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	if job.LoadMany != nil {
+		entries, err := job.LoadMany(ctx)
+		if err != nil {
+			return 0, err
+		}
+		for k, v := range entries {
+			if err = w.cache.Set(k, v, job.Expires); err != nil {
+				return n, err
+			}
+			n++
+		}
+		return n, nil
+	}
+
+	if job.Load == nil || job.Key == "" {
+		return 0, errors.New("job requires key and loader")
+	}
+
+	if job.SkipExisting {
+		var raw []byte
+		if w.cache.Get(job.Key, &raw) == nil {
+			return 0, nil
+		}
+	}
+
+	v, err := job.Load(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return 1, w.cache.Set(job.Key, v, job.Expires)
+}
+
+func (j WarmJob) name() string {
+	if j.Name != "" {
+		return j.Name
+	}
+	return j.Key
+}
@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func value(v interface{}) func(context.Context) (interface{}, error) {
+	return func(context.Context) (interface{}, error) { return v, nil }
+}
+
+func TestWarmup(t *testing.T) {
+	c := NewMemory()
+	c.Set("existing", "cached", 0)
+	var logs []string
+	var mu sync.Mutex
+	logf := func(format string, args ...interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		logs = append(logs, format)
+	}
+
+	loaded := false
+	jobs := []WarmJob{
+		{Key: "config", Load: value("on")},
+		{Name: "products", LoadMany: func(context.Context) (map[string]interface{}, error) {
+			return map[string]interface{}{"product:1": "kopi", "product:2": "teh"}, nil
+		}},
+		{Key: "existing", SkipExisting: true, Load: func(context.Context) (interface{}, error) {
+			loaded = true
+			return "fresh", nil
+		}},
+	}
+
+	if err := Warmup(context.Background(), jobs, WarmCache(c), WarmConcurrency(2), WarmLogf(logf)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for k, want := range map[string]string{"config": "on", "product:2": "teh", "existing": "cached"} {
+		var got string
+		if err := c.Get(k, &got); err != nil || got != want {
+			t.Errorf("%s: got %q (%v), want %q", k, got, err, want)
+		}
+	}
+	if loaded {
+		t.Error("existing key should not be loaded")
+	}
+	if len(logs) != len(jobs)+1 {
+		t.Errorf("expected progress logged per job, got %d lines", len(logs))
+	}
+}
+
+func TestWarmupFailures(t *testing.T) {
+	c := NewMemory()
+	fail := func(context.Context) (interface{}, error) { return nil, errors.New("db down") }
+	quiet := WarmLogf(func(string, ...interface{}) {})
+
+	jobs := []WarmJob{
+		{Key: "a", Load: fail},
+		{Key: "b", Load: value("b")},
+		{Key: "c", Load: func(context.Context) (interface{}, error) { panic("boom") }},
+	}
+
+	err := Warmup(context.Background(), jobs, WarmCache(c), quiet)
+	if err == nil || !strings.HasPrefix(err.Error(), ErrWarmupFailed.Error()) {
+		t.Errorf("expected warmup failed, got %v", err)
+	}
+
+	if err = Warmup(context.Background(), jobs, WarmCache(c), WarmMaxFailures(2), quiet); err != nil {
+		t.Errorf("failures within threshold, got %v", err)
+	}
+
+	jobs[0].Required = true
+	if err = Warmup(context.Background(), jobs, WarmCache(c), WarmMaxFailures(2), quiet); err == nil {
+		t.Error("expected error for failing required job")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err = WarmupHook(jobs, WarmCache(c), quiet)(ctx); err != context.Canceled {
+		t.Errorf("expected context canceled, got %v", err)
+	}
+}
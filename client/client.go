@@ -0,0 +1,240 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type (
+	// Middleware wraps the transport, ex. for logging or authentication.
+	Middleware func(next http.RoundTripper) http.RoundTripper
+
+	// RoundTripperFunc is an adapter to use function as http.RoundTripper.
+	RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+	// Option configures the client.
+	Option func(*Client)
+)
+
+// RoundTrip calls f(r).
+func (f RoundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// Client is http client with request builder, retry, circuit breaker
+// and per host timeout.
+type Client struct {
+	BaseURL string
+	Header  http.Header
+
+	// Timeout of each attempt, HostTimeouts overrides it per host.
+	Timeout      time.Duration
+	HostTimeouts map[string]time.Duration
+
+	Retry   *RetryPolicy
+	Breaker *Breaker
+
+	transport  http.RoundTripper
+	middleware []Middleware
+	http       *http.Client
+}
+
+// WithBaseURL sets base url of the request path.
+func WithBaseURL(u string) Option {
+	return func(c *Client) {
+		c.BaseURL = strings.TrimRight(u, "/")
+	}
+}
+
+// WithTimeout sets default timeout of each attempt.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.Timeout = d
+	}
+}
+
+// WithHostTimeout sets timeout of each attempt into the host.
+func WithHostTimeout(host string, d time.Duration) Option {
+	return func(c *Client) {
+		if c.HostTimeouts == nil {
+			c.HostTimeouts = make(map[string]time.Duration)
+		}
+		c.HostTimeouts[host] = d
+	}
+}
+
+// WithHeader sets header sent on every request.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.Header.Set(key, value)
+	}
+}
+
+// WithRetry sets the retry policy.
+func WithRetry(p *RetryPolicy) Option {
+	return func(c *Client) {
+		c.Retry = p
+	}
+}
+
+// WithBreaker sets circuit breaker that opens after the number of
+// consecutive failures into the host, for the cooldown duration.
+func WithBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Client) {
+		c.Breaker = NewBreaker(threshold, cooldown)
+	}
+}
+
+// WithTransport sets the underlying transport, ex. Recorder on tests.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		c.transport = rt
+	}
+}
+
+// WithMiddleware adds middleware of the transport, the first is the outermost.
+func WithMiddleware(m ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, m...)
+	}
+}
+
+// New creates client, the default timeout is 30s without retry.
+func New(opts ...Option) *Client {
+	c := &Client{
+		Header:    make(http.Header),
+		Timeout:   30 * time.Second,
+		transport: http.DefaultTransport,
+	}
+
+	for _, o := range opts {
+		o(c)
+	}
+
+	rt := c.transport
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		rt = c.middleware[i](rt)
+	}
+	c.http = &http.Client{Transport: rt}
+
+	return c
+}
+
+// Get creates GET request.
+func (c *Client) Get(path string) *Request {
+	return c.NewRequest(http.MethodGet, path)
+}
+
+// Post creates POST request.
+func (c *Client) Post(path string) *Request {
+	return c.NewRequest(http.MethodPost, path)
+}
+
+// Put creates PUT request.
+func (c *Client) Put(path string) *Request {
+	return c.NewRequest(http.MethodPut, path)
+}
+
+// Patch creates PATCH request.
+func (c *Client) Patch(path string) *Request {
+	return c.NewRequest(http.MethodPatch, path)
+}
+
+// Delete creates DELETE request.
+func (c *Client) Delete(path string) *Request {
+	return c.NewRequest(http.MethodDelete, path)
+}
+
+// NewRequest creates request of the method, path is joined
+// with the base url unless it is absolute url.
+func (c *Client) NewRequest(method, path string) *Request {
+	u := path
+	if c.BaseURL != "" && !strings.Contains(path, "://") {
+		u = c.BaseURL + "/" + strings.TrimLeft(path, "/")
+	}
+
+	r := &Request{
+		client: c,
+		method: method,
+		url:    u,
+		header: make(http.Header),
+		ctx:    context.Background(),
+	}
+
+	for k, v := range c.Header {
+		r.header[k] = append([]string{}, v...)
+	}
+
+	return r
+}
+
+func (c *Client) timeout(host string) time.Duration {
+	if d, ok := c.HostTimeouts[host]; ok {
+		return d
+	}
+
+	return c.Timeout
+}
+
+// do sends the request with retry and circuit breaker.
+func (c *Client) do(r *Request) (*Response, error) {
+	req, err := r.build()
+	if err != nil {
+		return nil, err
+	}
+
+	host := req.URL.Host
+	retry := c.Retry
+	if retry == nil {
+		retry = noRetry
+	}
+
+	for attempt := 0; ; attempt++ {
+		if c.Breaker != nil && !c.Breaker.Allow(host) {
+			return nil, ErrCircuitOpen
+		}
+
+		res, err := c.attempt(r, req)
+		if c.Breaker != nil {
+			c.Breaker.Done(host, err == nil && res.StatusCode < 500)
+		}
+
+		if attempt >= retry.MaxRetries || !retry.retryable(req, res, err) {
+			return res, err
+		}
+
+		wait := retry.backoff(attempt, res)
+		select {
+		case <-r.ctx.Done():
+			return nil, r.ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (c *Client) attempt(r *Request, req *http.Request) (*Response, error) {
+	ctx := r.ctx
+	if d := c.timeout(req.URL.Host); d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+
+	req = req.WithContext(ctx)
+	if r.body != nil {
+		req.Body = r.newBody()
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return readResponse(res)
+}
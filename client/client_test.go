@@ -0,0 +1,142 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/enigma-id/go/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+type user struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestRequest(t *testing.T) {
+	rec := NewRecorder()
+	rec.On("GET", "/users/1").Reply(200, &user{ID: 1, Name: "John"})
+	rec.On("POST", "/users").Reply(422, `{"errors":{"name":"required"}}`)
+
+	var calls []string
+	c := New(WithBaseURL("http://api.local/"), WithTransport(rec), WithHeader("X-App", "test"),
+		WithMiddleware(func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				calls = append(calls, r.Method+" "+r.URL.Path)
+				return next.RoundTrip(r)
+			})
+		}))
+
+	u := new(user)
+	assert.NoError(t, c.Get("/users/1").Query("expand", "roles").Into(u))
+	assert.Equal(t, "John", u.Name)
+
+	err := c.Post("users").JSON(&user{Name: ""}).Into(nil)
+	if assert.IsType(t, new(Error), err) {
+		assert.Equal(t, 422, err.(*Error).StatusCode)
+	}
+
+	reqs := rec.Requests()
+	if assert.Len(t, reqs, 2) {
+		assert.Equal(t, "http://api.local/users/1?expand=roles", reqs[0].URL)
+		assert.Equal(t, "test", reqs[0].Header.Get("X-App"))
+		assert.JSONEq(t, `{"id":0,"name":""}`, string(reqs[1].Body))
+		assert.Equal(t, "application/json", reqs[1].Header.Get("Content-Type"))
+	}
+	assert.Equal(t, []string{"GET /users/1", "POST /users"}, calls)
+
+	_, err = c.Delete("/unknown").Do()
+	assert.Error(t, err)
+}
+
+func TestRetry(t *testing.T) {
+	attempts := 0
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer s.Close()
+
+	c := New(WithBaseURL(s.URL), WithRetry(&RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond}))
+	res, err := c.Put("/").Body([]byte("data"), "text/plain").Do()
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", res.String())
+	assert.Equal(t, 3, attempts)
+
+	// non idempotent method is not retried
+	attempts = 0
+	res, _ = c.Post("/").Do()
+	assert.Equal(t, 503, res.StatusCode)
+	assert.Equal(t, 1, attempts)
+
+	p := &RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	assert.True(t, p.backoff(5, nil) <= time.Second)
+	res = &Response{Response: &http.Response{Header: http.Header{"Retry-After": {"5"}}}}
+	assert.Equal(t, time.Second, p.backoff(0, res))
+}
+
+func TestBreaker(t *testing.T) {
+	rec := NewRecorder()
+	rec.On("GET", "/").Fail(errors.New("connection refused"))
+
+	c := New(WithBaseURL("http://api.local"), WithTransport(rec), WithBreaker(2, time.Minute))
+	now := time.Now()
+	c.Breaker.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		_, err := c.Get("/").Do()
+		assert.Error(t, err)
+	}
+
+	_, err := c.Get("/").Do()
+	assert.Equal(t, ErrCircuitOpen, err)
+	assert.Equal(t, "open", c.Breaker.State("api.local"))
+	assert.Len(t, rec.Requests(), 2)
+
+	now = now.Add(time.Minute)
+	rec.On("GET", "/").Reply(200, "ok")
+	assert.Equal(t, "half-open", c.Breaker.State("api.local"))
+	_, err = c.Get("/").Do()
+	assert.NoError(t, err)
+	assert.Equal(t, "closed", c.Breaker.State("api.local"))
+}
+
+func TestTimeout(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+	}))
+	defer s.Close()
+
+	c := New(WithBaseURL(s.URL), WithHostTimeout(s.Listener.Addr().String(), 10*time.Millisecond))
+	_, err := c.Get("/").Do()
+	assert.Error(t, err)
+}
+
+func TestFrom(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(rest.HeaderXRequestID, "abc")
+	req.Header.Set(HeaderTraceparent, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	ctx := rest.New().NewContext(req, httptest.NewRecorder())
+
+	rec := NewRecorder()
+	rec.On("GET", "/").Reply(204, nil)
+	New(WithTransport(rec)).Get("http://api.local/").From(ctx).Do()
+
+	h := rec.Requests()[0].Header
+	assert.Equal(t, "abc", h.Get(rest.HeaderXRequestID))
+	assert.Regexp(t, "^00-0af7651916cd43dd8448eb211c80319c-[0-9a-f]{16}-01$", h.Get(HeaderTraceparent))
+	assert.NotContains(t, h.Get(HeaderTraceparent), "b7ad6b7169203331")
+
+	assert.Regexp(t, "^00-[0-9a-f]{32}-[0-9a-f]{16}-01$", Traceparent("invalid"))
+}
@@ -0,0 +1,152 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/enigma-id/go/utility"
+)
+
+// Recorder is transport for tests, it records the requests and replies
+// using the stubs, requests without stub are sent into Next when set.
+//
+//	rec := client.NewRecorder()
+//	rec.On("GET", "/users/1").Reply(200, `{"id":1}`)
+//	c := client.New(client.WithTransport(rec))
+type Recorder struct {
+	Next http.RoundTripper
+
+	mu       sync.Mutex
+	stubs    []*Stub
+	requests []*RecordedRequest
+}
+
+// RecordedRequest is the request sent through the recorder.
+type RecordedRequest struct {
+	Method string
+	URL    string
+	Header http.Header
+	Body   []byte
+}
+
+// Stub is prepared response of the method and path.
+type Stub struct {
+	Method string
+	Path   string
+	Status int
+	Header http.Header
+	Body   []byte
+	Err    error
+}
+
+// NewRecorder creates recorder.
+func NewRecorder() *Recorder {
+	return new(Recorder)
+}
+
+// On creates stub of the method and path, path matches url path or full url,
+// the last matching stub is used.
+func (r *Recorder) On(method, path string) *Stub {
+	s := &Stub{Method: method, Path: path, Status: http.StatusOK, Header: make(http.Header)}
+
+	r.mu.Lock()
+	r.stubs = append(r.stubs, s)
+	r.mu.Unlock()
+
+	return s
+}
+
+// Reply sets status and body of the stub, string and []byte body are written
+// as is, others are encoded as json.
+func (s *Stub) Reply(status int, body interface{}) *Stub {
+	s.Status = status
+	switch b := body.(type) {
+	case nil:
+	case string:
+		s.Body = []byte(b)
+	case []byte:
+		s.Body = b
+	default:
+		s.Body = []byte(utility.ToJSON(b))
+		s.Header.Set("Content-Type", "application/json")
+	}
+
+	return s
+}
+
+// Fail makes the stub returning error as network failure.
+func (s *Stub) Fail(err error) *Stub {
+	s.Err = err
+	return s
+}
+
+// Requests returns the recorded requests.
+func (r *Recorder) Requests() []*RecordedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*RecordedRequest{}, r.requests...)
+}
+
+// Reset removes stubs and recorded requests.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stubs, r.requests = nil, nil
+}
+
+// RoundTrip implement http.RoundTripper interfaces
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	rr := &RecordedRequest{Method: req.Method, URL: req.URL.String(), Header: req.Header.Clone()}
+	if req.Body != nil {
+		rr.Body, _ = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(rr.Body))
+	}
+
+	r.mu.Lock()
+	r.requests = append(r.requests, rr)
+
+	var stub *Stub
+	for i := len(r.stubs) - 1; i >= 0; i-- {
+		s := r.stubs[i]
+		if strings.EqualFold(s.Method, req.Method) && (s.Path == req.URL.Path || s.Path == rr.URL) {
+			stub = s
+			break
+		}
+	}
+	r.mu.Unlock()
+
+	if stub == nil {
+		if r.Next != nil {
+			return r.Next.RoundTrip(req)
+		}
+
+		return nil, fmt.Errorf("client: no stub for %s %s", req.Method, rr.URL)
+	}
+
+	if stub.Err != nil {
+		return nil, stub.Err
+	}
+
+	return &http.Response{
+		StatusCode:    stub.Status,
+		Status:        fmt.Sprintf("%d %s", stub.Status, http.StatusText(stub.Status)),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        stub.Header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(stub.Body)),
+		ContentLength: int64(len(stub.Body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,221 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Request is fluent request builder.
+type Request struct {
+	client *Client
+	method string
+	url    string
+	query  url.Values
+	header http.Header
+	body   []byte
+	ctx    context.Context
+	err    error
+}
+
+// Context sets context of the request.
+func (r *Request) Context(ctx context.Context) *Request {
+	r.ctx = ctx
+	return r
+}
+
+// Query adds query parameter.
+func (r *Request) Query(key string, value interface{}) *Request {
+	if r.query == nil {
+		r.query = make(url.Values)
+	}
+
+	r.query.Add(key, fmt.Sprint(value))
+	return r
+}
+
+// Header sets request header.
+func (r *Request) Header(key, value string) *Request {
+	r.header.Set(key, value)
+	return r
+}
+
+// BearerToken sets Authorization header with bearer token.
+func (r *Request) BearerToken(token string) *Request {
+	return r.Header("Authorization", "Bearer "+token)
+}
+
+// JSON sets the body encoded as json.
+func (r *Request) JSON(v interface{}) *Request {
+	b, err := json.Marshal(v)
+	if err != nil {
+		r.err = err
+		return r
+	}
+
+	return r.Body(b, "application/json")
+}
+
+// Form sets the body as url encoded form.
+func (r *Request) Form(v url.Values) *Request {
+	return r.Body([]byte(v.Encode()), "application/x-www-form-urlencoded")
+}
+
+// Body sets the raw body, the body is kept in memory so it can be resent on retry.
+func (r *Request) Body(b []byte, contentType string) *Request {
+	r.body = b
+	if contentType != "" {
+		r.header.Set("Content-Type", contentType)
+	}
+
+	return r
+}
+
+// Reader sets the body from reader, see Body.
+func (r *Request) Reader(rd io.Reader, contentType string) *Request {
+	b, err := ioutil.ReadAll(rd)
+	if err != nil {
+		r.err = err
+	}
+
+	return r.Body(b, contentType)
+}
+
+// Do sends the request, non 2xx status is not an error.
+func (r *Request) Do() (*Response, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return r.client.do(r)
+}
+
+// Into sends the request and decodes json response into v,
+// *Error is returned when the status is not 2xx.
+func (r *Request) Into(v interface{}) error {
+	res, err := r.Do()
+	if err != nil {
+		return err
+	}
+
+	if !res.IsSuccess() {
+		return res.Error()
+	}
+
+	if v == nil || len(res.Bytes()) == 0 {
+		return nil
+	}
+
+	return res.JSON(v)
+}
+
+func (r *Request) build() (*http.Request, error) {
+	u := r.url
+	if len(r.query) > 0 {
+		pu, err := url.Parse(u)
+		if err != nil {
+			return nil, err
+		}
+
+		q := pu.Query()
+		for k, v := range r.query {
+			q[k] = append(q[k], v...)
+		}
+		pu.RawQuery = q.Encode()
+		u = pu.String()
+	}
+
+	req, err := http.NewRequest(r.method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header = r.header
+	if r.body != nil {
+		req.ContentLength = int64(len(r.body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return r.newBody(), nil
+		}
+	}
+
+	return req, nil
+}
+
+func (r *Request) newBody() io.ReadCloser {
+	return ioutil.NopCloser(bytes.NewReader(r.body))
+}
+
+// Response is http response with the body read.
+type Response struct {
+	*http.Response
+	body []byte
+}
+
+func readResponse(res *http.Response) (*Response, error) {
+	defer res.Body.Close()
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Body = ioutil.NopCloser(bytes.NewReader(b))
+
+	return &Response{Response: res, body: b}, nil
+}
+
+// Bytes returns the body.
+func (r *Response) Bytes() []byte {
+	return r.body
+}
+
+// String returns the body as string.
+func (r *Response) String() string {
+	return string(r.body)
+}
+
+// JSON decodes the body into v.
+func (r *Response) JSON(v interface{}) error {
+	return json.Unmarshal(r.body, v)
+}
+
+// IsSuccess returns true when the status is 2xx.
+func (r *Response) IsSuccess() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// Error returns *Error of the response, nil when it is success.
+func (r *Response) Error() error {
+	if r.IsSuccess() {
+		return nil
+	}
+
+	return &Error{StatusCode: r.StatusCode, Body: r.body, Response: r}
+}
+
+// Error of non 2xx response.
+type Error struct {
+	StatusCode int
+	Body       []byte
+	Response   *Response
+}
+
+// Error implement error type interfaces
+func (e *Error) Error() string {
+	msg := string(e.Body)
+	if len(msg) > 200 {
+		msg = msg[:200] + "..."
+	}
+
+	return "client: " + strconv.Itoa(e.StatusCode) + " " + http.StatusText(e.StatusCode) + ", " + msg
+}
@@ -0,0 +1,172 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen returned when the circuit of the host is open.
+var ErrCircuitOpen = errors.New("client: circuit breaker is open")
+
+// RetryPolicy defines when and how long to wait before retrying the request.
+type RetryPolicy struct {
+	MaxRetries int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// RetryOn decides whether the attempt should be retried, default retries
+	// network errors, 429 and 502-504 of idempotent methods.
+	RetryOn func(req *http.Request, res *Response, err error) bool
+}
+
+var noRetry = &RetryPolicy{}
+
+// DefaultRetry retries 3 times with exponential backoff from 100ms up to 2s.
+func DefaultRetry() *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries: 3,
+		MinBackoff: 100 * time.Millisecond,
+		MaxBackoff: 2 * time.Second,
+	}
+}
+
+func (p *RetryPolicy) retryable(req *http.Request, res *Response, err error) bool {
+	if p.RetryOn != nil {
+		return p.RetryOn(req, res, err)
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// backoff returns the duration before next attempt, Retry-After
+// header of the response is respected up to MaxBackoff.
+func (p *RetryPolicy) backoff(attempt int, res *Response) time.Duration {
+	if res != nil {
+		if s, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil {
+			d := time.Duration(s) * time.Second
+			if p.MaxBackoff > 0 && d > p.MaxBackoff {
+				d = p.MaxBackoff
+			}
+			return d
+		}
+	}
+
+	d := p.MinBackoff << uint(attempt)
+	if p.MaxBackoff > 0 && (d > p.MaxBackoff || d <= 0) {
+		d = p.MaxBackoff
+	}
+
+	// full jitter on the second half
+	if d > 1 {
+		d = d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+	}
+
+	return d
+}
+
+// Breaker is per host circuit breaker, the circuit is opened after Threshold
+// consecutive failures and half opened after Cooldown allowing single trial.
+type Breaker struct {
+	Threshold int
+	Cooldown  time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*circuit
+	now   func() time.Time
+}
+
+type circuit struct {
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+// NewBreaker creates circuit breaker.
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		Threshold: threshold,
+		Cooldown:  cooldown,
+		hosts:     make(map[string]*circuit),
+		now:       time.Now,
+	}
+}
+
+// Allow returns true when request into the host is allowed.
+func (b *Breaker) Allow(host string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.hosts[host]
+	if c == nil || c.failures < b.Threshold {
+		return true
+	}
+
+	if c.trial || b.now().Sub(c.openedAt) < b.Cooldown {
+		return false
+	}
+
+	c.trial = true
+	return true
+}
+
+// Done records result of the request into the host.
+func (b *Breaker) Done(host string, success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.hosts[host]
+	if c == nil {
+		c = new(circuit)
+		b.hosts[host] = c
+	}
+
+	c.trial = false
+	if success {
+		c.failures = 0
+		return
+	}
+
+	c.failures++
+	if c.failures >= b.Threshold {
+		c.openedAt = b.now()
+	}
+}
+
+// State returns "closed", "open" or "half-open" state of the host.
+func (b *Breaker) State(host string) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.hosts[host]
+	switch {
+	case c == nil || c.failures < b.Threshold:
+		return "closed"
+	case c.trial || b.now().Sub(c.openedAt) >= b.Cooldown:
+		return "half-open"
+	}
+
+	return "open"
+}
@@ -0,0 +1,65 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	"github.com/enigma-id/go/rest"
+)
+
+// Trace context headers, see https://www.w3.org/TR/trace-context
+const (
+	HeaderTraceparent = "Traceparent"
+	HeaderTracestate  = "Tracestate"
+)
+
+// From propagates context, request id and trace context of the incoming request,
+// the trace id is kept and new parent id is generated for the outgoing request.
+func (r *Request) From(c *rest.Context) *Request {
+	req := c.Request()
+	r.ctx = req.Context()
+
+	rid := req.Header.Get(rest.HeaderXRequestID)
+	if rid == "" {
+		rid = c.Response().Header().Get(rest.HeaderXRequestID)
+	}
+	if rid != "" {
+		r.header.Set(rest.HeaderXRequestID, rid)
+	}
+
+	r.header.Set(HeaderTraceparent, Traceparent(req.Header.Get(HeaderTraceparent)))
+	if ts := req.Header.Get(HeaderTracestate); ts != "" {
+		r.header.Set(HeaderTracestate, ts)
+	}
+
+	return r
+}
+
+// Traceparent returns traceparent of child span of the parent traceparent,
+// new trace is started when the parent is empty or invalid.
+func Traceparent(parent string) string {
+	p := strings.Split(parent, "-")
+	if len(p) != 4 || len(p[0]) != 2 || len(p[1]) != 32 || len(p[2]) != 16 || len(p[3]) != 2 ||
+		p[0] == "ff" || !isHex(p[1]+p[2]+p[3]) || p[1] == strings.Repeat("0", 32) {
+		return "00-" + randomHex(16) + "-" + randomHex(8) + "-01"
+	}
+
+	return p[0] + "-" + p[1] + "-" + randomHex(8) + "-" + p[3]
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+func isHex(s string) bool {
+	_, err := hex.DecodeString(s)
+	return err == nil && strings.ToLower(s) == s
+}
@@ -0,0 +1,98 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package crypto
+
+import (
+	"time"
+
+	"github.com/enigma-id/go/cache"
+)
+
+type (
+	// Cache wraps cache.Cache, the tagged fields are encrypted
+	// before the value is cached and decrypted on read.
+	Cache struct {
+		cache.Cache
+		keyring *Keyring
+	}
+
+	getter struct {
+		cache.Getter
+		keyring *Keyring
+	}
+)
+
+// NewCache wraps the cache with field encryption of the keyring.
+//
+//	cache.Instance = crypto.NewCache(cache.Instance, k)
+func NewCache(c cache.Cache, k *Keyring) *Cache {
+	return &Cache{Cache: c, keyring: k}
+}
+
+// Get implements cache.Cache interfaces.
+func (c *Cache) Get(key string, ptrValue interface{}) error {
+	if err := c.Cache.Get(key, ptrValue); err != nil {
+		return err
+	}
+
+	return c.keyring.decode(ptrValue)
+}
+
+// GetMulti implements cache.Cache interfaces.
+func (c *Cache) GetMulti(keys ...string) (cache.Getter, error) {
+	g, err := c.Cache.GetMulti(keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &getter{Getter: g, keyring: c.keyring}, nil
+}
+
+// Set implements cache.Cache interfaces.
+func (c *Cache) Set(key string, value interface{}, expires time.Duration) error {
+	v, err := c.keyring.Seal(value)
+	if err != nil {
+		return err
+	}
+
+	return c.Cache.Set(key, v, expires)
+}
+
+// Add implements cache.Cache interfaces.
+func (c *Cache) Add(key string, value interface{}, expires time.Duration) error {
+	v, err := c.keyring.Seal(value)
+	if err != nil {
+		return err
+	}
+
+	return c.Cache.Add(key, v, expires)
+}
+
+// Replace implements cache.Cache interfaces.
+func (c *Cache) Replace(key string, value interface{}, expires time.Duration) error {
+	v, err := c.keyring.Seal(value)
+	if err != nil {
+		return err
+	}
+
+	return c.Cache.Replace(key, v, expires)
+}
+
+func (g *getter) Get(key string, ptrValue interface{}) error {
+	if err := g.Getter.Get(key, ptrValue); err != nil {
+		return err
+	}
+
+	return g.keyring.decode(ptrValue)
+}
+
+// decode decrypts the fields of the pointer, value without tagged field is ignored.
+func (k *Keyring) decode(ptrValue interface{}) error {
+	if err := k.DecryptFields(ptrValue); err != ErrNotPointer {
+		return err
+	}
+
+	return nil
+}
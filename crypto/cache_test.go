@@ -0,0 +1,39 @@
+package crypto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/enigma-id/go/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	k := keyring(t, "k1")
+	m := cache.NewMemory()
+	c := NewCache(m, k)
+
+	in := &customer{ID: 1, NIK: "3171234567890001"}
+	assert.NoError(t, c.Set("customer:1", in, time.Minute))
+	assert.Equal(t, "3171234567890001", in.NIK)
+	var raw []byte
+	assert.NoError(t, m.Get("customer:1", &raw))
+	assert.NotContains(t, string(raw), "3171234567890001")
+
+	out := new(customer)
+	if assert.NoError(t, c.Get("customer:1", out)) {
+		assert.Equal(t, "3171234567890001", out.NIK)
+	}
+
+	g, _ := c.GetMulti("customer:1")
+	out = new(customer)
+	if assert.NoError(t, g.Get("customer:1", out)) {
+		assert.Equal(t, "3171234567890001", out.NIK)
+	}
+
+	// value without tagged field is passed
+	assert.NoError(t, c.Set("count", 12, time.Minute))
+	var n int
+	assert.NoError(t, c.Get("count", &n))
+	assert.Equal(t, 12, n)
+}
@@ -0,0 +1,151 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Prefix of the encrypted value, the format is "enc:<key id>:<base64 of nonce and ciphertext>".
+const Prefix = "enc:"
+
+var (
+	// ErrUnknownKey returned when the value is encrypted by key that is not in the keyring.
+	ErrUnknownKey = errors.New("crypto: unknown key id")
+	// ErrMalformed returned when the encrypted value can't be decoded or authenticated.
+	ErrMalformed = errors.New("crypto: malformed encrypted value")
+)
+
+// Keyring holds AES-GCM keys by its id, values are always encrypted using
+// the primary key and decrypted using the key of the value, so the primary
+// key can be rotated while the old values are still readable.
+type Keyring struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// NewKeyring creates keyring of the keys, the key should be 16, 24 or 32 bytes
+// for AES-128, AES-192 or AES-256. The primary is the id of the encryption key.
+//
+//	k, err := crypto.NewKeyring("2019-08", map[string][]byte{
+//		"2019-01": oldKey,
+//		"2019-08": newKey,
+//	})
+func NewKeyring(primary string, keys map[string][]byte) (*Keyring, error) {
+	k := &Keyring{primary: primary, keys: make(map[string]cipher.AEAD, len(keys))}
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("crypto: invalid key id %q", id)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("crypto: key %s: %v", id, err)
+		}
+
+		if k.keys[id], err = cipher.NewGCM(block); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, ok := k.keys[primary]; !ok {
+		return nil, ErrUnknownKey
+	}
+
+	return k, nil
+}
+
+// Primary returns id of the encryption key.
+func (k *Keyring) Primary() string {
+	return k.primary
+}
+
+// Encrypt encrypts the plain text using the primary key.
+func (k *Keyring) Encrypt(plain []byte) ([]byte, error) {
+	aead := k.keys[k.primary]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	sealed := aead.Seal(nonce, nonce, plain, []byte(k.primary))
+	b := make([]byte, 0, len(Prefix)+len(k.primary)+1+base64.RawURLEncoding.EncodedLen(len(sealed)))
+	b = append(b, Prefix+k.primary+":"...)
+
+	return append(b, base64.RawURLEncoding.EncodeToString(sealed)...), nil
+}
+
+// Decrypt decrypts the value encrypted by Encrypt, value that is not
+// encrypted (doesn't have the prefix) is returned as is, so existing
+// plain values can be migrated gradually.
+func (k *Keyring) Decrypt(value []byte) ([]byte, error) {
+	id, data, ok := parse(string(value))
+	if !ok {
+		return value, nil
+	}
+
+	aead, ok := k.keys[id]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	sealed, err := base64.RawURLEncoding.DecodeString(data)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+
+	n := aead.NonceSize()
+	plain, err := aead.Open(nil, sealed[:n], sealed[n:], []byte(id))
+	if err != nil {
+		return nil, ErrMalformed
+	}
+
+	return plain, nil
+}
+
+// EncryptString is Encrypt of string.
+func (k *Keyring) EncryptString(plain string) (string, error) {
+	b, err := k.Encrypt([]byte(plain))
+	return string(b), err
+}
+
+// DecryptString is Decrypt of string.
+func (k *Keyring) DecryptString(value string) (string, error) {
+	b, err := k.Decrypt([]byte(value))
+	return string(b), err
+}
+
+// KeyID returns id of the key used to encrypt the value,
+// empty when the value is not encrypted.
+func KeyID(value string) string {
+	id, _, _ := parse(value)
+	return id
+}
+
+// IsEncrypted returns true when the value has the encrypted prefix.
+func IsEncrypted(value string) bool {
+	_, _, ok := parse(value)
+	return ok
+}
+
+func parse(value string) (id, data string, ok bool) {
+	if !strings.HasPrefix(value, Prefix) {
+		return "", "", false
+	}
+
+	value = value[len(Prefix):]
+	i := strings.IndexByte(value, ':')
+	if i <= 0 {
+		return "", "", false
+	}
+
+	return value[:i], value[i+1:], true
+}
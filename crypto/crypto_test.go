@@ -0,0 +1,68 @@
+package crypto
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func keyring(t *testing.T, primary string) *Keyring {
+	k, err := NewKeyring(primary, map[string][]byte{
+		"k1": bytes.Repeat([]byte{1}, 32),
+		"k2": bytes.Repeat([]byte{2}, 32),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestNewKeyring(t *testing.T) {
+	_, err := NewKeyring("k1", map[string][]byte{"k1": []byte("short")})
+	assert.Error(t, err)
+
+	_, err = NewKeyring("k3", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 16)})
+	assert.Equal(t, ErrUnknownKey, err)
+
+	_, err = NewKeyring("k:1", map[string][]byte{"k:1": bytes.Repeat([]byte{1}, 16)})
+	assert.Error(t, err)
+}
+
+func TestEncrypt(t *testing.T) {
+	k := keyring(t, "k1")
+
+	v, err := k.EncryptString("3171234567890001")
+	if assert.NoError(t, err) {
+		assert.True(t, strings.HasPrefix(v, "enc:k1:"))
+		assert.Equal(t, "k1", KeyID(v))
+		assert.NotContains(t, v, "3171234567890001")
+
+		other, _ := k.EncryptString("3171234567890001")
+		assert.NotEqual(t, v, other)
+
+		p, err := k.DecryptString(v)
+		assert.NoError(t, err)
+		assert.Equal(t, "3171234567890001", p)
+	}
+
+	// plain value is passed
+	p, err := k.DecryptString("plain")
+	assert.NoError(t, err)
+	assert.Equal(t, "plain", p)
+
+	// tampered value
+	_, err = k.DecryptString(v[:len(v)-2] + "AA")
+	assert.Equal(t, ErrMalformed, err)
+
+	// old key is still readable after rotation
+	k2 := keyring(t, "k2")
+	p, err = k2.DecryptString(v)
+	assert.NoError(t, err)
+	assert.Equal(t, "3171234567890001", p)
+
+	only, _ := NewKeyring("k2", map[string][]byte{"k2": bytes.Repeat([]byte{2}, 32)})
+	_, err = only.DecryptString(v)
+	assert.Equal(t, ErrUnknownKey, err)
+}
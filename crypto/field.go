@@ -0,0 +1,276 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Tag of the struct field that should be encrypted, ex. `encrypt:"true"`,
+// the field should be string, *string or []byte.
+const Tag = "encrypt"
+
+// ErrNotPointer returned when the fields can't be modified in place.
+var ErrNotPointer = errors.New("crypto: value should be pointer or slice")
+
+var (
+	// sensitives caches whether the type has encrypted field.
+	sensitives sync.Map
+	bytesType  = reflect.TypeOf([]byte(nil))
+)
+
+// EncryptFields encrypts the tagged fields of the struct in place,
+// nested struct, pointer and slice are traversed. Empty and already
+// encrypted values are kept as is.
+//
+//	type Customer struct {
+//		ID    int64
+//		Name  string
+//		NIK   string `encrypt:"true"`
+//		Phone string `encrypt:"true"`
+//	}
+func (k *Keyring) EncryptFields(i interface{}) error {
+	return k.fields(i, func(b []byte) ([]byte, error) {
+		if IsEncrypted(string(b)) {
+			return b, nil
+		}
+		return k.Encrypt(b)
+	})
+}
+
+// DecryptFields decrypts the tagged fields of the struct in place.
+func (k *Keyring) DecryptFields(i interface{}) error {
+	return k.fields(i, k.Decrypt)
+}
+
+// Rotate re-encrypts the tagged fields that are not encrypted by the primary
+// key, it returns true when any field is changed so it should be persisted.
+func (k *Keyring) Rotate(i interface{}) (changed bool, err error) {
+	err = k.fields(i, func(b []byte) ([]byte, error) {
+		if id := KeyID(string(b)); id == "" || id == k.primary {
+			return b, nil
+		}
+
+		plain, err := k.Decrypt(b)
+		if err != nil {
+			return nil, err
+		}
+
+		changed = true
+		return k.Encrypt(plain)
+	})
+
+	return
+}
+
+// Seal returns copy of the value with the tagged fields encrypted,
+// the value itself is not modified, so it can still be used after
+// being persisted or cached.
+func (k *Keyring) Seal(i interface{}) (interface{}, error) {
+	v := reflect.ValueOf(i)
+	if !v.IsValid() || !sensitive(v.Type()) {
+		return i, nil
+	}
+
+	c := clone(v)
+	if c.Kind() == reflect.Ptr || c.Kind() == reflect.Slice {
+		return c.Interface(), k.EncryptFields(c.Interface())
+	}
+
+	p := reflect.New(c.Type())
+	p.Elem().Set(c)
+	if err := k.EncryptFields(p.Interface()); err != nil {
+		return nil, err
+	}
+
+	return p.Elem().Interface(), nil
+}
+
+// Encode implements db.Codec, arguments of named query are encrypted.
+func (k *Keyring) Encode(arg interface{}) (interface{}, error) {
+	return k.Seal(arg)
+}
+
+// Decode implements db.Codec, scanned rows are decrypted.
+func (k *Keyring) Decode(dest interface{}) error {
+	return k.DecryptFields(dest)
+}
+
+func (k *Keyring) fields(i interface{}, fn func([]byte) ([]byte, error)) error {
+	v := reflect.ValueOf(i)
+	if v.Kind() != reflect.Ptr && v.Kind() != reflect.Slice {
+		return ErrNotPointer
+	}
+
+	return walk(v, fn)
+}
+
+// walk applies the function into the tagged fields.
+func walk(v reflect.Value, fn func([]byte) ([]byte, error)) error {
+	if !sensitive(v.Type()) {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			return walk(v.Elem(), fn)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := walk(v.Index(i), fn); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf, f := t.Field(i), v.Field(i)
+			if sf.PkgPath != "" || !f.CanSet() {
+				continue
+			}
+
+			var err error
+			if sf.Tag.Get(Tag) == "true" {
+				err = apply(f, fn)
+			} else {
+				err = walk(f, fn)
+			}
+
+			if err != nil {
+				return fmt.Errorf("crypto: field %s: %v", sf.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// apply sets the field with the result of the function, pointer is
+// replaced instead of modified since it may be shared with a copy.
+func apply(f reflect.Value, fn func([]byte) ([]byte, error)) error {
+	switch {
+	case f.Kind() == reflect.String:
+		if f.Len() == 0 {
+			return nil
+		}
+
+		b, err := fn([]byte(f.String()))
+		if err != nil {
+			return err
+		}
+		f.SetString(string(b))
+	case f.Type() == bytesType:
+		if f.Len() == 0 {
+			return nil
+		}
+
+		b, err := fn(f.Bytes())
+		if err != nil {
+			return err
+		}
+		f.SetBytes(b)
+	case f.Kind() == reflect.Ptr && f.Type().Elem().Kind() == reflect.String:
+		if f.IsNil() || f.Elem().Len() == 0 {
+			return nil
+		}
+
+		b, err := fn([]byte(f.Elem().String()))
+		if err != nil {
+			return err
+		}
+
+		p := reflect.New(f.Type().Elem())
+		p.Elem().SetString(string(b))
+		f.Set(p)
+	default:
+		return fmt.Errorf("unsupported type %s", f.Type())
+	}
+
+	return nil
+}
+
+// clone copies the value deep enough, so encrypting the copy
+// doesn't modify the original.
+func clone(v reflect.Value) reflect.Value {
+	if !sensitive(v.Type()) {
+		return v
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+
+		p := reflect.New(v.Type().Elem())
+		p.Elem().Set(clone(v.Elem()))
+		return p
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+
+		s := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			s.Index(i).Set(clone(v.Index(i)))
+		}
+		return s
+	case reflect.Array:
+		a := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			a.Index(i).Set(clone(v.Index(i)))
+		}
+		return a
+	case reflect.Struct:
+		s := reflect.New(v.Type()).Elem()
+		s.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if s.Field(i).CanSet() {
+				s.Field(i).Set(clone(v.Field(i)))
+			}
+		}
+		return s
+	}
+
+	return v
+}
+
+// sensitive returns true when the type has tagged field.
+func sensitive(t reflect.Type) bool {
+	if s, ok := sensitives.Load(t); ok {
+		return s.(bool)
+	}
+
+	s := sensitiveOf(t, make(map[reflect.Type]bool))
+	sensitives.Store(t, s)
+
+	return s
+}
+
+// sensitiveOf checks the type, seen is the guard of recursive type.
+func sensitiveOf(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return sensitiveOf(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if sf.PkgPath == "" && (sf.Tag.Get(Tag) == "true" || sensitiveOf(sf.Type, seen)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
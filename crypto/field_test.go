@@ -0,0 +1,113 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	address struct {
+		Street string `encrypt:"true"`
+		City   string
+	}
+
+	customer struct {
+		ID        int64
+		Name      string
+		NIK       string  `encrypt:"true"`
+		Phone     *string `encrypt:"true"`
+		Secret    []byte  `encrypt:"true"`
+		Address   *address
+		Addresses []address
+	}
+)
+
+func TestFields(t *testing.T) {
+	k := keyring(t, "k1")
+	phone := "08123456789"
+	c := &customer{
+		ID:        1,
+		Name:      "Jon",
+		NIK:       "3171234567890001",
+		Phone:     &phone,
+		Secret:    []byte("secret"),
+		Address:   &address{Street: "Jl. Sudirman", City: "Jakarta"},
+		Addresses: []address{{Street: "Jl. Braga", City: "Bandung"}},
+	}
+
+	assert.NoError(t, k.EncryptFields(c))
+	assert.Equal(t, "Jon", c.Name)
+	assert.True(t, IsEncrypted(c.NIK))
+	assert.True(t, IsEncrypted(*c.Phone))
+	assert.True(t, IsEncrypted(string(c.Secret)))
+	assert.True(t, IsEncrypted(c.Address.Street))
+	assert.Equal(t, "Jakarta", c.Address.City)
+	assert.True(t, IsEncrypted(c.Addresses[0].Street))
+	assert.Equal(t, "08123456789", phone)
+
+	// encrypting twice is no op
+	nik := c.NIK
+	assert.NoError(t, k.EncryptFields(c))
+	assert.Equal(t, nik, c.NIK)
+
+	assert.NoError(t, k.DecryptFields(c))
+	assert.Equal(t, "3171234567890001", c.NIK)
+	assert.Equal(t, "08123456789", *c.Phone)
+	assert.Equal(t, "secret", string(c.Secret))
+	assert.Equal(t, "Jl. Sudirman", c.Address.Street)
+	assert.Equal(t, "Jl. Braga", c.Addresses[0].Street)
+
+	assert.Equal(t, ErrNotPointer, k.EncryptFields(*c))
+
+	var invalid struct {
+		Age int `encrypt:"true"`
+	}
+	invalid.Age = 12
+	assert.Error(t, k.EncryptFields(&invalid))
+}
+
+func TestSeal(t *testing.T) {
+	k := keyring(t, "k1")
+	c := &customer{NIK: "3171234567890001", Address: &address{Street: "Jl. Sudirman"}, Addresses: []address{{Street: "Jl. Braga"}}}
+
+	v, err := k.Seal(c)
+	if assert.NoError(t, err) {
+		s := v.(*customer)
+		assert.True(t, IsEncrypted(s.NIK))
+		assert.True(t, IsEncrypted(s.Address.Street))
+		assert.True(t, IsEncrypted(s.Addresses[0].Street))
+
+		// original is not modified
+		assert.Equal(t, "3171234567890001", c.NIK)
+		assert.Equal(t, "Jl. Sudirman", c.Address.Street)
+		assert.Equal(t, "Jl. Braga", c.Addresses[0].Street)
+	}
+
+	v, err = k.Seal(*c)
+	if assert.NoError(t, err) {
+		assert.True(t, IsEncrypted(v.(customer).NIK))
+	}
+
+	v, err = k.Seal(map[string]interface{}{"nik": "3171234567890001"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"nik": "3171234567890001"}, v)
+}
+
+func TestRotate(t *testing.T) {
+	c := &customer{NIK: "3171234567890001"}
+	assert.NoError(t, keyring(t, "k1").EncryptFields(c))
+
+	k := keyring(t, "k2")
+	changed, err := k.Rotate(c)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "k2", KeyID(c.NIK))
+
+	changed, err = k.Rotate(c)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	assert.NoError(t, k.DecryptFields(c))
+	assert.Equal(t, "3171234567890001", c.NIK)
+}
@@ -0,0 +1,122 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// queryer is implemented by sql.DB and sql.Tx.
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// conn implements Querier on top of sql.DB or sql.Tx.
+type conn struct {
+	q  queryer
+	db *DB
+}
+
+// Exec executes query without returning any rows.
+func (c *conn) Exec(ctx context.Context, query string, args ...interface{}) (res sql.Result, err error) {
+	t := time.Now()
+	res, err = c.q.ExecContext(ctx, query, args...)
+	c.db.after(ctx, query, args, t, err)
+
+	return
+}
+
+// Query executes query that returns rows.
+func (c *conn) Query(ctx context.Context, query string, args ...interface{}) (rows *sql.Rows, err error) {
+	t := time.Now()
+	rows, err = c.q.QueryContext(ctx, query, args...)
+	c.db.after(ctx, query, args, t, err)
+
+	return
+}
+
+// Get executes query and scan the first row into dest,
+// dest can be pointer of struct or scalar value.
+// Returns sql.ErrNoRows when query doesn't returns any rows.
+func (c *conn) Get(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	rows, err := c.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if err = scanOne(rows, dest); err != nil {
+		return err
+	}
+
+	return c.decode(dest)
+}
+
+// Select executes query and scan all rows into dest,
+// dest should be pointer of slice.
+func (c *conn) Select(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	rows, err := c.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if err = scanAll(rows, dest); err != nil {
+		return err
+	}
+
+	return c.decode(dest)
+}
+
+// NamedExec executes query with named parameters, see Named.
+func (c *conn) NamedExec(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	q, args, err := c.named(query, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Exec(ctx, q, args...)
+}
+
+// NamedGet same as Get with named parameters.
+func (c *conn) NamedGet(ctx context.Context, dest interface{}, query string, arg interface{}) error {
+	q, args, err := c.named(query, arg)
+	if err != nil {
+		return err
+	}
+
+	return c.Get(ctx, dest, q, args...)
+}
+
+// NamedSelect same as Select with named parameters.
+func (c *conn) NamedSelect(ctx context.Context, dest interface{}, query string, arg interface{}) error {
+	q, args, err := c.named(query, arg)
+	if err != nil {
+		return err
+	}
+
+	return c.Select(ctx, dest, q, args...)
+}
+
+func (c *conn) named(query string, arg interface{}) (string, []interface{}, error) {
+	if c.db.codec != nil {
+		a, err := c.db.codec.Encode(arg)
+		if err != nil {
+			return "", nil, err
+		}
+		arg = a
+	}
+
+	return bindNamed(query, arg, bindTypeOf(c.db.driver))
+}
+
+func (c *conn) decode(dest interface{}) error {
+	if c.db.codec == nil {
+		return nil
+	}
+
+	return c.db.codec.Decode(dest)
+}
@@ -0,0 +1,174 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/enigma-id/go/env"
+)
+
+// Default database instances used by package level functions, sets by Connect.
+var Default *DB
+
+// ErrNoDefault error when the package level functions called before Connect.
+var ErrNoDefault = errors.New("db: default database is not connected")
+
+type (
+	// Querier is the common interfaces of DB and Tx, repositories
+	// should depend on this so it can be used inside and outside transaction.
+	Querier interface {
+		Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+		Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+		Get(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+		Select(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+		NamedExec(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+		NamedGet(ctx context.Context, dest interface{}, query string, arg interface{}) error
+		NamedSelect(ctx context.Context, dest interface{}, query string, arg interface{}) error
+	}
+
+	// DB wraps sql.DB with named parameters, struct scanning,
+	// transaction helpers and query instrumentation.
+	DB struct {
+		conn
+		SQL *sql.DB
+
+		// SlowThreshold query that takes longer than this is logged as warning,
+		// zero means slow query log is disabled.
+		SlowThreshold time.Duration
+
+		// MaxRetries number of retry of InTx when the transaction
+		// failed because of serialization failure or deadlock.
+		MaxRetries int
+
+		driver  string
+		hooks   []Hook
+		codec   Codec
+		queries int64
+		slow    int64
+		errors  int64
+		retries int64
+	}
+
+	// Codec transforms the struct argument of named query before it's
+	// bound and the destination after it's scanned, ex. crypto.Keyring
+	// encrypts the sensitive fields.
+	Codec interface {
+		Encode(arg interface{}) (interface{}, error)
+		Decode(dest interface{}) error
+	}
+
+	// Option configures the DB instances.
+	Option func(*DB)
+)
+
+// WithSlowThreshold sets slow query threshold.
+func WithSlowThreshold(d time.Duration) Option {
+	return func(db *DB) {
+		db.SlowThreshold = d
+	}
+}
+
+// WithMaxRetries sets maximum retry of transaction.
+func WithMaxRetries(n int) Option {
+	return func(db *DB) {
+		db.MaxRetries = n
+	}
+}
+
+// WithHook adds hook that called after each query.
+func WithHook(h Hook) Option {
+	return func(db *DB) {
+		db.hooks = append(db.hooks, h)
+	}
+}
+
+// WithCodec sets codec of named query arguments and scanned rows.
+func WithCodec(c Codec) Option {
+	return func(db *DB) {
+		db.codec = c
+	}
+}
+
+// Open opens database with the driver, the driver should be imported by the application.
+func Open(driver, dsn string, opts ...Option) (*DB, error) {
+	s, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(s, driver, opts...), nil
+}
+
+// New wraps existing sql.DB.
+func New(s *sql.DB, driver string, opts ...Option) *DB {
+	db := &DB{
+		SQL:           s,
+		SlowThreshold: 200 * time.Millisecond,
+		MaxRetries:    3,
+		driver:        driver,
+	}
+	db.conn = conn{q: s, db: db}
+
+	for _, o := range opts {
+		o(db)
+	}
+
+	return db
+}
+
+// Connect opens the default database using mysql configurations
+// from environment variable (MYSQL_HOST, MYSQL_DB, MYSQL_USER, MYSQL_PASS),
+// DB_SLOW_THRESHOLD can be used to sets slow query threshold in milliseconds.
+func Connect(opts ...Option) (err error) {
+	dsn := fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=utf8mb4&parseTime=true&loc=Local",
+		env.GetString("MYSQL_USER", "root"),
+		env.GetString("MYSQL_PASS", ""),
+		env.GetString("MYSQL_HOST", "127.0.0.1:3306"),
+		env.GetString("MYSQL_DB", ""),
+	)
+
+	opts = append([]Option{WithSlowThreshold(time.Duration(env.GetInt("DB_SLOW_THRESHOLD", 200)) * time.Millisecond)}, opts...)
+	if Default, err = Open(env.GetString("DB_DRIVER", "mysql"), dsn, opts...); err == nil {
+		err = Default.SQL.Ping()
+	}
+
+	return
+}
+
+// Driver returns driver name of the database.
+func (db *DB) Driver() string {
+	return db.driver
+}
+
+// Close closes the database.
+func (db *DB) Close() error {
+	return db.SQL.Close()
+}
+
+// Stats returns connection pool and query metrics of the database.
+func (db *DB) Stats() Stats {
+	return Stats{
+		DBStats:     db.SQL.Stats(),
+		Queries:     atomic.LoadInt64(&db.queries),
+		SlowQueries: atomic.LoadInt64(&db.slow),
+		Errors:      atomic.LoadInt64(&db.errors),
+		TxRetries:   atomic.LoadInt64(&db.retries),
+	}
+}
+
+// InTx runs fn inside transaction of the default database.
+func InTx(ctx context.Context, fn TxFunc) error {
+	if Default == nil {
+		return ErrNoDefault
+	}
+
+	return Default.InTx(ctx, fn)
+}
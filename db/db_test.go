@@ -0,0 +1,237 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+type Base struct {
+	ID        int64     `db:"id"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type user struct {
+	Base
+	FullName string
+	Email    sql.NullString
+	Ignored  string `db:"-"`
+}
+
+type post struct {
+	ID     int64  `orm:"column(id);auto"`
+	Author *user  `orm:"column(author_id);rel(fk)"`
+	Title  string `orm:"column(post_title)"`
+	Draft  bool   `orm:"-"`
+}
+
+func testDB(t *testing.T, opts ...Option) *DB {
+	db, err := Open("sqlite3", ":memory:", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SQL.SetMaxOpenConns(1)
+
+	_, err = db.Exec(context.Background(), "CREATE TABLE user (id INTEGER PRIMARY KEY, full_name TEXT, email TEXT, created_at DATETIME)")
+	assert.NoError(t, err)
+
+	return db
+}
+
+func TestNamed(t *testing.T) {
+	q, args, err := Named("SELECT * FROM user WHERE email = :email AND name = ':name' AND id::text = :id", map[string]interface{}{"email": "a@b.c", "id": 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM user WHERE email = ? AND name = ':name' AND id::text = ?", q)
+	assert.Equal(t, []interface{}{"a@b.c", 1}, args)
+
+	q, args, err = bindNamed("UPDATE user SET full_name = :full_name WHERE id = :id", &user{Base: Base{ID: 2}, FullName: "John"}, bindDollar)
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE user SET full_name = $1 WHERE id = $2", q)
+	assert.Equal(t, []interface{}{"John", int64(2)}, args)
+
+	_, _, err = Named("SELECT :missing", map[string]interface{}{})
+	assert.Error(t, err)
+	_, _, err = Named("SELECT :id", 1)
+	assert.Error(t, err)
+
+	assert.Equal(t, "user_id", snakeCase("UserID"))
+	assert.Equal(t, "html_body", snakeCase("HTMLBody"))
+}
+
+func TestQuery(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+
+	now := time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC)
+	for _, n := range []string{"John", "Jane"} {
+		_, err := db.NamedExec(ctx, "INSERT INTO user (full_name, email, created_at) VALUES (:full_name, :email, :created_at)", &user{
+			Base:     Base{CreatedAt: now},
+			FullName: n,
+			Email:    sql.NullString{String: n + "@example.com", Valid: true},
+		})
+		assert.NoError(t, err)
+	}
+
+	u := new(user)
+	assert.NoError(t, db.NamedGet(ctx, u, "SELECT * FROM user WHERE full_name = :name", map[string]interface{}{"name": "Jane"}))
+	assert.Equal(t, int64(2), u.ID)
+	assert.Equal(t, "Jane@example.com", u.Email.String)
+	assert.True(t, now.Equal(u.CreatedAt))
+
+	var users []*user
+	assert.NoError(t, db.Select(ctx, &users, "SELECT id, full_name, 1 AS unknown FROM user ORDER BY id"))
+	if assert.Len(t, users, 2) {
+		assert.Equal(t, "John", users[0].FullName)
+	}
+
+	var count int
+	assert.NoError(t, db.Get(ctx, &count, "SELECT COUNT(*) FROM user"))
+	assert.Equal(t, 2, count)
+
+	var names []string
+	assert.NoError(t, db.Select(ctx, &names, "SELECT full_name FROM user ORDER BY id DESC"))
+	assert.Equal(t, []string{"Jane", "John"}, names)
+
+	p := new(post)
+	assert.NoError(t, db.Get(ctx, p, "SELECT 1 AS id, 2 AS author_id, 'Hello' AS post_title, 1 AS draft"))
+	assert.Equal(t, int64(2), p.Author.ID)
+	assert.Equal(t, "Hello", p.Title)
+	assert.False(t, p.Draft)
+
+	assert.Equal(t, sql.ErrNoRows, db.Get(ctx, u, "SELECT * FROM user WHERE id = ?", 10))
+	assert.Error(t, db.Get(ctx, &count, "SELECT id, full_name FROM user"))
+}
+
+func TestInTx(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+
+	err := db.InTx(ctx, func(ctx context.Context, tx *Tx) error {
+		tx.Exec(ctx, "INSERT INTO user (full_name) VALUES ('John')")
+
+		// nested transaction joins the running transaction
+		return db.InTx(ctx, func(ctx context.Context, tx2 *Tx) error {
+			assert.Equal(t, tx, tx2)
+			return errors.New("failed")
+		})
+	})
+	assert.EqualError(t, err, "failed")
+
+	var count int
+	db.Get(ctx, &count, "SELECT COUNT(*) FROM user")
+	assert.Equal(t, 0, count)
+
+	attempts := 0
+	err = db.InTx(ctx, func(ctx context.Context, tx *Tx) error {
+		attempts++
+		tx.Exec(ctx, "INSERT INTO user (full_name) VALUES ('John')")
+		if attempts < 3 {
+			return errors.New("Error 1213: Deadlock found when trying to get lock")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, int64(2), db.Stats().TxRetries)
+
+	db.Get(ctx, &count, "SELECT COUNT(*) FROM user")
+	assert.Equal(t, 1, count)
+
+	assert.Panics(t, func() {
+		db.InTx(ctx, func(ctx context.Context, tx *Tx) error {
+			tx.Exec(ctx, "INSERT INTO user (full_name) VALUES ('Jane')")
+			panic("boom")
+		})
+	})
+	db.Get(ctx, &count, "SELECT COUNT(*) FROM user")
+	assert.Equal(t, 1, count)
+}
+
+func TestHook(t *testing.T) {
+	var events []*QueryEvent
+	db := testDB(t, WithSlowThreshold(time.Nanosecond), WithHook(func(ctx context.Context, e *QueryEvent) {
+		events = append(events, e)
+	}))
+
+	_, err := db.Exec(context.Background(), "SELECT * FROM unknown")
+	assert.Error(t, err)
+
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, "SELECT * FROM unknown", events[1].Query)
+		assert.Error(t, events[1].Err)
+	}
+
+	s := db.Stats()
+	assert.Equal(t, int64(2), s.Queries)
+	assert.Equal(t, int64(2), s.SlowQueries)
+	assert.Equal(t, int64(1), s.Errors)
+	assert.Equal(t, 1, s.MaxOpenConnections)
+
+	Default = nil
+	assert.Equal(t, ErrNoDefault, InTx(context.Background(), nil))
+}
+
+// reverseCodec reverses full name of the user.
+type reverseCodec struct{}
+
+func reverse(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
+
+func (reverseCodec) Encode(arg interface{}) (interface{}, error) {
+	if u, ok := arg.(*user); ok {
+		c := *u
+		c.FullName = reverse(u.FullName)
+		return &c, nil
+	}
+	return arg, nil
+}
+
+func (reverseCodec) Decode(dest interface{}) error {
+	switch d := dest.(type) {
+	case *user:
+		d.FullName = reverse(d.FullName)
+	case *[]*user:
+		for _, u := range *d {
+			u.FullName = reverse(u.FullName)
+		}
+	}
+	return nil
+}
+
+func TestCodec(t *testing.T) {
+	ctx := context.Background()
+	db := testDB(t, WithCodec(reverseCodec{}))
+
+	u := &user{FullName: "John"}
+	_, err := db.NamedExec(ctx, "INSERT INTO user (full_name) VALUES (:full_name)", u)
+	assert.NoError(t, err)
+	assert.Equal(t, "John", u.FullName)
+
+	var raw string
+	db.Get(ctx, &raw, "SELECT full_name FROM user")
+	assert.Equal(t, "nhoJ", raw)
+
+	got := new(user)
+	assert.NoError(t, db.Get(ctx, got, "SELECT id, full_name FROM user"))
+	assert.Equal(t, "John", got.FullName)
+
+	var users []*user
+	assert.NoError(t, db.Select(ctx, &users, "SELECT id, full_name FROM user"))
+	if assert.Len(t, users, 1) {
+		assert.Equal(t, "John", users[0].FullName)
+	}
+}
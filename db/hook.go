@@ -0,0 +1,65 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+	"time"
+
+	"github.com/enigma-id/go/utility/log"
+	"go.uber.org/zap"
+)
+
+type (
+	// QueryEvent information of executed query passed into hooks.
+	QueryEvent struct {
+		Query    string
+		Args     []interface{}
+		Start    time.Time
+		Duration time.Duration
+		Err      error
+	}
+
+	// Hook called after each query executed, can be used for tracing or metrics.
+	Hook func(ctx context.Context, e *QueryEvent)
+
+	// Stats connection pool and query metrics of the database.
+	Stats struct {
+		sql.DBStats
+		Queries     int64
+		SlowQueries int64
+		Errors      int64
+		TxRetries   int64
+	}
+)
+
+// after records the query metrics, logs slow query and calls the hooks.
+func (db *DB) after(ctx context.Context, query string, args []interface{}, start time.Time, err error) {
+	d := time.Since(start)
+
+	atomic.AddInt64(&db.queries, 1)
+	if err != nil && err != sql.ErrNoRows {
+		atomic.AddInt64(&db.errors, 1)
+	}
+
+	if db.SlowThreshold > 0 && d >= db.SlowThreshold {
+		atomic.AddInt64(&db.slow, 1)
+		log.Warn("slow query",
+			zap.String("query", query),
+			zap.Int("args", len(args)),
+			zap.Duration("duration", d),
+			zap.Error(err),
+		)
+	}
+
+	if len(db.hooks) > 0 {
+		e := &QueryEvent{Query: query, Args: args, Start: start, Duration: d, Err: err}
+		for _, h := range db.hooks {
+			h(ctx, e)
+		}
+	}
+}
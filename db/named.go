@@ -0,0 +1,128 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type bindType int
+
+const (
+	bindQuestion bindType = iota // ? used by mysql and sqlite
+	bindDollar                   // $1 used by postgres
+)
+
+func bindTypeOf(driver string) bindType {
+	switch driver {
+	case "postgres", "pgx", "cockroach":
+		return bindDollar
+	}
+
+	return bindQuestion
+}
+
+// Named converts query with named parameters into query with ? placeholder
+// and list of arguments taken from arg, arg could be map[string]interface{}
+// or struct where the name is taken from the `db` tag or snake case of field name.
+//
+//	db.Named("SELECT * FROM user WHERE email = :email AND is_active = :active", map[string]interface{}{
+//	  "email":  "john@example.com",
+//	  "active": 1,
+//	})
+func Named(query string, arg interface{}) (string, []interface{}, error) {
+	return bindNamed(query, arg, bindQuestion)
+}
+
+func bindNamed(query string, arg interface{}, bt bindType) (string, []interface{}, error) {
+	lookup, err := namedLookup(arg)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var b strings.Builder
+	var args []interface{}
+	var quote byte
+
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == ':' && i+1 < len(query) && query[i+1] == ':':
+			// postgres type cast
+			b.WriteString("::")
+			i++
+			continue
+		case c == ':' && i+1 < len(query) && isNameChar(query[i+1]):
+			j := i + 1
+			for j < len(query) && (isNameChar(query[j]) || query[j] == '.') {
+				j++
+			}
+
+			name := query[i+1 : j]
+			v, ok := lookup(name)
+			if !ok {
+				return "", nil, fmt.Errorf("db: missing named parameter %q", name)
+			}
+
+			args = append(args, v)
+			if bt == bindDollar {
+				b.WriteString("$" + strconv.Itoa(len(args)))
+			} else {
+				b.WriteByte('?')
+			}
+
+			i = j - 1
+			continue
+		}
+
+		b.WriteByte(c)
+	}
+
+	return b.String(), args, nil
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// namedLookup returns function to lookup value of the name from the arg.
+func namedLookup(arg interface{}) (func(string) (interface{}, bool), error) {
+	if m, ok := arg.(map[string]interface{}); ok {
+		return func(n string) (v interface{}, ok bool) {
+			v, ok = m[n]
+			return
+		}, nil
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(arg))
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("db: named arg should be map[string]interface{} or struct, got %T", arg)
+	}
+
+	fields := structFields(v.Type())
+	return func(n string) (interface{}, bool) {
+		f, ok := fields[n]
+		if !ok {
+			return nil, false
+		}
+
+		fv, ok := fieldByIndex(v, f)
+		if !ok {
+			return nil, true
+		}
+
+		return fv.Interface(), true
+	}, nil
+}
@@ -0,0 +1,267 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package qb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/enigma-id/go/db"
+)
+
+// InsertBuilder builds insert query.
+type InsertBuilder struct {
+	placeholder Placeholder
+	table       string
+	columns     []string
+	rows        [][]interface{}
+	err         error
+}
+
+// Insert creates insert query builder into the table.
+func Insert(table string) *InsertBuilder {
+	return &InsertBuilder{table: table, placeholder: DefaultPlaceholder}
+}
+
+// PlaceholderFormat sets placeholder format of the query.
+func (b *InsertBuilder) PlaceholderFormat(p Placeholder) *InsertBuilder {
+	b.placeholder = p
+	return b
+}
+
+// Columns sets the inserted columns.
+func (b *InsertBuilder) Columns(columns ...string) *InsertBuilder {
+	if err := checkIdentifiers(columns...); err != nil {
+		b.err = err
+	}
+
+	b.columns = columns
+	return b
+}
+
+// Values adds row of values, can be called multiple times for bulk insert.
+func (b *InsertBuilder) Values(values ...interface{}) *InsertBuilder {
+	b.rows = append(b.rows, values)
+	return b
+}
+
+// SetMap sets columns and values from the map, columns are sorted.
+func (b *InsertBuilder) SetMap(m map[string]interface{}) *InsertBuilder {
+	columns, values := sortedMap(m)
+	return b.Columns(columns...).Values(values...)
+}
+
+// ToSQL returns the query and its arguments.
+func (b *InsertBuilder) ToSQL() (string, []interface{}, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+
+	if len(b.rows) == 0 {
+		return "", nil, errors.New("qb: insert without values")
+	}
+
+	var sb strings.Builder
+	var args []interface{}
+
+	sb.WriteString("INSERT INTO " + b.table)
+	if len(b.columns) > 0 {
+		sb.WriteString(" (" + strings.Join(b.columns, ", ") + ")")
+	}
+
+	sb.WriteString(" VALUES ")
+	for i, r := range b.rows {
+		if len(b.columns) > 0 && len(r) != len(b.columns) {
+			return "", nil, errors.New("qb: number of values doesn't match the columns")
+		}
+
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("(" + placeholders(len(r)) + ")")
+		args = append(args, r...)
+	}
+
+	return rebind(b.placeholder, sb.String()), args, nil
+}
+
+// Exec executes the query.
+func (b *InsertBuilder) Exec(ctx context.Context, q db.Querier) (sql.Result, error) {
+	return exec(ctx, q, b)
+}
+
+// UpdateBuilder builds update query.
+type UpdateBuilder struct {
+	placeholder Placeholder
+	table       string
+	sets        []Cond
+	where       []Cond
+	err         error
+}
+
+// Update creates update query builder of the table.
+func Update(table string) *UpdateBuilder {
+	return &UpdateBuilder{table: table, placeholder: DefaultPlaceholder}
+}
+
+// PlaceholderFormat sets placeholder format of the query.
+func (b *UpdateBuilder) PlaceholderFormat(p Placeholder) *UpdateBuilder {
+	b.placeholder = p
+	return b
+}
+
+// Set sets column with the value.
+func (b *UpdateBuilder) Set(column string, value interface{}) *UpdateBuilder {
+	if err := checkIdentifiers(column); err != nil {
+		b.err = err
+	}
+
+	b.sets = append(b.sets, Cond{column + " = ?", []interface{}{value}})
+	return b
+}
+
+// SetExpr sets column with sql expression, ex. SetExpr("stock", "stock - ?", 1).
+func (b *UpdateBuilder) SetExpr(column string, expr string, args ...interface{}) *UpdateBuilder {
+	if err := checkIdentifiers(column); err != nil {
+		b.err = err
+	}
+
+	b.sets = append(b.sets, Cond{column + " = " + expr, args})
+	return b
+}
+
+// SetMap sets columns and values from the map, columns are sorted.
+func (b *UpdateBuilder) SetMap(m map[string]interface{}) *UpdateBuilder {
+	columns, values := sortedMap(m)
+	for i, c := range columns {
+		b.Set(c, values[i])
+	}
+
+	return b
+}
+
+// Where adds condition joined with AND.
+func (b *UpdateBuilder) Where(cond string, args ...interface{}) *UpdateBuilder {
+	b.where = append(b.where, Cond{cond, args})
+	return b
+}
+
+// WhereIn adds column IN (...) condition.
+func (b *UpdateBuilder) WhereIn(column string, values ...interface{}) *UpdateBuilder {
+	b.where = append(b.where, in(column, values, &b.err))
+	return b
+}
+
+// ToSQL returns the query and its arguments.
+func (b *UpdateBuilder) ToSQL() (string, []interface{}, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+
+	if len(b.sets) == 0 {
+		return "", nil, errors.New("qb: update without values")
+	}
+
+	var sb strings.Builder
+	var args []interface{}
+
+	sb.WriteString("UPDATE " + b.table + " SET ")
+	for i, s := range b.sets {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(s.SQL)
+		args = append(args, s.Args...)
+	}
+
+	args = writeConds(&sb, " WHERE ", b.where, args)
+
+	return rebind(b.placeholder, sb.String()), args, nil
+}
+
+// Exec executes the query.
+func (b *UpdateBuilder) Exec(ctx context.Context, q db.Querier) (sql.Result, error) {
+	return exec(ctx, q, b)
+}
+
+// DeleteBuilder builds delete query.
+type DeleteBuilder struct {
+	placeholder Placeholder
+	table       string
+	where       []Cond
+	err         error
+}
+
+// Delete creates delete query builder from the table.
+func Delete(table string) *DeleteBuilder {
+	return &DeleteBuilder{table: table, placeholder: DefaultPlaceholder}
+}
+
+// PlaceholderFormat sets placeholder format of the query.
+func (b *DeleteBuilder) PlaceholderFormat(p Placeholder) *DeleteBuilder {
+	b.placeholder = p
+	return b
+}
+
+// Where adds condition joined with AND.
+func (b *DeleteBuilder) Where(cond string, args ...interface{}) *DeleteBuilder {
+	b.where = append(b.where, Cond{cond, args})
+	return b
+}
+
+// WhereIn adds column IN (...) condition.
+func (b *DeleteBuilder) WhereIn(column string, values ...interface{}) *DeleteBuilder {
+	b.where = append(b.where, in(column, values, &b.err))
+	return b
+}
+
+// ToSQL returns the query and its arguments.
+func (b *DeleteBuilder) ToSQL() (string, []interface{}, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("DELETE FROM " + b.table)
+	args := writeConds(&sb, " WHERE ", b.where, nil)
+
+	return rebind(b.placeholder, sb.String()), args, nil
+}
+
+// Exec executes the query.
+func (b *DeleteBuilder) Exec(ctx context.Context, q db.Querier) (sql.Result, error) {
+	return exec(ctx, q, b)
+}
+
+type sqlizer interface {
+	ToSQL() (string, []interface{}, error)
+}
+
+func exec(ctx context.Context, q db.Querier, b sqlizer) (sql.Result, error) {
+	query, args, err := b.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	return q.Exec(ctx, query, args...)
+}
+
+func sortedMap(m map[string]interface{}) ([]string, []interface{}) {
+	columns := make([]string, 0, len(m))
+	for c := range m {
+		columns = append(columns, c)
+	}
+	sort.Strings(columns)
+
+	values := make([]interface{}, len(columns))
+	for i, c := range columns {
+		values[i] = m[c]
+	}
+
+	return columns, values
+}
@@ -0,0 +1,151 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package qb is lightweight sql query builder, values are always passed
+// as arguments and identifiers that usually comes from the request
+// (order by, column names) are validated so the query is safe from injection.
+//
+//	q := qb.Select("*").From("orders").Where("status = ?", s).OrderBy("-created_at").Paginate(p)
+//	err := q.Select(ctx, db.Default, &orders)
+package qb
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Placeholder format of the query arguments.
+type Placeholder int
+
+const (
+	// Question uses ? placeholder, used by mysql and sqlite.
+	Question Placeholder = iota
+	// Dollar uses $1 placeholder, used by postgres.
+	Dollar
+)
+
+// DefaultPlaceholder placeholder used by new builders.
+var DefaultPlaceholder = Question
+
+// Paginator is implemented by rest.Pagination.
+type Paginator interface {
+	Limit() int
+	Offset() int
+}
+
+// Filterer is implemented by rest.Filters.
+type Filterer interface {
+	Where() (string, []interface{})
+	OrderBy() []string
+}
+
+var identRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// ValidIdentifier reports whether s is safe to be used as column or table name.
+func ValidIdentifier(s string) bool {
+	return identRegex.MatchString(s)
+}
+
+func checkIdentifiers(idents ...string) error {
+	for _, i := range idents {
+		if !ValidIdentifier(i) {
+			return fmt.Errorf("qb: invalid identifier %q", i)
+		}
+	}
+
+	return nil
+}
+
+// Cond is condition of the query with its arguments.
+type Cond struct {
+	SQL  string
+	Args []interface{}
+}
+
+// C creates condition, used by OrWhere.
+//
+//	qb.Select().From("user").OrWhere(qb.C("email = ?", s), qb.C("phone = ?", s))
+func C(sql string, args ...interface{}) Cond {
+	return Cond{sql, args}
+}
+
+func or(conds []Cond) Cond {
+	var parts []string
+	var args []interface{}
+	for _, c := range conds {
+		parts = append(parts, "("+c.SQL+")")
+		args = append(args, c.Args...)
+	}
+
+	return Cond{strings.Join(parts, " OR "), args}
+}
+
+func in(column string, values []interface{}, errp *error) Cond {
+	if err := checkIdentifiers(column); err != nil {
+		*errp = err
+	}
+
+	if len(values) == 0 {
+		return Cond{"1 = 0", nil}
+	}
+
+	return Cond{column + " IN (" + placeholders(len(values)) + ")", values}
+}
+
+// writeConds writes conditions joined with AND.
+func writeConds(sb *strings.Builder, keyword string, conds []Cond, args []interface{}) []interface{} {
+	for i, c := range conds {
+		if i == 0 {
+			sb.WriteString(keyword)
+		} else {
+			sb.WriteString(" AND ")
+		}
+
+		if len(conds) > 1 {
+			sb.WriteString("(" + c.SQL + ")")
+		} else {
+			sb.WriteString(c.SQL)
+		}
+		args = append(args, c.Args...)
+	}
+
+	return args
+}
+
+// rebind converts ? placeholder into the placeholder format,
+// question mark inside quoted string is not replaced.
+func rebind(p Placeholder, query string) string {
+	if p != Dollar {
+		return query
+	}
+
+	var b strings.Builder
+	var quote byte
+	n := 0
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?':
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
+
+// placeholders returns n comma separated placeholder, used by WhereIn and Values.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
@@ -0,0 +1,137 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package qb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/enigma-id/go/db"
+	"github.com/enigma-id/go/rest"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+type order struct {
+	ID     int64  `orm:"column(id);auto"`
+	Code   string `orm:"column(code)"`
+	Status string `orm:"column(status)"`
+	Total  int    `orm:"column(total)"`
+}
+
+func TestSelect(t *testing.T) {
+	p := rest.NewPagination(url.Values{"page": {"2"}, "limit": {"10"}})
+
+	q, args, err := Select("o.*").From("orders o").
+		LeftJoin("customer c", "c.id = o.customer_id").
+		Where("o.status = ?", "new").
+		WhereIn("o.type", 1, 2).
+		OrWhere(C("o.code = ?", "A"), C("c.name LIKE ?", "%A%")).
+		OrderBy("-o.created_at", "id asc").
+		Paginate(p).
+		ToSQL()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT o.* FROM orders o LEFT JOIN customer c ON c.id = o.customer_id "+
+		"WHERE (o.status = ?) AND (o.type IN (?, ?)) AND ((o.code = ?) OR (c.name LIKE ?)) "+
+		"ORDER BY o.created_at DESC, id ASC LIMIT 10 OFFSET 10", q)
+	assert.Equal(t, []interface{}{"new", 1, 2, "A", "%A%"}, args)
+
+	q, args, err = Select("status", "COUNT(*)").From("orders").Where("total > ?", 10).GroupBy("status").
+		PlaceholderFormat(Dollar).CountSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM (SELECT status, COUNT(*) FROM orders WHERE total > $1 GROUP BY status) t", q)
+	assert.Equal(t, []interface{}{10}, args)
+
+	q, _, _ = Select().From("orders").WhereIn("id").ToSQL()
+	assert.Equal(t, "SELECT * FROM orders WHERE 1 = 0", q)
+
+	_, _, err = Select().From("orders").OrderBy("id; DROP TABLE orders").ToSQL()
+	assert.Error(t, err)
+	_, _, err = Select().From("orders").OrderBy("id sideways").ToSQL()
+	assert.Error(t, err)
+	_, _, err = Select().ToSQL()
+	assert.Error(t, err)
+}
+
+func TestSelectFilter(t *testing.T) {
+	e := rest.New()
+	req := httptest.NewRequest(http.MethodGet, "/?filter[status]=paid&filter[total][gte]=100&sort=-created_at", nil)
+	f, err := rest.ParseFilters(e.NewContext(req, nil), []string{"status", "total", "created_at"})
+	assert.NoError(t, err)
+
+	q, args, err := Select().From("orders").Where("deleted_at IS NULL").Filter(f).ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM orders WHERE (deleted_at IS NULL) AND (status = ? AND total >= ?) ORDER BY created_at DESC", q)
+	assert.Equal(t, []interface{}{"paid", "100"}, args)
+
+	q, _, _ = Select().From("orders").Filter(&rest.Filters{}).ToSQL()
+	assert.Equal(t, "SELECT * FROM orders", q)
+}
+
+func TestMutate(t *testing.T) {
+	q, args, err := Insert("orders").Columns("code", "total").Values("A", 1).Values("B", 2).ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO orders (code, total) VALUES (?, ?), (?, ?)", q)
+	assert.Equal(t, []interface{}{"A", 1, "B", 2}, args)
+
+	_, _, err = Insert("orders").Columns("code", "total").Values("A").ToSQL()
+	assert.Error(t, err)
+
+	q, args, err = Update("orders").SetMap(map[string]interface{}{"status": "paid", "code": "A"}).
+		SetExpr("total", "total + ?", 1).Where("id = ?", 1).PlaceholderFormat(Dollar).ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE orders SET code = $1, status = $2, total = total + $3 WHERE id = $4", q)
+	assert.Equal(t, []interface{}{"A", "paid", 1, 1}, args)
+
+	_, _, err = Update("orders").Set("status = 1 --", 1).ToSQL()
+	assert.Error(t, err)
+
+	q, args, _ = Delete("orders").WhereIn("id", 1, 2).ToSQL()
+	assert.Equal(t, "DELETE FROM orders WHERE id IN (?, ?)", q)
+	assert.Equal(t, []interface{}{1, 2}, args)
+}
+
+func TestExec(t *testing.T) {
+	d, err := db.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.SQL.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	d.Exec(ctx, "CREATE TABLE orders (id INTEGER PRIMARY KEY, code TEXT, status TEXT, total INTEGER)")
+
+	ins := Insert("orders").Columns("code", "status", "total")
+	for i, c := range []string{"A", "B", "C"} {
+		ins.Values(c, "new", i+1)
+	}
+	_, err = ins.Exec(ctx, d)
+	assert.NoError(t, err)
+
+	_, err = Update("orders").Set("status", "paid").Where("code = ?", "B").Exec(ctx, d)
+	assert.NoError(t, err)
+
+	var orders []*order
+	total, err := Select().From("orders").Where("status = ?", "new").OrderBy("-total").Limit(1).Paged(ctx, d, &orders)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	if assert.Len(t, orders, 1) {
+		assert.Equal(t, "C", orders[0].Code)
+	}
+
+	o := new(order)
+	assert.NoError(t, Select().From("orders").Where("code = ?", "B").Get(ctx, d, o))
+	assert.Equal(t, "paid", o.Status)
+
+	_, err = Delete("orders").Where("status = ?", "paid").Exec(ctx, d)
+	assert.NoError(t, err)
+
+	n, _ := Select().From("orders").Count(ctx, d)
+	assert.Equal(t, int64(2), n)
+}
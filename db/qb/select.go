@@ -0,0 +1,257 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package qb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/enigma-id/go/db"
+)
+
+// SelectBuilder builds select query.
+type SelectBuilder struct {
+	placeholder Placeholder
+	columns     []string
+	from        string
+	joins       []Cond
+	where       []Cond
+	groupBy     []string
+	having      []Cond
+	orderBy     []string
+	limit       int
+	offset      int
+	forUpdate   bool
+	err         error
+}
+
+// Select creates select query builder with the columns.
+func Select(columns ...string) *SelectBuilder {
+	if len(columns) == 0 {
+		columns = []string{"*"}
+	}
+
+	return &SelectBuilder{columns: columns, placeholder: DefaultPlaceholder}
+}
+
+// PlaceholderFormat sets placeholder format of the query.
+func (b *SelectBuilder) PlaceholderFormat(p Placeholder) *SelectBuilder {
+	b.placeholder = p
+	return b
+}
+
+// From sets the table, alias can be included like "orders o".
+func (b *SelectBuilder) From(table string) *SelectBuilder {
+	b.from = table
+	return b
+}
+
+// Join adds inner join clause.
+func (b *SelectBuilder) Join(table, on string, args ...interface{}) *SelectBuilder {
+	b.joins = append(b.joins, Cond{"INNER JOIN " + table + " ON " + on, args})
+	return b
+}
+
+// LeftJoin adds left join clause.
+func (b *SelectBuilder) LeftJoin(table, on string, args ...interface{}) *SelectBuilder {
+	b.joins = append(b.joins, Cond{"LEFT JOIN " + table + " ON " + on, args})
+	return b
+}
+
+// Where adds condition joined with AND, values should be passed
+// as arguments using ? placeholder.
+func (b *SelectBuilder) Where(cond string, args ...interface{}) *SelectBuilder {
+	b.where = append(b.where, Cond{cond, args})
+	return b
+}
+
+// WhereIn adds column IN (...) condition, empty values makes
+// the condition always false.
+func (b *SelectBuilder) WhereIn(column string, values ...interface{}) *SelectBuilder {
+	b.where = append(b.where, in(column, values, &b.err))
+	return b
+}
+
+// OrWhere adds group of conditions joined with OR.
+func (b *SelectBuilder) OrWhere(conds ...Cond) *SelectBuilder {
+	b.where = append(b.where, or(conds))
+	return b
+}
+
+// Filter adds conditions and order by of the filter, usually rest.Filters
+// parsed from the request.
+func (b *SelectBuilder) Filter(f Filterer) *SelectBuilder {
+	if cond, args := f.Where(); cond != "" {
+		b.Where(cond, args...)
+	}
+
+	return b.OrderBy(f.OrderBy()...)
+}
+
+// GroupBy sets group by columns.
+func (b *SelectBuilder) GroupBy(columns ...string) *SelectBuilder {
+	if err := checkIdentifiers(columns...); err != nil {
+		b.err = err
+	}
+
+	b.groupBy = append(b.groupBy, columns...)
+	return b
+}
+
+// Having adds having condition joined with AND.
+func (b *SelectBuilder) Having(cond string, args ...interface{}) *SelectBuilder {
+	b.having = append(b.having, Cond{cond, args})
+	return b
+}
+
+// OrderBy adds order by columns, prefix the column with - for descending
+// or use "column desc", column are validated so it is safe to pass it from request.
+func (b *SelectBuilder) OrderBy(columns ...string) *SelectBuilder {
+	for _, c := range columns {
+		c = strings.TrimSpace(c)
+		dir := "ASC"
+
+		if strings.HasPrefix(c, "-") {
+			c, dir = c[1:], "DESC"
+		} else if f := strings.Fields(c); len(f) == 2 {
+			c, dir = f[0], strings.ToUpper(f[1])
+		}
+
+		if dir != "ASC" && dir != "DESC" {
+			b.err = fmt.Errorf("qb: invalid order direction %q", dir)
+		} else if err := checkIdentifiers(c); err != nil {
+			b.err = err
+		}
+
+		b.orderBy = append(b.orderBy, c+" "+dir)
+	}
+
+	return b
+}
+
+// Limit sets maximum rows returned.
+func (b *SelectBuilder) Limit(n int) *SelectBuilder {
+	b.limit = n
+	return b
+}
+
+// Offset sets number of rows skipped.
+func (b *SelectBuilder) Offset(n int) *SelectBuilder {
+	b.offset = n
+	return b
+}
+
+// Paginate sets limit and offset from the pagination, usually rest.Pagination.
+func (b *SelectBuilder) Paginate(p Paginator) *SelectBuilder {
+	return b.Limit(p.Limit()).Offset(p.Offset())
+}
+
+// ForUpdate adds FOR UPDATE lock.
+func (b *SelectBuilder) ForUpdate() *SelectBuilder {
+	b.forUpdate = true
+	return b
+}
+
+// ToSQL returns the query and its arguments.
+func (b *SelectBuilder) ToSQL() (string, []interface{}, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+
+	if b.from == "" {
+		return "", nil, fmt.Errorf("qb: select without table")
+	}
+
+	var sb strings.Builder
+	var args []interface{}
+
+	sb.WriteString("SELECT " + strings.Join(b.columns, ", ") + " FROM " + b.from)
+	for _, j := range b.joins {
+		sb.WriteString(" " + j.SQL)
+		args = append(args, j.Args...)
+	}
+
+	args = writeConds(&sb, " WHERE ", b.where, args)
+
+	if len(b.groupBy) > 0 {
+		sb.WriteString(" GROUP BY " + strings.Join(b.groupBy, ", "))
+	}
+
+	args = writeConds(&sb, " HAVING ", b.having, args)
+
+	if len(b.orderBy) > 0 {
+		sb.WriteString(" ORDER BY " + strings.Join(b.orderBy, ", "))
+	}
+
+	if b.limit > 0 {
+		sb.WriteString(" LIMIT " + strconv.Itoa(b.limit))
+	}
+
+	if b.offset > 0 {
+		sb.WriteString(" OFFSET " + strconv.Itoa(b.offset))
+	}
+
+	if b.forUpdate {
+		sb.WriteString(" FOR UPDATE")
+	}
+
+	return rebind(b.placeholder, sb.String()), args, nil
+}
+
+// CountSQL returns query that count all rows matched without limit and offset.
+func (b *SelectBuilder) CountSQL() (string, []interface{}, error) {
+	c := *b
+	c.orderBy, c.limit, c.offset, c.forUpdate = nil, 0, 0, false
+
+	if len(b.groupBy) > 0 {
+		q, args, err := c.ToSQL()
+		return "SELECT COUNT(*) FROM (" + q + ") t", args, err
+	}
+
+	c.columns = []string{"COUNT(*)"}
+	return c.ToSQL()
+}
+
+// Get executes the query and scan the first row into dest.
+func (b *SelectBuilder) Get(ctx context.Context, q db.Querier, dest interface{}) error {
+	query, args, err := b.ToSQL()
+	if err != nil {
+		return err
+	}
+
+	return q.Get(ctx, dest, query, args...)
+}
+
+// Select executes the query and scan all rows into dest.
+func (b *SelectBuilder) Select(ctx context.Context, q db.Querier, dest interface{}) error {
+	query, args, err := b.ToSQL()
+	if err != nil {
+		return err
+	}
+
+	return q.Select(ctx, dest, query, args...)
+}
+
+// Count executes count query of the builder.
+func (b *SelectBuilder) Count(ctx context.Context, q db.Querier) (total int64, err error) {
+	query, args, err := b.CountSQL()
+	if err == nil {
+		err = q.Get(ctx, &total, query, args...)
+	}
+
+	return
+}
+
+// Paged executes the query into dest and returns total rows matched without limit,
+// the total can be set into rest.Pagination.
+func (b *SelectBuilder) Paged(ctx context.Context, q db.Querier, dest interface{}) (total int64, err error) {
+	if total, err = b.Count(ctx, q); err == nil {
+		err = b.Select(ctx, q, dest)
+	}
+
+	return
+}
@@ -0,0 +1,261 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	fieldCache  sync.Map
+)
+
+// structFields returns map of column name into field index of the struct,
+// column name is taken from `db` tag, column of `orm` tag or snake case of the field name,
+// embedded struct fields are flattened and `db:"-"` is ignored.
+func structFields(t reflect.Type) map[string][]int {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.(map[string][]int)
+	}
+
+	fields := make(map[string][]int)
+	collectFields(t, nil, fields)
+	fieldCache.Store(t, fields)
+
+	return fields
+}
+
+func collectFields(t reflect.Type, index []int, fields map[string][]int) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := columnTag(sf)
+		if tag == "-" || (sf.PkgPath != "" && !sf.Anonymous) {
+			continue
+		}
+
+		idx := append(append([]int{}, index...), i)
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		if !isScalar(ft) {
+			if sf.Anonymous && tag == "" {
+				collectFields(ft, idx, fields)
+			} else if id := idField(ft); id != nil && tag != "" {
+				// relation field of orm model, ex. `orm:"column(user_id);rel(fk)"`
+				// the column is scanned into id of the related struct
+				fields[tag] = append(idx, id...)
+			}
+			continue
+		}
+
+		if tag == "" {
+			tag = snakeCase(sf.Name)
+		}
+
+		if _, exists := fields[tag]; !exists {
+			fields[tag] = idx
+		}
+	}
+}
+
+// columnTag returns column name from `db` tag or the column of `orm` tag,
+// so the models generated for orm can be used directly.
+func columnTag(sf reflect.StructField) string {
+	if tag := strings.Split(sf.Tag.Get("db"), ",")[0]; tag != "" {
+		return tag
+	}
+
+	o := sf.Tag.Get("orm")
+	if o == "-" {
+		return o
+	}
+
+	if i := strings.Index(o, "column("); i >= 0 {
+		o = o[i+7:]
+		if j := strings.Index(o, ")"); j >= 0 {
+			return o[:j]
+		}
+	}
+
+	return ""
+}
+
+// idField returns index of the id field of the struct, including embedded struct.
+func idField(t reflect.Type) []int {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath == "" && (columnTag(sf) == "id" || sf.Name == "ID" || sf.Name == "Id") {
+			return []int{i}
+		}
+
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			if id := idField(sf.Type); id != nil {
+				return append([]int{i}, id...)
+			}
+		}
+	}
+
+	return nil
+}
+
+// fieldByIndex returns field of the index without allocating nil pointers.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v, true
+}
+
+// fieldForScan returns addressable field of the index, allocates nil pointers.
+func fieldForScan(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v
+}
+
+// snakeCase converts field name into snake case, XxYy to xx_yy, UserID to user_id.
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' {
+			if i > 0 && (s[i-1] >= 'a' && s[i-1] <= 'z' || (i+1 < len(s) && s[i+1] >= 'a' && s[i+1] <= 'z' && s[i-1] != '_')) {
+				b.WriteByte('_')
+			}
+			c += 'a' - 'A'
+		}
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
+
+// isScalar returns true when the type should be scanned directly instead of its fields.
+func isScalar(t reflect.Type) bool {
+	return t.Kind() != reflect.Struct || reflect.PtrTo(t).Implements(scannerType) || t.PkgPath() == "time"
+}
+
+// targets returns scan destinations of the columns into the struct value.
+func targets(v reflect.Value, columns []string) ([]interface{}, error) {
+	if isScalar(v.Type()) {
+		if len(columns) != 1 {
+			return nil, fmt.Errorf("db: scanning %d columns into scalar %s", len(columns), v.Type())
+		}
+
+		return []interface{}{v.Addr().Interface()}, nil
+	}
+
+	fields := structFields(v.Type())
+	dest := make([]interface{}, len(columns))
+	for i, c := range columns {
+		idx, ok := fields[strings.ToLower(c)]
+		if !ok {
+			dest[i] = new(interface{})
+			continue
+		}
+
+		dest[i] = fieldForScan(v, idx).Addr().Interface()
+	}
+
+	return dest, nil
+}
+
+func scanOne(rows *sql.Rows, dest interface{}) error {
+	defer rows.Close()
+
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return errors.New("db: destination should be non nil pointer")
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	t, err := targets(v.Elem(), columns)
+	if err != nil {
+		return err
+	}
+
+	if err = rows.Scan(t...); err != nil {
+		return err
+	}
+
+	return rows.Close()
+}
+
+func scanAll(rows *sql.Rows, dest interface{}) error {
+	defer rows.Close()
+
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return errors.New("db: destination should be pointer of slice")
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	slice := v.Elem()
+	et := slice.Type().Elem()
+	isPtr := et.Kind() == reflect.Ptr
+	if isPtr {
+		et = et.Elem()
+	}
+
+	slice.SetLen(0)
+	for rows.Next() {
+		e := reflect.New(et)
+
+		t, err := targets(e.Elem(), columns)
+		if err != nil {
+			return err
+		}
+
+		if err = rows.Scan(t...); err != nil {
+			return err
+		}
+
+		if isPtr {
+			slice.Set(reflect.Append(slice, e))
+		} else {
+			slice.Set(reflect.Append(slice, e.Elem()))
+		}
+	}
+
+	return rows.Err()
+}
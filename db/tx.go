@@ -0,0 +1,123 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// Tx is transaction that implements Querier.
+	Tx struct {
+		conn
+		SQL *sql.Tx
+	}
+
+	// TxFunc function executed inside the transaction, ctx carries the transaction
+	// so nested InTx will join the running transaction instead of creating new one.
+	TxFunc func(ctx context.Context, tx *Tx) error
+
+	txKey struct{}
+)
+
+// IsRetryable reports whether the transaction error is caused by serialization
+// failure or deadlock and safe to be retried, can be replaced for other drivers.
+var IsRetryable = func(err error) bool {
+	var s interface{ SQLState() string }
+	if errors.As(err, &s) {
+		return s.SQLState() == "40001" || s.SQLState() == "40P01"
+	}
+
+	msg := err.Error()
+	for _, s := range []string{"Error 1213", "Error 1205", "40001", "40P01", "could not serialize access", "database is locked"} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TxFromContext returns running transaction carried by the context.
+func TxFromContext(ctx context.Context) (*Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(*Tx)
+	return tx, ok
+}
+
+// Begin starts new transaction.
+func (db *DB) Begin(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	t, err := db.SQL.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tx{conn: conn{q: t, db: db}, SQL: t}, nil
+}
+
+// Commit commits the transaction.
+func (tx *Tx) Commit() error {
+	return tx.SQL.Commit()
+}
+
+// Rollback aborts the transaction.
+func (tx *Tx) Rollback() error {
+	return tx.SQL.Rollback()
+}
+
+// InTx runs fn inside transaction, the transaction is committed when fn
+// returns nil and rollbacked on error or panic. When the transaction failed
+// because of serialization failure or deadlock, the whole fn is retried
+// up to MaxRetries times so fn should not have any side effect outside the database.
+func (db *DB) InTx(ctx context.Context, fn TxFunc) error {
+	return db.InTxWithOptions(ctx, nil, fn)
+}
+
+// InTxWithOptions same as InTx with transaction options (isolation level, read only).
+func (db *DB) InTxWithOptions(ctx context.Context, opts *sql.TxOptions, fn TxFunc) (err error) {
+	if tx, ok := TxFromContext(ctx); ok {
+		return fn(ctx, tx)
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err = db.runTx(ctx, opts, fn); err == nil || attempt >= db.MaxRetries || !IsRetryable(err) {
+			return
+		}
+
+		atomic.AddInt64(&db.retries, 1)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt+1) * 20 * time.Millisecond):
+		}
+	}
+}
+
+func (db *DB) runTx(ctx context.Context, opts *sql.TxOptions, fn TxFunc) (err error) {
+	tx, err := db.Begin(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+
+		if err != nil {
+			tx.Rollback()
+		} else if err = tx.Commit(); err != nil {
+			err = fmt.Errorf("db: commit failed: %w", err)
+		}
+	}()
+
+	return fn(context.WithValue(ctx, txKey{}, tx), tx)
+}
@@ -0,0 +1,234 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/enigma-id/go/dev/core"
+	"github.com/enigma-id/go/env"
+	"github.com/gomodule/redigo/redis"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+var doctorCommand = &core.Command{
+	Name: "doctor",
+	Info: "check the config and the services of the application.",
+	Usage: `
+dev doctor [-env=.env] [-migrations=migrations]
+	Doctor command checks the application is ready to run, the keys of
+	.env.example are set, mysql and redis are reachable and the migrations
+	are applied, it exits with 1 when any check failed.
+	-env: 	 	the env file loaded, default is .env
+	-migrations: 	directory of the migration files, default is migrations
+`,
+}
+
+var doctorEnv, doctorMigrations core.DocVal
+
+// doctorTimeout of each connectivity check.
+const doctorTimeout = 5 * time.Second
+
+func init() {
+	doctorCommand.Run = actionDoctor
+	doctorCommand.Flag.Var(&doctorEnv, "env", "the env file loaded.")
+	doctorCommand.Flag.Var(&doctorMigrations, "migrations", "directory of the migration files.")
+}
+
+// actionDoctor runs the checks, the failed checks are logged as error.
+func actionDoctor(_ *core.Command, _ []string) int {
+	envFile, dir := doctorEnv.String(), doctorMigrations.String()
+	if envFile == "" {
+		envFile = ".env"
+	}
+	if dir == "" {
+		dir = "migrations"
+	}
+
+	core.Log.Info("")
+	core.Log.Info("Checking applications ...")
+	core.Log.Info("--------------------------------------")
+
+	failed := 0
+	check := func(name string, err error, skipped string) {
+		switch {
+		case err != nil:
+			failed++
+			core.Log.Error(fmt.Sprintf("[FAIL] %s: %s", name, err.Error()))
+		case skipped != "":
+			core.Log.Info(fmt.Sprintf("[SKIP] %s: %s", name, skipped))
+		default:
+			core.Log.Info(fmt.Sprintf("[ OK ] %s", name))
+		}
+	}
+
+	if _, err := os.Stat(envFile); err == nil {
+		check("env file "+envFile, env.Load(envFile), "")
+	} else {
+		check("env file "+envFile, nil, "not found, using the environment")
+	}
+	check("env keys", checkEnvKeys(".env.example"), "")
+
+	db, err := openMySQL()
+	if db == nil && err == nil {
+		check("mysql", nil, "MYSQL_HOST is not set")
+	} else {
+		check("mysql "+os.Getenv("MYSQL_HOST"), err, "")
+	}
+
+	if host := os.Getenv("REDIS_HOST"); host != "" {
+		check("redis "+host, pingRedis(host, os.Getenv("REDIS_PASSWORD")), "")
+	} else {
+		check("redis", nil, "REDIS_HOST is not set")
+	}
+
+	switch _, err := os.Stat(dir); {
+	case err != nil:
+		check("migrations", nil, dir+" directory not found")
+	case db == nil:
+		check("migrations", nil, "mysql is not reachable")
+	default:
+		status, err := checkMigrations(db, dir)
+		check("migrations "+status, err, "")
+	}
+
+	if db != nil {
+		db.Close()
+	}
+
+	core.Log.Info("--------------------------------------")
+	if failed > 0 {
+		core.Log.Error(fmt.Sprintf("%d checks failed.", failed))
+		return 1
+	}
+	core.Log.Info("All checks passed.")
+
+	return 0
+}
+
+// checkEnvKeys returns error listing the keys of the example file that are
+// not set, the file is optional.
+func checkEnvKeys(example string) error {
+	f, err := os.Open(example)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var missing []string
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key := strings.TrimSpace(strings.SplitN(strings.TrimPrefix(line, "export "), "=", 2)[0])
+		if key != "" && os.Getenv(key) == "" {
+			missing = append(missing, key)
+		}
+	}
+	if err = s.Err(); err != nil {
+		return err
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%s of %s are not set", strings.Join(missing, ", "), example)
+	}
+	return nil
+}
+
+// openMySQL connects into the database of rest config,
+// nil without error when it's not configured.
+func openMySQL() (*sql.DB, error) {
+	host := os.Getenv("MYSQL_HOST")
+	if host == "" {
+		return nil, nil
+	}
+
+	ds := fmt.Sprintf("%s:%s@tcp(%s)/%s?timeout=%s", os.Getenv("MYSQL_USER"), os.Getenv("MYSQL_PASS"), host, os.Getenv("MYSQL_DB"), doctorTimeout)
+	db, err := sql.Open("mysql", ds)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), doctorTimeout)
+	defer cancel()
+	if err = db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func pingRedis(host, password string) error {
+	c, err := redis.Dial("tcp", host,
+		redis.DialConnectTimeout(doctorTimeout),
+		redis.DialReadTimeout(doctorTimeout),
+		redis.DialWriteTimeout(doctorTimeout),
+		redis.DialPassword(password))
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	_, err = c.Do("PING")
+	return err
+}
+
+// checkMigrations compares the latest migration file, ex. 0012_orders.up.sql,
+// with the version applied into schema_migrations table of golang-migrate.
+func checkMigrations(db *sql.DB, dir string) (string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	var versions []int
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || !strings.HasSuffix(name, ".up.sql") {
+			continue
+		}
+		if v, err := strconv.Atoi(strings.SplitN(name, "_", 2)[0]); err == nil {
+			versions = append(versions, v)
+		}
+	}
+	if len(versions) == 0 {
+		return "", fmt.Errorf("no migration files in %s", filepath.Clean(dir))
+	}
+	sort.Ints(versions)
+	latest := versions[len(versions)-1]
+
+	var (
+		applied int
+		dirty   bool
+	)
+	err = db.QueryRow("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&applied, &dirty)
+	switch {
+	case err != nil:
+		return "", fmt.Errorf("reading schema_migrations: %s", err.Error())
+	case dirty:
+		return "", fmt.Errorf("version %d is dirty, the last migration failed", applied)
+	case applied < latest:
+		return "", fmt.Errorf("version %d is applied, %d is the latest", applied, latest)
+	}
+
+	return fmt.Sprintf("at version %d", applied), nil
+}
@@ -7,6 +7,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/enigma-id/go/dev/core"
 	"github.com/enigma-id/go/dev/generate"
@@ -23,6 +24,12 @@ dev make model [-tables=""] [-database=test] [-conn="root:@tcp(127.0.0.1:3306)"]
 	-conn:  	the connection string used by the driver.
 				default for mysql: root:@tcp(127.0.0.1:3306)
 
+dev make repository [-tables=""] [-database=test] [-conn="root:@tcp(127.0.0.1:3306)"] [-cache=10m]
+	generate repository of the models using db and qb packages, should be run inside model directory.
+	-tables: 	a list of table names separated by ',', default is empty, indicating all tables
+	-cache: 	expiry of the cached rows, Find is read through the cache and Delete invalidates it,
+				default is empty, indicating no cache
+
 dev make request [-name=test]
 	generate appcode based on an existing database
 	-name: 	    request name
@@ -33,7 +40,7 @@ dev make handler [-methods=""]
 `,
 }
 
-var name, database, conn, mode, tables, endpoint, methods core.DocVal
+var name, database, conn, mode, tables, endpoint, methods, cacheExpires core.DocVal
 
 func init() {
 	makeCommand.Run = actionMake
@@ -43,6 +50,7 @@ func init() {
 	makeCommand.Flag.Var(&endpoint, "endpoint", "specify endpoint name, will be used as folder name as well.")
 	makeCommand.Flag.Var(&methods, "methods", "specify HTTP method that will serve by the endpoint.")
 	makeCommand.Flag.Var(&name, "name", "specify project name.")
+	makeCommand.Flag.Var(&cacheExpires, "cache", "specify expiry of the cached rows of the repository.")
 }
 
 func actionMake(cmd *core.Command, args []string) int {
@@ -86,6 +94,27 @@ func actionMake(cmd *core.Command, args []string) int {
 		core.Log.Info("Making a model file ...")
 		generate.FileModel("mysql", c, tables.String(), tpl)
 
+	case "repository":
+		cmd.Flag.Parse(args[1:])
+		var tpl = &core.StubTemplate{
+			AppPath:     curpath,
+			PackageName: core.GetDirName(curpath),
+		}
+
+		if cacheExpires != "" {
+			d, err := time.ParseDuration(cacheExpires.String())
+			if err != nil || d <= 0 {
+				core.Log.Error("cache must be positive duration, ex. 10m.")
+				os.Exit(2)
+			}
+			tpl.CacheExpires = generate.DurationSource(d)
+		}
+
+		c := sqlConnection()
+
+		core.Log.Info("Making a repository file ...")
+		generate.FileRepository("mysql", c, tables.String(), tpl)
+
 	case "handler":
 		cmd.Flag.Parse(args[1:])
 		var tpl = &core.StubTemplate{
@@ -0,0 +1,105 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/enigma-id/go/dev/core"
+	"github.com/enigma-id/go/rest"
+)
+
+var routesCommand = &core.Command{
+	Name: "routes",
+	Info: "print the route table of the application.",
+	Usage: `
+dev routes [-pkg=.] [-json]
+	Routes command builds the application and starts it in inspection mode,
+	the route table is printed instead of serving the requests.
+	The application must start the server with rest Start, so the routes
+	registered before it are listed.
+	-pkg: 	package of the main, default is current directory
+	-json: 	print the route table as json
+`,
+}
+
+var (
+	routesPkg  core.DocVal
+	routesJSON bool
+)
+
+func init() {
+	routesCommand.Run = actionRoutes
+	routesCommand.Flag.Var(&routesPkg, "pkg", "package of the main.")
+	routesCommand.Flag.BoolVar(&routesJSON, "json", false, "print the route table as json.")
+}
+
+// actionRoutes builds the application then runs it with rest.EnvInspect.
+func actionRoutes(_ *core.Command, _ []string) int {
+	pkg := routesPkg.String()
+	if pkg == "" {
+		pkg = "."
+	}
+
+	dir, err := ioutil.TempDir("", "dev-routes")
+	if err != nil {
+		core.Log.Error(err.Error())
+		return 2
+	}
+	defer os.RemoveAll(dir)
+
+	bin := filepath.Join(dir, "app")
+	build := exec.Command("go", "build", "-o", bin, pkg)
+	build.Stdout, build.Stderr = os.Stderr, os.Stderr
+	if err = build.Run(); err != nil {
+		core.Log.Error("Failed to build.")
+		return 2
+	}
+
+	table := filepath.Join(dir, "routes.json")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// the application exits with error after the table is written
+	app := exec.CommandContext(ctx, bin)
+	app.Env = append(os.Environ(), rest.EnvInspect+"="+table)
+	out, _ := app.CombinedOutput()
+
+	b, err := ioutil.ReadFile(table)
+	if err != nil {
+		core.Log.Error("The application didn't start the server, output:")
+		fmt.Fprintln(os.Stderr, strings.TrimSpace(string(out)))
+		return 2
+	}
+
+	if routesJSON {
+		fmt.Println(string(b))
+		return 0
+	}
+
+	var routes []rest.InspectedRoute
+	if err = json.Unmarshal(b, &routes); err != nil {
+		core.Log.Error(err.Error())
+		return 2
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "METHOD\tPATH\tHANDLER\tSCOPES\tSUMMARY")
+	for _, r := range routes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.Method, r.Path, r.Name, strings.Join(r.Scopes, ","), r.Summary)
+	}
+	w.Flush()
+
+	return 0
+}
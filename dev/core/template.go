@@ -22,6 +22,7 @@ type StubTemplate struct {
 	ModelNameSingular string
 	ModelNamePlural   string
 	TableName         string
+	CacheExpires      string
 }
 
 func GetDirName(currentPath string) string {
@@ -0,0 +1,96 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package generate
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"database/sql"
+
+	"github.com/enigma-id/go/dev/core"
+	dbReader "github.com/enigma-id/go/dev/generate/db_reader"
+	"github.com/enigma-id/go/dev/generate/stubs"
+	"github.com/enigma-id/go/utility"
+)
+
+// FileRepository generates repository of each tables using db and qb packages,
+// the repository is placed on the same package with the models. When the
+// CacheExpires of the template is set, Find of the repository is read through
+// the cache and Delete invalidates it, see cache.Memoize.
+func FileRepository(driver string, conn string, selectedTables string, tpl *core.StubTemplate) {
+	var tables map[string]bool
+	if selectedTables != "" {
+		tables = make(map[string]bool)
+		for _, v := range strings.Split(selectedTables, ",") {
+			tables[v] = true
+		}
+	}
+
+	db, err := sql.Open(driver, conn)
+	if err != nil {
+		core.Log.Error("Could not connect to database ")
+		core.Log.Error(fmt.Sprintf("using: %s, %s, %s", driver, conn, err.Error()))
+		os.Exit(2)
+	}
+	defer db.Close()
+
+	trans, ok := dbReader.DBDriver[driver]
+	if !ok {
+		core.Log.Error(fmt.Sprintf("%s database is not supported yet.", driver))
+		os.Exit(2)
+	}
+
+	core.Log.Info("")
+	core.Log.Info("Generating repository file ...")
+	core.Log.Info("--------------------------------------")
+
+	for _, tb := range dbReader.GetTableObjects(trans.GetTableNames(db), db, trans) {
+		if tables != nil && !tables[tb.Name] {
+			continue
+		}
+
+		file := path.Join(tpl.AppPath, dbReader.GetFileName(tb.Name)+"_repository.go")
+		f, err := FileReader(file)
+		if err != nil || f == nil {
+			continue
+		}
+
+		tpl.ModelName = utility.ToCamelCase(tb.Name)
+		tpl.TableName = tb.Name
+		stub := stubs.Repository
+		if tpl.CacheExpires != "" {
+			stub = stubs.CachedRepository
+		}
+		WriteFile(f, stub, tpl)
+		core.FormatSourceCode(f.Name())
+		core.Log.Info(fmt.Sprintf("%-20s => \t\t%s", "repository", file))
+	}
+}
+
+// DurationSource returns go source of the duration, ex. "10 * time.Minute",
+// used as CacheExpires of the template.
+func DurationSource(d time.Duration) string {
+	units := []struct {
+		d    time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+	}
+
+	for _, u := range units {
+		if d != 0 && d%u.d == 0 {
+			return fmt.Sprintf("%d * %s", d/u.d, u.name)
+		}
+	}
+
+	return fmt.Sprintf("time.Duration(%d)", d)
+}
@@ -76,6 +76,7 @@ func StubReplaces(content string, tpl *core.StubTemplate) string {
 	content = strings.Replace(content, "{{ModelNameSingular}}", tpl.ModelNameSingular, -1)
 	content = strings.Replace(content, "{{ModelNamePlural}}", tpl.ModelNamePlural, -1)
 	content = strings.Replace(content, "{{TableName}}", tpl.TableName, -1)
+	content = strings.Replace(content, "{{CacheExpires}}", tpl.CacheExpires, -1)
 
 	return content
 }
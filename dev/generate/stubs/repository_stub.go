@@ -0,0 +1,140 @@
+package stubs
+
+var Repository = `
+package {{PackageName}}
+
+import (
+	"context"
+
+	"github.com/enigma-id/go/db"
+	"github.com/enigma-id/go/db/qb"
+	"github.com/enigma-id/go/rest"
+)
+
+// {{ModelName}}Repository data access of {{TableName}} table using db and qb packages.
+type {{ModelName}}Repository struct {
+	DB db.Querier
+}
+
+// New{{ModelName}}Repository creates repository, pass *db.Tx to use it inside transaction.
+func New{{ModelName}}Repository(q db.Querier) *{{ModelName}}Repository {
+	return &{{ModelName}}Repository{DB: q}
+}
+
+// Query returns select builder of the table.
+func (r *{{ModelName}}Repository) Query() *qb.SelectBuilder {
+	return qb.Select("*").From("{{TableName}}")
+}
+
+// Find returns single row by the id.
+func (r *{{ModelName}}Repository) Find(ctx context.Context, id int64) (m *{{ModelName}}, err error) {
+	m = new({{ModelName}})
+	err = r.Query().Where("id = ?", id).Get(ctx, r.DB, m)
+
+	return
+}
+
+// List returns rows of the page, total rows is set into the pagination.
+func (r *{{ModelName}}Repository) List(ctx context.Context, p *rest.Pagination, q *qb.SelectBuilder) (ms []*{{ModelName}}, err error) {
+	if q == nil {
+		q = r.Query()
+	}
+
+	p.Total, err = q.Paginate(p).Paged(ctx, r.DB, &ms)
+
+	return
+}
+
+// Delete removes row by the id.
+func (r *{{ModelName}}Repository) Delete(ctx context.Context, id int64) (err error) {
+	_, err = qb.Delete("{{TableName}}").Where("id = ?", id).Exec(ctx, r.DB)
+
+	return
+}
+`
+
+var CachedRepository = `
+package {{PackageName}}
+
+import (
+	"context"
+	"time"
+
+	"github.com/enigma-id/go/cache"
+	"github.com/enigma-id/go/db"
+	"github.com/enigma-id/go/db/qb"
+	"github.com/enigma-id/go/rest"
+)
+
+// {{ModelName}}CacheExpires expiry of the cached rows of {{TableName}} table.
+var {{ModelName}}CacheExpires = {{CacheExpires}}
+
+// {{ModelName}}Repository data access of {{TableName}} table using db and qb packages,
+// Find is read through the cache and Delete invalidates the cached row.
+type {{ModelName}}Repository struct {
+	DB db.Querier
+
+	find *cache.Memo
+}
+
+// New{{ModelName}}Repository creates repository, pass *db.Tx to use it inside transaction.
+func New{{ModelName}}Repository(q db.Querier) *{{ModelName}}Repository {
+	r := &{{ModelName}}Repository{DB: q}
+	r.find = cache.Memoize(nil, {{ModelName}}CacheExpires, cache.Key("{{TableName}}"), func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		return r.get(ctx, args[0].(int64))
+	})
+
+	return r
+}
+
+// Query returns select builder of the table.
+func (r *{{ModelName}}Repository) Query() *qb.SelectBuilder {
+	return qb.Select("*").From("{{TableName}}")
+}
+
+// Find returns single row by the id, the row is read from the database
+// inside transaction so it sees the uncommitted writes.
+func (r *{{ModelName}}Repository) Find(ctx context.Context, id int64) (m *{{ModelName}}, err error) {
+	if _, ok := r.DB.(*db.Tx); ok {
+		return r.get(ctx, id)
+	}
+
+	m = new({{ModelName}})
+	err = r.find.Get(ctx, m, id)
+
+	return
+}
+
+// List returns rows of the page, total rows is set into the pagination.
+func (r *{{ModelName}}Repository) List(ctx context.Context, p *rest.Pagination, q *qb.SelectBuilder) (ms []*{{ModelName}}, err error) {
+	if q == nil {
+		q = r.Query()
+	}
+
+	p.Total, err = q.Paginate(p).Paged(ctx, r.DB, &ms)
+
+	return
+}
+
+// Delete removes row by the id and its cached row.
+func (r *{{ModelName}}Repository) Delete(ctx context.Context, id int64) (err error) {
+	if _, err = qb.Delete("{{TableName}}").Where("id = ?", id).Exec(ctx, r.DB); err != nil {
+		return
+	}
+
+	return r.Forget(id)
+}
+
+// Forget invalidates the cached row, call it after the row is updated
+// (or after the transaction that updated it is committed).
+func (r *{{ModelName}}Repository) Forget(id int64) error {
+	return r.find.Forget(id)
+}
+
+func (r *{{ModelName}}Repository) get(ctx context.Context, id int64) (m *{{ModelName}}, err error) {
+	m = new({{ModelName}})
+	err = r.Query().Where("id = ?", id).Get(ctx, r.DB, m)
+
+	return
+}
+`
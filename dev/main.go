@@ -33,6 +33,8 @@ func init() {
 var cmd = []*core.Command{
 	runCommand,
 	makeCommand,
+	routesCommand,
+	doctorCommand,
 }
 
 func main() {
@@ -0,0 +1,233 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package env
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// key is the config key of a struct field.
+type key struct {
+	name  string // name of the env variable, ex. REDIS_HOST
+	def   string
+	help  string
+	value reflect.Value
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Bind sets the fields of the struct pointers from the env variables named
+// by the env tag, the default tag is used when the variable is empty.
+// Nested struct without env tag is bound as well. Supported fields are
+// string, bool, int, uint, float, time.Duration and []string (comma separated).
+//
+//	type Config struct {
+//		RedisHost string        `env:"REDIS_HOST" default:"127.0.0.1:6379" help:"address of redis"`
+//		Timeout   time.Duration `env:"HTTP_TIMEOUT" default:"30s"`
+//	}
+func Bind(vs ...interface{}) error {
+	keys, err := keysOf(vs)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		v := os.Getenv(k.name)
+		if v == "" {
+			v = k.def
+		}
+		if err := setValue(k.value, v); err != nil {
+			return fmt.Errorf("env: invalid value %q of %s, %s", v, k.name, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Parse registers a flag for each key of the structs, ex. --redis-host for
+// REDIS_HOST, parses the args then binds the structs. The value is taken
+// from the first of flag, env variable, the .env file loaded by Load and
+// the default tag. The flag sets the env variable, so it's visible to
+// GetString and the other getters. Help flag prints the config keys
+// and returns flag.ErrHelp.
+//
+//	if err := env.Parse(os.Args[1:], &config); err != nil {
+//		os.Exit(2)
+//	}
+func Parse(args []string, vs ...interface{}) error {
+	keys, err := keysOf(vs)
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	for _, k := range keys {
+		// same key of several structs is a single flag
+		if fs.Lookup(FlagName(k.name)) == nil {
+			fs.String(FlagName(k.name), k.def, k.help)
+		}
+	}
+	if err = fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			printUsage(os.Stderr, keys)
+		}
+		return err
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		for _, k := range keys {
+			if FlagName(k.name) == f.Name {
+				os.Setenv(k.name, f.Value.String())
+				break
+			}
+		}
+	})
+
+	return Bind(vs...)
+}
+
+// Usage writes the config keys of the structs with their flag,
+// default value and help.
+func Usage(w io.Writer, vs ...interface{}) error {
+	keys, err := keysOf(vs)
+	if err != nil {
+		return err
+	}
+
+	printUsage(w, keys)
+	return nil
+}
+
+// FlagName returns the flag of the env variable, ex. redis-host for REDIS_HOST.
+func FlagName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), "_", "-")
+}
+
+func printUsage(w io.Writer, keys []key) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "FLAG\tENV\tDEFAULT\tDESCRIPTION")
+	for _, k := range keys {
+		fmt.Fprintf(tw, "--%s\t%s\t%s\t%s\n", FlagName(k.name), k.name, k.def, k.help)
+	}
+	tw.Flush()
+}
+
+func keysOf(vs []interface{}) ([]key, error) {
+	var keys []key
+	for _, v := range vs {
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+			return nil, fmt.Errorf("env: bind requires struct pointer, got %T", v)
+		}
+		keys = appendKeys(keys, rv.Elem())
+	}
+
+	return keys, nil
+}
+
+func appendKeys(keys []key, rv reflect.Value) []key {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		name := f.Tag.Get("env")
+		if name == "" {
+			if f.Type.Kind() == reflect.Struct && f.Type != reflect.TypeOf(time.Time{}) {
+				keys = appendKeys(keys, rv.Field(i))
+			}
+			continue
+		}
+
+		keys = append(keys, key{
+			name:  name,
+			def:   f.Tag.Get("default"),
+			help:  f.Tag.Get("help"),
+			value: rv.Field(i),
+		})
+	}
+
+	return keys
+}
+
+func setValue(rv reflect.Value, v string) error {
+	if rv.Type() == durationType {
+		if v == "" {
+			rv.SetInt(0)
+			return nil
+		}
+		d, err := time.ParseDuration(v)
+		if err == nil {
+			rv.SetInt(int64(d))
+		}
+		return err
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(v)
+	case reflect.Bool:
+		b := false
+		if v != "" {
+			var err error
+			if b, err = strconv.ParseBool(v); err != nil {
+				return err
+			}
+		}
+		rv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := parseNumber(v, func(s string) (interface{}, error) { return strconv.ParseInt(s, 10, rv.Type().Bits()) })
+		if err != nil {
+			return err
+		}
+		rv.SetInt(n.(int64))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := parseNumber(v, func(s string) (interface{}, error) { return strconv.ParseUint(s, 10, rv.Type().Bits()) })
+		if err != nil {
+			return err
+		}
+		rv.SetUint(n.(uint64))
+	case reflect.Float32, reflect.Float64:
+		n, err := parseNumber(v, func(s string) (interface{}, error) { return strconv.ParseFloat(s, rv.Type().Bits()) })
+		if err != nil {
+			return err
+		}
+		rv.SetFloat(n.(float64))
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", rv.Type())
+		}
+		var s []string
+		for _, p := range strings.Split(v, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				s = append(s, p)
+			}
+		}
+		rv.Set(reflect.ValueOf(s).Convert(rv.Type()))
+	default:
+		return fmt.Errorf("unsupported type %s", rv.Type())
+	}
+
+	return nil
+}
+
+// parseNumber parses the value, empty value is zero.
+func parseNumber(v string, parse func(string) (interface{}, error)) (interface{}, error) {
+	if v == "" {
+		v = "0"
+	}
+	return parse(v)
+}
@@ -0,0 +1,110 @@
+package env
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type redisConfig struct {
+	Host string `env:"TEST_REDIS_HOST" default:"127.0.0.1:6379" help:"address of redis"`
+	DB   int    `env:"TEST_REDIS_DB"`
+}
+
+type appConfig struct {
+	Name    string        `env:"TEST_APP_NAME" default:"orders"`
+	Debug   bool          `env:"TEST_APP_DEBUG"`
+	Timeout time.Duration `env:"TEST_APP_TIMEOUT" default:"30s" help:"timeout of the request"`
+	Ratio   float64       `env:"TEST_APP_RATIO" default:"0.5"`
+	Origins []string      `env:"TEST_APP_ORIGINS" default:"a.com, b.com"`
+	Redis   redisConfig
+	secret  string
+}
+
+func TestBind(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("TEST_REDIS_DB", "2")
+	os.Setenv("TEST_APP_DEBUG", "true")
+
+	var c appConfig
+	if err := Bind(&c); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := appConfig{
+		Name:    "orders",
+		Debug:   true,
+		Timeout: 30 * time.Second,
+		Ratio:   0.5,
+		Origins: []string{"a.com", "b.com"},
+		Redis:   redisConfig{Host: "127.0.0.1:6379", DB: 2},
+	}
+	if !reflect.DeepEqual(expected, c) {
+		t.Errorf("Expected %+v, got %+v", expected, c)
+	}
+
+	os.Setenv("TEST_REDIS_DB", "two")
+	if err := Bind(&c); err == nil || !strings.Contains(err.Error(), "TEST_REDIS_DB") {
+		t.Errorf("Expected invalid value error, got %v", err)
+	}
+	if err := Bind(c); err == nil {
+		t.Error("Expected error binding non pointer")
+	}
+}
+
+func TestParse(t *testing.T) {
+	os.Clearenv()
+	if err := Load("_fixture/plain.env"); err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("TEST_APP_NAME", "env")
+	os.Setenv("TEST_REDIS_HOST", "env:6379")
+
+	var c appConfig
+	err := Parse([]string{"--test-redis-host", "flag:6379", "-test-app-timeout=1m"}, &c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Redis.Host != "flag:6379" || c.Timeout != time.Minute || c.Name != "env" || c.Ratio != 0.5 {
+		t.Errorf("Unexpected precedence %+v", c)
+	}
+	if GetString("TEST_REDIS_HOST", "") != "flag:6379" {
+		t.Error("Flag doesn't set the env variable")
+	}
+	if GetString("OPTION_A", "") != "1" {
+		t.Error("Env file isn't loaded")
+	}
+
+	if err = Parse([]string{"--unknown"}, &c); err == nil {
+		t.Error("Expected error parsing unknown flag")
+	}
+
+	stderr := os.Stderr
+	os.Stderr, _ = os.Open(os.DevNull)
+	err = Parse([]string{"--help"}, &c)
+	os.Stderr = stderr
+	if err != flag.ErrHelp {
+		t.Errorf("Expected flag.ErrHelp, got %v", err)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	var (
+		c   appConfig
+		buf bytes.Buffer
+	)
+	if err := Usage(&buf, &c); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{"--test-redis-host", "TEST_REDIS_HOST", "127.0.0.1:6379", "address of redis", "--test-app-timeout"} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("Usage doesn't contain %q\n%s", s, buf.String())
+		}
+	}
+}
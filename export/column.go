@@ -0,0 +1,137 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package export
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TagName is struct tag used to define header and format of the column,
+// ex. `export:"Grand Total,rupiah"`, `export:"Created,02/01/2006"` or `export:"-"`.
+const TagName = "export"
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	columns  sync.Map
+)
+
+// Column of the export.
+type Column struct {
+	Name   string
+	Header string
+	Format string
+	index  []int
+}
+
+// Columns returns exportable columns of struct v.
+func Columns(v interface{}) []Column {
+	cols, _ := columnsOf(reflect.TypeOf(v), nil)
+	return cols
+}
+
+func columnsOf(t reflect.Type, only []string) ([]Column, error) {
+	t = indirect(t)
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%v, got %s", ErrInvalidRows, t)
+	}
+
+	var all []Column
+	if c, ok := columns.Load(t); ok {
+		all = c.([]Column)
+	} else {
+		all = fields(t, nil)
+		columns.Store(t, all)
+	}
+
+	if len(only) == 0 {
+		return all, nil
+	}
+
+	cols := make([]Column, 0, len(only))
+	for _, o := range only {
+		for _, c := range all {
+			if c.Name == o || c.Header == o {
+				cols = append(cols, c)
+				break
+			}
+		}
+	}
+
+	return cols, nil
+}
+
+func fields(t reflect.Type, index []int) (cols []Column) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get(TagName)
+		if tag == "-" || (f.PkgPath != "" && !f.Anonymous) {
+			continue
+		}
+
+		idx := append(append([]int{}, index...), i)
+
+		if f.Anonymous && tag == "" {
+			if ft := indirect(f.Type); ft.Kind() == reflect.Struct && ft != timeType {
+				cols = append(cols, fields(ft, idx)...)
+			}
+			continue
+		}
+
+		// format may contains comma, ex. `export:"Total,#.###,##"`
+		c := Column{Name: f.Name, Header: f.Name, index: idx}
+		if tag != "" {
+			p := strings.SplitN(tag, ",", 2)
+			if p[0] != "" {
+				c.Header = p[0]
+			}
+			if len(p) > 1 {
+				c.Format = p[1]
+			}
+		}
+
+		cols = append(cols, c)
+	}
+
+	return
+}
+
+func rowOf(v reflect.Value, cols []Column) []Cell {
+	cells := make([]Cell, len(cols))
+	for i, c := range cols {
+		if f, ok := fieldByIndex(v, c.index); ok {
+			cells[i] = format(f, c.Format)
+		}
+	}
+
+	return cells
+}
+
+// fieldByIndex like reflect.Value.FieldByIndex
+// but returns false on nil embedded pointer.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return v, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v, true
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
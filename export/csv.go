@@ -0,0 +1,42 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package export
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+type csvWriter struct {
+	w   *csv.Writer
+	rec []string
+}
+
+func newCSVWriter(w io.Writer, opts Options) *csvWriter {
+	cw := csv.NewWriter(w)
+	if opts.Comma != 0 {
+		cw.Comma = opts.Comma
+	}
+
+	return &csvWriter{w: cw}
+}
+
+func (w *csvWriter) Write(cells []Cell) error {
+	w.rec = w.rec[:0]
+	for _, c := range cells {
+		w.rec = append(w.rec, c.Value)
+	}
+
+	return w.w.Write(w.rec)
+}
+
+func (w *csvWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+func (w *csvWriter) Close() error {
+	return w.Flush()
+}
@@ -0,0 +1,241 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// Supported export formats.
+const (
+	CSV  = "csv"
+	XLSX = "xlsx"
+)
+
+// DefaultChunkSize is number of rows written before
+// the output flushed into the underlying writer.
+const DefaultChunkSize = 1000
+
+var (
+	// ErrUnsupportedFormat returned when format is not csv or xlsx.
+	ErrUnsupportedFormat = errors.New("export: unsupported format")
+
+	// ErrInvalidRows returned when rows is not a slice, channel or iterator of structs.
+	ErrInvalidRows = errors.New("export: rows must be a slice, channel or iterator of struct")
+)
+
+// Iterator produces rows one by one by calling yield,
+// use it to export straight from database cursor without
+// holding the whole result in memory.
+type Iterator func(yield func(row interface{}) error) error
+
+// Options of the export.
+type Options struct {
+	// Format of the file, csv or xlsx, default csv.
+	Format string
+
+	// Filename of the attachment, extension of the format
+	// is appended when missing, default export.
+	Filename string
+
+	// Sheet name of xlsx, default Sheet1.
+	Sheet string
+
+	// Columns limits and orders exported columns by field name or header,
+	// empty means all tagged fields.
+	Columns []string
+
+	// ChunkSize is number of rows between flush, default DefaultChunkSize.
+	ChunkSize int
+
+	// Comma is field delimiter of csv, default ','.
+	Comma rune
+
+	// NoHeader skip the header row.
+	NoHeader bool
+}
+
+// FileName returns filename with extension of the format.
+func (o Options) FileName() string {
+	fn := o.Filename
+	if fn == "" {
+		fn = "export"
+	}
+
+	ext := "." + o.format()
+	if !strings.HasSuffix(strings.ToLower(fn), ext) {
+		fn += ext
+	}
+
+	return fn
+}
+
+// ContentType returns mime type of the format.
+func (o Options) ContentType() string {
+	if o.format() == XLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+
+	return "text/csv; charset=utf-8"
+}
+
+func (o Options) format() string {
+	if o.Format == "" {
+		return CSV
+	}
+
+	return strings.ToLower(o.Format)
+}
+
+// Cell is a formatted value of a column.
+type Cell struct {
+	Value  string
+	Number bool
+}
+
+// writer writes rows into the file format.
+type writer interface {
+	Write(cells []Cell) error
+	Flush() error
+	Close() error
+}
+
+func newWriter(w io.Writer, opts Options) (writer, error) {
+	switch opts.format() {
+	case CSV:
+		return newCSVWriter(w, opts), nil
+	case XLSX:
+		return newXLSXWriter(w, opts)
+	}
+
+	return nil, ErrUnsupportedFormat
+}
+
+// Write exports the rows into w, rows can be slice of struct,
+// channel of struct or an Iterator. Output is flushed every
+// ChunkSize rows, when w is http.Flusher it is flushed as well.
+func Write(w io.Writer, rows interface{}, opts Options) (err error) {
+	var fw writer
+	if fw, err = newWriter(w, opts); err != nil {
+		return
+	}
+
+	chunk := opts.ChunkSize
+	if chunk <= 0 {
+		chunk = DefaultChunkSize
+	}
+
+	var cols []Column
+	var ready bool
+	var n int
+
+	flush := func() error {
+		if e := fw.Flush(); e != nil {
+			return e
+		}
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+		return nil
+	}
+
+	header := func(t reflect.Type) (e error) {
+		ready = true
+		if cols, e = columnsOf(t, opts.Columns); e != nil || opts.NoHeader {
+			return
+		}
+
+		cells := make([]Cell, len(cols))
+		for i, c := range cols {
+			cells[i] = Cell{Value: c.Header}
+		}
+
+		return fw.Write(cells)
+	}
+
+	write := func(v reflect.Value) (e error) {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return ErrInvalidRows
+			}
+			v = v.Elem()
+		}
+
+		if !ready {
+			if e = header(v.Type()); e != nil {
+				return
+			}
+		}
+
+		if e = fw.Write(rowOf(v, cols)); e != nil {
+			return
+		}
+
+		if n++; n%chunk == 0 {
+			e = flush()
+		}
+
+		return
+	}
+
+	if err = each(rows, header, write); err != nil {
+		fw.Close()
+		return
+	}
+
+	return fw.Close()
+}
+
+// each walks over rows, header is called up front when
+// the element type is a struct, so empty rows still get the header.
+func each(rows interface{}, header func(reflect.Type) error, fn func(reflect.Value) error) (err error) {
+	if it, ok := rows.(Iterator); ok {
+		return it(func(row interface{}) error {
+			return fn(reflect.ValueOf(row))
+		})
+	}
+
+	if it, ok := rows.(func(func(interface{}) error) error); ok {
+		return each(Iterator(it), header, fn)
+	}
+
+	v := reflect.ValueOf(rows)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Chan:
+		if t := indirect(v.Type().Elem()); t.Kind() == reflect.Struct {
+			if err = header(t); err != nil {
+				return
+			}
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err = fn(v.Index(i)); err != nil {
+				return
+			}
+		}
+	case reflect.Chan:
+		for {
+			x, ok := v.Recv()
+			if !ok {
+				break
+			}
+			if err = fn(x); err != nil {
+				return
+			}
+		}
+	default:
+		return fmt.Errorf("%v, got %T", ErrInvalidRows, rows)
+	}
+
+	return
+}
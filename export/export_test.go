@@ -0,0 +1,165 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type base struct {
+	ID int64 `export:"No"`
+}
+
+type order struct {
+	base
+	Code      string    `export:"Kode"`
+	Customer  *string   `export:"Pelanggan"`
+	Total     float64   `export:"Total,rupiah"`
+	Discount  float64   `export:"Diskon,#.###,##"`
+	Paid      bool      `export:"Lunas,Ya/Tidak"`
+	CreatedAt time.Time `export:"Tanggal,02/01/2006"`
+	Secret    string    `export:"-"`
+	note      string
+}
+
+func orders() []*order {
+	name := "Budi, Jr."
+	at := time.Date(2019, 8, 17, 10, 0, 0, 0, time.UTC)
+
+	return []*order{
+		{base{1}, "INV-1", &name, 1250000, 1500.5, true, at, "x", "y"},
+		{base{2}, "INV-2", nil, 75000, 0, false, time.Time{}, "x", "y"},
+	}
+}
+
+func TestColumns(t *testing.T) {
+	cols := Columns(order{})
+
+	var h []string
+	for _, c := range cols {
+		h = append(h, c.Header)
+	}
+
+	assert.Equal(t, []string{"No", "Kode", "Pelanggan", "Total", "Diskon", "Lunas", "Tanggal"}, h)
+	assert.Equal(t, "#.###,##", cols[4].Format)
+}
+
+func TestWriteCSV(t *testing.T) {
+	var b bytes.Buffer
+	assert.NoError(t, Write(&b, orders(), Options{}))
+
+	rec, err := csv.NewReader(&b).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"No", "Kode", "Pelanggan", "Total", "Diskon", "Lunas", "Tanggal"},
+		{"1", "INV-1", "Budi, Jr.", "Rp 1.250.000", "1.500,50", "Ya", "17/08/2019"},
+		{"2", "INV-2", "", "Rp 75.000", "0,00", "Tidak", ""},
+	}, rec)
+}
+
+func TestWriteColumns(t *testing.T) {
+	var b bytes.Buffer
+	assert.NoError(t, Write(&b, orders(), Options{Columns: []string{"Code", "No"}, Comma: ';'}))
+	assert.Equal(t, "Kode;No\nINV-1;1\nINV-2;2\n", b.String())
+
+	b.Reset()
+	assert.NoError(t, Write(&b, orders(), Options{Columns: []string{"Code"}, NoHeader: true}))
+	assert.Equal(t, "INV-1\nINV-2\n", b.String())
+}
+
+func TestWriteEmpty(t *testing.T) {
+	var b bytes.Buffer
+	assert.NoError(t, Write(&b, []order{}, Options{Columns: []string{"Kode"}}))
+	assert.Equal(t, "Kode\n", b.String())
+}
+
+func TestWriteSources(t *testing.T) {
+	ch := make(chan order)
+	go func() {
+		for _, o := range orders() {
+			ch <- *o
+		}
+		close(ch)
+	}()
+
+	var b bytes.Buffer
+	assert.NoError(t, Write(&b, ch, Options{Columns: []string{"Code"}}))
+	assert.Equal(t, "Kode\nINV-1\nINV-2\n", b.String())
+
+	b.Reset()
+	it := Iterator(func(yield func(interface{}) error) error {
+		for _, o := range orders() {
+			if err := yield(o); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	assert.NoError(t, Write(&b, it, Options{Columns: []string{"Code"}}))
+	assert.Equal(t, "Kode\nINV-1\nINV-2\n", b.String())
+
+	assert.Error(t, Write(&b, "rows", Options{}))
+	assert.Equal(t, ErrUnsupportedFormat, Write(&b, orders(), Options{Format: "pdf"}))
+}
+
+type flusher struct {
+	bytes.Buffer
+	n int
+}
+
+func (f *flusher) Flush() { f.n++ }
+
+func TestWriteChunk(t *testing.T) {
+	rows := make([]base, 10)
+
+	f := &flusher{}
+	assert.NoError(t, Write(f, rows, Options{ChunkSize: 3}))
+	assert.Equal(t, 3, f.n)
+	assert.Equal(t, 11, strings.Count(f.String(), "\n"))
+}
+
+func TestWriteXLSX(t *testing.T) {
+	var b bytes.Buffer
+	assert.NoError(t, Write(&b, orders(), Options{Format: XLSX, Sheet: "Order [2019]"}))
+
+	zr, err := zip.NewReader(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	assert.NoError(t, err)
+
+	files := map[string]string{}
+	for _, f := range zr.File {
+		r, _ := f.Open()
+		x, _ := ioutil.ReadAll(r)
+		r.Close()
+		files[f.Name] = string(x)
+	}
+
+	assert.Contains(t, files, "[Content_Types].xml")
+	assert.Contains(t, files["xl/workbook.xml"], `name="Order 2019"`)
+
+	sheet := files["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<c r="A1" t="inlineStr"><is><t xml:space="preserve">No</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="A2"><v>1</v></c>`)
+	assert.Contains(t, sheet, `<t xml:space="preserve">Rp 1.250.000</t>`)
+	assert.True(t, strings.HasSuffix(sheet, "</sheetData></worksheet>"))
+}
+
+func TestOptions(t *testing.T) {
+	assert.Equal(t, "export.csv", Options{}.FileName())
+	assert.Equal(t, "report.xlsx", Options{Format: XLSX, Filename: "report"}.FileName())
+	assert.Equal(t, "report.XLSX", Options{Format: XLSX, Filename: "report.XLSX"}.FileName())
+	assert.Equal(t, "text/csv; charset=utf-8", Options{}.ContentType())
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "AZ", columnName(51))
+	assert.Equal(t, "BA", columnName(52))
+}
@@ -0,0 +1,110 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package export
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/enigma-id/go/utility"
+)
+
+// DefaultTimeLayout is layout of time.Time column without format.
+var DefaultTimeLayout = "2006-01-02 15:04:05"
+
+// format the value by the column format, supported format:
+//   - time layout for time.Time, ex. 02/01/2006
+//   - rupiah, number with indonesian thousand separator prefixed with Rp
+//   - number pattern of utility.FormatNumber, ex. #.###,##
+//   - printf verb, ex. %.2f or %05d
+//   - true/false label for bool, ex. Ya/Tidak
+func format(v reflect.Value, f string) Cell {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return Cell{}
+		}
+		v = v.Elem()
+	}
+
+	if !v.CanInterface() {
+		return Cell{}
+	}
+
+	i := v.Interface()
+	if t, ok := i.(time.Time); ok {
+		if t.IsZero() {
+			return Cell{}
+		}
+		if f == "" {
+			f = DefaultTimeLayout
+		}
+		return Cell{Value: t.Format(f)}
+	}
+
+	if dv, ok := i.(driver.Valuer); ok {
+		x, err := dv.Value()
+		if err != nil || x == nil {
+			return Cell{}
+		}
+		return format(reflect.ValueOf(x), f)
+	}
+
+	if strings.Contains(f, "%") {
+		return Cell{Value: fmt.Sprintf(f, i)}
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if p := strings.SplitN(f, "/", 2); len(p) == 2 {
+			if v.Bool() {
+				return Cell{Value: p[0]}
+			}
+			return Cell{Value: p[1]}
+		}
+		return Cell{Value: strconv.FormatBool(v.Bool())}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if f != "" {
+			return number(float64(v.Int()), f)
+		}
+		return Cell{Value: strconv.FormatInt(v.Int(), 10), Number: true}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if f != "" {
+			return number(float64(v.Uint()), f)
+		}
+		return Cell{Value: strconv.FormatUint(v.Uint(), 10), Number: true}
+	case reflect.Float32, reflect.Float64:
+		if f != "" {
+			return number(v.Float(), f)
+		}
+		return Cell{Value: strconv.FormatFloat(v.Float(), 'f', -1, 64), Number: true}
+	case reflect.String:
+		return Cell{Value: v.String()}
+	}
+
+	if s, ok := i.(fmt.Stringer); ok {
+		return Cell{Value: s.String()}
+	}
+
+	return Cell{Value: fmt.Sprint(i)}
+}
+
+func number(n float64, f string) Cell {
+	switch {
+	case f == "rupiah":
+		return Cell{Value: "Rp " + utility.FormatNumber("#.###,", n)}
+	case strings.Contains(f, "#"):
+		return Cell{Value: utility.FormatNumber(f, n)}
+	}
+
+	if p, err := strconv.Atoi(f); err == nil {
+		return Cell{Value: strconv.FormatFloat(n, 'f', p, 64), Number: true}
+	}
+
+	return Cell{Value: strconv.FormatFloat(n, 'f', -1, 64), Number: true}
+}
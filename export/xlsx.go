@@ -0,0 +1,152 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="{{sheet}}" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// xlsxWriter writes a minimal workbook with single sheet, cells are
+// written as inline string so the sheet can be streamed row by row
+// without keeping shared strings table in memory.
+type xlsxWriter struct {
+	zw  *zip.Writer
+	w   *bufio.Writer
+	row int
+}
+
+func newXLSXWriter(w io.Writer, opts Options) (x *xlsxWriter, err error) {
+	x = &xlsxWriter{zw: zip.NewWriter(w)}
+
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", strings.Replace(xlsxWorkbook, "{{sheet}}", escape(sheetName(opts.Sheet)), 1)},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+
+	var f io.Writer
+	for _, p := range parts {
+		if f, err = x.zw.Create(p.name); err != nil {
+			return nil, err
+		}
+		if _, err = io.WriteString(f, p.body); err != nil {
+			return nil, err
+		}
+	}
+
+	if f, err = x.zw.Create("xl/worksheets/sheet1.xml"); err != nil {
+		return nil, err
+	}
+
+	x.w = bufio.NewWriter(f)
+	_, err = x.w.WriteString(xlsxSheetStart)
+
+	return
+}
+
+func (x *xlsxWriter) Write(cells []Cell) (err error) {
+	x.row++
+	r := strconv.Itoa(x.row)
+
+	x.w.WriteString(`<row r="` + r + `">`)
+	for i, c := range cells {
+		if c.Value == "" {
+			continue
+		}
+
+		ref := columnName(i) + r
+		if c.Number {
+			x.w.WriteString(`<c r="` + ref + `"><v>` + c.Value + `</v></c>`)
+			continue
+		}
+
+		x.w.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t xml:space="preserve">`)
+		x.w.WriteString(escape(c.Value))
+		x.w.WriteString(`</t></is></c>`)
+	}
+	_, err = x.w.WriteString(`</row>`)
+
+	return
+}
+
+func (x *xlsxWriter) Flush() error {
+	if err := x.w.Flush(); err != nil {
+		return err
+	}
+
+	return x.zw.Flush()
+}
+
+func (x *xlsxWriter) Close() error {
+	if _, err := x.w.WriteString(xlsxSheetEnd); err != nil {
+		return err
+	}
+	if err := x.w.Flush(); err != nil {
+		return err
+	}
+
+	return x.zw.Close()
+}
+
+// columnName returns spreadsheet column name of index i, 0 is A, 26 is AA.
+func columnName(i int) string {
+	var b []byte
+	for i++; i > 0; i = (i - 1) / 26 {
+		b = append([]byte{byte('A' + (i-1)%26)}, b...)
+	}
+
+	return string(b)
+}
+
+// sheetName removes characters not allowed by excel
+// and limit it into 31 characters.
+func sheetName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return -1
+		}
+		return r
+	}, s)
+
+	if s = strings.TrimSpace(s); s == "" {
+		return "Sheet1"
+	}
+	if r := []rune(s); len(r) > 31 {
+		s = string(r[:31])
+	}
+
+	return s
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
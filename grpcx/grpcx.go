@@ -0,0 +1,209 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package grpcx
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/enigma-id/go/rest"
+	"github.com/enigma-id/go/utility/log"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type (
+	// Option configures the server.
+	Option func(*options)
+
+	options struct {
+		logger  *zap.Logger
+		unary   []grpc.UnaryServerInterceptor
+		stream  []grpc.StreamServerInterceptor
+		server  []grpc.ServerOption
+		metrics *Metrics
+		logging bool
+	}
+
+	// Server is grpc server with recovery and logging interceptors
+	// and health service registered.
+	Server struct {
+		*grpc.Server
+
+		// Health is the health service, serving status is set
+		// into not serving when the server is shutting down.
+		Health *health.Server
+
+		Listener net.Listener
+		Logger   *zap.Logger
+	}
+)
+
+// WithLogger sets logger of the server, default log.Logger.
+func WithLogger(l *zap.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// WithUnary adds unary interceptors, executed after the default interceptors.
+func WithUnary(i ...grpc.UnaryServerInterceptor) Option {
+	return func(o *options) {
+		o.unary = append(o.unary, i...)
+	}
+}
+
+// WithStream adds stream interceptors, executed after the default interceptors.
+func WithStream(i ...grpc.StreamServerInterceptor) Option {
+	return func(o *options) {
+		o.stream = append(o.stream, i...)
+	}
+}
+
+// WithJWT adds jwt auth interceptors using the config.
+func WithJWT(config JWTConfig) Option {
+	return func(o *options) {
+		o.unary = append(o.unary, UnaryJWT(config))
+		o.stream = append(o.stream, StreamJWT(config))
+	}
+}
+
+// WithMetrics records the metrics of the requests, including the recovered panic.
+func WithMetrics(m *Metrics) Option {
+	return func(o *options) {
+		o.metrics = m
+	}
+}
+
+// WithServerOptions adds grpc server options, ex. grpc.Creds.
+func WithServerOptions(opts ...grpc.ServerOption) Option {
+	return func(o *options) {
+		o.server = append(o.server, opts...)
+	}
+}
+
+// WithoutLogging disables request logging.
+func WithoutLogging() Option {
+	return func(o *options) {
+		o.logging = false
+	}
+}
+
+// New creates grpc server, requests are recovered from panic
+// and logged by default. The interceptors are chained in order of
+// metrics, logger, recovery then the added interceptors.
+func New(opts ...Option) *Server {
+	o := &options{logger: log.Logger, logging: true}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var unary []grpc.UnaryServerInterceptor
+	var stream []grpc.StreamServerInterceptor
+	if o.metrics != nil {
+		unary = append(unary, o.metrics.Unary())
+		stream = append(stream, o.metrics.Stream())
+	}
+	if o.logging {
+		unary = append(unary, UnaryLogger(o.logger))
+		stream = append(stream, StreamLogger(o.logger))
+	}
+	unary = append(unary, UnaryRecover(o.logger))
+	stream = append(stream, StreamRecover(o.logger))
+
+	so := append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(append(unary, o.unary...)...),
+		grpc.ChainStreamInterceptor(append(stream, o.stream...)...),
+	}, o.server...)
+
+	s := &Server{
+		Server: grpc.NewServer(so...),
+		Health: health.NewServer(),
+		Logger: o.logger,
+	}
+	healthpb.RegisterHealthServer(s.Server, s.Health)
+
+	return s
+}
+
+// Start listens on the address and serves the requests.
+func (s *Server) Start(address string) (err error) {
+	if s.Listener == nil {
+		if s.Listener, err = net.Listen("tcp", address); err != nil {
+			return
+		}
+	}
+
+	s.Logger.Info("grpc server started on " + s.Listener.Addr().String())
+	return s.Serve(s.Listener)
+}
+
+// Shutdown stops the server gracefully, pending requests are
+// forcibly closed when the context is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.Health.Shutdown()
+
+	done := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.Stop()
+		return ctx.Err()
+	}
+}
+
+// Run starts the rest engine and the grpc server, both are shut down
+// gracefully when SIGINT or SIGTERM received or either of them failed.
+//
+//	e := rest.New()
+//	s := grpcx.New(grpcx.WithJWT(grpcx.JWTConfig{SigningKey: rest.JwtKey()}))
+//	pb.RegisterUserServiceServer(s, &userService{})
+//
+//	log.Fatal(grpcx.Run(e, s, ":8080", ":9090", 10*time.Second))
+func Run(e *rest.Rest, s *Server, httpAddr, grpcAddr string, timeout time.Duration) error {
+	errs := make(chan error, 2)
+	go func() { errs <- e.Start(httpAddr) }()
+	go func() { errs <- s.Start(grpcAddr) }()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(quit)
+
+	var err error
+	select {
+	case <-quit:
+	case err = <-errs:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	s.Logger.Info("shutting down http and grpc server")
+	if e := e.Shutdown(ctx); err == nil {
+		err = e
+	}
+	if e := s.Shutdown(ctx); err == nil {
+		err = e
+	}
+
+	if err == http.ErrServerClosed || err == grpc.ErrServerStopped {
+		err = nil
+	}
+
+	return err
+}
@@ -0,0 +1,114 @@
+package grpcx
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func serve(t *testing.T, s *Server) (healthpb.HealthClient, func()) {
+	l := bufconn.Listen(1 << 20)
+	s.Listener = l
+	go s.Start("")
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return l.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+
+	return healthpb.NewHealthClient(conn), func() {
+		conn.Close()
+		s.Shutdown(context.Background())
+	}
+}
+
+func TestHealth(t *testing.T) {
+	s := New(WithLogger(zap.NewNop()))
+	c, stop := serve(t, s)
+
+	res, err := c.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, res.Status)
+	}
+
+	stop()
+	res, _ = s.Health.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, res.Status)
+}
+
+func TestJWT(t *testing.T) {
+	key := []byte("secret")
+	var sub interface{}
+
+	check := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, h grpc.UnaryHandler) (interface{}, error) {
+		sub = Token(ctx).Claims.(jwt.MapClaims)["sub"]
+		return h(ctx, req)
+	}
+
+	s := New(WithLogger(zap.NewNop()),
+		WithJWT(JWTConfig{SigningKey: key, Skipper: func(string) bool { return false }}),
+		WithUnary(check))
+	c, stop := serve(t, s)
+	defer stop()
+
+	_, err := c.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "svc-order"}).SignedString(key)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+	_, err = c.Check(ctx, &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, "svc-order", sub)
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token+"x")
+	_, err = c.Check(ctx, &healthpb.HealthCheckRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestRecoverAndMetrics(t *testing.T) {
+	m := NewMetrics("grpc_test")
+	boom := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, h grpc.UnaryHandler) (interface{}, error) {
+		if RequestID(ctx) == "panic" {
+			panic("boom")
+		}
+		return h(ctx, req)
+	}
+
+	s := New(WithLogger(zap.NewNop()), WithMetrics(m), WithUnary(boom))
+	c, stop := serve(t, s)
+	defer stop()
+
+	_, err := c.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), MetadataRequestID, "panic")
+	_, err = c.Check(ctx, &healthpb.HealthCheckRequest{})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	method := "/grpc.health.v1.Health/Check"
+	assert.Equal(t, "2", m.Requests.Get(method).String())
+	assert.Equal(t, "1", m.Codes.Get(method+" OK").String())
+	assert.Equal(t, "1", m.Codes.Get(method+" Internal").String())
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	s := New(WithLogger(zap.NewNop()))
+	_, stop := serve(t, s)
+	defer stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, s.Shutdown(ctx))
+}
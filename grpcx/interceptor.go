@@ -0,0 +1,114 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package grpcx
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// MetadataRequestID is the metadata key of request id,
+// same as X-Request-ID header of the rest.
+const MetadataRequestID = "x-request-id"
+
+// UnaryRecover recovers panic of the handler into codes.Internal error.
+func UnaryRecover(l *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, h grpc.UnaryHandler) (res interface{}, err error) {
+		defer recovery(l, info.FullMethod, &err)
+		return h(ctx, req)
+	}
+}
+
+// StreamRecover recovers panic of the handler into codes.Internal error.
+func StreamRecover(l *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, h grpc.StreamHandler) (err error) {
+		defer recovery(l, info.FullMethod, &err)
+		return h(srv, ss)
+	}
+}
+
+func recovery(l *zap.Logger, method string, err *error) {
+	if r := recover(); r != nil {
+		stack := make([]byte, 4<<10)
+		stack = stack[:runtime.Stack(stack, false)]
+
+		l.Error(fmt.Sprintf("[PANIC RECOVER] %v", r), zap.String("method", method), zap.ByteString("stack", stack))
+		*err = status.Error(codes.Internal, "internal error")
+	}
+}
+
+// UnaryLogger logs the requests, like HTTPLogger of the rest.
+func UnaryLogger(l *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, h grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		res, err := h(ctx, req)
+		logRequest(l, ctx, info.FullMethod, start, err)
+		return res, err
+	}
+}
+
+// StreamLogger logs the streams when it ends.
+func StreamLogger(l *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, h grpc.StreamHandler) error {
+		start := time.Now()
+		err := h(srv, ss)
+		logRequest(l, ss.Context(), info.FullMethod, start, err)
+		return err
+	}
+}
+
+func logRequest(l *zap.Logger, ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	fields := []zap.Field{
+		zap.String("method", method),
+		zap.String("latecy", fmt.Sprintf("%1.1fms", float64(time.Since(start))/float64(time.Millisecond))),
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		fields = append(fields, zap.String("ip", p.Addr.String()))
+	}
+	if id := RequestID(ctx); id != "" {
+		fields = append(fields, zap.String("request_id", id))
+	}
+
+	switch code {
+	case codes.OK:
+		l.Info("GRPC/"+code.String(), fields...)
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		l.Error("GRPC/"+code.String(), append(fields, zap.Error(err))...)
+	default:
+		l.Warn("GRPC/"+code.String(), fields...)
+	}
+}
+
+// RequestID returns request id from the incoming metadata.
+func RequestID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(MetadataRequestID); len(v) > 0 {
+			return v[0]
+		}
+	}
+
+	return ""
+}
+
+// wrappedStream overrides context of the server stream.
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (w *wrappedStream) Context() context.Context {
+	return w.ctx
+}
@@ -0,0 +1,154 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package grpcx
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type (
+	// JWTConfig defines the config of jwt interceptors, like JWTConfig of mw.
+	JWTConfig struct {
+		// Skipper defines a function to skip the method, by default
+		// health service is skipped.
+		Skipper func(method string) bool
+
+		// Signing key to validate token.
+		// Required.
+		SigningKey interface{}
+
+		// Signing method, used to check token signing method.
+		// Optional. Default value HS256.
+		SigningMethod string
+
+		// Claims are extendable claims data defining token content.
+		// Optional. Default value jwt.MapClaims
+		Claims jwt.Claims
+
+		// Metadata key of the token.
+		// Optional. Default value "authorization".
+		Metadata string
+
+		// AuthScheme of the token.
+		// Optional. Default value "Bearer".
+		AuthScheme string
+	}
+
+	tokenKey struct{}
+)
+
+// DefaultJWTSkipper skips the health service.
+func DefaultJWTSkipper(method string) bool {
+	return strings.HasPrefix(method, "/grpc.health.v1.Health/")
+}
+
+// UnaryJWT returns unary interceptor validating jwt of the incoming metadata,
+// the token can be retrieved using Token, invalid or missing token returns
+// codes.Unauthenticated.
+func UnaryJWT(config JWTConfig) grpc.UnaryServerInterceptor {
+	auth := config.authenticate()
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, h grpc.UnaryHandler) (interface{}, error) {
+		if config.Skipper(info.FullMethod) {
+			return h(ctx, req)
+		}
+
+		ctx, err := auth(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return h(ctx, req)
+	}
+}
+
+// StreamJWT returns stream interceptor validating jwt of the incoming metadata.
+func StreamJWT(config JWTConfig) grpc.StreamServerInterceptor {
+	auth := config.authenticate()
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, h grpc.StreamHandler) error {
+		if config.Skipper(info.FullMethod) {
+			return h(srv, ss)
+		}
+
+		ctx, err := auth(ss.Context())
+		if err != nil {
+			return err
+		}
+
+		return h(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// Token returns the jwt token of the request.
+func Token(ctx context.Context) *jwt.Token {
+	t, _ := ctx.Value(tokenKey{}).(*jwt.Token)
+	return t
+}
+
+func (config *JWTConfig) authenticate() func(context.Context) (context.Context, error) {
+	if config.SigningKey == nil {
+		panic("grpcx: jwt interceptor requires signing key")
+	}
+	if config.Skipper == nil {
+		config.Skipper = DefaultJWTSkipper
+	}
+	if config.SigningMethod == "" {
+		config.SigningMethod = "HS256"
+	}
+	if config.Claims == nil {
+		config.Claims = jwt.MapClaims{}
+	}
+	if config.Metadata == "" {
+		config.Metadata = "authorization"
+	}
+	if config.AuthScheme == "" {
+		config.AuthScheme = "Bearer"
+	}
+
+	keyFunc := func(t *jwt.Token) (interface{}, error) {
+		if t.Method.Alg() != config.SigningMethod {
+			return nil, fmt.Errorf("unexpected jwt signing method=%v", t.Header["alg"])
+		}
+		return config.SigningKey, nil
+	}
+
+	return func(ctx context.Context) (context.Context, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		var auth string
+		if v := md.Get(config.Metadata); len(v) > 0 {
+			auth = v[0]
+		}
+
+		l := len(config.AuthScheme)
+		if len(auth) <= l+1 || !strings.EqualFold(auth[:l], config.AuthScheme) {
+			return ctx, status.Error(codes.Unauthenticated, "missing or malformed jwt")
+		}
+
+		var t *jwt.Token
+		var err error
+		if _, ok := config.Claims.(jwt.MapClaims); ok {
+			t, err = jwt.Parse(auth[l+1:], keyFunc)
+		} else {
+			claims := reflect.New(reflect.ValueOf(config.Claims).Type().Elem()).Interface().(jwt.Claims)
+			t, err = jwt.ParseWithClaims(auth[l+1:], claims, keyFunc)
+		}
+		if err != nil || !t.Valid {
+			return ctx, status.Error(codes.Unauthenticated, "invalid or expired jwt")
+		}
+
+		return context.WithValue(ctx, tokenKey{}, t), nil
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package grpcx
+
+import (
+	"context"
+	"expvar"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Metrics counts requests, response codes and latency of each method,
+// published through expvar so it's available on /debug/vars.
+type Metrics struct {
+	Requests *expvar.Map
+	Codes    *expvar.Map
+	Latency  *expvar.Map
+}
+
+// NewMetrics creates metrics published as expvar with the name,
+// name should be unique, ex. "grpc".
+func NewMetrics(name string) *Metrics {
+	m := &Metrics{Requests: new(expvar.Map), Codes: new(expvar.Map), Latency: new(expvar.Map)}
+
+	v := expvar.NewMap(name)
+	v.Set("requests", m.Requests)
+	v.Set("codes", m.Codes)
+	v.Set("latency_ms", m.Latency)
+
+	return m
+}
+
+// Unary returns unary interceptor recording the metrics.
+func (m *Metrics) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, h grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		res, err := h(ctx, req)
+		m.record(info.FullMethod, start, err)
+		return res, err
+	}
+}
+
+// Stream returns stream interceptor recording the metrics.
+func (m *Metrics) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, h grpc.StreamHandler) error {
+		start := time.Now()
+		err := h(srv, ss)
+		m.record(info.FullMethod, start, err)
+		return err
+	}
+}
+
+func (m *Metrics) record(method string, start time.Time, err error) {
+	m.Requests.Add(method, 1)
+	m.Codes.Add(method+" "+status.Code(err).String(), 1)
+	m.Latency.AddFloat(method, float64(time.Since(start))/float64(time.Millisecond))
+}
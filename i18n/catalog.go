@@ -0,0 +1,111 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Load loads catalogs of the directory into Default bundle.
+func Load(dir string) error {
+	return Default.LoadDir(dir)
+}
+
+// LoadDir loads all json and yaml files in the directory, locale is taken
+// from the file name before the first dot, so en.json, en.validation.yaml
+// and id.yml are loaded as en, en and id.
+func (b *Bundle) LoadDir(dir string) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+
+		switch filepath.Ext(f.Name()) {
+		case ".json", ".yaml", ".yml":
+			if err = b.LoadFile(filepath.Join(dir, f.Name())); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadFile loads single catalog file, see LoadDir.
+func (b *Bundle) LoadFile(file string) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	name := filepath.Base(file)
+	locale := name[:strings.Index(name, ".")]
+
+	messages := make(map[string]interface{})
+	if filepath.Ext(name) == ".json" {
+		err = json.Unmarshal(data, &messages)
+	} else {
+		err = yaml.Unmarshal(data, &messages)
+	}
+
+	if err != nil {
+		return fmt.Errorf("i18n: parsing %s: %v", file, err)
+	}
+
+	b.Add(locale, messages)
+
+	return nil
+}
+
+func flatten(c map[string]*Message, prefix string, messages map[string]interface{}) {
+	for k, v := range messages {
+		if prefix != "" {
+			k = prefix + "." + k
+		}
+
+		switch v := v.(type) {
+		case string:
+			c[k] = &Message{Text: v}
+		case map[string]interface{}:
+			if isPlural(v) {
+				m := &Message{Plural: make(map[string]string)}
+				for p, t := range v {
+					m.Plural[p] = fmt.Sprint(t)
+				}
+				c[k] = m
+				continue
+			}
+
+			flatten(c, k, v)
+		default:
+			c[k] = &Message{Text: fmt.Sprint(v)}
+		}
+	}
+}
+
+func isPlural(m map[string]interface{}) bool {
+	if _, ok := m["other"]; !ok {
+		return false
+	}
+
+	for k, v := range m {
+		if _, ok := v.(string); !ok || !pluralCategories[k] {
+			return false
+		}
+	}
+
+	return true
+}
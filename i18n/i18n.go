@@ -0,0 +1,246 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/enigma-id/go/env"
+	"golang.org/x/text/language"
+)
+
+// Default bundle used by the package level functions,
+// the fallback locale is taken from APP_LOCALE, default is en.
+var Default = New(env.GetString("APP_LOCALE", "en"))
+
+// Bundle holding catalogs of the locales.
+type Bundle struct {
+	Fallback string
+
+	mu       sync.RWMutex
+	catalogs map[string]map[string]*Message
+	locales  []string
+	tags     []language.Tag
+	matcher  language.Matcher
+}
+
+// Message is single translation, plural forms are keyed
+// by the plural category (zero, one, two, few, many, other).
+type Message struct {
+	Text   string
+	Plural map[string]string
+}
+
+// New creates bundle with the fallback locale.
+func New(fallback string) *Bundle {
+	return &Bundle{
+		Fallback: fallback,
+		catalogs: make(map[string]map[string]*Message),
+	}
+}
+
+// Add adds messages into catalog of the locale, nested map is flattened
+// using dot as separator, map of plural categories is read as plural message.
+func (b *Bundle) Add(locale string, messages map[string]interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.catalogs[locale]
+	if !ok {
+		c = make(map[string]*Message)
+		b.catalogs[locale] = c
+
+		b.locales = append(b.locales, locale)
+		b.tags = append(b.tags, language.Make(locale))
+		b.matcher = language.NewMatcher(b.tags)
+	}
+
+	flatten(c, "", messages)
+}
+
+// Locales returns the loaded locales.
+func (b *Bundle) Locales() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return append([]string{}, b.locales...)
+}
+
+// Match returns the best loaded locale for the Accept-Language header value,
+// fallback locale is returned when nothing is matched.
+func (b *Bundle) Match(accept ...string) string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.matcher == nil {
+		return b.Fallback
+	}
+
+	var tags []language.Tag
+	for _, a := range accept {
+		if t, _, err := language.ParseAcceptLanguage(a); err == nil {
+			tags = append(tags, t...)
+		}
+	}
+
+	if _, i, c := b.matcher.Match(tags...); c != language.No {
+		return b.locales[i]
+	}
+
+	return b.Fallback
+}
+
+// Lookup returns message of the key in the locale, the base language and
+// the fallback locale are tried when the message is not found.
+func (b *Bundle) Lookup(locale string, key string) (*Message, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, l := range []string{locale, baseLanguage(locale), b.Fallback} {
+		if m, ok := b.catalogs[l][key]; ok {
+			return m, true
+		}
+	}
+
+	return nil, false
+}
+
+// Translate returns translated message of the key, when the args contains
+// "count" the plural form is selected. Key is returned as is when not found.
+func (b *Bundle) Translate(locale string, key string, args ...map[string]interface{}) string {
+	m, ok := b.Lookup(locale, key)
+	if !ok {
+		return interpolate(key, args)
+	}
+
+	return m.Format(locale, args...)
+}
+
+// Format returns text of the message with the args interpolated.
+func (m *Message) Format(locale string, args ...map[string]interface{}) string {
+	text := m.Text
+	if len(m.Plural) > 0 {
+		text = m.Plural["other"]
+
+		if n, ok := count(args); ok {
+			if t, ok := m.Plural[PluralCategory(locale, n)]; ok {
+				text = t
+			}
+			if t, ok := m.Plural["zero"]; ok && n == 0 {
+				text = t
+			}
+		}
+	}
+
+	return interpolate(text, args)
+}
+
+type localeKey struct{}
+
+// WithLocale returns context holding the locale.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// Locale returns the locale of the context, fallback locale
+// of Default bundle is returned when not set.
+func Locale(ctx context.Context) string {
+	if ctx != nil {
+		if l, ok := ctx.Value(localeKey{}).(string); ok && l != "" {
+			return l
+		}
+	}
+
+	return Default.Fallback
+}
+
+type timezoneKey struct{}
+
+// WithTimezone returns context holding the timezone of the user.
+func WithTimezone(ctx context.Context, loc *time.Location) context.Context {
+	return context.WithValue(ctx, timezoneKey{}, loc)
+}
+
+// Timezone returns the timezone of the context, time.Local when not set.
+func Timezone(ctx context.Context) *time.Location {
+	if ctx != nil {
+		if loc, ok := ctx.Value(timezoneKey{}).(*time.Location); ok && loc != nil {
+			return loc
+		}
+	}
+
+	return time.Local
+}
+
+// T translates the key using locale of the context.
+//
+//	i18n.T(ctx, "order.created", map[string]interface{}{"code": o.Code})
+func T(ctx context.Context, key string, args ...map[string]interface{}) string {
+	return Default.Translate(Locale(ctx), key, args...)
+}
+
+// TDefault same as T, but returns def interpolated with the args
+// when the key is not found.
+func TDefault(ctx context.Context, key string, def string, args ...map[string]interface{}) string {
+	if m, ok := Default.Lookup(Locale(ctx), key); ok {
+		return m.Format(Locale(ctx), args...)
+	}
+
+	return interpolate(def, args)
+}
+
+var placeholder = regexp.MustCompile(`:([a-zA-Z_][a-zA-Z0-9_]*)`)
+
+// interpolate replaces :name in the text with value of the args,
+// unknown placeholder is left as is.
+func interpolate(text string, args []map[string]interface{}) string {
+	if len(args) == 0 || !strings.Contains(text, ":") {
+		return text
+	}
+
+	return placeholder.ReplaceAllStringFunc(text, func(s string) string {
+		for _, a := range args {
+			if v, ok := a[s[1:]]; ok {
+				return fmt.Sprint(v)
+			}
+		}
+
+		return s
+	})
+}
+
+func count(args []map[string]interface{}) (int64, bool) {
+	for _, a := range args {
+		switch n := a["count"].(type) {
+		case int:
+			return int64(n), true
+		case int32:
+			return int64(n), true
+		case int64:
+			return n, true
+		case uint:
+			return int64(n), true
+		case uint64:
+			return int64(n), true
+		case float64:
+			return int64(n), true
+		}
+	}
+
+	return 0, false
+}
+
+func baseLanguage(locale string) string {
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		return locale[:i]
+	}
+
+	return locale
+}
@@ -0,0 +1,59 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package i18n
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBundle(t *testing.T) {
+	b := New("en")
+	assert.NoError(t, b.LoadDir("_fixture/lang"))
+	assert.ElementsMatch(t, []string{"en", "id"}, b.Locales())
+
+	args := map[string]interface{}{"code": "SO-1"}
+	assert.Equal(t, "Order SO-1 has been created", b.Translate("en", "order.created", args))
+	assert.Equal(t, "Pesanan SO-1 telah dibuat", b.Translate("id-ID", "order.created", args))
+	assert.Equal(t, "The :attribute field is required", b.Translate("fr", "validation.required"))
+	assert.Equal(t, "missing.key", b.Translate("id", "missing.key"))
+
+	assert.Equal(t, "No items", b.Translate("en", "order.items", map[string]interface{}{"count": 0}))
+	assert.Equal(t, "1 item", b.Translate("en", "order.items", map[string]interface{}{"count": 1}))
+	assert.Equal(t, "3 items", b.Translate("en", "order.items", map[string]interface{}{"count": 3}))
+	assert.Equal(t, "1 barang", b.Translate("id", "order.items", map[string]interface{}{"count": int64(1)}))
+
+	assert.Equal(t, "id", b.Match("id-ID,id;q=0.9,en;q=0.8"))
+	assert.Equal(t, "en", b.Match("en-GB"))
+	assert.Equal(t, "en", b.Match("ja"))
+	assert.Equal(t, "en", b.Match("invalid;;"))
+}
+
+func TestT(t *testing.T) {
+	d := Default
+	defer func() { Default = d }()
+
+	Default = New("en")
+	assert.NoError(t, Load("_fixture/lang"))
+
+	ctx := WithLocale(context.Background(), "id")
+	assert.Equal(t, "id", Locale(ctx))
+	assert.Equal(t, "en", Locale(context.Background()))
+
+	assert.Equal(t, "Tidak Ditemukan", T(ctx, "Not Found"))
+	assert.Equal(t, "name wajib diisi", T(ctx, "validation.required", map[string]interface{}{"attribute": "name"}))
+	assert.Equal(t, "Hi John :x", TDefault(ctx, "greeting", "Hi :name :x", map[string]interface{}{"name": "John"}))
+}
+
+func TestPluralCategory(t *testing.T) {
+	assert.Equal(t, "other", PluralCategory("id", 1))
+	assert.Equal(t, "one", PluralCategory("en-US", 1))
+	assert.Equal(t, "other", PluralCategory("en", 0))
+	assert.Equal(t, "one", PluralCategory("fr", 0))
+	assert.Equal(t, "few", PluralCategory("ru", 22))
+	assert.Equal(t, "many", PluralCategory("ru", 11))
+}
@@ -0,0 +1,59 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package i18n
+
+// languages without plural forms.
+var noPlural = map[string]bool{
+	"id": true, "ms": true, "ja": true, "ko": true, "zh": true,
+	"th": true, "vi": true, "lo": true, "my": true, "km": true,
+}
+
+// PluralCategory returns plural category of the number in the locale,
+// covering the languages we are using, other languages follow english rules.
+func PluralCategory(locale string, n int64) string {
+	lang := baseLanguage(locale)
+	if n < 0 {
+		n = -n
+	}
+
+	switch {
+	case noPlural[lang]:
+		return "other"
+	case lang == "fr" || lang == "pt":
+		if n <= 1 {
+			return "one"
+		}
+	case lang == "ar":
+		switch {
+		case n == 0:
+			return "zero"
+		case n == 1:
+			return "one"
+		case n == 2:
+			return "two"
+		case n%100 >= 3 && n%100 <= 10:
+			return "few"
+		case n%100 >= 11:
+			return "many"
+		}
+	case lang == "ru" || lang == "uk":
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return "one"
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+	case n == 1:
+		return "one"
+	}
+
+	return "other"
+}
+
+var pluralCategories = map[string]bool{
+	"zero": true, "one": true, "two": true, "few": true, "many": true, "other": true,
+}
@@ -0,0 +1,77 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package mail
+
+import (
+	"fmt"
+
+	"github.com/enigma-id/go/env"
+)
+
+// Config represents all configurable mail data.
+var Config *config
+
+type config struct {
+	Driver         string // smtp, ses, mailgun or file
+	DevDir         string // directory of written emails for file driver
+	MailgunDomain  string
+	MailgunAPIKey  string
+	MailgunBaseURL string
+	SESRegion      string
+	SESAccessKey   string
+	SESSecretKey   string
+}
+
+// ReadEnv set all configurable data from env variable,
+// on development mode (APP_MODE=DEV) the default driver is file.
+func ReadEnv() {
+	driver := "smtp"
+	if env.GetString("APP_MODE", "") == "DEV" {
+		driver = "file"
+	}
+
+	Config = &config{
+		Driver:         env.GetString("MAIL_DRIVER", driver),
+		DevDir:         env.GetString("MAIL_DEV_DIR", "storage/mails"),
+		MailgunDomain:  env.GetString("MAILGUN_DOMAIN", ""),
+		MailgunAPIKey:  env.GetString("MAILGUN_API_KEY", ""),
+		MailgunBaseURL: env.GetString("MAILGUN_ENDPOINT", MailgunEndpoint),
+		SESRegion:      env.GetString("SES_REGION", env.GetString("AWS_REGION", "ap-southeast-1")),
+		SESAccessKey:   env.GetString("SES_ACCESS_KEY", env.GetString("AWS_ACCESS_KEY_ID", "")),
+		SESSecretKey:   env.GetString("SES_SECRET_KEY", env.GetString("AWS_SECRET_ACCESS_KEY", "")),
+	}
+}
+
+// NewDriver creates driver based on the configuration.
+func NewDriver() (Driver, error) {
+	switch Config.Driver {
+	case "smtp":
+		return NewSMTPDriver(), nil
+	case "file":
+		return NewFileDriver(Config.DevDir), nil
+	case "mailgun":
+		d := NewMailgunDriver(Config.MailgunDomain, Config.MailgunAPIKey)
+		d.Endpoint = Config.MailgunBaseURL
+		return d, nil
+	case "ses":
+		return NewSESDriver(Config.SESRegion, Config.SESAccessKey, Config.SESSecretKey), nil
+	}
+
+	return nil, fmt.Errorf("mail: unknown driver %q", Config.Driver)
+}
+
+// Default creates mailer using driver from the configuration.
+func Default(opts ...Option) (*Mailer, error) {
+	d, err := NewDriver()
+	if err != nil {
+		return nil, err
+	}
+
+	return New(d, opts...), nil
+}
+
+func init() {
+	ReadEnv()
+}
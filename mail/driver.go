@@ -0,0 +1,106 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/enigma-id/go/mailer"
+	"github.com/enigma-id/go/utility/log"
+)
+
+// SMTPDriver deliver the email through smtp server using mailer.Dialer,
+// connection is opened for each message sent.
+type SMTPDriver struct {
+	Dialer *mailer.Dialer
+}
+
+// NewSMTPDriver creates smtp driver using SMTP_* configurations.
+func NewSMTPDriver() *SMTPDriver {
+	return &SMTPDriver{Dialer: mailer.NewDialer()}
+}
+
+// Send implements Driver interfaces.
+func (d *SMTPDriver) Send(from string, to []string, msg io.WriterTo) error {
+	s, err := d.Dialer.Dial()
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	return s.Send(from, to, msg)
+}
+
+// FileDriver is the driver for development mode, instead of delivering
+// the email it writes the raw message as .eml file into the directory.
+type FileDriver struct {
+	Dir string
+}
+
+// NewFileDriver creates file driver that writes the emails into dir.
+func NewFileDriver(dir string) *FileDriver {
+	return &FileDriver{Dir: dir}
+}
+
+// Send implements Driver interfaces.
+func (d *FileDriver) Send(from string, to []string, msg io.WriterTo) error {
+	if err := os.MkdirAll(d.Dir, 0755); err != nil {
+		return err
+	}
+
+	b := make([]byte, 4)
+	rand.Read(b)
+
+	fn := filepath.Join(d.Dir, fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405"), hex.EncodeToString(b)))
+	f, err := os.Create(fn)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err = msg.WriteTo(f); err == nil {
+		log.Infof("mail: message from %s to %v written into %s", from, to, fn)
+	}
+
+	return err
+}
+
+// raw renders the message into bytes, used by the api drivers.
+func raw(msg io.WriterTo) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := msg.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// httpClient returns client or the default client with timeout.
+func httpClient(c *http.Client) *http.Client {
+	if c != nil {
+		return c
+	}
+
+	return &http.Client{Timeout: 30 * time.Second}
+}
+
+// checkResponse returns error when the api responded non 2xx status.
+func checkResponse(driver string, res *http.Response) error {
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return nil
+	}
+
+	b, _ := ioutil.ReadAll(io.LimitReader(res.Body, 1024))
+	return fmt.Errorf("mail: %s responded %d: %s", driver, res.StatusCode, bytes.TrimSpace(b))
+}
@@ -0,0 +1,73 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package mail
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+// MailgunEndpoint default api endpoint of mailgun,
+// use https://api.eu.mailgun.net for domains on eu region.
+const MailgunEndpoint = "https://api.mailgun.net"
+
+// MailgunDriver deliver the email using mailgun messages.mime api.
+type MailgunDriver struct {
+	Domain   string
+	APIKey   string
+	Endpoint string
+	Client   *http.Client
+}
+
+// NewMailgunDriver creates mailgun driver for the domain.
+func NewMailgunDriver(domain, apiKey string) *MailgunDriver {
+	return &MailgunDriver{
+		Domain:   domain,
+		APIKey:   apiKey,
+		Endpoint: MailgunEndpoint,
+	}
+}
+
+// Send implements Driver interfaces.
+func (d *MailgunDriver) Send(from string, to []string, msg io.WriterTo) error {
+	m, err := raw(msg)
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	w.WriteField("to", strings.Join(to, ","))
+
+	fw, err := w.CreateFormFile("message", "message.mime")
+	if err != nil {
+		return err
+	}
+	fw.Write(m)
+	w.Close()
+
+	endpoint := d.Endpoint
+	if endpoint == "" {
+		endpoint = MailgunEndpoint
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimRight(endpoint, "/")+"/v3/"+d.Domain+"/messages.mime", &body)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth("api", d.APIKey)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	res, err := httpClient(d.Client).Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	return checkResponse("mailgun", res)
+}
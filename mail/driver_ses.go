@@ -0,0 +1,83 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package mail
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/enigma-id/go/utility/sigv4"
+)
+
+// SESDriver deliver the email using amazon ses SendRawEmail api,
+// the request is signed with aws signature version 4.
+type SESDriver struct {
+	Region    string
+	AccessKey string
+	SecretKey string
+	Endpoint  string
+	Client    *http.Client
+
+	now func() time.Time
+}
+
+// NewSESDriver creates ses driver for the region.
+func NewSESDriver(region, accessKey, secretKey string) *SESDriver {
+	return &SESDriver{
+		Region:    region,
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+	}
+}
+
+// Send implements Driver interfaces.
+func (d *SESDriver) Send(from string, to []string, msg io.WriterTo) error {
+	m, err := raw(msg)
+	if err != nil {
+		return err
+	}
+
+	form := url.Values{}
+	form.Set("Action", "SendRawEmail")
+	form.Set("Version", "2010-12-01")
+	form.Set("Source", from)
+	form.Set("RawMessage.Data", base64.StdEncoding.EncodeToString(m))
+	for i, t := range to {
+		form.Set("Destinations.member."+strconv.Itoa(i+1), t)
+	}
+	body := form.Encode()
+
+	endpoint := d.Endpoint
+	if endpoint == "" {
+		endpoint = "https://email." + d.Region + ".amazonaws.com"
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimRight(endpoint, "/")+"/", strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	t := time.Now()
+	if d.now != nil {
+		t = d.now()
+	}
+
+	signer := &sigv4.Signer{AccessKey: d.AccessKey, SecretKey: d.SecretKey, Region: d.Region, Service: "ses"}
+	signer.Sign(req, sigv4.HashHex([]byte(body)), t)
+
+	res, err := httpClient(d.Client).Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	return checkResponse("ses", res)
+}
@@ -0,0 +1,252 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package mail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"path/filepath"
+
+	"github.com/enigma-id/go/mailer"
+)
+
+type (
+	// Driver is the backend that deliver the email, it has the same
+	// contract with mailer.Sender so any mailer.SendFunc can be used as driver.
+	Driver interface {
+		Send(from string, to []string, msg io.WriterTo) error
+	}
+
+	// Queue is the interface that wraps the Enqueue method, used when
+	// the email should be delivered asynchronously by a worker.
+	// The worker should call Mailer.HandleQueued with the payload.
+	Queue interface {
+		Enqueue(topic string, payload []byte) error
+	}
+
+	// Option configures the mailer instances.
+	Option func(*Mailer)
+
+	// Mailer sending the messages using the driver,
+	// optionaly render it from templates and send it through queue.
+	Mailer struct {
+		Driver    Driver
+		Templates *Templates
+		Queue     Queue
+		From      string
+	}
+
+	// Message represents an email that will be sent,
+	// the message is serializable so it can be passed into queue.
+	Message struct {
+		From        string              `json:"from,omitempty"`
+		To          []string            `json:"to"`
+		Cc          []string            `json:"cc,omitempty"`
+		Bcc         []string            `json:"bcc,omitempty"`
+		ReplyTo     string              `json:"reply_to,omitempty"`
+		Subject     string              `json:"subject"`
+		HTML        string              `json:"html,omitempty"`
+		Text        string              `json:"text,omitempty"`
+		Headers     map[string][]string `json:"headers,omitempty"`
+		Attachments []*Attachment       `json:"attachments,omitempty"`
+	}
+
+	// Attachment file that attached or embedded into message.
+	Attachment struct {
+		Name        string `json:"name"`
+		ContentType string `json:"content_type,omitempty"`
+		Content     []byte `json:"content"`
+		Inline      bool   `json:"inline,omitempty"`
+	}
+)
+
+// QueueTopic topic name used when enqueueing messages.
+const QueueTopic = "mail.deliver"
+
+var (
+	// ErrNoRecipient error when message doesn't have any recipient.
+	ErrNoRecipient = errors.New("mail: message has no recipient")
+	// ErrNoDriver error when mailer doesn't have any driver.
+	ErrNoDriver = errors.New("mail: driver is not configured")
+)
+
+// WithTemplates sets templates used by SendTemplate.
+func WithTemplates(t *Templates) Option {
+	return func(m *Mailer) {
+		m.Templates = t
+	}
+}
+
+// WithQueue makes the mailer enqueue the messages instead of
+// delivering it directly.
+func WithQueue(q Queue) Option {
+	return func(m *Mailer) {
+		m.Queue = q
+	}
+}
+
+// WithFrom sets default sender of the messages.
+func WithFrom(address, name string) Option {
+	return func(m *Mailer) {
+		m.From = mailer.NewMessage().FormatAddress(address, name)
+	}
+}
+
+// New creates new mailer instances with the driver.
+func New(d Driver, opts ...Option) *Mailer {
+	m := &Mailer{Driver: d}
+	for _, o := range opts {
+		o(m)
+	}
+
+	return m
+}
+
+// Send sends the message, when queue is configured
+// the message will be enqueued and delivered by the worker.
+func (m *Mailer) Send(msg *Message) error {
+	if len(msg.To)+len(msg.Cc)+len(msg.Bcc) == 0 {
+		return ErrNoRecipient
+	}
+
+	if m.Queue != nil {
+		b, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+
+		return m.Queue.Enqueue(QueueTopic, b)
+	}
+
+	return m.Deliver(msg)
+}
+
+// SendTemplate renders the template with data into the message then send it.
+func (m *Mailer) SendTemplate(name string, data interface{}, msg *Message) (err error) {
+	if err = m.Render(msg, name, data); err == nil {
+		err = m.Send(msg)
+	}
+
+	return
+}
+
+// Render fills the message body and subject from the template.
+func (m *Mailer) Render(msg *Message, name string, data interface{}) error {
+	if m.Templates == nil {
+		return errors.New("mail: templates is not configured")
+	}
+
+	return m.Templates.Render(msg, name, data)
+}
+
+// Deliver sends the message directly using the driver.
+func (m *Mailer) Deliver(msg *Message) error {
+	if m.Driver == nil {
+		return ErrNoDriver
+	}
+
+	mm, err := m.build(msg)
+	if err != nil {
+		return err
+	}
+
+	return mailer.Send(m.Driver, mm)
+}
+
+// HandleQueued decodes the queued payload and deliver the message,
+// this should be called by the queue worker.
+func (m *Mailer) HandleQueued(payload []byte) error {
+	msg := new(Message)
+	if err := json.Unmarshal(payload, msg); err != nil {
+		return fmt.Errorf("mail: invalid queued message: %v", err)
+	}
+
+	return m.Deliver(msg)
+}
+
+// build converts message into mailer.Message.
+func (m *Mailer) build(msg *Message) (*mailer.Message, error) {
+	mm := mailer.NewMessage()
+	if msg.From != "" {
+		mm.SetHeader("From", msg.From)
+	} else if m.From != "" {
+		mm.SetHeader("From", m.From)
+	}
+
+	mm.SetHeaders(msg.Headers)
+	if len(msg.To) > 0 {
+		mm.SetRecipient(msg.To...)
+	}
+	if len(msg.Cc) > 0 {
+		mm.SetHeader("Cc", msg.Cc...)
+	}
+	if len(msg.Bcc) > 0 {
+		mm.SetHeader("Bcc", msg.Bcc...)
+	}
+	if msg.ReplyTo != "" {
+		mm.SetHeader("Reply-To", msg.ReplyTo)
+	}
+	mm.SetSubject(msg.Subject)
+
+	switch {
+	case msg.Text != "" && msg.HTML != "":
+		mm.SetBody("text/plain", msg.Text)
+		mm.AddAlternative("text/html", msg.HTML)
+	case msg.HTML != "":
+		mm.SetBody("text/html", msg.HTML)
+	default:
+		mm.SetBody("text/plain", msg.Text)
+	}
+
+	for _, a := range msg.Attachments {
+		if a.Name == "" {
+			return nil, errors.New("mail: attachment name is required")
+		}
+
+		settings := []mailer.FileSetting{mailer.SetCopyFunc(a.copy)}
+		if a.ContentType != "" {
+			settings = append(settings, mailer.SetHeader(map[string][]string{"Content-Type": {a.ContentType}}))
+		}
+
+		if a.Inline {
+			mm.Embed(a.Name, settings...)
+		} else {
+			mm.Attach(a.Name, settings...)
+		}
+	}
+
+	return mm, nil
+}
+
+// Attach adds attachment from content.
+func (msg *Message) Attach(name string, content []byte) *Attachment {
+	a := &Attachment{
+		Name:        name,
+		ContentType: mime.TypeByExtension(filepath.Ext(name)),
+		Content:     content,
+	}
+	msg.Attachments = append(msg.Attachments, a)
+
+	return a
+}
+
+// AttachFile reads file from the path and adds it as attachment.
+func (msg *Message) AttachFile(path string) (*Attachment, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return msg.Attach(filepath.Base(path), b), nil
+}
+
+func (a *Attachment) copy(w io.Writer) error {
+	_, err := w.Write(a.Content)
+	return err
+}
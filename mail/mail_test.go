@@ -0,0 +1,149 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package mail
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enigma-id/go/mailer"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQueue struct {
+	topic   string
+	payload []byte
+}
+
+func (q *fakeQueue) Enqueue(topic string, payload []byte) error {
+	q.topic, q.payload = topic, payload
+	return nil
+}
+
+func capture(from *string, to *[]string, raw *bytes.Buffer) Driver {
+	return mailer.SendFunc(func(f string, t []string, msg io.WriterTo) error {
+		*from, *to = f, t
+		_, err := msg.WriteTo(raw)
+		return err
+	})
+}
+
+func TestTemplates(t *testing.T) {
+	tpl, err := NewTemplates("_fixture/templates", nil)
+	assert.NoError(t, err)
+
+	msg := &Message{}
+	data := map[string]string{"Name": "John", "Team": "Enigma"}
+	assert.NoError(t, tpl.Render(msg, "welcome", data))
+	assert.Equal(t, "Welcome John", msg.Subject)
+	assert.Equal(t, "<html><body><h1>Hello John</h1><p>Regards, Enigma</p></body></html>", msg.HTML)
+	assert.Equal(t, "Hello John\n", msg.Text)
+
+	assert.Error(t, tpl.Render(msg, "unknown", data))
+}
+
+func TestMailer_Send(t *testing.T) {
+	var from string
+	var to []string
+	var raw bytes.Buffer
+
+	m := New(capture(&from, &to, &raw), WithFrom("noreply@example.com", ""))
+	msg := &Message{To: []string{"to@example.com"}, Bcc: []string{"bcc@example.com"}, Subject: "Hi", Text: "Hello"}
+	msg.Attach("report.csv", []byte("a,b"))
+
+	assert.NoError(t, m.Send(msg))
+	assert.Equal(t, "noreply@example.com", from)
+	assert.Equal(t, []string{"to@example.com", "bcc@example.com"}, to)
+	assert.Contains(t, raw.String(), "Subject: Hi")
+	assert.Contains(t, raw.String(), `filename="report.csv"`)
+	assert.NotContains(t, raw.String(), "bcc@example.com")
+
+	assert.Equal(t, ErrNoRecipient, m.Send(&Message{}))
+	assert.Equal(t, ErrNoDriver, New(nil).Deliver(msg))
+}
+
+func TestMailer_Queue(t *testing.T) {
+	var from string
+	var to []string
+	var raw bytes.Buffer
+
+	q := &fakeQueue{}
+	tpl, _ := NewTemplates("_fixture/templates", nil)
+	m := New(capture(&from, &to, &raw), WithQueue(q), WithTemplates(tpl))
+
+	err := m.SendTemplate("welcome", map[string]string{"Name": "John"}, &Message{To: []string{"to@example.com"}})
+	assert.NoError(t, err)
+	assert.Equal(t, QueueTopic, q.topic)
+	assert.Empty(t, to)
+
+	assert.NoError(t, m.HandleQueued(q.payload))
+	assert.Equal(t, []string{"to@example.com"}, to)
+	assert.Contains(t, raw.String(), "Subject: Welcome John")
+	assert.Error(t, m.HandleQueued([]byte("invalid")))
+}
+
+func TestFileDriver(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "mail")
+	defer os.RemoveAll(dir)
+
+	m := New(NewFileDriver(dir))
+	assert.NoError(t, m.Send(&Message{To: []string{"to@example.com"}, Subject: "Dev", HTML: "<b>Hi</b>"}))
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if assert.Len(t, files, 1) {
+		b, _ := ioutil.ReadFile(files[0])
+		assert.Contains(t, string(b), "Subject: Dev")
+	}
+}
+
+func TestMailgunDriver(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "api" || pass != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		assert.Equal(t, "/v3/example.com/messages.mime", r.URL.Path)
+		assert.Equal(t, "to@example.com", r.FormValue("to"))
+
+		f, _, err := r.FormFile("message")
+		if assert.NoError(t, err) {
+			b, _ := ioutil.ReadAll(f)
+			assert.Contains(t, string(b), "Subject: Hi")
+		}
+	}))
+	defer srv.Close()
+
+	d := NewMailgunDriver("example.com", "key")
+	d.Endpoint = srv.URL
+	assert.NoError(t, New(d).Send(&Message{To: []string{"to@example.com"}, Subject: "Hi"}))
+
+	d.APIKey = "invalid"
+	assert.Error(t, New(d).Send(&Message{To: []string{"to@example.com"}}))
+}
+
+func TestSESDriver(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		assert.Equal(t, "SendRawEmail", r.Form.Get("Action"))
+		assert.Equal(t, "to@example.com", r.Form.Get("Destinations.member.1"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/20190301/ap-southeast-1/ses/aws4_request"))
+		assert.Equal(t, "20190301T000000Z", r.Header.Get("X-Amz-Date"))
+	}))
+	defer srv.Close()
+
+	d := NewSESDriver("ap-southeast-1", "AKID", "secret")
+	d.Endpoint = srv.URL
+	d.now = func() time.Time { return time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC) }
+	assert.NoError(t, New(d).Send(&Message{To: []string{"to@example.com"}, Subject: "Hi"}))
+}
@@ -0,0 +1,140 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	ttemplate "text/template"
+)
+
+// Templates collection of email templates loaded from directory
+// with the following structure:
+//
+//	templates/
+//	  layouts/*.html   layout, executed as "layout" or "layout.html"
+//	  partials/*.html  partials, available on every templates
+//	  welcome.html     html body, can define "subject" template
+//	  welcome.txt      optional plain text body
+//
+// Each html template should define "content" block
+// that will be rendered by the layout.
+type Templates struct {
+	html map[string]*template.Template
+	text map[string]*ttemplate.Template
+}
+
+// NewTemplates loads all templates inside the dir.
+func NewTemplates(dir string, funcs template.FuncMap) (*Templates, error) {
+	base := template.New("").Funcs(funcs)
+
+	for _, sub := range []string{"layouts", "partials"} {
+		files, err := filepath.Glob(filepath.Join(dir, sub, "*.html"))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(files) > 0 {
+			if base, err = base.ParseFiles(files...); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	t := &Templates{
+		html: make(map[string]*template.Template),
+		text: make(map[string]*ttemplate.Template),
+	}
+
+	pages, err := filepath.Glob(filepath.Join(dir, "*.html"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range pages {
+		c, err := base.Clone()
+		if err != nil {
+			return nil, err
+		}
+
+		if c, err = c.ParseFiles(p); err != nil {
+			return nil, err
+		}
+
+		t.html[name(p)] = c
+	}
+
+	texts, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range texts {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+
+		if t.text[name(p)], err = ttemplate.New(name(p)).Funcs(ttemplate.FuncMap(funcs)).Parse(string(b)); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// Render executes templates with the name and fills the message
+// html, text and subject (if defined and message subject is empty).
+func (t *Templates) Render(msg *Message, name string, data interface{}) error {
+	h, hok := t.html[name]
+	x, xok := t.text[name]
+	if !hok && !xok {
+		return fmt.Errorf("mail: template %q is not found", name)
+	}
+
+	var buf bytes.Buffer
+	if hok {
+		entry := name + ".html"
+		for _, l := range []string{"layout", "layout.html"} {
+			if h.Lookup(l) != nil {
+				entry = l
+				break
+			}
+		}
+
+		if err := h.ExecuteTemplate(&buf, entry, data); err != nil {
+			return err
+		}
+		msg.HTML = buf.String()
+
+		if s := h.Lookup("subject"); s != nil && msg.Subject == "" {
+			buf.Reset()
+			if err := s.Execute(&buf, data); err != nil {
+				return err
+			}
+			msg.Subject = strings.TrimSpace(buf.String())
+		}
+	}
+
+	if xok {
+		buf.Reset()
+		if err := x.Execute(&buf, data); err != nil {
+			return err
+		}
+		msg.Text = buf.String()
+	}
+
+	return nil
+}
+
+// name returns base name of file without extension.
+func name(path string) string {
+	b := filepath.Base(path)
+	return strings.TrimSuffix(b, filepath.Ext(b))
+}
@@ -0,0 +1,91 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/enigma-id/go/env"
+)
+
+// Config represents all configurable notify data.
+var Config *config
+
+type config struct {
+	Driver             string // log, twilio, zenziva, wablas or comma separated drivers
+	TwilioAccountSID   string
+	TwilioAuthToken    string
+	TwilioFrom         string
+	TwilioWhatsAppFrom string
+	ZenzivaUserKey     string
+	ZenzivaPassKey     string
+	WablasEndpoint     string
+	WablasToken        string
+}
+
+// ReadEnv set all configurable data from env variable,
+// on development mode (APP_MODE=DEV) the default driver is log.
+func ReadEnv() {
+	driver := "twilio"
+	if env.GetString("APP_MODE", "") == "DEV" {
+		driver = "log"
+	}
+
+	Config = &config{
+		Driver:             env.GetString("NOTIFY_DRIVER", driver),
+		TwilioAccountSID:   env.GetString("TWILIO_ACCOUNT_SID", ""),
+		TwilioAuthToken:    env.GetString("TWILIO_AUTH_TOKEN", ""),
+		TwilioFrom:         env.GetString("TWILIO_FROM", ""),
+		TwilioWhatsAppFrom: env.GetString("TWILIO_WHATSAPP_FROM", ""),
+		ZenzivaUserKey:     env.GetString("ZENZIVA_USER_KEY", ""),
+		ZenzivaPassKey:     env.GetString("ZENZIVA_PASS_KEY", ""),
+		WablasEndpoint:     env.GetString("WABLAS_ENDPOINT", ""),
+		WablasToken:        env.GetString("WABLAS_TOKEN", ""),
+	}
+}
+
+// NewDriver creates driver based on the configuration, multiple drivers
+// can be separated by comma, ex. "zenziva,wablas" sends sms through zenziva
+// and whatsapp through wablas.
+func NewDriver() (Driver, error) {
+	var m Multi
+	for _, name := range strings.Split(Config.Driver, ",") {
+		switch strings.TrimSpace(name) {
+		case "log":
+			m = append(m, NewLogDriver())
+		case "twilio":
+			d := NewTwilioDriver(Config.TwilioAccountSID, Config.TwilioAuthToken, Config.TwilioFrom)
+			d.WhatsAppFrom = Config.TwilioWhatsAppFrom
+			m = append(m, d)
+		case "zenziva":
+			m = append(m, NewZenzivaDriver(Config.ZenzivaUserKey, Config.ZenzivaPassKey))
+		case "wablas":
+			m = append(m, NewWablasDriver(Config.WablasEndpoint, Config.WablasToken))
+		default:
+			return nil, fmt.Errorf("notify: unknown driver %q", name)
+		}
+	}
+
+	if len(m) == 1 {
+		return m[0], nil
+	}
+
+	return m, nil
+}
+
+// Default creates notifier using driver from the configuration.
+func Default(opts ...Option) (*Notifier, error) {
+	d, err := NewDriver()
+	if err != nil {
+		return nil, err
+	}
+
+	return New(d, opts...), nil
+}
+
+func init() {
+	ReadEnv()
+}
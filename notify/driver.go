@@ -0,0 +1,89 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/enigma-id/go/utility/log"
+)
+
+// LogDriver is the driver for development mode, instead of delivering
+// the message it only writes the message into log.
+type LogDriver struct{}
+
+// NewLogDriver creates log driver.
+func NewLogDriver() *LogDriver {
+	return &LogDriver{}
+}
+
+// Send implements Driver interfaces.
+func (d *LogDriver) Send(msg *Message) error {
+	log.Infof("notify: %s to %s: %s", msg.Channel, msg.To, msg.Body)
+	return nil
+}
+
+// DriverFunc is an adapter to use function as Driver.
+type DriverFunc func(msg *Message) error
+
+// Send calls f(msg).
+func (f DriverFunc) Send(msg *Message) error {
+	return f(msg)
+}
+
+// Multi uses the first driver supporting the channel, ex. twilio for sms
+// and wablas for whatsapp.
+type Multi []Driver
+
+// Send implements Driver interfaces.
+func (m Multi) Send(msg *Message) error {
+	for _, d := range m {
+		if err := d.Send(msg); err != ErrUnsupportedChannel {
+			return err
+		}
+	}
+
+	return ErrUnsupportedChannel
+}
+
+func httpClient(c *http.Client) *http.Client {
+	if c != nil {
+		return c
+	}
+
+	return &http.Client{Timeout: 30 * time.Second}
+}
+
+// postForm posts the form and returns the response body,
+// non 2xx status is returned as error.
+func postForm(c *http.Client, u string, form url.Values, header http.Header) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodPost, u, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := httpClient(c).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	b, _ := ioutil.ReadAll(res.Body)
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("notify: %s returns %s: %s", req.URL.Host, res.Status, b)
+	}
+
+	return b, nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ZenzivaEndpoint is the default zenziva api endpoint.
+const ZenzivaEndpoint = "https://console.zenziva.net"
+
+// ZenzivaDriver sends sms and whatsapp message through zenziva,
+// the local indonesian gateway.
+type ZenzivaDriver struct {
+	UserKey  string
+	PassKey  string
+	Endpoint string
+	Client   *http.Client
+}
+
+// NewZenzivaDriver creates zenziva driver.
+func NewZenzivaDriver(userKey, passKey string) *ZenzivaDriver {
+	return &ZenzivaDriver{UserKey: userKey, PassKey: passKey, Endpoint: ZenzivaEndpoint}
+}
+
+// Send implements Driver interfaces.
+func (d *ZenzivaDriver) Send(msg *Message) error {
+	var path string
+	switch msg.Channel {
+	case SMS:
+		path = "/reguler/api/sendsms/"
+	case WhatsApp:
+		path = "/wareguler/api/sendWA/"
+	default:
+		return ErrUnsupportedChannel
+	}
+
+	b, err := postForm(d.Client, d.Endpoint+path, url.Values{
+		"userkey": {d.UserKey},
+		"passkey": {d.PassKey},
+		"to":      {localPhone(msg.To)},
+		"message": {msg.Body},
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	var res struct {
+		Status string `json:"status"`
+		Text   string `json:"text"`
+	}
+	if err = json.Unmarshal(b, &res); err != nil {
+		return fmt.Errorf("notify: invalid zenziva response: %s", b)
+	}
+
+	if res.Status != "1" {
+		return fmt.Errorf("notify: zenziva failed: %s", res.Text)
+	}
+
+	return nil
+}
+
+// WablasDriver sends whatsapp message through wablas,
+// the local indonesian whatsapp gateway.
+type WablasDriver struct {
+	Endpoint string // ex. https://solo.wablas.com
+	Token    string
+	Client   *http.Client
+}
+
+// NewWablasDriver creates wablas driver.
+func NewWablasDriver(endpoint, token string) *WablasDriver {
+	return &WablasDriver{Endpoint: strings.TrimRight(endpoint, "/"), Token: token}
+}
+
+// Send implements Driver interfaces.
+func (d *WablasDriver) Send(msg *Message) error {
+	if msg.Channel != WhatsApp {
+		return ErrUnsupportedChannel
+	}
+
+	b, err := postForm(d.Client, d.Endpoint+"/api/send-message", url.Values{
+		"phone":   {strings.TrimPrefix(msg.To, "+")},
+		"message": {msg.Body},
+	}, http.Header{"Authorization": {d.Token}})
+	if err != nil {
+		return err
+	}
+
+	var res struct {
+		Status  bool   `json:"status"`
+		Message string `json:"message"`
+	}
+	if err = json.Unmarshal(b, &res); err != nil {
+		return fmt.Errorf("notify: invalid wablas response: %s", b)
+	}
+
+	if !res.Status {
+		return fmt.Errorf("notify: wablas failed: %s", res.Message)
+	}
+
+	return nil
+}
+
+// localPhone converts +628xx into 08xx used by local gateways.
+func localPhone(phone string) string {
+	if strings.HasPrefix(phone, "+62") {
+		return "0" + phone[3:]
+	}
+
+	return strings.TrimPrefix(phone, "+")
+}
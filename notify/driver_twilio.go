@@ -0,0 +1,59 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+)
+
+// TwilioEndpoint is the default twilio api endpoint.
+const TwilioEndpoint = "https://api.twilio.com"
+
+// TwilioDriver sends sms and whatsapp message through twilio messages api.
+type TwilioDriver struct {
+	AccountSID   string
+	AuthToken    string
+	From         string // sender number or messaging service sid (MG...)
+	WhatsAppFrom string // whatsapp enabled sender number
+	Endpoint     string
+	Client       *http.Client
+}
+
+// NewTwilioDriver creates twilio driver.
+func NewTwilioDriver(sid, token, from string) *TwilioDriver {
+	return &TwilioDriver{AccountSID: sid, AuthToken: token, From: from, Endpoint: TwilioEndpoint}
+}
+
+// Send implements Driver interfaces.
+func (d *TwilioDriver) Send(msg *Message) error {
+	form := url.Values{"Body": {msg.Body}}
+
+	switch msg.Channel {
+	case SMS:
+		form.Set("To", msg.To)
+		if len(d.From) > 2 && d.From[:2] == "MG" {
+			form.Set("MessagingServiceSid", d.From)
+		} else {
+			form.Set("From", d.From)
+		}
+	case WhatsApp:
+		if d.WhatsAppFrom == "" {
+			return ErrUnsupportedChannel
+		}
+		form.Set("To", "whatsapp:"+msg.To)
+		form.Set("From", "whatsapp:"+d.WhatsAppFrom)
+	default:
+		return ErrUnsupportedChannel
+	}
+
+	u := d.Endpoint + "/2010-04-01/Accounts/" + url.PathEscape(d.AccountSID) + "/Messages.json"
+	auth := base64.StdEncoding.EncodeToString([]byte(d.AccountSID + ":" + d.AuthToken))
+
+	_, err := postForm(d.Client, u, form, http.Header{"Authorization": {"Basic " + auth}})
+
+	return err
+}
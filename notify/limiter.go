@@ -0,0 +1,63 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/enigma-id/go/cache"
+)
+
+// ErrRateLimited returned when the recipient exceeds the rate limit.
+var ErrRateLimited = errors.New("notify: too many messages to the recipient")
+
+// Limiter limits number of messages of each recipient in the window,
+// the counter is kept in the cache so it is shared between instances.
+type Limiter struct {
+	Cache  cache.Cache
+	Max    int
+	Window time.Duration
+	Prefix string
+
+	now func() time.Time
+}
+
+// NewLimiter creates limiter using the cache, ex. 5 messages in 10 minutes.
+func NewLimiter(c cache.Cache, max int, window time.Duration) *Limiter {
+	return &Limiter{Cache: c, Max: max, Window: window, Prefix: "notify:limit:"}
+}
+
+// Allow increments counter of the recipient, ErrRateLimited is returned
+// when the counter exceeds the maximum.
+func (l *Limiter) Allow(to string) error {
+	now := time.Now
+	if l.now != nil {
+		now = l.now
+	}
+
+	w := now().UnixNano() / int64(l.Window)
+	key := fmt.Sprintf("%s%s:%d", l.Prefix, to, w)
+
+	// the counter is started using Add, so concurrent first messages
+	// doesn't reset each other, the increment itself is best effort.
+	if err := l.Cache.Add(key, 1, l.Window); err == nil {
+		return nil
+	} else if err != cache.ErrNotStored {
+		return err
+	}
+
+	var n int
+	if err := l.Cache.Get(key, &n); err != nil && err != cache.ErrCacheMiss {
+		return err
+	}
+
+	if n >= l.Max {
+		return ErrRateLimited
+	}
+
+	return l.Cache.Set(key, n+1, l.Window)
+}
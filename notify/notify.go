@@ -0,0 +1,191 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/enigma-id/go/utility"
+)
+
+// Channels of the message.
+const (
+	SMS      = "sms"
+	WhatsApp = "whatsapp"
+)
+
+// QueueTopic topic name used when enqueueing messages.
+const QueueTopic = "notify.deliver"
+
+var (
+	// ErrNoRecipient error when message doesn't have valid recipient.
+	ErrNoRecipient = errors.New("notify: message has no valid recipient")
+	// ErrNoDriver error when notifier doesn't have any driver.
+	ErrNoDriver = errors.New("notify: driver is not configured")
+	// ErrUnsupportedChannel returned by driver that doesn't support the channel.
+	ErrUnsupportedChannel = errors.New("notify: channel is not supported by the driver")
+)
+
+type (
+	// Driver is the gateway that deliver the message.
+	Driver interface {
+		Send(msg *Message) error
+	}
+
+	// Queue is the interface that wraps the Enqueue method, used when
+	// the message should be delivered asynchronously by a worker.
+	// The worker should call Notifier.HandleQueued with the payload.
+	Queue interface {
+		Enqueue(topic string, payload []byte) error
+	}
+
+	// Option configures the notifier instances.
+	Option func(*Notifier)
+
+	// Notifier sending the messages using the driver, optionaly rendered
+	// from templates, rate limited and sent through queue.
+	Notifier struct {
+		Driver    Driver
+		Templates *Templates
+		Queue     Queue
+		Limiter   *Limiter
+	}
+
+	// Message represents sms or whatsapp message that will be sent,
+	// the message is serializable so it can be passed into queue.
+	Message struct {
+		Channel  string                 `json:"channel"`
+		To       string                 `json:"to"`
+		Body     string                 `json:"body"`
+		Template string                 `json:"template,omitempty"`
+		Data     map[string]interface{} `json:"data,omitempty"`
+	}
+)
+
+// WithTemplates sets templates of the messages.
+func WithTemplates(t *Templates) Option {
+	return func(n *Notifier) {
+		n.Templates = t
+	}
+}
+
+// WithQueue makes the notifier enqueue the messages instead of
+// delivering it directly.
+func WithQueue(q Queue) Option {
+	return func(n *Notifier) {
+		n.Queue = q
+	}
+}
+
+// WithLimiter sets rate limiter of each recipient.
+func WithLimiter(l *Limiter) Option {
+	return func(n *Notifier) {
+		n.Limiter = l
+	}
+}
+
+// New creates new notifier instances with the driver.
+func New(d Driver, opts ...Option) *Notifier {
+	n := &Notifier{Driver: d}
+	for _, o := range opts {
+		o(n)
+	}
+
+	return n
+}
+
+// Send renders the template when the body is empty, checks the rate limit
+// of the recipient then send the message, when queue is configured
+// the message will be enqueued and delivered by the worker.
+func (n *Notifier) Send(msg *Message) (err error) {
+	if msg.To = NormalizePhone(msg.To); msg.To == "" {
+		return ErrNoRecipient
+	}
+
+	if msg.Channel == "" {
+		msg.Channel = SMS
+	}
+
+	if msg.Body == "" && msg.Template != "" {
+		if n.Templates == nil {
+			return errors.New("notify: templates is not configured")
+		}
+
+		if msg.Body, err = n.Templates.Render(msg.Template, msg.Channel, msg.Data); err != nil {
+			return err
+		}
+	}
+
+	if n.Limiter != nil {
+		if err = n.Limiter.Allow(msg.To); err != nil {
+			return err
+		}
+	}
+
+	if n.Queue != nil {
+		b, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+
+		return n.Queue.Enqueue(QueueTopic, b)
+	}
+
+	return n.Deliver(msg)
+}
+
+// SendTemplate sends message of the channel rendered from the template.
+func (n *Notifier) SendTemplate(channel, to, name string, data map[string]interface{}) error {
+	return n.Send(&Message{Channel: channel, To: to, Template: name, Data: data})
+}
+
+// Deliver sends the message directly using the driver.
+func (n *Notifier) Deliver(msg *Message) error {
+	if n.Driver == nil {
+		return ErrNoDriver
+	}
+
+	return n.Driver.Send(msg)
+}
+
+// HandleQueued decodes the queued payload and deliver the message,
+// this should be called by the queue worker.
+func (n *Notifier) HandleQueued(payload []byte) error {
+	msg := new(Message)
+	if err := json.Unmarshal(payload, msg); err != nil {
+		return fmt.Errorf("notify: invalid queued message: %v", err)
+	}
+
+	return n.Deliver(msg)
+}
+
+// NormalizePhone formats phone number into E.164 format,
+// local indonesian number (08xx) is converted into +628xx.
+func NormalizePhone(phone string) string {
+	phone = strings.TrimSpace(phone)
+	if strings.HasPrefix(phone, "+") {
+		var b strings.Builder
+		for _, r := range phone[1:] {
+			if r >= '0' && r <= '9' {
+				b.WriteRune(r)
+			}
+		}
+
+		if b.Len() < 8 || b.Len() > 15 {
+			return ""
+		}
+
+		return "+" + b.String()
+	}
+
+	if p := utility.FormatPhone(phone); p != "" {
+		return "+" + p
+	}
+
+	return ""
+}
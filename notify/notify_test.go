@@ -0,0 +1,115 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/enigma-id/go/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQueue struct {
+	topic   string
+	payload []byte
+}
+
+func (q *fakeQueue) Enqueue(topic string, payload []byte) error {
+	q.topic, q.payload = topic, payload
+	return nil
+}
+
+func TestSend(t *testing.T) {
+	tpl, err := NewTemplates("_fixture/templates", nil)
+	assert.NoError(t, err)
+
+	var sent []*Message
+	n := New(DriverFunc(func(msg *Message) error {
+		sent = append(sent, msg)
+		return nil
+	}), WithTemplates(tpl), WithLimiter(NewLimiter(cache.NewMemory(), 2, time.Minute)))
+
+	data := map[string]interface{}{"code": "1234", "minutes": 5}
+	assert.NoError(t, n.SendTemplate(SMS, "0812-3456-7890", "otp", data))
+	assert.NoError(t, n.SendTemplate(WhatsApp, "+62 812 3456 7890", "otp", data))
+	assert.Equal(t, ErrRateLimited, n.Send(&Message{To: "081234567890", Body: "hi"}))
+	assert.Equal(t, ErrNoRecipient, n.Send(&Message{To: "123", Body: "hi"}))
+
+	if assert.Len(t, sent, 2) {
+		assert.Equal(t, "+6281234567890", sent[0].To)
+		assert.Equal(t, "Kode OTP Anda 1234, berlaku 5 menit.", sent[0].Body)
+		assert.Equal(t, "*1234* adalah kode OTP Anda.", sent[1].Body)
+	}
+
+	q := new(fakeQueue)
+	n = New(nil, WithQueue(q))
+	assert.NoError(t, n.Send(&Message{To: "+14155552671", Body: "hello"}))
+	assert.Equal(t, QueueTopic, q.topic)
+
+	n.Driver = DriverFunc(func(msg *Message) error {
+		sent = append(sent, msg)
+		return nil
+	})
+	assert.NoError(t, n.HandleQueued(q.payload))
+	assert.Equal(t, &Message{Channel: SMS, To: "+14155552671", Body: "hello"}, sent[2])
+}
+
+func TestDrivers(t *testing.T) {
+	var form map[string]string
+	var auth string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = map[string]string{"path": r.URL.Path}
+		for k := range r.PostForm {
+			form[k] = r.PostForm.Get(k)
+		}
+		auth = r.Header.Get("Authorization")
+
+		switch r.URL.Path {
+		case "/api/send-message":
+			w.Write([]byte(`{"status":true}`))
+		case "/reguler/api/sendsms/":
+			w.Write([]byte(`{"status":"1","text":"Success"}`))
+		case "/wareguler/api/sendWA/":
+			w.Write([]byte(`{"status":"0","text":"Invalid number"}`))
+		default:
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer s.Close()
+
+	msg := &Message{Channel: SMS, To: "+6281234567890", Body: "hi"}
+
+	tw := NewTwilioDriver("AC1", "token", "+15005550006")
+	tw.Endpoint = s.URL
+	assert.NoError(t, tw.Send(msg))
+	assert.Equal(t, "/2010-04-01/Accounts/AC1/Messages.json", form["path"])
+	assert.Equal(t, "+15005550006", form["From"])
+	assert.Equal(t, "Basic QUMxOnRva2Vu", auth)
+	assert.Equal(t, ErrUnsupportedChannel, tw.Send(&Message{Channel: WhatsApp, To: msg.To}))
+
+	zz := NewZenzivaDriver("user", "pass")
+	zz.Endpoint = s.URL
+	assert.NoError(t, zz.Send(msg))
+	assert.Equal(t, "081234567890", form["to"])
+	assert.EqualError(t, zz.Send(&Message{Channel: WhatsApp, To: msg.To}), "notify: zenziva failed: Invalid number")
+
+	wb := NewWablasDriver(s.URL+"/", "secret")
+	d := Multi{wb, tw}
+	assert.NoError(t, d.Send(&Message{Channel: WhatsApp, To: msg.To, Body: "hi"}))
+	assert.Equal(t, "6281234567890", form["phone"])
+	assert.Equal(t, "secret", auth)
+
+	assert.NoError(t, d.Send(msg))
+	assert.Equal(t, "/2010-04-01/Accounts/AC1/Messages.json", form["path"])
+
+	Config.Driver = "log"
+	dd, err := NewDriver()
+	assert.NoError(t, err)
+	assert.IsType(t, new(LogDriver), dd)
+}
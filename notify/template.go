@@ -0,0 +1,70 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Templates of the messages, a template can have version per channel,
+// ex. "otp.whatsapp" is used for whatsapp and "otp" for the others.
+type Templates struct {
+	t *template.Template
+}
+
+// NewTemplates loads *.txt files in the directory, template name
+// is the file name without extension, ex. otp.txt and otp.whatsapp.txt.
+func NewTemplates(dir string, funcs template.FuncMap) (*Templates, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]string)
+	for _, f := range files {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+
+		m[strings.TrimSuffix(filepath.Base(f), ".txt")] = string(b)
+	}
+
+	return ParseTemplates(m, funcs)
+}
+
+// ParseTemplates parses templates from map of name and text.
+func ParseTemplates(m map[string]string, funcs template.FuncMap) (*Templates, error) {
+	t := template.New("").Funcs(funcs)
+	for name, text := range m {
+		if _, err := t.New(name).Parse(text); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Templates{t: t}, nil
+}
+
+// Render executes the template of the channel with the data.
+func (t *Templates) Render(name, channel string, data interface{}) (string, error) {
+	tpl := t.t.Lookup(name + "." + channel)
+	if tpl == nil {
+		if tpl = t.t.Lookup(name); tpl == nil {
+			return "", fmt.Errorf("notify: template %s is not found", name)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
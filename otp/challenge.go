@@ -0,0 +1,69 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// ErrChallenge returned when the challenge token is invalid or expired.
+var ErrChallenge = errors.New("otp: invalid challenge token")
+
+// Challenge issues short lived token of two factor login, returned after
+// the password is verified and exchanged for the login token after the
+// otp is verified:
+//
+//	// POST /login
+//	token, _ := otp.Challenge(rest.JwtKey(), user.ID, 5*time.Minute)
+//	return c.JSON(http.StatusOK, rest.Map{"challenge": token})
+//
+//	// POST /login/otp
+//	sub, err := otp.ParseChallenge(rest.JwtKey(), r.Challenge)
+//	... verify the code of the sub
+//	return c.JSON(http.StatusOK, rest.Map{"token": rest.JwtToken("id", sub)})
+//
+// The token is signed by key derived from the jwt key,
+// so it is never accepted as login token by the JWT middleware.
+func Challenge(key []byte, sub interface{}, ttl time.Duration) (string, error) {
+	claims := jwt.MapClaims{
+		"sub": sub,
+		"mfa": "pending",
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(ttl).Unix(),
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(challengeKey(key))
+}
+
+// ParseChallenge validates the challenge token and returns the subject.
+func ParseChallenge(key []byte, token string) (interface{}, error) {
+	t, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
+		if t.Method != jwt.SigningMethodHS256 {
+			return nil, ErrChallenge
+		}
+		return challengeKey(key), nil
+	})
+	if err != nil || !t.Valid {
+		return nil, ErrChallenge
+	}
+
+	claims := t.Claims.(jwt.MapClaims)
+	if claims["mfa"] != "pending" {
+		return nil, ErrChallenge
+	}
+
+	return claims["sub"], nil
+}
+
+func challengeKey(key []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte("otp.challenge"))
+	return h.Sum(nil)
+}
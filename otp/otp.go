@@ -0,0 +1,272 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package otp
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/enigma-id/go/cache"
+	"github.com/enigma-id/go/notify"
+)
+
+var (
+	// ErrInvalid returned when the code doesn't match.
+	ErrInvalid = errors.New("otp: invalid code")
+	// ErrExpired returned when there is no active code of the recipient.
+	ErrExpired = errors.New("otp: code is expired")
+	// ErrTooManyAttempts returned when verification exceeds max attempts,
+	// the code is discarded and new one should be requested.
+	ErrTooManyAttempts = errors.New("otp: too many attempts")
+	// ErrCooldown returned when new code is requested before the cooldown ends.
+	ErrCooldown = errors.New("otp: please wait before requesting new code")
+	// ErrRateLimited returned when the recipient requests too many codes.
+	ErrRateLimited = errors.New("otp: too many code requested")
+)
+
+type (
+	// Option configures the OTP instances.
+	Option func(*OTP)
+
+	// OTP generates and verifies numeric one time password, the hashed code
+	// is kept in the cache so it is shared between instances.
+	OTP struct {
+		Cache       cache.Cache
+		Length      int
+		TTL         time.Duration
+		MaxAttempts int
+		Cooldown    time.Duration
+		Limiter     *notify.Limiter
+		Notifier    *notify.Notifier
+		Channel     string
+		Template    string
+		Prefix      string
+
+		now func() time.Time
+	}
+
+	// Entry is the stored code of the recipient.
+	Entry struct {
+		Hash    string
+		Expires int64
+	}
+)
+
+// WithLength sets number of digits, default 6.
+func WithLength(n int) Option {
+	return func(o *OTP) {
+		o.Length = n
+	}
+}
+
+// WithTTL sets lifetime of the code, default 5 minutes.
+func WithTTL(d time.Duration) Option {
+	return func(o *OTP) {
+		o.TTL = d
+	}
+}
+
+// WithMaxAttempts sets max wrong attempts before the code discarded, default 5.
+func WithMaxAttempts(n int) Option {
+	return func(o *OTP) {
+		o.MaxAttempts = n
+	}
+}
+
+// WithCooldown sets minimum duration between resend, default 1 minute.
+func WithCooldown(d time.Duration) Option {
+	return func(o *OTP) {
+		o.Cooldown = d
+	}
+}
+
+// WithLimit limits number of code generated of each recipient in the window,
+// ex. WithLimit(5, time.Hour).
+func WithLimit(max int, window time.Duration) Option {
+	return func(o *OTP) {
+		o.Limiter = notify.NewLimiter(o.Cache, max, window)
+		o.Limiter.Prefix = o.Prefix + "limit:"
+	}
+}
+
+// WithNotifier sets notifier used by Send, the template receives
+// "code" and "minutes" data, default channel sms and template "otp".
+func WithNotifier(n *notify.Notifier, channel, template string) Option {
+	return func(o *OTP) {
+		o.Notifier = n
+		o.Channel = channel
+		o.Template = template
+	}
+}
+
+// New creates OTP instances using the cache, the cache must be
+// cache.Counter, ex. cache.RedisCache, to count the attempts.
+func New(c cache.Cache, opts ...Option) *OTP {
+	o := &OTP{
+		Cache:       c,
+		Length:      6,
+		TTL:         5 * time.Minute,
+		MaxAttempts: 5,
+		Cooldown:    time.Minute,
+		Channel:     notify.SMS,
+		Template:    "otp",
+		Prefix:      "otp:",
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// Generate creates new code of the recipient for the purpose, ex. "login"
+// or "reset-password", previous code of the same purpose is replaced.
+func (o *OTP) Generate(purpose, to string) (string, error) {
+	now := o.clock()
+
+	// cooldown is started using Add, so concurrent requests
+	// can't bypass it each other.
+	if err := o.Cache.Add(o.key("cooldown", purpose, to), now.Add(o.Cooldown).UnixNano(), o.Cooldown); err == cache.ErrNotStored {
+		return "", ErrCooldown
+	} else if err != nil {
+		return "", err
+	}
+
+	if o.Limiter != nil {
+		if err := o.Limiter.Allow(purpose + ":" + to); err == notify.ErrRateLimited {
+			return "", ErrRateLimited
+		} else if err != nil {
+			return "", err
+		}
+	}
+
+	code, err := Digits(o.Length)
+	if err != nil {
+		return "", err
+	}
+
+	e := Entry{Hash: hash(purpose, to, code), Expires: now.Add(o.TTL).UnixNano()}
+	if err = o.Cache.Set(o.key("code", purpose, to), e, o.TTL); err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+// Send generates the code and send it to the recipient using the notifier.
+func (o *OTP) Send(purpose, to string) error {
+	if o.Notifier == nil {
+		return errors.New("otp: notifier is not configured")
+	}
+
+	code, err := o.Generate(purpose, to)
+	if err != nil {
+		return err
+	}
+
+	return o.Notifier.SendTemplate(o.Channel, to, o.Template, map[string]interface{}{
+		"code":    code,
+		"purpose": purpose,
+		"minutes": int(o.TTL.Minutes()),
+	})
+}
+
+// Verify checks the code of the recipient, the code can only be used once.
+// The attempts are counted by the atomic increment of the cache, so the
+// parallel guesses can't exceed MaxAttempts.
+func (o *OTP) Verify(purpose, to, code string) error {
+	key := o.key("code", purpose, to)
+
+	var e Entry
+	if err := o.Cache.Get(key, &e); err == cache.ErrCacheMiss {
+		return ErrExpired
+	} else if err != nil {
+		return err
+	}
+
+	now := o.clock().UnixNano()
+	if e.Expires <= now {
+		o.Cache.Delete(key)
+		return ErrExpired
+	}
+
+	// the counter belongs to the code, new code starts from zero
+	n, err := cache.Increment(o.Cache, key+":attempts:"+e.Hash[:16], 1, time.Duration(e.Expires-now))
+	if err != nil {
+		return err
+	}
+	if int(n) > o.MaxAttempts {
+		return ErrTooManyAttempts
+	}
+
+	if subtle.ConstantTimeCompare([]byte(e.Hash), []byte(hash(purpose, to, code))) == 1 {
+		// only the caller deleting the code uses it
+		if err = o.Cache.Delete(key); err == cache.ErrCacheMiss {
+			return ErrExpired
+		} else if err != nil {
+			return err
+		}
+
+		o.Cache.Delete(o.key("cooldown", purpose, to))
+		return nil
+	}
+
+	if int(n) >= o.MaxAttempts {
+		o.Cache.Delete(key)
+		return ErrTooManyAttempts
+	}
+
+	return ErrInvalid
+}
+
+// Wait returns remaining cooldown of the recipient before new code can be requested.
+func (o *OTP) Wait(purpose, to string) time.Duration {
+	var until int64
+	if err := o.Cache.Get(o.key("cooldown", purpose, to), &until); err != nil {
+		return 0
+	}
+
+	if d := time.Duration(until - o.clock().UnixNano()); d > 0 {
+		return d
+	}
+
+	return 0
+}
+
+func (o *OTP) key(kind, purpose, to string) string {
+	return o.Prefix + kind + ":" + purpose + ":" + to
+}
+
+func (o *OTP) clock() time.Time {
+	if o.now != nil {
+		return o.now()
+	}
+
+	return time.Now()
+}
+
+// Digits returns n random digits using crypto/rand.
+func Digits(n int) (string, error) {
+	b := make([]byte, n)
+	for i := range b {
+		x, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		b[i] = byte('0' + x.Int64())
+	}
+
+	return string(b), nil
+}
+
+func hash(purpose, to, code string) string {
+	h := sha256.Sum256([]byte(purpose + "\x00" + to + "\x00" + code))
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,140 @@
+package otp
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/enigma-id/go/cache"
+	"github.com/enigma-id/go/notify"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateVerify(t *testing.T) {
+	o := New(cache.NewMemory(), WithLength(4))
+
+	code, err := o.Generate("login", "+628123456789")
+	assert.NoError(t, err)
+	assert.Len(t, code, 4)
+
+	assert.Equal(t, ErrInvalid, o.Verify("login", "+628123456789", "xxxx"))
+	assert.Equal(t, ErrExpired, o.Verify("reset", "+628123456789", code))
+	assert.NoError(t, o.Verify("login", "+628123456789", code))
+
+	// used once
+	assert.Equal(t, ErrExpired, o.Verify("login", "+628123456789", code))
+}
+
+func TestCooldown(t *testing.T) {
+	now := time.Now()
+	o := New(cache.NewMemory(), WithCooldown(time.Minute))
+	o.now = func() time.Time { return now }
+
+	_, err := o.Generate("login", "budi")
+	assert.NoError(t, err)
+
+	_, err = o.Generate("login", "budi")
+	assert.Equal(t, ErrCooldown, err)
+
+	now = now.Add(15 * time.Second)
+	assert.Equal(t, 45*time.Second, o.Wait("login", "budi"))
+	assert.Equal(t, time.Duration(0), o.Wait("login", "andi"))
+}
+
+func TestMaxAttempts(t *testing.T) {
+	o := New(cache.NewMemory(), WithMaxAttempts(3))
+
+	code, _ := o.Generate("login", "budi")
+	assert.Equal(t, ErrInvalid, o.Verify("login", "budi", "000000x"))
+	assert.Equal(t, ErrInvalid, o.Verify("login", "budi", "000000x"))
+	assert.Equal(t, ErrTooManyAttempts, o.Verify("login", "budi", "000000x"))
+	assert.Equal(t, ErrExpired, o.Verify("login", "budi", code))
+}
+
+func TestVerifyConcurrent(t *testing.T) {
+	o := New(cache.NewMemory(), WithMaxAttempts(3))
+	code, _ := o.Generate("login", "budi")
+
+	verify := func(n int, code string) map[error]int {
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		res := make(map[error]int)
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := o.Verify("login", "budi", code)
+				mu.Lock()
+				res[err]++
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+		return res
+	}
+
+	// parallel guesses are limited by the attempts
+	res := verify(20, "000000x")
+	assert.Equal(t, 2, res[ErrInvalid])
+	assert.Equal(t, 0, res[nil])
+	assert.Equal(t, ErrExpired, o.Verify("login", "budi", code))
+
+	// the code is used once
+	o.Cache.Delete(o.key("cooldown", "login", "budi"))
+	code, _ = o.Generate("login", "budi")
+	o.MaxAttempts = 100
+	res = verify(20, code)
+	assert.Equal(t, 1, res[nil])
+	assert.Equal(t, 19, res[ErrExpired])
+}
+
+func TestExpired(t *testing.T) {
+	now := time.Now()
+	o := New(cache.NewMemory(), WithTTL(time.Minute))
+	o.now = func() time.Time { return now }
+
+	code, _ := o.Generate("login", "budi")
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, ErrExpired, o.Verify("login", "budi", code))
+}
+
+func TestRateLimit(t *testing.T) {
+	o := New(cache.NewMemory(), WithLimit(2, time.Hour))
+
+	var errs []error
+	for i := 0; i < 3; i++ {
+		_, err := o.Generate("login", "budi")
+		o.Cache.Delete(o.key("cooldown", "login", "budi"))
+		errs = append(errs, err)
+	}
+
+	assert.Equal(t, []error{nil, nil, ErrRateLimited}, errs)
+}
+
+func TestSend(t *testing.T) {
+	var sent *notify.Message
+	tpl, _ := notify.ParseTemplates(map[string]string{"otp": "Kode {{.code}} berlaku {{.minutes}} menit"}, nil)
+	n := notify.New(notify.DriverFunc(func(m *notify.Message) error {
+		sent = m
+		return nil
+	}), notify.WithTemplates(tpl))
+
+	o := New(cache.NewMemory(), WithNotifier(n, notify.WhatsApp, "otp"))
+	assert.NoError(t, o.Send("login", "081234567890"))
+
+	if assert.NotNil(t, sent) {
+		assert.Equal(t, notify.WhatsApp, sent.Channel)
+		assert.True(t, strings.HasSuffix(sent.Body, "berlaku 5 menit"))
+		assert.NoError(t, o.Verify("login", "081234567890", sent.Data["code"].(string)))
+	}
+
+	assert.Error(t, New(cache.NewMemory()).Send("login", "081234567890"))
+}
+
+func TestDigits(t *testing.T) {
+	d, err := Digits(8)
+	assert.NoError(t, err)
+	assert.Len(t, d, 8)
+	assert.Equal(t, "", strings.Trim(d, "0123456789"))
+}
@@ -0,0 +1,168 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/enigma-id/go/cache"
+)
+
+// ErrReplayed returned when totp code has been used.
+var ErrReplayed = errors.New("otp: code has been used")
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTP is time based one time password of RFC 6238, compatible
+// with google authenticator and the likes (SHA1, 6 digits, 30 seconds).
+type TOTP struct {
+	Secret  string
+	Issuer  string
+	Account string
+	Digits  int
+	Period  int
+
+	// Skew is number of periods accepted before and after current time.
+	Skew int
+}
+
+// NewTOTP creates TOTP with new random secret for the account.
+func NewTOTP(issuer, account string) (*TOTP, error) {
+	s, err := GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	return &TOTP{Secret: s, Issuer: issuer, Account: account, Digits: 6, Period: 30, Skew: 1}, nil
+}
+
+// GenerateSecret returns random 160 bits secret encoded in base32.
+func GenerateSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return encoding.EncodeToString(b), nil
+}
+
+// URI returns otpauth uri of the provisioning, render it as qr code
+// so it can be scanned by authenticator app.
+func (t *TOTP) URI() string {
+	q := url.Values{}
+	q.Set("secret", t.Secret)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", fmt.Sprint(t.digits()))
+	q.Set("period", fmt.Sprint(t.period()))
+
+	label := url.PathEscape(t.Account)
+	if t.Issuer != "" {
+		q.Set("issuer", t.Issuer)
+		label = url.PathEscape(t.Issuer) + ":" + label
+	}
+
+	return "otpauth://totp/" + label + "?" + q.Encode()
+}
+
+// Code returns the code at the time.
+func (t *TOTP) Code(at time.Time) (string, error) {
+	key, err := t.key()
+	if err != nil {
+		return "", err
+	}
+
+	return HOTP(key, uint64(at.Unix())/uint64(t.period()), t.digits()), nil
+}
+
+// Validate checks the code at the time within the skew.
+func (t *TOTP) Validate(code string, at time.Time) bool {
+	_, ok := t.step(code, at)
+	return ok
+}
+
+// step returns counter of the matching code.
+func (t *TOTP) step(code string, at time.Time) (uint64, bool) {
+	key, err := t.key()
+	if err != nil || len(code) != t.digits() {
+		return 0, false
+	}
+
+	c := uint64(at.Unix()) / uint64(t.period())
+	for i := -t.Skew; i <= t.Skew; i++ {
+		n := c + uint64(i)
+		if subtle.ConstantTimeCompare([]byte(HOTP(key, n, t.digits())), []byte(code)) == 1 {
+			return n, true
+		}
+	}
+
+	return 0, false
+}
+
+func (t *TOTP) key() ([]byte, error) {
+	s := strings.ToUpper(strings.Replace(t.Secret, " ", "", -1))
+	return encoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+func (t *TOTP) digits() int {
+	if t.Digits <= 0 {
+		return 6
+	}
+	return t.Digits
+}
+
+func (t *TOTP) period() int {
+	if t.Period <= 0 {
+		return 30
+	}
+	return t.Period
+}
+
+// VerifyTOTP validates the totp code and make sure the same code can't be
+// used twice by keeping the used counter in the cache.
+func (o *OTP) VerifyTOTP(t *TOTP, code string) error {
+	n, ok := t.step(code, o.clock())
+	if !ok {
+		return ErrInvalid
+	}
+
+	ttl := time.Duration(t.period()*(2*t.Skew+1)) * time.Second
+	if err := o.Cache.Add(fmt.Sprintf("%stotp:%s:%d", o.Prefix, t.Account, n), 1, ttl); err == cache.ErrNotStored {
+		return ErrReplayed
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// HOTP returns counter based one time password of RFC 4226.
+func HOTP(key []byte, counter uint64, digits int) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+
+	h := hmac.New(sha1.New, key)
+	h.Write(msg[:])
+	sum := h.Sum(nil)
+
+	off := sum[len(sum)-1] & 0x0f
+	v := binary.BigEndian.Uint32(sum[off:]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, v%mod)
+}
@@ -0,0 +1,81 @@
+package otp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/enigma-id/go/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+// rfc 6238 test vectors of SHA1
+func TestTOTPCode(t *testing.T) {
+	tp := &TOTP{Secret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", Digits: 8}
+
+	for ts, code := range map[int64]string{
+		59:         "94287082",
+		1111111109: "07081804",
+		1111111111: "14050471",
+		1234567890: "89005924",
+		2000000000: "69279037",
+	} {
+		c, err := tp.Code(time.Unix(ts, 0))
+		assert.NoError(t, err)
+		assert.Equal(t, code, c)
+	}
+}
+
+func TestTOTPValidate(t *testing.T) {
+	tp, err := NewTOTP("Enigma", "budi@mail.com")
+	assert.NoError(t, err)
+	assert.Len(t, tp.Secret, 32)
+
+	now := time.Now()
+	code, _ := tp.Code(now)
+	assert.True(t, tp.Validate(code, now))
+	assert.True(t, tp.Validate(code, now.Add(30*time.Second)))
+	assert.False(t, tp.Validate(code, now.Add(90*time.Second)))
+	assert.False(t, tp.Validate("12345", now))
+}
+
+func TestTOTPURI(t *testing.T) {
+	tp := &TOTP{Secret: "JBSWY3DPEHPK3PXP", Issuer: "Enigma ID", Account: "budi@mail.com"}
+
+	u, err := url.Parse(tp.URI())
+	assert.NoError(t, err)
+	assert.Equal(t, "otpauth", u.Scheme)
+	assert.Equal(t, "totp", u.Host)
+	assert.Equal(t, "/Enigma ID:budi@mail.com", u.Path)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", u.Query().Get("secret"))
+	assert.Equal(t, "Enigma ID", u.Query().Get("issuer"))
+	assert.Equal(t, "6", u.Query().Get("digits"))
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	o := New(cache.NewMemory())
+	tp, _ := NewTOTP("Enigma", "budi")
+
+	code, _ := tp.Code(time.Now())
+	assert.NoError(t, o.VerifyTOTP(tp, code))
+	assert.Equal(t, ErrReplayed, o.VerifyTOTP(tp, code))
+	assert.Equal(t, ErrInvalid, o.VerifyTOTP(tp, "abcdef"))
+}
+
+func TestChallenge(t *testing.T) {
+	key := []byte("secret")
+
+	token, err := Challenge(key, 10, time.Minute)
+	assert.NoError(t, err)
+
+	sub, err := ParseChallenge(key, token)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(10), sub)
+
+	_, err = ParseChallenge([]byte("other"), token)
+	assert.Equal(t, ErrChallenge, err)
+
+	token, _ = Challenge(key, 10, -time.Minute)
+	_, err = ParseChallenge(key, token)
+	assert.Equal(t, ErrChallenge, err)
+}
@@ -0,0 +1,93 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/enigma-id/go/broker"
+	"github.com/enigma-id/go/db"
+)
+
+// Table name of the outbox.
+var Table = "outbox"
+
+// Message is the stored event of the outbox.
+type Message struct {
+	ID          string     `db:"id"`
+	Topic       string     `db:"topic"`
+	Type        string     `db:"type"`
+	Payload     string     `db:"payload"`
+	Attempts    int        `db:"attempts"`
+	LastError   *string    `db:"last_error"`
+	CreatedAt   time.Time  `db:"created_at"`
+	AvailableAt time.Time  `db:"available_at"`
+	PublishedAt *time.Time `db:"published_at"`
+}
+
+// Schema returns create table statement of the outbox for the driver.
+func Schema(driver string) string {
+	text, ts := "TEXT", "DATETIME"
+	switch driver {
+	case "postgres", "pgx", "cockroach":
+		ts = "TIMESTAMPTZ"
+	case "mysql":
+		text, ts = "MEDIUMTEXT", "DATETIME(6)"
+	}
+
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id VARCHAR(64) NOT NULL PRIMARY KEY,
+	topic VARCHAR(255) NOT NULL,
+	type VARCHAR(255) NOT NULL,
+	payload %s NOT NULL,
+	attempts INT NOT NULL DEFAULT 0,
+	last_error %s NULL,
+	created_at %s NOT NULL,
+	available_at %s NOT NULL,
+	published_at %s NULL
+)`, Table, text, text, ts, ts, ts)
+}
+
+// Store saves the envelope into the outbox, q should be the transaction
+// of the domain writes so the event is stored only when the writes committed.
+//
+//	err := d.InTx(ctx, func(ctx context.Context, tx *db.Tx) error {
+//		if _, err := tx.NamedExec(ctx, "INSERT INTO orders ...", o); err != nil {
+//			return err
+//		}
+//		return outbox.Add(ctx, tx, "order.created", "order.created", o)
+//	})
+func Store(ctx context.Context, q db.Querier, topic string, e *broker.Envelope) error {
+	b, err := e.Marshal()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	_, err = q.NamedExec(ctx, "INSERT INTO "+Table+" (id, topic, type, payload, attempts, created_at, available_at) "+
+		"VALUES (:id, :topic, :type, :payload, 0, :created_at, :available_at)", map[string]interface{}{
+		"id":           e.ID,
+		"topic":        topic,
+		"type":         e.Type,
+		"payload":      string(b),
+		"created_at":   now,
+		"available_at": now,
+	})
+
+	return err
+}
+
+// Add creates envelope of the event and saves it into the outbox,
+// trace context is taken from the context as broker.NewEnvelope.
+func Add(ctx context.Context, q db.Querier, topic, typ string, data interface{}) error {
+	e, err := broker.NewEnvelope(ctx, typ, data)
+	if err != nil {
+		return err
+	}
+
+	return Store(ctx, q, topic, e)
+}
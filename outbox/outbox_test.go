@@ -0,0 +1,157 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/enigma-id/go/broker"
+	"github.com/enigma-id/go/db"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type publisher struct {
+	published []*broker.Envelope
+	err       error
+}
+
+func (p *publisher) Publish(ctx context.Context, topic string, e *broker.Envelope) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.published = append(p.published, e)
+	return nil
+}
+
+func (p *publisher) Close() error { return nil }
+
+func testDB(t *testing.T) *db.DB {
+	d, err := db.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.SQL.SetMaxOpenConns(1)
+
+	_, err = d.Exec(context.Background(), Schema(d.Driver()))
+	assert.NoError(t, err)
+	_, err = d.Exec(context.Background(), "CREATE TABLE orders (id INTEGER PRIMARY KEY, total REAL)")
+	assert.NoError(t, err)
+
+	return d
+}
+
+func TestStoreInTx(t *testing.T) {
+	d := testDB(t)
+	ctx := context.Background()
+
+	// rollbacked writes doesn't leave the event
+	err := d.InTx(ctx, func(ctx context.Context, tx *db.Tx) error {
+		tx.Exec(ctx, "INSERT INTO orders (id, total) VALUES (1, 25000)")
+		assert.NoError(t, Add(ctx, tx, "order", "order.created", map[string]interface{}{"id": 1}))
+		return errors.New("failed")
+	})
+	assert.Error(t, err)
+
+	err = d.InTx(ctx, func(ctx context.Context, tx *db.Tx) error {
+		tx.Exec(ctx, "INSERT INTO orders (id, total) VALUES (2, 25000)")
+		return Add(ctx, tx, "order", "order.created", map[string]interface{}{"id": 2})
+	})
+	assert.NoError(t, err)
+
+	var msgs []Message
+	assert.NoError(t, d.Select(ctx, &msgs, "SELECT * FROM outbox"))
+	if assert.Len(t, msgs, 1) {
+		assert.Equal(t, "order", msgs[0].Topic)
+		assert.Equal(t, "order.created", msgs[0].Type)
+		assert.Nil(t, msgs[0].PublishedAt)
+	}
+}
+
+func TestRelay(t *testing.T) {
+	d := testDB(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, Add(ctx, d, "order", "order.created", i))
+	}
+
+	p := &publisher{}
+	r := NewRelay(d, p, WithBatchSize(2))
+	r.Logger = zap.NewNop()
+
+	n, err := r.Flush(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	n, err = r.Flush(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	n, err = r.Flush(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	if assert.Len(t, p.published, 3) {
+		var i int
+		assert.NoError(t, p.published[2].Decode(&i))
+		assert.Equal(t, 2, i)
+	}
+
+	r.Retention = -time.Minute
+	deleted, err := r.Cleanup(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+}
+
+func TestRelayFailure(t *testing.T) {
+	d := testDB(t)
+	ctx := context.Background()
+
+	assert.NoError(t, Add(ctx, d, "order", "order.created", 1))
+	assert.NoError(t, Add(ctx, d, "order", "order.paid", 1))
+
+	p := &publisher{err: errors.New("broker is down")}
+	r := NewRelay(d, p, WithMaxAttempts(2))
+	r.Logger = zap.NewNop()
+	r.Backoff = 0
+
+	n, err := r.Flush(ctx)
+	assert.EqualError(t, err, "broker is down")
+	assert.Equal(t, 0, n)
+
+	var m Message
+	assert.NoError(t, d.Get(ctx, &m, "SELECT * FROM outbox WHERE type = 'order.created'"))
+	assert.Equal(t, 1, m.Attempts)
+	if assert.NotNil(t, m.LastError) {
+		assert.Equal(t, "broker is down", *m.LastError)
+	}
+
+	// abandoned after max attempts, the next message is published
+	r.Flush(ctx)
+	p.err = nil
+	n, err = r.Flush(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, "order.paid", p.published[0].Type)
+}
+
+func TestRun(t *testing.T) {
+	d := testDB(t)
+	p := &publisher{}
+	r := NewRelay(d, p, WithInterval(5*time.Millisecond))
+	r.Logger = zap.NewNop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		Add(context.Background(), d, "order", "order.created", 1)
+	}()
+
+	assert.Equal(t, context.DeadlineExceeded, r.Run(ctx))
+	assert.Len(t, p.published, 1)
+}
@@ -0,0 +1,211 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/enigma-id/go/broker"
+	"github.com/enigma-id/go/db"
+	"github.com/enigma-id/go/utility/log"
+	"go.uber.org/zap"
+)
+
+type (
+	// Option configures the relay.
+	Option func(*Relay)
+
+	// Relay publishes stored messages of the outbox through the publisher.
+	// Pending messages are locked while publishing (FOR UPDATE SKIP LOCKED on
+	// mysql and postgres) so multiple relays don't publish the same message,
+	// a message is marked as published after the publisher accepted it.
+	// When the relay crashes between publish and mark, the message is published
+	// again, consumer should deduplicate using the envelope id.
+	Relay struct {
+		DB        *db.DB
+		Publisher broker.Publisher
+
+		// BatchSize is max messages published on each poll, default 100.
+		BatchSize int
+
+		// Interval between polls when there is no pending message, default 1s.
+		Interval time.Duration
+
+		// MaxAttempts of failed message before it's abandoned, default 10.
+		MaxAttempts int
+
+		// Backoff before failed message is published again, doubled on each attempt, default 1s.
+		Backoff time.Duration
+
+		// Retention of published messages before deleted, zero keeps them forever.
+		Retention time.Duration
+
+		Logger *zap.Logger
+	}
+)
+
+// WithBatchSize sets max messages published on each poll.
+func WithBatchSize(n int) Option {
+	return func(r *Relay) {
+		r.BatchSize = n
+	}
+}
+
+// WithInterval sets interval between polls.
+func WithInterval(d time.Duration) Option {
+	return func(r *Relay) {
+		r.Interval = d
+	}
+}
+
+// WithMaxAttempts sets max attempts of failed message.
+func WithMaxAttempts(n int) Option {
+	return func(r *Relay) {
+		r.MaxAttempts = n
+	}
+}
+
+// WithRetention deletes published messages older than d.
+func WithRetention(d time.Duration) Option {
+	return func(r *Relay) {
+		r.Retention = d
+	}
+}
+
+// NewRelay creates relay of the outbox in the database.
+func NewRelay(d *db.DB, p broker.Publisher, opts ...Option) *Relay {
+	r := &Relay{
+		DB:          d,
+		Publisher:   p,
+		BatchSize:   100,
+		Interval:    time.Second,
+		MaxAttempts: 10,
+		Backoff:     time.Second,
+		Logger:      log.Logger,
+	}
+	for _, o := range opts {
+		o(r)
+	}
+
+	return r
+}
+
+// Run polls and publishes the pending messages until the context is done.
+func (r *Relay) Run(ctx context.Context) error {
+	cleanup := time.Now()
+
+	for {
+		n, err := r.Flush(ctx)
+		if err != nil && ctx.Err() == nil {
+			r.Logger.Warn("outbox: relay failed", zap.Error(err))
+		}
+
+		if r.Retention > 0 && time.Since(cleanup) > time.Minute {
+			if _, err := r.Cleanup(ctx); err != nil && ctx.Err() == nil {
+				r.Logger.Warn("outbox: cleanup failed", zap.Error(err))
+			}
+			cleanup = time.Now()
+		}
+
+		// full batch means there may be more pending messages
+		if n == r.BatchSize && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.Interval):
+		}
+	}
+}
+
+// Flush publishes a batch of pending messages in order of creation, returns
+// number of published messages. Publishing stops on the first failure so
+// the order of the messages is kept.
+func (r *Relay) Flush(ctx context.Context) (n int, err error) {
+	var failed error
+
+	err = r.DB.InTx(ctx, func(ctx context.Context, tx *db.Tx) error {
+		n, failed = 0, nil
+
+		var msgs []Message
+		if err := tx.NamedSelect(ctx, &msgs, "SELECT * FROM "+Table+" WHERE published_at IS NULL AND attempts < :max "+
+			"AND available_at <= :now ORDER BY created_at, id LIMIT :limit"+lockClause(r.DB.Driver()), map[string]interface{}{
+			"max":   r.MaxAttempts,
+			"now":   time.Now().UTC(),
+			"limit": r.BatchSize,
+		}); err != nil {
+			return err
+		}
+
+		for _, m := range msgs {
+			if failed = r.publish(ctx, &m); failed != nil {
+				return r.fail(ctx, tx, &m, failed)
+			}
+
+			if _, err := tx.NamedExec(ctx, "UPDATE "+Table+" SET published_at = :now, attempts = attempts + 1 WHERE id = :id",
+				map[string]interface{}{"now": time.Now().UTC(), "id": m.ID}); err != nil {
+				return err
+			}
+			n++
+		}
+
+		return nil
+	})
+
+	if err == nil {
+		err = failed
+	}
+
+	return
+}
+
+func (r *Relay) publish(ctx context.Context, m *Message) error {
+	e, err := broker.Unmarshal([]byte(m.Payload))
+	if err != nil {
+		return err
+	}
+
+	return r.Publisher.Publish(ctx, m.Topic, e)
+}
+
+// fail records the failure and schedules next attempt with backoff.
+func (r *Relay) fail(ctx context.Context, tx *db.Tx, m *Message, cause error) error {
+	backoff := r.Backoff << uint(m.Attempts)
+	if backoff < r.Backoff || backoff > time.Hour {
+		backoff = time.Hour
+	}
+
+	if m.Attempts+1 >= r.MaxAttempts {
+		r.Logger.Error("outbox: message is abandoned", zap.String("id", m.ID), zap.String("topic", m.Topic), zap.Error(cause))
+	}
+
+	_, err := tx.NamedExec(ctx, "UPDATE "+Table+" SET attempts = attempts + 1, last_error = :error, available_at = :at WHERE id = :id",
+		map[string]interface{}{"error": cause.Error(), "at": time.Now().UTC().Add(backoff), "id": m.ID})
+
+	return err
+}
+
+// Cleanup deletes published messages older than the retention.
+func (r *Relay) Cleanup(ctx context.Context) (int64, error) {
+	res, err := r.DB.NamedExec(ctx, "DELETE FROM "+Table+" WHERE published_at IS NOT NULL AND published_at < :before",
+		map[string]interface{}{"before": time.Now().UTC().Add(-r.Retention)})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+func lockClause(driver string) string {
+	switch driver {
+	case "mysql", "postgres", "pgx":
+		return " FOR UPDATE SKIP LOCKED"
+	}
+
+	return ""
+}
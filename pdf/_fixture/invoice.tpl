@@ -0,0 +1,12 @@
+# INVOICE
+Nomor :: {{.Number}}
+Tanggal :: {{date .Date "02/01/2006"}}
+Pelanggan :: {{.Customer}}
+---
+| No:C:10 | Item | Qty:R:20 | Harga:R:40 |
+{{range $i, $x := .Items}}| {{$i}} | {{$x.Name}} | {{$x.Qty}} | {{rupiah $x.Price}} |
+{{end}}
+= Subtotal | {{rupiah .Total}}
+= Total | {{rupiah .Total}}
+
+^ Terima kasih
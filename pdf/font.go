@@ -0,0 +1,52 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package pdf
+
+import (
+	_ "embed"
+	"sync"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// DefaultFont is the embedded unicode font family.
+const DefaultFont = "DejaVu"
+
+var (
+	//go:embed font/DejaVuSansCondensed.ttf
+	dejavu []byte
+
+	//go:embed font/DejaVuSansCondensed-Bold.ttf
+	dejavuBold []byte
+
+	mu    sync.RWMutex
+	fonts = []font{
+		{DefaultFont, "", dejavu},
+		{DefaultFont, "B", dejavuBold},
+	}
+)
+
+type font struct {
+	family string
+	style  string
+	ttf    []byte
+}
+
+// RegisterFont registers truetype font, the font is available on
+// all documents created afterward, style is "" for regular, "B", "I" or "BI".
+func RegisterFont(family, style string, ttf []byte) {
+	mu.Lock()
+	fonts = append(fonts, font{family, style, ttf})
+	mu.Unlock()
+}
+
+func registerFonts(p *gofpdf.Fpdf) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, f := range fonts {
+		p.AddUTF8FontFromBytes(f.family, f.style, f.ttf)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package pdf
+
+import (
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/enigma-id/go/utility"
+)
+
+// Rupiah formats n as rupiah, ex. Rp 1.250.000.
+func Rupiah(n float64) string {
+	if n < 0 {
+		return "-Rp " + utility.FormatNumber("#.###,", -n)
+	}
+
+	return "Rp " + utility.FormatNumber("#.###,", n)
+}
+
+// Number formats n with indonesian thousand separator and precision, ex. 1.250,50.
+func Number(n float64, precision int) string {
+	if precision <= 0 {
+		return utility.FormatNumber("#.###,", n)
+	}
+
+	return utility.FormatNumber("#.###,"+strings.Repeat("#", precision), n)
+}
+
+// Date formats t with the layout, zero time is empty.
+func Date(t time.Time, layout string) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(layout)
+}
+
+// Funcs are the template functions available on the templates.
+var Funcs = template.FuncMap{
+	"rupiah": func(n interface{}) string { return Rupiah(toFloat(n)) },
+	"number": func(n interface{}, precision int) string { return Number(toFloat(n), precision) },
+	"date":   func(t time.Time, layout string) string { return Date(t, layout) },
+}
+
+func toFloat(n interface{}) float64 {
+	switch v := n.(type) {
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	case int:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case string:
+		f, _ := strconv.ParseFloat(v, 64)
+		return f
+	}
+
+	return 0
+}
+
+func itoa(i int) string {
+	return strconv.Itoa(i)
+}
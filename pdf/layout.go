@@ -0,0 +1,128 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package pdf
+
+import (
+	"io"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Text alignments.
+const (
+	Left   = "L"
+	Center = "C"
+	Right  = "R"
+)
+
+// lineHeight returns line height in millimeter of the font size in point.
+func lineHeight(size float64) float64 {
+	return size * 0.5
+}
+
+func (d *Document) write(text, style string, size float64, align string) *Document {
+	d.pdf.SetFont(d.font, style, size)
+	d.pdf.MultiCell(0, lineHeight(size), text, "", align, false)
+	d.pdf.SetFont(d.font, "", d.fontSize)
+	return d
+}
+
+// Heading writes bold text with bigger size.
+func (d *Document) Heading(text string) *Document {
+	return d.write(text, "B", d.fontSize*1.6, Left)
+}
+
+// Subheading writes bold text with slightly bigger size.
+func (d *Document) Subheading(text string) *Document {
+	return d.write(text, "B", d.fontSize*1.2, Left)
+}
+
+// Text writes paragraph, long text is wrapped.
+func (d *Document) Text(text string) *Document {
+	return d.write(text, "", d.fontSize, Left)
+}
+
+// Bold writes paragraph with bold style.
+func (d *Document) Bold(text string) *Document {
+	return d.write(text, "B", d.fontSize, Left)
+}
+
+// Align writes paragraph with the alignment, Left, Center or Right.
+func (d *Document) Align(text, align string) *Document {
+	return d.write(text, "", d.fontSize, align)
+}
+
+// Line draws horizontal line across the page.
+func (d *Document) Line() *Document {
+	l, _, r, _ := d.pdf.GetMargins()
+	w, _ := d.pdf.GetPageSize()
+	y := d.pdf.GetY() + 1
+
+	d.pdf.Line(l, y, w-r, y)
+	d.pdf.SetY(y + 1)
+	return d
+}
+
+// Space adds vertical space in millimeter.
+func (d *Document) Space(h float64) *Document {
+	d.pdf.Ln(h)
+	return d
+}
+
+// PageBreak starts a new page.
+func (d *Document) PageBreak() *Document {
+	d.pdf.AddPage()
+	return d
+}
+
+// KeyValue writes pairs of label and value as two columns,
+// ex. KeyValue("No. Invoice", "INV-001", "Tanggal", "17/08/2019").
+func (d *Document) KeyValue(pairs ...string) *Document {
+	lw := 0.0
+	for i := 0; i < len(pairs); i += 2 {
+		if w := d.pdf.GetStringWidth(pairs[i]); w > lw {
+			lw = w
+		}
+	}
+	lw += 4
+
+	lh := lineHeight(d.fontSize)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		d.pdf.CellFormat(lw, lh, pairs[i], "", 0, Left, false, 0, "")
+		d.pdf.CellFormat(3, lh, ":", "", 0, Left, false, 0, "")
+		d.pdf.MultiCell(0, lh, pairs[i+1], "", Left, false)
+	}
+
+	return d
+}
+
+// Summary writes pairs of label and value aligned to the right,
+// the last pair is bold, suitable for total of the invoice.
+func (d *Document) Summary(pairs ...string) *Document {
+	l, _, r, _ := d.pdf.GetMargins()
+	w, _ := d.pdf.GetPageSize()
+	vw := (w - l - r) / 4
+
+	lh := lineHeight(d.fontSize) + 1
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i+2 >= len(pairs) {
+			d.pdf.SetFont(d.font, "B", d.fontSize)
+		}
+		d.pdf.CellFormat(w-l-r-vw, lh, pairs[i], "", 0, Right, false, 0, "")
+		d.pdf.CellFormat(vw, lh, pairs[i+1], "", 1, Right, false, 0, "")
+	}
+	d.pdf.SetFont(d.font, "", d.fontSize)
+
+	return d
+}
+
+// Image draws png, jpg or gif image from the reader with width in millimeter,
+// height is scaled by the ratio. Name should be unique per image in the document.
+func (d *Document) Image(name string, r io.Reader, w float64) *Document {
+	opt := gofpdf.ImageOptions{ReadDpi: true}
+	d.pdf.RegisterImageOptionsReader(name, opt, r)
+	d.pdf.ImageOptions(name, -1, -1, w, 0, true, opt, 0, "")
+	return d
+}
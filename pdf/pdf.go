@@ -0,0 +1,161 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Page sizes and orientations.
+const (
+	A4     = "A4"
+	A5     = "A5"
+	Letter = "Letter"
+	Legal  = "Legal"
+
+	Portrait  = "P"
+	Landscape = "L"
+)
+
+type (
+	// Option configures the document.
+	Option func(*config)
+
+	config struct {
+		size        string
+		orientation string
+		margin      float64
+		font        string
+		fontSize    float64
+		title       string
+		author      string
+		footer      string
+	}
+
+	// Document is a pdf document with layout helpers, units are in millimeter.
+	// Underlying gofpdf instance can be accessed through Fpdf for anything
+	// not covered by the helpers.
+	Document struct {
+		// Filename used by content disposition of the response.
+		Filename string
+
+		pdf      *gofpdf.Fpdf
+		font     string
+		fontSize float64
+	}
+)
+
+// WithSize sets page size, default A4.
+func WithSize(size string) Option {
+	return func(c *config) {
+		c.size = size
+	}
+}
+
+// WithOrientation sets page orientation, default Portrait.
+func WithOrientation(o string) Option {
+	return func(c *config) {
+		c.orientation = o
+	}
+}
+
+// WithMargin sets page margin in millimeter, default 15.
+func WithMargin(m float64) Option {
+	return func(c *config) {
+		c.margin = m
+	}
+}
+
+// WithFont sets font family and size of the text, the family is
+// either embedded DejaVu, font registered by RegisterFont or pdf core font.
+func WithFont(family string, size float64) Option {
+	return func(c *config) {
+		c.font = family
+		c.fontSize = size
+	}
+}
+
+// WithTitle sets title and author of the document metadata.
+func WithTitle(title, author string) Option {
+	return func(c *config) {
+		c.title = title
+		c.author = author
+	}
+}
+
+// WithPageNumber adds page number on the footer, {n} is replaced
+// with the page number and {nb} with total pages, ex. "Halaman {n} dari {nb}".
+func WithPageNumber(format string) Option {
+	return func(c *config) {
+		c.footer = format
+	}
+}
+
+// New creates new document with the first page added.
+func New(opts ...Option) *Document {
+	c := &config{
+		size:        A4,
+		orientation: Portrait,
+		margin:      15,
+		font:        DefaultFont,
+		fontSize:    10,
+	}
+	for _, o := range opts {
+		o(c)
+	}
+
+	p := gofpdf.New(c.orientation, "mm", c.size, "")
+	p.SetMargins(c.margin, c.margin, c.margin)
+	p.SetAutoPageBreak(true, c.margin)
+	p.SetTitle(c.title, true)
+	p.SetAuthor(c.author, true)
+	p.SetCreator("enigma", false)
+	registerFonts(p)
+
+	d := &Document{pdf: p, font: c.font, fontSize: c.fontSize}
+
+	if c.footer != "" {
+		p.AliasNbPages("{nb}")
+		p.SetFooterFunc(func() {
+			p.SetY(-c.margin + 5)
+			p.SetFont(d.font, "", 8)
+			p.CellFormat(0, 5, strings.Replace(c.footer, "{n}", itoa(p.PageNo()), -1), "", 0, "C", false, 0, "")
+		})
+	}
+
+	p.AddPage()
+	p.SetFont(c.font, "", c.fontSize)
+
+	return d
+}
+
+// Fpdf returns underlying gofpdf instance.
+func (d *Document) Fpdf() *gofpdf.Fpdf {
+	return d.pdf
+}
+
+// Err returns first error occured while building the document.
+func (d *Document) Err() error {
+	return d.pdf.Error()
+}
+
+// Output writes the document into w.
+func (d *Document) Output(w io.Writer) error {
+	return d.pdf.Output(w)
+}
+
+// Bytes returns content of the document.
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := d.Output(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
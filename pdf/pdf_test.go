@@ -0,0 +1,86 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type item struct {
+	Name  string
+	Qty   int
+	Price float64
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "Rp 1.250.000", Rupiah(1250000))
+	assert.Equal(t, "-Rp 75.000", Rupiah(-75000))
+	assert.Equal(t, "1.250,50", Number(1250.5, 2))
+	assert.Equal(t, "1.251", Number(1250.5, 0))
+	assert.Equal(t, "", Date(time.Time{}, "02/01/2006"))
+	assert.Equal(t, "17/08/2019", Date(time.Date(2019, 8, 17, 0, 0, 0, 0, time.UTC), "02/01/2006"))
+}
+
+func TestDocument(t *testing.T) {
+	d := New(WithTitle("Invoice", "Enigma"), WithPageNumber("Halaman {n} dari {nb}"))
+	d.Heading("INVOICE").
+		KeyValue("Nomor", "INV-001", "Pelanggan", "Budi").
+		Line().
+		Table([]Column{{Header: "Item"}, {Header: "Harga", Width: 40, Align: Right}}, [][]string{
+			{"Kopi susu gula aren dengan tambahan es batu yang cukup panjang untuk dibungkus", Rupiah(25000)},
+			{"Roti bakar – cokelat keju", Rupiah(18000)},
+		}).
+		Summary("Total", Rupiah(43000)).
+		Align("Terima kasih", Center)
+
+	b, err := d.Bytes()
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(b, []byte("%PDF-")))
+	assert.Equal(t, 1, d.Fpdf().PageNo())
+}
+
+func TestTablePageBreak(t *testing.T) {
+	var rows [][]string
+	for i := 0; i < 200; i++ {
+		rows = append(rows, []string{fmt.Sprint(i), "item"})
+	}
+
+	d := New().Table([]Column{{Header: "No", Width: 20}, {Header: "Item"}}, rows)
+	assert.NoError(t, d.Err())
+	assert.True(t, d.Fpdf().PageNo() > 1)
+}
+
+func TestTemplates(t *testing.T) {
+	tpl, err := NewTemplates("_fixture", nil)
+	assert.NoError(t, err)
+
+	d, err := tpl.Render("invoice", map[string]interface{}{
+		"Number":   "INV-001",
+		"Date":     time.Now(),
+		"Customer": "Budi",
+		"Items":    []item{{"Kopi", 2, 25000}, {"Roti", 1, 18000}},
+		"Total":    68000,
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "invoice.pdf", d.Filename)
+
+		var buf bytes.Buffer
+		assert.NoError(t, d.Output(&buf))
+		assert.True(t, buf.Len() > 0)
+	}
+
+	_, err = tpl.Render("receipt", nil)
+	assert.Error(t, err)
+}
+
+func TestMarkup(t *testing.T) {
+	assert.Equal(t, []string{"1", "Kopi", ""}, cells("| 1 | Kopi | |"))
+	assert.Equal(t, []Column{{Header: "No", Align: Center, Width: 10}, {Header: "Item"}}, columns([]string{"No:c:10", "Item"}))
+
+	d := New().Markup("# Title\n**bold**\n> right\n+++\nplain")
+	assert.NoError(t, d.Err())
+	assert.Equal(t, 2, d.Fpdf().PageNo())
+}
@@ -0,0 +1,123 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package pdf
+
+// Column of the table.
+type Column struct {
+	Header string
+
+	// Width in millimeter, zero width columns share the remaining width.
+	Width float64
+
+	// Align of the cells, Left, Center or Right.
+	Align string
+}
+
+// Table draws table with the columns, long cell is wrapped and
+// the header is repeated when the table continues on the next page.
+func (d *Document) Table(cols []Column, rows [][]string) *Document {
+	if len(cols) == 0 {
+		return d
+	}
+
+	widths := d.widths(cols)
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.Header
+	}
+
+	d.pdf.SetFillColor(235, 235, 235)
+	d.pdf.SetFont(d.font, "B", d.fontSize)
+	d.row(cols, widths, header, true)
+	d.pdf.SetFont(d.font, "", d.fontSize)
+
+	for _, r := range rows {
+		if d.overflow(d.height(widths, r)) {
+			d.pdf.AddPage()
+			d.pdf.SetFont(d.font, "B", d.fontSize)
+			d.row(cols, widths, header, true)
+			d.pdf.SetFont(d.font, "", d.fontSize)
+		}
+		d.row(cols, widths, r, false)
+	}
+
+	return d
+}
+
+func (d *Document) widths(cols []Column) []float64 {
+	l, _, r, _ := d.pdf.GetMargins()
+	pw, _ := d.pdf.GetPageSize()
+
+	free := pw - l - r
+	auto := 0
+	for _, c := range cols {
+		if c.Width > 0 {
+			free -= c.Width
+		} else {
+			auto++
+		}
+	}
+
+	w := make([]float64, len(cols))
+	for i, c := range cols {
+		if w[i] = c.Width; w[i] <= 0 && auto > 0 {
+			w[i] = free / float64(auto)
+		}
+	}
+
+	return w
+}
+
+func (d *Document) height(widths []float64, cells []string) float64 {
+	n := 1
+	for i, w := range widths {
+		if i < len(cells) {
+			if l := len(d.pdf.SplitText(cells[i], w)); l > n {
+				n = l
+			}
+		}
+	}
+
+	return float64(n)*lineHeight(d.fontSize) + 2
+}
+
+func (d *Document) overflow(h float64) bool {
+	_, ph := d.pdf.GetPageSize()
+	_, _, _, b := d.pdf.GetMargins()
+
+	return d.pdf.GetY()+h > ph-b
+}
+
+func (d *Document) row(cols []Column, widths []float64, cells []string, header bool) {
+	h := d.height(widths, cells)
+	lh := lineHeight(d.fontSize)
+	x, y := d.pdf.GetXY()
+
+	style := "D"
+	if header {
+		style = "FD"
+	}
+
+	for i, w := range widths {
+		d.pdf.Rect(x, y, w, h, style)
+
+		if i < len(cells) {
+			align := cols[i].Align
+			if header || align == "" {
+				align = Left
+			}
+
+			for j, l := range d.pdf.SplitText(cells[i], w) {
+				d.pdf.SetXY(x, y+1+float64(j)*lh)
+				d.pdf.CellFormat(w, lh, l, "", 0, align, false, 0, "")
+			}
+		}
+
+		x += w
+	}
+
+	l, _, _, _ := d.pdf.GetMargins()
+	d.pdf.SetXY(l, y+h)
+}
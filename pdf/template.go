@@ -0,0 +1,197 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Templates of the documents, template is text/template that
+// produces the markup of the document, see Document.Markup.
+type Templates struct {
+	t *template.Template
+}
+
+// NewTemplates loads *.tpl files in the directory, template name
+// is the file name without extension, ex. invoice.tpl.
+func NewTemplates(dir string, funcs template.FuncMap) (*Templates, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tpl"))
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]string)
+	for _, f := range files {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+
+		m[strings.TrimSuffix(filepath.Base(f), ".tpl")] = string(b)
+	}
+
+	return ParseTemplates(m, funcs)
+}
+
+// ParseTemplates parses templates from map of name and text,
+// Funcs are always available on the templates.
+func ParseTemplates(m map[string]string, funcs template.FuncMap) (*Templates, error) {
+	t := template.New("").Funcs(Funcs).Funcs(funcs)
+	for name, text := range m {
+		if _, err := t.New(name).Parse(text); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Templates{t: t}, nil
+}
+
+// Render executes the template with the data into new document.
+func (t *Templates) Render(name string, data interface{}, opts ...Option) (*Document, error) {
+	tpl := t.t.Lookup(name)
+	if tpl == nil {
+		return nil, fmt.Errorf("pdf: template %s is not found", name)
+	}
+
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	d := New(opts...)
+	d.Filename = name + ".pdf"
+
+	return d, d.Markup(buf.String()).Err()
+}
+
+// Markup writes the line based markup into the document:
+//
+//	# Heading
+//	## Subheading
+//	**bold text**
+//	> right aligned text
+//	^ centered text
+//	---                     horizontal line
+//	+++                     page break
+//	| No:C:10 | Item | Price:R:40 |
+//	| 1 | Kopi | Rp 25.000 |
+//	= Total | Rp 25.000     summary, the last one is bold
+//	Label :: value          key value
+//
+// The first row of consecutive table rows is the header, header cell can
+// define alignment and width as Header:align:width. Blank line adds space,
+// other lines are written as paragraph.
+func (d *Document) Markup(s string) *Document {
+	var cols []Column
+	var rows [][]string
+	var sum, kv []string
+
+	flush := func() {
+		if cols != nil {
+			d.Table(cols, rows)
+			cols, rows = nil, nil
+		}
+		if sum != nil {
+			d.Summary(sum...)
+			sum = nil
+		}
+		if kv != nil {
+			d.KeyValue(kv...)
+			kv = nil
+		}
+	}
+
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(line, "|"):
+			cells := cells(line)
+			if cols == nil {
+				flush()
+				cols = columns(cells)
+			} else {
+				rows = append(rows, cells)
+			}
+			continue
+		case strings.HasPrefix(line, "= "):
+			if sum == nil {
+				flush()
+			}
+			p := strings.SplitN(line[2:], "|", 2)
+			if len(p) == 2 {
+				sum = append(sum, strings.TrimSpace(p[0]), strings.TrimSpace(p[1]))
+			}
+			continue
+		case strings.Contains(line, " :: "):
+			if kv == nil {
+				flush()
+			}
+			p := strings.SplitN(line, " :: ", 2)
+			kv = append(kv, strings.TrimSpace(p[0]), strings.TrimSpace(p[1]))
+			continue
+		}
+
+		flush()
+
+		switch {
+		case line == "":
+			d.Space(lineHeight(d.fontSize) / 2)
+		case line == "---":
+			d.Line()
+		case line == "+++":
+			d.PageBreak()
+		case strings.HasPrefix(line, "## "):
+			d.Subheading(line[3:])
+		case strings.HasPrefix(line, "# "):
+			d.Heading(line[2:])
+		case strings.HasPrefix(line, "**") && strings.HasSuffix(line, "**") && len(line) > 4:
+			d.Bold(line[2 : len(line)-2])
+		case strings.HasPrefix(line, "> "):
+			d.Align(line[2:], Right)
+		case strings.HasPrefix(line, "^ "):
+			d.Align(line[2:], Center)
+		default:
+			d.Text(line)
+		}
+	}
+
+	flush()
+
+	return d
+}
+
+func cells(line string) []string {
+	line = strings.TrimSuffix(strings.TrimPrefix(line, "|"), "|")
+
+	c := strings.Split(line, "|")
+	for i := range c {
+		c[i] = strings.TrimSpace(c[i])
+	}
+
+	return c
+}
+
+func columns(header []string) []Column {
+	cols := make([]Column, len(header))
+	for i, h := range header {
+		p := strings.Split(h, ":")
+		cols[i].Header = p[0]
+		if len(p) > 1 {
+			cols[i].Align = strings.ToUpper(p[1])
+		}
+		if len(p) > 2 {
+			cols[i].Width, _ = strconv.ParseFloat(p[2], 64)
+		}
+	}
+
+	return cols
+}
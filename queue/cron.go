@@ -0,0 +1,283 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/enigma-id/go/utility/log"
+)
+
+type (
+	// Schedule returns the next time of the job after t,
+	// zero time when there is no next time.
+	Schedule interface {
+		Next(t time.Time) time.Time
+	}
+
+	// Cron enqueues the jobs on their schedule instead of running them inline,
+	// so the periodic heavy work is processed by the workers of the queue.
+	// Each job is enqueued once across the instances running the same Cron
+	// with Redis queue, every instance enqueues the job with other queues.
+	Cron struct {
+		q       Delayer
+		mu      sync.Mutex
+		entries []*cronEntry
+		cancel  context.CancelFunc
+		wg      sync.WaitGroup
+	}
+
+	cronEntry struct {
+		schedule Schedule
+		topic    string
+		payload  []byte
+	}
+
+	every time.Duration
+
+	// cronSchedule is the bit set of the allowed values of each field.
+	cronSchedule struct {
+		minute, hour, dom, month, dow uint64
+		anyDom, anyDow                bool
+	}
+)
+
+// NewCron creates Cron enqueuing the jobs into the queue.
+func NewCron(q Delayer) *Cron {
+	return &Cron{q: q}
+}
+
+// Add registers the job of the topic on the cron spec, see ParseCron.
+func (c *Cron) Add(spec string, topic string, payload []byte) error {
+	s, err := ParseCron(spec)
+	if err != nil {
+		return err
+	}
+
+	c.Schedule(s, topic, payload)
+	return nil
+}
+
+// Schedule registers the job of the topic on the schedule,
+// it must be called before Start.
+func (c *Cron) Schedule(s Schedule, topic string, payload []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = append(c.entries, &cronEntry{schedule: s, topic: topic, payload: payload})
+}
+
+// Start starts enqueuing the registered jobs.
+func (c *Cron) Start() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+
+	c.wg.Add(len(c.entries))
+	for _, e := range c.entries {
+		go c.run(ctx, e)
+	}
+}
+
+// Stop stops enqueuing the jobs, the enqueued jobs are kept in the queue.
+func (c *Cron) Stop() {
+	c.mu.Lock()
+	cancel := c.cancel
+	c.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	c.wg.Wait()
+}
+
+// run enqueues the next job ahead as delayed job, then waits until it's due.
+// With Redis the job id is the scheduled time, so the instances add the same job.
+func (c *Cron) run(ctx context.Context, e *cronEntry) {
+	defer c.wg.Done()
+
+	next := e.schedule.Next(time.Now())
+	for !next.IsZero() {
+		var err error
+		if r, ok := c.q.(*Redis); ok {
+			err = r.enqueueAt("cron-"+strconv.FormatInt(next.UnixMilli(), 10), e.topic, e.payload, next)
+		} else {
+			err = c.q.EnqueueAt(e.topic, e.payload, next)
+		}
+		if err != nil {
+			log.Warnf("queue: scheduling %s at %s failed, %s", e.topic, next.Format(time.RFC3339), err.Error())
+		}
+
+		if !sleep(ctx, time.Until(next)) {
+			return
+		}
+		next = e.schedule.Next(next)
+	}
+}
+
+// Task returns func enqueuing the job, to be registered into other scheduler
+// so the job is processed by the workers of the queue instead of inline.
+//
+//	c.AddFunc("@hourly", queue.Task(q, "report.export", nil))
+func Task(q Queue, topic string, payload []byte) func() {
+	return func() {
+		if err := q.Enqueue(topic, payload); err != nil {
+			log.Warnf("queue: enqueuing %s failed, %s", topic, err.Error())
+		}
+	}
+}
+
+// Every returns schedule repeating every d, aligned to multiple of d
+// since zero time, ex. every hour is on the minute 0.
+func Every(d time.Duration) Schedule {
+	if d < time.Second {
+		d = time.Second
+	}
+	return every(d)
+}
+
+func (d every) Next(t time.Time) time.Time {
+	return t.Truncate(time.Duration(d)).Add(time.Duration(d))
+}
+
+// ParseCron parses the standard cron spec of 5 fields, minute hour
+// day-of-month month day-of-week, each field supports *, list,
+// range and step, ex. "*/15 8-17 * * 1-5". The descriptors @hourly,
+// @daily, @weekly, @monthly, @yearly and "@every <duration>" are supported.
+// The schedule is in the location of the time passed into Next.
+func ParseCron(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	switch spec {
+	case "@hourly":
+		spec = "0 * * * *"
+	case "@daily", "@midnight":
+		spec = "0 0 * * *"
+	case "@weekly":
+		spec = "0 0 * * 0"
+	case "@monthly":
+		spec = "0 0 1 * *"
+	case "@yearly", "@annually":
+		spec = "0 0 1 1 *"
+	}
+
+	if strings.HasPrefix(spec, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(spec[len("@every "):]))
+		if err != nil {
+			return nil, fmt.Errorf("queue: invalid cron spec %q, %s", spec, err.Error())
+		}
+		return Every(d), nil
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("queue: invalid cron spec %q, expected 5 fields", spec)
+	}
+
+	var (
+		s   cronSchedule
+		err error
+	)
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	sets := [5]*uint64{&s.minute, &s.hour, &s.dom, &s.month, &s.dow}
+	for i, f := range fields {
+		if *sets[i], err = parseCronField(f, bounds[i][0], bounds[i][1]); err != nil {
+			return nil, fmt.Errorf("queue: invalid cron spec %q, %s", spec, err.Error())
+		}
+	}
+
+	// 7 is sunday as well
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.anyDom = fields[2] == "*"
+	s.anyDow = fields[4] == "*"
+
+	return &s, nil
+}
+
+func parseCronField(f string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(f, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i != -1 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q", part)
+			}
+			part = part[:i]
+		}
+
+		lo, hi := min, max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("invalid value %q", part)
+				}
+			} else if step > 1 {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("value %q out of range %d-%d", part, min, max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+// Next returns the next minute matching the schedule after t,
+// it gives up after 5 years for the schedule that never matches.
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.day(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// day matches either day of month or day of week when both are restricted.
+func (s *cronSchedule) day(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.anyDom || s.anyDow {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,74 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCron(t *testing.T) {
+	at := time.Date(2019, 8, 30, 10, 7, 30, 0, time.UTC) // friday
+
+	tests := []struct {
+		spec string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2019, 8, 30, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2019, 8, 30, 10, 15, 0, 0, time.UTC)},
+		{"5,40 8-17 * * *", time.Date(2019, 8, 30, 10, 40, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2019, 9, 2, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2019, 9, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * 5", time.Date(2019, 9, 1, 0, 0, 0, 0, time.UTC)},
+		{"30 2 29 2 *", time.Date(2020, 2, 29, 2, 30, 0, 0, time.UTC)},
+		{"@daily", time.Date(2019, 8, 31, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2019, 9, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 1h", time.Date(2019, 8, 30, 11, 0, 0, 0, time.UTC)},
+		{"0 0 31 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		s, err := ParseCron(tt.spec)
+		if assert.NoError(t, err, tt.spec) {
+			assert.Equal(t, tt.next, s.Next(at), tt.spec)
+		}
+	}
+
+	for _, spec := range []string{"* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@every x"} {
+		_, err := ParseCron(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestCron(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		got []string
+	)
+
+	q := NewMemory(1)
+	q.Handle("report", func(ctx context.Context, payload []byte) error {
+		mu.Lock()
+		got = append(got, string(payload))
+		mu.Unlock()
+		return nil
+	})
+
+	c := NewCron(q)
+	c.Schedule(Every(time.Second), "report", []byte("daily"))
+	assert.Error(t, c.Add("* *", "report", nil))
+	c.Start()
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(got) >= 2
+	}, 3*time.Second, 10*time.Millisecond)
+
+	c.Stop()
+	assert.NoError(t, q.Close())
+	assert.Equal(t, "daily", got[0])
+
+	Task(q, "report", nil)()
+}
@@ -0,0 +1,188 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type (
+	// Memory is in process queue processed by a pool of workers,
+	// useful on development, testing and for short lived jobs.
+	// Jobs are lost when the process is stopped, including the delayed jobs.
+	Memory struct {
+		// ErrorHandler is called when the handler returns error or panics.
+		// Optional.
+		ErrorHandler func(topic string, payload []byte, err error)
+
+		mu       sync.RWMutex
+		handlers map[string]Handler
+		depths   map[string]int64
+		timers   map[*time.Timer]struct{}
+		jobs     chan *job
+
+		// cmu guards the jobs channel from being closed while sending
+		cmu    sync.RWMutex
+		closed bool
+		cancel context.CancelFunc
+		wg     sync.WaitGroup
+	}
+
+	job struct {
+		topic   string
+		payload []byte
+	}
+)
+
+// NewMemory creates in process queue with number of workers,
+// at least one worker is started.
+func NewMemory(workers int) *Memory {
+	if workers < 1 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Memory{
+		handlers: make(map[string]Handler),
+		depths:   make(map[string]int64),
+		timers:   make(map[*time.Timer]struct{}),
+		jobs:     make(chan *job, 1024),
+		cancel:   cancel,
+	}
+
+	m.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go m.work(ctx)
+	}
+
+	return m
+}
+
+// Handle registers handler of the topic.
+func (m *Memory) Handle(topic string, h Handler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.handlers[topic] = h
+}
+
+// Enqueue implements Queue interfaces, it blocks when the buffer is full.
+func (m *Memory) Enqueue(topic string, payload []byte) error {
+	m.cmu.RLock()
+	defer m.cmu.RUnlock()
+
+	if m.closed {
+		return ErrClosed
+	}
+
+	m.mu.Lock()
+	if _, ok := m.handlers[topic]; !ok {
+		m.mu.Unlock()
+		return ErrNoHandler
+	}
+	m.depths[topic]++
+	m.mu.Unlock()
+
+	m.jobs <- &job{topic: topic, payload: payload}
+
+	return nil
+}
+
+// EnqueueAt implements Delayer interfaces, the job is kept in a timer until
+// the time, it's passed into ErrorHandler when it can't be enqueued.
+func (m *Memory) EnqueueAt(topic string, payload []byte, at time.Time) error {
+	m.cmu.RLock()
+	defer m.cmu.RUnlock()
+
+	if m.closed {
+		return ErrClosed
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.handlers[topic]; !ok {
+		return ErrNoHandler
+	}
+
+	var t *time.Timer
+	t = time.AfterFunc(time.Until(at), func() {
+		m.mu.Lock()
+		delete(m.timers, t)
+		m.mu.Unlock()
+
+		if err := m.Enqueue(topic, payload); err != nil && m.ErrorHandler != nil {
+			m.ErrorHandler(topic, payload, err)
+		}
+	})
+	m.timers[t] = struct{}{}
+
+	return nil
+}
+
+// Len returns number of jobs of the topic that are not processed yet,
+// it can be used as depth function of the admin queues.
+func (m *Memory) Len(topic string) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.depths[topic], nil
+}
+
+// Close stops accepting new jobs, waits the enqueued jobs to be processed
+// then stops the workers. Delayed jobs that are not due yet are dropped.
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	for t := range m.timers {
+		t.Stop()
+		delete(m.timers, t)
+	}
+	m.mu.Unlock()
+
+	m.cmu.Lock()
+	if !m.closed {
+		m.closed = true
+		close(m.jobs)
+	}
+	m.cmu.Unlock()
+
+	m.wg.Wait()
+	m.cancel()
+
+	return nil
+}
+
+func (m *Memory) work(ctx context.Context) {
+	defer m.wg.Done()
+
+	for j := range m.jobs {
+		m.mu.RLock()
+		h := m.handlers[j.topic]
+		m.mu.RUnlock()
+
+		err := m.run(ctx, h, j)
+
+		m.mu.Lock()
+		m.depths[j.topic]--
+		m.mu.Unlock()
+
+		if err != nil && m.ErrorHandler != nil {
+			m.ErrorHandler(j.topic, j.payload, err)
+		}
+	}
+}
+
+func (m *Memory) run(ctx context.Context, h Handler, j *job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("queue: handler of %s panics: %v", j.topic, r)
+		}
+	}()
+
+	return h(ctx, j.payload)
+}
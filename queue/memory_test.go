@@ -0,0 +1,78 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemory(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		got    []string
+		failed []error
+	)
+
+	q := NewMemory(2)
+	q.ErrorHandler = func(topic string, payload []byte, err error) {
+		mu.Lock()
+		failed = append(failed, err)
+		mu.Unlock()
+	}
+	q.Handle("export", func(ctx context.Context, payload []byte) error {
+		mu.Lock()
+		got = append(got, string(payload))
+		mu.Unlock()
+
+		switch string(payload) {
+		case "fail":
+			return errors.New("failed")
+		case "panic":
+			panic("boom")
+		}
+		return nil
+	})
+
+	assert.Equal(t, ErrNoHandler, q.Enqueue("import", nil))
+	for _, p := range []string{"a", "b", "fail", "panic"} {
+		assert.NoError(t, q.Enqueue("export", []byte(p)))
+	}
+
+	assert.NoError(t, q.Close())
+	assert.ElementsMatch(t, []string{"a", "b", "fail", "panic"}, got)
+	assert.Len(t, failed, 2)
+
+	n, _ := q.Len("export")
+	assert.Zero(t, n)
+	assert.Equal(t, ErrClosed, q.Enqueue("export", nil))
+}
+
+func TestMemoryDelayed(t *testing.T) {
+	done := make(chan time.Time, 2)
+	q := NewMemory(1)
+	q.Handle("remind", func(ctx context.Context, payload []byte) error {
+		done <- time.Now()
+		return nil
+	})
+
+	start := time.Now()
+	assert.Equal(t, ErrNoHandler, q.EnqueueAt("import", nil, start))
+	assert.NoError(t, EnqueueIn(q, "remind", []byte("a"), 50*time.Millisecond))
+	assert.NoError(t, q.EnqueueAt("remind", []byte("b"), start.Add(time.Hour)))
+
+	select {
+	case at := <-done:
+		assert.True(t, at.Sub(start) >= 50*time.Millisecond)
+	case <-time.After(time.Second):
+		t.Fatal("delayed job is not delivered")
+	}
+
+	// job that is not due yet is dropped
+	assert.NoError(t, q.Close())
+	assert.Len(t, done, 0)
+	assert.Equal(t, ErrClosed, q.EnqueueAt("remind", nil, start))
+}
@@ -0,0 +1,41 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrClosed returned when enqueueing into closed queue.
+	ErrClosed = errors.New("queue: closed")
+	// ErrNoHandler returned when the topic doesn't have any handler.
+	ErrNoHandler = errors.New("queue: topic has no handler")
+)
+
+type (
+	// Queue is the interface that wraps the Enqueue method,
+	// it's compatible with the queue of mail and notify package.
+	Queue interface {
+		Enqueue(topic string, payload []byte) error
+	}
+
+	// Delayer is the queue that delivers the job at the given time,
+	// the job is delivered immediately when the time is passed.
+	Delayer interface {
+		Queue
+		EnqueueAt(topic string, payload []byte, at time.Time) error
+	}
+
+	// Handler processes the payload of the topic.
+	Handler func(ctx context.Context, payload []byte) error
+)
+
+// EnqueueIn enqueues the job delivered after the delay.
+func EnqueueIn(q Delayer, topic string, payload []byte, delay time.Duration) error {
+	return q.EnqueueAt(topic, payload, time.Now().Add(delay))
+}
@@ -0,0 +1,523 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/enigma-id/go/env"
+	"github.com/enigma-id/go/utility/log"
+	"github.com/gomodule/redigo/redis"
+)
+
+// ErrMaxDeliveries passed into ErrorHandler when the job is dropped
+// after being delivered MaxDeliveries times.
+var ErrMaxDeliveries = errors.New("queue: job exceeds max deliveries")
+
+type (
+	// Redis is durable queue on redis streams, each topic is a stream consumed
+	// by a consumer group so the jobs are shared by the workers of all instances.
+	// Failed job and job of crashed worker stay pending and are claimed again
+	// after ClaimIdle, until it's delivered MaxDeliveries times.
+	Redis struct {
+		Pool *redis.Pool
+
+		// Prefix of the stream keys, default is "queue:".
+		Prefix string
+
+		// Group is name of the consumer group, default is "workers".
+		Group string
+
+		// Consumer is name of this consumer in the group, default is hostname-pid.
+		Consumer string
+
+		// MaxLen trims the stream approximately to the length, 0 is unlimited.
+		// Oldest jobs are dropped even when they are not processed yet.
+		MaxLen int64
+
+		// ClaimIdle is duration after the pending job is claimed, default is 1 minute.
+		ClaimIdle time.Duration
+
+		// MaxDeliveries is the maximum attempts of the job, default is 5.
+		MaxDeliveries int64
+
+		// Block is the longest duration of waiting new jobs, default is 5 seconds.
+		Block time.Duration
+
+		// Poll is the interval of moving the due delayed jobs
+		// into the streams, default is 1 second.
+		Poll time.Duration
+
+		// ErrorHandler is called when the job is dropped after MaxDeliveries.
+		// Optional.
+		ErrorHandler func(topic string, payload []byte, err error)
+
+		mu       sync.RWMutex
+		handlers map[string]Handler
+		closed   bool
+		cancel   context.CancelFunc
+		wg       sync.WaitGroup
+	}
+
+	// message of the stream.
+	message struct {
+		id         string
+		payload    []byte
+		deliveries int64
+	}
+)
+
+// NewRedis creates redis streams queue using REDIS_HOST and REDIS_PASSWORD,
+// call Start after the handlers are registered to consume the jobs.
+func NewRedis() *Redis {
+	host, _ := os.Hostname()
+	return &Redis{
+		Prefix:        "queue:",
+		Group:         "workers",
+		Consumer:      fmt.Sprintf("%s-%d", host, os.Getpid()),
+		ClaimIdle:     time.Minute,
+		MaxDeliveries: 5,
+		Block:         5 * time.Second,
+		Poll:          time.Second,
+		handlers:      make(map[string]Handler),
+		Pool: &redis.Pool{
+			MaxIdle:     3,
+			IdleTimeout: 4 * time.Minute,
+			Dial: func() (redis.Conn, error) {
+				return redis.DialURL(fmt.Sprintf("redis://%s", env.GetString("REDIS_HOST", "127.0.0.1:6379")),
+					redis.DialPassword(env.GetString("REDIS_PASSWORD", "")))
+			},
+		},
+	}
+}
+
+// Handle registers handler of the topic, it must be called before Start.
+func (q *Redis) Handle(topic string, h Handler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.handlers[topic] = h
+}
+
+// Enqueue implements Queue interfaces, it appends the job into stream of the topic.
+// The topic doesn't need handler in this process, the jobs can be consumed by other service.
+func (q *Redis) Enqueue(topic string, payload []byte) error {
+	q.mu.RLock()
+	closed := q.closed
+	q.mu.RUnlock()
+	if closed {
+		return ErrClosed
+	}
+
+	conn := q.Pool.Get()
+	defer conn.Close()
+
+	args := redis.Args{q.Prefix + topic}
+	if q.MaxLen > 0 {
+		args = args.Add("MAXLEN", "~", q.MaxLen)
+	}
+	_, err := conn.Do("XADD", args.Add("*", "payload", payload)...)
+
+	return err
+}
+
+// EnqueueAt implements Delayer interfaces, the job is kept in a sorted set
+// scored by the time, it's moved into the stream of the topic by the started queue.
+func (q *Redis) EnqueueAt(topic string, payload []byte, at time.Time) error {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+
+	return q.enqueueAt(hex.EncodeToString(id), topic, payload, at)
+}
+
+// enqueueAt adds the delayed job, job with the same id and topic
+// is added once until it's moved into the stream.
+func (q *Redis) enqueueAt(id, topic string, payload []byte, at time.Time) error {
+	q.mu.RLock()
+	closed := q.closed
+	q.mu.RUnlock()
+	if closed {
+		return ErrClosed
+	}
+
+	conn := q.Pool.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("ZADD", q.Prefix+delayedKey, "NX", at.UnixMilli(), delayedMember(id, topic, payload))
+
+	return err
+}
+
+// Delayed returns number of the delayed jobs of all topics that are not due yet.
+func (q *Redis) Delayed() (int64, error) {
+	conn := q.Pool.Get()
+	defer conn.Close()
+
+	return redis.Int64(conn.Do("ZCARD", q.Prefix+delayedKey))
+}
+
+// Len returns number of jobs of the topic that are not processed yet,
+// it can be used as depth function of the admin queues.
+func (q *Redis) Len(topic string) (int64, error) {
+	conn := q.Pool.Get()
+	defer conn.Close()
+
+	return redis.Int64(conn.Do("XLEN", q.Prefix+topic))
+}
+
+// Start creates the consumer groups and starts the workers consuming the
+// topics of the registered handlers, with a claimer of the pending jobs
+// and a mover of the due delayed jobs.
+func (q *Redis) Start(workers int) error {
+	if workers < 1 {
+		workers = 1
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.handlers) == 0 {
+		return ErrNoHandler
+	}
+
+	conn := q.Pool.Get()
+	defer conn.Close()
+
+	streams := make([]string, 0, len(q.handlers))
+	for topic := range q.handlers {
+		key := q.Prefix + topic
+		if _, err := conn.Do("XGROUP", "CREATE", key, q.Group, "0", "MKSTREAM"); err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+			return err
+		}
+		streams = append(streams, key)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q.cancel = cancel
+
+	q.wg.Add(workers + 2)
+	for i := 0; i < workers; i++ {
+		go q.work(ctx, streams)
+	}
+	go q.claim(ctx, streams)
+	go q.schedule(ctx)
+
+	return nil
+}
+
+// Close stops the workers after the jobs being processed are finished,
+// it waits at most Block for the workers waiting new jobs.
+func (q *Redis) Close() error {
+	q.mu.Lock()
+	q.closed = true
+	cancel := q.cancel
+	q.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	q.wg.Wait()
+
+	return q.Pool.Close()
+}
+
+// work reads new jobs of the streams.
+func (q *Redis) work(ctx context.Context, streams []string) {
+	defer q.wg.Done()
+
+	args := redis.Args{"GROUP", q.Group, q.Consumer, "COUNT", 1, "BLOCK", q.Block.Milliseconds(), "STREAMS"}
+	args = args.AddFlat(streams)
+	for range streams {
+		args = args.Add(">")
+	}
+
+	for ctx.Err() == nil {
+		conn := q.Pool.Get()
+		reply, err := conn.Do("XREADGROUP", args...)
+		conn.Close()
+
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Warnf("queue: reading redis streams failed, %s", err.Error())
+				sleep(ctx, time.Second)
+			}
+			continue
+		}
+
+		entries, err := parseStreams(reply)
+		if err != nil {
+			log.Warnf("queue: parsing redis streams failed, %s", err.Error())
+			continue
+		}
+
+		for key, msgs := range entries {
+			for _, m := range msgs {
+				q.process(ctx, key, m)
+			}
+		}
+	}
+}
+
+// claim periodically claims the jobs pending longer than ClaimIdle,
+// the jobs of crashed workers and the failed jobs.
+func (q *Redis) claim(ctx context.Context, streams []string) {
+	defer q.wg.Done()
+
+	for sleep(ctx, q.ClaimIdle/2) {
+		for _, key := range streams {
+			if err := q.claimStream(ctx, key); err != nil && ctx.Err() == nil {
+				log.Warnf("queue: claiming pending jobs of %s failed, %s", key, err.Error())
+			}
+		}
+	}
+}
+
+func (q *Redis) claimStream(ctx context.Context, key string) error {
+	conn := q.Pool.Get()
+	defer conn.Close()
+
+	idle := q.ClaimIdle.Milliseconds()
+	reply, err := conn.Do("XPENDING", key, q.Group, "IDLE", idle, "-", "+", 100)
+	if err != nil {
+		return err
+	}
+
+	pending, err := parsePending(reply)
+	if err != nil || len(pending) == 0 {
+		return err
+	}
+
+	args := redis.Args{key, q.Group, q.Consumer, idle}
+	for id := range pending {
+		args = args.Add(id)
+	}
+	if reply, err = conn.Do("XCLAIM", args...); err != nil {
+		return err
+	}
+
+	msgs, err := parseMessages(reply)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range msgs {
+		// XCLAIM increments the deliveries
+		m.deliveries = pending[m.id] + 1
+		q.process(ctx, key, m)
+	}
+
+	return nil
+}
+
+// schedule periodically moves the due delayed jobs into the streams,
+// the jobs are moved atomically so it's safe across instances.
+func (q *Redis) schedule(ctx context.Context) {
+	defer q.wg.Done()
+
+	for sleep(ctx, q.Poll) {
+		conn := q.Pool.Get()
+		for {
+			n, err := redis.Int(moveScript.Do(conn, q.Prefix+delayedKey, time.Now().UnixMilli(), 100, q.Prefix, q.MaxLen))
+			if err != nil && ctx.Err() == nil {
+				log.Warnf("queue: moving delayed jobs failed, %s", err.Error())
+			}
+			if err != nil || n < 100 {
+				break
+			}
+		}
+		conn.Close()
+	}
+}
+
+// process runs handler of the job, the job is acknowledged when it succeed
+// or it exceeds max deliveries, otherwise it stays pending to be claimed.
+func (q *Redis) process(ctx context.Context, key string, m message) {
+	topic := strings.TrimPrefix(key, q.Prefix)
+	q.mu.RLock()
+	h := q.handlers[topic]
+	q.mu.RUnlock()
+
+	var err error
+	if m.deliveries > q.MaxDeliveries {
+		err = ErrMaxDeliveries
+	} else if err = q.run(ctx, topic, h, m.payload); err != nil {
+		log.Warnf("queue: job %s of %s failed (attempt %d), %s", m.id, topic, m.deliveries, err.Error())
+		if m.deliveries < q.MaxDeliveries {
+			return
+		}
+	}
+
+	if err != nil && q.ErrorHandler != nil {
+		q.ErrorHandler(topic, m.payload, err)
+	}
+
+	conn := q.Pool.Get()
+	defer conn.Close()
+
+	conn.Send("XACK", key, q.Group, m.id)
+	conn.Send("XDEL", key, m.id)
+	if err = conn.Flush(); err == nil {
+		_, err = conn.Receive()
+	}
+	if err == nil {
+		_, err = conn.Receive()
+	}
+	if err != nil {
+		log.Warnf("queue: acknowledging job %s of %s failed, %s", m.id, topic, err.Error())
+	}
+}
+
+func (q *Redis) run(ctx context.Context, topic string, h Handler, payload []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("queue: handler of %s panics: %v", topic, r)
+		}
+	}()
+
+	if h == nil {
+		return ErrNoHandler
+	}
+
+	return h(ctx, payload)
+}
+
+// delayedKey is the sorted set of the delayed jobs, it's prefixed by
+// "@" to not collide with the streams of the topics.
+const delayedKey = "@delayed"
+
+// moveScript moves at most ARGV[2] jobs that are due at ARGV[1]
+// from the sorted set into the streams of the topics.
+var moveScript = redis.NewScript(1, `
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, m in ipairs(due) do
+	redis.call('ZREM', KEYS[1], m)
+	local i = string.find(m, '\n', 1, true)
+	local j = string.find(m, '\n', i + 1, true)
+	local key = ARGV[3] .. string.sub(m, i + 1, j - 1)
+	if tonumber(ARGV[4]) > 0 then
+		redis.call('XADD', key, 'MAXLEN', '~', ARGV[4], '*', 'payload', string.sub(m, j + 1))
+	else
+		redis.call('XADD', key, '*', 'payload', string.sub(m, j + 1))
+	end
+end
+return #due
+`)
+
+// delayedMember encodes the delayed job as "id\ntopic\npayload".
+func delayedMember(id, topic string, payload []byte) string {
+	return id + "\n" + topic + "\n" + string(payload)
+}
+
+// sleep waits for the duration, it returns false when the context is done.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// parseStreams parses reply of XREADGROUP, nil reply is returned on timeout.
+func parseStreams(reply interface{}) (map[string][]message, error) {
+	if reply == nil {
+		return nil, nil
+	}
+
+	streams, err := redis.Values(reply, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string][]message, len(streams))
+	for _, s := range streams {
+		kv, err := redis.Values(s, nil)
+		if err != nil || len(kv) != 2 {
+			return nil, fmt.Errorf("unexpected stream reply %v", s)
+		}
+
+		key, err := redis.String(kv[0], nil)
+		if err != nil {
+			return nil, err
+		}
+		if entries[key], err = parseMessages(kv[1]); err != nil {
+			return nil, err
+		}
+	}
+
+	return entries, nil
+}
+
+// parseMessages parses list of the stream entries, first delivery is assumed.
+func parseMessages(reply interface{}) ([]message, error) {
+	values, err := redis.Values(reply, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := make([]message, 0, len(values))
+	for _, v := range values {
+		entry, err := redis.Values(v, nil)
+		if err != nil || len(entry) != 2 {
+			return nil, fmt.Errorf("unexpected entry reply %v", v)
+		}
+
+		m := message{deliveries: 1}
+		if m.id, err = redis.String(entry[0], nil); err != nil {
+			return nil, err
+		}
+
+		// deleted entry has nil fields
+		fields, _ := redis.ByteSlices(entry[1], nil)
+		for i := 0; i+1 < len(fields); i += 2 {
+			if string(fields[i]) == "payload" {
+				m.payload = fields[i+1]
+			}
+		}
+
+		msgs = append(msgs, m)
+	}
+
+	return msgs, nil
+}
+
+// parsePending parses reply of extended XPENDING into deliveries keyed by id.
+func parsePending(reply interface{}) (map[string]int64, error) {
+	values, err := redis.Values(reply, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make(map[string]int64, len(values))
+	for _, v := range values {
+		p, err := redis.Values(v, nil)
+		if err != nil || len(p) != 4 {
+			return nil, fmt.Errorf("unexpected pending reply %v", v)
+		}
+
+		id, err := redis.String(p[0], nil)
+		if err != nil {
+			return nil, err
+		}
+		if pending[id], err = redis.Int64(p[3], nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return pending, nil
+}
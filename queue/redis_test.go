@@ -0,0 +1,116 @@
+package queue
+
+import (
+	"context"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStreams(t *testing.T) {
+	reply := []interface{}{
+		[]interface{}{
+			[]byte("queue:mail"),
+			[]interface{}{
+				[]interface{}{[]byte("1-0"), []interface{}{[]byte("payload"), []byte("a")}},
+				[]interface{}{[]byte("2-0"), nil},
+			},
+		},
+	}
+
+	entries, err := parseStreams(reply)
+	assert.NoError(t, err)
+	assert.Equal(t, []message{
+		{id: "1-0", payload: []byte("a"), deliveries: 1},
+		{id: "2-0", deliveries: 1},
+	}, entries["queue:mail"])
+
+	entries, err = parseStreams(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, entries)
+
+	_, err = parseStreams([]interface{}{[]interface{}{[]byte("queue:mail")}})
+	assert.Error(t, err)
+}
+
+func TestParsePending(t *testing.T) {
+	reply := []interface{}{
+		[]interface{}{[]byte("1-0"), []byte("worker-1"), int64(65000), int64(2)},
+		[]interface{}{[]byte("3-0"), []byte("worker-2"), int64(61000), int64(5)},
+	}
+
+	pending, err := parsePending(reply)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"1-0": 2, "3-0": 5}, pending)
+
+	_, err = parsePending([]interface{}{[]interface{}{[]byte("1-0")}})
+	assert.Error(t, err)
+}
+
+func TestRedis(t *testing.T) {
+	host := os.Getenv("REDIS_HOST")
+	if host == "" {
+		host = "127.0.0.1:6379"
+	}
+	conn, err := net.DialTimeout("tcp", host, time.Second)
+	if err != nil {
+		t.Skip("redis is not available")
+	}
+	conn.Close()
+
+	q := NewRedis()
+	q.Prefix = "queue-test:"
+	q.Block = 100 * time.Millisecond
+	q.ClaimIdle = 200 * time.Millisecond
+	q.MaxDeliveries = 2
+
+	c := q.Pool.Get()
+	c.Do("DEL", "queue-test:ok", "queue-test:fail")
+	c.Close()
+
+	var mu sync.Mutex
+	var done, attempts int
+	var dropped []byte
+
+	q.ErrorHandler = func(topic string, payload []byte, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		dropped = payload
+	}
+	q.Handle("ok", func(ctx context.Context, payload []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		done++
+		return nil
+	})
+	q.Handle("fail", func(ctx context.Context, payload []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		return assert.AnError
+	})
+
+	assert.NoError(t, q.Start(2))
+	assert.NoError(t, q.Enqueue("ok", []byte("1")))
+	assert.NoError(t, q.Enqueue("ok", []byte("2")))
+	assert.NoError(t, q.Enqueue("fail", []byte("x")))
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return done == 2 && dropped != nil
+	}, 5*time.Second, 50*time.Millisecond)
+
+	n, err := q.Len("ok")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, []byte("x"), dropped)
+
+	assert.NoError(t, q.Close())
+	assert.Equal(t, ErrClosed, q.Enqueue("ok", nil))
+}
@@ -0,0 +1,286 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package realtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/enigma-id/go/rest"
+	"github.com/enigma-id/go/utility/random"
+	"golang.org/x/net/websocket"
+)
+
+var (
+	// WriteTimeout is maximum duration of writing single message.
+	WriteTimeout = 10 * time.Second
+
+	// MaxPayloadBytes is maximum size of message from the client.
+	MaxPayloadBytes = 4096
+)
+
+// EventGoingAway is sent to the clients before the connection is closed
+// on graceful shutdown, the client should reconnect into other instance.
+const EventGoingAway = "going_away"
+
+// Conn is single websocket connection of the hub.
+type Conn struct {
+	ID    string
+	Token *jwt.Token
+
+	hub   *Hub
+	ws    *websocket.Conn
+	send  chan []byte
+	done  chan struct{}
+	once  sync.Once
+	rooms map[string]bool // guarded by hub.mu
+}
+
+// command sent by the client.
+type command struct {
+	Action string `json:"action"`
+	Room   string `json:"room"`
+}
+
+// Claims returns claims of the jwt, nil when the connection is not authenticated.
+func (c *Conn) Claims() jwt.MapClaims {
+	if c.Token != nil {
+		if mc, ok := c.Token.Claims.(jwt.MapClaims); ok {
+			return mc
+		}
+	}
+
+	return nil
+}
+
+// Send sends the event only into this connection.
+func (c *Conn) Send(event string, data interface{}) error {
+	m := &Message{Event: event}
+	if data != nil {
+		b, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		m.Data = b
+	}
+
+	payload, _ := json.Marshal(m)
+	if !c.enqueue(payload) {
+		return fmt.Errorf("realtime: send queue of connection %s is full", c.ID)
+	}
+
+	return nil
+}
+
+// GoAway sends EventGoingAway and closes the connection
+// after the queued messages are sent.
+func (c *Conn) GoAway() {
+	c.reply(EventGoingAway, "", nil)
+	if !c.enqueue(nil) {
+		c.Close()
+	}
+}
+
+// Close disconnects the connection.
+func (c *Conn) Close() error {
+	c.once.Do(func() {
+		close(c.done)
+		c.ws.Close()
+	})
+
+	return nil
+}
+
+func (c *Conn) enqueue(payload []byte) bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+	}
+
+	select {
+	case c.send <- payload:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *Conn) writeLoop() {
+	for {
+		select {
+		case <-c.done:
+			return
+		case p := <-c.send:
+			if p == nil {
+				// GoAway
+				c.Close()
+				return
+			}
+
+			c.ws.SetWriteDeadline(time.Now().Add(WriteTimeout))
+			if err := websocket.Message.Send(c.ws, string(p)); err != nil {
+				c.Close()
+				return
+			}
+		}
+	}
+}
+
+func (c *Conn) readLoop() {
+	for {
+		var cmd command
+		if err := websocket.JSON.Receive(c.ws, &cmd); err != nil {
+			return
+		}
+
+		switch cmd.Action {
+		case "subscribe":
+			if cmd.Room == "" || !c.hub.subscribe(c, cmd.Room) {
+				c.reply("error", cmd.Room, "forbidden")
+				continue
+			}
+			c.reply("subscribed", cmd.Room, nil)
+		case "unsubscribe":
+			c.hub.unsubscribe(c, cmd.Room)
+			c.reply("unsubscribed", cmd.Room, nil)
+		case "ping":
+			c.reply("pong", "", nil)
+		default:
+			c.reply("error", cmd.Room, "unknown action")
+		}
+	}
+}
+
+func (c *Conn) reply(event string, room string, data interface{}) {
+	m := &Message{Room: room, Event: event}
+	if data != nil {
+		m.Data, _ = json.Marshal(data)
+	}
+
+	payload, _ := json.Marshal(m)
+	c.enqueue(payload)
+}
+
+// Handler returns handler that upgrades the request into websocket connection
+// of the hub, the jwt is taken from mw.JWT, "token" query param or Authorization header.
+// The connections are sent EventGoingAway when the server starts shutting down.
+//
+//	r.GET("/ws", hub.Handler())
+func (h *Hub) Handler() rest.HandlerFunc {
+	return func(c *rest.Context) error {
+		token, err := h.authenticate(c)
+		if err != nil {
+			return err
+		}
+
+		s := websocket.Server{
+			Handshake: func(cfg *websocket.Config, r *http.Request) error {
+				return h.checkOrigin(r)
+			},
+			Handler: func(ws *websocket.Conn) {
+				h.serve(ws, token, c.ShuttingDown())
+			},
+		}
+		s.ServeHTTP(c.Response(), c.Request())
+
+		return nil
+	}
+}
+
+func (h *Hub) serve(ws *websocket.Conn, token *jwt.Token, shutdown <-chan struct{}) {
+	ws.MaxPayloadBytes = MaxPayloadBytes
+
+	c := &Conn{
+		ID:    random.String(16),
+		Token: token,
+		hub:   h,
+		ws:    ws,
+		send:  make(chan []byte, h.QueueSize),
+		done:  make(chan struct{}),
+		rooms: make(map[string]bool),
+	}
+
+	if !h.register(c) {
+		ws.Close()
+		return
+	}
+
+	go c.writeLoop()
+	go func() {
+		select {
+		case <-shutdown:
+			c.GoAway()
+		case <-c.done:
+		}
+	}()
+	c.readLoop()
+
+	h.unregister(c)
+	c.Close()
+}
+
+func (h *Hub) authenticate(c *rest.Context) (*jwt.Token, error) {
+	if t, ok := c.Get("user").(*jwt.Token); ok {
+		return t, nil
+	}
+
+	if h.SigningKey == nil {
+		return nil, nil
+	}
+
+	auth := c.QueryParam("token")
+	if a := c.Request().Header.Get(rest.HeaderAuthorization); auth == "" && strings.HasPrefix(a, "Bearer ") {
+		auth = a[7:]
+	}
+
+	if auth == "" {
+		return nil, rest.ErrUnauthorized
+	}
+
+	t, err := jwt.Parse(auth, func(t *jwt.Token) (interface{}, error) {
+		if t.Method.Alg() != "HS256" {
+			return nil, fmt.Errorf("unexpected jwt signing method=%v", t.Header["alg"])
+		}
+		return h.SigningKey, nil
+	})
+	if err != nil || !t.Valid {
+		return nil, &rest.HTTPError{
+			Code:     http.StatusUnauthorized,
+			Message:  "invalid or expired jwt",
+			Internal: err,
+		}
+	}
+
+	return t, nil
+}
+
+// checkOrigin allows request without origin or from the same host,
+// use CheckOrigin to allow other origins.
+func (h *Hub) checkOrigin(r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if h.CheckOrigin != nil {
+		if h.CheckOrigin(origin) {
+			return nil
+		}
+		return fmt.Errorf("realtime: origin %s is not allowed", origin)
+	}
+
+	if origin == "" {
+		return nil
+	}
+
+	if u, err := url.Parse(origin); err == nil && u.Host == r.Host {
+		return nil
+	}
+
+	return fmt.Errorf("realtime: origin %s is not allowed", origin)
+}
@@ -0,0 +1,293 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/enigma-id/go/utility/log"
+)
+
+// ErrClosed returned when broadcasting into closed hub.
+var ErrClosed = errors.New("realtime: hub is closed")
+
+type (
+	// Message is the envelope sent to the clients and through the bridge.
+	Message struct {
+		Room  string          `json:"room,omitempty"`
+		Event string          `json:"event"`
+		Data  json.RawMessage `json:"data,omitempty"`
+	}
+
+	// Bridge delivers broadcast into hubs of all instances, ex. RedisBridge.
+	Bridge interface {
+		Publish(payload []byte) error
+		Subscribe(ctx context.Context, fn func(payload []byte)) error
+	}
+
+	// AuthorizeFunc decides whether the connection may subscribe into the room.
+	AuthorizeFunc func(c *Conn, room string) bool
+
+	// Option configures the hub.
+	Option func(*Hub)
+)
+
+// Hub holding the connections and their rooms.
+type Hub struct {
+	// QueueSize is size of send queue of each connection, slow connection
+	// that fills its queue is disconnected. Default is 64.
+	QueueSize int
+
+	// Authorize is called on each subscription, all rooms are allowed when nil.
+	Authorize AuthorizeFunc
+
+	// SigningKey of the HS256 jwt, when set the connection requires valid token.
+	SigningKey interface{}
+
+	// CheckOrigin returns true when the origin is allowed,
+	// default only allows the same host.
+	CheckOrigin func(origin string) bool
+
+	bridge Bridge
+	cancel context.CancelFunc
+
+	mu     sync.RWMutex
+	conns  map[*Conn]bool
+	rooms  map[string]map[*Conn]bool
+	closed bool
+}
+
+// WithBridge sets bridge to deliver broadcast across instances.
+func WithBridge(b Bridge) Option {
+	return func(h *Hub) {
+		h.bridge = b
+	}
+}
+
+// WithAuthorize sets the subscription authorization.
+func WithAuthorize(fn AuthorizeFunc) Option {
+	return func(h *Hub) {
+		h.Authorize = fn
+	}
+}
+
+// WithOrigins allows the origins to connect, "*" allows any origin.
+func WithOrigins(origins ...string) Option {
+	return func(h *Hub) {
+		h.CheckOrigin = func(origin string) bool {
+			for _, o := range origins {
+				if o == "*" || o == origin {
+					return true
+				}
+			}
+			return false
+		}
+	}
+}
+
+// WithJWT requires the connection to be authenticated using jwt signed by the key.
+func WithJWT(key interface{}) Option {
+	return func(h *Hub) {
+		h.SigningKey = key
+	}
+}
+
+// NewHub creates hub, when bridge is set the hub starts subscribing
+// into the bridge until Close is called.
+func NewHub(opts ...Option) *Hub {
+	h := &Hub{
+		QueueSize: 64,
+		conns:     make(map[*Conn]bool),
+		rooms:     make(map[string]map[*Conn]bool),
+	}
+
+	for _, o := range opts {
+		o(h)
+	}
+
+	if h.bridge != nil {
+		var ctx context.Context
+		ctx, h.cancel = context.WithCancel(context.Background())
+
+		go func() {
+			if err := h.bridge.Subscribe(ctx, h.receive); err != nil && ctx.Err() == nil {
+				log.Errorf("realtime: bridge subscription stopped, %s", err.Error())
+			}
+		}()
+	}
+
+	return h
+}
+
+// Broadcast sends the event into all connections subscribed to the room,
+// through the bridge when it is set.
+func (h *Hub) Broadcast(room string, event string, data interface{}) error {
+	m := &Message{Room: room, Event: event}
+	if data != nil {
+		b, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		m.Data = b
+	}
+
+	payload, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	if h.isClosed() {
+		return ErrClosed
+	}
+
+	if h.bridge != nil {
+		return h.bridge.Publish(payload)
+	}
+
+	h.deliver(room, payload)
+
+	return nil
+}
+
+// Rooms returns number of connections of each room.
+func (h *Hub) Rooms() map[string]int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	r := make(map[string]int, len(h.rooms))
+	for k, v := range h.rooms {
+		r[k] = len(v)
+	}
+
+	return r
+}
+
+// Len returns number of connections.
+func (h *Hub) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.conns)
+}
+
+// Close stops the bridge subscription and disconnects all connections.
+func (h *Hub) Close() error {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return nil
+	}
+	h.closed = true
+
+	conns := make([]*Conn, 0, len(h.conns))
+	for c := range h.conns {
+		conns = append(conns, c)
+	}
+	h.mu.Unlock()
+
+	if h.cancel != nil {
+		h.cancel()
+	}
+
+	for _, c := range conns {
+		c.Close()
+	}
+
+	return nil
+}
+
+func (h *Hub) isClosed() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.closed
+}
+
+// receive delivers payload from the bridge.
+func (h *Hub) receive(payload []byte) {
+	m := new(Message)
+	if err := json.Unmarshal(payload, m); err != nil {
+		log.Warn("realtime: invalid bridge payload")
+		return
+	}
+
+	h.deliver(m.Room, payload)
+}
+
+func (h *Hub) deliver(room string, payload []byte) {
+	h.mu.RLock()
+	var slow []*Conn
+	for c := range h.rooms[room] {
+		if !c.enqueue(payload) {
+			slow = append(slow, c)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, c := range slow {
+		log.Warnf("realtime: connection %s is too slow, disconnecting", c.ID)
+		c.Close()
+	}
+}
+
+func (h *Hub) register(c *Conn) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return false
+	}
+
+	h.conns[c] = true
+	return true
+}
+
+func (h *Hub) unregister(c *Conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.conns, c)
+	for room := range c.rooms {
+		h.leave(c, room)
+	}
+}
+
+func (h *Hub) subscribe(c *Conn, room string) bool {
+	if h.Authorize != nil && !h.Authorize(c, room) {
+		return false
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.rooms[room] == nil {
+		h.rooms[room] = make(map[*Conn]bool)
+	}
+
+	h.rooms[room][c] = true
+	c.rooms[room] = true
+
+	return true
+}
+
+func (h *Hub) unsubscribe(c *Conn, room string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.leave(c, room)
+}
+
+func (h *Hub) leave(c *Conn, room string) {
+	delete(c.rooms, room)
+	if r := h.rooms[room]; r != nil {
+		delete(r, c)
+		if len(r) == 0 {
+			delete(h.rooms, room)
+		}
+	}
+}
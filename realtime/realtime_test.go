@@ -0,0 +1,172 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package realtime
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/enigma-id/go/rest"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+)
+
+// memoryBridge delivers payload into all subscribed hubs.
+type memoryBridge struct {
+	mu   sync.Mutex
+	subs []func([]byte)
+}
+
+func (b *memoryBridge) Publish(payload []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, fn := range b.subs {
+		fn(payload)
+	}
+	return nil
+}
+
+func (b *memoryBridge) Subscribe(ctx context.Context, fn func([]byte)) error {
+	b.mu.Lock()
+	b.subs = append(b.subs, fn)
+	b.mu.Unlock()
+
+	<-ctx.Done()
+	return nil
+}
+
+func serve(h *Hub) *httptest.Server {
+	r := rest.New()
+	r.GET("/ws", h.Handler())
+
+	return httptest.NewServer(r)
+}
+
+func dial(t *testing.T, s *httptest.Server, query string) *websocket.Conn {
+	u := "ws" + strings.TrimPrefix(s.URL, "http") + "/ws" + query
+	ws, err := websocket.Dial(u, "", s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws.SetDeadline(time.Now().Add(2 * time.Second))
+
+	return ws
+}
+
+func receive(t *testing.T, ws *websocket.Conn) *Message {
+	m := new(Message)
+	assert.NoError(t, websocket.JSON.Receive(ws, m))
+
+	return m
+}
+
+func TestHub(t *testing.T) {
+	bridge := new(memoryBridge)
+	h1 := NewHub(WithBridge(bridge), WithAuthorize(func(c *Conn, room string) bool {
+		return room != "private"
+	}))
+	h2 := NewHub(WithBridge(bridge))
+	defer h1.Close()
+	defer h2.Close()
+
+	s1, s2 := serve(h1), serve(h2)
+	defer s1.Close()
+	defer s2.Close()
+
+	// wait for bridge subscription
+	for {
+		bridge.mu.Lock()
+		n := len(bridge.subs)
+		bridge.mu.Unlock()
+		if n == 2 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	c1, c2 := dial(t, s1, ""), dial(t, s2, "")
+	defer c1.Close()
+	defer c2.Close()
+
+	websocket.JSON.Send(c1, command{Action: "subscribe", Room: "orders"})
+	assert.Equal(t, "subscribed", receive(t, c1).Event)
+	websocket.JSON.Send(c1, command{Action: "subscribe", Room: "private"})
+	assert.Equal(t, "error", receive(t, c1).Event)
+	websocket.JSON.Send(c2, command{Action: "subscribe", Room: "orders"})
+	assert.Equal(t, "subscribed", receive(t, c2).Event)
+
+	assert.Equal(t, map[string]int{"orders": 1}, h1.Rooms())
+
+	// broadcast from the second instance reaches both
+	assert.NoError(t, h2.Broadcast("orders", "created", map[string]string{"code": "SO-1"}))
+	for _, c := range []*websocket.Conn{c1, c2} {
+		m := receive(t, c)
+		assert.Equal(t, "orders", m.Room)
+		assert.Equal(t, "created", m.Event)
+		assert.JSONEq(t, `{"code":"SO-1"}`, string(m.Data))
+	}
+
+	websocket.JSON.Send(c1, command{Action: "unsubscribe", Room: "orders"})
+	assert.Equal(t, "unsubscribed", receive(t, c1).Event)
+	assert.Empty(t, h1.Rooms())
+
+	h1.Close()
+	assert.Equal(t, ErrClosed, h1.Broadcast("orders", "created", nil))
+}
+
+func TestHubJWT(t *testing.T) {
+	key := []byte("secret")
+	h := NewHub(WithJWT(key))
+	s := serve(h)
+	defer s.Close()
+	defer h.Close()
+
+	u := "ws" + strings.TrimPrefix(s.URL, "http") + "/ws"
+	_, err := websocket.Dial(u, "", s.URL)
+	assert.Error(t, err)
+
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"id": 1}).SignedString(key)
+	ws := dial(t, s, "?token="+token)
+	defer ws.Close()
+
+	websocket.JSON.Send(ws, command{Action: "ping"})
+	assert.Equal(t, "pong", receive(t, ws).Event)
+	assert.Equal(t, 1, h.Len())
+
+	_, err = websocket.Dial(u+"?token="+token, "", "http://evil.example.com")
+	assert.Error(t, err)
+}
+
+func TestHubGoingAway(t *testing.T) {
+	h := NewHub()
+	defer h.Close()
+
+	r := rest.New()
+	r.GET("/ws", h.Handler())
+	s := httptest.NewServer(r)
+	defer s.Close()
+
+	ws := dial(t, s, "")
+	defer ws.Close()
+
+	websocket.JSON.Send(ws, command{Action: "ping"})
+	assert.Equal(t, "pong", receive(t, ws).Event)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	r.Shutdown(ctx)
+
+	assert.Equal(t, EventGoingAway, receive(t, ws).Event)
+
+	var m Message
+	assert.Error(t, websocket.JSON.Receive(ws, &m))
+	assert.Eventually(t, func() bool { return h.Len() == 0 }, time.Second, 10*time.Millisecond)
+}
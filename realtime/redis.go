@@ -0,0 +1,97 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package realtime
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/enigma-id/go/env"
+	"github.com/enigma-id/go/utility/log"
+	"github.com/gomodule/redigo/redis"
+)
+
+// RedisBridge delivers broadcast across instances using redis pub/sub.
+type RedisBridge struct {
+	Pool    *redis.Pool
+	Channel string
+}
+
+// NewRedisBridge creates bridge using REDIS_HOST and REDIS_PASSWORD,
+// channel is taken from REALTIME_CHANNEL, default is "realtime".
+func NewRedisBridge() *RedisBridge {
+	return &RedisBridge{
+		Channel: env.GetString("REALTIME_CHANNEL", "realtime"),
+		Pool: &redis.Pool{
+			MaxIdle:     3,
+			IdleTimeout: 4 * time.Minute,
+			Dial: func() (redis.Conn, error) {
+				return redis.DialURL(fmt.Sprintf("redis://%s", env.GetString("REDIS_HOST", "127.0.0.1:6379")),
+					redis.DialPassword(env.GetString("REDIS_PASSWORD", "")))
+			},
+		},
+	}
+}
+
+// Publish publishes the payload into the channel.
+func (b *RedisBridge) Publish(payload []byte) error {
+	conn := b.Pool.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("PUBLISH", b.Channel, payload)
+
+	return err
+}
+
+// Subscribe receives payload of the channel until the context is done,
+// the subscription is reconnected when the connection is lost.
+func (b *RedisBridge) Subscribe(ctx context.Context, fn func(payload []byte)) error {
+	for ctx.Err() == nil {
+		if err := b.subscribe(ctx, fn); err != nil && ctx.Err() == nil {
+			log.Warnf("realtime: redis subscription failed, %s", err.Error())
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+		}
+	}
+
+	return nil
+}
+
+func (b *RedisBridge) subscribe(ctx context.Context, fn func(payload []byte)) error {
+	psc := redis.PubSubConn{Conn: b.Pool.Get()}
+	defer psc.Close()
+
+	if err := psc.Subscribe(b.Channel); err != nil {
+		return err
+	}
+
+	// unblock Receive when the context is done
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			psc.Unsubscribe()
+		case <-stop:
+		}
+	}()
+
+	for {
+		switch m := psc.Receive().(type) {
+		case redis.Message:
+			fn(m.Data)
+		case redis.Subscription:
+			if m.Count == 0 {
+				return nil
+			}
+		case error:
+			return m
+		}
+	}
+}
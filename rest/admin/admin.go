@@ -0,0 +1,284 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/enigma-id/go/rest"
+	"github.com/enigma-id/go/rest/mw"
+	"github.com/enigma-id/go/utility/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Errors
+var (
+	ErrUnknownLevel     = rest.NewHTTPError(http.StatusBadRequest, "admin: unknown log level")
+	ErrUnknownNamespace = rest.NewHTTPError(http.StatusNotFound, "admin: unknown cache namespace")
+	ErrUnknownQuota     = rest.NewHTTPError(http.StatusNotFound, "admin: quota is not used")
+)
+
+type (
+	// Config defines the config of the admin endpoints.
+	Config struct {
+		// Prefix of the admin endpoints.
+		// Optional. Default value "/_admin".
+		Prefix string
+
+		// Token that should be sent as bearer or X-Admin-Token header.
+		// Optional. Default value from env ADMIN_TOKEN, the endpoints
+		// are not registered when both token and middleware are empty.
+		Token string
+
+		// Middleware used instead of the token, ex. mw.JWT with role check.
+		// Optional.
+		Middleware []rest.MiddlewareFunc
+
+		// Caches is the flush function of each cache namespace.
+		// Optional.
+		Caches map[string]func() error
+
+		// Queues is the depth function of each queue.
+		// Optional.
+		Queues map[string]func() (int64, error)
+
+		// RateLimits is the store of RateLimit middleware, used
+		// to query the usage of the quota.
+		// Optional.
+		RateLimits mw.RateLimitStore
+
+		// Handlers registers additional endpoints into the admin group,
+		// ex. delivery logs of webhook.Dispatcher.
+		// Optional.
+		Handlers []rest.RouteHandlers
+	}
+
+	// status of the maintenance mode.
+	status struct {
+		Enabled bool   `json:"enabled"`
+		Message string `json:"message,omitempty"`
+	}
+)
+
+const headerToken = "X-Admin-Token"
+
+var (
+	// DefaultConfig is the default config of the admin endpoints.
+	DefaultConfig = Config{
+		Prefix: "/_admin",
+	}
+
+	mu          sync.RWMutex
+	maintenance status
+)
+
+// Register registers the admin endpoints into the rest instances:
+//
+//	GET  /_admin/log-level       {"level": "info"}
+//	PUT  /_admin/log-level       {"level": "debug"}
+//	GET  /_admin/maintenance     {"enabled": false}
+//	PUT  /_admin/maintenance     {"enabled": true, "message": "upgrading database"}
+//	GET  /_admin/routes
+//	POST /_admin/cache/:name/flush
+//	GET  /_admin/queues          {"email": 12}
+//	GET  /_admin/ratelimit/:key  {"key": "tenant:12", "limit": 100, "used": 12, ...}
+func Register(e *rest.Rest, config Config) *rest.Group {
+	if config.Prefix == "" {
+		config.Prefix = DefaultConfig.Prefix
+	}
+	if config.Token == "" {
+		config.Token = os.Getenv("ADMIN_TOKEN")
+	}
+
+	m := config.Middleware
+	if len(m) == 0 {
+		if config.Token == "" {
+			e.Logger.Warn("admin: endpoints are disabled, token is not configured")
+			return nil
+		}
+
+		m = []rest.MiddlewareFunc{authorize(config.Token)}
+	}
+
+	g := e.Group(config.Prefix, m...)
+	g.GET("/log-level", getLevel)
+	g.PUT("/log-level", setLevel)
+	g.GET("/maintenance", getMaintenance)
+	g.PUT("/maintenance", setMaintenance)
+	g.GET("/routes", routes)
+	g.POST("/cache/:name/flush", config.flush)
+	g.GET("/queues", config.queues)
+	if config.RateLimits != nil {
+		g.GET("/ratelimit/:key", config.rateLimit)
+	}
+	for _, h := range config.Handlers {
+		h.Route(g)
+	}
+
+	return g
+}
+
+// SetMaintenance toggles the maintenance mode, the message is
+// returned to the clients by Maintenance middleware.
+func SetMaintenance(enabled bool, message string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	maintenance = status{Enabled: enabled, Message: message}
+}
+
+// InMaintenance returns true when the maintenance mode is enabled.
+func InMaintenance() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return maintenance.Enabled
+}
+
+// Maintenance returns a middleware that responds 503 while the maintenance
+// mode is enabled, request to the admin endpoints are always passed.
+func Maintenance(prefix ...string) rest.MiddlewareFunc {
+	p := DefaultConfig.Prefix
+	if len(prefix) > 0 {
+		p = prefix[0]
+	}
+
+	return func(next rest.HandlerFunc) rest.HandlerFunc {
+		return func(c *rest.Context) error {
+			mu.RLock()
+			s := maintenance
+			mu.RUnlock()
+
+			path := c.Request().URL.Path
+			if !s.Enabled || path == p || strings.HasPrefix(path, p+"/") {
+				return next(c)
+			}
+
+			msg := s.Message
+			if msg == "" {
+				msg = "service is under maintenance"
+			}
+
+			return rest.NewHTTPError(http.StatusServiceUnavailable, msg)
+		}
+	}
+}
+
+// authorize checks the admin token of the request.
+func authorize(token string) rest.MiddlewareFunc {
+	return func(next rest.HandlerFunc) rest.HandlerFunc {
+		return func(c *rest.Context) error {
+			t := c.Request().Header.Get(headerToken)
+			if t == "" {
+				t = strings.TrimPrefix(c.Request().Header.Get(rest.HeaderAuthorization), "Bearer ")
+			}
+
+			if subtle.ConstantTimeCompare([]byte(t), []byte(token)) != 1 {
+				return rest.ErrUnauthorized
+			}
+
+			return next(c)
+		}
+	}
+}
+
+func getLevel(c *rest.Context) error {
+	return c.JSON(http.StatusOK, rest.Map{"level": log.Level.String()})
+}
+
+func setLevel(c *rest.Context) error {
+	var r struct {
+		Level string `json:"level"`
+	}
+	if err := c.Bind(&r); err != nil {
+		return err
+	}
+
+	var l zapcore.Level
+	if err := l.UnmarshalText([]byte(r.Level)); err != nil || r.Level == "" {
+		return ErrUnknownLevel
+	}
+
+	log.Level.SetLevel(l)
+	c.Logger().Info("admin: log level changed", zap.String("level", l.String()))
+
+	return getLevel(c)
+}
+
+func getMaintenance(c *rest.Context) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return c.JSON(http.StatusOK, maintenance)
+}
+
+func setMaintenance(c *rest.Context) error {
+	var s status
+	if err := c.Bind(&s); err != nil {
+		return err
+	}
+
+	SetMaintenance(s.Enabled, s.Message)
+
+	return getMaintenance(c)
+}
+
+func routes(c *rest.Context) error {
+	r := c.Rest().Routes()
+	sort.Slice(r, func(i, j int) bool {
+		if r[i].Path == r[j].Path {
+			return r[i].Method < r[j].Method
+		}
+		return r[i].Path < r[j].Path
+	})
+
+	return c.JSON(http.StatusOK, r)
+}
+
+func (config *Config) flush(c *rest.Context) error {
+	fn, ok := config.Caches[c.Param("name")]
+	if !ok {
+		return ErrUnknownNamespace
+	}
+
+	if err := fn(); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (config *Config) queues(c *rest.Context) error {
+	depths := make(map[string]int64, len(config.Queues))
+	for name, fn := range config.Queues {
+		n, err := fn()
+		if err != nil {
+			return err
+		}
+
+		depths[name] = n
+	}
+
+	return c.JSON(http.StatusOK, depths)
+}
+
+func (config *Config) rateLimit(c *rest.Context) error {
+	u, err := config.RateLimits.Usage(c.Param("key"))
+	if err != nil {
+		return err
+	}
+
+	if u == nil {
+		return ErrUnknownQuota
+	}
+
+	return c.JSON(http.StatusOK, u)
+}
@@ -0,0 +1,160 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enigma-id/go/rest"
+	"github.com/enigma-id/go/rest/mw"
+	"github.com/enigma-id/go/utility/log"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func request(e *rest.Rest, method, path, body, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set(rest.HeaderContentType, rest.MIMEApplicationJSON)
+	}
+	if token != "" {
+		req.Header.Set(rest.HeaderAuthorization, "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestRegisterWithoutToken(t *testing.T) {
+	e := rest.New()
+	assert.Nil(t, Register(e, Config{}))
+	assert.Len(t, e.Routes(), 0)
+}
+
+func TestAuthorize(t *testing.T) {
+	e := rest.New()
+	Register(e, Config{Token: "secret"})
+
+	assert.Equal(t, http.StatusUnauthorized, request(e, http.MethodGet, "/_admin/routes", "", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, request(e, http.MethodGet, "/_admin/routes", "", "wrong").Code)
+	assert.Equal(t, http.StatusOK, request(e, http.MethodGet, "/_admin/routes", "", "secret").Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/_admin/routes", nil)
+	req.Header.Set(headerToken, "secret")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestLogLevel(t *testing.T) {
+	defer log.Level.SetLevel(log.Level.Level())
+
+	e := rest.New()
+	Register(e, Config{Token: "secret"})
+
+	rec := request(e, http.MethodPut, "/_admin/log-level", `{"level":"warn"}`, "secret")
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		assert.Contains(t, rec.Body.String(), `"level":"warn"`)
+		assert.Equal(t, zap.WarnLevel, log.Level.Level())
+	}
+
+	rec = request(e, http.MethodPut, "/_admin/log-level", `{"level":"verbose"}`, "secret")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, zap.WarnLevel, log.Level.Level())
+}
+
+func TestMaintenance(t *testing.T) {
+	defer SetMaintenance(false, "")
+
+	e := rest.New()
+	e.Pre(Maintenance())
+	Register(e, Config{Token: "secret"})
+	e.GET("/users", func(c *rest.Context) error {
+		return c.String(http.StatusOK, "users")
+	})
+
+	assert.Equal(t, http.StatusOK, request(e, http.MethodGet, "/users", "", "").Code)
+
+	rec := request(e, http.MethodPut, "/_admin/maintenance", `{"enabled":true,"message":"upgrading"}`, "secret")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, InMaintenance())
+
+	rec = request(e, http.MethodGet, "/users", "", "")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), "upgrading")
+
+	// admin endpoints still served while maintenance
+	rec = request(e, http.MethodPut, "/_admin/maintenance", `{"enabled":false}`, "secret")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.False(t, InMaintenance())
+	assert.Equal(t, http.StatusOK, request(e, http.MethodGet, "/users", "", "").Code)
+}
+
+func TestCacheFlush(t *testing.T) {
+	var flushed []string
+	e := rest.New()
+	Register(e, Config{Token: "secret", Caches: map[string]func() error{
+		"product": func() error {
+			flushed = append(flushed, "product")
+			return nil
+		},
+		"broken": func() error {
+			return errors.New("connection refused")
+		},
+	}})
+
+	assert.Equal(t, http.StatusNoContent, request(e, http.MethodPost, "/_admin/cache/product/flush", "", "secret").Code)
+	assert.Equal(t, []string{"product"}, flushed)
+	assert.Equal(t, http.StatusNotFound, request(e, http.MethodPost, "/_admin/cache/user/flush", "", "secret").Code)
+	assert.Equal(t, http.StatusInternalServerError, request(e, http.MethodPost, "/_admin/cache/broken/flush", "", "secret").Code)
+}
+
+func TestQueuesAndRoutes(t *testing.T) {
+	e := rest.New()
+	Register(e, Config{Token: "secret", Queues: map[string]func() (int64, error){
+		"email": func() (int64, error) { return 12, nil },
+	}})
+
+	rec := request(e, http.MethodGet, "/_admin/queues", "", "secret")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"email":12}`, rec.Body.String())
+
+	rec = request(e, http.MethodGet, "/_admin/routes", "", "secret")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"path":"/_admin/queues"`)
+}
+
+func TestRateLimitUsage(t *testing.T) {
+	store := mw.NewRateLimitMemoryStore()
+	store.Take(mw.RateLimitPolicy{Key: "tenant:12", Limit: 100, Window: time.Minute})
+
+	e := rest.New()
+	Register(e, Config{Token: "secret", RateLimits: store})
+
+	rec := request(e, http.MethodGet, "/_admin/ratelimit/tenant:12", "", "secret")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"used":1,"remaining":99`)
+
+	assert.Equal(t, http.StatusNotFound, request(e, http.MethodGet, "/_admin/ratelimit/tenant:13", "", "secret").Code)
+}
+
+type pingHandlers struct{}
+
+func (pingHandlers) Route(g *rest.Group) {
+	g.GET("/ping", func(c *rest.Context) error {
+		return c.String(http.StatusOK, "pong")
+	})
+}
+
+func TestHandlers(t *testing.T) {
+	e := rest.New()
+	Register(e, Config{Token: "secret", Handlers: []rest.RouteHandlers{pingHandlers{}}})
+
+	assert.Equal(t, http.StatusUnauthorized, request(e, http.MethodGet, "/_admin/ping", "", "").Code)
+	assert.Equal(t, "pong", request(e, http.MethodGet, "/_admin/ping", "", "secret").Body.String())
+}
@@ -0,0 +1,254 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/enigma-id/go/cache"
+	"github.com/enigma-id/go/queue"
+)
+
+// Job states
+const (
+	JobPending = "pending"
+	JobRunning = "running"
+	JobDone    = "done"
+	JobFailed  = "failed"
+)
+
+// JobTopic is the queue topic of the async jobs.
+const JobTopic = "rest.async"
+
+// Errors
+var (
+	ErrJobsNotConfigured = errors.New("rest: async jobs is not configured")
+	ErrJobNotFound       = NewHTTPError(http.StatusNotFound, "job is not found")
+)
+
+type (
+	// AsyncFunc is the long running work of the async job, the returned
+	// value is stored as the result of the job. The context is not the
+	// request context, since the work continues after the response is sent.
+	AsyncFunc func(ctx context.Context) (interface{}, error)
+
+	// Job is the state of async job returned by the status handler.
+	Job struct {
+		ID        string          `json:"id"`
+		Status    string          `json:"status"`
+		StatusURL string          `json:"status_url"`
+		Result    json.RawMessage `json:"result,omitempty"`
+		Error     string          `json:"error,omitempty"`
+		CreatedAt time.Time       `json:"created_at"`
+		UpdatedAt time.Time       `json:"updated_at"`
+	}
+
+	// Jobs enqueues the work of Context.Async into the queue and keeps
+	// the state of the jobs in the cache. The work is a function so it's
+	// kept in the process, the queue should be processed by the same
+	// instance, ex. queue.Memory.
+	Jobs struct {
+		// Queue of the jobs, Handle should be registered as the handler of JobTopic.
+		Queue queue.Queue
+
+		// Cache where the state of the jobs is stored.
+		Cache cache.Cache
+
+		// TTL of the job state.
+		// Optional. Default value 24 hours.
+		TTL time.Duration
+
+		// Path of the status handler, the status url is "<path>/<id>".
+		// Optional. Default value "/jobs".
+		Path string
+
+		mu   sync.Mutex
+		work map[string]AsyncFunc
+	}
+)
+
+// NewJobs creates async jobs on the queue, the handler of the topic
+// is registered when the queue is queue.Memory.
+//
+//	q := queue.NewMemory(4)
+//	e.Jobs = rest.NewJobs(q, cache.Instance)
+//	e.GET("/jobs/:id", e.Jobs.Status)
+func NewJobs(q queue.Queue, c cache.Cache) *Jobs {
+	j := &Jobs{
+		Queue: q,
+		Cache: c,
+		TTL:   24 * time.Hour,
+		Path:  "/jobs",
+		work:  make(map[string]AsyncFunc),
+	}
+
+	if m, ok := q.(*queue.Memory); ok {
+		m.Handle(JobTopic, j.Handle)
+	}
+
+	return j
+}
+
+// Async enqueues the work and responds 202 with the job and its status url
+// in Location header, so client can poll the state of the job.
+//
+//	r.POST("/reports/export", func(c *rest.Context) error {
+//		return c.Async(func(ctx context.Context) (interface{}, error) {
+//			return exportReport(ctx, month)
+//		})
+//	})
+func (c *Context) Async(fn AsyncFunc) error {
+	j := c.rest.Jobs
+	if j == nil {
+		return ErrJobsNotConfigured
+	}
+
+	job, err := j.Enqueue(fn)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(HeaderLocation, job.StatusURL)
+
+	return c.JSON(http.StatusAccepted, job)
+}
+
+// Enqueue stores pending job of the work and enqueues it.
+func (j *Jobs) Enqueue(fn AsyncFunc) (*Job, error) {
+	// the id is the only credential of the status url
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	job := &Job{
+		ID:        hex.EncodeToString(id),
+		Status:    JobPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	job.StatusURL = strings.TrimRight(j.Path, "/") + "/" + job.ID
+
+	if err := j.save(job); err != nil {
+		return nil, err
+	}
+
+	j.mu.Lock()
+	j.work[job.ID] = fn
+	j.mu.Unlock()
+
+	if err := j.Queue.Enqueue(JobTopic, []byte(job.ID)); err != nil {
+		j.take(job.ID)
+		j.Cache.Delete(j.key(job.ID))
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Handle runs the work of the queued job, it's the queue handler of JobTopic.
+func (j *Jobs) Handle(ctx context.Context, payload []byte) (err error) {
+	job, err := j.Get(string(payload))
+	if err != nil {
+		return err
+	}
+
+	fn := j.take(job.ID)
+	if fn == nil {
+		job.Status, job.Error = JobFailed, "job is lost"
+		return j.save(job)
+	}
+
+	job.Status = JobRunning
+	if err = j.save(job); err != nil {
+		return err
+	}
+
+	result, err := j.run(ctx, fn)
+	if err == nil {
+		job.Result, err = json.Marshal(result)
+	}
+
+	job.Status = JobDone
+	if err != nil {
+		job.Status, job.Error = JobFailed, err.Error()
+	}
+
+	return j.save(job)
+}
+
+// Get returns the state of the job.
+func (j *Jobs) Get(id string) (*Job, error) {
+	var b []byte
+	if err := j.Cache.Get(j.key(id), &b); err != nil {
+		if err == cache.ErrCacheMiss {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+
+	job := new(Job)
+	if err := json.Unmarshal(b, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Status is the handler that responds the state of the job,
+// the job id is taken from "id" param.
+func (j *Jobs) Status(c *Context) error {
+	job, err := j.Get(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, job)
+}
+
+func (j *Jobs) run(ctx context.Context, fn AsyncFunc) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panics: %v", r)
+		}
+	}()
+
+	return fn(ctx)
+}
+
+func (j *Jobs) take(id string) AsyncFunc {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	fn := j.work[id]
+	delete(j.work, id)
+
+	return fn
+}
+
+// save stores the job as json, so the result doesn't need to be gob encodable.
+func (j *Jobs) save(job *Job) error {
+	job.UpdatedAt = time.Now()
+	b, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	return j.Cache.Set(j.key(job.ID), b, j.TTL)
+}
+
+func (j *Jobs) key(id string) string {
+	return JobTopic + ":" + id
+}
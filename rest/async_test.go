@@ -0,0 +1,121 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/enigma-id/go/cache"
+	"github.com/enigma-id/go/queue"
+	"github.com/stretchr/testify/assert"
+)
+
+// jobCache is minimal cache.Cache for testing.
+type jobCache struct {
+	sync.Mutex
+	m map[string][]byte
+}
+
+func (c *jobCache) Get(key string, ptr interface{}) error {
+	c.Lock()
+	defer c.Unlock()
+	b, ok := c.m[key]
+	if !ok {
+		return cache.ErrCacheMiss
+	}
+	return cache.Deserialize(b, ptr)
+}
+
+func (c *jobCache) Set(key string, value interface{}, _ time.Duration) error {
+	b, err := cache.Serialize(value)
+	if err != nil {
+		return err
+	}
+	c.Lock()
+	defer c.Unlock()
+	c.m[key] = b
+	return nil
+}
+
+func (c *jobCache) Add(key string, value interface{}, d time.Duration) error {
+	return c.Set(key, value, d)
+}
+func (c *jobCache) Replace(key string, value interface{}, d time.Duration) error {
+	return c.Set(key, value, d)
+}
+func (c *jobCache) Delete(key string) error {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.m, key)
+	return nil
+}
+func (c *jobCache) GetMulti(keys ...string) (cache.Getter, error) { return c, nil }
+func (c *jobCache) Flush() error                                  { return nil }
+
+func TestAsync(t *testing.T) {
+	q := queue.NewMemory(1)
+	e := New()
+	e.Jobs = NewJobs(q, &jobCache{m: make(map[string][]byte)})
+	e.GET("/jobs/:id", e.Jobs.Status)
+
+	release := make(chan struct{})
+	e.POST("/exports", func(c *Context) error {
+		return c.Async(func(ctx context.Context) (interface{}, error) {
+			<-release
+			return Map{"rows": 12}, nil
+		})
+	})
+	e.POST("/imports", func(c *Context) error {
+		return c.Async(func(ctx context.Context) (interface{}, error) {
+			return nil, errors.New("invalid file")
+		})
+	})
+
+	request := func(method, path string) (*httptest.ResponseRecorder, *Job) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		job := new(Job)
+		json.Unmarshal(rec.Body.Bytes(), job)
+		return rec, job
+	}
+
+	rec, job := request(http.MethodPost, "/exports")
+	if assert.Equal(t, http.StatusAccepted, rec.Code) {
+		assert.Equal(t, JobPending, job.Status)
+		assert.Equal(t, "/jobs/"+job.ID, job.StatusURL)
+		assert.Equal(t, job.StatusURL, rec.Header().Get(HeaderLocation))
+	}
+
+	_, state := request(http.MethodGet, job.StatusURL)
+	assert.Contains(t, []string{JobPending, JobRunning}, state.Status)
+
+	close(release)
+	_, failed := request(http.MethodPost, "/imports")
+	q.Close()
+
+	rec, state = request(http.MethodGet, job.StatusURL)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		assert.Equal(t, JobDone, state.Status)
+		assert.JSONEq(t, `{"rows":12}`, string(state.Result))
+	}
+
+	_, state = request(http.MethodGet, failed.StatusURL)
+	assert.Equal(t, JobFailed, state.Status)
+	assert.Equal(t, "invalid file", state.Error)
+
+	rec, _ = request(http.MethodGet, "/jobs/unknown")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestAsyncNotConfigured(t *testing.T) {
+	e := New()
+	c := e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), httptest.NewRecorder())
+	assert.Equal(t, ErrJobsNotConfigured, c.Async(func(ctx context.Context) (interface{}, error) {
+		return nil, nil
+	}))
+}
@@ -0,0 +1,78 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/tinylib/msgp/msgp"
+	"google.golang.org/protobuf/proto"
+)
+
+// isProtobuf returns true when the content type is protobuf.
+func isProtobuf(ctype string) bool {
+	return strings.HasPrefix(ctype, MIMEApplicationProtobuf) || strings.HasPrefix(ctype, MIMEApplicationXProtobuf)
+}
+
+// isMsgpack returns true when the content type is msgpack.
+func isMsgpack(ctype string) bool {
+	return strings.HasPrefix(ctype, MIMEApplicationMsgpack) || strings.HasPrefix(ctype, MIMEApplicationXMsgpack)
+}
+
+// bindBinary decodes protobuf body when `i` implements proto.Message
+// and msgpack body when `i` implements msgp.Unmarshaler (generated by msgp).
+func (b *DefaultBinder) bindBinary(i interface{}, c *Context, ctype string) error {
+	var decode func(b []byte) error
+	switch {
+	case isProtobuf(ctype):
+		m, ok := i.(proto.Message)
+		if !ok {
+			return ErrUnsupportedMediaType
+		}
+		decode = func(b []byte) error { return proto.Unmarshal(b, m) }
+	case isMsgpack(ctype):
+		m, ok := i.(msgp.Unmarshaler)
+		if !ok {
+			return ErrUnsupportedMediaType
+		}
+		decode = func(b []byte) error {
+			_, err := m.UnmarshalMsg(b)
+			return err
+		}
+	}
+
+	data, err := b.readBody(c)
+	if err != nil {
+		return err
+	}
+
+	if err = decode(data); err != nil {
+		return NewHTTPError(http.StatusBadRequest, "Invalid request body format").SetInternal(err)
+	}
+
+	return nil
+}
+
+// Protobuf sends protobuf encoded response with status code.
+func (c *Context) Protobuf(code int, m proto.Message) error {
+	b, err := proto.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	return c.Blob(code, MIMEApplicationXProtobuf, b)
+}
+
+// Msgpack sends msgpack encoded response with status code,
+// `m` is usually generated by msgp.
+func (c *Context) Msgpack(code int, m msgp.Marshaler) error {
+	b, err := m.MarshalMsg(nil)
+	if err != nil {
+		return err
+	}
+
+	return c.Blob(code, MIMEApplicationMsgpack, b)
+}
@@ -0,0 +1,114 @@
+package rest
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tinylib/msgp/msgp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// msgpUser implements msgp interfaces as generated by msgp.
+type msgpUser struct {
+	Name string `valid:"required"`
+}
+
+func (u *msgpUser) MarshalMsg(b []byte) ([]byte, error) {
+	b = msgp.AppendMapHeader(b, 1)
+	b = msgp.AppendString(b, "name")
+	return msgp.AppendString(b, u.Name), nil
+}
+
+func (u *msgpUser) UnmarshalMsg(b []byte) ([]byte, error) {
+	n, b, err := msgp.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for ; n > 0; n-- {
+		var k string
+		if k, b, err = msgp.ReadStringBytes(b); err != nil {
+			return b, err
+		}
+		if k == "name" {
+			if u.Name, b, err = msgp.ReadStringBytes(b); err != nil {
+				return b, err
+			}
+		} else if b, err = msgp.Skip(b); err != nil {
+			return b, err
+		}
+	}
+	return b, nil
+}
+
+func binaryContext(e *Rest, ctype string, body []byte) (*Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	req.Header.Set(HeaderContentType, ctype)
+	rec := httptest.NewRecorder()
+	return e.NewContext(req, rec), rec
+}
+
+func TestBindProtobuf(t *testing.T) {
+	e := New()
+	body, _ := proto.Marshal(wrapperspb.String("Jon"))
+
+	for _, ctype := range []string{MIMEApplicationXProtobuf, MIMEApplicationProtobuf} {
+		c, _ := binaryContext(e, ctype, body)
+		m := new(wrapperspb.StringValue)
+		if assert.NoError(t, c.Bind(m)) {
+			assert.Equal(t, "Jon", m.GetValue())
+		}
+	}
+
+	c, _ := binaryContext(e, MIMEApplicationXProtobuf, []byte{0xff, 0xff})
+	err := c.Bind(new(wrapperspb.StringValue))
+	if assert.IsType(t, &HTTPError{}, err) {
+		assert.Equal(t, http.StatusBadRequest, err.(*HTTPError).Code)
+	}
+
+	// body size
+	e.Binder.(*DefaultBinder).MaxBytes = int64(len(body) - 1)
+	c, _ = binaryContext(e, MIMEApplicationXProtobuf, body)
+	assert.Equal(t, ErrStatusRequestEntityTooLarge, c.Bind(new(wrapperspb.StringValue)))
+	e.Binder.(*DefaultBinder).MaxBytes = 0
+
+	// target doesn't implement proto.Message
+	c, _ = binaryContext(e, MIMEApplicationXProtobuf, body)
+	assert.Equal(t, ErrUnsupportedMediaType, c.Bind(new(msgpUser)))
+
+	c, rec := binaryContext(e, MIMEApplicationXProtobuf, body)
+	if assert.NoError(t, c.Protobuf(http.StatusOK, wrapperspb.String("Jon"))) {
+		assert.Equal(t, MIMEApplicationXProtobuf, rec.Header().Get(HeaderContentType))
+		m := new(wrapperspb.StringValue)
+		assert.NoError(t, proto.Unmarshal(rec.Body.Bytes(), m))
+		assert.Equal(t, "Jon", m.GetValue())
+	}
+}
+
+func TestBindMsgpack(t *testing.T) {
+	e := New()
+	body, _ := (&msgpUser{Name: "Jon"}).MarshalMsg(nil)
+
+	c, _ := binaryContext(e, MIMEApplicationMsgpack, body)
+	u := new(msgpUser)
+	if assert.NoError(t, c.Bind(u)) {
+		assert.Equal(t, "Jon", u.Name)
+	}
+
+	// validated after decoding
+	empty, _ := (&msgpUser{}).MarshalMsg(nil)
+	c, _ = binaryContext(e, MIMEApplicationXMsgpack, empty)
+	assert.Error(t, c.Bind(new(msgpUser)))
+
+	c, _ = binaryContext(e, MIMEApplicationMsgpack, body)
+	assert.Equal(t, ErrUnsupportedMediaType, c.Bind(new(wrapperspb.StringValue)))
+
+	c, rec := binaryContext(e, MIMEApplicationMsgpack, nil)
+	if assert.NoError(t, c.Msgpack(http.StatusOK, &msgpUser{Name: "Jon"})) {
+		assert.Equal(t, MIMEApplicationMsgpack, rec.Header().Get(HeaderContentType))
+		assert.Equal(t, body, rec.Body.Bytes())
+	}
+}
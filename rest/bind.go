@@ -3,6 +3,8 @@ package rest
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"reflect"
 	"strconv"
@@ -12,6 +14,25 @@ import (
 	"github.com/enigma-id/go/validation"
 )
 
+// MaxBindIndex is the maximum index of nested slice binding,
+// ex. items[999][sku], to prevent huge allocation.
+var MaxBindIndex = 1000
+
+// ValidatorOptions are the options of the validator used by the binder,
+// ex. validation.JSONKeys() or validation.FailFast().
+var ValidatorOptions []validation.Option
+
+// MaxBindDepth is the default maximum nesting depth of json body.
+var MaxBindDepth = 32
+
+// MaxBindElements is the default maximum elements of each json array,
+// zero is unlimited.
+var MaxBindElements = 0
+
+// MaxBindBytes is the default maximum size of json and binary body,
+// zero is unlimited.
+var MaxBindBytes int64 = 0
+
 type (
 	// Binder is the interface that wraps the Bind method.
 	Binder interface {
@@ -19,7 +40,28 @@ type (
 	}
 
 	// DefaultBinder is the default implementation of the Binder interface.
-	DefaultBinder struct{}
+	DefaultBinder struct {
+		// MaxDepth is the maximum nesting depth of json body,
+		// MaxBindDepth when zero and unlimited when negative.
+		MaxDepth int
+
+		// MaxElements is the maximum elements of each json array,
+		// MaxBindElements when zero and unlimited when negative.
+		MaxElements int
+
+		// MaxBytes is the maximum size of json and binary body, the body is
+		// read before it's decoded. MaxBindBytes when zero and unlimited when negative.
+		MaxBytes int64
+
+		mu    sync.RWMutex
+		types map[reflect.Type]TypeBinder
+	}
+
+	// TypeBinder converts query, form or path value into the registered type.
+	TypeBinder func(value string) (interface{}, error)
+
+	// BindHook is called before or after the request is bound into `i`.
+	BindHook func(c *Context, i interface{}) error
 
 	// BindUnmarshaler is the interface used to wrap the UnmarshalParam method.
 	BindUnmarshaler interface {
@@ -36,15 +78,19 @@ type (
 
 // Bind implements the `Binder#Bind` function.
 func (b *DefaultBinder) Bind(i interface{}, c *Context) (err error) {
+	// fields restricted by bind tag are restored after decoding
+	g := guardFields(i, c.bindProfile)
+
 	req := c.Request()
 	if req.ContentLength == 0 {
 		if req.Method == http.MethodGet || req.Method == http.MethodDelete {
 			if err = b.bindData(i, c.QueryParams(), "query"); err != nil {
 				err = NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 			}
+			g.restore(i)
 
 			if req.Method == http.MethodDelete {
-				err = c.validator.Validate(i)
+				err = c.Validate(i)
 			}
 
 		} else {
@@ -54,16 +100,25 @@ func (b *DefaultBinder) Bind(i interface{}, c *Context) (err error) {
 		ctype := req.Header.Get(HeaderContentType)
 
 		if strings.HasPrefix(ctype, MIMEApplicationJSON) {
-			if err = json.NewDecoder(req.Body).Decode(i); err != nil {
-				if _, ok := err.(*json.UnmarshalTypeError); ok {
-					err = NewHTTPError(http.StatusBadRequest, "Incorrect data structure")
-				} else if _, ok := err.(*json.SyntaxError); ok {
-					err = NewHTTPError(http.StatusBadRequest, "Invalid JSON format")
-				}
+			err = b.bindJSON(i, c)
+			g.restore(i)
+
+			if err == nil {
+				err = validateBody(i, c)
+			}
+		} else if strings.HasPrefix(ctype, MIMETextCSV) {
+			err = b.bindCSV(i, c)
+			g.restore(i)
+
+			if err == nil {
+				err = validateBody(i, c)
+			}
+		} else if isProtobuf(ctype) || isMsgpack(ctype) {
+			err = b.bindBinary(i, c, ctype)
+			g.restore(i)
 
-				err = NewHTTPError(http.StatusBadRequest, err.Error())
-			} else {
-				err = c.validator.Validate(i)
+			if err == nil {
+				err = c.Validate(i)
 			}
 		} else {
 			err = ErrUnsupportedMediaType
@@ -73,6 +128,91 @@ func (b *DefaultBinder) Bind(i interface{}, c *Context) (err error) {
 	return
 }
 
+// readBody reads the request body, ErrStatusRequestEntityTooLarge is
+// returned when it's over MaxBytes.
+func (b *DefaultBinder) readBody(c *Context) ([]byte, error) {
+	var body io.Reader = c.Request().Body
+	max := b.MaxBytes
+	if max == 0 {
+		max = MaxBindBytes
+	}
+	if max > 0 {
+		// one more byte tells the body is over the limit
+		body = io.LimitReader(body, max+1)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	if max > 0 && int64(len(data)) > max {
+		return nil, ErrStatusRequestEntityTooLarge
+	}
+
+	return data, nil
+}
+
+// Register registers binding function of the type of `v`, it's used when
+// binding query, form and path values into fields of the type, ex.
+//
+//	b.Register(Money{}, func(v string) (interface{}, error) { return ParseMoney(v) })
+//
+// json body is decoded by encoding/json, so the type should also
+// implement json.Unmarshaler to be bound from json.
+func (b *DefaultBinder) Register(v interface{}, fn TypeBinder) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.types == nil {
+		b.types = make(map[reflect.Type]TypeBinder)
+	}
+	b.types[reflect.TypeOf(v)] = fn
+}
+
+// bindType binds the value using the registered binding function
+// of the field type, returns false when there is none.
+func (b *DefaultBinder) bindType(val string, field reflect.Value) (bool, error) {
+	typ := field.Type()
+	ptr := typ.Kind() == reflect.Ptr
+
+	b.mu.RLock()
+	fn, ok := b.types[typ]
+	if !ok && ptr {
+		fn, ok = b.types[typ.Elem()]
+	} else {
+		ptr = false
+	}
+	b.mu.RUnlock()
+
+	if !ok {
+		return false, nil
+	}
+
+	if ptr {
+		if field.IsNil() {
+			field.Set(reflect.New(typ.Elem()))
+		}
+		field = field.Elem()
+	}
+
+	return b.setType(fn, val, field)
+}
+
+func (b *DefaultBinder) setType(fn TypeBinder, val string, field reflect.Value) (bool, error) {
+	v, err := fn(val)
+	if err != nil {
+		return true, err
+	}
+
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || !rv.Type().AssignableTo(field.Type()) {
+		return true, errors.New("binding function returns invalid type of " + field.Type().String())
+	}
+	field.Set(rv)
+
+	return true, nil
+}
+
 func (b *DefaultBinder) bindData(ptr interface{}, data map[string][]string, tag string) error {
 	typ := reflect.TypeOf(ptr).Elem()
 	val := reflect.ValueOf(ptr).Elem()
@@ -101,6 +241,14 @@ func (b *DefaultBinder) bindData(ptr interface{}, data map[string][]string, tag
 			}
 		}
 
+		// nested values, ex. items[0][sku]=A or address.city=Jakarta
+		if ok, err := b.bindNested(structField, inputFieldName, data, tag); ok {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
 		inputValue, exists := data[inputFieldName]
 		if !exists {
 			// Go json.Unmarshal supports case insensitive binding.  However the
@@ -121,6 +269,13 @@ func (b *DefaultBinder) bindData(ptr interface{}, data map[string][]string, tag
 			continue
 		}
 
+		if ok, err := b.bindType(inputValue[0], structField); ok {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
 		// Call this first, in case we're dealing with an alias to an array type
 		if ok, err := unmarshalField(typeField.Type.Kind(), inputValue[0], structField); ok {
 			if err != nil {
@@ -134,6 +289,13 @@ func (b *DefaultBinder) bindData(ptr interface{}, data map[string][]string, tag
 			sliceOf := structField.Type().Elem().Kind()
 			slice := reflect.MakeSlice(structField.Type(), numElems, numElems)
 			for j := 0; j < numElems; j++ {
+				if ok, err := b.bindType(inputValue[j], slice.Index(j)); ok {
+					if err != nil {
+						return err
+					}
+					continue
+				}
+
 				if err := setWithProperType(sliceOf, inputValue[j], slice.Index(j)); err != nil {
 					return err
 				}
@@ -147,17 +309,187 @@ func (b *DefaultBinder) bindData(ptr interface{}, data map[string][]string, tag
 	return nil
 }
 
+// bindNested binds values of struct or slice of struct field using bracket
+// (items[0][sku]) or dot (items.0.sku) notation, returns false when
+// the field is not nested or there is no nested value.
+func (b *DefaultBinder) bindNested(field reflect.Value, name string, data map[string][]string, tag string) (bool, error) {
+	typ := field.Type()
+	ptr := typ.Kind() == reflect.Ptr
+	if ptr {
+		typ = typ.Elem()
+	}
+
+	switch {
+	case typ.Kind() == reflect.Struct:
+		if _, ok := bindUnmarshaler(reflect.New(typ).Elem()); ok {
+			return false, nil
+		}
+
+		sub := nestedData(data, name)
+		if len(sub) == 0 {
+			return false, nil
+		}
+
+		if ptr {
+			if field.IsNil() {
+				field.Set(reflect.New(typ))
+			}
+			field = field.Elem()
+		}
+
+		return true, b.bindData(field.Addr().Interface(), sub, tag)
+
+	case typ.Kind() == reflect.Slice && !ptr:
+		elem := typ.Elem()
+		elemPtr := elem.Kind() == reflect.Ptr
+		if elemPtr {
+			elem = elem.Elem()
+		}
+		if elem.Kind() != reflect.Struct {
+			return false, nil
+		}
+
+		groups := make(map[int]map[string][]string)
+		max := -1
+		for k, v := range nestedData(data, name) {
+			i := strings.Index(k, ".")
+			if i < 0 {
+				continue
+			}
+
+			n, err := strconv.Atoi(k[:i])
+			if err != nil || n < 0 {
+				continue
+			}
+			if n >= MaxBindIndex {
+				return true, fmt.Errorf("index %d of %s exceeds the maximum", n, name)
+			}
+
+			if groups[n] == nil {
+				groups[n] = make(map[string][]string)
+			}
+			groups[n][k[i+1:]] = v
+
+			if n > max {
+				max = n
+			}
+		}
+
+		if max < 0 {
+			return false, nil
+		}
+
+		slice := reflect.MakeSlice(typ, max+1, max+1)
+		for n, d := range groups {
+			e := slice.Index(n)
+			if elemPtr {
+				e.Set(reflect.New(elem))
+				e = e.Elem()
+			}
+
+			if err := b.bindData(e.Addr().Interface(), d, tag); err != nil {
+				return true, err
+			}
+		}
+		field.Set(slice)
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// nestedData returns values of the keys under the name, the keys are
+// normalized into dot notation and the name prefix is removed.
+func nestedData(data map[string][]string, name string) map[string][]string {
+	prefix := strings.ToLower(name) + "."
+
+	var sub map[string][]string
+	for k, v := range data {
+		if strings.ContainsAny(k, "[") {
+			k = strings.NewReplacer("][", ".", "].", ".", "[", ".", "]", "").Replace(k)
+		}
+
+		if len(k) > len(prefix) && strings.ToLower(k[:len(prefix)]) == prefix {
+			if sub == nil {
+				sub = make(map[string][]string)
+			}
+			sub[k[len(prefix):]] = v
+		}
+	}
+
+	return sub
+}
+
+// unmarshalTypeError converts json type error into bad request, the field
+// is reported using the same key format as validation errors, ex.
+// "items.2.qty": "must be a number".
+func unmarshalTypeError(ute *json.UnmarshalTypeError) error {
+	he := NewHTTPError(http.StatusBadRequest, "Incorrect data structure")
+	if ute.Field == "" {
+		return he
+	}
+
+	rule, msg := "type", "has invalid type"
+	switch ute.Type.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		rule, msg = "number", "must be a number"
+	case reflect.String:
+		rule, msg = "string", "must be a string"
+	case reflect.Bool:
+		rule, msg = "boolean", "must be a boolean"
+	case reflect.Slice, reflect.Array:
+		rule, msg = "array", "must be an array"
+	case reflect.Struct, reflect.Map:
+		rule, msg = "object", "must be an object"
+	}
+
+	return he.SetInternal(validation.SetError(ute.Field+"."+rule, msg))
+}
+
+// validationErrors returns validation response of the error,
+// it could be the error itself or internal error of HTTPError.
+func validationErrors(err error) *validation.Response {
+	if he, ok := err.(*HTTPError); ok {
+		err = he.Internal
+	}
+
+	o, _ := err.(*validation.Response)
+	return o
+}
+
 // Validate the request when binding
 func (v *binderValidator) Validate(obj interface{}) (err error) {
+	return v.validate(obj, nil)
+}
+
+// ValidateMeta implements MetaValidator interfaces.
+func (v *binderValidator) ValidateMeta(obj interface{}, m validation.Meta) (err error) {
+	return v.validate(obj, &m)
+}
+
+func (v *binderValidator) validate(obj interface{}, m *validation.Meta) (err error) {
 	v.lazyinit()
 
 	var o *validation.Response
-	if vr, ok := obj.(validation.Request); ok {
+	vr, ok := obj.(validation.Request)
+	switch {
+	case ok && m != nil:
+		o = v.validator.RequestMeta(vr, *m)
+	case ok:
 		o = v.validator.Request(vr)
-	} else {
+	case m != nil:
+		o = v.validator.StructMeta(obj, *m)
+	default:
 		o = v.validator.Struct(obj)
 	}
 
+	if o.Err() != nil {
+		// lookup of the rule failed, ex. database is down
+		return o.Err()
+	}
 	if !o.Valid {
 		err = o
 	}
@@ -168,7 +500,7 @@ func (v *binderValidator) Validate(obj interface{}) (err error) {
 // lazyinit initialing validator instances for one of time only.
 func (v *binderValidator) lazyinit() {
 	v.once.Do(func() {
-		v.validator = validation.New()
+		v.validator = validation.New(ValidatorOptions...)
 	})
 }
 
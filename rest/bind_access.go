@@ -0,0 +1,166 @@
+package rest
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// BindTag is the struct tag restricting the fields that can be bound from
+// the request, "-" is never bound and "admin,system" is only bound when
+// the handler binds with one of the profiles using BindProfile.
+//
+//	type User struct {
+//		Name    string `json:"name"`
+//		Role    string `json:"role" bind:"admin"`
+//		Balance int64  `json:"balance" bind:"-"`
+//	}
+const BindTag = "bind"
+
+// bindGuard keeps values of the protected fields before binding.
+type bindGuard struct {
+	profile string
+	saved   map[string]reflect.Value
+}
+
+// BindProfile binds the request like Bind, fields tagged with the profile
+// are also bound, ex. c.BindProfile("admin", u) for admin endpoints.
+func (c *Context) BindProfile(profile string, i interface{}) error {
+	c.bindProfile = profile
+	defer func() {
+		c.bindProfile = ""
+	}()
+
+	return c.Bind(i)
+}
+
+// guardFields saves the protected fields of `i` so it can be restored
+// after the request is decoded. The values are deep copied since the
+// decoders write into the existing slices, maps and pointees.
+func guardFields(i interface{}, profile string) *bindGuard {
+	g := &bindGuard{profile: profile}
+	seen := make(map[uintptr]reflect.Value)
+	g.walk(reflect.ValueOf(i), "", func(path string, f reflect.Value) {
+		if g.saved == nil {
+			g.saved = make(map[string]reflect.Value)
+		}
+
+		g.saved[path] = deepCopy(f, seen)
+	})
+
+	return g
+}
+
+// restore sets the protected fields back into their values before binding,
+// fields that didn't exist before, ex. new slice items, are zeroed.
+func (g *bindGuard) restore(i interface{}) {
+	g.walk(reflect.ValueOf(i), "", func(path string, f reflect.Value) {
+		if v, ok := g.saved[path]; ok {
+			f.Set(v)
+		} else {
+			f.Set(reflect.Zero(f.Type()))
+		}
+	})
+}
+
+// walk calls fn for every protected field of the value.
+func (g *bindGuard) walk(v reflect.Value, path string, fn func(string, reflect.Value)) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := v.Field(i)
+			if !f.CanSet() {
+				continue
+			}
+
+			p := path + "." + strconv.Itoa(i)
+			if tag, ok := t.Field(i).Tag.Lookup(BindTag); ok && !g.allowed(tag) {
+				fn(p, f)
+				continue
+			}
+
+			g.walk(f, p, fn)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			g.walk(v.Index(i), path+"["+strconv.Itoa(i)+"]", fn)
+		}
+	}
+}
+
+func (g *bindGuard) allowed(tag string) bool {
+	if tag == "-" || g.profile == "" {
+		return false
+	}
+
+	for _, p := range strings.Split(tag, ",") {
+		if strings.TrimSpace(p) == g.profile {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deepCopy returns a copy of the value that shares no slices, maps or
+// pointees with it, seen keeps the copied pointers so cycles are preserved.
+// Unexported fields are copied shallowly, the decoders can't set them.
+func deepCopy(v reflect.Value, seen map[uintptr]reflect.Value) reflect.Value {
+	c := reflect.New(v.Type()).Elem()
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return c
+		}
+		if p, ok := seen[v.Pointer()]; ok {
+			return p
+		}
+		c.Set(reflect.New(v.Type().Elem()))
+		seen[v.Pointer()] = c
+		c.Elem().Set(deepCopy(v.Elem(), seen))
+	case reflect.Interface:
+		if !v.IsNil() {
+			c.Set(deepCopy(v.Elem(), seen))
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			return c
+		}
+		c.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i), seen))
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i), seen))
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			return c
+		}
+		c.Set(reflect.MakeMapWithSize(v.Type(), v.Len()))
+		for _, k := range v.MapKeys() {
+			c.SetMapIndex(k, deepCopy(v.MapIndex(k), seen))
+		}
+	case reflect.Struct:
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(deepCopy(v.Field(i), seen))
+			}
+		}
+	default:
+		c.Set(v)
+	}
+
+	return c
+}
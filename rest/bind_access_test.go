@@ -0,0 +1,99 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	bindAccessAddress struct {
+		City     string `json:"city"`
+		Verified bool   `json:"verified" bind:"-"`
+	}
+
+	bindAccessUser struct {
+		Name      string               `json:"name" query:"name"`
+		Role      string               `json:"role" query:"role" bind:"admin"`
+		Balance   int64                `json:"balance" query:"balance" bind:"-"`
+		Address   *bindAccessAddress   `json:"address"`
+		Addresses []*bindAccessAddress `json:"addresses"`
+	}
+
+	bindAccessProfile struct {
+		Role string `json:"role"`
+	}
+
+	bindAccessAccount struct {
+		Name    string             `json:"name"`
+		Roles   []string           `json:"roles" bind:"-"`
+		Profile *bindAccessProfile `json:"profile" bind:"-"`
+		Limits  map[string]int     `json:"limits" bind:"-"`
+		Tags    [2]string          `json:"tags" bind:"-"`
+	}
+)
+
+func TestBindAccess(t *testing.T) {
+	e := New()
+	body := `{"name":"Jon","role":"admin","balance":1000,"address":{"city":"Jakarta","verified":true},` +
+		`"addresses":[{"city":"Bandung","verified":true}]}`
+
+	newContext := func() *Context {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+		return e.NewContext(req, httptest.NewRecorder())
+	}
+
+	u := &bindAccessUser{Role: "member", Balance: 10, Address: &bindAccessAddress{Verified: false}}
+	if assert.NoError(t, newContext().Bind(u)) {
+		assert.Equal(t, "Jon", u.Name)
+		assert.Equal(t, "member", u.Role)
+		assert.Equal(t, int64(10), u.Balance)
+		assert.Equal(t, &bindAccessAddress{City: "Jakarta"}, u.Address)
+		assert.Equal(t, []*bindAccessAddress{{City: "Bandung"}}, u.Addresses)
+	}
+
+	u = &bindAccessUser{Role: "member", Balance: 10}
+	c := newContext()
+	if assert.NoError(t, c.BindProfile("admin", u)) {
+		assert.Equal(t, "admin", u.Role)
+		assert.Equal(t, int64(10), u.Balance)
+		assert.False(t, u.Address.Verified)
+	}
+	assert.Empty(t, c.bindProfile)
+}
+
+func TestBindAccessLoaded(t *testing.T) {
+	e := New()
+	body := `{"name":"Jon","roles":["admin"],"profile":{"role":"admin"},"limits":{"transfer":0,"withdraw":9},"tags":["a","b"]}`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	roles := []string{"user"}
+	profile := &bindAccessProfile{Role: "user"}
+	limits := map[string]int{"transfer": 100}
+	u := &bindAccessAccount{Roles: roles, Profile: profile, Limits: limits, Tags: [2]string{"x"}}
+	if assert.NoError(t, c.Bind(u)) {
+		assert.Equal(t, "Jon", u.Name)
+		assert.Equal(t, []string{"user"}, u.Roles)
+		assert.Equal(t, &bindAccessProfile{Role: "user"}, u.Profile)
+		assert.Equal(t, map[string]int{"transfer": 100}, u.Limits)
+		assert.Equal(t, [2]string{"x"}, u.Tags)
+	}
+}
+
+func TestBindAccessQuery(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/?name=Jon&role=admin&balance=1000", nil)
+
+	u := new(bindAccessUser)
+	if assert.NoError(t, e.NewContext(req, httptest.NewRecorder()).Bind(u)) {
+		assert.Equal(t, "Jon", u.Name)
+		assert.Empty(t, u.Role)
+		assert.Zero(t, u.Balance)
+	}
+}
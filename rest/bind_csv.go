@@ -0,0 +1,172 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/enigma-id/go/validation"
+)
+
+// CSVTag is the struct tag of the csv column, json name
+// or the field name is used when the tag is empty.
+const CSVTag = "csv"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// bindCSV binds rows of the csv body into slice of struct, first row
+// is the header and the columns are matched into the fields by name.
+// Conversion errors are keyed by the row index and column, ex. "2.qty".
+func (b *DefaultBinder) bindCSV(i interface{}, c *Context) error {
+	typ := reflect.TypeOf(i)
+	if !isSliceOf(i) {
+		return NewHTTPError(http.StatusBadRequest, "CSV body can only be bound into slice")
+	}
+
+	elem := typ.Elem().Elem()
+	elemPtr := elem.Kind() == reflect.Ptr
+	if elemPtr {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return NewHTTPError(http.StatusBadRequest, "CSV body can only be bound into slice of struct")
+	}
+
+	r := csv.NewReader(c.Request().Body)
+	r.TrimLeadingSpace = true
+	r.ReuseRecord = true
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return NewHTTPError(http.StatusBadRequest, "Request body can't be empty")
+	} else if err != nil {
+		return NewHTTPError(http.StatusBadRequest, "Invalid CSV format").SetInternal(err)
+	}
+	header = csvHeader(header)
+	fields := csvFields(elem, header)
+
+	max := limit(b.MaxElements, MaxBindElements)
+	slice := reflect.MakeSlice(typ.Elem(), 0, 0)
+	for n := 0; ; n++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return NewHTTPError(http.StatusBadRequest, "Invalid CSV format").SetInternal(err)
+		}
+		if max > 0 && n >= max {
+			return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("CSV exceeds the maximum of %d rows", max))
+		}
+
+		row := reflect.New(elem).Elem()
+		for col, value := range record {
+			if col >= len(fields) || fields[col] == nil {
+				continue
+			}
+
+			if err := b.setCSV(row.FieldByIndex(fields[col]), value); err != nil {
+				he := NewHTTPError(http.StatusBadRequest, "Incorrect data structure")
+				return he.SetInternal(validation.SetError(fmt.Sprintf("%d.%s.type", n, header[col]), "has invalid value"))
+			}
+		}
+
+		if elemPtr {
+			row = row.Addr()
+		}
+		slice = reflect.Append(slice, row)
+	}
+	reflect.ValueOf(i).Elem().Set(slice)
+
+	return nil
+}
+
+// setCSV converts the value into the field, empty value keeps zero value.
+func (b *DefaultBinder) setCSV(field reflect.Value, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	if ok, err := b.bindType(value, field); ok {
+		return err
+	}
+	if ok, err := unmarshalField(field.Kind(), value, field); ok {
+		return err
+	}
+
+	kind := field.Kind()
+	if kind == reflect.Ptr {
+		field.Set(reflect.New(field.Type().Elem()))
+		field, kind = field.Elem(), field.Type().Elem().Kind()
+	}
+	if field.Type() == timeType {
+		t, err := parseCSVTime(value)
+		if err == nil {
+			field.Set(reflect.ValueOf(t))
+		}
+		return err
+	}
+	if kind == reflect.Struct || kind == reflect.Slice || kind == reflect.Map {
+		return errors.New("unsupported type of csv column " + field.Type().String())
+	}
+
+	return setWithProperType(kind, value, field)
+}
+
+// parseCSVTime parses RFC3339 time or the date.
+func parseCSVTime(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// csvFields returns index of the fields by the header columns,
+// nil when the column has no field.
+func csvFields(t reflect.Type, header []string) [][]int {
+	names := make(map[string][]int)
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		name := sf.Tag.Get(CSVTag)
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.Split(sf.Tag.Get("json"), ",")[0]
+		}
+		if name == "" || name == "-" {
+			name = sf.Name
+		}
+		names[strings.ToLower(name)] = sf.Index
+	}
+
+	fields := make([][]int, len(header))
+	for i, h := range header {
+		fields[i] = names[strings.ToLower(h)]
+	}
+
+	return fields
+}
+
+// csvHeader returns copy of the header without spaces
+// and utf-8 bom written by the spreadsheet apps.
+func csvHeader(record []string) []string {
+	header := make([]string, len(record))
+	for i, h := range record {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))
+	}
+
+	return header
+}
@@ -0,0 +1,199 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/enigma-id/go/validation"
+)
+
+// bindJSON decodes json body into `i` after the body is checked
+// against the size, nesting depth and array elements guards.
+func (b *DefaultBinder) bindJSON(i interface{}, c *Context) error {
+	data, err := b.readBody(c)
+	if err != nil {
+		return err
+	}
+
+	if err = checkJSON(data, limit(b.MaxDepth, MaxBindDepth), limit(b.MaxElements, MaxBindElements)); err != nil {
+		return NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	// root array bound into struct or map receives clear error instead
+	// of the type error of encoding/json, the other targets decode it
+	if first := firstToken(data); first == '[' && isObjectOf(i) {
+		return NewHTTPError(http.StatusBadRequest, "Request body must be a JSON object")
+	} else if first == '{' && isSliceOf(i) {
+		return NewHTTPError(http.StatusBadRequest, "Request body must be a JSON array")
+	}
+
+	if err = json.NewDecoder(bytes.NewReader(data)).Decode(i); err != nil {
+		if ute, ok := err.(*json.UnmarshalTypeError); ok {
+			return unmarshalTypeError(ute)
+		} else if _, ok := err.(*json.SyntaxError); ok {
+			return NewHTTPError(http.StatusBadRequest, "Invalid JSON format").SetInternal(err)
+		}
+
+		return NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return nil
+}
+
+// validateBody validates the bound body, each element of slice is validated
+// and the failures are keyed by its index, ex. "2.email".
+func validateBody(i interface{}, c *Context) error {
+	if !isSliceOf(i) {
+		return c.Validate(i)
+	}
+
+	res := validation.NewResponse()
+	s := reflect.ValueOf(i).Elem()
+	for n := 0; n < s.Len(); n++ {
+		e := s.Index(n)
+		if e.Kind() != reflect.Ptr && e.CanAddr() {
+			e = e.Addr()
+		}
+		if e.Kind() == reflect.Ptr && (e.IsNil() || e.Elem().Kind() != reflect.Struct) {
+			continue
+		}
+
+		err := c.Validate(e.Interface())
+		if err == nil {
+			continue
+		}
+
+		r, ok := err.(*validation.Response)
+		if !ok {
+			return err
+		}
+		for k, m := range r.GetMessages() {
+			res.Failure(fmt.Sprintf("%d.%s", n, k), m)
+		}
+	}
+
+	if !res.Valid {
+		return res
+	}
+
+	return nil
+}
+
+// checkJSON scans the json document, it returns error when the nesting
+// is deeper than maxDepth or an array has more than maxElements,
+// zero or negative limit is unlimited. Syntax is checked by the decoder.
+func checkJSON(data []byte, maxDepth, maxElements int) error {
+	// elements of the open arrays, -1 for object
+	var stack []int
+	str, esc := false, false
+
+	for _, ch := range data {
+		if str {
+			switch {
+			case esc:
+				esc = false
+			case ch == '\\':
+				esc = true
+			case ch == '"':
+				str = false
+			}
+			continue
+		}
+
+		switch ch {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+
+		// first element of the array
+		if n := len(stack); n > 0 && stack[n-1] == 0 && ch != ']' {
+			stack[n-1] = 1
+		}
+
+		switch ch {
+		case '"':
+			str = true
+		case '{', '[':
+			if maxDepth > 0 && len(stack) >= maxDepth {
+				return fmt.Errorf("JSON nesting exceeds the maximum depth of %d", maxDepth)
+			}
+			if ch == '{' {
+				stack = append(stack, -1)
+			} else {
+				stack = append(stack, 0)
+			}
+		case '}', ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case ',':
+			if n := len(stack); n > 0 && stack[n-1] > 0 {
+				stack[n-1]++
+				if maxElements > 0 && stack[n-1] > maxElements {
+					return fmt.Errorf("JSON array exceeds the maximum of %d elements", maxElements)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// firstToken returns first non whitespace byte of the json.
+func firstToken(data []byte) byte {
+	for _, ch := range data {
+		switch ch {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return ch
+	}
+
+	return 0
+}
+
+// isSliceOf returns true when `i` is pointer to slice, except bytes and
+// the slices having their own json decoding like json.RawMessage.
+func isSliceOf(i interface{}) bool {
+	t := reflect.TypeOf(i)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Slice || t.Elem().Elem().Kind() == reflect.Uint8 {
+		return false
+	}
+	_, ok := i.(json.Unmarshaler)
+
+	return !ok
+}
+
+// isObjectOf returns true when `i` is pointer to struct or map, except
+// the types having their own json decoding.
+func isObjectOf(i interface{}) bool {
+	t := reflect.TypeOf(i)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return false
+	}
+	for ; t.Kind() == reflect.Ptr; t = t.Elem() {
+		if t.Implements(jsonUnmarshaler) {
+			return false
+		}
+	}
+
+	return t.Kind() == reflect.Struct || t.Kind() == reflect.Map
+}
+
+var jsonUnmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// limit returns the default when v is zero.
+func limit(v, def int) int {
+	if v == 0 {
+		return def
+	}
+
+	return v
+}
@@ -2,15 +2,19 @@ package rest
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/enigma-id/go/validation"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -199,6 +203,113 @@ func TestBindUnmarshalParamPtr(t *testing.T) {
 	}
 }
 
+func TestBindRegisteredType(t *testing.T) {
+	type money int64
+	e := New()
+	b := new(DefaultBinder)
+	b.Register(money(0), func(v string) (interface{}, error) {
+		f, err := strconv.ParseFloat(strings.Replace(strings.Replace(v, ".", "", -1), ",", ".", 1), 64)
+		return money(f * 100), err
+	})
+	e.Binder = b
+
+	req := httptest.NewRequest(http.MethodGet, "/?price=1.234,56&ptr=10&list=1,5&list=2", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+	result := struct {
+		Price money   `query:"price"`
+		Ptr   *money  `query:"ptr"`
+		List  []money `query:"list"`
+	}{}
+	if assert.NoError(t, c.Bind(&result)) {
+		assert.Equal(t, money(123456), result.Price)
+		assert.Equal(t, money(1000), *result.Ptr)
+		assert.Equal(t, []money{150, 200}, result.List)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?price=abc", nil)
+	c = e.NewContext(req, httptest.NewRecorder())
+	assert.Error(t, c.Bind(&result))
+}
+
+func TestBindNestedQuery(t *testing.T) {
+	type item struct {
+		SKU string `query:"sku" json:"sku" valid:"required"`
+		Qty int    `query:"qty" json:"qty" valid:"required|gte:1"`
+	}
+	type address struct {
+		City string `query:"city"`
+	}
+	type order struct {
+		Code    string   `query:"code"`
+		Items   []item   `query:"items" json:"items" valid:"required"`
+		Ptrs    []*item  `query:"ptrs"`
+		Address *address `query:"address"`
+		Tags    []string `query:"tags"`
+	}
+
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/?code=X&items[0][sku]=A&items[0][qty]=2&items[1][sku]=B&items.1.qty=3"+
+		"&ptrs[0].sku=C&address[city]=Jakarta&tags=a&tags=b", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	o := new(order)
+	if assert.NoError(t, c.Bind(o)) {
+		assert.Equal(t, "X", o.Code)
+		assert.Equal(t, []item{{"A", 2}, {"B", 3}}, o.Items)
+		assert.Equal(t, []*item{{SKU: "C"}}, o.Ptrs)
+		assert.Equal(t, &address{"Jakarta"}, o.Address)
+		assert.Equal(t, []string{"a", "b"}, o.Tags)
+	}
+
+	// validated with the slice rules
+	req = httptest.NewRequest(http.MethodGet, "/?items[0][sku]=A&items[0][qty]=1&items[1][sku]=B&items[1][qty]=0", nil)
+	c = e.NewContext(req, httptest.NewRecorder())
+	o = new(order)
+	assert.NoError(t, c.Bind(o))
+	err := c.Validate(o)
+	if assert.Error(t, err) {
+		assert.Contains(t, validationErrors(err).GetErrors(), "items.1.qty")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?items[5000][sku]=A", nil)
+	c = e.NewContext(req, httptest.NewRecorder())
+	assert.Error(t, c.Bind(new(order)))
+
+	req = httptest.NewRequest(http.MethodGet, "/?items[0][qty]=many", nil)
+	c = e.NewContext(req, httptest.NewRecorder())
+	assert.Error(t, c.Bind(new(order)))
+}
+
+func TestBindHooks(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(userJSON))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	var calls []string
+	c.BeforeBind(func(c *Context, i interface{}) error {
+		calls = append(calls, "before:"+i.(*user).Name)
+		return nil
+	})
+	c.AfterBind(func(c *Context, i interface{}) error {
+		calls = append(calls, "after:"+i.(*user).Name)
+		i.(*user).Name = strings.ToUpper(i.(*user).Name)
+		return nil
+	})
+
+	u := new(user)
+	if assert.NoError(t, c.Bind(u)) {
+		assert.Equal(t, []string{"before:", "after:Jon Snow"}, calls)
+		assert.Equal(t, "JON SNOW", u.Name)
+	}
+
+	c.Reset(req, httptest.NewRecorder())
+	c.BeforeBind(func(c *Context, i interface{}) error {
+		return ErrBadRequest
+	})
+	assert.Equal(t, ErrBadRequest, c.Bind(new(user)))
+}
+
 func TestBindUnsupportedMediaType(t *testing.T) {
 	assert := assert.New(t)
 	testBindError(assert, strings.NewReader(invalidContent), MIMEApplicationJSON, &json.SyntaxError{})
@@ -224,9 +335,45 @@ func TestBindUnmarshalTypeError(t *testing.T) {
 
 	err := c.Bind(u)
 
-	he := NewHTTPError(http.StatusBadRequest, "Incorrect data structure")
+	if assert.IsType(t, new(HTTPError), err) {
+		he := err.(*HTTPError)
+		assert.Equal(t, http.StatusBadRequest, he.Code)
+		assert.Equal(t, "Incorrect data structure", he.Message)
+		assert.Equal(t, map[string]string{"id": "must be a number"}, validationErrors(he).GetErrors())
+	}
+}
+
+func TestBindUnmarshalTypeErrorPath(t *testing.T) {
+	type item struct {
+		Qty int `json:"qty"`
+	}
+	type order struct {
+		Items []item `json:"items"`
+		Note  string `json:"note"`
+	}
 
-	assert.Equal(t, he, err)
+	e := New()
+	tests := map[string]map[string]string{
+		`{"items":[{"qty":1},{"qty":2},{"qty":"x"}]}`: {"items.2.qty": "must be a number"},
+		`{"note":10}`:    {"note": "must be a string"},
+		`{"items":true}`: {"items": "must be an array"},
+	}
+	for body, want := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := c.Bind(new(order))
+		assert.Equal(t, want, validationErrors(err).GetErrors(), body)
+
+		// same error shape as validation failure, with status 400
+		e.HTTPErrorHandler(err, c)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		for k, v := range want {
+			assert.Contains(t, rec.Body.String(), `"`+k+`":"`+v+`"`)
+		}
+	}
 }
 
 func TestBindSetWithProperType(t *testing.T) {
@@ -355,3 +502,247 @@ func testBindError(assert *assert.Assertions, r io.Reader, ctype string, expecte
 		}
 	}
 }
+
+func TestBindRequiredOn(t *testing.T) {
+	type product struct {
+		Name  string `json:"name" valid:"required_on:POST"`
+		Price int    `json:"price" valid:"required_on:POST|gt:0"`
+	}
+
+	e := New()
+	bind := func(method, body string) error {
+		req := httptest.NewRequest(method, "/products", strings.NewReader(body))
+		req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+		return e.NewContext(req, httptest.NewRecorder()).Bind(new(product))
+	}
+
+	err := bind(http.MethodPost, `{"price":10}`)
+	if assert.IsType(t, &validation.Response{}, err) {
+		assert.Equal(t, map[string]string{"name": "The name field is required"}, err.(*validation.Response).GetErrors())
+	}
+	assert.NoError(t, bind(http.MethodPost, `{"name":"Kopi","price":10}`))
+	assert.NoError(t, bind(http.MethodPatch, `{"price":10}`))
+	assert.IsType(t, &validation.Response{}, bind(http.MethodPatch, `{"price":-1}`))
+}
+
+func TestBindJSONGuards(t *testing.T) {
+	type item struct {
+		SKU string `json:"sku" valid:"required"`
+	}
+	type doc struct {
+		A    interface{}   `json:"a"`
+		Tags []int         `json:"tags"`
+		More []interface{} `json:"more"`
+	}
+
+	e := New()
+	bind := func(body string, i interface{}) error {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+		return e.NewContext(req, httptest.NewRecorder()).Bind(i)
+	}
+	message := func(err error) interface{} {
+		if he, ok := err.(*HTTPError); ok {
+			return he.Message
+		}
+		return err
+	}
+
+	nested := func(depth int) string {
+		return strings.Repeat(`{"a":`, depth) + "1" + strings.Repeat("}", depth)
+	}
+	assert.Equal(t, "JSON nesting exceeds the maximum depth of 32", message(bind(nested(MaxBindDepth+1), new(doc))))
+	assert.NoError(t, bind(nested(MaxBindDepth), new(doc)))
+	// brackets in strings are not nesting
+	assert.NoError(t, bind(`{"sku":"`+strings.Repeat("[", 100)+`\"]"}`, new(item)))
+
+	b := e.Binder.(*DefaultBinder)
+	b.MaxElements = 3
+	assert.NoError(t, bind(`{"tags":[1,2,3],"more":[[],[1],["a,b","c,d"]]}`, new(doc)))
+	assert.Equal(t, "JSON array exceeds the maximum of 3 elements", message(bind(`{"tags":[1,2,3,4]}`, new(doc))))
+	assert.Equal(t, "JSON array exceeds the maximum of 3 elements", message(bind(`[{"sku":"a"},{"sku":"b"},{"sku":"c"},{"sku":"d"}]`, &[]item{})))
+	b.MaxElements = 0
+
+	// body size
+	b.MaxBytes = 17
+	assert.NoError(t, bind(`{"sku":"abcdefg"}`, new(item)))
+	assert.Equal(t, ErrStatusRequestEntityTooLarge, bind(`{"sku":"abcdefgh"}`, new(item)))
+	b.MaxBytes = -1
+	assert.NoError(t, bind(`{"sku":"`+strings.Repeat("a", 1<<10)+`"}`, new(item)))
+	b.MaxBytes = 0
+	// unlimited by default
+	assert.NoError(t, bind(`{"tags":[`+strings.Repeat("1,", 20000)+`1]}`, new(doc)))
+
+	// root array
+	assert.Equal(t, "Request body must be a JSON object", message(bind(`[{"sku":"a"}]`, new(item))))
+	assert.Equal(t, "Request body must be a JSON object", message(bind(`[1,2]`, &map[string]int{})))
+	assert.Equal(t, "Request body must be a JSON array", message(bind(`{"sku":"a"}`, &[]item{})))
+
+	// the other targets decode root array
+	decode := func(body string, i interface{}) error {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		return b.bindJSON(i, e.NewContext(req, httptest.NewRecorder()))
+	}
+	var any interface{}
+	assert.NoError(t, decode(`[1,2]`, &any))
+	assert.Equal(t, []interface{}{1.0, 2.0}, any)
+	var raw json.RawMessage
+	assert.NoError(t, decode(`[1,2]`, &raw))
+	assert.Equal(t, `[1,2]`, string(raw))
+	var ids idList
+	assert.NoError(t, decode(`[1,2]`, &ids))
+	assert.Equal(t, idList{IDs: []int{1, 2}}, ids)
+
+	var items []item
+	assert.NoError(t, bind(`[{"sku":"a"},{"sku":"b"}]`, &items))
+	assert.Equal(t, []item{{SKU: "a"}, {SKU: "b"}}, items)
+
+	err := bind(`[{"sku":"a"},{}]`, &[]*item{})
+	if assert.IsType(t, &validation.Response{}, err) {
+		assert.Equal(t, map[string]string{"1.sku": "The sku field is required"}, err.(*validation.Response).GetErrors())
+	}
+
+	assert.False(t, isSliceOf(new(json.RawMessage)))
+	assert.False(t, isSliceOf(new([]byte)))
+}
+
+// idList is decoded from json array by its UnmarshalJSON.
+type idList struct {
+	IDs []int
+}
+
+func (l *idList) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &l.IDs)
+}
+
+func TestBindScenario(t *testing.T) {
+	type product struct {
+		Name  string `json:"name" valid:"required;on=create"`
+		Price int    `json:"price" valid:"required;on=PUT|gt:0"`
+	}
+
+	e := New()
+	bind := func(method, scenario, body string) error {
+		req := httptest.NewRequest(method, "/products", strings.NewReader(body))
+		req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+		c := e.NewContext(req, httptest.NewRecorder())
+		if scenario != "" {
+			c.Set(ScenarioKey, scenario)
+		}
+		return c.Bind(new(product))
+	}
+
+	err := bind(http.MethodPost, "create", `{"price":10}`)
+	if assert.IsType(t, &validation.Response{}, err) {
+		assert.Equal(t, map[string]string{"name": "The name field is required"}, err.(*validation.Response).GetErrors())
+	}
+	assert.NoError(t, bind(http.MethodPatch, "update", `{"price":10}`))
+	assert.NoError(t, bind(http.MethodPatch, "", `{"price":5}`))
+	assert.IsType(t, &validation.Response{}, bind(http.MethodPut, "update", `{"name":"Kopi"}`))
+}
+
+func TestBindCSV(t *testing.T) {
+	type row struct {
+		SKU     string    `json:"sku" csv:"sku" valid:"required"`
+		Name    string    `json:"name"`
+		Qty     int       `csv:"qty" valid:"gt:0"`
+		Price   *float64  `csv:"price"`
+		Expired time.Time `csv:"expired_at"`
+		Secret  string    `csv:"-"`
+	}
+
+	e := New()
+	bind := func(body string, i interface{}) error {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(HeaderContentType, MIMETextCSV+"; charset=utf-8")
+		return e.NewContext(req, httptest.NewRecorder()).Bind(i)
+	}
+
+	var rows []row
+	body := "\ufeffSKU, name,qty,price,expired_at,Secret,extra\nA1,Kopi,2,10.5,2024-12-31,x,y\nB2,\"Teh, manis\",1,,,,\n"
+	assert.NoError(t, bind(body, &rows))
+	if assert.Len(t, rows, 2) {
+		assert.Equal(t, "A1", rows[0].SKU)
+		assert.Equal(t, "Kopi", rows[0].Name)
+		assert.Equal(t, 2, rows[0].Qty)
+		assert.Equal(t, 10.5, *rows[0].Price)
+		assert.Equal(t, time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), rows[0].Expired)
+		assert.Empty(t, rows[0].Secret)
+		assert.Equal(t, "Teh, manis", rows[1].Name)
+		assert.Nil(t, rows[1].Price)
+	}
+
+	var ptrs []*row
+	err := bind("sku,qty\nA1,1\n,0\n", &ptrs)
+	if assert.IsType(t, &validation.Response{}, err) {
+		assert.Equal(t, map[string]string{
+			"1.sku": "The sku field is required",
+			"1.qty": "The qty should be greater than 0",
+		}, err.(*validation.Response).GetErrors())
+	}
+
+	err = bind("sku,qty\nA1,1\nB2,two\n", &rows)
+	assert.Equal(t, map[string]string{"1.qty": "has invalid value"}, validationErrors(err).GetErrors())
+
+	err = bind("sku,qty\nA1,1,3\n", &rows)
+	assert.Equal(t, "Invalid CSV format", err.(*HTTPError).Message)
+	assert.Equal(t, "CSV body can only be bound into slice", bind("sku\nA1\n", new(row)).(*HTTPError).Message)
+
+	b := e.Binder.(*DefaultBinder)
+	b.MaxElements = 1
+	assert.Equal(t, "CSV exceeds the maximum of 1 rows", bind("sku,qty\nA1,1\nB2,2\n", &rows).(*HTTPError).Message)
+	b.MaxElements = 0
+}
+
+func TestBindProvider(t *testing.T) {
+	type signup struct {
+		Email string `json:"email" valid:"unique:users"`
+	}
+
+	opts := ValidatorOptions
+	defer func() { ValidatorOptions = opts }()
+	ValidatorOptions = []validation.Option{validation.Provider("unique", "The %s has already been taken", func(ctx context.Context, value interface{}, p []string) (bool, error) {
+		if value == "down" {
+			return false, errors.New("database is down")
+		}
+		return value != "a@b.co", nil
+	})}
+
+	e := New()
+	bind := func(body string) error {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+		return e.NewContext(req, httptest.NewRecorder()).Bind(new(signup))
+	}
+
+	assert.NoError(t, bind(`{"email":"c@d.co"}`))
+	assert.Equal(t, map[string]string{"email": "The email has already been taken"}, validationErrors(bind(`{"email":"a@b.co"}`)).GetErrors())
+	assert.EqualError(t, bind(`{"email":"down"}`), "database is down")
+}
+
+func TestBindContextRule(t *testing.T) {
+	type tenantKey struct{}
+	type transfer struct {
+		Account string `json:"account" valid:"tenant_account"`
+	}
+
+	opts := ValidatorOptions
+	defer func() { ValidatorOptions = opts }()
+	ValidatorOptions = []validation.Option{validation.ContextRule("tenant_account", func(ctx context.Context, value interface{}, _ string) (bool, string) {
+		if strings.HasPrefix(value.(string), ctx.Value(tenantKey{}).(string)) {
+			return true, ""
+		}
+		return false, "The %s must belong to the tenant"
+	})}
+
+	e := New()
+	bind := func(body string) error {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+		req = req.WithContext(context.WithValue(req.Context(), tenantKey{}, "acme"))
+		return e.NewContext(req, httptest.NewRecorder()).Bind(new(transfer))
+	}
+
+	assert.NoError(t, bind(`{"account":"acme-1"}`))
+	assert.Equal(t, map[string]string{"account": "The account must belong to the tenant"}, validationErrors(bind(`{"account":"umbrella-1"}`)).GetErrors())
+}
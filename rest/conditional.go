@@ -0,0 +1,80 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// NotModified sets Last-Modified and ETag headers of the resource, then
+// evaluates If-None-Match and If-Modified-Since of the request, when the
+// client copy is still fresh 304 is written and it returns true.
+// Zero time or empty etag is not sent nor evaluated.
+//
+//	if c.NotModified(order.UpdatedAt, fmt.Sprintf(`"%d"`, order.Version)) {
+//		return nil
+//	}
+//	return c.OK(order)
+func (c *Context) NotModified(lastModified time.Time, etag string) bool {
+	h := c.Response().Header()
+	if etag != "" {
+		if !strings.HasSuffix(etag, `"`) {
+			etag = `"` + etag + `"`
+		}
+		h.Set(HeaderETag, etag)
+	}
+	if !lastModified.IsZero() {
+		h.Set(HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	req := c.Request()
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+
+	if !c.fresh(lastModified, etag) {
+		return false
+	}
+
+	// 304 should not contain representation headers
+	h.Del(HeaderContentType)
+	h.Del(HeaderContentLength)
+	c.NoContent(http.StatusNotModified)
+
+	return true
+}
+
+// fresh checks the validators of the request, If-None-Match takes
+// precedence over If-Modified-Since as described in RFC 7232.
+func (c *Context) fresh(lastModified time.Time, etag string) bool {
+	req := c.Request()
+	if inm := req.Header.Get(HeaderIfNoneMatch); inm != "" {
+		if etag == "" {
+			return false
+		}
+
+		for _, t := range strings.Split(inm, ",") {
+			if t = strings.TrimSpace(t); t == "*" || weakEqual(t, etag) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if ims := req.Header.Get(HeaderIfModifiedSince); ims != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(ims)
+		return err == nil && !lastModified.Truncate(time.Second).After(t)
+	}
+
+	return false
+}
+
+// weakEqual compares two entity tags ignoring the weak indicator.
+func weakEqual(a, b string) bool {
+	return strings.TrimPrefix(a, "W/") == strings.TrimPrefix(b, "W/")
+}
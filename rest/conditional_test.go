@@ -0,0 +1,64 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextNotModified(t *testing.T) {
+	e := New()
+	modified := time.Date(2019, 1, 2, 3, 4, 5, 600, time.UTC)
+
+	tests := []struct {
+		name    string
+		method  string
+		headers map[string]string
+		want    bool
+	}{
+		{"no validators", http.MethodGet, nil, false},
+		{"etag match", http.MethodGet, map[string]string{HeaderIfNoneMatch: `"v1"`}, true},
+		{"etag weak match", http.MethodGet, map[string]string{HeaderIfNoneMatch: `"v0", W/"v1"`}, true},
+		{"etag any", http.MethodGet, map[string]string{HeaderIfNoneMatch: `*`}, true},
+		{"etag mismatch", http.MethodGet, map[string]string{HeaderIfNoneMatch: `"v2"`}, false},
+		{"etag precedence", http.MethodGet, map[string]string{HeaderIfNoneMatch: `"v2"`, HeaderIfModifiedSince: modified.Format(http.TimeFormat)}, false},
+		{"not modified since", http.MethodGet, map[string]string{HeaderIfModifiedSince: modified.Format(http.TimeFormat)}, true},
+		{"modified since", http.MethodGet, map[string]string{HeaderIfModifiedSince: modified.Add(-time.Hour).Format(http.TimeFormat)}, false},
+		{"invalid date", http.MethodGet, map[string]string{HeaderIfModifiedSince: "yesterday"}, false},
+		{"head", http.MethodHead, map[string]string{HeaderIfNoneMatch: `"v1"`}, true},
+		{"post", http.MethodPost, map[string]string{HeaderIfNoneMatch: `"v1"`}, false},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/", nil)
+		for k, v := range tt.headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		assert.Equal(t, tt.want, c.NotModified(modified, "v1"), tt.name)
+		assert.Equal(t, `"v1"`, rec.Header().Get(HeaderETag), tt.name)
+		assert.Equal(t, "Wed, 02 Jan 2019 03:04:05 GMT", rec.Header().Get(HeaderLastModified), tt.name)
+		if tt.want {
+			assert.Equal(t, http.StatusNotModified, rec.Code, tt.name)
+		}
+	}
+}
+
+func TestContextNotModifiedResponse(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if !c.NotModified(time.Time{}, `W/"abc"`) {
+		c.OK("data")
+	}
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `W/"abc"`, rec.Header().Get(HeaderETag))
+	assert.Empty(t, rec.Header().Get(HeaderLastModified))
+}
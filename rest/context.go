@@ -11,10 +11,13 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strconv"
 	"strings"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/enigma-id/go/trace"
+	"github.com/enigma-id/go/validation"
 	"go.uber.org/zap"
 )
 
@@ -31,6 +34,11 @@ type Context struct {
 	store        Map
 	rest         *Rest
 	validator    Validator
+	beforeBind   []BindHook
+	afterBind    []BindHook
+	bindProfile  string
+	meta         Map
+	autoHead     bool
 	ResponseBody *ResponseFormat
 }
 
@@ -192,6 +200,35 @@ func (c *Context) Get(key string) interface{} {
 	return c.store[key]
 }
 
+// MustGet retrieves data from the context, it panics when the key is not set
+// which is usually the middleware setting it is missing or misordered.
+func (c *Context) MustGet(key string) interface{} {
+	v, ok := c.store[key]
+	if !ok {
+		panic(fmt.Sprintf("rest: context key %q is not set for %s %s, is the middleware setting it registered before the handler?", key, c.request.Method, c.path))
+	}
+	return v
+}
+
+// GetAs retrieves data from the context as T, false is returned
+// when the key is not set or the value is not a T.
+//
+//	token, ok := rest.GetAs[*jwt.Token](c, "user")
+func GetAs[T any](c *Context, key string) (T, bool) {
+	v, ok := c.Get(key).(T)
+	return v, ok
+}
+
+// MustGetAs same as GetAs, but it panics when the key is not set
+// or the value is not a T, see MustGet.
+func MustGetAs[T any](c *Context, key string) T {
+	v, ok := c.MustGet(key).(T)
+	if !ok {
+		panic(fmt.Sprintf("rest: context key %q holds %T, not %s", key, c.store[key], reflect.TypeOf((*T)(nil)).Elem()))
+	}
+	return v
+}
+
 // Set saves data in the context.
 func (c *Context) Set(key string, val interface{}) {
 	if c.store == nil {
@@ -201,9 +238,71 @@ func (c *Context) Set(key string, val interface{}) {
 }
 
 // Bind binds the request body into provided type `i`. The default binder
-// does it based on Content-Type header.
+// does it based on Content-Type header, the before bind hooks are called
+// first and the after bind hooks are called when the binding succeed.
 func (c *Context) Bind(i interface{}) error {
-	return c.rest.Binder.Bind(i, c)
+	for _, h := range c.beforeBind {
+		if err := h(c, i); err != nil {
+			return err
+		}
+	}
+
+	s := c.span(trace.KindBind, i)
+	err := c.rest.Binder.Bind(i, c)
+	s.End(err)
+	if err != nil {
+		return err
+	}
+
+	for _, h := range c.afterBind {
+		if err := h(c, i); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// span starts span of the type when the request is traced.
+func (c *Context) span(kind string, i interface{}) *trace.Span {
+	t := trace.FromContext(c.Request().Context())
+	if t == nil {
+		return nil
+	}
+
+	return t.Start(kind, fmt.Sprintf("%T", i))
+}
+
+// BeforeBind adds hooks that are called before the request is bound,
+// ex. a middleware that rewrites legacy payload shape.
+func (c *Context) BeforeBind(h ...BindHook) {
+	c.beforeBind = append(c.beforeBind, h...)
+}
+
+// AfterBind adds hooks that are called after the request is bound.
+func (c *Context) AfterBind(h ...BindHook) {
+	c.afterBind = append(c.afterBind, h...)
+}
+
+// Validate validates `i` using the validator of the rest instances,
+// method, route, locale and scenario of the request are passed when
+// the validator implements MetaValidator.
+func (c *Context) Validate(i interface{}) (err error) {
+	s := c.span(trace.KindValidate, i)
+	defer func() { s.End(err) }()
+
+	if mv, ok := c.validator.(MetaValidator); ok {
+		scenario, _ := c.Get(ScenarioKey).(string)
+		return mv.ValidateMeta(i, validation.Meta{Method: c.Request().Method, Route: c.Path(), Locale: c.Locale(), Timezone: c.Timezone(), Scenario: scenario, Context: c.Request().Context()})
+	}
+
+	return c.validator.Validate(i)
+}
+
+// Validated returns the request that was bound and validated
+// by mw.ValidateBody, returns nil when there is none.
+func (c *Context) Validated() interface{} {
+	return c.Get(ValidatedKey)
 }
 
 // String sends a string response with status code.
@@ -218,6 +317,7 @@ func (c *Context) Serve(e error) (err error) {
 	c.ResponseBody.Code = http.StatusOK
 	if e != nil {
 		c.ResponseBody.SetError(e)
+		c.translateResponse(e)
 	}
 
 	if c.Request().Method == http.MethodHead || c.Request().Method == http.MethodOptions {
@@ -299,7 +399,9 @@ func (c *Context) Blob(code int, contentType string, b []byte) (err error) {
 	return
 }
 
-// Stream sends a streaming response with status code and content type.
+// Stream sends a streaming response with status code and content type,
+// the reader is sent from its current position. Use Content to serve
+// the Range requests of the seekable reader.
 func (c *Context) Stream(code int, contentType string, r io.Reader) (err error) {
 	c.writeContentType(contentType)
 	c.response.WriteHeader(code)
@@ -343,6 +445,12 @@ func (c *Context) SetHandler(h HandlerFunc) {
 	c.handler = h
 }
 
+// ShuttingDown returns channel that is closed when the server
+// starts shutting down, see Rest.ShuttingDown.
+func (c *Context) ShuttingDown() <-chan struct{} {
+	return c.rest.ShuttingDown()
+}
+
 // Logger returns the `Logger` instance.
 func (c *Context) Logger() *zap.Logger {
 	return c.rest.Logger
@@ -359,19 +467,24 @@ func (c *Context) Reset(r *http.Request, w http.ResponseWriter) {
 	c.store = nil
 	c.path = ""
 	c.pnames = nil
+	c.beforeBind = nil
+	c.afterBind = nil
+	c.bindProfile = ""
+	c.meta = nil
+	c.autoHead = false
 	c.ResponseBody.reset()
 }
 
 // JwtUsers get a user sessions that having jwt token in
 // request header and checked again the model.
 func (c *Context) JwtUsers(model jwtUser) interface{} {
-	if u := c.Get("user"); u != nil {
-		s := u.(*jwt.Token)
-		c := s.Claims.(jwt.MapClaims)
-		id := int64(c["id"].(float64))
-
-		if users, err := model.GetUser(id); err == nil {
-			return users
+	if s, ok := GetAs[*jwt.Token](c, "user"); ok {
+		if claims, ok := s.Claims.(jwt.MapClaims); ok {
+			if id, ok := claims["id"].(float64); ok {
+				if users, err := model.GetUser(int64(id)); err == nil {
+					return users
+				}
+			}
 		}
 	}
 
@@ -3,6 +3,7 @@ package rest
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -12,6 +13,9 @@ import (
 	"text/template"
 	"time"
 
+	"github.com/dgrijalva/jwt-go"
+	"github.com/enigma-id/go/export"
+	"github.com/enigma-id/go/pdf"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -255,6 +259,28 @@ func TestContextStore(t *testing.T) {
 	assert.Equal(t, "Jon Snow", c.Get("name"))
 }
 
+func TestContextGetAs(t *testing.T) {
+	e := New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	c.SetPath("/profile")
+	c.Set("user", &jwt.Token{Valid: true})
+	c.Set("name", "Jon Snow")
+
+	token, ok := GetAs[*jwt.Token](c, "user")
+	assert.True(t, ok)
+	assert.True(t, token.Valid)
+
+	_, ok = GetAs[string](c, "user")
+	assert.False(t, ok)
+	_, ok = GetAs[*jwt.Token](c, "missing")
+	assert.False(t, ok)
+
+	assert.Equal(t, "Jon Snow", c.MustGet("name"))
+	assert.Equal(t, "Jon Snow", MustGetAs[string](c, "name"))
+	assert.PanicsWithValue(t, `rest: context key "name" holds string, not fmt.Stringer`, func() { MustGetAs[fmt.Stringer](c, "name") })
+	assert.PanicsWithValue(t, `rest: context key "tenant" is not set for GET /profile, is the middleware setting it registered before the handler?`, func() { c.MustGet("tenant") })
+}
+
 func TestContextHandler(t *testing.T) {
 	e := New()
 	r := e.Router()
@@ -269,3 +295,37 @@ func TestContextHandler(t *testing.T) {
 	c.Handler()(c)
 	assert.Equal(t, "handler", b.String())
 }
+
+func TestContextExport(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	rows := []struct {
+		Name string `export:"Nama"`
+	}{{"Jon Snow"}}
+
+	if assert.NoError(t, c.Export(rows, export.Options{Filename: "users"})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get(HeaderContentType))
+		assert.Equal(t, "attachment;filename=users.csv", rec.Header().Get("Content-Disposition"))
+		assert.Equal(t, "Nama\nJon Snow\n", rec.Body.String())
+	}
+}
+
+func TestContextPDF(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	doc := pdf.New().Heading("Invoice")
+	doc.Filename = "invoice.pdf"
+
+	if assert.NoError(t, c.PDF(http.StatusOK, doc)) {
+		assert.Equal(t, "application/pdf", rec.Header().Get(HeaderContentType))
+		assert.Equal(t, "inline;filename=invoice.pdf", rec.Header().Get("Content-Disposition"))
+		assert.True(t, strings.HasPrefix(rec.Body.String(), "%PDF-"))
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"net/http"
+
+	"github.com/enigma-id/go/i18n"
+	"github.com/enigma-id/go/validation"
+)
+
+type (
+	// Envelope is the standard response body written by OK, Created and Fail.
+	Envelope struct {
+		Status  string            `json:"status"`
+		Message string            `json:"message,omitempty"`
+		Data    interface{}       `json:"data,omitempty"`
+		Errors  map[string]string `json:"errors,omitempty"`
+		Codes   map[string]string `json:"codes,omitempty"`
+		Meta    Map               `json:"meta,omitempty"`
+	}
+
+	// EnvelopeFunc wraps the data or error into response body, it's set once
+	// on the rest instances so all the services have the same shape.
+	EnvelopeFunc func(c *Context, code int, data interface{}, err error) interface{}
+)
+
+// DefaultEnvelope wraps the response into Envelope, error message and
+// validation errors are translated using locale of the request and their
+// codes are kept untranslated, ex. {"email": "VAL_EMAIL"}.
+func DefaultEnvelope(c *Context, code int, data interface{}, err error) interface{} {
+	en := &Envelope{Status: HTTPResponseSuccess, Data: data, Meta: c.meta}
+	if err == nil {
+		return en
+	}
+
+	en.Status = HTTPResponseFailed
+	en.Data = nil
+	en.Message = http.StatusText(code)
+
+	if he, ok := err.(*HTTPError); ok {
+		en.Message = he.Error()
+	} else if _, ok := err.(*validation.Response); !ok && c.rest.Config.DevMode {
+		en.Message = err.Error()
+	}
+	if o := validationErrors(err); o != nil {
+		en.Errors = c.translateErrors(o)
+		en.Codes = o.GetCodes()
+	}
+	en.Message = i18n.T(c.Request().Context(), en.Message)
+
+	return en
+}
+
+// SetMeta sets value into meta of the response envelope,
+// ex. c.SetMeta("pagination", p).
+func (c *Context) SetMeta(key string, value interface{}) {
+	if c.meta == nil {
+		c.meta = make(Map)
+	}
+	c.meta[key] = value
+}
+
+// OK sends the data wrapped in the envelope with status 200.
+func (c *Context) OK(data interface{}) error {
+	return c.envelope(http.StatusOK, data, nil)
+}
+
+// Created sends the data wrapped in the envelope with status 201.
+func (c *Context) Created(data interface{}) error {
+	return c.envelope(http.StatusCreated, data, nil)
+}
+
+// Empty sends no body with status 204, ex. the response of delete.
+func (c *Context) Empty() error {
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Fail sends the error wrapped in the envelope, status code is taken from
+// HTTPError, 422 for validation errors, otherwise 500.
+func (c *Context) Fail(err error) error {
+	code := http.StatusInternalServerError
+	if he, ok := err.(*HTTPError); ok {
+		code = he.Code
+	} else if _, ok := err.(*validation.Response); ok {
+		code = http.StatusUnprocessableEntity
+	}
+
+	return c.envelope(code, nil, err)
+}
+
+func (c *Context) envelope(code int, data interface{}, err error) error {
+	if c.Request().Method == http.MethodHead {
+		return c.NoContent(code)
+	}
+
+	fn := c.rest.Envelope
+	if fn == nil {
+		fn = DefaultEnvelope
+	}
+
+	return c.JSON(code, fn(c, code, data, err))
+}
@@ -0,0 +1,96 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/enigma-id/go/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextEnvelope(t *testing.T) {
+	e := New()
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	c.SetMeta("total", 1)
+	if assert.NoError(t, c.OK([]string{"jon"})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"status":"success","data":["jon"],"meta":{"total":1}}`, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), rec)
+	if assert.NoError(t, c.Created(Map{"id": 1})) {
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.JSONEq(t, `{"status":"success","data":{"id":1}}`, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodDelete, "/", nil), rec)
+	if assert.NoError(t, c.Empty()) {
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Empty(t, rec.Body.String())
+	}
+}
+
+func TestContextFail(t *testing.T) {
+	e := New()
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	if assert.NoError(t, c.Fail(NewHTTPError(http.StatusNotFound, "user not found"))) {
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.JSONEq(t, `{"status":"failed","message":"user not found"}`, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	if assert.NoError(t, c.Fail(validation.SetError("name", "name is required"))) {
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.JSONEq(t, `{"status":"failed","message":"Unprocessable Entity","errors":{"name":"name is required"}}`, rec.Body.String())
+	}
+
+	dev := e.Config.DevMode
+	defer func() { e.Config.DevMode = dev }()
+	e.Config.DevMode = false
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	if assert.NoError(t, c.Fail(errors.New("connection refused"))) {
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.JSONEq(t, `{"status":"failed","message":"Internal Server Error"}`, rec.Body.String())
+	}
+}
+
+func TestContextCustomEnvelope(t *testing.T) {
+	e := New()
+	e.Envelope = func(c *Context, code int, data interface{}, err error) interface{} {
+		return Map{"code": code, "result": data}
+	}
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	if assert.NoError(t, c.OK("pong")) {
+		assert.JSONEq(t, `{"code":200,"result":"pong"}`, rec.Body.String())
+	}
+}
+
+func TestContextFailCodes(t *testing.T) {
+	e := New()
+	type signup struct {
+		Email string `json:"email" valid:"required|email"`
+		Name  string `json:"name" valid:"required"`
+	}
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), rec)
+	if assert.NoError(t, c.Fail(validation.New().Struct(signup{Email: "x"}))) {
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.JSONEq(t, `{"status":"failed","message":"Unprocessable Entity",
+			"errors":{"email":"The email must be a valid email address","name":"The name field is required"},
+			"codes":{"email":"VAL_EMAIL","name":"VAL_REQUIRED"}}`, rec.Body.String())
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/enigma-id/go/export"
+)
+
+// Export streams rows as csv or xlsx attachment, rows can be slice of struct,
+// channel of struct or export.Iterator. Response is flushed every chunk of rows
+// so large report is not buffered in memory.
+func (c *Context) Export(rows interface{}, opts export.Options) error {
+	c.writeContentType(opts.ContentType())
+	c.response.Header().Set("Content-Disposition", fmt.Sprintf("attachment;filename=%s", opts.FileName()))
+	c.response.WriteHeader(http.StatusOK)
+
+	return export.Write(c.response, rows, opts)
+}
@@ -0,0 +1,203 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// File sends the file of the path, the Range requests are served
+// with partial content so the download is resumable.
+func (c *Context) File(path string) error {
+	f, fi, err := openFile(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return c.Content(typeByName(fi.Name()), f, fi.ModTime())
+}
+
+// Attachment sends the file of the path as attachment with the name,
+// the Range requests are served with partial content.
+func (c *Context) Attachment(path, name string) error {
+	return c.disposition(path, name, "attachment")
+}
+
+// Inline sends the file of the path to be displayed by the browser,
+// ex. image or pdf, the Range requests are served with partial content.
+func (c *Context) Inline(path, name string) error {
+	return c.disposition(path, name, "inline")
+}
+
+// Content sends the content supporting the Range and If-Range requests,
+// a single range is served as 206 with Content-Range, multiple ranges
+// are served as the whole content. Last-Modified is set from modtime
+// unless it's zero, set ETag before calling it to validate If-Range and
+// If-None-Match with the entity tag.
+//
+//	obj, err := s.Get(c.Ctx(), key)
+//	c.Response().Header().Set(rest.HeaderETag, obj.ETag)
+//	return c.Content("video/mp4", obj, obj.LastModified)
+func (c *Context) Content(contentType string, content io.ReadSeeker, modtime time.Time) error {
+	size, err := content.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	h := c.response.Header()
+	if c.NotModified(modtime, h.Get(HeaderETag)) {
+		return nil
+	}
+
+	if contentType == "" {
+		contentType = MIMEOctetStream
+	}
+	c.writeContentType(contentType)
+	h.Set(HeaderAcceptRanges, "bytes")
+
+	req := c.Request()
+	start, length, code := int64(0), size, http.StatusOK
+	if rng := req.Header.Get(HeaderRange); rng != "" && (req.Method == http.MethodGet || req.Method == http.MethodHead) && c.ifRange(modtime) {
+		s, l, ok, multi := parseRange(rng, size)
+		switch {
+		case !ok:
+			h.Set(HeaderContentRange, fmt.Sprintf("bytes */%d", size))
+			return ErrRangeNotSatisfiable
+		case !multi:
+			start, length, code = s, l, http.StatusPartialContent
+			h.Set(HeaderContentRange, fmt.Sprintf("bytes %d-%d/%d", s, s+l-1, size))
+		}
+	}
+
+	h.Set(HeaderContentLength, strconv.FormatInt(length, 10))
+	c.response.WriteHeader(code)
+	if req.Method == http.MethodHead {
+		return nil
+	}
+
+	if _, err = content.Seek(start, io.SeekStart); err != nil {
+		return err
+	}
+	_, err = io.CopyN(c.response, content, length)
+
+	return err
+}
+
+func (c *Context) disposition(path, name, kind string) error {
+	f, fi, err := openFile(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if name == "" {
+		name = fi.Name()
+	}
+	c.response.Header().Set(HeaderContentDisposition, mime.FormatMediaType(kind, map[string]string{"filename": name}))
+
+	return c.Content(typeByName(name), f, fi.ModTime())
+}
+
+// ifRange returns true when the Range should be served, If-Range holds
+// either the entity tag or the date of the representation.
+func (c *Context) ifRange(modtime time.Time) bool {
+	ir := c.Request().Header.Get(HeaderIfRange)
+	if ir == "" {
+		return true
+	}
+
+	// weak tag is never matched for the range
+	if strings.HasPrefix(ir, `"`) {
+		return ir == c.response.Header().Get(HeaderETag)
+	}
+
+	t, err := http.ParseTime(ir)
+	return err == nil && !modtime.IsZero() && modtime.Truncate(time.Second).Equal(t)
+}
+
+// parseRange parses the bytes range of the content size, ok is false when the
+// range is not satisfiable, multi is true for the multiple ranges.
+func parseRange(s string, size int64) (start, length int64, ok, multi bool) {
+	const prefix = "bytes="
+	if !strings.HasPrefix(s, prefix) {
+		// unknown unit is ignored, the whole content is served
+		return 0, size, true, true
+	}
+	s = strings.TrimSpace(s[len(prefix):])
+	if strings.Contains(s, ",") {
+		return 0, size, true, true
+	}
+
+	i := strings.Index(s, "-")
+	if i < 0 {
+		return 0, 0, false, false
+	}
+	first, last := strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:])
+
+	if first == "" {
+		// suffix range, ex. bytes=-500 is the last 500 bytes
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n <= 0 || size == 0 {
+			return 0, 0, false, false
+		}
+		if n > size {
+			n = size
+		}
+		return size - n, n, true, false
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 || start >= size {
+		return 0, 0, false, false
+	}
+
+	end := size - 1
+	if last != "" {
+		if end, err = strconv.ParseInt(last, 10, 64); err != nil || end < start {
+			return 0, 0, false, false
+		}
+		if end >= size {
+			end = size - 1
+		}
+	}
+
+	return start, end - start + 1, true, false
+}
+
+func openFile(path string) (*os.File, os.FileInfo, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil, ErrNotFound
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	fi, err := f.Stat()
+	if err == nil && fi.IsDir() {
+		err = ErrNotFound
+	}
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+
+	return f, fi, nil
+}
+
+func typeByName(name string) string {
+	if t := mime.TypeByExtension(filepath.Ext(name)); t != "" {
+		return t
+	}
+	return MIMEOctetStream
+}
@@ -0,0 +1,116 @@
+package rest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextContentRange(t *testing.T) {
+	e := New()
+	modified := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	body := "0123456789"
+
+	tests := []struct {
+		name    string
+		method  string
+		etag    string
+		headers map[string]string
+		code    int
+		rng     string
+		want    string
+	}{
+		{"full", http.MethodGet, "", nil, http.StatusOK, "", body},
+		{"range", http.MethodGet, "", map[string]string{HeaderRange: "bytes=2-5"}, http.StatusPartialContent, "bytes 2-5/10", "2345"},
+		{"open range", http.MethodGet, "", map[string]string{HeaderRange: "bytes=7-"}, http.StatusPartialContent, "bytes 7-9/10", "789"},
+		{"suffix range", http.MethodGet, "", map[string]string{HeaderRange: "bytes=-3"}, http.StatusPartialContent, "bytes 7-9/10", "789"},
+		{"end beyond size", http.MethodGet, "", map[string]string{HeaderRange: "bytes=8-20"}, http.StatusPartialContent, "bytes 8-9/10", "89"},
+		{"multiple ranges", http.MethodGet, "", map[string]string{HeaderRange: "bytes=0-1,4-5"}, http.StatusOK, "", body},
+		{"unknown unit", http.MethodGet, "", map[string]string{HeaderRange: "items=0-1"}, http.StatusOK, "", body},
+		{"unsatisfiable", http.MethodGet, "", map[string]string{HeaderRange: "bytes=10-"}, http.StatusRequestedRangeNotSatisfiable, "bytes */10", `{"message":"Requested Range Not Satisfiable"}`},
+		{"head", http.MethodHead, "", map[string]string{HeaderRange: "bytes=2-5"}, http.StatusPartialContent, "bytes 2-5/10", ""},
+		{"if-range date", http.MethodGet, "", map[string]string{HeaderRange: "bytes=2-5", HeaderIfRange: modified.Format(http.TimeFormat)}, http.StatusPartialContent, "bytes 2-5/10", "2345"},
+		{"if-range stale date", http.MethodGet, "", map[string]string{HeaderRange: "bytes=2-5", HeaderIfRange: modified.Add(-time.Hour).Format(http.TimeFormat)}, http.StatusOK, "", body},
+		{"if-range etag", http.MethodGet, `"v1"`, map[string]string{HeaderRange: "bytes=2-5", HeaderIfRange: `"v1"`}, http.StatusPartialContent, "bytes 2-5/10", "2345"},
+		{"if-range stale etag", http.MethodGet, `"v2"`, map[string]string{HeaderRange: "bytes=2-5", HeaderIfRange: `"v1"`}, http.StatusOK, "", body},
+		{"if-range weak etag", http.MethodGet, `W/"v1"`, map[string]string{HeaderRange: "bytes=2-5", HeaderIfRange: `W/"v1"`}, http.StatusOK, "", body},
+		{"not modified", http.MethodGet, `"v1"`, map[string]string{HeaderRange: "bytes=2-5", HeaderIfNoneMatch: `"v1"`}, http.StatusNotModified, "", ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/", nil)
+		for k, v := range tt.headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if tt.etag != "" {
+			c.Response().Header().Set(HeaderETag, tt.etag)
+		}
+
+		err := c.Content("text/plain", strings.NewReader(body), modified)
+		if err != nil {
+			e.HTTPErrorHandler(err, c)
+		}
+
+		assert.Equal(t, tt.code, rec.Code, tt.name)
+		assert.Equal(t, tt.rng, rec.Header().Get(HeaderContentRange), tt.name)
+		assert.Equal(t, tt.want, rec.Body.String(), tt.name)
+		if tt.code == http.StatusOK || tt.code == http.StatusPartialContent {
+			assert.Equal(t, "bytes", rec.Header().Get(HeaderAcceptRanges), tt.name)
+			assert.Equal(t, modified.Format(http.TimeFormat), rec.Header().Get(HeaderLastModified), tt.name)
+		}
+	}
+}
+
+func TestContextStreamRange(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderRange, "bytes=0-3")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	// the stream is sent from its position, range is served by Content only
+	r := strings.NewReader("response from a stream")
+	r.Seek(9, io.SeekStart)
+	if assert.NoError(t, c.Stream(http.StatusOK, "video/mp4", r)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get(HeaderContentRange))
+		assert.Equal(t, "from a stream", rec.Body.String())
+	}
+}
+
+func TestContextAttachment(t *testing.T) {
+	e := New()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.csv")
+	assert.NoError(t, os.WriteFile(path, []byte("id,name\n1,a\n"), 0644))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderRange, "bytes=8-")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	if assert.NoError(t, c.Attachment(path, "orders 2019.csv")) {
+		assert.Equal(t, http.StatusPartialContent, rec.Code)
+		assert.Equal(t, `attachment; filename="orders 2019.csv"`, rec.Header().Get(HeaderContentDisposition))
+		assert.Contains(t, rec.Header().Get(HeaderContentType), "text/csv")
+		assert.Equal(t, "1,a\n", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	if assert.NoError(t, c.Inline(path, "")) {
+		assert.Equal(t, `inline; filename=report.csv`, rec.Header().Get(HeaderContentDisposition))
+	}
+
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	assert.Equal(t, ErrNotFound, c.File(filepath.Join(dir, "missing.csv")))
+	assert.Equal(t, ErrNotFound, c.File(dir))
+}
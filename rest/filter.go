@@ -0,0 +1,192 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// FilterOp is the operator of the filter.
+type FilterOp string
+
+// Filter operators, used as ?filter[field][op]=value, eq is used
+// when the operator is omitted.
+const (
+	OpEq   FilterOp = "eq"
+	OpNe   FilterOp = "ne"
+	OpGt   FilterOp = "gt"
+	OpGte  FilterOp = "gte"
+	OpLt   FilterOp = "lt"
+	OpLte  FilterOp = "lte"
+	OpLike FilterOp = "like"
+	OpIn   FilterOp = "in"
+	OpNin  FilterOp = "nin"
+	OpNull FilterOp = "null"
+)
+
+var (
+	filterRegex = regexp.MustCompile(`^filter\[([^\[\]]+)\](?:\[([a-z]+)\])?$`)
+
+	filterOps = map[FilterOp]string{
+		OpEq:   " = ?",
+		OpNe:   " <> ?",
+		OpGt:   " > ?",
+		OpGte:  " >= ?",
+		OpLt:   " < ?",
+		OpLte:  " <= ?",
+		OpLike: " LIKE ?",
+		OpIn:   " IN ",
+		OpNin:  " NOT IN ",
+		OpNull: " IS NULL",
+	}
+)
+
+type (
+	// Filter is a condition on a field requested by the client.
+	Filter struct {
+		Field  string   `json:"field"`
+		Column string   `json:"-"`
+		Op     FilterOp `json:"op"`
+		Values []string `json:"values"`
+	}
+
+	// Filters are the conditions joined with AND and the sort order of the
+	// request, it can be passed into qb.SelectBuilder.Filter.
+	Filters struct {
+		Conditions []Filter `json:"conditions"`
+		Sort       []string `json:"sort"`
+	}
+)
+
+// ParseFilters parses filter and sort query params of the request, only
+// fields in the allowed list can be filtered and sorted, the field can be
+// mapped into different column using "field:column".
+//
+//	// ?filter[status]=paid&filter[total][gte]=100&filter[id][in]=1,2&sort=-created_at
+//	f, err := rest.ParseFilters(c, []string{"status", "total", "id", "created_at", "customer:c.name"})
+//	qb.Select().From("orders").Filter(f)
+func ParseFilters(c *Context, allowed []string) (*Filters, error) {
+	columns := make(map[string]string, len(allowed))
+	for _, a := range allowed {
+		if i := strings.Index(a, ":"); i > 0 {
+			columns[a[:i]] = a[i+1:]
+		} else {
+			columns[a] = a
+		}
+	}
+
+	params := c.QueryParams()
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	f := new(Filters)
+	for _, k := range keys {
+		m := filterRegex.FindStringSubmatch(k)
+		if m == nil {
+			if strings.HasPrefix(k, "filter[") {
+				return nil, NewHTTPError(http.StatusBadRequest, "Invalid filter "+k)
+			}
+			continue
+		}
+
+		column, ok := columns[m[1]]
+		if !ok {
+			return nil, NewHTTPError(http.StatusBadRequest, "Filter on "+m[1]+" is not allowed")
+		}
+
+		op := OpEq
+		if m[2] != "" {
+			op = FilterOp(m[2])
+		}
+		if _, ok := filterOps[op]; !ok {
+			return nil, NewHTTPError(http.StatusBadRequest, "Invalid filter operator "+m[2])
+		}
+
+		values := params[k]
+		if op == OpIn || op == OpNin {
+			var v []string
+			for _, s := range values {
+				v = append(v, strings.Split(s, ",")...)
+			}
+			values = v
+		} else if op == OpEq && len(values) > 1 {
+			op = OpIn
+		} else {
+			values = values[:1]
+		}
+
+		f.Conditions = append(f.Conditions, Filter{Field: m[1], Column: column, Op: op, Values: values})
+	}
+
+	if s := c.QueryParam("sort"); s != "" {
+		for _, field := range strings.Split(s, ",") {
+			dir := ""
+			if field = strings.TrimSpace(field); strings.HasPrefix(field, "-") {
+				field, dir = field[1:], "-"
+			}
+
+			column, ok := columns[field]
+			if !ok {
+				return nil, NewHTTPError(http.StatusBadRequest, "Sort by "+field+" is not allowed")
+			}
+
+			f.Sort = append(f.Sort, dir+column)
+		}
+	}
+
+	return f, nil
+}
+
+// SQL returns the condition of the filter with its arguments.
+func (f Filter) SQL() (string, []interface{}) {
+	switch f.Op {
+	case OpIn, OpNin:
+		args := make([]interface{}, len(f.Values))
+		for i, v := range f.Values {
+			args[i] = v
+		}
+		return f.Column + filterOps[f.Op] + "(" + strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ") + ")", args
+	case OpNull:
+		if v := f.value(); v == "false" || v == "0" {
+			return f.Column + " IS NOT NULL", nil
+		}
+		return f.Column + filterOps[f.Op], nil
+	case OpLike:
+		return f.Column + filterOps[f.Op], []interface{}{"%" + f.value() + "%"}
+	}
+
+	return f.Column + filterOps[f.Op], []interface{}{f.value()}
+}
+
+func (f Filter) value() string {
+	if len(f.Values) == 0 {
+		return ""
+	}
+	return f.Values[0]
+}
+
+// Where returns the conditions joined with AND, empty when there is no filter.
+func (f *Filters) Where() (string, []interface{}) {
+	var parts []string
+	var args []interface{}
+	for _, c := range f.Conditions {
+		s, a := c.SQL()
+		parts = append(parts, s)
+		args = append(args, a...)
+	}
+
+	return strings.Join(parts, " AND "), args
+}
+
+// OrderBy returns the sort columns, prefixed with - for descending.
+func (f *Filters) OrderBy() []string {
+	return f.Sort
+}
@@ -0,0 +1,62 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFilters(t *testing.T) {
+	e := New()
+	allowed := []string{"status", "total", "id", "note", "deleted_at", "created_at", "customer:c.name"}
+
+	req := httptest.NewRequest(http.MethodGet, "/?filter[status]=paid&filter[total][gte]=100&filter[id][in]=1,2&filter[id][in]=3"+
+		"&filter[customer][like]=jon&filter[deleted_at][null]=true&filter[note][null]=0&sort=-created_at,customer&page=2", nil)
+	f, err := ParseFilters(e.NewContext(req, nil), allowed)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []Filter{
+			{Field: "customer", Column: "c.name", Op: OpLike, Values: []string{"jon"}},
+			{Field: "deleted_at", Column: "deleted_at", Op: OpNull, Values: []string{"true"}},
+			{Field: "id", Column: "id", Op: OpIn, Values: []string{"1", "2", "3"}},
+			{Field: "note", Column: "note", Op: OpNull, Values: []string{"0"}},
+			{Field: "status", Column: "status", Op: OpEq, Values: []string{"paid"}},
+			{Field: "total", Column: "total", Op: OpGte, Values: []string{"100"}},
+		}, f.Conditions)
+		assert.Equal(t, []string{"-created_at", "c.name"}, f.OrderBy())
+
+		sql, args := f.Where()
+		assert.Equal(t, "c.name LIKE ? AND deleted_at IS NULL AND id IN (?, ?, ?) AND note IS NOT NULL AND status = ? AND total >= ?", sql)
+		assert.Equal(t, []interface{}{"%jon%", "1", "2", "3", "paid", "100"}, args)
+	}
+
+	// multiple values of eq becomes in
+	req = httptest.NewRequest(http.MethodGet, "/?filter[status]=paid&filter[status]=new", nil)
+	f, err = ParseFilters(e.NewContext(req, nil), allowed)
+	if assert.NoError(t, err) {
+		sql, args := f.Where()
+		assert.Equal(t, "status IN (?, ?)", sql)
+		assert.Equal(t, []interface{}{"paid", "new"}, args)
+	}
+}
+
+func TestParseFiltersNotAllowed(t *testing.T) {
+	e := New()
+	allowed := []string{"status"}
+
+	for _, q := range []string{
+		"filter[password]=x",
+		"filter[status][regex]=x",
+		"filter[status]]=x",
+		"filter[status][eq][x]=1",
+		"sort=password",
+		"sort=-status%3Bdrop",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/?"+q, nil)
+		_, err := ParseFilters(e.NewContext(req, nil), allowed)
+		if assert.Error(t, err, q) {
+			assert.Equal(t, http.StatusBadRequest, err.(*HTTPError).Code, q)
+		}
+	}
+}
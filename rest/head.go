@@ -0,0 +1,80 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// headWriter discards the body written by GET handler serving HEAD request,
+// the header is held until the handler returns so Content-Length is set
+// to the size of the discarded body.
+type headWriter struct {
+	w       http.ResponseWriter
+	code    int
+	size    int64
+	flushed bool
+}
+
+// autoHead runs the chain of GET handler for HEAD request, the load balancers
+// and uptime checkers get the same headers as GET without the body.
+func autoHead(next HandlerFunc) HandlerFunc {
+	return func(c *Context) error {
+		res := c.Response()
+		w := res.Writer
+		hw := &headWriter{w: w}
+		res.Writer = hw
+		err := next(c)
+		res.Writer = w
+		res.Size = 0
+
+		hw.finish()
+		return err
+	}
+}
+
+func (h *headWriter) Header() http.Header {
+	return h.w.Header()
+}
+
+func (h *headWriter) WriteHeader(code int) {
+	if h.code == 0 {
+		h.code = code
+	}
+}
+
+func (h *headWriter) Write(b []byte) (int, error) {
+	if h.code == 0 {
+		h.code = http.StatusOK
+	}
+	h.size += int64(len(b))
+	return len(b), nil
+}
+
+// Flush sends the header, Content-Length is unknown from now on.
+func (h *headWriter) Flush() {
+	if !h.flushed && h.code != 0 {
+		h.flushed = true
+		h.w.WriteHeader(h.code)
+	}
+	if f, ok := h.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// finish sends the header held, nothing is sent when the handler didn't
+// respond, so the error handler responds.
+func (h *headWriter) finish() {
+	if h.flushed || h.code == 0 {
+		return
+	}
+
+	hd := h.w.Header()
+	if hd.Get(HeaderContentLength) == "" && h.code != http.StatusNoContent && h.code != http.StatusNotModified && h.code >= http.StatusOK {
+		hd.Set(HeaderContentLength, strconv.FormatInt(h.size, 10))
+	}
+	h.w.WriteHeader(h.code)
+}
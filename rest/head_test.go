@@ -0,0 +1,66 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestAutoHead(t *testing.T) {
+	e := New()
+	e.GET("/users/:id", func(c *Context) error {
+		c.Response().Header().Set("X-User", c.Param("id"))
+		return c.String(http.StatusOK, "user "+c.Param("id"))
+	})
+	e.GET("/download", func(c *Context) error {
+		c.Response().Header().Set(HeaderContentLength, "1024")
+		return c.NoContent(http.StatusOK)
+	})
+	e.GET("/missing", func(c *Context) error {
+		return ErrNotFound
+	})
+	e.HEAD("/explicit", func(c *Context) error {
+		c.Response().Header().Set("X-Head", "1")
+		return c.NoContent(http.StatusOK)
+	})
+	e.GET("/explicit", func(c *Context) error {
+		return c.String(http.StatusOK, "get")
+	})
+	e.POST("/orders", func(c *Context) error {
+		return c.NoContent(http.StatusCreated)
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/users/7", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "7", rec.Header().Get("X-User"))
+	assert.Equal(t, MIMETextPlainCharsetUTF8, rec.Header().Get(HeaderContentType))
+	assert.Equal(t, "6", rec.Header().Get(HeaderContentLength))
+	assert.Empty(t, rec.Body.String())
+
+	// the content length set by the handler is kept
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/download", nil))
+	assert.Equal(t, "1024", rec.Header().Get(HeaderContentLength))
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/missing", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/explicit", nil))
+	assert.Equal(t, "1", rec.Header().Get("X-Head"))
+
+	code, _ := request(http.MethodHead, "/orders", e)
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+	code, _ = request(http.MethodHead, "/nowhere", e)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	// the GET is not affected
+	code, body := request(http.MethodGet, "/users/7", e)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "user 7", body)
+}
@@ -0,0 +1,104 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type (
+	// StartOption configures the start hook.
+	StartOption func(*startHook)
+
+	// startHook is executed after the listener is bound and before serving.
+	startHook struct {
+		name    string
+		fn      func(ctx context.Context) error
+		timeout time.Duration
+		logOnly bool
+	}
+)
+
+// HookName sets name of the hook, used in the logs.
+func HookName(name string) StartOption {
+	return func(h *startHook) {
+		h.name = name
+	}
+}
+
+// HookTimeout sets maximum duration of the hook, the context
+// passed into the hook is canceled after the timeout.
+func HookTimeout(d time.Duration) StartOption {
+	return func(h *startHook) {
+		h.timeout = d
+	}
+}
+
+// HookContinue makes the failure of the hook only logged, by default
+// the failure aborts the start and returned by Start.
+func HookContinue() StartOption {
+	return func(h *startHook) {
+		h.logOnly = true
+	}
+}
+
+// OnStart adds hook that is executed after the listener is bound but
+// before the requests are served, ex. warming the cache, checking the
+// migrations or connecting into the broker. Hooks are executed once
+// in the order they were added.
+//
+//	e.OnStart(warmCache, rest.HookName("cache"), rest.HookTimeout(30*time.Second), rest.HookContinue())
+func (e *Rest) OnStart(fn func(ctx context.Context) error, opts ...StartOption) {
+	h := &startHook{name: fmt.Sprintf("hook#%d", len(e.startHooks)+1), fn: fn}
+	for _, o := range opts {
+		o(h)
+	}
+
+	e.startHooks = append(e.startHooks, h)
+}
+
+// runStartHooks executes the start hooks once, the error of aborting hook
+// is returned on every call so both http and https server are aborted.
+func (e *Rest) runStartHooks() error {
+	e.startOnce.Do(func() {
+		for _, h := range e.startHooks {
+			if err := h.run(); err != nil {
+				if !h.logOnly {
+					e.startErr = fmt.Errorf("rest: start hook %s failed: %v", h.name, err)
+					return
+				}
+
+				e.Logger.Warn("start hook failed", zap.String("hook", h.name), zap.Error(err))
+			}
+		}
+	})
+
+	return e.startErr
+}
+
+func (h *startHook) run() error {
+	ctx := context.Background()
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- h.fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
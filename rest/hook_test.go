@@ -0,0 +1,81 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestOnStart(t *testing.T) {
+	e := New()
+	e.GET("/", func(c *Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	var order []string
+	ready := make(chan struct{})
+	e.OnStart(func(ctx context.Context) error {
+		order = append(order, "cache")
+		return nil
+	})
+	e.OnStart(func(ctx context.Context) error {
+		order = append(order, "broker")
+		return errors.New("connection refused")
+	}, HookName("broker"), HookContinue())
+	e.OnStart(func(ctx context.Context) error {
+		order = append(order, "ready")
+		close(ready)
+		return nil
+	})
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	e.Listener = l
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- e.Start("")
+	}()
+
+	<-ready
+	assert.Equal(t, []string{"cache", "broker", "ready"}, order)
+
+	res, err := http.Get("http://" + l.Addr().String())
+	if assert.NoError(t, err) {
+		res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	}
+
+	assert.NoError(t, e.Close())
+	assert.Equal(t, http.ErrServerClosed, <-errCh)
+}
+
+func TestRestOnStartAbort(t *testing.T) {
+	e := New()
+	var called bool
+	e.OnStart(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, HookName("migration"), HookTimeout(10*time.Millisecond))
+	e.OnStart(func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	err := e.Start("127.0.0.1:0")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "start hook migration failed")
+	}
+	assert.False(t, called)
+
+	// the listener is closed
+	_, err = e.Listener.Accept()
+	assert.Error(t, err)
+}
@@ -0,0 +1,57 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"strings"
+	"time"
+
+	"github.com/enigma-id/go/i18n"
+	"github.com/enigma-id/go/validation"
+)
+
+// Locale returns locale of the request, set by Locale middleware.
+func (c *Context) Locale() string {
+	return i18n.Locale(c.Request().Context())
+}
+
+// Timezone returns timezone of the user set by mw.Locale, time.Local when not set.
+func (c *Context) Timezone() *time.Location {
+	return i18n.Timezone(c.Request().Context())
+}
+
+// T translates the key using locale of the request.
+func (c *Context) T(key string, args ...map[string]interface{}) string {
+	return i18n.T(c.Request().Context(), key, args...)
+}
+
+// translateResponse translates message and validation errors of the response,
+// validation messages use "validation.<rule>" key with the placeholders
+// of validation.Response.Args, ex. :attribute and :param.
+func (c *Context) translateResponse(err error) {
+	ctx := c.Request().Context()
+	if msg, ok := c.ResponseBody.Message.(string); ok {
+		c.ResponseBody.Message = i18n.T(ctx, msg)
+	}
+
+	if o := validationErrors(err); o != nil {
+		c.ResponseBody.Errors = c.translateErrors(o)
+	}
+}
+
+// translateErrors translates the validation errors.
+func (c *Context) translateErrors(o *validation.Response) map[string]string {
+	ctx := c.Request().Context()
+	o.Translate(func(k string, e string) string {
+		i := strings.LastIndex(k, ".")
+		if i < 0 {
+			return e
+		}
+
+		return i18n.TDefault(ctx, "validation."+k[i+1:], e, o.Args(k))
+	})
+
+	return o.GetErrors()
+}
@@ -0,0 +1,77 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+)
+
+// EnvInspect is the env variable of the file the route table is written
+// into by Start instead of serving, used by `dev routes` to list the
+// routes of the project without running it.
+const EnvInspect = "REST_INSPECT"
+
+// ErrInspected returned by Start in inspection mode, after the route
+// table is written.
+var ErrInspected = errors.New("rest: routes inspected, server is not started")
+
+// InspectedRoute is the route of the route table written in inspection mode.
+type InspectedRoute struct {
+	Method  string   `json:"method"`
+	Path    string   `json:"path"`
+	Name    string   `json:"name"`
+	Summary string   `json:"summary,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+	Scopes  []string `json:"scopes,omitempty"`
+}
+
+// RouteTable returns the registered routes sorted by the path and method,
+// along with their metadata.
+func (e *Rest) RouteTable() []InspectedRoute {
+	routes := e.Routes()
+	table := make([]InspectedRoute, 0, len(routes))
+	for _, r := range routes {
+		i := r.Info()
+		table = append(table, InspectedRoute{
+			Method:  r.Method,
+			Path:    r.Path,
+			Name:    r.Name,
+			Summary: i.Summary,
+			Tags:    i.Tags,
+			Scopes:  i.Scopes,
+		})
+	}
+
+	sort.Slice(table, func(i, j int) bool {
+		if table[i].Path != table[j].Path {
+			return table[i].Path < table[j].Path
+		}
+		return table[i].Method < table[j].Method
+	})
+
+	return table
+}
+
+// inspect writes the route table into the file of EnvInspect,
+// false when the inspection mode is off.
+func (e *Rest) inspect() (bool, error) {
+	file := os.Getenv(EnvInspect)
+	if file == "" {
+		return false, nil
+	}
+
+	b, err := json.MarshalIndent(e.RouteTable(), "", "  ")
+	if err == nil {
+		err = os.WriteFile(file, b, 0644)
+	}
+	if err != nil {
+		return true, err
+	}
+
+	return true, ErrInspected
+}
@@ -0,0 +1,51 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInspect(t *testing.T) {
+	e := New()
+	h := func(c *Context) error { return c.NoContent(http.StatusOK) }
+	e.POST("/orders", h)
+	e.GET("/orders/:id", h).Scopes("orders:read").Summary("Show order").Tags("orders")
+	e.GET("/orders", h)
+
+	file := filepath.Join(t.TempDir(), "routes.json")
+	t.Setenv(EnvInspect, file)
+
+	started := false
+	e.OnStart(func(ctx context.Context) error {
+		started = true
+		return nil
+	})
+	assert.Equal(t, ErrInspected, e.Start("127.0.0.1:0"))
+	assert.False(t, started, "start hooks are not run in inspection mode")
+	assert.Nil(t, e.Listener)
+
+	b, err := os.ReadFile(file)
+	assert.NoError(t, err)
+
+	var table []InspectedRoute
+	assert.NoError(t, json.Unmarshal(b, &table))
+	if assert.Len(t, table, 3) {
+		assert.Equal(t, "GET /orders", table[0].Method+" "+table[0].Path)
+		assert.Equal(t, "POST /orders", table[1].Method+" "+table[1].Path)
+		assert.Equal(t, InspectedRoute{
+			Method:  http.MethodGet,
+			Path:    "/orders/:id",
+			Name:    table[2].Name,
+			Summary: "Show order",
+			Tags:    []string{"orders"},
+			Scopes:  []string{"orders:read"},
+		}, table[2])
+		assert.NotEmpty(t, table[2].Name)
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"math/rand"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/enigma-id/go/rest/openapi"
+)
+
+type (
+	// MockOption configures the routes of MockFromOpenAPI.
+	MockOption func(*mock)
+
+	mock struct {
+		doc         *openapi.Document
+		minLatency  time.Duration
+		maxLatency  time.Duration
+		errorRate   float64
+		errorStatus int
+		rand        func() float64
+	}
+)
+
+// mockPrefer selects the response by the Prefer header, ex. "Prefer: code=404".
+var mockPrefer = regexp.MustCompile(`code=(\d{3})`)
+
+// MockLatency delays each response by random duration between min and max.
+func MockLatency(min, max time.Duration) MockOption {
+	return func(m *mock) {
+		m.minLatency, m.maxLatency = min, max
+	}
+}
+
+// MockErrorRate fails the rate (0 to 1) of the requests with the status,
+// the example of the status is returned when the operation defines it.
+func MockErrorRate(rate float64, status int) MockOption {
+	return func(m *mock) {
+		m.errorRate, m.errorStatus = rate, status
+	}
+}
+
+// MockFromOpenAPI registers routes of the operations of the spec that return
+// the example responses, so the frontend can develop against the mock before
+// the handlers exist. The success response with the lowest code is returned,
+// "Prefer: code=404" header selects other response of the operation.
+// Body is the example of the json content, generated from the schema when
+// there is none.
+//
+//	var doc openapi.Document
+//	json.Unmarshal(spec, &doc)
+//	e.MockFromOpenAPI(&doc, rest.MockLatency(50*time.Millisecond, 300*time.Millisecond))
+func (e *Rest) MockFromOpenAPI(spec *openapi.Document, opts ...MockOption) []*Route {
+	m := &mock{doc: spec, errorStatus: http.StatusInternalServerError, rand: rand.Float64}
+	for _, o := range opts {
+		o(m)
+	}
+
+	paths := make([]string, 0, len(spec.Paths))
+	for p := range spec.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var routes []*Route
+	for _, p := range paths {
+		item := spec.Paths[p]
+		for _, method := range methods {
+			op := item.Operation(method)
+			if op == nil {
+				continue
+			}
+
+			r := e.Add(method, routePath(p), m.handler(op)).Summary(op.Summary).Description(op.Description)
+			if len(op.Tags) > 0 {
+				r.Tags(op.Tags...)
+			}
+			routes = append(routes, r)
+		}
+	}
+
+	return routes
+}
+
+func (m *mock) handler(op *openapi.Operation) HandlerFunc {
+	return func(c *Context) error {
+		if err := m.delay(c); err != nil {
+			return err
+		}
+
+		code, res := m.response(op, c.Request().Header.Get("Prefer"))
+		if code == 0 {
+			return NewHTTPError(m.errorStatus)
+		}
+		if res == nil {
+			return c.NoContent(code)
+		}
+
+		for ct, mt := range res.Content {
+			if !strings.Contains(ct, "json") {
+				continue
+			}
+			if mt.Example != nil {
+				return c.JSON(code, mt.Example)
+			}
+			return c.JSON(code, m.example(mt.Schema, 0))
+		}
+
+		return c.NoContent(code)
+	}
+}
+
+// response returns the code and response of the operation, code 0 is the
+// injected error that is not defined by the operation.
+func (m *mock) response(op *openapi.Operation, prefer string) (int, *openapi.Response) {
+	if s := mockPrefer.FindStringSubmatch(prefer); s != nil {
+		if res, ok := op.Responses[s[1]]; ok {
+			code, _ := strconv.Atoi(s[1])
+			return code, res
+		}
+	}
+
+	if m.errorRate > 0 && m.rand() < m.errorRate {
+		if res, ok := op.Responses[strconv.Itoa(m.errorStatus)]; ok {
+			return m.errorStatus, res
+		}
+		return 0, nil
+	}
+
+	codes := make([]string, 0, len(op.Responses))
+	for k := range op.Responses {
+		if strings.HasPrefix(k, "2") && len(k) == 3 {
+			codes = append(codes, k)
+		}
+	}
+	sort.Strings(codes)
+
+	if len(codes) > 0 {
+		code, _ := strconv.Atoi(codes[0])
+		return code, op.Responses[codes[0]]
+	}
+
+	return http.StatusOK, op.Responses["default"]
+}
+
+func (m *mock) delay(c *Context) error {
+	d := m.minLatency
+	if m.maxLatency > m.minLatency {
+		d += time.Duration(m.rand() * float64(m.maxLatency-m.minLatency))
+	}
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-c.Request().Context().Done():
+		return c.Request().Context().Err()
+	}
+}
+
+// example generates example of the schema, $ref is resolved from the components.
+func (m *mock) example(s *openapi.Schema, depth int) interface{} {
+	if s == nil || depth > 16 {
+		return nil
+	}
+	if s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, "#/components/schemas/")
+		if m.doc.Components == nil {
+			return nil
+		}
+		return m.example(m.doc.Components.Schemas[name], depth+1)
+	}
+	if s.Example != nil {
+		return s.Example
+	}
+	if len(s.Enum) > 0 {
+		return s.Enum[0]
+	}
+
+	switch s.Type {
+	case "object", "":
+		if s.Type == "" && len(s.Properties) == 0 {
+			return nil
+		}
+		obj := make(map[string]interface{}, len(s.Properties))
+		for k, p := range s.Properties {
+			obj[k] = m.example(p, depth+1)
+		}
+		return obj
+	case "array":
+		return []interface{}{m.example(s.Items, depth+1)}
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	}
+
+	switch s.Format {
+	case "date-time":
+		return "2018-01-01T00:00:00Z"
+	case "date":
+		return "2018-01-01"
+	case "email":
+		return "user@example.com"
+	case "uuid":
+		return "00000000-0000-4000-8000-000000000000"
+	case "uri", "url":
+		return "https://example.com"
+	}
+
+	return "string"
+}
+
+// routePath converts OpenAPI path template into the route path,
+// ex. /orders/{id} to /orders/:id.
+func routePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			segments[i] = ":" + s[1:len(s)-1]
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
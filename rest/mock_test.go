@@ -0,0 +1,108 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/enigma-id/go/rest/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+const mockSpec = `{
+	"openapi": "3.1.0",
+	"info": {"title": "Orders", "version": "1.0"},
+	"paths": {
+		"/orders": {
+			"get": {
+				"summary": "List orders",
+				"responses": {
+					"200": {"description": "OK", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Order"}}}}}
+				}
+			},
+			"post": {
+				"responses": {
+					"201": {"description": "Created", "content": {"application/json": {"example": {"id": 1}}}},
+					"422": {"description": "Invalid", "content": {"application/json": {"example": {"status": "failure"}}}}
+				}
+			}
+		},
+		"/orders/{id}": {
+			"delete": {"responses": {"204": {"description": "No Content"}}}
+		}
+	},
+	"components": {
+		"schemas": {
+			"Order": {
+				"type": "object",
+				"properties": {
+					"id": {"type": "integer", "example": 7},
+					"status": {"type": "string", "enum": ["paid", "void"]},
+					"email": {"type": "string", "format": "email"},
+					"paid": {"type": "boolean"}
+				}
+			}
+		}
+	}
+}`
+
+func TestMockFromOpenAPI(t *testing.T) {
+	var doc openapi.Document
+	assert.NoError(t, json.Unmarshal([]byte(mockSpec), &doc))
+
+	e := New()
+	routes := e.MockFromOpenAPI(&doc)
+	assert.Len(t, routes, 3)
+	assert.Equal(t, "List orders", routes[0].Info().Summary)
+
+	send := func(method, path, prefer string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if prefer != "" {
+			req.Header.Set("Prefer", prefer)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send(http.MethodGet, "/orders", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[{"id":7,"status":"paid","email":"user@example.com","paid":false}]`, rec.Body.String())
+
+	rec = send(http.MethodPost, "/orders", "")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.JSONEq(t, `{"id":1}`, rec.Body.String())
+
+	rec = send(http.MethodPost, "/orders", "code=422")
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.JSONEq(t, `{"status":"failure"}`, rec.Body.String())
+
+	assert.Equal(t, http.StatusNoContent, send(http.MethodDelete, "/orders/1", "").Code)
+}
+
+func TestMockInjection(t *testing.T) {
+	var doc openapi.Document
+	assert.NoError(t, json.Unmarshal([]byte(mockSpec), &doc))
+
+	e := New()
+	e.MockFromOpenAPI(&doc, MockErrorRate(1, http.StatusUnprocessableEntity), MockLatency(10*time.Millisecond, 20*time.Millisecond))
+
+	start := time.Now()
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/orders", nil))
+	assert.True(t, time.Since(start) >= 10*time.Millisecond)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.JSONEq(t, `{"status":"failure"}`, rec.Body.String())
+
+	// the status is not defined by the operation
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders", nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestRoutePath(t *testing.T) {
+	assert.Equal(t, "/orders/:id/items/:item", routePath("/orders/{id}/items/{item}"))
+	assert.Equal(t, "/health", routePath("/health"))
+}
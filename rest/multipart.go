@@ -0,0 +1,178 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"bufio"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+type (
+	// MultipartConfig defines the limits of Context.Multipart.
+	MultipartConfig struct {
+		// MaxPartSize is the maximum size of each file in bytes, 0 is unlimited.
+		MaxPartSize int64
+
+		// FileFields is the form names of the files passed into the handler,
+		// the files of the other names are skipped. Empty means all files.
+		FileFields []string
+
+		// MaxParts is the maximum number of the parts, 0 is unlimited.
+		MaxParts int
+
+		// AllowedTypes is the list of allowed content type of the files, detected
+		// from the content rather than trusting the request, ex. image/png, image/*.
+		AllowedTypes []string
+
+		// Progress is called on every read of the request body with the bytes
+		// read so far and the content length, -1 when it's unknown.
+		Progress func(read, total int64)
+	}
+
+	// Part is a part of the streaming multipart request,
+	// the content is read from the request as it arrives.
+	Part struct {
+		*multipart.Part
+
+		// ContentType of the file detected from the first 512 bytes,
+		// or the header of the part for the form field.
+		ContentType string
+
+		r        io.Reader
+		n        int64
+		max      int64
+		exceeded bool
+	}
+
+	// progressReader reports the bytes read of the request body.
+	progressReader struct {
+		io.ReadCloser
+		n, total int64
+		fn       func(read, total int64)
+	}
+)
+
+// Multipart reads the multipart request part by part, fn is called for each
+// part as it arrives without buffering the files into memory or temporary
+// files. The file exceeding MaxPartSize fails with 413 and the file with type
+// not in AllowedTypes fails with 415. Part that is not read by fn is skipped.
+//
+//	err := c.Multipart(rest.MultipartConfig{MaxPartSize: 1 << 30, AllowedTypes: []string{"video/*"}}, func(p *rest.Part) error {
+//		if !p.IsFile() {
+//			return nil
+//		}
+//		return s.Put(c.Ctx(), "videos/"+p.FileName(), p, -1, p.ContentType)
+//	})
+func (c *Context) Multipart(config MultipartConfig, fn func(p *Part) error) error {
+	req := c.Request()
+	if config.Progress != nil {
+		req.Body = &progressReader{ReadCloser: req.Body, total: req.ContentLength, fn: config.Progress}
+	}
+
+	mr, err := req.MultipartReader()
+	if err != nil {
+		return NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	for i := 0; ; i++ {
+		mp, err := mr.NextPart()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		if config.MaxParts > 0 && i >= config.MaxParts {
+			mp.Close()
+			return NewHTTPError(http.StatusRequestEntityTooLarge, "Too many parts")
+		}
+
+		p := &Part{Part: mp, r: mp, ContentType: mp.Header.Get(HeaderContentType)}
+		if p.IsFile() && len(config.FileFields) > 0 && !inStrings(p.FormName(), config.FileFields) {
+			mp.Close()
+			continue
+		}
+		if p.IsFile() {
+			br := bufio.NewReaderSize(mp, 512)
+			b, _ := br.Peek(512)
+			p.r, p.ContentType, p.max = br, http.DetectContentType(b), config.MaxPartSize
+
+			if !allowedType(p.ContentType, config.AllowedTypes) {
+				mp.Close()
+				return NewHTTPError(http.StatusUnsupportedMediaType, "File type "+p.ContentType+" is not allowed")
+			}
+		}
+
+		err = fn(p)
+		mp.Close()
+
+		if p.exceeded {
+			return ErrStatusRequestEntityTooLarge
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// IsFile returns true when the part is a file rather than a form field.
+func (p *Part) IsFile() bool {
+	return p.FileName() != ""
+}
+
+// Size returns the bytes read of the part so far.
+func (p *Part) Size() int64 {
+	return p.n
+}
+
+// Read reads the content of the part, it fails
+// with 413 when the file exceeds MaxPartSize.
+func (p *Part) Read(b []byte) (n int, err error) {
+	n, err = p.r.Read(b)
+	p.n += int64(n)
+	if p.max > 0 && p.n > p.max {
+		p.exceeded = true
+		return n, ErrStatusRequestEntityTooLarge
+	}
+
+	return
+}
+
+func (r *progressReader) Read(b []byte) (n int, err error) {
+	n, err = r.ReadCloser.Read(b)
+	if n > 0 {
+		r.n += int64(n)
+		r.fn(r.n, r.total)
+	}
+	return
+}
+
+// allowedType returns true when the content type matches one of the types,
+// the type can be a wildcard of the subtype, ex. image/*.
+func allowedType(ct string, types []string) bool {
+	if len(types) == 0 {
+		return true
+	}
+
+	ct = strings.TrimSpace(strings.Split(ct, ";")[0])
+	for _, t := range types {
+		if t == ct || (strings.HasSuffix(t, "/*") && strings.HasPrefix(ct, strings.TrimSuffix(t, "*"))) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func inStrings(s string, list []string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package rest
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextMultipart(t *testing.T) {
+	body := new(bytes.Buffer)
+	mw := multipart.NewWriter(body)
+	mw.WriteField("title", "holiday")
+	fw, _ := mw.CreateFormFile("video", "holiday.png")
+	fw.Write([]byte("\x89PNG\r\n\x1a\n" + strings.Repeat("0", 1000)))
+	mw.Close()
+
+	newContext := func() *Context {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body.Bytes()))
+		req.Header.Set(HeaderContentType, mw.FormDataContentType())
+		return New().NewContext(req, httptest.NewRecorder())
+	}
+
+	var (
+		parts    []string
+		read     int64
+		progress int
+	)
+	err := newContext().Multipart(MultipartConfig{
+		AllowedTypes: []string{"image/*"},
+		Progress: func(n, total int64) {
+			read = n
+			progress++
+			assert.Equal(t, int64(body.Len()), total)
+		},
+	}, func(p *Part) error {
+		b, err := io.ReadAll(p)
+		parts = append(parts, p.FormName()+":"+p.ContentType+":"+string(b[:4]))
+		if p.IsFile() {
+			assert.Equal(t, int64(1008), p.Size())
+		}
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"title::holi", "video:image/png:\x89PNG"}, parts)
+	assert.Equal(t, int64(body.Len()), read)
+	assert.NotZero(t, progress)
+
+	// unread part is skipped
+	err = newContext().Multipart(MultipartConfig{MaxPartSize: 100}, func(p *Part) error {
+		return nil
+	})
+	assert.NoError(t, err)
+
+	err = newContext().Multipart(MultipartConfig{MaxPartSize: 100}, func(p *Part) error {
+		_, err := io.Copy(io.Discard, p)
+		return err
+	})
+	assert.Equal(t, ErrStatusRequestEntityTooLarge, err)
+
+	err = newContext().Multipart(MultipartConfig{AllowedTypes: []string{"video/*"}}, func(p *Part) error {
+		return nil
+	})
+	if assert.IsType(t, new(HTTPError), err) {
+		assert.Equal(t, http.StatusUnsupportedMediaType, err.(*HTTPError).Code)
+	}
+
+	err = newContext().Multipart(MultipartConfig{MaxParts: 1}, func(p *Part) error {
+		return nil
+	})
+	if assert.IsType(t, new(HTTPError), err) {
+		assert.Equal(t, http.StatusRequestEntityTooLarge, err.(*HTTPError).Code)
+	}
+
+	c := New().NewContext(httptest.NewRequest(http.MethodPost, "/", nil), httptest.NewRecorder())
+	err = c.Multipart(MultipartConfig{}, func(p *Part) error { return nil })
+	if assert.IsType(t, new(HTTPError), err) {
+		assert.Equal(t, http.StatusBadRequest, err.(*HTTPError).Code)
+	}
+}
@@ -0,0 +1,134 @@
+package mw
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/enigma-id/go/auth"
+	"github.com/enigma-id/go/rest"
+	"go.uber.org/zap"
+)
+
+type (
+	// SecurityEvent is the allow or deny decision made by the JWT,
+	// KeyAuth and RBAC middlewares, to be collected as audit evidence.
+	SecurityEvent struct {
+		Time       time.Time `json:"time"`
+		Middleware string    `json:"middleware"` // jwt, key_auth or rbac
+		Allowed    bool      `json:"allowed"`
+		Principal  string    `json:"principal,omitempty"`
+		TenantID   string    `json:"tenant_id,omitempty"`
+		Method     string    `json:"method"`
+		Route      string    `json:"route"`
+		Path       string    `json:"path"`
+		IP         string    `json:"ip"`
+		RequestID  string    `json:"request_id,omitempty"`
+		Reason     string    `json:"reason,omitempty"` // one of the Reason constants
+		Error      string    `json:"error,omitempty"`
+	}
+
+	// AuditHook receives the security events, it's called synchronously
+	// on the request so it should hand the event off rather than block.
+	AuditHook func(c *rest.Context, e *SecurityEvent)
+)
+
+// Reasons of the security decisions.
+const (
+	ReasonMissingCredential = "missing_credential"
+	ReasonInvalidCredential = "invalid_credential"
+	ReasonInvalidPrincipal  = "invalid_principal"
+	ReasonValidatorError    = "validator_error"
+	ReasonUnauthenticated   = "unauthenticated"
+	ReasonMissingRole       = "missing_role"
+	ReasonMissingScope      = "missing_scope"
+	ReasonUnknownRoute      = "unknown_route"
+)
+
+// DefaultAuditHook receives the events of the middlewares without
+// their own AuditHook, so the decisions of every route can be collected
+// in one place. It's read on each request, so it can be set after the
+// middlewares are registered. Default value nil, no event is fired.
+//
+//	mw.DefaultAuditHook = mw.AuditLogger(logger)
+var DefaultAuditHook AuditHook
+
+// AuditLogger returns a hook writing the events into the logger,
+// the allowed as info and the denied as warning.
+func AuditLogger(logger *zap.Logger) AuditHook {
+	return func(c *rest.Context, e *SecurityEvent) {
+		fields := []zap.Field{
+			zap.Time("time", e.Time),
+			zap.String("middleware", e.Middleware),
+			zap.Bool("allowed", e.Allowed),
+			zap.String("principal", e.Principal),
+			zap.String("tenant_id", e.TenantID),
+			zap.String("method", e.Method),
+			zap.String("route", e.Route),
+			zap.String("path", e.Path),
+			zap.String("ip", e.IP),
+			zap.String("request_id", e.RequestID),
+			zap.String("reason", e.Reason),
+			zap.String("error", e.Error),
+		}
+		if e.Allowed {
+			logger.Info("security decision", fields...)
+		} else {
+			logger.Warn("security decision", fields...)
+		}
+	}
+}
+
+// audit fires the event of the decision into the hook, or the
+// DefaultAuditHook when it's nil. The principal is taken from
+// auth.Principal placed on the context, the subject otherwise.
+func audit(c *rest.Context, hook AuditHook, middleware, subject, reason string, err error) {
+	if hook == nil {
+		if hook = DefaultAuditHook; hook == nil {
+			return
+		}
+	}
+
+	req := c.Request()
+	e := &SecurityEvent{
+		Time:       time.Now(),
+		Middleware: middleware,
+		Allowed:    reason == "",
+		Principal:  subject,
+		Method:     req.Method,
+		Route:      c.Path(),
+		Path:       req.URL.Path,
+		IP:         c.RealIP(),
+		RequestID:  c.Response().Header().Get(rest.HeaderXRequestID),
+		Reason:     reason,
+	}
+	if e.RequestID == "" {
+		e.RequestID = req.Header.Get(rest.HeaderXRequestID)
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	if p := auth.Get(c); p != nil {
+		e.Principal, e.TenantID = p.ID, p.TenantID
+	}
+
+	hook(c, e)
+}
+
+// tokenSubject returns the sub claim of the token, for the
+// principal of the JWT middleware without ClaimsMapper.
+func tokenSubject(token *jwt.Token) string {
+	if claims, ok := token.Claims.(jwt.MapClaims); ok {
+		sub, _ := claims["sub"].(string)
+		return sub
+	}
+
+	// custom claims, ex. embedding jwt.StandardClaims
+	var t struct {
+		Sub string `json:"sub"`
+	}
+	if b, err := json.Marshal(token.Claims); err == nil {
+		json.Unmarshal(b, &t)
+	}
+	return t.Sub
+}
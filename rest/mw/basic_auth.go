@@ -0,0 +1,105 @@
+package mw
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/enigma-id/go/rest"
+)
+
+type (
+	// BasicAuthConfig defines the config for BasicAuth middleware.
+	BasicAuthConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Validator is a function to validate BasicAuth credentials.
+		// Required.
+		Validator BasicAuthValidator
+
+		// Realm is a string to define realm attribute of BasicAuth.
+		// Optional. Default value "Restricted".
+		Realm string
+
+		// Context key to store the authenticated username into context.
+		// Optional. Default value "identity".
+		ContextKey string
+	}
+
+	// BasicAuthValidator defines a function to validate BasicAuth credentials.
+	BasicAuthValidator func(username, password string, c *rest.Context) (bool, error)
+)
+
+const basic = "basic"
+
+var (
+	// DefaultBasicAuthConfig is the default BasicAuth middleware config.
+	DefaultBasicAuthConfig = BasicAuthConfig{
+		Skipper:    DefaultSkipper,
+		Realm:      "Restricted",
+		ContextKey: IdentityKey,
+	}
+)
+
+// BasicAuth returns an BasicAuth middleware.
+//
+// For valid credentials it sets the username in context and calls next handler.
+// For missing or invalid credentials, it sends "401 - Unauthorized" response.
+func BasicAuth(fn BasicAuthValidator) rest.MiddlewareFunc {
+	c := DefaultBasicAuthConfig
+	c.Validator = fn
+	return BasicAuthWithConfig(c)
+}
+
+// BasicAuthWithConfig returns an BasicAuth middleware with config.
+// See `BasicAuth()`.
+func BasicAuthWithConfig(config BasicAuthConfig) rest.MiddlewareFunc {
+	// Defaults
+	if config.Validator == nil {
+		panic("rest: basic-auth middleware requires a validator function")
+	}
+	if config.Skipper == nil {
+		config.Skipper = DefaultBasicAuthConfig.Skipper
+	}
+	if config.Realm == "" {
+		config.Realm = DefaultBasicAuthConfig.Realm
+	}
+	if config.ContextKey == "" {
+		config.ContextKey = DefaultBasicAuthConfig.ContextKey
+	}
+
+	return func(next rest.HandlerFunc) rest.HandlerFunc {
+		return func(c *rest.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			auth := c.Request().Header.Get(rest.HeaderAuthorization)
+			l := len(basic)
+
+			if len(auth) > l+1 && strings.ToLower(auth[:l]) == basic {
+				b, err := base64.StdEncoding.DecodeString(auth[l+1:])
+				if err != nil {
+					return rest.NewHTTPError(http.StatusBadRequest, "invalid basic auth encoding")
+				}
+				cred := string(b)
+				if i := strings.IndexByte(cred, ':'); i >= 0 {
+					// Verify credentials
+					valid, err := config.Validator(cred[:i], cred[i+1:], c)
+					if err != nil {
+						return err
+					} else if valid {
+						c.Set(config.ContextKey, cred[:i])
+						return next(c)
+					}
+				}
+			}
+
+			// Need to return `401` for browsers to pop-up login box.
+			c.Response().Header().Set(rest.HeaderWWWAuthenticate, "Basic realm="+strconv.Quote(config.Realm))
+			return rest.ErrUnauthorized
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package mw
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/enigma-id/go/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBasicAuth(t *testing.T) {
+	e := rest.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	res := httptest.NewRecorder()
+	c := e.NewContext(req, res)
+	f := func(u, p string, c *rest.Context) (bool, error) {
+		if u == "joe" && p == "secret" {
+			return true, nil
+		}
+		return false, nil
+	}
+	h := BasicAuth(f)(func(c *rest.Context) error {
+		return c.String(http.StatusOK, "test")
+	})
+
+	// Valid credentials
+	auth := "basic " + base64.StdEncoding.EncodeToString([]byte("joe:secret"))
+	req.Header.Set(rest.HeaderAuthorization, auth)
+	assert.NoError(t, h(c))
+	assert.Equal(t, "joe", c.Get(IdentityKey))
+
+	// Case-insensitive header scheme
+	auth = "Basic " + base64.StdEncoding.EncodeToString([]byte("joe:secret"))
+	req.Header.Set(rest.HeaderAuthorization, auth)
+	assert.NoError(t, h(c))
+
+	// Invalid credentials
+	auth = "basic " + base64.StdEncoding.EncodeToString([]byte("joe:invalid-password"))
+	req.Header.Set(rest.HeaderAuthorization, auth)
+	he := h(c).(*rest.HTTPError)
+	assert.Equal(t, http.StatusUnauthorized, he.Code)
+	assert.Equal(t, `Basic realm="Restricted"`, res.Header().Get(rest.HeaderWWWAuthenticate))
+
+	// Missing Authorization header
+	req.Header.Del(rest.HeaderAuthorization)
+	he = h(c).(*rest.HTTPError)
+	assert.Equal(t, http.StatusUnauthorized, he.Code)
+
+	// Invalid encoding
+	req.Header.Set(rest.HeaderAuthorization, "basic !!!")
+	he = h(c).(*rest.HTTPError)
+	assert.Equal(t, http.StatusBadRequest, he.Code)
+
+	// Validator error
+	err := errors.New("store down")
+	h = BasicAuth(func(u, p string, c *rest.Context) (bool, error) {
+		return false, err
+	})(func(c *rest.Context) error { return nil })
+	req.Header.Set(rest.HeaderAuthorization, auth)
+	assert.Equal(t, err, h(c))
+
+	assert.Panics(t, func() { BasicAuth(nil) })
+}
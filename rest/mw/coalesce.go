@@ -0,0 +1,204 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package mw
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/enigma-id/go/rest"
+)
+
+type (
+	// CoalesceConfig defines the config for Coalesce middleware.
+	CoalesceConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// KeyFunc returns the key of identical requests, the requests of
+		// different users must have different keys.
+		// Optional. Default value is route path, query, authorization and cookie headers.
+		KeyFunc func(c *rest.Context) string
+	}
+
+	// coalesceGroup tracks the in flight handler executions by key.
+	coalesceGroup struct {
+		mu    sync.Mutex
+		calls map[string]*coalesceCall
+	}
+
+	// coalesceCall is an in flight or completed handler execution.
+	coalesceCall struct {
+		wg     sync.WaitGroup
+		result *coalesceRecorder
+		err    error
+	}
+
+	// coalesceRecorder records the response of the handler.
+	coalesceRecorder struct {
+		header http.Header
+		code   int
+		body   bytes.Buffer
+	}
+)
+
+var (
+	// DefaultCoalesceConfig is the default Coalesce middleware config.
+	DefaultCoalesceConfig = CoalesceConfig{
+		Skipper: DefaultSkipper,
+		KeyFunc: coalesceKey,
+	}
+)
+
+// Coalesce returns a middleware that collapses concurrent identical GET
+// requests into single handler execution, the response is replayed
+// to all the waiting requests.
+func Coalesce() rest.MiddlewareFunc {
+	return CoalesceWithConfig(DefaultCoalesceConfig)
+}
+
+// CoalesceWithConfig returns a Coalesce middleware with config.
+func CoalesceWithConfig(config CoalesceConfig) rest.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultCoalesceConfig.Skipper
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = DefaultCoalesceConfig.KeyFunc
+	}
+
+	g := &coalesceGroup{calls: make(map[string]*coalesceCall)}
+
+	return func(next rest.HandlerFunc) rest.HandlerFunc {
+		return func(c *rest.Context) error {
+			if config.Skipper(c) || c.Request().Method != http.MethodGet {
+				return next(c)
+			}
+
+			key := config.KeyFunc(c)
+
+			g.mu.Lock()
+			if call, ok := g.calls[key]; ok {
+				g.mu.Unlock()
+				call.wg.Wait()
+				return call.replay(c.Response())
+			}
+
+			call := new(coalesceCall)
+			call.wg.Add(1)
+			g.calls[key] = call
+			g.mu.Unlock()
+
+			defer func() {
+				// the handler panics, waiters get an internal error
+				if call.result == nil {
+					call.err = rest.ErrInternalServerError
+				}
+
+				g.mu.Lock()
+				delete(g.calls, key)
+				g.mu.Unlock()
+				call.wg.Done()
+			}()
+
+			return call.run(c, next)
+		}
+	}
+}
+
+// run executes the handler with recorded response, then writes
+// the recorded response into the original writer.
+func (call *coalesceCall) run(c *rest.Context, next rest.HandlerFunc) error {
+	res := c.Response()
+	w := res.Writer
+	initial := w.Header().Clone()
+
+	rec := &coalesceRecorder{header: w.Header().Clone()}
+	res.Writer = rec
+	defer func() {
+		res.Writer = w
+	}()
+
+	call.err = next(c)
+
+	// only headers set by the handler are replayed
+	call.result = &coalesceRecorder{header: make(http.Header), code: rec.code, body: rec.body}
+	for k, v := range rec.header {
+		if !equalValues(initial[k], v) {
+			call.result.header[k] = v
+		}
+	}
+
+	for k, v := range call.result.header {
+		w.Header()[k] = v
+	}
+	if rec.code != 0 {
+		w.WriteHeader(rec.code)
+		w.Write(rec.body.Bytes())
+	}
+
+	return call.err
+}
+
+// replay writes the recorded response of the call.
+func (call *coalesceCall) replay(res *rest.Response) error {
+	if call.result == nil || call.result.code == 0 {
+		return call.err
+	}
+
+	for k, v := range call.result.header {
+		res.Header()[k] = append([]string(nil), v...)
+	}
+	res.WriteHeader(call.result.code)
+	res.Write(call.result.body.Bytes())
+
+	return call.err
+}
+
+func (r *coalesceRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *coalesceRecorder) WriteHeader(code int) {
+	if r.code == 0 {
+		r.code = code
+	}
+}
+
+func (r *coalesceRecorder) Write(b []byte) (int, error) {
+	if r.code == 0 {
+		r.code = http.StatusOK
+	}
+	return r.body.Write(b)
+}
+
+// Flush implements http.Flusher, the response is buffered
+// until the handler returns so there is nothing to flush.
+func (r *coalesceRecorder) Flush() {
+	if r.code == 0 {
+		r.code = http.StatusOK
+	}
+}
+
+// coalesceKey separates the users by the authorization and the cookies,
+// so the session of one user is never replayed to another.
+func coalesceKey(c *rest.Context) string {
+	h := c.Request().Header
+	return c.Request().URL.Path + "?" + c.QueryString() + "\n" + h.Get(rest.HeaderAuthorization) + "\n" + strings.Join(h["Cookie"], "; ")
+}
+
+func equalValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,134 @@
+package mw
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/enigma-id/go/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoalesce(t *testing.T) {
+	e := rest.New()
+	var calls int32
+	release := make(chan struct{})
+	e.GET("/report", func(c *rest.Context) error {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		c.Response().Header().Set("X-Report", "daily")
+		return c.String(http.StatusOK, "report")
+	}, Coalesce())
+
+	var wg sync.WaitGroup
+	recs := make([]*httptest.ResponseRecorder, 5)
+	for i := range recs {
+		recs[i] = httptest.NewRecorder()
+		wg.Add(1)
+		go func(rec *httptest.ResponseRecorder) {
+			defer wg.Done()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/report?day=1", nil))
+		}(recs[i])
+	}
+
+	// give the other requests time to join the in flight one
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	for _, rec := range recs {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "report", rec.Body.String())
+		assert.Equal(t, "daily", rec.Header().Get("X-Report"))
+	}
+}
+
+func TestCoalesceKey(t *testing.T) {
+	e := rest.New()
+	var calls int32
+	block := make(chan struct{})
+	h := Coalesce()(func(c *rest.Context) error {
+		atomic.AddInt32(&calls, 1)
+		<-block
+		return c.String(http.StatusOK, c.QueryParam("id"))
+	})
+
+	var wg sync.WaitGroup
+	for _, target := range []string{"/?id=1", "/?id=2"} {
+		wg.Add(1)
+		go func(target string) {
+			defer wg.Done()
+			rec := httptest.NewRecorder()
+			h(e.NewContext(httptest.NewRequest(http.MethodGet, target, nil), rec))
+			assert.Equal(t, target[len(target)-1:], rec.Body.String())
+		}(target)
+	}
+
+	// different queries are not coalesced, so both are running
+	for atomic.LoadInt32(&calls) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	close(block)
+	wg.Wait()
+}
+
+func TestCoalesceCookie(t *testing.T) {
+	e := rest.New()
+	var calls int32
+	block := make(chan struct{})
+	h := Coalesce()(func(c *rest.Context) error {
+		atomic.AddInt32(&calls, 1)
+		<-block
+		cookie, _ := c.Cookie("session")
+		return c.String(http.StatusOK, cookie.Value)
+	})
+
+	var wg sync.WaitGroup
+	for _, session := range []string{"alice", "bob"} {
+		wg.Add(1)
+		go func(session string) {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			req.AddCookie(&http.Cookie{Name: "session", Value: session})
+			rec := httptest.NewRecorder()
+			h(e.NewContext(req, rec))
+			assert.Equal(t, session, rec.Body.String())
+		}(session)
+	}
+
+	// the sessions are not coalesced, so both are running
+	for atomic.LoadInt32(&calls) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	close(block)
+	wg.Wait()
+}
+
+func TestCoalesceFlush(t *testing.T) {
+	e := rest.New()
+	h := Coalesce()(func(c *rest.Context) error {
+		c.Response().Write([]byte("chunk"))
+		c.Response().Flush()
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	assert.NoError(t, h(e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "chunk", rec.Body.String())
+}
+
+func TestCoalesceSkipsNonGet(t *testing.T) {
+	e := rest.New()
+	h := Coalesce()(func(c *rest.Context) error {
+		return c.NoContent(http.StatusCreated)
+	})
+
+	rec := httptest.NewRecorder()
+	assert.NoError(t, h(e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), rec)))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
@@ -3,13 +3,18 @@ package mw
 import (
 	"bufio"
 	"compress/gzip"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 
+	"github.com/andybalholm/brotli"
 	"github.com/enigma-id/go/rest"
+	"github.com/klauspost/compress/zstd"
 )
 
 type (
@@ -23,14 +28,52 @@ type (
 		Level int `yaml:"level"`
 	}
 
-	gzipResponseWriter struct {
+	// CompressConfig defines the config for Compress middleware.
+	CompressConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Encodings supported, the encoding is chosen by the quality values
+		// of Accept-Encoding, the first one is preferred on the same quality.
+		// Optional. Default value br, zstd, gzip.
+		Encodings []string `yaml:"encodings"`
+
+		// Gzip compression level.
+		// Optional. Default value -1.
+		GzipLevel int `yaml:"gzip_level"`
+
+		// Brotli compression level, 1 to 11, the higher levels are too slow
+		// for the dynamic responses.
+		// Optional. Default value 4.
+		BrotliLevel int `yaml:"brotli_level"`
+
+		// Zstd compression level, 1 (fastest) to 4 (best).
+		// Optional. Default value 2.
+		ZstdLevel int `yaml:"zstd_level"`
+	}
+
+	// compressor is the pooled writer of an encoding.
+	compressor interface {
+		io.WriteCloser
+		Flush() error
+		Reset(w io.Writer)
+	}
+
+	compressResponseWriter struct {
 		io.Writer
 		http.ResponseWriter
 	}
 )
 
+// Encodings of Compress middleware.
 const (
-	gzipScheme = "gzip"
+	EncodingGzip   = "gzip"
+	EncodingBrotli = "br"
+	EncodingZstd   = "zstd"
+)
+
+const (
+	gzipScheme = EncodingGzip
 )
 
 var (
@@ -39,6 +82,15 @@ var (
 		Skipper: DefaultSkipper,
 		Level:   -1,
 	}
+
+	// DefaultCompressConfig is the default Compress middleware config.
+	DefaultCompressConfig = CompressConfig{
+		Skipper:     DefaultSkipper,
+		Encodings:   []string{EncodingBrotli, EncodingZstd, EncodingGzip},
+		GzipLevel:   -1,
+		BrotliLevel: 4,
+		ZstdLevel:   int(zstd.SpeedDefault),
+	}
 )
 
 // Gzip returns a middleware which compresses HTTP response using gzip compression
@@ -58,6 +110,48 @@ func GzipWithConfig(config GzipConfig) rest.MiddlewareFunc {
 		config.Level = DefaultGzipConfig.Level
 	}
 
+	return CompressWithConfig(CompressConfig{
+		Skipper:   config.Skipper,
+		Encodings: []string{EncodingGzip},
+		GzipLevel: config.Level,
+	})
+}
+
+// Compress returns a middleware which compresses HTTP response using brotli,
+// zstd or gzip, whichever the client prefers.
+func Compress() rest.MiddlewareFunc {
+	return CompressWithConfig(DefaultCompressConfig)
+}
+
+// CompressWithConfig return Compress middleware with config.
+// See: `Compress()`.
+func CompressWithConfig(config CompressConfig) rest.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultCompressConfig.Skipper
+	}
+	if len(config.Encodings) == 0 {
+		config.Encodings = DefaultCompressConfig.Encodings
+	}
+	if config.GzipLevel == 0 {
+		config.GzipLevel = DefaultCompressConfig.GzipLevel
+	}
+	if config.BrotliLevel == 0 {
+		config.BrotliLevel = DefaultCompressConfig.BrotliLevel
+	}
+	if config.ZstdLevel == 0 {
+		config.ZstdLevel = DefaultCompressConfig.ZstdLevel
+	}
+
+	pools := make(map[string]*sync.Pool, len(config.Encodings))
+	for _, enc := range config.Encodings {
+		pool, err := compressorPool(enc, &config)
+		if err != nil {
+			panic("rest: compress middleware " + err.Error())
+		}
+		pools[enc] = pool
+	}
+
 	return func(next rest.HandlerFunc) rest.HandlerFunc {
 		return func(c *rest.Context) error {
 			if config.Skipper(c) {
@@ -66,35 +160,104 @@ func GzipWithConfig(config GzipConfig) rest.MiddlewareFunc {
 
 			res := c.Response()
 			res.Header().Add(rest.HeaderVary, rest.HeaderAcceptEncoding)
-			if strings.Contains(c.Request().Header.Get(rest.HeaderAcceptEncoding), gzipScheme) {
-				res.Header().Set(rest.HeaderContentEncoding, gzipScheme) // Issue #806
-				rw := res.Writer
-				w, err := gzip.NewWriterLevel(rw, config.Level)
-				if err != nil {
-					return err
-				}
-				defer func() {
-					if res.Size == 0 {
-						if res.Header().Get(rest.HeaderContentEncoding) == gzipScheme {
-							res.Header().Del(rest.HeaderContentEncoding)
-						}
-						// We have to reset response to it's pristine state when
-						// nothing is written to body or error is returned.
-						// See issue #424, #407.
-						res.Writer = rw
-						w.Reset(ioutil.Discard)
-					}
-					w.Close()
-				}()
-				grw := &gzipResponseWriter{Writer: w, ResponseWriter: rw}
-				res.Writer = grw
+			enc := negotiateEncoding(c.Request().Header.Get(rest.HeaderAcceptEncoding), config.Encodings)
+			if enc == "" {
+				return next(c)
 			}
+
+			res.Header().Set(rest.HeaderContentEncoding, enc) // Issue #806
+			rw := res.Writer
+			pool := pools[enc]
+			w := pool.Get().(compressor)
+			w.Reset(rw)
+			defer func() {
+				if res.Size == 0 {
+					if res.Header().Get(rest.HeaderContentEncoding) == enc {
+						res.Header().Del(rest.HeaderContentEncoding)
+					}
+					// We have to reset response to it's pristine state when
+					// nothing is written to body or error is returned.
+					// See issue #424, #407.
+					res.Writer = rw
+					w.Reset(ioutil.Discard)
+				}
+				w.Close()
+				w.Reset(ioutil.Discard)
+				pool.Put(w)
+			}()
+			res.Writer = &compressResponseWriter{Writer: w, ResponseWriter: rw}
+
 			return next(c)
 		}
 	}
 }
 
-func (w *gzipResponseWriter) WriteHeader(code int) {
+// compressorPool returns the pool of the writers of the encoding.
+func compressorPool(enc string, config *CompressConfig) (*sync.Pool, error) {
+	var fn func() (compressor, error)
+	switch enc {
+	case EncodingGzip:
+		fn = func() (compressor, error) { return gzip.NewWriterLevel(ioutil.Discard, config.GzipLevel) }
+	case EncodingBrotli:
+		fn = func() (compressor, error) { return brotli.NewWriterLevel(ioutil.Discard, config.BrotliLevel), nil }
+	case EncodingZstd:
+		fn = func() (compressor, error) {
+			return zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.EncoderLevel(config.ZstdLevel)), zstd.WithEncoderConcurrency(1))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported encoding %s", enc)
+	}
+
+	// the level is checked once, so the pool doesn't fail
+	if _, err := fn(); err != nil {
+		return nil, err
+	}
+
+	return &sync.Pool{New: func() interface{} {
+		w, _ := fn()
+		return w
+	}}, nil
+}
+
+// negotiateEncoding returns the supported encoding having the highest quality
+// in Accept-Encoding, empty when none is acceptable.
+func negotiateEncoding(accept string, supported []string) string {
+	if accept == "" {
+		return ""
+	}
+
+	qs := make(map[string]float64)
+	for _, part := range strings.Split(accept, ",") {
+		p := strings.Split(part, ";")
+		name := strings.ToLower(strings.TrimSpace(p[0]))
+		q := 1.0
+		for _, param := range p[1:] {
+			if param = strings.TrimSpace(param); strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if name != "" {
+			qs[name] = q
+		}
+	}
+
+	best, bestQ := "", 0.0
+	for _, enc := range supported {
+		q, ok := qs[enc]
+		if !ok {
+			q = qs["*"]
+		}
+		if q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+
+	return best
+}
+
+func (w *compressResponseWriter) WriteHeader(code int) {
 	if code == http.StatusNoContent { // Issue #489
 		w.ResponseWriter.Header().Del(rest.HeaderContentEncoding)
 	}
@@ -102,21 +265,24 @@ func (w *gzipResponseWriter) WriteHeader(code int) {
 	w.ResponseWriter.WriteHeader(code)
 }
 
-func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+func (w *compressResponseWriter) Write(b []byte) (int, error) {
 	if w.Header().Get(rest.HeaderContentType) == "" {
 		w.Header().Set(rest.HeaderContentType, http.DetectContentType(b))
 	}
 	return w.Writer.Write(b)
 }
 
-func (w *gzipResponseWriter) Flush() {
-	w.Writer.(*gzip.Writer).Flush()
+func (w *compressResponseWriter) Flush() {
+	w.Writer.(compressor).Flush()
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
 }
 
-func (w *gzipResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+func (w *compressResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	return w.ResponseWriter.(http.Hijacker).Hijack()
 }
 
-func (w *gzipResponseWriter) CloseNotify() <-chan bool {
+func (w *compressResponseWriter) CloseNotify() <-chan bool {
 	return w.ResponseWriter.(http.CloseNotifier).CloseNotify()
 }
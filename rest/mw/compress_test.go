@@ -3,11 +3,15 @@ package mw
 import (
 	"bytes"
 	"compress/gzip"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/andybalholm/brotli"
 	"github.com/enigma-id/go/rest"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -74,3 +78,74 @@ func TestGzipErrorReturned(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, rec.Code)
 	assert.Empty(t, rec.Header().Get(rest.HeaderContentEncoding))
 }
+
+func TestCompress(t *testing.T) {
+	e := rest.New()
+	body := strings.Repeat("compressed response ", 64)
+	e.Use(Compress())
+	e.GET("/", func(c *rest.Context) error {
+		return c.String(http.StatusOK, body)
+	})
+
+	decode := map[string]func(r io.Reader) (io.Reader, error){
+		EncodingGzip: func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		EncodingBrotli: func(r io.Reader) (io.Reader, error) {
+			return brotli.NewReader(r), nil
+		},
+		EncodingZstd: func(r io.Reader) (io.Reader, error) { return zstd.NewReader(r) },
+	}
+
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"gzip, deflate, br, zstd", EncodingBrotli},
+		{"gzip;q=1.0, br;q=0.5, zstd;q=0.8", EncodingGzip},
+		{"zstd, gzip", EncodingZstd},
+		{"br;q=0, *;q=0.5", EncodingZstd},
+		{"identity", ""},
+		{"gzip;q=0", ""},
+		{"", ""},
+	}
+
+	// the pooled writers are reused by the requests
+	for i := 0; i < 2; i++ {
+		for _, tt := range tests {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(rest.HeaderAcceptEncoding, tt.accept)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.want, rec.Header().Get(rest.HeaderContentEncoding), tt.accept)
+			assert.Equal(t, rest.HeaderAcceptEncoding, rec.Header().Get(rest.HeaderVary), tt.accept)
+			if tt.want == "" {
+				assert.Equal(t, body, rec.Body.String(), tt.accept)
+				continue
+			}
+
+			assert.Less(t, rec.Body.Len(), len(body), tt.accept)
+			r, err := decode[tt.want](rec.Body)
+			if assert.NoError(t, err, tt.accept) {
+				b, err := io.ReadAll(r)
+				assert.NoError(t, err, tt.accept)
+				assert.Equal(t, body, string(b), tt.accept)
+			}
+		}
+	}
+}
+
+func TestCompressWithConfig(t *testing.T) {
+	h := CompressWithConfig(CompressConfig{Encodings: []string{EncodingZstd, EncodingGzip}, ZstdLevel: 4})(func(c *rest.Context) error {
+		return c.String(http.StatusOK, "test")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(rest.HeaderAcceptEncoding, "br, gzip")
+	rec := httptest.NewRecorder()
+	if assert.NoError(t, h(rest.New().NewContext(req, rec))) {
+		assert.Equal(t, EncodingGzip, rec.Header().Get(rest.HeaderContentEncoding))
+	}
+
+	assert.Panics(t, func() { CompressWithConfig(CompressConfig{Encodings: []string{"deflate"}}) })
+	assert.Panics(t, func() { CompressWithConfig(CompressConfig{GzipLevel: 12}) })
+}
@@ -0,0 +1,404 @@
+package mw
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/enigma-id/go/rest"
+	"github.com/enigma-id/go/rest/openapi"
+	"github.com/enigma-id/go/validation"
+	"go.uber.org/zap"
+)
+
+type (
+	// ContractConfig defines the config for Contract middleware.
+	ContractConfig struct {
+		// Skipper defines a function to skip middleware.
+		// Optional. Default value skips when the rest is not in dev mode.
+		Skipper Skipper
+
+		// Spec is the OpenAPI document the requests and responses
+		// are validated against, ex. from rest.OpenAPI or loaded from file.
+		// Required.
+		Spec *openapi.Document
+
+		// Enforce fails the request with 400 on request drift, and replaces
+		// the response with 500 on response drift, the drift is only
+		// reported when false.
+		// Optional. Default value false.
+		Enforce bool
+
+		// DisableResponse skips the validation of the response,
+		// the response is buffered to be validated otherwise.
+		// Optional. Default value false.
+		DisableResponse bool
+
+		// OnDrift is called with the drift of the request ("request")
+		// or the response ("response"), keyed by the location of the value,
+		// ex. "query.page" or "body.items.0.qty".
+		// Optional. Default value logs the drift as warning.
+		OnDrift func(c *rest.Context, kind string, drift map[string]string)
+	}
+
+	contract struct {
+		config ContractConfig
+		drift  map[string]string
+	}
+)
+
+var (
+	// DefaultContractConfig is the default Contract middleware config.
+	DefaultContractConfig = ContractConfig{
+		Skipper: func(c *rest.Context) bool {
+			return !c.Rest().Config.DevMode
+		},
+		OnDrift: func(c *rest.Context, kind string, drift map[string]string) {
+			c.Logger().Warn("rest: "+kind+" doesn't match the contract",
+				zap.String("method", c.Request().Method),
+				zap.String("path", c.Path()),
+				zap.Any("drift", drift),
+			)
+		},
+	}
+)
+
+// Contract returns a middleware that validates the requests and responses
+// against the OpenAPI document, to catch the drift between the implementation
+// and the published docs. The middleware is skipped outside of dev mode.
+//
+//	e.Use(mw.Contract(spec))
+func Contract(spec *openapi.Document) rest.MiddlewareFunc {
+	c := DefaultContractConfig
+	c.Spec = spec
+	return ContractWithConfig(c)
+}
+
+// ContractWithConfig returns a Contract middleware with config.
+func ContractWithConfig(config ContractConfig) rest.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultContractConfig.Skipper
+	}
+	if config.OnDrift == nil {
+		config.OnDrift = DefaultContractConfig.OnDrift
+	}
+	if config.Spec == nil {
+		panic("rest: contract middleware requires spec")
+	}
+
+	return func(next rest.HandlerFunc) rest.HandlerFunc {
+		return func(c *rest.Context) error {
+			if config.Skipper(c) || c.Path() == "" {
+				return next(c)
+			}
+
+			item := config.Spec.Paths[contractPath(c.Path())]
+			var op *openapi.Operation
+			if item != nil {
+				op = item.Operation(c.Request().Method)
+			}
+
+			ct := &contract{config: config, drift: make(map[string]string)}
+			if op == nil {
+				ct.fail("route", "route is not documented")
+			} else {
+				ct.request(c, op)
+			}
+			if len(ct.drift) > 0 {
+				config.OnDrift(c, "request", ct.drift)
+				if config.Enforce {
+					return &rest.HTTPError{
+						Code:     http.StatusBadRequest,
+						Message:  "Request doesn't match the contract",
+						Internal: ct.errors(),
+					}
+				}
+			}
+
+			if op == nil || config.DisableResponse {
+				return next(c)
+			}
+
+			return ct.response(c, op, next)
+		}
+	}
+}
+
+// request validates the parameters and the json body of the request.
+func (ct *contract) request(c *rest.Context, op *openapi.Operation) {
+	req := c.Request()
+	for _, p := range op.Parameters {
+		var (
+			v  string
+			ok bool
+		)
+		switch p.In {
+		case "path":
+			v = c.Param(p.Name)
+			ok = v != ""
+		case "query":
+			_, ok = req.URL.Query()[p.Name]
+			v = req.URL.Query().Get(p.Name)
+		case "header":
+			v = req.Header.Get(p.Name)
+			ok = v != ""
+		case "cookie":
+			if ck, err := req.Cookie(p.Name); err == nil {
+				v, ok = ck.Value, true
+			}
+		}
+
+		key := p.In + "." + p.Name
+		if !ok {
+			if p.Required {
+				ct.fail(key, "parameter is required")
+			}
+			continue
+		}
+		ct.param(key, ct.resolve(p.Schema, 0), v)
+	}
+
+	if op.RequestBody == nil {
+		return
+	}
+	if req.ContentLength == 0 {
+		if op.RequestBody.Required {
+			ct.fail("body", "body is required")
+		}
+		return
+	}
+
+	mt := op.RequestBody.Content[rest.MIMEApplicationJSON]
+	if mt == nil || mt.Schema == nil || !strings.HasPrefix(req.Header.Get(rest.HeaderContentType), rest.MIMEApplicationJSON) {
+		return
+	}
+
+	b, err := io.ReadAll(req.Body)
+	req.Body = io.NopCloser(bytes.NewReader(b))
+	if err != nil {
+		return
+	}
+	ct.body("body", mt.Schema, b)
+}
+
+// response runs the handler with recorded response, the response
+// is written after its body is validated.
+func (ct *contract) response(c *rest.Context, op *openapi.Operation, next rest.HandlerFunc) error {
+	res := c.Response()
+	w := res.Writer
+
+	rec := &coalesceRecorder{header: w.Header()}
+	res.Writer = rec
+	err := next(c)
+	res.Writer = w
+
+	code := rec.code
+	if code == 0 {
+		return err
+	}
+
+	r := op.Responses[strconv.Itoa(code)]
+	if r == nil {
+		r = op.Responses[strconv.Itoa(code/100)+"XX"]
+	}
+	if r == nil {
+		r = op.Responses["default"]
+	}
+
+	if r == nil {
+		ct.fail("status", fmt.Sprintf("status %d is not documented", code))
+	} else if mt := r.Content[rest.MIMEApplicationJSON]; mt != nil && mt.Schema != nil &&
+		strings.HasPrefix(rec.header.Get(rest.HeaderContentType), rest.MIMEApplicationJSON) {
+		ct.body("body", mt.Schema, rec.body.Bytes())
+	}
+
+	if len(ct.drift) > 0 {
+		ct.config.OnDrift(c, "response", ct.drift)
+		if ct.config.Enforce {
+			// recorded response is dropped, let the error handler respond
+			res.Committed, res.Size = false, 0
+			return &rest.HTTPError{
+				Code:     http.StatusInternalServerError,
+				Message:  "Response doesn't match the contract",
+				Internal: ct.errors(),
+			}
+		}
+	}
+
+	w.WriteHeader(code)
+	w.Write(rec.body.Bytes())
+
+	return err
+}
+
+func (ct *contract) body(key string, s *openapi.Schema, b []byte) {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		ct.fail(key, "body is not valid json")
+		return
+	}
+	ct.value(key, s, v, 0)
+}
+
+// param validates the string value of the parameter.
+func (ct *contract) param(key string, s *openapi.Schema, v string) {
+	if s == nil {
+		return
+	}
+
+	var err error
+	switch s.Type {
+	case "integer":
+		_, err = strconv.ParseInt(v, 10, 64)
+	case "number":
+		_, err = strconv.ParseFloat(v, 64)
+	case "boolean":
+		_, err = strconv.ParseBool(v)
+	}
+	if err != nil {
+		ct.fail(key, "must be "+s.Type)
+		return
+	}
+
+	ct.enum(key, s, v)
+}
+
+// value validates the decoded json value against the schema,
+// null is accepted as the document doesn't describe nullable.
+func (ct *contract) value(key string, s *openapi.Schema, v interface{}, depth int) {
+	if s = ct.resolve(s, 0); s == nil || v == nil || depth > 32 {
+		return
+	}
+
+	switch s.Type {
+	case "object":
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			ct.fail(key, "must be object")
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := m[name]; !ok {
+				ct.fail(key+"."+name, "field is required")
+			}
+		}
+		for name, ps := range s.Properties {
+			if pv, ok := m[name]; ok {
+				ct.value(key+"."+name, ps, pv, depth+1)
+			}
+		}
+	case "array":
+		a, ok := v.([]interface{})
+		if !ok {
+			ct.fail(key, "must be array")
+			return
+		}
+		for i, iv := range a {
+			ct.value(key+"."+strconv.Itoa(i), s.Items, iv, depth+1)
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			ct.fail(key, "must be string")
+			return
+		}
+		if !validFormat(s.Format, str) {
+			ct.fail(key, "must be "+s.Format)
+			return
+		}
+		ct.enum(key, s, str)
+	case "integer":
+		if n, ok := v.(float64); !ok || n != float64(int64(n)) {
+			ct.fail(key, "must be integer")
+			return
+		}
+		ct.enum(key, s, v)
+	case "number":
+		if _, ok := v.(float64); !ok {
+			ct.fail(key, "must be number")
+			return
+		}
+		ct.enum(key, s, v)
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			ct.fail(key, "must be boolean")
+		}
+	}
+}
+
+func (ct *contract) enum(key string, s *openapi.Schema, v interface{}) {
+	if len(s.Enum) == 0 {
+		return
+	}
+
+	for _, e := range s.Enum {
+		if fmt.Sprint(e) == fmt.Sprint(v) {
+			return
+		}
+	}
+	ct.fail(key, "must be one of the enum")
+}
+
+// resolve returns the schema referenced by $ref from the components.
+func (ct *contract) resolve(s *openapi.Schema, depth int) *openapi.Schema {
+	if s == nil || s.Ref == "" {
+		return s
+	}
+
+	comp := ct.config.Spec.Components
+	if comp == nil || depth > 8 {
+		return nil
+	}
+	return ct.resolve(comp.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")], depth+1)
+}
+
+func (ct *contract) fail(key, msg string) {
+	if _, ok := ct.drift[key]; !ok {
+		ct.drift[key] = msg
+	}
+}
+
+// errors returns the drift as validation errors, to be served
+// by the error handler.
+func (ct *contract) errors() *validation.Response {
+	keys := make([]string, 0, len(ct.drift))
+	for k := range ct.drift {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	res := validation.NewResponse()
+	for _, k := range keys {
+		res.Failure(k+".contract", ct.drift[k])
+	}
+	return res
+}
+
+func validFormat(format, v string) bool {
+	var err error
+	switch format {
+	case "date-time":
+		_, err = time.Parse(time.RFC3339, v)
+	case "date":
+		_, err = time.Parse("2006-01-02", v)
+	}
+	return err == nil
+}
+
+// contractPath converts path of the route into OpenAPI path template.
+func contractPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") {
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,155 @@
+package mw
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/enigma-id/go/rest"
+	"github.com/enigma-id/go/rest/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func contractSpec() *openapi.Document {
+	order := &openapi.Schema{
+		Type:     "object",
+		Required: []string{"id", "status"},
+		Properties: map[string]*openapi.Schema{
+			"id":     {Type: "integer"},
+			"status": {Type: "string", Enum: []interface{}{"paid", "unpaid"}},
+		},
+	}
+
+	return &openapi.Document{
+		Paths: map[string]*openapi.PathItem{
+			"/orders/{id}": {
+				Put: &openapi.Operation{
+					Parameters: []*openapi.Parameter{
+						{Name: "id", In: "path", Required: true, Schema: &openapi.Schema{Type: "integer"}},
+						{Name: "notify", In: "query", Schema: &openapi.Schema{Type: "boolean"}},
+					},
+					RequestBody: &openapi.RequestBody{
+						Required: true,
+						Content: map[string]*openapi.MediaType{
+							rest.MIMEApplicationJSON: {Schema: &openapi.Schema{Ref: "#/components/schemas/Order"}},
+						},
+					},
+					Responses: map[string]*openapi.Response{
+						"200": {Content: map[string]*openapi.MediaType{
+							rest.MIMEApplicationJSON: {Schema: &openapi.Schema{Ref: "#/components/schemas/Order"}},
+						}},
+					},
+				},
+			},
+		},
+		Components: &openapi.Components{
+			Schemas: map[string]*openapi.Schema{"Order": order},
+		},
+	}
+}
+
+func TestContract(t *testing.T) {
+	e := rest.New()
+
+	var drifts []map[string]string
+	e.Use(ContractWithConfig(ContractConfig{
+		Skipper: DefaultSkipper,
+		Spec:    contractSpec(),
+		Enforce: true,
+		OnDrift: func(c *rest.Context, kind string, drift map[string]string) {
+			drifts = append(drifts, drift)
+		},
+	}))
+	e.PUT("/orders/:id", func(c *rest.Context) error {
+		if c.QueryParam("drift") != "" {
+			return c.JSON(http.StatusOK, rest.Map{"id": "1"})
+		}
+		return c.JSON(http.StatusOK, rest.Map{"id": 1, "status": "paid"})
+	})
+	e.GET("/orders", func(c *rest.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	send := func(method, target, body string) *httptest.ResponseRecorder {
+		drifts = nil
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(rest.HeaderContentType, rest.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send(http.MethodPut, "/orders/1?notify=true", `{"id":1,"status":"paid"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"id":1,"status":"paid"}`, rec.Body.String())
+	assert.Empty(t, drifts)
+
+	rec = send(http.MethodPut, "/orders/abc?notify=yes", `{"id":1.5,"status":"void"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	if assert.Len(t, drifts, 1) {
+		assert.Equal(t, map[string]string{
+			"path.id":      "must be integer",
+			"query.notify": "must be boolean",
+			"body.id":      "must be integer",
+			"body.status":  "must be one of the enum",
+		}, drifts[0])
+	}
+	assert.Contains(t, rec.Body.String(), `"body.status":"must be one of the enum"`)
+
+	rec = send(http.MethodPut, "/orders/1", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"body":"body is required"`)
+
+	rec = send(http.MethodPut, "/orders/1?drift=1", `{"id":1,"status":"paid"}`)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	if assert.Len(t, drifts, 1) {
+		assert.Equal(t, map[string]string{
+			"body.id":     "must be integer",
+			"body.status": "field is required",
+		}, drifts[0])
+	}
+	assert.NotContains(t, rec.Body.String(), `"id":"1"`)
+
+	rec = send(http.MethodGet, "/orders", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"route":"route is not documented"`)
+}
+
+func TestContractReportOnly(t *testing.T) {
+	e := rest.New()
+
+	var drifts int
+	h := ContractWithConfig(ContractConfig{
+		Spec:    contractSpec(),
+		Skipper: DefaultSkipper,
+		OnDrift: func(c *rest.Context, kind string, drift map[string]string) {
+			drifts++
+		},
+	})(func(c *rest.Context) error {
+		return c.JSON(http.StatusCreated, rest.Map{"id": 1})
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/orders/1", strings.NewReader(`{"id":1,"status":"paid"}`))
+	req.Header.Set(rest.HeaderContentType, rest.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/orders/:id")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	assert.NoError(t, h(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.JSONEq(t, `{"id":1}`, rec.Body.String())
+	assert.Equal(t, 1, drifts)
+
+	assert.Panics(t, func() { Contract(nil) })
+
+	// skipped outside of dev mode
+	drifts = 0
+	h = Contract(contractSpec())(func(c *rest.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+	assert.NoError(t, h(e.NewContext(httptest.NewRequest(http.MethodGet, "/orders", nil), httptest.NewRecorder())))
+	assert.Equal(t, 0, drifts)
+}
@@ -0,0 +1,253 @@
+package mw
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/enigma-id/go/cache"
+	"github.com/enigma-id/go/rest"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// IdentityKey is the context key holding the identity authenticated
+// by the BasicAuth and KeyAuth middlewares.
+const IdentityKey = "identity"
+
+type (
+	// CredentialStore verifies secrets of the auth middlewares.
+	CredentialStore interface {
+		// Verify returns the identity owning the secret, or empty string
+		// when the secret doesn't match. Empty identity means the secret is
+		// an api key that has to be looked up by itself.
+		Verify(identity, secret string) (string, error)
+	}
+
+	// Credentials is an in memory store of identity and hashed secret pairs.
+	// A secret is a bcrypt hash (as generated by `htpasswd -B`) or
+	// "sha256:<hex>" for high entropy api keys, plain values are rejected.
+	// Only the sha256 secrets can be looked up as api key.
+	Credentials struct {
+		secrets map[string]string
+		keys    map[string]string
+	}
+
+	// CacheCredentials keeps bcrypt hashed passwords and api keys in the cache,
+	// so they can be shared and revoked across instances.
+	CacheCredentials struct {
+		Cache  cache.Cache
+		Prefix string
+	}
+)
+
+// HashSecret returns bcrypt hash of the secret, suitable for passwords.
+func HashSecret(secret string) (string, error) {
+	b, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	return string(b), err
+}
+
+// HashKey returns "sha256:<hex>" of the key, api keys are random enough
+// that a fast hash is safe and keeps the lookup cheap.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// MatchSecret reports whether secret matches the hashed value,
+// the value that is not bcrypt or sha256 hash never matches.
+func MatchSecret(hashed, secret string) bool {
+	switch {
+	case isBcrypt(hashed):
+		return bcrypt.CompareHashAndPassword([]byte(hashed), []byte(secret)) == nil
+	case strings.HasPrefix(hashed, "sha256:"):
+		return subtle.ConstantTimeCompare([]byte(hashed), []byte(HashKey(secret))) == 1
+	}
+	return false
+}
+
+// NewCredentials creates store from identity and hashed secret pairs,
+// it panics when a secret is not hashed.
+func NewCredentials(m map[string]string) *Credentials {
+	s := &Credentials{secrets: make(map[string]string, len(m)), keys: make(map[string]string)}
+	for id, secret := range m {
+		if !isHashed(secret) {
+			panic(fmt.Sprintf("rest: credentials require bcrypt or sha256 hashed secret of %q", id))
+		}
+
+		s.secrets[id] = secret
+		if strings.HasPrefix(secret, "sha256:") {
+			s.keys[secret] = id
+		}
+	}
+	return s
+}
+
+// EnvCredentials creates store from environment variable in the form of
+// "user:hash,user2:hash2".
+func EnvCredentials(name string) (*Credentials, error) {
+	m := make(map[string]string)
+	for _, pair := range strings.Split(os.Getenv(name), ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		id, secret, err := parseCredential(pair)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		m[id] = secret
+	}
+	return NewCredentials(m), nil
+}
+
+// FileCredentials creates store from htpasswd like file, one "user:hash"
+// per line, empty lines and lines starting with "#" are ignored.
+func FileCredentials(path string) (*Credentials, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	m := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		id, secret, err := parseCredential(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, n, err)
+		}
+		m[id] = secret
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewCredentials(m), nil
+}
+
+// Verify implements CredentialStore. The api key is looked up by its
+// sha256 hash, the unknown identity takes as long as the bcrypt one.
+func (s *Credentials) Verify(identity, secret string) (string, error) {
+	if identity == "" {
+		return s.keys[HashKey(secret)], nil
+	}
+
+	hashed, ok := s.secrets[identity]
+	if !ok {
+		dummyCompare(secret)
+		return "", nil
+	}
+	if MatchSecret(hashed, secret) {
+		return identity, nil
+	}
+	return "", nil
+}
+
+// NewCacheCredentials creates cache backed store, prefix namespaces the keys.
+func NewCacheCredentials(c cache.Cache, prefix string) *CacheCredentials {
+	return &CacheCredentials{Cache: c, Prefix: prefix}
+}
+
+// Set stores bcrypt hash of the password for identity.
+func (s *CacheCredentials) Set(identity, password string, expires time.Duration) error {
+	hashed, err := HashSecret(password)
+	if err != nil {
+		return err
+	}
+	return s.Cache.Set(s.Prefix+"user:"+identity, hashed, expires)
+}
+
+// Delete removes password of the identity.
+func (s *CacheCredentials) Delete(identity string) error {
+	return s.Cache.Delete(s.Prefix + "user:" + identity)
+}
+
+// SetKey stores api key owned by identity, only the key hash is kept.
+func (s *CacheCredentials) SetKey(identity, key string, expires time.Duration) error {
+	return s.Cache.Set(s.Prefix+"key:"+HashKey(key), identity, expires)
+}
+
+// DeleteKey revokes the api key.
+func (s *CacheCredentials) DeleteKey(key string) error {
+	return s.Cache.Delete(s.Prefix + "key:" + HashKey(key))
+}
+
+// Verify implements CredentialStore.
+func (s *CacheCredentials) Verify(identity, secret string) (id string, err error) {
+	if identity == "" {
+		err = s.Cache.Get(s.Prefix+"key:"+HashKey(secret), &id)
+	} else {
+		var hashed string
+		if err = s.Cache.Get(s.Prefix+"user:"+identity, &hashed); err == nil && MatchSecret(hashed, secret) {
+			id = identity
+		} else if err == cache.ErrCacheMiss {
+			dummyCompare(secret)
+		}
+	}
+	if err == cache.ErrCacheMiss {
+		err = nil
+	}
+	return
+}
+
+// BasicAuthStore returns BasicAuth validator verifying against the store.
+func BasicAuthStore(s CredentialStore) BasicAuthValidator {
+	return func(username, password string, c *rest.Context) (bool, error) {
+		id, err := s.Verify(username, password)
+		return id != "", err
+	}
+}
+
+// KeyAuthStore returns KeyAuth validator verifying against the store,
+// the identity owning the key is set in context under IdentityKey.
+func KeyAuthStore(s CredentialStore) KeyAuthValidator {
+	return func(key string, c *rest.Context) (bool, error) {
+		id, err := s.Verify("", key)
+		if id != "" {
+			c.Set(IdentityKey, id)
+		}
+		return id != "", err
+	}
+}
+
+func parseCredential(s string) (string, string, error) {
+	i := strings.IndexByte(s, ':')
+	if i <= 0 || i == len(s)-1 {
+		return "", "", errors.New(`invalid credential, expecting "identity:secret"`)
+	}
+	if !isHashed(s[i+1:]) {
+		return "", "", fmt.Errorf("invalid credential of %q, expecting bcrypt or sha256 hash", s[:i])
+	}
+	return s[:i], s[i+1:], nil
+}
+
+func isBcrypt(s string) bool {
+	return strings.HasPrefix(s, "$2a$") || strings.HasPrefix(s, "$2b$") || strings.HasPrefix(s, "$2y$")
+}
+
+func isHashed(s string) bool {
+	return isBcrypt(s) || strings.HasPrefix(s, "sha256:")
+}
+
+var dummyHash struct {
+	once sync.Once
+	b    []byte
+}
+
+// dummyCompare runs bcrypt for the unknown identity,
+// so the response time doesn't tell whether it exists.
+func dummyCompare(secret string) {
+	dummyHash.once.Do(func() {
+		dummyHash.b, _ = bcrypt.GenerateFromPassword([]byte("dummy"), bcrypt.DefaultCost)
+	})
+	bcrypt.CompareHashAndPassword(dummyHash.b, []byte(secret))
+}
@@ -0,0 +1,163 @@
+package mw
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/enigma-id/go/cache"
+	"github.com/enigma-id/go/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchSecret(t *testing.T) {
+	hashed, err := HashSecret("secret")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "secret", hashed)
+	assert.True(t, MatchSecret(hashed, "secret"))
+	assert.False(t, MatchSecret(hashed, "other"))
+
+	assert.True(t, MatchSecret(HashKey("k3y"), "k3y"))
+	assert.False(t, MatchSecret(HashKey("k3y"), "key"))
+
+	assert.False(t, MatchSecret("plain", "plain"))
+	assert.False(t, MatchSecret("", ""))
+}
+
+func TestCredentials(t *testing.T) {
+	hashed, _ := HashSecret("secret")
+	s := NewCredentials(map[string]string{"joe": hashed, "ci": HashKey("ci-key")})
+
+	// the password is not an api key
+	id, _ := s.Verify("", "secret")
+	assert.Empty(t, id)
+	id, _ = s.Verify("", "ci-key")
+	assert.Equal(t, "ci", id)
+	id, _ = s.Verify("ci", "ci-key")
+	assert.Equal(t, "ci", id)
+
+	// the unknown identity takes the time of bcrypt
+	start := time.Now()
+	id, _ = s.Verify("nobody", "secret")
+	assert.Empty(t, id)
+	unknown := time.Since(start)
+	start = time.Now()
+	s.Verify("joe", "wrong")
+	assert.True(t, unknown > time.Since(start)/4, "unknown %s, known %s", unknown, time.Since(start))
+
+	assert.PanicsWithValue(t, `rest: credentials require bcrypt or sha256 hashed secret of "joe"`, func() {
+		NewCredentials(map[string]string{"joe": "plain"})
+	})
+}
+
+func TestEnvCredentials(t *testing.T) {
+	hashed, _ := HashSecret("secret")
+	os.Setenv("TEST_CREDENTIALS", "joe:"+hashed+", ci:"+HashKey("ci-key"))
+	defer os.Unsetenv("TEST_CREDENTIALS")
+
+	s, err := EnvCredentials("TEST_CREDENTIALS")
+	if assert.NoError(t, err) {
+		id, _ := s.Verify("joe", "secret")
+		assert.Equal(t, "joe", id)
+		id, _ = s.Verify("joe", "wrong")
+		assert.Empty(t, id)
+		id, _ = s.Verify("", "ci-key")
+		assert.Equal(t, "ci", id)
+		id, _ = s.Verify("nobody", "secret")
+		assert.Empty(t, id)
+	}
+
+	os.Setenv("TEST_CREDENTIALS", "joe")
+	_, err = EnvCredentials("TEST_CREDENTIALS")
+	assert.Error(t, err)
+
+	os.Setenv("TEST_CREDENTIALS", "joe:secret")
+	_, err = EnvCredentials("TEST_CREDENTIALS")
+	assert.EqualError(t, err, `TEST_CREDENTIALS: invalid credential of "joe", expecting bcrypt or sha256 hash`)
+}
+
+func TestFileCredentials(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "credentials")
+	defer os.RemoveAll(dir)
+
+	hashed, _ := HashSecret("secret")
+	path := filepath.Join(dir, ".htpasswd")
+	ioutil.WriteFile(path, []byte("# admins\njoe:"+hashed+"\n\nops:"+HashKey("ops-key")+"\n"), 0600)
+
+	s, err := FileCredentials(path)
+	if assert.NoError(t, err) {
+		id, _ := s.Verify("joe", "secret")
+		assert.Equal(t, "joe", id)
+		id, _ = s.Verify("", "ops-key")
+		assert.Equal(t, "ops", id)
+	}
+
+	ioutil.WriteFile(path, []byte("joe:\n"), 0600)
+	_, err = FileCredentials(path)
+	assert.EqualError(t, err, path+`:1: invalid credential, expecting "identity:secret"`)
+
+	_, err = FileCredentials(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestCacheCredentials(t *testing.T) {
+	s := NewCacheCredentials(cache.NewMemory(), "auth:")
+	assert.NoError(t, s.Set("joe", "secret", time.Hour))
+	assert.NoError(t, s.SetKey("joe", "joe-key", time.Hour))
+
+	id, err := s.Verify("joe", "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "joe", id)
+	id, _ = s.Verify("joe", "wrong")
+	assert.Empty(t, id)
+	id, _ = s.Verify("", "joe-key")
+	assert.Equal(t, "joe", id)
+
+	assert.NoError(t, s.DeleteKey("joe-key"))
+	id, err = s.Verify("", "joe-key")
+	assert.NoError(t, err)
+	assert.Empty(t, id)
+
+	assert.NoError(t, s.Delete("joe"))
+	id, _ = s.Verify("joe", "secret")
+	assert.Empty(t, id)
+}
+
+func TestCredentialStoreMiddleware(t *testing.T) {
+	hashed, _ := HashSecret("secret")
+	s := NewCredentials(map[string]string{"joe": hashed, "ci": HashKey("ci-key")})
+
+	e := rest.New()
+	e.GET("/basic", func(c *rest.Context) error {
+		return c.String(http.StatusOK, c.Get(IdentityKey).(string))
+	}, BasicAuth(BasicAuthStore(s)))
+	e.GET("/key", func(c *rest.Context) error {
+		return c.String(http.StatusOK, c.Get(IdentityKey).(string))
+	}, KeyAuth(KeyAuthStore(s)))
+
+	request := func(path, auth string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(rest.HeaderAuthorization, auth)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := request("/basic", "Basic "+base64.StdEncoding.EncodeToString([]byte("joe:secret")))
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		assert.Equal(t, "joe", rec.Body.String())
+	}
+	rec = request("/basic", "Basic "+base64.StdEncoding.EncodeToString([]byte("joe:"+hashed)))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = request("/key", "Bearer ci-key")
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		assert.Equal(t, "ci", rec.Body.String())
+	}
+	assert.Equal(t, http.StatusUnauthorized, request("/key", "Bearer "+HashKey("ci-key")).Code)
+}
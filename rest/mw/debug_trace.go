@@ -0,0 +1,91 @@
+package mw
+
+import (
+	"crypto/subtle"
+
+	"github.com/enigma-id/go/rest"
+	"github.com/enigma-id/go/trace"
+	"go.uber.org/zap"
+)
+
+type (
+	// DebugTraceConfig defines the config for DebugTrace middleware.
+	DebugTraceConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Header carrying the secret that turns on the trace.
+		// Optional. Default value rest.HeaderXDebugTrace.
+		Header string
+
+		// Secret that must be sent on the header.
+		// Required.
+		Secret string
+
+		// DisableMeta doesn't attach the trace into the response meta,
+		// the trace is only written into the debug log.
+		// Optional. Default value false.
+		DisableMeta bool
+	}
+)
+
+var (
+	// DefaultDebugTraceConfig is the default DebugTrace middleware config.
+	DefaultDebugTraceConfig = DebugTraceConfig{
+		Skipper: DefaultSkipper,
+		Header:  rest.HeaderXDebugTrace,
+	}
+)
+
+// DebugTrace returns a middleware that traces the request when the header
+// has the secret, the middleware order, bind and validate timing, cache
+// hits and downstream calls are attached into meta of the response and
+// written into the debug log. Register it as pre middleware so the whole
+// chain is traced.
+//
+//	e.Pre(mw.DebugTrace(os.Getenv("DEBUG_TRACE_SECRET")))
+func DebugTrace(secret string) rest.MiddlewareFunc {
+	c := DefaultDebugTraceConfig
+	c.Secret = secret
+	return DebugTraceWithConfig(c)
+}
+
+// DebugTraceWithConfig returns a DebugTrace middleware with config.
+func DebugTraceWithConfig(config DebugTraceConfig) rest.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultDebugTraceConfig.Skipper
+	}
+	if config.Header == "" {
+		config.Header = DefaultDebugTraceConfig.Header
+	}
+	if config.Secret == "" {
+		panic("rest: debug trace middleware requires secret")
+	}
+
+	return func(next rest.HandlerFunc) rest.HandlerFunc {
+		return func(c *rest.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			req := c.Request()
+			key := req.Header.Get(config.Header)
+			if key == "" || subtle.ConstantTimeCompare([]byte(key), []byte(config.Secret)) != 1 {
+				return next(c)
+			}
+
+			t := trace.New()
+			c.SetRequest(req.WithContext(trace.WithTrace(req.Context(), t)))
+			if !config.DisableMeta {
+				c.SetMeta("trace", t)
+			}
+
+			err := next(c)
+
+			c.Logger().Debug("trace "+req.Method+" "+req.URL.Path, zap.Reflect("trace", t), zap.Error(err))
+
+			return err
+		}
+	}
+}
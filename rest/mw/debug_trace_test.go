@@ -0,0 +1,69 @@
+package mw
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/enigma-id/go/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugTrace(t *testing.T) {
+	assert.Panics(t, func() { DebugTrace("") })
+
+	type request struct {
+		Name string `json:"name" valid:"required"`
+	}
+
+	e := rest.New()
+	e.Pre(DebugTrace("s3cret"))
+	e.Use(RequestID())
+	e.POST("/users", func(c *rest.Context) error {
+		var r request
+		if err := c.Bind(&r); err != nil {
+			return err
+		}
+		return c.OK(r)
+	})
+
+	send := func(secret string) map[string]interface{} {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"kora"}`))
+		req.Header.Set(rest.HeaderContentType, rest.MIMEApplicationJSON)
+		if secret != "" {
+			req.Header.Set(rest.HeaderXDebugTrace, secret)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var body struct {
+			Meta map[string]interface{} `json:"meta"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return body.Meta
+	}
+
+	assert.Nil(t, send(""))
+	assert.Nil(t, send("wrong"))
+
+	meta := send("s3cret")
+	tr, ok := meta["trace"].(map[string]interface{})
+	if !assert.True(t, ok) {
+		return
+	}
+
+	var names []string
+	for _, s := range tr["spans"].([]interface{}) {
+		s := s.(map[string]interface{})
+		names = append(names, s["kind"].(string)+":"+s["name"].(string))
+	}
+	assert.Equal(t, []string{
+		"middleware:mw.RequestIDWithConfig",
+		"handler:POST /users",
+		"bind:*mw.request",
+		"validate:*mw.request",
+	}, names)
+}
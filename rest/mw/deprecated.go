@@ -0,0 +1,114 @@
+package mw
+
+import (
+	"expvar"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/enigma-id/go/rest"
+)
+
+type (
+	// DeprecatedConfig defines the config for Deprecated middleware.
+	DeprecatedConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Since is the date the route was deprecated, sent as
+		// Deprecation header "@<unix time>", or "true" when empty.
+		// Optional.
+		Since time.Time
+
+		// Sunset is the date the route will be removed.
+		// Optional.
+		Sunset time.Time
+
+		// Link to the migration guide or the replacement route.
+		// Optional.
+		Link string
+
+		// Metrics counts the calls of each deprecated route,
+		// keyed by "<method> <route path>".
+		// Optional. Default value DeprecatedCalls.
+		Metrics *expvar.Map
+
+		// OnCall is called on every call of the deprecated route,
+		// ex. to log the client that still uses it.
+		// Optional.
+		OnCall func(c *rest.Context)
+	}
+)
+
+var (
+	// DeprecatedCalls counts the calls of deprecated routes,
+	// published on /debug/vars as "rest.deprecated".
+	DeprecatedCalls = expvar.NewMap("rest.deprecated")
+
+	// DefaultDeprecatedConfig is the default Deprecated middleware config.
+	DefaultDeprecatedConfig = DeprecatedConfig{
+		Skipper: DefaultSkipper,
+		Metrics: DeprecatedCalls,
+	}
+)
+
+// Deprecated returns a middleware that marks the route as deprecated,
+// by setting Deprecation, Sunset and Link headers on the response.
+//
+//	r.GET("/v1/orders", listOrders, mw.Deprecated(time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC), "https://docs.kora.id/orders/v2"))
+func Deprecated(sunset time.Time, link string) rest.MiddlewareFunc {
+	c := DefaultDeprecatedConfig
+	c.Sunset = sunset
+	c.Link = link
+	return DeprecatedWithConfig(c)
+}
+
+// DeprecatedWithConfig returns a Deprecated middleware with config.
+func DeprecatedWithConfig(config DeprecatedConfig) rest.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultDeprecatedConfig.Skipper
+	}
+	if config.Metrics == nil {
+		config.Metrics = DefaultDeprecatedConfig.Metrics
+	}
+
+	deprecation := "true"
+	if !config.Since.IsZero() {
+		deprecation = fmt.Sprintf("@%d", config.Since.Unix())
+	}
+
+	var sunset string
+	if !config.Sunset.IsZero() {
+		sunset = config.Sunset.UTC().Format(http.TimeFormat)
+	}
+
+	var link string
+	if config.Link != "" {
+		link = fmt.Sprintf(`<%s>; rel="deprecation"`, config.Link)
+	}
+
+	return func(next rest.HandlerFunc) rest.HandlerFunc {
+		return func(c *rest.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			h := c.Response().Header()
+			h.Set(rest.HeaderDeprecation, deprecation)
+			if sunset != "" {
+				h.Set(rest.HeaderSunset, sunset)
+			}
+			if link != "" {
+				h.Add(rest.HeaderLink, link)
+			}
+
+			config.Metrics.Add(c.Request().Method+" "+c.Path(), 1)
+			if config.OnCall != nil {
+				config.OnCall(c)
+			}
+
+			return next(c)
+		}
+	}
+}
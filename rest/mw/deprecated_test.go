@@ -0,0 +1,55 @@
+package mw
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/enigma-id/go/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecated(t *testing.T) {
+	e := rest.New()
+	sunset := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	e.GET("/v1/orders", func(c *rest.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, Deprecated(sunset, "https://docs.kora.id/orders/v2"))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/orders", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "true", rec.Header().Get(rest.HeaderDeprecation))
+	assert.Equal(t, "Sat, 01 Jun 2019 00:00:00 GMT", rec.Header().Get(rest.HeaderSunset))
+	assert.Equal(t, `<https://docs.kora.id/orders/v2>; rel="deprecation"`, rec.Header().Get(rest.HeaderLink))
+	assert.Equal(t, "1", DeprecatedCalls.Get("GET /v1/orders").String())
+}
+
+func TestDeprecatedWithConfig(t *testing.T) {
+	e := rest.New()
+	m := new(expvar.Map)
+	var called bool
+	h := DeprecatedWithConfig(DeprecatedConfig{
+		Since:   time.Unix(1546300800, 0),
+		Metrics: m,
+		OnCall:  func(c *rest.Context) { called = true },
+	})(func(c *rest.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/v1/users")
+	assert.NoError(t, h(c))
+
+	assert.Equal(t, "@1546300800", rec.Header().Get(rest.HeaderDeprecation))
+	assert.Empty(t, rec.Header().Get(rest.HeaderSunset))
+	assert.Empty(t, rec.Header().Get(rest.HeaderLink))
+	assert.Equal(t, "1", m.Get("GET /v1/users").String())
+	assert.True(t, called)
+}
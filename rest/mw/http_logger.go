@@ -5,24 +5,88 @@
 package mw
 
 import (
+	"expvar"
 	"fmt"
+	"strconv"
+	"sync/atomic"
 	"time"
 
+	"github.com/dgrijalva/jwt-go"
+	"github.com/enigma-id/go/auth"
 	"github.com/enigma-id/go/rest"
 	"go.uber.org/zap"
 )
 
+type (
+	// HTTPLoggerConfig defines the config for HTTPLogger middleware.
+	HTTPLoggerConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// SampleRate logs 1 of N successful requests, failed and slow
+		// requests are always logged.
+		// Optional. Default value 1, logs all requests.
+		SampleRate uint64
+
+		// SlowThreshold of the request latency, slower request is logged
+		// as warning with "slow" field and counted in SlowRequests.
+		// Optional. Default value 0, disabled.
+		SlowThreshold time.Duration
+
+		// Extractors add fields of the request into the log, ex. identity
+		// of the actor using LogClaims("sub", "tenant_id").
+		// Optional.
+		Extractors []LogExtractor
+	}
+
+	// LogExtractor returns fields of the request logged by HTTPLogger,
+	// it's called after the handler so the values set by the inner
+	// middleware like mw.JWT are available.
+	LogExtractor func(c *rest.Context) []zap.Field
+)
+
+var (
+	// SlowRequests counts the slow requests of each route,
+	// published on /debug/vars as "rest.slow".
+	SlowRequests = expvar.NewMap("rest.slow")
+
+	// DefaultHTTPLoggerConfig is the default HTTPLogger middleware config.
+	DefaultHTTPLoggerConfig = HTTPLoggerConfig{
+		Skipper:    DefaultSkipper,
+		SampleRate: 1,
+	}
+)
+
 // HTTPLogger returns a middleware that logs HTTP requests.
 func HTTPLogger() rest.MiddlewareFunc {
+	return HTTPLoggerWithConfig(DefaultHTTPLoggerConfig)
+}
+
+// HTTPLoggerWithConfig returns a HTTPLogger middleware with config.
+func HTTPLoggerWithConfig(config HTTPLoggerConfig) rest.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultHTTPLoggerConfig.Skipper
+	}
+	if config.SampleRate == 0 {
+		config.SampleRate = DefaultHTTPLoggerConfig.SampleRate
+	}
+
+	var count uint64
+
 	return func(n rest.HandlerFunc) rest.HandlerFunc {
 		return func(c *rest.Context) error {
-			return logRequest(n, c)
+			if config.Skipper(c) {
+				return n(c)
+			}
+
+			return config.logRequest(n, c, &count)
 		}
 	}
 }
 
 // logRequest print all http request on consoles.
-func logRequest(hand rest.HandlerFunc, c *rest.Context) (err error) {
+func (config *HTTPLoggerConfig) logRequest(hand rest.HandlerFunc, c *rest.Context, count *uint64) (err error) {
 	start := time.Now()
 	req := c.Request()
 	res := c.Response()
@@ -32,6 +96,12 @@ func logRequest(hand rest.HandlerFunc, c *rest.Context) (err error) {
 	end := time.Now()
 	latency := end.Sub(start) / 1e5
 
+	slow := config.SlowThreshold > 0 && end.Sub(start) >= config.SlowThreshold
+	failed := err != nil || res.Status >= 400
+	if !slow && !failed && config.SampleRate > 1 && atomic.AddUint64(count, 1)%config.SampleRate != 0 {
+		return
+	}
+
 	var fields = []zap.Field{
 		zap.String("path", req.URL.Path),
 		zap.String("query", req.URL.RawQuery),
@@ -40,7 +110,16 @@ func logRequest(hand rest.HandlerFunc, c *rest.Context) (err error) {
 		zap.String("latecy", fmt.Sprintf("%1.1fms", float64(latency))),
 	}
 
-	if err == nil {
+	for _, ex := range config.Extractors {
+		fields = append(fields, ex(c)...)
+	}
+
+	if slow {
+		fields = append(fields, zap.Bool("slow", true))
+		SlowRequests.Add(req.Method+" "+c.Path(), 1)
+	}
+
+	if err == nil && !slow {
 		c.Logger().Info(fmt.Sprintf("%s/%d", req.Method, res.Status), fields...)
 	} else {
 		c.Logger().Warn(fmt.Sprintf("%s/%d", req.Method, res.Status), fields...)
@@ -48,3 +127,55 @@ func logRequest(hand rest.HandlerFunc, c *rest.Context) (err error) {
 
 	return
 }
+
+// LogClaims returns extractor of the claims of the jwt set by mw.JWT,
+// the field is named by the claim and missing claim is not logged.
+//
+//	mw.HTTPLoggerWithConfig(mw.HTTPLoggerConfig{
+//		Extractors: []mw.LogExtractor{mw.LogClaims("sub", "tenant_id")},
+//	})
+func LogClaims(claims ...string) LogExtractor {
+	return func(c *rest.Context) []zap.Field {
+		t, ok := c.Get(DefaultJWTConfig.ContextKey).(*jwt.Token)
+		if !ok {
+			return nil
+		}
+		mc, ok := t.Claims.(jwt.MapClaims)
+		if !ok {
+			return nil
+		}
+
+		var fields []zap.Field
+		for _, k := range claims {
+			switch v := mc[k].(type) {
+			case nil:
+			case string:
+				fields = append(fields, zap.String(k, v))
+			case float64:
+				fields = append(fields, zap.String(k, strconv.FormatFloat(v, 'f', -1, 64)))
+			default:
+				fields = append(fields, zap.Any(k, v))
+			}
+		}
+
+		return fields
+	}
+}
+
+// LogPrincipal returns extractor of the auth.Principal set by mw.JWT
+// with ClaimsMapper, logged as "sub" and "tenant_id".
+func LogPrincipal() LogExtractor {
+	return func(c *rest.Context) []zap.Field {
+		p := auth.Get(c)
+		if p == nil {
+			return nil
+		}
+
+		fields := []zap.Field{zap.String("sub", p.ID)}
+		if p.TenantID != "" {
+			fields = append(fields, zap.String("tenant_id", p.TenantID))
+		}
+
+		return fields
+	}
+}
@@ -0,0 +1,93 @@
+package mw
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/enigma-id/go/auth"
+	"github.com/enigma-id/go/rest"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestHTTPLoggerSampling(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	e := rest.New()
+	e.Logger = zap.New(core)
+
+	e.GET("/ok", func(c *rest.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, HTTPLoggerWithConfig(HTTPLoggerConfig{SampleRate: 5}))
+	e.GET("/fail", func(c *rest.Context) error {
+		return errors.New("failed")
+	}, HTTPLoggerWithConfig(HTTPLoggerConfig{SampleRate: 5}))
+
+	for i := 0; i < 10; i++ {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+	}
+	assert.Equal(t, 2, logs.FilterMessage("GET/200").Len())
+
+	for i := 0; i < 3; i++ {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+	}
+	assert.Equal(t, 3, logs.FilterMessage("GET/500").Len())
+}
+
+func TestHTTPLoggerSlow(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	e := rest.New()
+	e.Logger = zap.New(core)
+
+	e.GET("/slow", func(c *rest.Context) error {
+		time.Sleep(5 * time.Millisecond)
+		return c.NoContent(http.StatusOK)
+	}, HTTPLoggerWithConfig(HTTPLoggerConfig{SampleRate: 100, SlowThreshold: time.Millisecond}))
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	entries := logs.FilterField(zap.Bool("slow", true)).All()
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, zapcore.WarnLevel, entries[0].Level)
+	}
+	assert.Equal(t, "1", SlowRequests.Get("GET /slow").String())
+}
+
+func TestHTTPLoggerExtractors(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	e := rest.New()
+	e.Logger = zap.New(core)
+
+	key := []byte("secret")
+	e.GET("/me", func(c *rest.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, HTTPLoggerWithConfig(HTTPLoggerConfig{Extractors: []LogExtractor{LogClaims("sub", "tenant_id", "missing")}}), JWT(key))
+	e.GET("/principal", func(c *rest.Context) error {
+		auth.Set(c, &auth.Principal{ID: "42", TenantID: "kora"})
+		return c.NoContent(http.StatusOK)
+	}, HTTPLoggerWithConfig(HTTPLoggerConfig{Extractors: []LogExtractor{LogPrincipal(), LogClaims("sub")}}))
+
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "u-1", "tenant_id": float64(7)}).SignedString(key)
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set(rest.HeaderAuthorization, "Bearer "+token)
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/principal", nil))
+
+	entries := logs.All()
+	if assert.Len(t, entries, 2) {
+		ctx := entries[0].ContextMap()
+		assert.Equal(t, "u-1", ctx["sub"])
+		assert.Equal(t, "7", ctx["tenant_id"])
+		assert.NotContains(t, ctx, "missing")
+
+		ctx = entries[1].ContextMap()
+		assert.Equal(t, "42", ctx["sub"])
+		assert.Equal(t, "kora", ctx["tenant_id"])
+	}
+}
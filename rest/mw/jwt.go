@@ -1,12 +1,15 @@
 package mw
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/enigma-id/go/auth"
 	"github.com/enigma-id/go/rest"
 )
 
@@ -55,6 +58,25 @@ type (
 		// Optional. Default value "Bearer".
 		AuthScheme string
 
+		// ClaimsMapper maps claims of the valid token into auth.Principal
+		// placed on the context, token it can't map is rejected.
+		// Optional. ex. auth.DefaultClaimsMapper
+		ClaimsMapper auth.ClaimsMapper
+
+		// Leeway is the clock skew allowed when checking exp, nbf and iat,
+		// for tokens minted by the servers having different clock.
+		// Optional. Default value 0.
+		Leeway time.Duration
+
+		// MaxTokenAge rejects the token issued (iat) longer than the age ago,
+		// even when the exp is still far, token without iat is rejected.
+		// Optional. Default value 0, the age is not checked.
+		MaxTokenAge time.Duration
+
+		// AuditHook receives the allow or deny decision of each request.
+		// Optional. Default value DefaultAuditHook.
+		AuditHook AuditHook
+
 		keyFunc jwt.Keyfunc
 	}
 
@@ -137,6 +159,8 @@ func JWTWithConfig(config JWTConfig) rest.MiddlewareFunc {
 	}
 
 	// Initialize
+	// times of the claims are validated by the middleware with the leeway
+	parser := &jwt.Parser{SkipClaimsValidation: config.Leeway > 0 || config.MaxTokenAge > 0}
 	parts := strings.Split(config.TokenLookup, ":")
 	extractor := jwtFromHeader(parts[1], config.AuthScheme)
 	switch parts[0] {
@@ -156,8 +180,9 @@ func JWTWithConfig(config JWTConfig) rest.MiddlewareFunc {
 				config.BeforeFunc(c)
 			}
 
-			auth, err := extractor(c)
+			raw, err := extractor(c)
 			if err != nil {
+				audit(c, config.AuditHook, "jwt", "", ReasonMissingCredential, err)
 				if config.ErrorHandler != nil {
 					return config.ErrorHandler(err)
 				}
@@ -166,20 +191,37 @@ func JWTWithConfig(config JWTConfig) rest.MiddlewareFunc {
 			token := new(jwt.Token)
 			// Issue #647, #656
 			if _, ok := config.Claims.(jwt.MapClaims); ok {
-				token, err = jwt.Parse(auth, config.keyFunc)
+				token, err = parser.Parse(raw, config.keyFunc)
 			} else {
 				t := reflect.ValueOf(config.Claims).Type().Elem()
 				claims := reflect.New(t).Interface().(jwt.Claims)
-				token, err = jwt.ParseWithClaims(auth, claims, config.keyFunc)
+				token, err = parser.ParseWithClaims(raw, claims, config.keyFunc)
+			}
+			if err == nil && parser.SkipClaimsValidation {
+				err = config.validClaims(token.Claims, time.Now())
 			}
 			if err == nil && token.Valid {
 				// Store user information from token into context.
 				c.Set(config.ContextKey, token)
+				if config.ClaimsMapper != nil {
+					p, err := config.ClaimsMapper(token)
+					if err != nil {
+						audit(c, config.AuditHook, "jwt", tokenSubject(token), ReasonInvalidPrincipal, err)
+						return &rest.HTTPError{
+							Code:     http.StatusUnauthorized,
+							Message:  "invalid or expired jwt",
+							Internal: err,
+						}
+					}
+					auth.Set(c, p)
+				}
+				audit(c, config.AuditHook, "jwt", tokenSubject(token), "", nil)
 				if config.SuccessHandler != nil {
 					config.SuccessHandler(c)
 				}
 				return next(c)
 			}
+			audit(c, config.AuditHook, "jwt", "", ReasonInvalidCredential, err)
 			if config.ErrorHandler != nil {
 				return config.ErrorHandler(err)
 			}
@@ -192,6 +234,53 @@ func JWTWithConfig(config JWTConfig) rest.MiddlewareFunc {
 	}
 }
 
+// validClaims validates the claims with the leeway and max age of the token,
+// other validations of the claims, ex. custom Valid(), are still applied.
+func (config *JWTConfig) validClaims(claims jwt.Claims, now time.Time) error {
+	if err := claims.Valid(); err != nil {
+		ve, ok := err.(*jwt.ValidationError)
+		if !ok {
+			return err
+		}
+		if ve.Errors &^= jwt.ValidationErrorExpired | jwt.ValidationErrorNotValidYet | jwt.ValidationErrorIssuedAt; ve.Errors != 0 {
+			return ve
+		}
+	}
+
+	var t struct {
+		Exp *float64 `json:"exp"`
+		Nbf *float64 `json:"nbf"`
+		Iat *float64 `json:"iat"`
+	}
+	b, err := json.Marshal(claims)
+	if err == nil {
+		err = json.Unmarshal(b, &t)
+	}
+	if err != nil {
+		return err
+	}
+
+	leeway := config.Leeway
+	switch {
+	case t.Exp != nil && now.After(unix(*t.Exp).Add(leeway)):
+		return jwt.NewValidationError("token is expired", jwt.ValidationErrorExpired)
+	case t.Nbf != nil && now.Before(unix(*t.Nbf).Add(-leeway)):
+		return jwt.NewValidationError("token is not valid yet", jwt.ValidationErrorNotValidYet)
+	case t.Iat != nil && now.Before(unix(*t.Iat).Add(-leeway)):
+		return jwt.NewValidationError("token used before issued", jwt.ValidationErrorIssuedAt)
+	case config.MaxTokenAge > 0 && t.Iat == nil:
+		return jwt.NewValidationError("token has no issued at", jwt.ValidationErrorIssuedAt)
+	case config.MaxTokenAge > 0 && now.Sub(unix(*t.Iat)) > config.MaxTokenAge+leeway:
+		return jwt.NewValidationError("token exceeds the max age", jwt.ValidationErrorIssuedAt)
+	}
+
+	return nil
+}
+
+func unix(sec float64) time.Time {
+	return time.Unix(int64(sec), 0)
+}
+
 // jwtFromHeader returns a `jwtExtractor` that extracts token from the request header.
 func jwtFromHeader(header string, authScheme string) jwtExtractor {
 	return func(c *rest.Context) (string, error) {
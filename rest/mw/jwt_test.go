@@ -4,8 +4,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/enigma-id/go/auth"
 	"github.com/enigma-id/go/rest"
 	"github.com/stretchr/testify/assert"
 )
@@ -224,3 +226,70 @@ func TestJWT(t *testing.T) {
 		}
 	}
 }
+
+func TestJWTClaimsMapper(t *testing.T) {
+	e := rest.New()
+	key := []byte("secret")
+	h := JWTWithConfig(JWTConfig{
+		SigningKey:   key,
+		ClaimsMapper: auth.DefaultClaimsMapper,
+	})(func(c *rest.Context) error {
+		return c.String(http.StatusOK, auth.Get(c).ID+":"+auth.FromContext(c.Request().Context()).TenantID)
+	})
+
+	request := func(claims jwt.MapClaims) (*httptest.ResponseRecorder, error) {
+		token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(rest.HeaderAuthorization, "Bearer "+token)
+		rec := httptest.NewRecorder()
+		return rec, h(e.NewContext(req, rec))
+	}
+
+	rec, err := request(jwt.MapClaims{"sub": "42", "tenant_id": "acme"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "42:acme", rec.Body.String())
+	}
+
+	_, err = request(jwt.MapClaims{"name": "John Doe"})
+	if he, ok := err.(*rest.HTTPError); assert.True(t, ok) {
+		assert.Equal(t, http.StatusUnauthorized, he.Code)
+		assert.Equal(t, auth.ErrNoSubject, he.Internal)
+	}
+}
+
+func TestJWTLeeway(t *testing.T) {
+	e := rest.New()
+	key := []byte("secret")
+	now := time.Now()
+
+	request := func(config JWTConfig, claims jwt.Claims) error {
+		config.SigningKey = key
+		token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(rest.HeaderAuthorization, "Bearer "+token)
+		return JWTWithConfig(config)(func(c *rest.Context) error {
+			return c.NoContent(http.StatusOK)
+		})(e.NewContext(req, httptest.NewRecorder()))
+	}
+	internal := func(err error) string {
+		if he, ok := err.(*rest.HTTPError); ok && he.Internal != nil {
+			return he.Internal.Error()
+		}
+		return ""
+	}
+
+	skew := JWTConfig{Leeway: 30 * time.Second}
+	expired := jwt.MapClaims{"sub": "1", "exp": now.Add(-10 * time.Second).Unix()}
+	assert.Error(t, request(JWTConfig{}, expired))
+	assert.NoError(t, request(skew, expired))
+	assert.Equal(t, "token is expired", internal(request(skew, jwt.MapClaims{"exp": now.Add(-time.Minute).Unix()})))
+
+	assert.NoError(t, request(skew, jwt.MapClaims{"nbf": now.Add(10 * time.Second).Unix(), "iat": now.Add(10 * time.Second).Unix()}))
+	assert.Equal(t, "token is not valid yet", internal(request(skew, jwt.MapClaims{"nbf": now.Add(time.Minute).Unix()})))
+	assert.Equal(t, "token used before issued", internal(request(skew, jwt.MapClaims{"iat": now.Add(time.Minute).Unix()})))
+
+	age := JWTConfig{MaxTokenAge: time.Hour, Claims: &jwt.StandardClaims{}}
+	assert.NoError(t, request(age, &jwt.StandardClaims{IssuedAt: now.Add(-50 * time.Minute).Unix(), ExpiresAt: now.Add(24 * time.Hour).Unix()}))
+	assert.Equal(t, "token exceeds the max age", internal(request(age, &jwt.StandardClaims{IssuedAt: now.Add(-2 * time.Hour).Unix(), ExpiresAt: now.Add(24 * time.Hour).Unix()})))
+	assert.Equal(t, "token has no issued at", internal(request(age, &jwt.StandardClaims{ExpiresAt: now.Add(time.Hour).Unix()})))
+}
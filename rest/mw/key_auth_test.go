@@ -0,0 +1,70 @@
+package mw
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/enigma-id/go/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyAuth(t *testing.T) {
+	e := rest.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	res := httptest.NewRecorder()
+	c := e.NewContext(req, res)
+	config := KeyAuthConfig{
+		Validator: func(key string, c *rest.Context) (bool, error) {
+			return key == "valid-key", nil
+		},
+	}
+	h := KeyAuthWithConfig(config)(func(c *rest.Context) error {
+		return c.String(http.StatusOK, "test")
+	})
+
+	// Valid key
+	auth := DefaultKeyAuthConfig.AuthScheme + " " + "valid-key"
+	req.Header.Set(rest.HeaderAuthorization, auth)
+	assert.NoError(t, h(c))
+
+	// Invalid key
+	auth = DefaultKeyAuthConfig.AuthScheme + " " + "invalid-key"
+	req.Header.Set(rest.HeaderAuthorization, auth)
+	he := h(c).(*rest.HTTPError)
+	assert.Equal(t, http.StatusUnauthorized, he.Code)
+
+	// Missing Authorization header
+	req.Header.Del(rest.HeaderAuthorization)
+	he = h(c).(*rest.HTTPError)
+	assert.Equal(t, http.StatusBadRequest, he.Code)
+
+	// Key from custom header
+	config.KeyLookup = "header:API-Key"
+	h = KeyAuthWithConfig(config)(func(c *rest.Context) error {
+		return c.String(http.StatusOK, "test")
+	})
+	req.Header.Set("API-Key", "valid-key")
+	assert.NoError(t, h(c))
+
+	// Key from query string
+	config.KeyLookup = "query:key"
+	h = KeyAuthWithConfig(config)(func(c *rest.Context) error {
+		return c.String(http.StatusOK, "test")
+	})
+	q := req.URL.Query()
+	q.Add("key", "valid-key")
+	req.URL.RawQuery = q.Encode()
+	assert.NoError(t, h(c))
+
+	// Key from cookie
+	config.KeyLookup = "cookie:key"
+	h = KeyAuthWithConfig(config)(func(c *rest.Context) error {
+		return c.String(http.StatusOK, "test")
+	})
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "key", Value: "valid-key"})
+	assert.NoError(t, h(e.NewContext(req, res)))
+
+	assert.Panics(t, func() { KeyAuth(nil) })
+}
@@ -0,0 +1,89 @@
+package mw
+
+import (
+	"time"
+
+	"github.com/enigma-id/go/i18n"
+	"github.com/enigma-id/go/rest"
+)
+
+type (
+	// LocaleConfig defines the config for Locale middleware.
+	LocaleConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Bundle used to match the requested locale.
+		// Optional. Default value i18n.Default.
+		Bundle *i18n.Bundle
+
+		// QueryParam name of query parameter that overrides Accept-Language header.
+		// Optional. Default value "lang".
+		QueryParam string
+
+		// TimezoneHeader is the header holding IANA timezone of the user,
+		// ex. "Asia/Jakarta", unknown timezone is ignored.
+		// Optional. Default value "X-Timezone".
+		TimezoneHeader string
+	}
+)
+
+var (
+	// DefaultLocaleConfig is the default Locale middleware config.
+	DefaultLocaleConfig = LocaleConfig{
+		Skipper:        DefaultSkipper,
+		QueryParam:     "lang",
+		TimezoneHeader: "X-Timezone",
+	}
+)
+
+// Locale returns a middleware that resolves locale of the request
+// from the query parameter or Accept-Language header, the locale
+// is placed on the request context so i18n.T and c.T use it.
+// Timezone of the user is placed on the context as well.
+func Locale() rest.MiddlewareFunc {
+	return LocaleWithConfig(DefaultLocaleConfig)
+}
+
+// LocaleWithConfig returns a Locale middleware with config.
+func LocaleWithConfig(config LocaleConfig) rest.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultLocaleConfig.Skipper
+	}
+	if config.QueryParam == "" {
+		config.QueryParam = DefaultLocaleConfig.QueryParam
+	}
+	if config.TimezoneHeader == "" {
+		config.TimezoneHeader = DefaultLocaleConfig.TimezoneHeader
+	}
+
+	return func(next rest.HandlerFunc) rest.HandlerFunc {
+		return func(c *rest.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			b := config.Bundle
+			if b == nil {
+				b = i18n.Default
+			}
+
+			req := c.Request()
+			locale := b.Match(c.QueryParam(config.QueryParam), req.Header.Get(rest.HeaderAcceptLanguage))
+
+			ctx := i18n.WithLocale(req.Context(), locale)
+			if tz := req.Header.Get(config.TimezoneHeader); tz != "" {
+				if loc, err := time.LoadLocation(tz); err == nil {
+					ctx = i18n.WithTimezone(ctx, loc)
+				}
+			}
+
+			c.SetRequest(req.WithContext(ctx))
+			c.Response().Header().Set(rest.HeaderContentLanguage, locale)
+			c.Response().Header().Add(rest.HeaderVary, rest.HeaderAcceptLanguage)
+
+			return next(c)
+		}
+	}
+}
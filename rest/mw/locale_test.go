@@ -0,0 +1,99 @@
+package mw
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/enigma-id/go/i18n"
+	"github.com/enigma-id/go/rest"
+	"github.com/enigma-id/go/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocale(t *testing.T) {
+	b := i18n.New("en")
+	b.Add("en", map[string]interface{}{"hello": "Hello :name"})
+	b.Add("id", map[string]interface{}{"hello": "Halo :name"})
+
+	e := rest.New()
+	h := LocaleWithConfig(LocaleConfig{Bundle: b})(func(c *rest.Context) error {
+		return c.String(http.StatusOK, c.Locale())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(rest.HeaderAcceptLanguage, "id-ID,id;q=0.9")
+	rec := httptest.NewRecorder()
+	h(e.NewContext(req, rec))
+	assert.Equal(t, "id", rec.Body.String())
+	assert.Equal(t, "id", rec.Header().Get(rest.HeaderContentLanguage))
+
+	req = httptest.NewRequest(http.MethodGet, "/?lang=en", nil)
+	req.Header.Set(rest.HeaderAcceptLanguage, "id")
+	rec = httptest.NewRecorder()
+	h(e.NewContext(req, rec))
+	assert.Equal(t, "en", rec.Body.String())
+}
+
+func TestLocaleMessages(t *testing.T) {
+	d := i18n.Default
+	defer func() { i18n.Default = d }()
+
+	i18n.Default = i18n.New("en")
+	i18n.Default.Add("id", map[string]interface{}{
+		"Unprocessable Entity": "Data tidak valid",
+		"validation":           map[string]interface{}{"required": ":attribute wajib diisi"},
+	})
+
+	e := rest.New()
+	h := Locale()(func(c *rest.Context) error {
+		return c.Serve(validation.SetError("full_name.required", "The full name field is required"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(rest.HeaderAcceptLanguage, "id")
+	rec := httptest.NewRecorder()
+	h(e.NewContext(req, rec))
+	assert.Contains(t, rec.Body.String(), `"message":"Data tidak valid"`)
+	assert.Contains(t, rec.Body.String(), `"full_name":"full name wajib diisi"`)
+}
+
+func TestLocaleTimezone(t *testing.T) {
+	e := rest.New()
+	h := Locale()(func(c *rest.Context) error {
+		return c.String(http.StatusOK, c.Timezone().String())
+	})
+
+	for tz, want := range map[string]string{"Asia/Jakarta": "Asia/Jakarta", "Mars/Olympus": "Local", "": "Local"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Timezone", tz)
+		rec := httptest.NewRecorder()
+		h(e.NewContext(req, rec))
+		assert.Equal(t, want, rec.Body.String(), tz)
+	}
+}
+
+func TestLocaleMessagePlaceholders(t *testing.T) {
+	d := i18n.Default
+	defer func() { i18n.Default = d }()
+
+	i18n.Default = i18n.New("en")
+	i18n.Default.Add("id", map[string]interface{}{
+		"validation": map[string]interface{}{"gte": ":attribute minimal :param karakter"},
+	})
+
+	type signup struct {
+		Password string `json:"password" valid:"gte:8"`
+	}
+
+	e := rest.New()
+	h := Locale()(func(c *rest.Context) error {
+		return c.Serve(validation.New().Struct(signup{Password: "secret"}))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(rest.HeaderAcceptLanguage, "id")
+	rec := httptest.NewRecorder()
+	h(e.NewContext(req, rec))
+	assert.Contains(t, rec.Body.String(), `"password":"password minimal 8 karakter"`)
+}
@@ -0,0 +1,329 @@
+package mw
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/enigma-id/go/rest"
+	"golang.org/x/oauth2"
+)
+
+type (
+	// OAuth2Config defines the config for OAuth2 middleware.
+	OAuth2Config struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Providers used for login, keyed by the provider name.
+		// Required.
+		Providers []*OAuth2Provider
+
+		// Signing key of the state cookie and the issued jwt.
+		// Required.
+		SigningKey []byte
+
+		// Prefix of the login and callback path, the provider login is served
+		// on "<prefix>/<provider>/login" and callback on "<prefix>/<provider>/callback".
+		// Optional. Default value "/auth".
+		Prefix string
+
+		// SuccessHandler is called with the identity after successful callback.
+		// Optional. Default issues jwt of the identity, see OAuth2Config.TokenExpiry.
+		SuccessHandler OAuth2SuccessHandler
+
+		// RedirectURL where the user is redirected after login with the jwt set
+		// into "token" cookie, when empty the jwt is returned as json.
+		// Optional.
+		RedirectURL string
+
+		// TokenExpiry of the issued jwt.
+		// Optional. Default value 24 hours.
+		TokenExpiry time.Duration
+	}
+
+	// OAuth2SuccessHandler handles the identity after successful login.
+	OAuth2SuccessHandler func(c *rest.Context, id *OAuth2Identity) error
+
+	// OAuth2Identity is normalized user information of the providers.
+	OAuth2Identity struct {
+		Provider      string                 `json:"provider"`
+		ID            string                 `json:"id"`
+		Email         string                 `json:"email,omitempty"`
+		EmailVerified bool                   `json:"email_verified"`
+		Name          string                 `json:"name,omitempty"`
+		Username      string                 `json:"username,omitempty"`
+		Picture       string                 `json:"picture,omitempty"`
+		Raw           map[string]interface{} `json:"-"`
+		Token         *oauth2.Token          `json:"-"`
+	}
+
+	// oauth2State is kept on signed cookie between login and callback.
+	oauth2State struct {
+		State    string `json:"s"`
+		Verifier string `json:"v"`
+		Redirect string `json:"r,omitempty"`
+		Expires  int64  `json:"e"`
+	}
+)
+
+const oauth2StateCookie = "oauth2_state"
+
+// Errors
+var (
+	ErrOAuth2State = rest.NewHTTPError(http.StatusBadRequest, "invalid oauth2 state")
+)
+
+var (
+	// DefaultOAuth2Config is the default OAuth2 middleware config.
+	DefaultOAuth2Config = OAuth2Config{
+		Skipper:     DefaultSkipper,
+		Prefix:      "/auth",
+		TokenExpiry: 24 * time.Hour,
+	}
+)
+
+// OAuth2 returns a middleware serving social login of the providers,
+// the resulting identity is issued as jwt signed by the key, so the
+// following requests can be authenticated using JWT middleware.
+//
+//	r.Pre(mw.OAuth2(key, mw.Google(id, secret, callbackURL), mw.GitHub(id, secret, callbackURL)))
+//	// GET /auth/google/login, GET /auth/google/callback
+func OAuth2(key []byte, providers ...*OAuth2Provider) rest.MiddlewareFunc {
+	c := DefaultOAuth2Config
+	c.SigningKey = key
+	c.Providers = providers
+	return OAuth2WithConfig(c)
+}
+
+// OAuth2WithConfig returns an OAuth2 middleware with config.
+func OAuth2WithConfig(config OAuth2Config) rest.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultOAuth2Config.Skipper
+	}
+	if len(config.SigningKey) == 0 {
+		panic("rest: oauth2 middleware requires signing key")
+	}
+	if config.Prefix == "" {
+		config.Prefix = DefaultOAuth2Config.Prefix
+	}
+	if config.TokenExpiry == 0 {
+		config.TokenExpiry = DefaultOAuth2Config.TokenExpiry
+	}
+	if config.SuccessHandler == nil {
+		config.SuccessHandler = config.issueToken
+	}
+
+	providers := make(map[string]*OAuth2Provider)
+	for _, p := range config.Providers {
+		providers[p.Name] = p
+	}
+
+	return func(next rest.HandlerFunc) rest.HandlerFunc {
+		return func(c *rest.Context) error {
+			if config.Skipper(c) || c.Request().Method != http.MethodGet {
+				return next(c)
+			}
+
+			path := c.Request().URL.Path
+			if !strings.HasPrefix(path, config.Prefix+"/") {
+				return next(c)
+			}
+
+			parts := strings.Split(strings.TrimPrefix(path, config.Prefix+"/"), "/")
+			p, ok := providers[parts[0]]
+			if len(parts) != 2 || !ok {
+				return next(c)
+			}
+
+			switch parts[1] {
+			case "login":
+				return config.login(c, p)
+			case "callback":
+				return config.callback(c, p)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// login redirects into authorization page of the provider.
+func (config *OAuth2Config) login(c *rest.Context, p *OAuth2Provider) error {
+	cfg, err := p.config(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	state := make([]byte, 32)
+	if _, err = rand.Read(state); err != nil {
+		return err
+	}
+
+	s := &oauth2State{
+		State:    base64.RawURLEncoding.EncodeToString(state),
+		Verifier: oauth2.GenerateVerifier(),
+		Expires:  time.Now().Add(10 * time.Minute).Unix(),
+	}
+
+	// only relative redirect is kept, to prevent open redirect
+	if r := c.QueryParam("redirect"); localRedirect(r) {
+		s.Redirect = r
+	}
+
+	c.SetCookie(&http.Cookie{
+		Name:     oauth2StateCookie,
+		Value:    config.sign(s),
+		Path:     config.Prefix,
+		MaxAge:   600,
+		HttpOnly: true,
+		Secure:   c.IsTLS(),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return c.Redirect(http.StatusFound, cfg.AuthCodeURL(s.State, oauth2.S256ChallengeOption(s.Verifier)))
+}
+
+// localRedirect reports whether the redirect is a path of this host,
+// backslash is rejected since browsers read "/\evil.com" as "//evil.com".
+func localRedirect(r string) bool {
+	if !strings.HasPrefix(r, "/") || strings.HasPrefix(r, "//") || strings.ContainsAny(r, "\\\r\n\t") {
+		return false
+	}
+
+	u, err := url.Parse(r)
+	return err == nil && u.Scheme == "" && u.Host == ""
+}
+
+// callback exchanges the code and fetches the identity.
+func (config *OAuth2Config) callback(c *rest.Context, p *OAuth2Provider) error {
+	if e := c.QueryParam("error"); e != "" {
+		return rest.NewHTTPError(http.StatusUnauthorized, "oauth2: "+e)
+	}
+
+	ck, err := c.Cookie(oauth2StateCookie)
+	if err != nil {
+		return ErrOAuth2State
+	}
+
+	s, ok := config.verify(ck.Value)
+	if !ok || s.Expires < time.Now().Unix() || !hmac.Equal([]byte(s.State), []byte(c.QueryParam("state"))) {
+		return ErrOAuth2State
+	}
+
+	c.SetCookie(&http.Cookie{Name: oauth2StateCookie, Path: config.Prefix, MaxAge: -1, HttpOnly: true})
+
+	ctx := c.Request().Context()
+	cfg, err := p.config(ctx)
+	if err != nil {
+		return err
+	}
+
+	if p.Client != nil {
+		ctx = contextWithClient(ctx, p.Client)
+	}
+
+	token, err := cfg.Exchange(ctx, c.QueryParam("code"), oauth2.VerifierOption(s.Verifier))
+	if err != nil {
+		return &rest.HTTPError{Code: http.StatusUnauthorized, Message: "oauth2: code exchange failed", Internal: err}
+	}
+
+	id, err := p.identity(ctx, cfg.Client(ctx, token))
+	if err != nil {
+		return &rest.HTTPError{Code: http.StatusUnauthorized, Message: "oauth2: fetching user info failed", Internal: err}
+	}
+	id.Provider = p.Name
+	id.Token = token
+
+	c.Set("oauth2", id)
+	if s.Redirect != "" {
+		c.Set("oauth2.redirect", s.Redirect)
+	}
+
+	return config.SuccessHandler(c, id)
+}
+
+// issueToken is default success handler, it issues jwt of the identity
+// and set it into "token" cookie when RedirectURL is set.
+func (config *OAuth2Config) issueToken(c *rest.Context, id *OAuth2Identity) error {
+	claims := jwt.MapClaims{
+		"sub":            id.Provider + ":" + id.ID,
+		"provider":       id.Provider,
+		"email":          id.Email,
+		"email_verified": id.EmailVerified,
+		"name":           id.Name,
+		"picture":        id.Picture,
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(config.TokenExpiry).Unix(),
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(config.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	if config.RedirectURL == "" {
+		return c.JSON(http.StatusOK, rest.Map{"token": token, "identity": id})
+	}
+
+	c.SetCookie(&http.Cookie{
+		Name:     "token",
+		Value:    token,
+		Path:     "/",
+		Expires:  time.Now().Add(config.TokenExpiry),
+		HttpOnly: true,
+		Secure:   c.IsTLS(),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	redirect := config.RedirectURL
+	if r, ok := c.Get("oauth2.redirect").(string); ok {
+		redirect = r
+	}
+
+	return c.Redirect(http.StatusFound, redirect)
+}
+
+func (config *OAuth2Config) sign(s *oauth2State) string {
+	b, _ := json.Marshal(s)
+	v := base64.RawURLEncoding.EncodeToString(b)
+
+	mac := hmac.New(sha256.New, config.SigningKey)
+	mac.Write([]byte(v))
+
+	return v + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (config *OAuth2Config) verify(v string) (*oauth2State, bool) {
+	i := strings.LastIndex(v, ".")
+	if i < 0 {
+		return nil, false
+	}
+
+	mac := hmac.New(sha256.New, config.SigningKey)
+	mac.Write([]byte(v[:i]))
+	sig, err := base64.RawURLEncoding.DecodeString(v[i+1:])
+	if err != nil || !hmac.Equal(sig, mac.Sum(nil)) {
+		return nil, false
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(v[:i])
+	if err != nil {
+		return nil, false
+	}
+
+	s := new(oauth2State)
+	if err = json.Unmarshal(b, s); err != nil {
+		return nil, false
+	}
+
+	return s, true
+}
@@ -0,0 +1,249 @@
+package mw
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// OAuth2Provider is oauth2 or openid connect provider.
+type OAuth2Provider struct {
+	Name         string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	Endpoint     oauth2.Endpoint
+
+	// UserInfoURL returns openid connect claims of the user.
+	UserInfoURL string
+
+	// Issuer of openid connect provider, the endpoints are discovered
+	// from the issuer when Endpoint is empty.
+	Issuer string
+
+	// UserInfo fetches the identity using the authorized client,
+	// default reads openid connect claims from UserInfoURL.
+	UserInfo func(ctx context.Context, client *http.Client) (*OAuth2Identity, error)
+
+	// Client used to call the provider, default is http.DefaultClient.
+	Client *http.Client
+
+	mu sync.Mutex
+}
+
+// Google returns Google provider, the redirect url should point into
+// the callback path, ex. https://api.example.com/auth/google/callback.
+func Google(clientID, clientSecret, redirectURL string) *OAuth2Provider {
+	return &OAuth2Provider{
+		Name:         "google",
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       []string{"openid", "email", "profile"},
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  "https://accounts.google.com/o/oauth2/v2/auth",
+			TokenURL: "https://oauth2.googleapis.com/token",
+		},
+		UserInfoURL: "https://openidconnect.googleapis.com/v1/userinfo",
+	}
+}
+
+// GitHub returns GitHub provider, primary verified email is used
+// when the public email of the user is empty.
+func GitHub(clientID, clientSecret, redirectURL string) *OAuth2Provider {
+	p := &OAuth2Provider{
+		Name:         "github",
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       []string{"read:user", "user:email"},
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  "https://github.com/login/oauth/authorize",
+			TokenURL: "https://github.com/login/oauth/access_token",
+		},
+		UserInfoURL: "https://api.github.com/user",
+	}
+	p.UserInfo = p.githubUser
+
+	return p
+}
+
+// OIDC returns generic openid connect provider, the endpoints
+// are discovered from the issuer on the first login.
+func OIDC(name, issuer, clientID, clientSecret, redirectURL string) *OAuth2Provider {
+	return &OAuth2Provider{
+		Name:         name,
+		Issuer:       strings.TrimRight(issuer, "/"),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       []string{"openid", "email", "profile"},
+	}
+}
+
+func (p *OAuth2Provider) config(ctx context.Context) (*oauth2.Config, error) {
+	if err := p.discover(ctx); err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Config{
+		ClientID:     p.ClientID,
+		ClientSecret: p.ClientSecret,
+		RedirectURL:  p.RedirectURL,
+		Scopes:       p.Scopes,
+		Endpoint:     p.Endpoint,
+	}, nil
+}
+
+// discover reads endpoints of the issuer from .well-known/openid-configuration.
+func (p *OAuth2Provider) discover(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.Issuer == "" || p.Endpoint.AuthURL != "" {
+		return nil
+	}
+
+	var d struct {
+		Issuer           string `json:"issuer"`
+		AuthURL          string `json:"authorization_endpoint"`
+		TokenURL         string `json:"token_endpoint"`
+		UserInfoEndpoint string `json:"userinfo_endpoint"`
+	}
+
+	if err := p.getJSON(ctx, p.client(), p.Issuer+"/.well-known/openid-configuration", &d); err != nil {
+		return fmt.Errorf("oauth2: discovery of %s failed, %v", p.Issuer, err)
+	}
+
+	if strings.TrimRight(d.Issuer, "/") != p.Issuer {
+		return fmt.Errorf("oauth2: issuer mismatch, expected %s got %s", p.Issuer, d.Issuer)
+	}
+
+	p.Endpoint = oauth2.Endpoint{AuthURL: d.AuthURL, TokenURL: d.TokenURL}
+	if p.UserInfoURL == "" {
+		p.UserInfoURL = d.UserInfoEndpoint
+	}
+
+	return nil
+}
+
+func (p *OAuth2Provider) identity(ctx context.Context, client *http.Client) (*OAuth2Identity, error) {
+	if p.UserInfo != nil {
+		return p.UserInfo(ctx, client)
+	}
+
+	raw := make(map[string]interface{})
+	if err := p.getJSON(ctx, client, p.UserInfoURL, &raw); err != nil {
+		return nil, err
+	}
+
+	id := &OAuth2Identity{
+		ID:       claimString(raw, "sub"),
+		Email:    claimString(raw, "email"),
+		Name:     claimString(raw, "name"),
+		Username: claimString(raw, "preferred_username"),
+		Picture:  claimString(raw, "picture"),
+		Raw:      raw,
+	}
+
+	// some providers return email_verified as string
+	switch v := raw["email_verified"].(type) {
+	case bool:
+		id.EmailVerified = v
+	case string:
+		id.EmailVerified = v == "true"
+	}
+
+	if id.ID == "" {
+		return nil, fmt.Errorf("oauth2: user info without subject")
+	}
+
+	return id, nil
+}
+
+func (p *OAuth2Provider) githubUser(ctx context.Context, client *http.Client) (*OAuth2Identity, error) {
+	raw := make(map[string]interface{})
+	if err := p.getJSON(ctx, client, p.UserInfoURL, &raw); err != nil {
+		return nil, err
+	}
+
+	id := &OAuth2Identity{
+		ID:       claimString(raw, "id"),
+		Email:    claimString(raw, "email"),
+		Name:     claimString(raw, "name"),
+		Username: claimString(raw, "login"),
+		Picture:  claimString(raw, "avatar_url"),
+		Raw:      raw,
+	}
+
+	// public email is not verified, use the primary verified one instead
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := p.getJSON(ctx, client, strings.TrimSuffix(p.UserInfoURL, "/user")+"/user/emails", &emails); err == nil {
+		for _, e := range emails {
+			if e.Primary && e.Verified {
+				id.Email, id.EmailVerified = e.Email, true
+			}
+		}
+	}
+
+	if id.ID == "" {
+		return nil, fmt.Errorf("oauth2: github user without id")
+	}
+
+	return id, nil
+}
+
+func (p *OAuth2Provider) client() *http.Client {
+	if p.Client != nil {
+		return p.Client
+	}
+
+	return http.DefaultClient
+}
+
+func (p *OAuth2Provider) getJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returns %s", url, res.Status)
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+func claimString(m map[string]interface{}, key string) string {
+	switch v := m[key].(type) {
+	case string:
+		return v
+	case float64:
+		return fmt.Sprintf("%.0f", v)
+	case json.Number:
+		return v.String()
+	}
+
+	return ""
+}
+
+func contextWithClient(ctx context.Context, c *http.Client) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, c)
+}
@@ -0,0 +1,149 @@
+package mw
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/enigma-id/go/rest"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+func fakeOIDC(t *testing.T) *httptest.Server {
+	var s *httptest.Server
+	var challenge string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 s.URL,
+			"authorization_endpoint": s.URL + "/authorize",
+			"token_endpoint":         s.URL + "/token",
+			"userinfo_endpoint":      s.URL + "/userinfo",
+		})
+	})
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		challenge = r.URL.Query().Get("code_challenge")
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("code") != "good" || oauth2.S256ChallengeFromVerifier(r.Form.Get("code_verifier")) != challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"at","token_type":"Bearer"}`))
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer at", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"sub":"42","email":"john@example.com","email_verified":"true","name":"John"}`))
+	})
+	s = httptest.NewServer(mux)
+
+	return s
+}
+
+func TestOAuth2(t *testing.T) {
+	idp := fakeOIDC(t)
+	defer idp.Close()
+
+	key := []byte("secret")
+	e := rest.New()
+	e.Pre(OAuth2(key, OIDC("acme", idp.URL, "client", "secret", "http://app/auth/acme/callback")))
+	e.GET("/me", func(c *rest.Context) error {
+		return c.String(http.StatusOK, c.Get("user").(*jwt.Token).Claims.(jwt.MapClaims)["email"].(string))
+	}, JWT(key))
+
+	// login redirects into the provider
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/auth/acme/login", nil))
+	assert.Equal(t, http.StatusFound, rec.Code)
+
+	loc, _ := url.Parse(rec.Header().Get(rest.HeaderLocation))
+	assert.True(t, strings.HasPrefix(loc.String(), idp.URL+"/authorize"))
+	assert.Equal(t, "S256", loc.Query().Get("code_challenge_method"))
+	http.Get(loc.String())
+
+	cookie := rec.Result().Cookies()[0]
+	state := loc.Query().Get("state")
+
+	// invalid state
+	req := httptest.NewRequest(http.MethodGet, "/auth/acme/callback?code=good&state=other", nil)
+	req.AddCookie(cookie)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// tampered cookie
+	req = httptest.NewRequest(http.MethodGet, "/auth/acme/callback?code=good&state="+state, nil)
+	req.AddCookie(&http.Cookie{Name: cookie.Name, Value: cookie.Value + "x"})
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/auth/acme/callback?code=good&state="+state, nil)
+	req.AddCookie(cookie)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var res struct {
+		Token    string         `json:"token"`
+		Identity OAuth2Identity `json:"identity"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, "42", res.Identity.ID)
+	assert.Equal(t, "acme", res.Identity.Provider)
+	assert.True(t, res.Identity.EmailVerified)
+
+	// issued jwt authenticates following requests
+	req = httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set(rest.HeaderAuthorization, "Bearer "+res.Token)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, "john@example.com", rec.Body.String())
+}
+
+func TestGitHubUser(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/user/emails" {
+			w.Write([]byte(`[{"email":"old@example.com","verified":true},{"email":"john@example.com","primary":true,"verified":true}]`))
+			return
+		}
+		w.Write([]byte(`{"id":1001,"login":"john","email":"public@example.com","avatar_url":"http://img"}`))
+	}))
+	defer s.Close()
+
+	p := GitHub("id", "secret", "")
+	p.UserInfoURL = s.URL + "/user"
+
+	id, err := p.identity(context.Background(), http.DefaultClient)
+	assert.NoError(t, err)
+	assert.Equal(t, "1001", id.ID)
+	assert.Equal(t, "john", id.Username)
+	assert.Equal(t, "john@example.com", id.Email)
+	assert.True(t, id.EmailVerified)
+}
+
+func TestOAuth2LocalRedirect(t *testing.T) {
+	for r, ok := range map[string]bool{
+		"/dashboard":         true,
+		"/orders?page=2#top": true,
+		"":                   false,
+		"dashboard":          false,
+		"//evil.com":         false,
+		"/\\evil.com":        false,
+		"/\\/evil.com":       false,
+		"/%0d%0aevil":        true,
+		"/\r\nLocation: x":   false,
+		"https://evil.com":   false,
+	} {
+		assert.Equal(t, ok, localRedirect(r), r)
+	}
+}
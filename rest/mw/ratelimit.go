@@ -0,0 +1,265 @@
+package mw
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/enigma-id/go/cache"
+	"github.com/enigma-id/go/rest"
+)
+
+type (
+	// RateLimitConfig defines the config for RateLimit middleware.
+	RateLimitConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Policy resolves the quota of the request, ex. by plan of the
+		// tenant or the api key, zero limit means unlimited.
+		// Optional. Default value 100 requests per minute of each ip.
+		Policy func(c *rest.Context) RateLimitPolicy
+
+		// Store keeps the counter of the quota.
+		// Optional. Default value is in memory store.
+		Store RateLimitStore
+	}
+
+	// RateLimitPolicy is the quota of the request.
+	RateLimitPolicy struct {
+		// Key identifies the quota, ex. "tenant:12" or "key:abc".
+		Key    string
+		Limit  int
+		Window time.Duration
+	}
+
+	// RateLimitUsage is the usage of the quota in the current window.
+	RateLimitUsage struct {
+		Key       string    `json:"key"`
+		Limit     int       `json:"limit"`
+		Used      int       `json:"used"`
+		Remaining int       `json:"remaining"`
+		Reset     time.Time `json:"reset"`
+	}
+
+	// RateLimitStore counts the requests of the quota in fixed window.
+	RateLimitStore interface {
+		// Take increments the counter of the quota and returns the usage.
+		Take(p RateLimitPolicy) (*RateLimitUsage, error)
+
+		// Usage returns the usage of the quota, nil when the quota
+		// is not used in the current window.
+		Usage(key string) (*RateLimitUsage, error)
+	}
+
+	// memoryRateLimitStore keeps the counters in memory.
+	memoryRateLimitStore struct {
+		mu      sync.Mutex
+		windows map[string]*RateLimitUsage
+		sweep   time.Time
+	}
+
+	// cacheRateLimitStore keeps the counters in the cache.
+	cacheRateLimitStore struct {
+		cache  cache.Cache
+		prefix string
+	}
+
+	// rateLimitWindow is the limit of the current window of the quota.
+	rateLimitWindow struct {
+		Limit int
+		Reset time.Time
+	}
+)
+
+// Headers of the rate limit.
+const (
+	HeaderRateLimitLimit     = "X-RateLimit-Limit"
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining"
+	HeaderRateLimitReset     = "X-RateLimit-Reset"
+	HeaderRetryAfter         = "Retry-After"
+)
+
+var (
+	// DefaultRateLimitConfig is the default RateLimit middleware config.
+	DefaultRateLimitConfig = RateLimitConfig{
+		Skipper: DefaultSkipper,
+		Policy: func(c *rest.Context) RateLimitPolicy {
+			return RateLimitPolicy{Key: "ip:" + c.RealIP(), Limit: 100, Window: time.Minute}
+		},
+	}
+)
+
+// RateLimit returns a middleware that limits the requests of each ip
+// address, the limit is sent on X-RateLimit-* headers and 429 is
+// returned when the quota is exhausted.
+func RateLimit(limit int, window time.Duration) rest.MiddlewareFunc {
+	c := DefaultRateLimitConfig
+	c.Policy = func(c *rest.Context) RateLimitPolicy {
+		return RateLimitPolicy{Key: "ip:" + c.RealIP(), Limit: limit, Window: window}
+	}
+	return RateLimitWithConfig(c)
+}
+
+// RateLimitWithConfig returns a RateLimit middleware with config.
+//
+//	mw.RateLimitWithConfig(mw.RateLimitConfig{
+//		Policy: func(c *rest.Context) mw.RateLimitPolicy {
+//			t := tenant.FromContext(c.Request().Context())
+//			return mw.RateLimitPolicy{Key: "tenant:" + t.ID, Limit: plans[t.Plan], Window: time.Minute}
+//		},
+//		Store: mw.NewRateLimitCacheStore(cache.Default),
+//	})
+func RateLimitWithConfig(config RateLimitConfig) rest.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultRateLimitConfig.Skipper
+	}
+	if config.Policy == nil {
+		config.Policy = DefaultRateLimitConfig.Policy
+	}
+	if config.Store == nil {
+		config.Store = NewRateLimitMemoryStore()
+	}
+
+	return func(next rest.HandlerFunc) rest.HandlerFunc {
+		return func(c *rest.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			p := config.Policy(c)
+			if p.Limit <= 0 || p.Window <= 0 {
+				return next(c)
+			}
+
+			u, err := config.Store.Take(p)
+			if err != nil {
+				return err
+			}
+
+			h := c.Response().Header()
+			h.Set(HeaderRateLimitLimit, strconv.Itoa(u.Limit))
+			h.Set(HeaderRateLimitRemaining, strconv.Itoa(u.Remaining))
+			h.Set(HeaderRateLimitReset, strconv.FormatInt(u.Reset.Unix(), 10))
+
+			if u.Used > u.Limit {
+				retry := int(time.Until(u.Reset)/time.Second) + 1
+				h.Set(HeaderRetryAfter, strconv.Itoa(retry))
+				return rest.ErrTooManyRequests
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// NewRateLimitMemoryStore creates store that keeps the counters in memory,
+// it's only accurate for single instance service.
+func NewRateLimitMemoryStore() RateLimitStore {
+	return &memoryRateLimitStore{windows: make(map[string]*RateLimitUsage)}
+}
+
+func (s *memoryRateLimitStore) Take(p RateLimitPolicy) (*RateLimitUsage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.After(s.sweep) {
+		for k, u := range s.windows {
+			if !now.Before(u.Reset) {
+				delete(s.windows, k)
+			}
+		}
+		s.sweep = now.Add(time.Minute)
+	}
+
+	u, ok := s.windows[p.Key]
+	if !ok || !now.Before(u.Reset) {
+		u = &RateLimitUsage{Key: p.Key, Reset: now.Truncate(p.Window).Add(p.Window)}
+		s.windows[p.Key] = u
+	}
+
+	u.Limit = p.Limit
+	u.Used++
+	u.Remaining = remaining(u)
+
+	c := *u
+	return &c, nil
+}
+
+func (s *memoryRateLimitStore) Usage(key string) (*RateLimitUsage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.windows[key]
+	if !ok || !time.Now().Before(u.Reset) {
+		return nil, nil
+	}
+
+	c := *u
+	return &c, nil
+}
+
+// NewRateLimitCacheStore creates store that keeps the counters in the cache,
+// so it's shared between instances. The cache must be cache.Counter, ex.
+// cache.RedisCache, the requests are counted by its atomic increment.
+func NewRateLimitCacheStore(c cache.Cache) RateLimitStore {
+	return &cacheRateLimitStore{cache: c, prefix: "ratelimit:"}
+}
+
+func (s *cacheRateLimitStore) Take(p RateLimitPolicy) (*RateLimitUsage, error) {
+	reset := time.Now().Truncate(p.Window).Add(p.Window)
+	expires := time.Until(reset) + time.Second
+
+	n, err := cache.Increment(s.cache, s.counter(p.Key, reset), 1, expires)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &RateLimitUsage{Key: p.Key, Limit: p.Limit, Used: int(n), Reset: reset}
+	u.Remaining = remaining(u)
+
+	if n == 1 {
+		// the window is kept for Usage, the first request of the window sets it
+		err = s.cache.Set(s.prefix+p.Key, rateLimitWindow{Limit: p.Limit, Reset: reset}, expires)
+	}
+
+	return u, err
+}
+
+func (s *cacheRateLimitStore) Usage(key string) (*RateLimitUsage, error) {
+	w := new(rateLimitWindow)
+	if err := s.cache.Get(s.prefix+key, w); err == cache.ErrCacheMiss {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if !time.Now().Before(w.Reset) {
+		return nil, nil
+	}
+
+	// incrementing by zero reads the counter atomically
+	n, err := cache.Increment(s.cache, s.counter(key, w.Reset), 0, time.Until(w.Reset)+time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &RateLimitUsage{Key: key, Limit: w.Limit, Used: int(n), Reset: w.Reset}
+	u.Remaining = remaining(u)
+
+	return u, nil
+}
+
+// counter returns key of the counter of the window.
+func (s *cacheRateLimitStore) counter(key string, reset time.Time) string {
+	return s.prefix + key + ":" + strconv.FormatInt(reset.Unix(), 10)
+}
+
+func remaining(u *RateLimitUsage) int {
+	if n := u.Limit - u.Used; n > 0 {
+		return n
+	}
+	return 0
+}
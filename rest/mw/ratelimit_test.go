@@ -0,0 +1,133 @@
+package mw
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/enigma-id/go/cache"
+	"github.com/enigma-id/go/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	e := rest.New()
+	e.GET("/", func(c *rest.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, RateLimit(2, time.Minute))
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "2", rec.Header().Get(HeaderRateLimitLimit))
+		assert.Equal(t, strconv.Itoa(1-i), rec.Header().Get(HeaderRateLimitRemaining))
+		assert.NotEmpty(t, rec.Header().Get(HeaderRateLimitReset))
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "0", rec.Header().Get(HeaderRateLimitRemaining))
+	assert.NotEmpty(t, rec.Header().Get(HeaderRetryAfter))
+
+	// other ip has its own quota
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.2:1234"
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestRateLimitPolicy(t *testing.T) {
+	plans := map[string]int{"free": 1, "pro": 3, "internal": 0}
+	store := NewRateLimitCacheStore(cache.NewMemory())
+
+	e := rest.New()
+	e.GET("/", func(c *rest.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, RateLimitWithConfig(RateLimitConfig{
+		Store: store,
+		Policy: func(c *rest.Context) RateLimitPolicy {
+			key := c.Request().Header.Get("X-Api-Key")
+			return RateLimitPolicy{Key: "key:" + key, Limit: plans[key], Window: time.Hour}
+		},
+	}))
+
+	count := func(key string, n int) (ok int) {
+		for i := 0; i < n; i++ {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("X-Api-Key", key)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			if rec.Code == http.StatusOK {
+				ok++
+			}
+		}
+		return
+	}
+
+	assert.Equal(t, 1, count("free", 5))
+	assert.Equal(t, 3, count("pro", 5))
+	assert.Equal(t, 5, count("internal", 5))
+
+	u, err := store.Usage("key:pro")
+	if assert.NoError(t, err) && assert.NotNil(t, u) {
+		assert.Equal(t, 3, u.Limit)
+		assert.Equal(t, 5, u.Used)
+		assert.Equal(t, 0, u.Remaining)
+	}
+
+	u, err = store.Usage("key:internal")
+	assert.NoError(t, err)
+	assert.Nil(t, u)
+}
+
+func TestRateLimitMemoryStore(t *testing.T) {
+	s := NewRateLimitMemoryStore()
+	p := RateLimitPolicy{Key: "tenant:1", Limit: 10, Window: time.Minute}
+
+	for i := 0; i < 3; i++ {
+		_, err := s.Take(p)
+		assert.NoError(t, err)
+	}
+
+	u, err := s.Usage("tenant:1")
+	if assert.NoError(t, err) && assert.NotNil(t, u) {
+		assert.Equal(t, 3, u.Used)
+		assert.Equal(t, 7, u.Remaining)
+		assert.True(t, u.Reset.After(time.Now()))
+	}
+
+	u, _ = s.Usage("tenant:2")
+	assert.Nil(t, u)
+}
+
+func TestRateLimitCacheStore(t *testing.T) {
+	s := NewRateLimitCacheStore(cache.NewMemory())
+	p := RateLimitPolicy{Key: "tenant:1", Limit: 10, Window: time.Minute}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 25; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.Take(p)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	u, err := s.Usage("tenant:1")
+	if assert.NoError(t, err) && assert.NotNil(t, u) {
+		assert.Equal(t, 10, u.Limit)
+		assert.Equal(t, 25, u.Used)
+		assert.Equal(t, 0, u.Remaining)
+	}
+
+	_, err = NewRateLimitCacheStore(struct{ cache.Cache }{cache.NewMemory()}).Take(p)
+	assert.Equal(t, cache.ErrNotCounter, err)
+}
@@ -0,0 +1,82 @@
+package mw
+
+import (
+	"github.com/enigma-id/go/auth"
+	"github.com/enigma-id/go/rest"
+)
+
+type (
+	// RBACConfig defines the config for RBAC middleware.
+	RBACConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Roles allowed to access, the principal needs any of them.
+		// Optional. Empty allows any authenticated principal.
+		Roles []string
+
+		// AuditHook receives the allow or deny decision of each request.
+		// Optional. Default value DefaultAuditHook.
+		AuditHook AuditHook
+	}
+)
+
+var (
+	// DefaultRBACConfig is the default RBAC middleware config.
+	DefaultRBACConfig = RBACConfig{
+		Skipper: DefaultSkipper,
+	}
+)
+
+// RBAC returns role based access control middleware, it checks auth.Principal
+// placed by JWT middleware with ClaimsMapper, so it should be registered after it.
+// Scopes of the matched route (see rest.Route.Scopes) are enforced as well.
+//
+// For request without principal, it returns "401 - Unauthorized" error.
+// For principal without any of the roles or missing any scope of the route,
+// or when no route matches the request, it returns "403 - Forbidden" error.
+func RBAC(roles ...string) rest.MiddlewareFunc {
+	c := DefaultRBACConfig
+	c.Roles = roles
+	return RBACWithConfig(c)
+}
+
+// RBACWithConfig returns RBAC middleware with config.
+// See: `RBAC()`.
+func RBACWithConfig(config RBACConfig) rest.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultRBACConfig.Skipper
+	}
+
+	return func(next rest.HandlerFunc) rest.HandlerFunc {
+		return func(c *rest.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			p := auth.Get(c)
+			if p == nil {
+				audit(c, config.AuditHook, "rbac", "", ReasonUnauthenticated, nil)
+				return rest.ErrUnauthorized
+			}
+			if len(config.Roles) > 0 && !p.HasRole(config.Roles...) {
+				audit(c, config.AuditHook, "rbac", "", ReasonMissingRole, nil)
+				return rest.ErrForbidden
+			}
+			// fail closed, the scopes can't be checked without the route
+			r := c.Route()
+			if r == nil {
+				audit(c, config.AuditHook, "rbac", "", ReasonUnknownRoute, nil)
+				return rest.ErrForbidden
+			}
+			if !p.HasScopes(r.Info().Scopes...) {
+				audit(c, config.AuditHook, "rbac", "", ReasonMissingScope, nil)
+				return rest.ErrForbidden
+			}
+
+			audit(c, config.AuditHook, "rbac", "", "", nil)
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package mw
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/enigma-id/go/auth"
+	"github.com/enigma-id/go/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRBAC(t *testing.T) {
+	e := rest.New()
+	handler := func(c *rest.Context) error {
+		return c.String(http.StatusOK, "test")
+	}
+	e.GET("/", handler)
+
+	request := func(mw rest.MiddlewareFunc, p *auth.Principal) error {
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+		c.SetPath("/")
+		if p != nil {
+			auth.Set(c, p)
+		}
+		return mw(handler)(c)
+	}
+
+	admin := &auth.Principal{ID: "1", Roles: []string{"admin", "staff"}}
+	staff := &auth.Principal{ID: "2", Roles: []string{"staff"}}
+
+	assert.NoError(t, request(RBAC("admin"), admin))
+	assert.NoError(t, request(RBAC("admin", "staff"), staff))
+	assert.Equal(t, rest.ErrForbidden, request(RBAC("admin"), staff))
+	assert.Equal(t, rest.ErrUnauthorized, request(RBAC("admin"), nil))
+
+	// any authenticated principal
+	assert.NoError(t, request(RBAC(), &auth.Principal{ID: "3"}))
+	assert.Equal(t, rest.ErrUnauthorized, request(RBAC(), nil))
+
+	// no route to check the scopes of
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/unknown", nil), httptest.NewRecorder())
+	c.SetPath("/unknown")
+	auth.Set(c, admin)
+	assert.Equal(t, rest.ErrForbidden, RBAC()(handler)(c))
+
+	assert.NoError(t, request(RBACWithConfig(RBACConfig{
+		Skipper: func(*rest.Context) bool { return true },
+		Roles:   []string{"admin"},
+	}), nil))
+}
+
+func TestRBACScopes(t *testing.T) {
+	var principal *auth.Principal
+	e := rest.New()
+	e.Use(func(next rest.HandlerFunc) rest.HandlerFunc {
+		return func(c *rest.Context) error {
+			if principal != nil {
+				auth.Set(c, principal)
+			}
+			return next(c)
+		}
+	}, RBAC())
+	e.GET("/orders", func(c *rest.Context) error {
+		return c.NoContent(http.StatusOK)
+	}).Scopes("orders:read", "orders:export")
+	e.GET("/health", func(c *rest.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	serve := func(method, path string, p *auth.Principal) int {
+		principal = p
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/orders", &auth.Principal{ID: "1", Scopes: []string{"orders:export", "orders:read"}}))
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodGet, "/orders", &auth.Principal{ID: "1", Scopes: []string{"orders:read"}}))
+	assert.Equal(t, http.StatusUnauthorized, serve(http.MethodGet, "/orders", nil))
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/health", &auth.Principal{ID: "1"}))
+
+	// HEAD served by the GET handler requires the scopes of the GET route
+	assert.Equal(t, http.StatusOK, serve(http.MethodHead, "/orders", &auth.Principal{ID: "1", Scopes: []string{"orders:export", "orders:read"}}))
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodHead, "/orders", &auth.Principal{ID: "1", Scopes: []string{"orders:read"}}))
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodHead, "/orders", &auth.Principal{ID: "1"}))
+}
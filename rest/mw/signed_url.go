@@ -0,0 +1,60 @@
+package mw
+
+import (
+	"github.com/enigma-id/go/rest"
+)
+
+type (
+	// SignedURLConfig defines the config for VerifySignedURL middleware.
+	SignedURLConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Secret used to sign the url with rest.SignURL.
+		// Required.
+		Secret []byte
+	}
+)
+
+var (
+	// DefaultSignedURLConfig is the default VerifySignedURL middleware config.
+	DefaultSignedURLConfig = SignedURLConfig{
+		Skipper: DefaultSkipper,
+	}
+)
+
+// VerifySignedURL returns a middleware that only passes request
+// with valid and unexpired signature made by rest.SignURL,
+// otherwise rest.ErrInvalidSignature is returned.
+//
+//	r.GET("/invoices/:id/download", download, mw.VerifySignedURL(secret))
+func VerifySignedURL(secret []byte) rest.MiddlewareFunc {
+	c := DefaultSignedURLConfig
+	c.Secret = secret
+	return VerifySignedURLWithConfig(c)
+}
+
+// VerifySignedURLWithConfig returns a VerifySignedURL middleware with config.
+func VerifySignedURLWithConfig(config SignedURLConfig) rest.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultSignedURLConfig.Skipper
+	}
+	if len(config.Secret) == 0 {
+		panic("rest: signed url middleware requires secret")
+	}
+
+	return func(next rest.HandlerFunc) rest.HandlerFunc {
+		return func(c *rest.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			if err := rest.VerifyURL(c.Request().URL, config.Secret); err != nil {
+				return err
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package mw
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/enigma-id/go/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifySignedURL(t *testing.T) {
+	secret := []byte("secret")
+	e := rest.New()
+	e.GET("/invoices/:id/download", func(c *rest.Context) error {
+		return c.String(http.StatusOK, c.Param("id"))
+	}, VerifySignedURL(secret))
+
+	request := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	rec := request(rest.SignURL("/invoices/12/download", url.Values{"format": {"pdf"}}, time.Minute, secret))
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		assert.Equal(t, "12", rec.Body.String())
+	}
+
+	assert.Equal(t, http.StatusForbidden, request("/invoices/12/download").Code)
+	assert.Equal(t, http.StatusForbidden, request(rest.SignURL("/invoices/12/download", nil, -time.Minute, secret)).Code)
+	assert.Panics(t, func() { VerifySignedURL(nil) })
+}
@@ -0,0 +1,301 @@
+package mw
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/enigma-id/go/rest"
+)
+
+type (
+	// StaticConfig defines the config for Static middleware.
+	StaticConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Root is directory of the files, it's used when Filesystem is nil.
+		Root string `yaml:"root"`
+
+		// Filesystem of the files, ex. embed.FS or fs.Sub of it.
+		Filesystem fs.FS
+
+		// Prefix is the url path of the files, ex. "/assets".
+		// Optional. Default value is the prefix of Assets.
+		Prefix string `yaml:"prefix"`
+
+		// Index is served for directory request.
+		// Optional. Default value "index.html".
+		Index string `yaml:"index"`
+
+		// HTML5 serves Index on not found request, for single page application
+		// doing routing on the browser.
+		// Optional. Default value false.
+		HTML5 bool `yaml:"html5"`
+
+		// MaxAge of the files that are not fingerprinted, in seconds,
+		// the browser revalidates the files when it's zero.
+		// Optional. Default value 0.
+		MaxAge int `yaml:"max_age"`
+
+		// Fingerprint matches file name having hash of the content,
+		// the file is served with immutable Cache-Control. The first
+		// group is the hash, it must have a letter so a date like
+		// "report.20240101.csv" isn't taken as the hash.
+		// Optional. Default matches hex hash of 8, 10, 16 or 20 chars, ex. "app.3f2a9c1b.js".
+		Fingerprint *regexp.Regexp
+
+		// Assets resolves the hashed names of Assets.Path into the files.
+		// Optional.
+		Assets *Assets
+	}
+
+	// Assets is fingerprint of the static files, it's used by the templates
+	// to link the files using hashed names for cache busting.
+	Assets struct {
+		prefix string
+		hashes map[string]string // name -> hash
+		names  map[string]string // hashed name -> name
+	}
+)
+
+const immutable = "public, max-age=31536000, immutable"
+
+var (
+	// DefaultStaticConfig is the default Static middleware config.
+	DefaultStaticConfig = StaticConfig{
+		Skipper:     DefaultSkipper,
+		Index:       "index.html",
+		Fingerprint: regexp.MustCompile(`\.([0-9a-f]{8}|[0-9a-f]{10}|[0-9a-f]{16}|[0-9a-f]{20})\.\w+$`),
+	}
+)
+
+// Static returns a Static middleware serving files of the directory.
+func Static(root string) rest.MiddlewareFunc {
+	c := DefaultStaticConfig
+	c.Root = root
+	return StaticWithConfig(c)
+}
+
+// StaticFS returns a Static middleware serving files of the filesystem,
+// ex. frontend embedded into the binary:
+//
+//	//go:embed dist
+//	var dist embed.FS
+//
+//	sub, _ := fs.Sub(dist, "dist")
+//	e.Use(mw.StaticFS(sub))
+func StaticFS(fsys fs.FS) rest.MiddlewareFunc {
+	c := DefaultStaticConfig
+	c.Filesystem = fsys
+	return StaticWithConfig(c)
+}
+
+// StaticWithConfig returns a Static middleware with config.
+// See: `Static()`.
+func StaticWithConfig(config StaticConfig) rest.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultStaticConfig.Skipper
+	}
+	if config.Filesystem == nil {
+		if config.Root == "" {
+			panic("rest: static middleware requires root or filesystem")
+		}
+		config.Filesystem = os.DirFS(config.Root)
+	}
+	if config.Index == "" {
+		config.Index = DefaultStaticConfig.Index
+	}
+	if config.Fingerprint == nil {
+		config.Fingerprint = DefaultStaticConfig.Fingerprint
+	}
+	if config.Prefix == "" && config.Assets != nil {
+		config.Prefix = config.Assets.prefix
+	}
+	config.Prefix = strings.TrimRight(config.Prefix, "/")
+
+	return func(next rest.HandlerFunc) rest.HandlerFunc {
+		return func(c *rest.Context) error {
+			req := c.Request()
+			if config.Skipper(c) || (req.Method != http.MethodGet && req.Method != http.MethodHead) {
+				return next(c)
+			}
+
+			p := req.URL.Path
+			if !strings.HasPrefix(p, config.Prefix+"/") {
+				return next(c)
+			}
+			name := strings.TrimPrefix(path.Clean("/"+strings.TrimPrefix(p, config.Prefix)), "/")
+			if name == "" || strings.HasSuffix(p, "/") {
+				name = path.Join(name, config.Index)
+			}
+
+			if ok, err := config.serve(c, name); ok || err != nil {
+				return err
+			}
+
+			err := next(c)
+			if he, ok := err.(*rest.HTTPError); ok && he.Code == http.StatusNotFound && config.HTML5 {
+				if ok, err := config.serve(c, config.Index); ok || err != nil {
+					return err
+				}
+			}
+
+			return err
+		}
+	}
+}
+
+// serve sends the file, returns false when the file is not found.
+func (config *StaticConfig) serve(c *rest.Context, name string) (bool, error) {
+	cache := "no-cache"
+	if config.MaxAge > 0 {
+		cache = fmt.Sprintf("public, max-age=%d", config.MaxAge)
+	}
+
+	var etag string
+	if config.Assets != nil {
+		if orig, ok := config.Assets.names[name]; ok {
+			name, cache = orig, immutable
+		}
+		etag = config.Assets.hashes[name]
+	}
+	if fingerprinted(config.Fingerprint, path.Base(name)) {
+		cache = immutable
+	}
+
+	f, fi, err := open(config.Filesystem, name)
+	if err == nil && fi.IsDir() {
+		f.Close()
+		f, fi, err = open(config.Filesystem, path.Join(name, config.Index))
+	}
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrInvalid) {
+			return false, nil
+		}
+		return true, err
+	}
+	defer f.Close()
+
+	if fi.IsDir() {
+		return false, nil
+	}
+
+	rs, ok := f.(io.ReadSeeker)
+	if !ok {
+		return true, fmt.Errorf("rest: static file %s is not seekable", name)
+	}
+
+	h := c.Response().Header()
+	h.Set(rest.HeaderCacheControl, cache)
+	if etag != "" {
+		h.Set(rest.HeaderETag, `"`+etag+`"`)
+	}
+	http.ServeContent(c.Response(), c.Request(), fi.Name(), modTime(fi), rs)
+
+	return true, nil
+}
+
+func open(fsys fs.FS, name string) (fs.File, fs.FileInfo, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+
+	return f, fi, nil
+}
+
+// modTime returns zero time of embedded files, so Last-Modified is not sent.
+func modTime(fi fs.FileInfo) time.Time {
+	if t := fi.ModTime(); t.Unix() > 0 {
+		return t
+	}
+	return time.Time{}
+}
+
+// fingerprinted reports whether the name has the hash, see StaticConfig.Fingerprint.
+func fingerprinted(re *regexp.Regexp, name string) bool {
+	m := re.FindStringSubmatch(name)
+	if m == nil {
+		return false
+	}
+	if len(m) < 2 {
+		return true
+	}
+
+	return strings.IndexFunc(m[1], unicode.IsLetter) >= 0
+}
+
+// NewAssets hashes the files of the filesystem, prefix is the url path
+// where the files are served by Static middleware.
+func NewAssets(fsys fs.FS, prefix string) (*Assets, error) {
+	a := &Assets{
+		prefix: strings.TrimRight(prefix, "/"),
+		hashes: make(map[string]string),
+		names:  make(map[string]string),
+	}
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		f, err := fsys.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		sum := sha256.New()
+		if _, err = io.Copy(sum, f); err != nil {
+			return err
+		}
+
+		hash := hex.EncodeToString(sum.Sum(nil))[:10]
+		a.hashes[name] = hash
+		a.names[hashedName(name, hash)] = name
+
+		return nil
+	})
+
+	return a, err
+}
+
+// Path returns url of the file with hashed name, ex. "/assets/js/app.3f2a9c1b04.js",
+// unknown file is returned without hash.
+func (a *Assets) Path(name string) string {
+	name = strings.TrimPrefix(name, "/")
+	if hash, ok := a.hashes[name]; ok {
+		name = hashedName(name, hash)
+	}
+
+	return a.prefix + "/" + name
+}
+
+// FuncMap returns template functions, `{{ asset "js/app.js" }}`.
+func (a *Assets) FuncMap() template.FuncMap {
+	return template.FuncMap{"asset": a.Path}
+}
+
+func hashedName(name, hash string) string {
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + hash + ext
+}
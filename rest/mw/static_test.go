@@ -0,0 +1,129 @@
+package mw
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	"github.com/enigma-id/go/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticFingerprint(t *testing.T) {
+	re := DefaultStaticConfig.Fingerprint
+	for name, ok := range map[string]bool{
+		"app.3f2a9c1b.js":          true,
+		"app.3f2a9c1b04.js":        true,
+		"app.3f2a9c1b04d5e6f7.css": true,
+		"report.20240101.csv":      false,
+		"backup.2024010112.sql":    false,
+		"app.3f2a9c1.js":           false,
+		"app.3f2a9c1b0.js":         false,
+		"app.js":                   false,
+	} {
+		assert.Equal(t, ok, fingerprinted(re, name), name)
+	}
+
+	// without group the match is the fingerprint
+	assert.True(t, fingerprinted(regexp.MustCompile(`\.v\d+\.\w+$`), "app.v2.js"))
+}
+
+func TestStatic(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html":            {Data: []byte("<html>index</html>")},
+		"js/app.js":             {Data: []byte("console.log(1)")},
+		"js/vendor.0a1b2c3d.js": {Data: []byte("vendor")},
+		"report.20240101.csv":   {Data: []byte("id,total")},
+		"docs/index.html":       {Data: []byte("docs")},
+	}
+
+	e := rest.New()
+	e.Use(StaticWithConfig(StaticConfig{Filesystem: fsys, HTML5: true, MaxAge: 60}))
+	e.GET("/api/ping", func(c *rest.Context) error {
+		return c.String(http.StatusOK, "pong")
+	})
+
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	rec := get("/js/app.js")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "console.log(1)", rec.Body.String())
+	assert.Equal(t, "public, max-age=60", rec.Header().Get(rest.HeaderCacheControl))
+
+	rec = get("/js/vendor.0a1b2c3d.js")
+	assert.Equal(t, "vendor", rec.Body.String())
+	assert.Equal(t, "public, max-age=31536000, immutable", rec.Header().Get(rest.HeaderCacheControl))
+
+	// the date isn't hash
+	rec = get("/report.20240101.csv")
+	assert.Equal(t, "id,total", rec.Body.String())
+	assert.Equal(t, "public, max-age=60", rec.Header().Get(rest.HeaderCacheControl))
+
+	assert.Equal(t, "<html>index</html>", get("/").Body.String())
+	assert.Equal(t, "docs", get("/docs").Body.String())
+	assert.Equal(t, "docs", get("/docs/").Body.String())
+	assert.Equal(t, "pong", get("/api/ping").Body.String())
+
+	// html5 fallback
+	rec = get("/orders/10")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "<html>index</html>", rec.Body.String())
+	assert.Equal(t, "public, max-age=60", rec.Header().Get(rest.HeaderCacheControl))
+
+	// traversal
+	assert.Equal(t, "<html>index</html>", get("/../index.html").Body.String())
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/js/app.js", nil))
+	assert.NotEqual(t, "console.log(1)", rec.Body.String())
+}
+
+func TestStaticAssets(t *testing.T) {
+	fsys := fstest.MapFS{
+		"css/site.css": {Data: []byte("body{}")},
+	}
+
+	assets, err := NewAssets(fsys, "/assets/")
+	assert.NoError(t, err)
+
+	p := assets.Path("css/site.css")
+	assert.Regexp(t, `^/assets/css/site\.[0-9a-f]{10}\.css$`, p)
+	assert.Equal(t, "/assets/missing.js", assets.Path("/missing.js"))
+
+	var buf bytes.Buffer
+	tpl := template.Must(template.New("").Funcs(assets.FuncMap()).Parse(`<link href="{{ asset "css/site.css" }}">`))
+	assert.NoError(t, tpl.Execute(&buf, nil))
+	assert.Equal(t, `<link href="`+p+`">`, buf.String())
+
+	e := rest.New()
+	e.Use(StaticWithConfig(StaticConfig{Filesystem: fsys, Assets: assets}))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, p, nil))
+	assert.Equal(t, "body{}", rec.Body.String())
+	assert.Equal(t, "public, max-age=31536000, immutable", rec.Header().Get(rest.HeaderCacheControl))
+	etag := rec.Header().Get(rest.HeaderETag)
+	assert.NotEmpty(t, etag)
+
+	// original name is revalidated
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/assets/css/site.css", nil)
+	req.Header.Set(rest.HeaderIfNoneMatch, etag)
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Equal(t, "no-cache", rec.Header().Get(rest.HeaderCacheControl))
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/site.css", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	assert.Panics(t, func() { StaticWithConfig(StaticConfig{}) })
+}
@@ -0,0 +1,262 @@
+package mw
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/enigma-id/go/rest"
+)
+
+type (
+	// TransformConfig defines the config for Transform middleware.
+	TransformConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Request rewrites the request body before the handler reads it.
+		// Optional.
+		Request TransformFunc
+
+		// Response rewrites the response body before it's sent.
+		// Optional.
+		Response TransformFunc
+
+		// Limit is the max size of the body buffered for the transform, the larger
+		// request fails with 413 and the larger response is sent untransformed.
+		// Optional. Default value 1MB.
+		Limit int64
+
+		// ContentTypes of the bodies transformed, others are passed through.
+		// Optional. Default value application/json.
+		ContentTypes []string
+	}
+
+	// TransformFunc returns the rewritten body, the error fails the request.
+	TransformFunc func(c *rest.Context, body []byte) ([]byte, error)
+
+	// transformRecorder buffers the response up to the limit, then it
+	// passes the response through when it exceeds or isn't transformed.
+	transformRecorder struct {
+		w       http.ResponseWriter
+		config  *TransformConfig
+		code    int
+		body    bytes.Buffer
+		through bool
+	}
+)
+
+var (
+	// DefaultTransformConfig is the default Transform middleware config.
+	DefaultTransformConfig = TransformConfig{
+		Skipper:      DefaultSkipper,
+		Limit:        1 << 20,
+		ContentTypes: []string{rest.MIMEApplicationJSON},
+	}
+)
+
+// TransformRequest returns a middleware that rewrites the request body,
+// ex. renaming the fields sent by the legacy clients.
+//
+//	e.Use(mw.TransformRequest(mw.TransformJSON(func(c *rest.Context, v interface{}) (interface{}, error) {
+//		if m, ok := v.(map[string]interface{}); ok {
+//			m["phone_number"] = m["phone"]
+//			delete(m, "phone")
+//		}
+//		return v, nil
+//	})))
+func TransformRequest(fn TransformFunc) rest.MiddlewareFunc {
+	c := DefaultTransformConfig
+	c.Request = fn
+	return TransformWithConfig(c)
+}
+
+// TransformResponse returns a middleware that rewrites the response body,
+// ex. stripping the internal fields before the response leaves the service.
+// It should be registered after Compress, so it sees the plain body.
+func TransformResponse(fn TransformFunc) rest.MiddlewareFunc {
+	c := DefaultTransformConfig
+	c.Response = fn
+	return TransformWithConfig(c)
+}
+
+// TransformWithConfig returns a Transform middleware with config.
+func TransformWithConfig(config TransformConfig) rest.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultTransformConfig.Skipper
+	}
+	if config.Limit <= 0 {
+		config.Limit = DefaultTransformConfig.Limit
+	}
+	if len(config.ContentTypes) == 0 {
+		config.ContentTypes = DefaultTransformConfig.ContentTypes
+	}
+	if config.Request == nil && config.Response == nil {
+		panic("rest: transform middleware requires request or response func")
+	}
+
+	return func(next rest.HandlerFunc) rest.HandlerFunc {
+		return func(c *rest.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			if config.Request != nil {
+				if err := transformRequest(c, &config); err != nil {
+					return err
+				}
+			}
+			if config.Response == nil {
+				return next(c)
+			}
+
+			res := c.Response()
+			w := res.Writer
+			rec := &transformRecorder{w: w, config: &config}
+			res.Writer = rec
+			err := next(c)
+			res.Writer = w
+
+			if rec.through || rec.code == 0 {
+				return err
+			}
+
+			out, terr := config.Response(c, rec.body.Bytes())
+			if terr != nil {
+				// recorded response is dropped, let the error handler respond
+				res.Committed, res.Size = false, 0
+				return terr
+			}
+
+			if w.Header().Get(rest.HeaderContentLength) != "" {
+				w.Header().Set(rest.HeaderContentLength, strconv.Itoa(len(out)))
+			}
+			w.WriteHeader(rec.code)
+			w.Write(out)
+			res.Size = int64(len(out))
+
+			return err
+		}
+	}
+}
+
+// TransformJSON returns TransformFunc of the decoded json body, the numbers
+// are decoded as json.Number so they are kept as is.
+func TransformJSON(fn func(c *rest.Context, v interface{}) (interface{}, error)) TransformFunc {
+	return func(c *rest.Context, body []byte) ([]byte, error) {
+		if len(bytes.TrimSpace(body)) == 0 {
+			return body, nil
+		}
+
+		var v interface{}
+		d := json.NewDecoder(bytes.NewReader(body))
+		d.UseNumber()
+		if err := d.Decode(&v); err != nil {
+			return nil, rest.NewHTTPError(http.StatusBadRequest, "invalid json body").SetInternal(err)
+		}
+
+		v, err := fn(c, v)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(v)
+	}
+}
+
+func transformRequest(c *rest.Context, config *TransformConfig) error {
+	req := c.Request()
+	if req.Body == nil || req.ContentLength == 0 || !transformable(req.Header.Get(rest.HeaderContentType), config.ContentTypes) {
+		return nil
+	}
+	if req.ContentLength > config.Limit {
+		return rest.ErrStatusRequestEntityTooLarge
+	}
+
+	b, err := io.ReadAll(io.LimitReader(req.Body, config.Limit+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(b)) > config.Limit {
+		return rest.ErrStatusRequestEntityTooLarge
+	}
+
+	if b, err = config.Request(c, b); err != nil {
+		return err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(b))
+	req.ContentLength = int64(len(b))
+	req.Header.Set(rest.HeaderContentLength, strconv.Itoa(len(b)))
+
+	return nil
+}
+
+func (r *transformRecorder) Header() http.Header {
+	return r.w.Header()
+}
+
+func (r *transformRecorder) WriteHeader(code int) {
+	if r.code != 0 || r.through {
+		return
+	}
+
+	r.code = code
+	if code == http.StatusNoContent || code == http.StatusNotModified ||
+		!transformable(r.w.Header().Get(rest.HeaderContentType), r.config.ContentTypes) {
+		r.pass()
+	}
+}
+
+func (r *transformRecorder) Write(b []byte) (int, error) {
+	if r.code == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+	if r.through {
+		return r.w.Write(b)
+	}
+
+	if int64(r.body.Len()+len(b)) > r.config.Limit {
+		// too large to buffer, sent untransformed
+		if err := r.pass(); err != nil {
+			return 0, err
+		}
+		return r.w.Write(b)
+	}
+
+	return r.body.Write(b)
+}
+
+func (r *transformRecorder) Flush() {
+	if !r.through {
+		r.pass()
+	}
+	if f, ok := r.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// pass writes the buffered response and passes the rest through.
+func (r *transformRecorder) pass() error {
+	r.through = true
+	if r.code == 0 {
+		r.code = http.StatusOK
+	}
+	r.w.WriteHeader(r.code)
+	_, err := r.w.Write(r.body.Bytes())
+	r.body.Reset()
+
+	return err
+}
+
+func transformable(ct string, types []string) bool {
+	ct = strings.TrimSpace(strings.Split(ct, ";")[0])
+	for _, t := range types {
+		if strings.EqualFold(ct, t) {
+			return true
+		}
+	}
+	return false
+}
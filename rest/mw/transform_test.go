@@ -0,0 +1,107 @@
+package mw
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/enigma-id/go/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformRequest(t *testing.T) {
+	e := rest.New()
+	rename := TransformJSON(func(c *rest.Context, v interface{}) (interface{}, error) {
+		if m, ok := v.(map[string]interface{}); ok {
+			m["phone_number"] = m["phone"]
+			delete(m, "phone")
+		}
+		return v, nil
+	})
+	echo := func(c *rest.Context) error {
+		b, _ := io.ReadAll(c.Request().Body)
+		return c.String(http.StatusOK, string(b))
+	}
+
+	request := func(h rest.HandlerFunc, ct, body string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(rest.HeaderContentType, ct)
+		rec := httptest.NewRecorder()
+		return rec, h(e.NewContext(req, rec))
+	}
+
+	h := TransformRequest(rename)(echo)
+	rec, err := request(h, rest.MIMEApplicationJSON, `{"phone":"0812","amount":12.50}`)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"amount":12.50,"phone_number":"0812"}`, rec.Body.String())
+
+	// other content type is passed through
+	rec, err = request(h, rest.MIMETextPlain, `{"phone":"0812"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"phone":"0812"}`, rec.Body.String())
+
+	_, err = request(h, rest.MIMEApplicationJSON, `{"phone":`)
+	if assert.IsType(t, &rest.HTTPError{}, err) {
+		assert.Equal(t, http.StatusBadRequest, err.(*rest.HTTPError).Code)
+	}
+
+	h = TransformWithConfig(TransformConfig{Request: rename, Limit: 8})(echo)
+	_, err = request(h, rest.MIMEApplicationJSON, `{"phone":"0812"}`)
+	assert.Equal(t, rest.ErrStatusRequestEntityTooLarge, err)
+}
+
+func TestTransformResponse(t *testing.T) {
+	e := rest.New()
+	strip := TransformJSON(func(c *rest.Context, v interface{}) (interface{}, error) {
+		if m, ok := v.(map[string]interface{}); ok {
+			delete(m, "internal_note")
+		}
+		return v, nil
+	})
+
+	request := func(h rest.HandlerFunc) (*httptest.ResponseRecorder, *rest.Context, error) {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		return rec, c, h(c)
+	}
+
+	h := TransformResponse(strip)(func(c *rest.Context) error {
+		return c.JSON(http.StatusCreated, map[string]interface{}{"id": 1, "internal_note": "vip"})
+	})
+	rec, c, err := request(h)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, `{"id":1}`, rec.Body.String())
+	assert.Equal(t, int64(len(`{"id":1}`)), c.Response().Size)
+
+	// other content type is passed through
+	h = TransformResponse(strip)(func(c *rest.Context) error {
+		return c.String(http.StatusOK, `{"internal_note":"vip"}`)
+	})
+	rec, _, err = request(h)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"internal_note":"vip"}`, rec.Body.String())
+
+	// larger than the limit is sent untransformed
+	h = TransformWithConfig(TransformConfig{Response: strip, Limit: 8})(func(c *rest.Context) error {
+		return c.JSON(http.StatusOK, map[string]interface{}{"internal_note": "vip"})
+	})
+	rec, _, err = request(h)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"internal_note":"vip"}`, strings.TrimSpace(rec.Body.String()))
+
+	// failed transform lets the error handler respond
+	fail := errors.New("transform failed")
+	h = TransformResponse(func(*rest.Context, []byte) ([]byte, error) { return nil, fail })(func(c *rest.Context) error {
+		return c.JSON(http.StatusOK, map[string]interface{}{"id": 1})
+	})
+	rec, c, err = request(h)
+	assert.Equal(t, fail, err)
+	assert.False(t, c.Response().Committed)
+	assert.Empty(t, rec.Body.String())
+
+	assert.Panics(t, func() { TransformWithConfig(TransformConfig{}) })
+}
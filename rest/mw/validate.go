@@ -0,0 +1,88 @@
+package mw
+
+import (
+	"net/http"
+	"reflect"
+
+	"github.com/enigma-id/go/rest"
+	"github.com/enigma-id/go/validation"
+)
+
+type (
+	// ValidateBodyConfig defines the config for ValidateBody middleware.
+	ValidateBodyConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Request is the prototype of the request, a new instance of
+		// its type is created and bound on every request.
+		// Required.
+		Request interface{}
+	}
+)
+
+var (
+	// DefaultValidateBodyConfig is the default ValidateBody middleware config.
+	DefaultValidateBodyConfig = ValidateBodyConfig{
+		Skipper: DefaultSkipper,
+	}
+)
+
+// ValidateBody returns a middleware that binds and validates the request
+// before the handler runs, the validated request is kept on the context.
+//
+//	r.POST("/order", createOrder, mw.ValidateBody(&OrderCreateRequest{}))
+//
+//	func createOrder(c *rest.Context) error {
+//		req := c.Validated().(*OrderCreateRequest)
+//		...
+//	}
+func ValidateBody(i interface{}) rest.MiddlewareFunc {
+	c := DefaultValidateBodyConfig
+	c.Request = i
+	return ValidateBodyWithConfig(c)
+}
+
+// ValidateBodyWithConfig returns a ValidateBody middleware with config.
+func ValidateBodyWithConfig(config ValidateBodyConfig) rest.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultValidateBodyConfig.Skipper
+	}
+	if config.Request == nil {
+		panic("rest: validate body middleware requires request")
+	}
+
+	if err := validation.New(rest.ValidatorOptions...).Precompile(config.Request); err != nil {
+		panic("rest: validate body middleware " + err.Error())
+	}
+
+	typ := reflect.TypeOf(config.Request)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	return func(next rest.HandlerFunc) rest.HandlerFunc {
+		return func(c *rest.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			req := reflect.New(typ).Interface()
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			// binder only validates the query of delete request
+			if r := c.Request(); r.Method == http.MethodGet && r.ContentLength == 0 {
+				if err := c.Validate(req); err != nil {
+					return err
+				}
+			}
+
+			c.Set(rest.ValidatedKey, req)
+
+			return next(c)
+		}
+	}
+}
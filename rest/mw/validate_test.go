@@ -0,0 +1,69 @@
+package mw
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/enigma-id/go/rest"
+	"github.com/enigma-id/go/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+type orderCreateRequest struct {
+	Product string `json:"product" query:"product" valid:"required"`
+	Qty     int    `json:"qty" query:"qty" valid:"required|gte:1"`
+}
+
+func TestValidateBody(t *testing.T) {
+	e := rest.New()
+	h := ValidateBody(&orderCreateRequest{})(func(c *rest.Context) error {
+		r := c.Validated().(*orderCreateRequest)
+		return c.String(http.StatusOK, r.Product)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"product":"book","qty":2}`))
+	req.Header.Set(rest.HeaderContentType, rest.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	if assert.NoError(t, h(e.NewContext(req, rec))) {
+		assert.Equal(t, "book", rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"product":"book"}`))
+	req.Header.Set(rest.HeaderContentType, rest.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	err := h(e.NewContext(req, rec))
+	if assert.IsType(t, &validation.Response{}, err) {
+		assert.Contains(t, err.(*validation.Response).GetErrors(), "qty")
+	}
+	assert.Empty(t, rec.Body.String())
+
+	// each request has its own instances
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"product":"pen","qty":1}`))
+	req.Header.Set(rest.HeaderContentType, rest.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	assert.NoError(t, h(e.NewContext(req, rec)))
+	assert.Equal(t, "pen", rec.Body.String())
+}
+
+func TestValidateBodyQuery(t *testing.T) {
+	e := rest.New()
+	h := ValidateBody(orderCreateRequest{})(func(c *rest.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/?product=book&qty=1", nil)
+	assert.NoError(t, h(e.NewContext(req, httptest.NewRecorder())))
+
+	req = httptest.NewRequest(http.MethodGet, "/?product=book", nil)
+	assert.IsType(t, &validation.Response{}, h(e.NewContext(req, httptest.NewRecorder())))
+}
+
+func TestValidateBodyInvalidTag(t *testing.T) {
+	type request struct {
+		Code string `json:"code" valid:"match:^([A-Z]+$"`
+	}
+
+	assert.Panics(t, func() { ValidateBody(&request{}) })
+}
@@ -0,0 +1,88 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/enigma-id/go/rest/openapi"
+)
+
+// OpenAPISecurityScheme is name of the bearer security scheme
+// of the generated OpenAPI document.
+const OpenAPISecurityScheme = "bearerAuth"
+
+// OpenAPI generates OpenAPI document of the registered routes, the operations
+// are described by the route metadata (see Route.Summary) and the routes with
+// scopes require the bearer security scheme. Wildcard routes are not included.
+//
+//	e.GET("/openapi.json", func(c *rest.Context) error {
+//		return c.JSON(http.StatusOK, e.OpenAPI(openapi.Info{Title: "Orders", Version: "1.0"}))
+//	})
+func (e *Rest) OpenAPI(info openapi.Info) *openapi.Document {
+	doc := &openapi.Document{
+		OpenAPI: openapi.Version,
+		Info:    info,
+		Paths:   make(map[string]*openapi.PathItem),
+	}
+
+	for _, r := range e.Routes() {
+		if strings.Contains(r.Path, "*") {
+			continue
+		}
+
+		path, params := openAPIPath(r.Path)
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = new(openapi.PathItem)
+		}
+
+		ri := r.Info()
+		op := &openapi.Operation{
+			Summary:     ri.Summary,
+			Description: ri.Description,
+			Tags:        ri.Tags,
+			Parameters:  params,
+			Responses: map[string]*openapi.Response{
+				"default": {Description: http.StatusText(http.StatusOK)},
+			},
+		}
+		if len(ri.Scopes) > 0 {
+			op.Security = []openapi.Requirement{{OpenAPISecurityScheme: ri.Scopes}}
+			doc.Components = &openapi.Components{
+				SecuritySchemes: map[string]*openapi.SecurityScheme{
+					OpenAPISecurityScheme: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+				},
+			}
+		}
+
+		if item.SetOperation(r.Method, op); item.Operation(r.Method) != nil {
+			doc.Paths[path] = item
+		}
+	}
+
+	return doc
+}
+
+// openAPIPath converts path of the route into OpenAPI path template,
+// ex. /orders/:id to /orders/{id} with the path parameters.
+func openAPIPath(path string) (string, []*openapi.Parameter) {
+	var params []*openapi.Parameter
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") {
+			segments[i] = "{" + s[1:] + "}"
+			params = append(params, &openapi.Parameter{
+				Name:     s[1:],
+				In:       "path",
+				Required: true,
+				Schema:   &openapi.Schema{Type: "string"},
+			})
+		}
+	}
+
+	return strings.Join(segments, "/"), params
+}
@@ -0,0 +1,287 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package openapi
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/enigma-id/go/validation"
+)
+
+// formats of the rules, the value is the format of the string schema.
+var formats = map[string]string{
+	"email":    "email",
+	"url":      "uri",
+	"uuid":     "uuid",
+	"uuid4":    "uuid",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+	"hostname": "hostname",
+	"base64":   "byte",
+}
+
+// patterns of the rules without format.
+var patterns = map[string]string{
+	"alpha":     `^[a-zA-Z]+$`,
+	"alpha_num": `^[a-zA-Z0-9]+$`,
+	"hex":       `^(0[xX])?[0-9a-fA-F]+$`,
+	"lowercase": `^[^A-Z]*$`,
+	"uppercase": `^[^a-z]*$`,
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Constrain sets the constraints of the validation rules on the schema, the
+// type of the schema must be set since gte is minLength of the string and
+// minimum of the number. It returns true when the rules have required rule.
+// The conditional rules and the rules of scenario are not constraints.
+//
+//	rules, _ := v.Rules("required|gte:3|lte:64")
+//	s := &openapi.Schema{Type: "string"}
+//	required := openapi.Constrain(s, rules) // minLength 3, maxLength 64
+func Constrain(s *Schema, rules []validation.Rule) (required bool) {
+	for _, r := range rules {
+		if len(r.On) > 0 {
+			continue
+		}
+
+		switch r.Name {
+		case "required":
+			required = true
+		case "gte":
+			s.min(r.Param, false)
+		case "gt":
+			s.min(r.Param, true)
+		case "lte":
+			s.max(r.Param, false)
+		case "lt":
+			s.max(r.Param, true)
+		case "range":
+			if p := strings.Split(r.Param, ","); len(p) == 2 {
+				s.min(p[0], false)
+				s.max(p[1], false)
+			}
+		case "in":
+			if !strings.HasPrefix(r.Param, "@") {
+				s.Enum = s.enum(strings.Split(r.Param, ","))
+			}
+		case "match":
+			s.Pattern = r.Param
+		case "multiple_of", "divisible_by":
+			if n, err := strconv.ParseFloat(r.Param, 64); err == nil {
+				s.MultipleOf = &n
+			}
+		case "decimal":
+			p := strings.SplitN(r.Param, ",", 2)
+			if scale, err := strconv.Atoi(strings.TrimSpace(p[len(p)-1])); len(p) == 2 && err == nil {
+				n := math.Pow10(-scale)
+				s.MultipleOf = &n
+			}
+		case "max_items":
+			if n, err := strconv.Atoi(r.Param); err == nil {
+				s.MaxItems = &n
+			}
+		case "max_fields":
+			if n, err := strconv.Atoi(r.Param); err == nil {
+				s.MaxProperties = &n
+			}
+		case "each":
+			if s.Items != nil {
+				p := strings.SplitN(r.Param, ":", 2)
+				each := validation.Rule{Name: p[0]}
+				if len(p) == 2 {
+					each.Param = p[1]
+				}
+				Constrain(s.Items, []validation.Rule{each})
+			}
+		default:
+			if f, ok := formats[r.Name]; ok && s.Type == "string" {
+				s.Format = f
+			} else if p, ok := patterns[r.Name]; ok && s.Type == "string" && s.Pattern == "" {
+				s.Pattern = p
+			}
+		}
+	}
+
+	return
+}
+
+// SchemaOf returns the schema of the value with the constraints of its
+// validation rules, the properties are named as encoding/json does.
+// Validator with the registered rules can be passed, nil is the default one.
+//
+//	schema := openapi.SchemaOf(nil, CreateOrderRequest{})
+func SchemaOf(v *validation.Validator, value interface{}) *Schema {
+	if v == nil {
+		v = validation.New()
+	}
+	return schemaOf(v, reflect.TypeOf(value), make(map[reflect.Type]bool))
+}
+
+func schemaOf(v *validation.Validator, t reflect.Type, seen map[reflect.Type]bool) *Schema {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: schemaOf(v, t.Elem(), seen)}
+	case reflect.Map:
+		return &Schema{Type: "object"}
+	case reflect.Struct:
+		if t == timeType {
+			return &Schema{Type: "string", Format: "date-time"}
+		}
+		if seen[t] {
+			// recursive type is not expanded
+			return &Schema{Type: "object"}
+		}
+
+		seen[t] = true
+		s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		properties(v, t, s, seen)
+		delete(seen, t)
+
+		return s
+	}
+
+	return &Schema{}
+}
+
+// properties adds the fields of the struct into the schema,
+// the embedded struct without json name is flattened.
+func properties(v *validation.Validator, t reflect.Type, s *Schema, seen map[reflect.Type]bool) {
+	rules := make(map[string][]validation.Rule)
+	for _, f := range v.Describe(reflect.New(t).Elem().Interface()) {
+		rules[f.Field] = f.Rules
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, omit := jsonName(f)
+		if omit {
+			continue
+		}
+
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			properties(v, ft, s, seen)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		p := schemaOf(v, f.Type, seen)
+		if Constrain(p, rules[f.Name]) {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = p
+	}
+}
+
+// jsonName returns the name of the json tag, omit is true for "-".
+func jsonName(f reflect.StructField) (name string, omit bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	return strings.Split(tag, ",")[0], false
+}
+
+// min sets the lower bound by the type of the schema.
+func (s *Schema) min(param string, exclusive bool) {
+	n, err := strconv.ParseFloat(strings.TrimSpace(param), 64)
+	if err != nil {
+		return
+	}
+
+	switch s.Type {
+	case "string", "array":
+		l := int(n)
+		if exclusive {
+			l++
+		}
+		if s.Type == "string" {
+			s.MinLength = &l
+		} else {
+			s.MinItems = &l
+		}
+	case "integer", "number":
+		if exclusive {
+			s.ExclusiveMinimum = &n
+		} else {
+			s.Minimum = &n
+		}
+	}
+}
+
+// max sets the upper bound by the type of the schema.
+func (s *Schema) max(param string, exclusive bool) {
+	n, err := strconv.ParseFloat(strings.TrimSpace(param), 64)
+	if err != nil {
+		return
+	}
+
+	switch s.Type {
+	case "string", "array":
+		l := int(n)
+		if exclusive {
+			l--
+		}
+		if s.Type == "string" {
+			s.MaxLength = &l
+		} else {
+			s.MaxItems = &l
+		}
+	case "integer", "number":
+		if exclusive {
+			s.ExclusiveMaximum = &n
+		} else {
+			s.Maximum = &n
+		}
+	}
+}
+
+// enum returns the values typed by the schema.
+func (s *Schema) enum(values []string) []interface{} {
+	enum := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if s.Type == "integer" || s.Type == "number" {
+			if n, err := strconv.ParseFloat(v, 64); err == nil {
+				enum = append(enum, n)
+				continue
+			}
+		}
+		enum = append(enum, v)
+	}
+	return enum
+}
@@ -0,0 +1,81 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/enigma-id/go/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaOf(t *testing.T) {
+	type Audit struct {
+		CreatedBy string `json:"created_by" valid:"required"`
+	}
+	type item struct {
+		SKU string `json:"sku" valid:"required|alpha_num|lte:16"`
+		Qty int    `json:"qty" valid:"range:1,100|multiple_of:5"`
+	}
+	type order struct {
+		Audit
+		Email    string            `json:"email" valid:"required|email"`
+		Status   string            `json:"status,omitempty" valid:"in:draft,paid"`
+		Priority int               `json:"priority" valid:"in:1,2,3"`
+		Amount   float64           `json:"amount" valid:"gt:0|lt:1000|decimal:8,2"`
+		Note     *string           `json:"note" valid:"gte:3;on=create"`
+		Items    []item            `json:"items" valid:"required|max_items:10"`
+		Tags     []string          `json:"tags" valid:"gte:1|each:lte:8"`
+		Meta     map[string]string `json:"meta" valid:"max_fields:5"`
+		Paid     time.Time         `json:"paid_at"`
+		Internal string            `json:"-" valid:"required"`
+		Code     string            `valid:"match:^[A-Z]{3}$"`
+	}
+
+	s := SchemaOf(nil, &order{})
+	b, err := json.Marshal(s)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"required": ["created_by", "email", "items"],
+		"properties": {
+			"created_by": {"type": "string"},
+			"email": {"type": "string", "format": "email"},
+			"status": {"type": "string", "enum": ["draft", "paid"]},
+			"priority": {"type": "integer", "enum": [1, 2, 3]},
+			"amount": {"type": "number", "exclusiveMinimum": 0, "exclusiveMaximum": 1000, "multipleOf": 0.01},
+			"note": {"type": "string"},
+			"items": {"type": "array", "maxItems": 10, "items": {
+				"type": "object",
+				"required": ["sku"],
+				"properties": {
+					"sku": {"type": "string", "pattern": "^[a-zA-Z0-9]+$", "maxLength": 16},
+					"qty": {"type": "integer", "minimum": 1, "maximum": 100, "multipleOf": 5}
+				}
+			}},
+			"tags": {"type": "array", "minItems": 1, "items": {"type": "string", "maxLength": 8}},
+			"meta": {"type": "object", "maxProperties": 5},
+			"paid_at": {"type": "string", "format": "date-time"},
+			"Code": {"type": "string", "pattern": "^[A-Z]{3}$"}
+		}
+	}`, string(b))
+}
+
+func TestConstrain(t *testing.T) {
+	v := validation.New()
+	rules, err := v.Rules("required|gt:2|lt:10")
+	assert.NoError(t, err)
+
+	s := &Schema{Type: "string"}
+	assert.True(t, Constrain(s, rules))
+	assert.Equal(t, 3, *s.MinLength)
+	assert.Equal(t, 9, *s.MaxLength)
+
+	s = &Schema{Type: "string"}
+	assert.False(t, Constrain(s, []validation.Rule{{Name: "in", Param: "@currencies"}, {Name: "uuid4"}}))
+	assert.Nil(t, s.Enum)
+	assert.Equal(t, "uuid", s.Format)
+
+	_, err = v.Rules("required|unknown_rule")
+	assert.Error(t, err)
+}
@@ -0,0 +1,152 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package openapi is the model of OpenAPI 3 document,
+// generated from the routes by rest.OpenAPI.
+package openapi
+
+// Version of the OpenAPI specification.
+const Version = "3.1.0"
+
+type (
+	// Document is the root of OpenAPI document.
+	Document struct {
+		OpenAPI    string               `json:"openapi" yaml:"openapi"`
+		Info       Info                 `json:"info" yaml:"info"`
+		Paths      map[string]*PathItem `json:"paths" yaml:"paths"`
+		Components *Components          `json:"components,omitempty" yaml:"components,omitempty"`
+		Security   []Requirement        `json:"security,omitempty" yaml:"security,omitempty"`
+	}
+
+	// Info is the metadata of the API.
+	Info struct {
+		Title       string `json:"title" yaml:"title"`
+		Version     string `json:"version" yaml:"version"`
+		Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	}
+
+	// PathItem holds operations of the path.
+	PathItem struct {
+		Get     *Operation `json:"get,omitempty" yaml:"get,omitempty"`
+		Put     *Operation `json:"put,omitempty" yaml:"put,omitempty"`
+		Post    *Operation `json:"post,omitempty" yaml:"post,omitempty"`
+		Delete  *Operation `json:"delete,omitempty" yaml:"delete,omitempty"`
+		Options *Operation `json:"options,omitempty" yaml:"options,omitempty"`
+		Head    *Operation `json:"head,omitempty" yaml:"head,omitempty"`
+		Patch   *Operation `json:"patch,omitempty" yaml:"patch,omitempty"`
+		Trace   *Operation `json:"trace,omitempty" yaml:"trace,omitempty"`
+	}
+
+	// Operation is single API operation on a path.
+	Operation struct {
+		OperationID string               `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+		Summary     string               `json:"summary,omitempty" yaml:"summary,omitempty"`
+		Description string               `json:"description,omitempty" yaml:"description,omitempty"`
+		Tags        []string             `json:"tags,omitempty" yaml:"tags,omitempty"`
+		Parameters  []*Parameter         `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+		RequestBody *RequestBody         `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
+		Responses   map[string]*Response `json:"responses" yaml:"responses"`
+		Security    []Requirement        `json:"security,omitempty" yaml:"security,omitempty"`
+	}
+
+	// Parameter of the operation, In is "path", "query", "header" or "cookie".
+	Parameter struct {
+		Name        string  `json:"name" yaml:"name"`
+		In          string  `json:"in" yaml:"in"`
+		Description string  `json:"description,omitempty" yaml:"description,omitempty"`
+		Required    bool    `json:"required,omitempty" yaml:"required,omitempty"`
+		Schema      *Schema `json:"schema,omitempty" yaml:"schema,omitempty"`
+	}
+
+	// RequestBody of the operation.
+	RequestBody struct {
+		Description string                `json:"description,omitempty" yaml:"description,omitempty"`
+		Required    bool                  `json:"required,omitempty" yaml:"required,omitempty"`
+		Content     map[string]*MediaType `json:"content" yaml:"content"`
+	}
+
+	// Response of the operation.
+	Response struct {
+		Description string                `json:"description" yaml:"description"`
+		Content     map[string]*MediaType `json:"content,omitempty" yaml:"content,omitempty"`
+	}
+
+	// MediaType holds schema and example of the content.
+	MediaType struct {
+		Schema  *Schema     `json:"schema,omitempty" yaml:"schema,omitempty"`
+		Example interface{} `json:"example,omitempty" yaml:"example,omitempty"`
+	}
+
+	// Schema is the subset of JSON schema used by the generator.
+	Schema struct {
+		Ref        string             `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+		Type       string             `json:"type,omitempty" yaml:"type,omitempty"`
+		Format     string             `json:"format,omitempty" yaml:"format,omitempty"`
+		Properties map[string]*Schema `json:"properties,omitempty" yaml:"properties,omitempty"`
+		Items      *Schema            `json:"items,omitempty" yaml:"items,omitempty"`
+		Required   []string           `json:"required,omitempty" yaml:"required,omitempty"`
+		Enum       []interface{}      `json:"enum,omitempty" yaml:"enum,omitempty"`
+		Example    interface{}        `json:"example,omitempty" yaml:"example,omitempty"`
+
+		// constraints, see Constrain
+		MinLength        *int     `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+		MaxLength        *int     `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+		Pattern          string   `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+		Minimum          *float64 `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+		Maximum          *float64 `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+		ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
+		ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
+		MultipleOf       *float64 `json:"multipleOf,omitempty" yaml:"multipleOf,omitempty"`
+		MinItems         *int     `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+		MaxItems         *int     `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+		MaxProperties    *int     `json:"maxProperties,omitempty" yaml:"maxProperties,omitempty"`
+	}
+
+	// Components holds the reusable objects of the document.
+	Components struct {
+		Schemas         map[string]*Schema         `json:"schemas,omitempty" yaml:"schemas,omitempty"`
+		SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty" yaml:"securitySchemes,omitempty"`
+	}
+
+	// SecurityScheme defines the authentication of the API.
+	SecurityScheme struct {
+		Type         string `json:"type" yaml:"type"`
+		Description  string `json:"description,omitempty" yaml:"description,omitempty"`
+		Name         string `json:"name,omitempty" yaml:"name,omitempty"`
+		In           string `json:"in,omitempty" yaml:"in,omitempty"`
+		Scheme       string `json:"scheme,omitempty" yaml:"scheme,omitempty"`
+		BearerFormat string `json:"bearerFormat,omitempty" yaml:"bearerFormat,omitempty"`
+	}
+
+	// Requirement maps name of the security scheme to the required scopes.
+	Requirement map[string][]string
+)
+
+// Operation returns operation of the method, nil when not defined.
+func (p *PathItem) Operation(method string) *Operation {
+	if op := p.operations()[method]; op != nil {
+		return *op
+	}
+	return nil
+}
+
+// SetOperation sets operation of the method, unknown method is ignored.
+func (p *PathItem) SetOperation(method string, op *Operation) {
+	if o := p.operations()[method]; o != nil {
+		*o = op
+	}
+}
+
+func (p *PathItem) operations() map[string]**Operation {
+	return map[string]**Operation{
+		"GET":     &p.Get,
+		"PUT":     &p.Put,
+		"POST":    &p.Post,
+		"DELETE":  &p.Delete,
+		"OPTIONS": &p.Options,
+		"HEAD":    &p.Head,
+		"PATCH":   &p.Patch,
+		"TRACE":   &p.Trace,
+	}
+}
@@ -0,0 +1,54 @@
+package rest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/enigma-id/go/rest/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPI(t *testing.T) {
+	e := New()
+	h := func(c *Context) error { return nil }
+	e.GET("/orders", h).Summary("List orders").Tags("orders").Scopes("orders:read")
+	e.POST("/orders", h).Scopes("orders:write")
+	e.GET("/orders/:id/items/:item", h).Description("Item of the order")
+	e.GET("/health", h)
+	e.GET("/static/*", h)
+
+	doc := e.OpenAPI(openapi.Info{Title: "Orders", Version: "1.0"})
+	assert.Equal(t, openapi.Version, doc.OpenAPI)
+	assert.Equal(t, "Orders", doc.Info.Title)
+	assert.Len(t, doc.Paths, 3)
+
+	list := doc.Paths["/orders"].Get
+	if assert.NotNil(t, list) {
+		assert.Equal(t, "List orders", list.Summary)
+		assert.Equal(t, []string{"orders"}, list.Tags)
+		assert.Equal(t, []openapi.Requirement{{OpenAPISecurityScheme: {"orders:read"}}}, list.Security)
+	}
+	assert.Equal(t, []string{"orders:write"}, doc.Paths["/orders"].Post.Security[0][OpenAPISecurityScheme])
+
+	item := doc.Paths["/orders/{id}/items/{item}"].Get
+	if assert.NotNil(t, item) && assert.Len(t, item.Parameters, 2) {
+		assert.Equal(t, "item", item.Parameters[1].Name)
+		assert.Equal(t, "path", item.Parameters[1].In)
+		assert.True(t, item.Parameters[1].Required)
+		assert.Empty(t, item.Security)
+	}
+
+	if assert.NotNil(t, doc.Components) {
+		scheme := doc.Components.SecuritySchemes[OpenAPISecurityScheme]
+		assert.Equal(t, "bearer", scheme.Scheme)
+	}
+
+	b, err := json.Marshal(doc)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"securitySchemes":{"bearerAuth":{"type":"http","scheme":"bearer","bearerFormat":"JWT"}}`)
+
+	// no security schemes without scoped routes
+	e = New()
+	e.GET("/health", h)
+	assert.Nil(t, e.OpenAPI(openapi.Info{}).Components)
+}
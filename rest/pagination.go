@@ -0,0 +1,73 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"net/url"
+	"strconv"
+)
+
+var (
+	// DefaultPerPage number of items per page when limit is not requested.
+	DefaultPerPage = 25
+	// MaxPerPage maximum number of items per page can be requested.
+	MaxPerPage = 100
+)
+
+// Pagination holds the requested page, read from `page` and `limit`
+// (or `per_page`) query params, it can be passed into query builder.
+type Pagination struct {
+	Page    int   `json:"page"`
+	PerPage int   `json:"per_page"`
+	Total   int64 `json:"total"`
+}
+
+// NewPagination creates pagination from query params.
+func NewPagination(params url.Values) *Pagination {
+	p := &Pagination{Page: 1, PerPage: DefaultPerPage}
+
+	if n, err := strconv.Atoi(params.Get("page")); err == nil && n > 0 {
+		p.Page = n
+	}
+
+	l := params.Get("limit")
+	if l == "" {
+		l = params.Get("per_page")
+	}
+
+	if n, err := strconv.Atoi(l); err == nil && n > 0 {
+		p.PerPage = n
+	}
+
+	if p.PerPage > MaxPerPage {
+		p.PerPage = MaxPerPage
+	}
+
+	return p
+}
+
+// Pagination returns pagination of the request query params.
+func (c *Context) Pagination() *Pagination {
+	return NewPagination(c.QueryParams())
+}
+
+// Limit returns number of items per page.
+func (p *Pagination) Limit() int {
+	return p.PerPage
+}
+
+// Offset returns number of items skipped before the page.
+func (p *Pagination) Offset() int {
+	return (p.Page - 1) * p.PerPage
+}
+
+// TotalPages returns number of pages based on the total items.
+func (p *Pagination) TotalPages() int {
+	if p.PerPage <= 0 {
+		return 0
+	}
+
+	return int((p.Total + int64(p.PerPage) - 1) / int64(p.PerPage))
+}
@@ -0,0 +1,31 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPagination(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?page=3&limit=10", nil)
+	c := New().NewContext(req, httptest.NewRecorder())
+
+	p := c.Pagination()
+	assert.Equal(t, 10, p.Limit())
+	assert.Equal(t, 20, p.Offset())
+
+	p.Total = 21
+	assert.Equal(t, 3, p.TotalPages())
+
+	p = NewPagination(url.Values{"page": {"-1"}, "per_page": {"1000"}})
+	assert.Equal(t, 1, p.Page)
+	assert.Equal(t, MaxPerPage, p.Limit())
+	assert.Equal(t, 0, p.Offset())
+}
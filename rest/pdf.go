@@ -0,0 +1,27 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"fmt"
+
+	"github.com/enigma-id/go/pdf"
+)
+
+// PDF sends the pdf document with status code, the document
+// is shown inline by the browser using filename of the document.
+func (c *Context) PDF(code int, doc *pdf.Document) error {
+	if err := doc.Err(); err != nil {
+		return err
+	}
+
+	c.writeContentType("application/pdf")
+	if doc.Filename != "" {
+		c.response.Header().Set("Content-Disposition", fmt.Sprintf("inline;filename=%s", doc.Filename))
+	}
+	c.response.WriteHeader(code)
+
+	return doc.Output(c.response)
+}
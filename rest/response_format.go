@@ -18,6 +18,7 @@ type ResponseFormat struct {
 	Data    interface{}       `json:"data,omitempty"`
 	Total   int64             `json:"total,omitempty"`
 	Errors  map[string]string `json:"errors,omitempty"`
+	Codes   map[string]string `json:"codes,omitempty"`
 }
 
 // SetError set an error into response formater.
@@ -32,11 +33,16 @@ func (r *ResponseFormat) SetError(err error) {
 		// Error cause of http failure should return status as is the errors
 		// using standart http code.
 		r.Code = he.Code
+		if o := validationErrors(err); o != nil {
+			r.Errors = o.GetErrors()
+			r.Codes = o.GetCodes()
+		}
 	} else if o, ok := err.(*validation.Response); ok {
 		// Error cause of validation failure should return
 		// status 422 and returning all failure messages as errors.
 		r.Code = http.StatusUnprocessableEntity
 		r.Errors = o.GetErrors()
+		r.Codes = o.GetCodes()
 	}
 
 	r.Message = http.StatusText(r.Code)
@@ -46,6 +52,7 @@ func (r *ResponseFormat) SetError(err error) {
 func (r *ResponseFormat) reset() {
 	r.Data = nil
 	r.Errors = nil
+	r.Codes = nil
 	r.Message = nil
 	r.Total = 0
 }
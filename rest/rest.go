@@ -10,13 +10,17 @@ import (
 	"os"
 	"reflect"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
 	stdContext "context"
 	stdLog "log"
 
+	"github.com/enigma-id/go/i18n"
+	"github.com/enigma-id/go/trace"
 	"github.com/enigma-id/go/utility/log"
+	"github.com/enigma-id/go/validation"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/acme/autocert"
 )
@@ -38,8 +42,15 @@ type (
 		AutoTLSManager   autocert.Manager
 		HTTPErrorHandler HTTPErrorHandler
 		Binder           Binder
+		Envelope         EnvelopeFunc
+		Jobs             *Jobs
 		Logger           *zap.Logger
 		Config           *config
+		startHooks       []*startHook
+		startOnce        sync.Once
+		startErr         error
+		shutdown         chan struct{}
+		shutdownOnce     sync.Once
 	}
 
 	// Route contains a handler and information for matching against requests.
@@ -47,6 +58,9 @@ type (
 		Method string `json:"method"`
 		Path   string `json:"path"`
 		Name   string `json:"name"`
+
+		info    RouteInfo
+		timeout time.Duration
 	}
 
 	// HTTPError represents an error that occurred while handling a request.
@@ -89,6 +103,12 @@ type (
 		Validate(i interface{}) error
 	}
 
+	// MetaValidator is validator that receives the metadata of the request,
+	// used by conditional rules like required_on.
+	MetaValidator interface {
+		ValidateMeta(i interface{}, m validation.Meta) error
+	}
+
 	// i is the interface for Rest and Group.
 	i interface {
 		GET(string, HandlerFunc, ...MiddlewareFunc) *Route
@@ -102,9 +122,12 @@ const (
 	MIMEApplicationJavaScript            = "application/javascript"
 	MIMEApplicationJavaScriptCharsetUTF8 = MIMEApplicationJavaScript + "; charset=UTF-8"
 	MIMEApplicationProtobuf              = "application/protobuf"
+	MIMEApplicationXProtobuf             = "application/x-protobuf"
 	MIMEApplicationMsgpack               = "application/msgpack"
+	MIMEApplicationXMsgpack              = "application/x-msgpack"
 	MIMETextPlain                        = "text/plain"
 	MIMETextPlainCharsetUTF8             = MIMETextPlain + "; charset=UTF-8"
+	MIMETextCSV                          = "text/csv"
 	MIMEOctetStream                      = "application/octet-stream"
 )
 
@@ -112,16 +135,25 @@ const (
 const (
 	HeaderAccept              = "Accept"
 	HeaderAcceptEncoding      = "Accept-Encoding"
+	HeaderAcceptLanguage      = "Accept-Language"
+	HeaderAcceptRanges        = "Accept-Ranges"
 	HeaderAllow               = "Allow"
 	HeaderAuthorization       = "Authorization"
 	HeaderContentDisposition  = "Content-Disposition"
 	HeaderContentEncoding     = "Content-Encoding"
+	HeaderContentLanguage     = "Content-Language"
 	HeaderContentLength       = "Content-Length"
+	HeaderContentRange        = "Content-Range"
 	HeaderContentType         = "Content-Type"
 	HeaderCookie              = "Cookie"
 	HeaderSetCookie           = "Set-Cookie"
 	HeaderIfModifiedSince     = "If-Modified-Since"
+	HeaderIfNoneMatch         = "If-None-Match"
+	HeaderIfRange             = "If-Range"
+	HeaderRange               = "Range"
 	HeaderLastModified        = "Last-Modified"
+	HeaderETag                = "ETag"
+	HeaderCacheControl        = "Cache-Control"
 	HeaderLocation            = "Location"
 	HeaderUpgrade             = "Upgrade"
 	HeaderVary                = "Vary"
@@ -135,6 +167,7 @@ const (
 	HeaderXRealIP             = "X-Real-IP"
 	HeaderXRequestID          = "X-Request-ID"
 	HeaderXRequestedWith      = "X-Requested-With"
+	HeaderXDebugTrace         = "X-Debug-Trace"
 	HeaderServer              = "Server"
 	HeaderOrigin              = "Origin"
 
@@ -155,8 +188,20 @@ const (
 	HeaderXFrameOptions           = "X-Frame-Options"
 	HeaderContentSecurityPolicy   = "Content-Security-Policy"
 	HeaderXCSRFToken              = "X-CSRF-Token"
+
+	// Deprecation
+	HeaderDeprecation = "Deprecation"
+	HeaderSunset      = "Sunset"
+	HeaderLink        = "Link"
 )
 
+// ValidatedKey is the context key of the request validated by mw.ValidateBody.
+const ValidatedKey = "validated"
+
+// ScenarioKey is the context key of the validation scenario, the rules
+// having `;on=` option are applied on the scenario, method or route.
+const ScenarioKey = "scenario"
+
 var (
 	methods = [...]string{
 		http.MethodConnect,
@@ -188,6 +233,7 @@ var (
 	ErrInternalServerError         = NewHTTPError(http.StatusInternalServerError)
 	ErrRequestTimeout              = NewHTTPError(http.StatusRequestTimeout)
 	ErrServiceUnavailable          = NewHTTPError(http.StatusServiceUnavailable)
+	ErrRangeNotSatisfiable         = NewHTTPError(http.StatusRequestedRangeNotSatisfiable)
 	ErrValidatorNotRegistered      = errors.New("validator not registered")
 	ErrInvalidRedirectCode         = errors.New("invalid redirect status code")
 	ErrCookieNotFound              = errors.New("cookie not found")
@@ -226,10 +272,14 @@ func New() (e *Rest) {
 		StdLogger: stdLog.New(os.Stderr, "", 0),
 		Logger:    Logger,
 		Config:    Config,
+		shutdown:  make(chan struct{}),
 	}
 	e.Server.Handler = e
 	e.TLSServer.Handler = e
+	e.Server.RegisterOnShutdown(e.drain)
+	e.TLSServer.RegisterOnShutdown(e.drain)
 	e.HTTPErrorHandler = e.DefaultHTTPErrorHandler
+	e.Envelope = DefaultEnvelope
 	e.router = NewRouter(e)
 	e.pool.New = func() interface{} {
 		return e.NewContext(nil, nil)
@@ -261,13 +311,15 @@ func (e *Rest) Router() *Router {
 // with status code.
 func (e *Rest) DefaultHTTPErrorHandler(err error, c *Context) {
 	var (
-		code = http.StatusInternalServerError
-		msg  interface{}
+		code    = http.StatusInternalServerError
+		msg     interface{}
+		invalid *validation.Response
 	)
 
 	if he, ok := err.(*HTTPError); ok {
 		code = he.Code
 		msg = he.Message
+		invalid = validationErrors(he)
 		if he.Internal != nil {
 			err = fmt.Errorf("%v, %v", err, he.Internal)
 		}
@@ -276,8 +328,14 @@ func (e *Rest) DefaultHTTPErrorHandler(err error, c *Context) {
 	} else {
 		msg = http.StatusText(code)
 	}
-	if _, ok := msg.(string); ok {
-		msg = Map{"message": msg}
+	if m, ok := msg.(string); ok {
+		msg = Map{"message": i18n.T(c.Request().Context(), m)}
+		if invalid != nil {
+			msg.(Map)["errors"] = c.translateErrors(invalid)
+			if codes := invalid.GetCodes(); len(codes) > 0 {
+				msg.(Map)["codes"] = codes
+			}
+		}
 	}
 
 	// Send response
@@ -381,19 +439,22 @@ func (e *Rest) Match(methods []string, path string, handler HandlerFunc, middlew
 // in the router with optional route-level middleware.
 func (e *Rest) Add(method, path string, handler HandlerFunc, middleware ...MiddlewareFunc) *Route {
 	name := handlerName(handler)
+	r := &Route{
+		Method: method,
+		Path:   path,
+		Name:   name,
+	}
 	e.router.Add(method, path, func(c *Context) error {
 		h := handler
 		// Chain middleware
 		for i := len(middleware) - 1; i >= 0; i-- {
 			h = middleware[i](h)
 		}
+		if r.timeout > 0 {
+			return withTimeout(c, r.timeout, h)
+		}
 		return h(c)
 	})
-	r := &Route{
-		Method: method,
-		Path:   path,
-		Name:   name,
-	}
 	e.router.routes[method+path] = r
 	return r
 }
@@ -471,18 +532,11 @@ func (e *Rest) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	if e.premiddleware == nil {
 		e.router.Find(r.Method, getPath(r), c)
-		h = c.Handler()
-		for i := len(e.middleware) - 1; i >= 0; i-- {
-			h = e.middleware[i](h)
-		}
+		h = e.chain(c)
 	} else {
 		h = func(c *Context) error {
 			e.router.Find(r.Method, getPath(r), c)
-			h := c.Handler()
-			for i := len(e.middleware) - 1; i >= 0; i-- {
-				h = e.middleware[i](h)
-			}
-			return h(c)
+			return e.chain(c)(c)
 		}
 		for i := len(e.premiddleware) - 1; i >= 0; i-- {
 			h = e.premiddleware[i](h)
@@ -498,6 +552,52 @@ func (e *Rest) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	e.pool.Put(c)
 }
 
+// chain builds the middleware chain of the matched handler, each step
+// is recorded when the request is traced, see mw.DebugTrace.
+func (e *Rest) chain(c *Context) HandlerFunc {
+	h := c.Handler()
+	t := trace.FromContext(c.Request().Context())
+	if t != nil {
+		h = traced(t, trace.KindHandler, c.Request().Method+" "+c.Path(), h)
+	}
+
+	for i := len(e.middleware) - 1; i >= 0; i-- {
+		h = e.middleware[i](h)
+		if t != nil {
+			h = traced(t, trace.KindMiddleware, shortName(runtime.FuncForPC(reflect.ValueOf(e.middleware[i]).Pointer()).Name()), h)
+		}
+	}
+	if c.autoHead {
+		h = autoHead(h)
+	}
+
+	return h
+}
+
+func traced(t *trace.Trace, kind, name string, h HandlerFunc) HandlerFunc {
+	return func(c *Context) error {
+		s := t.Start(kind, name)
+		err := h(c)
+		s.End(err)
+		return err
+	}
+}
+
+// shortName trims the import path and closure suffix of the function name,
+// ex. github.com/enigma-id/go/rest/mw.CORSWithConfig.func1 into mw.CORSWithConfig.
+func shortName(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	for {
+		i := strings.LastIndex(name, ".func")
+		if i < 0 || strings.Trim(name[i+5:], "0123456789.") != "" {
+			return name
+		}
+		name = name[:i]
+	}
+}
+
 // Start starts an HTTP server.
 func (e *Rest) Start(address string) error {
 	e.Server.Addr = address
@@ -542,6 +642,10 @@ func (e *Rest) StartServer(s *http.Server) (err error) {
 	s.ErrorLog = e.StdLogger
 	s.Handler = e
 
+	if ok, err := e.inspect(); ok {
+		return err
+	}
+
 	if s.TLSConfig == nil {
 		if e.Listener == nil {
 			e.Listener, err = newListener(s.Addr)
@@ -549,6 +653,10 @@ func (e *Rest) StartServer(s *http.Server) (err error) {
 				return err
 			}
 		}
+		if err = e.runStartHooks(); err != nil {
+			e.Listener.Close()
+			return err
+		}
 		e.Logger.Info(fmt.Sprintf("http server started on %s", e.Listener.Addr()))
 		return s.Serve(e.Listener)
 	}
@@ -559,6 +667,10 @@ func (e *Rest) StartServer(s *http.Server) (err error) {
 		}
 		e.TLSListener = tls.NewListener(l, s.TLSConfig)
 	}
+	if err = e.runStartHooks(); err != nil {
+		e.TLSListener.Close()
+		return err
+	}
 	e.Logger.Info(fmt.Sprintf("https server started on %s", e.TLSListener.Addr()))
 	return s.Serve(e.TLSListener)
 }
@@ -566,6 +678,7 @@ func (e *Rest) StartServer(s *http.Server) (err error) {
 // Close immediately stops the server.
 // It internally calls `http.Server#Close()`.
 func (e *Rest) Close() error {
+	e.drain()
 	if err := e.TLSServer.Close(); err != nil {
 		return err
 	}
@@ -575,12 +688,37 @@ func (e *Rest) Close() error {
 // Shutdown stops server the gracefully.
 // It internally calls `http.Server#Shutdown()`.
 func (e *Rest) Shutdown(ctx stdContext.Context) error {
+	e.drain()
 	if err := e.TLSServer.Shutdown(ctx); err != nil {
 		return err
 	}
 	return e.Server.Shutdown(ctx)
 }
 
+// ShuttingDown returns channel that is closed when the server starts
+// shutting down, so long-lived handlers like SSE or websocket can say
+// goodbye to the client instead of being cut off.
+//
+//	for {
+//		select {
+//		case <-c.ShuttingDown():
+//			fmt.Fprint(c.Response(), "event: going_away\ndata: {}\n\n")
+//			return nil
+//		case m := <-events:
+//			fmt.Fprintf(c.Response(), "data: %s\n\n", m)
+//		}
+//		c.Response().Flush()
+//	}
+func (e *Rest) ShuttingDown() <-chan struct{} {
+	return e.shutdown
+}
+
+func (e *Rest) drain() {
+	e.shutdownOnce.Do(func() {
+		close(e.shutdown)
+	})
+}
+
 // NewHTTPError creates a new HTTPError instance.
 func NewHTTPError(code int, message ...interface{}) *HTTPError {
 	he := &HTTPError{Code: code, Message: http.StatusText(code)}
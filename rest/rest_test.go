@@ -238,10 +238,10 @@ func TestRestURL(t *testing.T) {
 func TestRestRoutes(t *testing.T) {
 	e := New()
 	routes := []*Route{
-		{http.MethodGet, "/users/:user/events", ""},
-		{http.MethodGet, "/users/:user/events/public", ""},
-		{http.MethodPost, "/repos/:owner/:repo/git/refs", ""},
-		{http.MethodPost, "/repos/:owner/:repo/git/tags", ""},
+		{Method: http.MethodGet, Path: "/users/:user/events"},
+		{Method: http.MethodGet, Path: "/users/:user/events/public"},
+		{Method: http.MethodPost, Path: "/repos/:owner/:repo/git/refs"},
+		{Method: http.MethodPost, Path: "/repos/:owner/:repo/git/tags"},
 	}
 	for _, r := range routes {
 		e.Add(r.Method, r.Path, func(c *Context) error {
@@ -465,3 +465,29 @@ func TestRestShutdown(t *testing.T) {
 	err := <-errCh
 	assert.Equal(t, err.Error(), "http: Server closed")
 }
+
+func TestShortName(t *testing.T) {
+	assert.Equal(t, "mw.CORSWithConfig", shortName("github.com/enigma-id/go/rest/mw.CORSWithConfig.func1"))
+	assert.Equal(t, "mw.RBAC", shortName("github.com/enigma-id/go/rest/mw.RBAC.func1.1"))
+	assert.Equal(t, "main.(*api).auth-fm", shortName("main.(*api).auth-fm"))
+}
+
+func TestRestShuttingDown(t *testing.T) {
+	e := New()
+	c := e.NewContext(nil, nil)
+
+	select {
+	case <-c.ShuttingDown():
+		t.Fatal("expected not shutting down")
+	default:
+	}
+
+	assert.NoError(t, e.Shutdown(stdContext.Background()))
+
+	select {
+	case <-c.ShuttingDown():
+	case <-time.After(time.Second):
+		t.Fatal("expected shutting down")
+	}
+	assert.NoError(t, e.Close())
+}
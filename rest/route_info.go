@@ -0,0 +1,63 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import "net/http"
+
+// RouteInfo is the metadata of the route, the scopes are enforced
+// by mw.RBAC and the rest is used by the OpenAPI generator.
+type RouteInfo struct {
+	Summary     string
+	Description string
+	Tags        []string
+	Scopes      []string
+}
+
+// Info returns metadata of the route.
+func (r *Route) Info() RouteInfo {
+	return r.info
+}
+
+// Scopes sets the scopes required to access the route,
+// principal must have all of them.
+//
+//	e.GET("/orders", list).Scopes("orders:read").Summary("List orders")
+func (r *Route) Scopes(scopes ...string) *Route {
+	return r.update(func(i *RouteInfo) { i.Scopes = append(i.Scopes[:0:0], scopes...) })
+}
+
+// Summary sets short summary of the route.
+func (r *Route) Summary(s string) *Route {
+	return r.update(func(i *RouteInfo) { i.Summary = s })
+}
+
+// Description sets description of the route.
+func (r *Route) Description(s string) *Route {
+	return r.update(func(i *RouteInfo) { i.Description = s })
+}
+
+// Tags sets tags of the route, used for grouping the operations.
+func (r *Route) Tags(tags ...string) *Route {
+	return r.update(func(i *RouteInfo) { i.Tags = append(i.Tags[:0:0], tags...) })
+}
+
+func (r *Route) update(fn func(*RouteInfo)) *Route {
+	fn(&r.info)
+	return r
+}
+
+// Route returns the matched route of the request, nil when not found.
+// HEAD request served by the GET handler returns the GET route.
+func (c *Context) Route() *Route {
+	if c.rest == nil {
+		return nil
+	}
+
+	method := c.request.Method
+	if c.autoHead {
+		method = http.MethodGet
+	}
+	return c.rest.router.routes[method+c.path]
+}
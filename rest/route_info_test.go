@@ -0,0 +1,44 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteInfo(t *testing.T) {
+	e := New()
+	var matched *Route
+	h := func(c *Context) error {
+		matched = c.Route()
+		return c.NoContent(http.StatusOK)
+	}
+
+	r := e.GET("/orders/:id", h).Scopes("orders:read").Summary("Show order").Tags("orders")
+	e.POST("/orders", h)
+
+	info := r.Info()
+	assert.Equal(t, []string{"orders:read"}, info.Scopes)
+	assert.Equal(t, "Show order", info.Summary)
+	assert.Equal(t, []string{"orders"}, info.Tags)
+
+	r.Scopes("orders:read", "orders:admin")
+	assert.Equal(t, []string{"orders:read", "orders:admin"}, r.Info().Scopes)
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/1", nil))
+	assert.Equal(t, r, matched)
+
+	matched = nil
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodHead, "/orders/1", nil))
+	assert.Equal(t, r, matched)
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/orders", nil))
+	if assert.NotNil(t, matched) {
+		assert.Empty(t, matched.Info().Scopes)
+	}
+
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/unknown", nil), httptest.NewRecorder())
+	assert.Nil(t, c.Route())
+}
@@ -274,6 +274,18 @@ func (n *node) findHandler(method string) HandlerFunc {
 	}
 }
 
+// resolve returns the handler of the method, HEAD without handler is served
+// by the GET handler and the body written by it is discarded.
+func (n *node) resolve(method string, ctx *Context) HandlerFunc {
+	h := n.findHandler(method)
+	if h == nil && method == http.MethodHead {
+		if h = n.methodHandler.get; h != nil {
+			ctx.autoHead = true
+		}
+	}
+	return h
+}
+
 func (n *node) checkMethodNotAllowed() HandlerFunc {
 	for _, m := range methods {
 		if h := n.findHandler(m); h != nil {
@@ -293,6 +305,7 @@ func (n *node) checkMethodNotAllowed() HandlerFunc {
 // - Return it `Rest#ReleaseContext()`.
 func (r *Router) Find(method, path string, ctx *Context) {
 	ctx.path = path
+	ctx.autoHead = false
 	cn := r.tree // Current node as root
 
 	var (
@@ -403,7 +416,7 @@ func (r *Router) Find(method, path string, ctx *Context) {
 		break
 	}
 
-	ctx.handler = cn.findHandler(method)
+	ctx.handler = cn.resolve(method, ctx)
 	ctx.path = cn.ppath
 	ctx.pnames = cn.pnames
 
@@ -416,7 +429,7 @@ func (r *Router) Find(method, path string, ctx *Context) {
 		if cn = cn.findChildByKind(akind); cn == nil {
 			return
 		}
-		if h := cn.findHandler(method); h != nil {
+		if h := cn.resolve(method, ctx); h != nil {
 			ctx.handler = h
 		} else {
 			ctx.handler = cn.checkMethodNotAllowed()
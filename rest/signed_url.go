@@ -0,0 +1,79 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Query parameters of the signed url.
+const (
+	SignatureExpires = "expires"
+	SignatureParam   = "signature"
+)
+
+// ErrInvalidSignature returned when the signed url is invalid or already expired.
+var ErrInvalidSignature = NewHTTPError(http.StatusForbidden, "invalid or expired signature")
+
+// SignURL signs the route path and the query params with hmac sha256,
+// the url is valid until the ttl is passed, so it can be used as download
+// or email action link without jwt. Use Reverse to build the route path,
+// the path is escaped in the url and signed in its escaped form.
+//
+//	link := rest.SignURL(e.Reverse("invoice.download", id), url.Values{"format": {"pdf"}}, time.Hour, secret)
+//	// /invoices/12/download?expires=1546300800&format=pdf&signature=...
+func SignURL(route string, params url.Values, ttl time.Duration, secret []byte) string {
+	q := url.Values{}
+	for k, v := range params {
+		q[k] = v
+	}
+	q.Del(SignatureParam)
+	q.Set(SignatureExpires, strconv.FormatInt(time.Now().Add(ttl).Unix(), 10))
+	path := escapedPath(route)
+	q.Set(SignatureParam, signURL(path, q, secret))
+
+	return path + "?" + q.Encode()
+}
+
+// VerifyURL validates the signature and the expiration of the url.
+func VerifyURL(u *url.URL, secret []byte) error {
+	q := u.Query()
+	sig := q.Get(SignatureParam)
+	q.Del(SignatureParam)
+
+	exp, err := strconv.ParseInt(q.Get(SignatureExpires), 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return ErrInvalidSignature
+	}
+
+	if !hmac.Equal([]byte(sig), []byte(signURL(u.EscapedPath(), q, secret))) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// escapedPath returns the path escaped the same way as the path
+// of the request, the already escaped path is kept as is.
+func escapedPath(route string) string {
+	if u, err := url.Parse(route); err == nil && u.Scheme == "" && u.Host == "" {
+		return u.EscapedPath()
+	}
+	return (&url.URL{Path: route}).EscapedPath()
+}
+
+// signURL returns hex of hmac sha256 of the path and the sorted query.
+func signURL(path string, q url.Values, secret []byte) string {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(path + "?" + q.Encode()))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,54 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignURL(t *testing.T) {
+	secret := []byte("secret")
+	link := SignURL("/invoices/12/download", url.Values{"format": {"pdf"}}, time.Hour, secret)
+
+	u, err := url.Parse(link)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "/invoices/12/download", u.Path)
+		assert.Equal(t, "pdf", u.Query().Get("format"))
+		assert.NoError(t, VerifyURL(u, secret))
+		assert.Equal(t, ErrInvalidSignature, VerifyURL(u, []byte("other")))
+	}
+
+	// tampered query
+	u, _ = url.Parse(link + "&format=csv")
+	assert.Equal(t, ErrInvalidSignature, VerifyURL(u, secret))
+
+	// tampered path
+	u, _ = url.Parse(link)
+	u.Path = "/invoices/13/download"
+	assert.Equal(t, ErrInvalidSignature, VerifyURL(u, secret))
+
+	// expired
+	u, _ = url.Parse(SignURL("/invoices/12/download", nil, -time.Second, secret))
+	assert.Equal(t, ErrInvalidSignature, VerifyURL(u, secret))
+
+	u, _ = url.Parse("/invoices/12/download")
+	assert.Equal(t, ErrInvalidSignature, VerifyURL(u, secret))
+}
+
+func TestSignURLEscaped(t *testing.T) {
+	secret := []byte("secret")
+	for route, path := range map[string]string{
+		"/files/annual report.pdf": "/files/annual%20report.pdf",
+		"/files/a%2Fb":             "/files/a%2Fb",
+		"/berkas/café":             "/berkas/caf%C3%A9",
+	} {
+		link := SignURL(route, nil, time.Hour, secret)
+		req := httptest.NewRequest(http.MethodGet, link, nil)
+		assert.Equal(t, path, req.URL.EscapedPath(), route)
+		assert.NoError(t, VerifyURL(req.URL, secret), route)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Timeout sets deadline of the route, the deadline is set into the request
+// context so it's inherited by c.Ctx() and the downstream calls using it.
+// When the deadline is exceeded before the response is written,
+// 504 Gateway Timeout is returned.
+//
+//	e.GET("/reports", report).Timeout(2 * time.Second)
+func (r *Route) Timeout(d time.Duration) *Route {
+	r.timeout = d
+	return r
+}
+
+// Ctx returns context of the request.
+func (c *Context) Ctx() context.Context {
+	return c.request.Context()
+}
+
+// withTimeout runs the handler with deadline of the route.
+func withTimeout(c *Context, d time.Duration, h HandlerFunc) error {
+	req := c.Request()
+	ctx, cancel := context.WithTimeout(req.Context(), d)
+	defer cancel()
+
+	c.SetRequest(req.WithContext(ctx))
+	err := h(c)
+
+	if ctx.Err() == context.DeadlineExceeded && !c.Response().Committed {
+		he := NewHTTPError(http.StatusGatewayTimeout)
+		if err != nil {
+			he.SetInternal(err)
+		}
+		return he
+	}
+
+	return err
+}
@@ -0,0 +1,53 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteTimeout(t *testing.T) {
+	e := New()
+	slow := func(c *Context) error {
+		select {
+		case <-c.Ctx().Done():
+			return c.Ctx().Err()
+		case <-time.After(time.Second):
+			return c.String(http.StatusOK, "done")
+		}
+	}
+
+	e.GET("/slow", slow).Timeout(20 * time.Millisecond)
+	e.GET("/fast", func(c *Context) error {
+		_, ok := c.Ctx().Deadline()
+		assert.True(t, ok)
+		return c.String(http.StatusOK, "fast")
+	}).Timeout(time.Second)
+	e.GET("/written", func(c *Context) error {
+		c.String(http.StatusOK, "partial")
+		<-c.Ctx().Done()
+		return nil
+	}).Timeout(20 * time.Millisecond)
+	e.GET("/none", func(c *Context) error {
+		_, ok := c.Ctx().Deadline()
+		assert.False(t, ok)
+		return c.Empty()
+	})
+
+	request := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	start := time.Now()
+	assert.Equal(t, http.StatusGatewayTimeout, request("/slow").Code)
+	assert.True(t, time.Since(start) < 500*time.Millisecond)
+
+	assert.Equal(t, "fast", request("/fast").Body.String())
+	assert.Equal(t, "partial", request("/written").Body.String())
+	assert.Equal(t, http.StatusNoContent, request("/none").Code)
+}
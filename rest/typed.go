@@ -0,0 +1,62 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"net/http"
+
+	"github.com/enigma-id/go/validation"
+)
+
+// Empty is the request of the typed handler without input,
+// the request is not bound.
+type Empty struct{}
+
+// H adapts typed handler into HandlerFunc, the request is bound and validated
+// using the Binder, the response is wrapped in the envelope with 201 on POST
+// and 200 on the other methods. HTTPError and validation error are sent using
+// Fail, the other errors are returned so HTTPErrorHandler responds and the
+// middlewares, ex. the logger, see the cause.
+//
+//	e.POST("/orders", rest.H(func(c *rest.Context, req CreateOrderRequest) (*Order, error) {
+//		return orders.Create(c.Ctx(), req)
+//	}))
+func H[Req any, Res any](fn func(c *Context, req Req) (Res, error)) HandlerFunc {
+	return func(c *Context) error {
+		var req Req
+		if _, empty := interface{}(req).(Empty); !empty {
+			if err := c.Bind(&req); err != nil {
+				return fail(c, err)
+			}
+
+			// binder only validates the query of delete request
+			if r := c.Request(); r.Method == http.MethodGet && r.ContentLength == 0 {
+				if err := c.Validate(&req); err != nil {
+					return fail(c, err)
+				}
+			}
+		}
+
+		res, err := fn(c, req)
+		if err != nil {
+			return fail(c, err)
+		}
+
+		if c.Request().Method == http.MethodPost {
+			return c.Created(res)
+		}
+		return c.OK(res)
+	}
+}
+
+// fail sends the client error using Fail and returns the internal error.
+func fail(c *Context, err error) error {
+	switch err.(type) {
+	case *HTTPError, *validation.Response:
+		return c.Fail(err)
+	}
+
+	return err
+}
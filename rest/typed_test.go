@@ -0,0 +1,81 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type createOrderRequest struct {
+	Product string `json:"product" query:"product" valid:"required"`
+	Qty     int    `json:"qty" query:"qty" valid:"gt:0"`
+}
+
+type orderResponse struct {
+	ID      int    `json:"id"`
+	Product string `json:"product"`
+}
+
+func TestH(t *testing.T) {
+	e := New()
+	e.POST("/orders", H(func(c *Context, req createOrderRequest) (orderResponse, error) {
+		if req.Product == "void" {
+			return orderResponse{}, NewHTTPError(http.StatusConflict, "Product is not available")
+		}
+		return orderResponse{ID: 1, Product: req.Product}, nil
+	}))
+	e.GET("/orders", H(func(c *Context, req createOrderRequest) ([]orderResponse, error) {
+		return []orderResponse{{ID: 1, Product: req.Product}}, nil
+	}))
+	e.GET("/health", H(func(c *Context, _ Empty) (string, error) {
+		return "", errors.New("database is down")
+	}))
+
+	send := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send(http.MethodPost, "/orders", `{"product":"book","qty":1}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.JSONEq(t, `{"status":"success","data":{"id":1,"product":"book"}}`, rec.Body.String())
+
+	rec = send(http.MethodPost, "/orders", `{"qty":0}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"product":"The product field is required"`)
+
+	rec = send(http.MethodPost, "/orders", `{"product":"void","qty":1}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"message":"Product is not available"`)
+
+	rec = send(http.MethodGet, "/orders?product=pen&qty=2", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"product":"pen"`)
+
+	rec = send(http.MethodGet, "/orders?qty=2", "")
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	// internal error is handled by the error handler
+	var handled error
+	e.HTTPErrorHandler = func(err error, c *Context) {
+		handled = err
+		e.DefaultHTTPErrorHandler(err, c)
+	}
+	rec = send(http.MethodGet, "/health", "")
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.EqualError(t, handled, "database is down")
+	assert.NotContains(t, rec.Body.String(), "database is down")
+
+	handled = nil
+	send(http.MethodPost, "/orders", `{"product":"void","qty":1}`)
+	assert.Nil(t, handled)
+}
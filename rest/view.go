@@ -0,0 +1,255 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ViewTag is the struct tag of the serialization groups, field without
+// the tag is included in all views.
+const ViewTag = "view"
+
+type (
+	// viewField is cached information of a struct field.
+	viewField struct {
+		index     []int
+		name      string
+		omitEmpty bool
+		views     []string
+	}
+
+	// viewObject is json object that keeps the order of the fields.
+	viewObject struct {
+		keys   []string
+		values []interface{}
+	}
+)
+
+var (
+	viewFields sync.Map
+
+	marshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// JSONView sends json response with status 200 of the object rendered
+// using the view, fields tagged with other views are excluded.
+//
+//	type User struct {
+//		ID    int64  `json:"id"`
+//		Name  string `json:"name"`
+//		Email string `json:"email" view:"admin,self"`
+//	}
+//
+//	c.JSONView("public", user) // {"id":1,"name":"Jon"}
+func (c *Context) JSONView(view string, i interface{}) error {
+	return c.JSON(http.StatusOK, View(view, i))
+}
+
+// View returns the object rendered using the view, the result can be
+// passed into any json response, ex. c.OK(rest.View("admin", users)).
+func View(view string, i interface{}) interface{} {
+	return renderView(view, reflect.ValueOf(i))
+}
+
+func renderView(view string, v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+
+	t := v.Type()
+	if t.Implements(marshalerType) || t.Implements(textMarshalerType) {
+		return v.Interface()
+	}
+	if v.CanAddr() && (reflect.PtrTo(t).Implements(marshalerType) || reflect.PtrTo(t).Implements(textMarshalerType)) {
+		return v.Addr().Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return renderView(view, v.Elem())
+	case reflect.Struct:
+		o := new(viewObject)
+		for _, f := range cachedViewFields(t) {
+			if !f.visible(view) {
+				continue
+			}
+
+			fv, ok := fieldByIndex(v, f.index)
+			if !ok || (f.omitEmpty && isEmptyValue(fv)) {
+				continue
+			}
+
+			o.keys = append(o.keys, f.name)
+			o.values = append(o.values, renderView(view, fv))
+		}
+		return o
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		fallthrough
+	case reflect.Array:
+		s := make([]interface{}, v.Len())
+		for i := range s {
+			s[i] = renderView(view, v.Index(i))
+		}
+		return s
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		if t.Key().Kind() != reflect.String {
+			return v.Interface()
+		}
+		m := make(map[string]interface{}, v.Len())
+		for _, k := range v.MapKeys() {
+			m[k.String()] = renderView(view, v.MapIndex(k))
+		}
+		return m
+	}
+
+	return v.Interface()
+}
+
+// cachedViewFields returns exported fields of the struct type following
+// encoding/json rules, embedded struct fields are promoted.
+func cachedViewFields(t reflect.Type) []viewField {
+	if f, ok := viewFields.Load(t); ok {
+		return f.([]viewField)
+	}
+
+	fields := typeViewFields(t, nil)
+	viewFields.Store(t, fields)
+
+	return fields
+}
+
+func typeViewFields(t reflect.Type, index []int) (fields []viewField) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		idx := append(append([]int(nil), index...), i)
+		name, opts := tag, ""
+		if j := strings.Index(tag, ","); j >= 0 {
+			name, opts = tag[:j], tag[j+1:]
+		}
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			fields = append(fields, typeViewFields(ft, idx)...)
+			continue
+		}
+
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+
+		f := viewField{index: idx, name: name, omitEmpty: strings.Contains(","+opts+",", ",omitempty,")}
+		if v := sf.Tag.Get(ViewTag); v != "" {
+			f.views = strings.Split(v, ",")
+		}
+
+		fields = append(fields, f)
+	}
+
+	return
+}
+
+func (f *viewField) visible(view string) bool {
+	if len(f.views) == 0 {
+		return true
+	}
+
+	for _, v := range f.views {
+		if strings.TrimSpace(v) == view {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fieldByIndex returns nested field, false when embedded pointer is nil.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v, true
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// MarshalJSON writes the object keeping the order of the fields.
+func (o *viewObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		kb, _ := json.Marshal(k)
+		vb, err := json.Marshal(o.values[i])
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(kb)
+		buf.WriteByte(':')
+		buf.Write(vb)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
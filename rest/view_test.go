@@ -0,0 +1,73 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	viewAudit struct {
+		CreatedBy string    `json:"created_by" view:"admin"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	viewAddress struct {
+		City   string `json:"city"`
+		Street string `json:"street" view:"admin, self"`
+	}
+
+	viewUser struct {
+		ID        int64             `json:"id"`
+		Name      string            `json:"name"`
+		Email     string            `json:"email,omitempty" view:"admin,self"`
+		Password  string            `json:"-"`
+		Address   *viewAddress      `json:"address"`
+		Addresses []viewAddress     `json:"addresses,omitempty"`
+		Meta      map[string]string `json:"meta,omitempty"`
+		secret    string
+		viewAudit
+	}
+)
+
+func TestView(t *testing.T) {
+	created := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	u := &viewUser{
+		ID: 1, Name: "Jon", Email: "jon@kora.id", Password: "x", secret: "y",
+		Address:   &viewAddress{City: "Jakarta", Street: "Sudirman"},
+		Addresses: []viewAddress{{City: "Bandung", Street: "Dago"}},
+		viewAudit: viewAudit{CreatedBy: "admin", CreatedAt: created},
+	}
+
+	b, err := json.Marshal(View("public", u))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":1,"name":"Jon","address":{"city":"Jakarta"},"addresses":[{"city":"Bandung"}],"created_at":"2019-01-02T03:04:05Z"}`, string(b))
+
+	b, err = json.Marshal(View("admin", []*viewUser{u, nil}))
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"id":1,"name":"Jon","email":"jon@kora.id","address":{"city":"Jakarta","street":"Sudirman"},`+
+		`"addresses":[{"city":"Bandung","street":"Dago"}],"created_by":"admin","created_at":"2019-01-02T03:04:05Z"},null]`, string(b))
+
+	// fields without view tag follows encoding/json
+	b, _ = json.Marshal(View("public", viewAddress{City: "Bogor"}))
+	c, _ := json.Marshal(struct {
+		City string `json:"city"`
+	}{"Bogor"})
+	assert.Equal(t, string(c), string(b))
+}
+
+func TestContextJSONView(t *testing.T) {
+	e := New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+	u := viewUser{ID: 1, Name: "Jon", Email: "jon@kora.id", Meta: map[string]string{"plan": "pro"}}
+	if assert.NoError(t, c.JSONView("self", u)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"id":1,"name":"Jon","email":"jon@kora.id","address":null,"meta":{"plan":"pro"},"created_at":"0001-01-01T00:00:00Z"}`, rec.Body.String())
+	}
+}
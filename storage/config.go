@@ -0,0 +1,70 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/enigma-id/go/env"
+)
+
+// Config represents all configurable storage data.
+var Config *config
+
+type config struct {
+	Driver    string // local, s3, minio or gcs
+	Root      string // root directory of local driver
+	BaseURL   string // base url of signed url on local driver
+	Secret    string // secret used to sign url on local driver
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+// ReadEnv set all configurable data from env variable.
+func ReadEnv() {
+	Config = &config{
+		Driver:    env.GetString("STORAGE_DRIVER", "local"),
+		Root:      env.GetString("STORAGE_ROOT", "storage/files"),
+		BaseURL:   env.GetString("STORAGE_BASE_URL", "/files"),
+		Secret:    env.GetString("STORAGE_SECRET", env.GetString("APP_JWT_SECRET", "")),
+		Endpoint:  env.GetString("STORAGE_ENDPOINT", ""),
+		Region:    env.GetString("STORAGE_REGION", "ap-southeast-1"),
+		Bucket:    env.GetString("STORAGE_BUCKET", ""),
+		AccessKey: env.GetString("STORAGE_ACCESS_KEY", ""),
+		SecretKey: env.GetString("STORAGE_SECRET_KEY", ""),
+	}
+}
+
+// New creates storage based on the configuration.
+func New() (Storage, error) {
+	c := Config
+
+	var s *S3
+	switch c.Driver {
+	case "local":
+		return NewLocal(c.Root, c.BaseURL, []byte(c.Secret)), nil
+	case "s3":
+		s = NewS3(c.Region, c.Bucket, c.AccessKey, c.SecretKey)
+	case "minio":
+		s = NewMinio(c.Endpoint, c.Bucket, c.AccessKey, c.SecretKey)
+	case "gcs":
+		s = NewGCS(c.Bucket, c.AccessKey, c.SecretKey)
+	default:
+		return nil, fmt.Errorf("storage: unknown driver %q", c.Driver)
+	}
+
+	if c.Endpoint != "" {
+		s.Endpoint = c.Endpoint
+	}
+
+	return s, nil
+}
+
+func init() {
+	ReadEnv()
+}
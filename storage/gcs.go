@@ -0,0 +1,22 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package storage
+
+// GCSEndpoint endpoint of google cloud storage xml api.
+const GCSEndpoint = "https://storage.googleapis.com"
+
+// NewGCS creates google cloud storage using the interoperability (s3 compatible) xml api,
+// the access and secret key are the HMAC keys of the service account,
+// see https://cloud.google.com/storage/docs/authentication/hmackeys.
+func NewGCS(bucket, accessKey, secretKey string) *S3 {
+	return &S3{
+		Endpoint:  GCSEndpoint,
+		Region:    "auto",
+		Bucket:    bucket,
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+		PathStyle: true,
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSignature error when the signed url is invalid or already expired.
+var ErrInvalidSignature = errors.New("storage: invalid or expired signature")
+
+// Local storage driver that stores the objects on the local disk.
+// Signed url are signed using Secret and should be served by the
+// Local itself as http.Handler mounted at BaseURL.
+type Local struct {
+	Root    string
+	BaseURL string
+	Secret  []byte
+}
+
+// NewLocal creates local storage on the root directory.
+func NewLocal(root, baseURL string, secret []byte) *Local {
+	return &Local{Root: root, BaseURL: baseURL, Secret: secret}
+}
+
+func (l *Local) path(key string) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(l.Root, filepath.FromSlash(key)), nil
+}
+
+// Put implements Storage interfaces, content is written into
+// temporary file and renamed once completed.
+func (l *Local) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(p), ".upload-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err = io.Copy(f, &ctxReader{ctx, r}); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), p)
+}
+
+// Get implements Storage interfaces.
+func (l *Local) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(p)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+
+	return f, err
+}
+
+// Delete implements Storage interfaces.
+func (l *Local) Delete(ctx context.Context, key string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(p); os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
+
+// List implements Storage interfaces.
+func (l *Local) List(ctx context.Context, prefix string) (objects []*Object, err error) {
+	err = filepath.Walk(l.Root, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		if fi.IsDir() || strings.HasPrefix(fi.Name(), ".upload-") {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(l.Root, p)
+		key := filepath.ToSlash(rel)
+		if strings.HasPrefix(key, prefix) {
+			objects = append(objects, &Object{
+				Key:          key,
+				Size:         fi.Size(),
+				ContentType:  contentType(key, ""),
+				LastModified: fi.ModTime(),
+			})
+		}
+
+		return ctx.Err()
+	})
+
+	return
+}
+
+// SignedURL implements Storage interfaces, the url is signed
+// with hmac sha256 of the method, key and expiration time.
+func (l *Local) SignedURL(ctx context.Context, key string, method string, expires time.Duration) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	exp := strconv.FormatInt(time.Now().Add(expires).Unix(), 10)
+	q := url.Values{}
+	q.Set("expires", exp)
+	q.Set("signature", l.sign(method, key, exp))
+
+	return strings.TrimRight(l.BaseURL, "/") + "/" + key + "?" + q.Encode(), nil
+}
+
+// Verify validates the signature of the url.
+func (l *Local) Verify(method, key string, q url.Values) error {
+	exp, err := strconv.ParseInt(q.Get("expires"), 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return ErrInvalidSignature
+	}
+
+	if !hmac.Equal([]byte(q.Get("signature")), []byte(l.sign(method, key, q.Get("expires")))) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// ServeHTTP serves signed url requests, GET to download
+// and PUT to upload the object, the key is taken from url path
+// so it should be mounted with http.StripPrefix.
+func (l *Local) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key, err := cleanKey(r.URL.Path)
+	if err == nil {
+		err = l.Verify(r.Method, key, r.URL.Query())
+	}
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		p, _ := l.path(key)
+		http.ServeFile(w, r, p)
+	case http.MethodPut:
+		if err = l.Put(r.Context(), key, r.Body, r.ContentLength, r.Header.Get("Content-Type")); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+func (l *Local) sign(method, key, expires string) string {
+	if method == http.MethodHead {
+		method = http.MethodGet
+	}
+
+	h := hmac.New(sha256.New, l.Secret)
+	h.Write([]byte(method + "\n" + key + "\n" + expires))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ctxReader stops reading when the context is done.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return r.r.Read(p)
+}
@@ -0,0 +1,356 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/enigma-id/go/utility/sigv4"
+)
+
+// DefaultPartSize size of each part on multipart upload,
+// objects larger than this (or unknown size) are uploaded in parts.
+const DefaultPartSize = 8 << 20
+
+// S3 storage driver for amazon s3 and any s3 compatible storage
+// like minio or gcs interoperability api.
+type S3 struct {
+	Endpoint  string // ex. https://s3.ap-southeast-1.amazonaws.com or http://localhost:9000
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool // use endpoint/bucket/key instead of bucket.endpoint/key, required by minio
+	PartSize  int  // s3 requires at least 5MB except for the last part
+	Client    *http.Client
+
+	now func() time.Time
+}
+
+// NewS3 creates amazon s3 storage on the region.
+func NewS3(region, bucket, accessKey, secretKey string) *S3 {
+	return &S3{
+		Endpoint:  "https://s3." + region + ".amazonaws.com",
+		Region:    region,
+		Bucket:    bucket,
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+	}
+}
+
+// NewMinio creates s3 storage for minio server on the endpoint.
+func NewMinio(endpoint, bucket, accessKey, secretKey string) *S3 {
+	return &S3{
+		Endpoint:  endpoint,
+		Region:    "us-east-1",
+		Bucket:    bucket,
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+		PathStyle: true,
+	}
+}
+
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func (e *s3Error) Error() string {
+	return "storage: " + e.Code + ": " + e.Message
+}
+
+// Put implements Storage interfaces, when the size is unknown or larger than
+// part size, the content is uploaded using multipart upload so only one part
+// is kept on memory.
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	contentType = strings.TrimSpace(contentType)
+
+	if size >= 0 && size <= int64(s.partSize()) {
+		return s.putObject(ctx, key, r, size, contentType)
+	}
+
+	buf := make([]byte, s.partSize())
+	n, err := io.ReadFull(r, buf)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return s.putObject(ctx, key, bytes.NewReader(buf[:n]), int64(n), contentType)
+	} else if err != nil {
+		return err
+	}
+
+	return s.multipart(ctx, key, r, buf, contentType)
+}
+
+func (s *S3) putObject(ctx context.Context, key string, r io.Reader, size int64, ct string) error {
+	h := http.Header{"Content-Type": {contentType(key, ct)}}
+	res, err := s.do(ctx, http.MethodPut, key, nil, h, r, size)
+	if err != nil {
+		return err
+	}
+
+	return res.Body.Close()
+}
+
+// multipart uploads the reader in parts, buf is already filled with the first part.
+func (s *S3) multipart(ctx context.Context, key string, r io.Reader, buf []byte, ct string) (err error) {
+	h := http.Header{"Content-Type": {contentType(key, ct)}}
+	res, err := s.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, h, nil, 0)
+	if err != nil {
+		return err
+	}
+
+	var init struct {
+		UploadID string `xml:"UploadId"`
+	}
+	err = xml.NewDecoder(res.Body).Decode(&init)
+	res.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			if res, e := s.do(context.Background(), http.MethodDelete, key, url.Values{"uploadId": {init.UploadID}}, nil, nil, 0); e == nil {
+				res.Body.Close()
+			}
+		}
+	}()
+
+	type part struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	}
+
+	var parts []part
+	for i, n := 1, len(buf); n > 0; i++ {
+		q := url.Values{"partNumber": {strconv.Itoa(i)}, "uploadId": {init.UploadID}}
+		if res, err = s.do(ctx, http.MethodPut, key, q, nil, bytes.NewReader(buf[:n]), int64(n)); err != nil {
+			return err
+		}
+		res.Body.Close()
+		parts = append(parts, part{PartNumber: i, ETag: res.Header.Get("ETag")})
+
+		if n, err = io.ReadFull(r, buf); err == io.EOF || err == io.ErrUnexpectedEOF {
+			err = nil
+		} else if err != nil {
+			return err
+		}
+	}
+
+	body, _ := xml.Marshal(struct {
+		XMLName xml.Name `xml:"CompleteMultipartUpload"`
+		Parts   []part   `xml:"Part"`
+	}{Parts: parts})
+
+	if res, err = s.do(ctx, http.MethodPost, key, url.Values{"uploadId": {init.UploadID}}, nil, bytes.NewReader(body), int64(len(body))); err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	// complete multipart could responded 200 with error on the body
+	b, _ := ioutil.ReadAll(res.Body)
+	if bytes.Contains(b, []byte("<Error>")) {
+		e := new(s3Error)
+		xml.Unmarshal(b, e)
+		return e
+	}
+
+	return nil
+}
+
+// Get implements Storage interfaces.
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.do(ctx, http.MethodGet, key, nil, nil, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Body, nil
+}
+
+// Delete implements Storage interfaces.
+func (s *S3) Delete(ctx context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.do(ctx, http.MethodDelete, key, nil, nil, nil, 0)
+	if err == ErrNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return res.Body.Close()
+}
+
+// List implements Storage interfaces using ListObjectsV2.
+func (s *S3) List(ctx context.Context, prefix string) (objects []*Object, err error) {
+	var token string
+	for {
+		q := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+
+		res, err := s.do(ctx, http.MethodGet, "", q, nil, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			Contents []struct {
+				Key          string    `xml:"Key"`
+				Size         int64     `xml:"Size"`
+				LastModified time.Time `xml:"LastModified"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+
+		err = xml.NewDecoder(res.Body).Decode(&result)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range result.Contents {
+			objects = append(objects, &Object{
+				Key:          c.Key,
+				Size:         c.Size,
+				ContentType:  contentType(c.Key, ""),
+				LastModified: c.LastModified,
+			})
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// SignedURL implements Storage interfaces using presigned url.
+func (s *S3) SignedURL(ctx context.Context, key string, method string, expires time.Duration) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := s.url(key, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return s.signer().Presign(method, u, expires, s.time()), nil
+}
+
+// do sends signed request to the object, non 2xx response returned as error.
+func (s *S3) do(ctx context.Context, method, key string, q url.Values, h http.Header, body io.Reader, size int64) (*http.Response, error) {
+	u, err := s.url(key, q)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.URL = u
+	req.ContentLength = size
+	if size == 0 {
+		req.Body = nil
+	}
+	for k, v := range h {
+		req.Header[k] = v
+	}
+
+	s.signer().Sign(req, sigv4.UnsignedPayload, s.time())
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return res, nil
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound && key != "" {
+		return nil, ErrNotFound
+	}
+
+	e := &s3Error{Code: strconv.Itoa(res.StatusCode), Message: http.StatusText(res.StatusCode)}
+	xml.NewDecoder(res.Body).Decode(e)
+
+	return nil, e
+}
+
+// url builds url of the object with path style or virtual host style.
+func (s *S3) url(key string, q url.Values) (*url.URL, error) {
+	u, err := url.Parse(s.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("storage: invalid endpoint: %v", err)
+	}
+
+	p := "/" + key
+	if s.PathStyle {
+		p = "/" + s.Bucket + p
+	} else {
+		u.Host = s.Bucket + "." + u.Host
+	}
+
+	u.Path = p
+	u.RawPath = sigv4.EscapePath(p)
+	u.RawQuery = q.Encode()
+
+	return u, nil
+}
+
+func (s *S3) signer() *sigv4.Signer {
+	return &sigv4.Signer{AccessKey: s.AccessKey, SecretKey: s.SecretKey, Region: s.Region, Service: "s3"}
+}
+
+func (s *S3) partSize() int {
+	if s.PartSize <= 0 {
+		return DefaultPartSize
+	}
+
+	return s.PartSize
+}
+
+func (s *S3) time() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+
+	return time.Now()
+}
@@ -0,0 +1,82 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"path"
+	"strings"
+	"time"
+)
+
+// Storage is the interface of the file storage drivers,
+// all the keys are slash separated path relative into the bucket or root directory.
+type Storage interface {
+	// Put stores content of the reader into the key, size could be -1
+	// when its unknown, the content is streamed and not buffered entirely.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+
+	// Get opens the object of the key, caller should close the reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the object, deleting non existing object is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// List returns all objects that has the prefix.
+	List(ctx context.Context, prefix string) ([]*Object, error)
+
+	// SignedURL returns temporary url that can be used to access
+	// the object with the http method without credentials.
+	SignedURL(ctx context.Context, key string, method string, expires time.Duration) (string, error)
+}
+
+// Object information of the stored file.
+type Object struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	ContentType  string    `json:"content_type,omitempty"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+var (
+	// ErrNotFound error when the object is not exists.
+	ErrNotFound = errors.New("storage: object not found")
+	// ErrInvalidKey error when the key is empty or trying to escape the root.
+	ErrInvalidKey = errors.New("storage: invalid object key")
+)
+
+// cleanKey normalize the key and make sure its not escaping the root.
+func cleanKey(key string) (string, error) {
+	if strings.Contains(key, "..") {
+		for _, s := range strings.Split(key, "/") {
+			if s == ".." {
+				return "", ErrInvalidKey
+			}
+		}
+	}
+
+	key = strings.TrimPrefix(path.Clean("/"+key), "/")
+	if key == "" {
+		return "", ErrInvalidKey
+	}
+
+	return key, nil
+}
+
+// contentType returns the content type or detect it from the key extension.
+func contentType(key, ct string) string {
+	if ct != "" {
+		return ct
+	}
+
+	if ct = mime.TypeByExtension(path.Ext(key)); ct != "" {
+		return ct
+	}
+
+	return "application/octet-stream"
+}
@@ -0,0 +1,188 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/enigma-id/go/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeS3 minimal in memory s3 server for the tests.
+type fakeS3 struct {
+	sync.Mutex
+	objects map[string][]byte
+	uploads map[string][][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+	q := r.URL.Query()
+	body, _ := ioutil.ReadAll(r.Body)
+
+	switch {
+	case r.Method == http.MethodGet && q.Get("list-type") == "2":
+		fmt.Fprint(w, "<ListBucketResult>")
+		for k, v := range f.objects {
+			if strings.HasPrefix(k, q.Get("prefix")) {
+				fmt.Fprintf(w, "<Contents><Key>%s</Key><Size>%d</Size><LastModified>2019-03-01T00:00:00.000Z</LastModified></Contents>", k, len(v))
+			}
+		}
+		fmt.Fprint(w, "<IsTruncated>false</IsTruncated></ListBucketResult>")
+	case r.Method == http.MethodPost && q.Has("uploads"):
+		f.uploads[key] = nil
+		fmt.Fprint(w, "<InitiateMultipartUploadResult><UploadId>upload-1</UploadId></InitiateMultipartUploadResult>")
+	case r.Method == http.MethodPut && q.Get("uploadId") != "":
+		f.uploads[key] = append(f.uploads[key], body)
+		w.Header().Set("ETag", fmt.Sprintf(`"%d"`, len(f.uploads[key])))
+	case r.Method == http.MethodPost && q.Get("uploadId") != "":
+		f.objects[key] = bytes.Join(f.uploads[key], nil)
+		fmt.Fprint(w, "<CompleteMultipartUploadResult></CompleteMultipartUploadResult>")
+	case r.Method == http.MethodPut:
+		f.objects[key] = body
+	case r.Method == http.MethodGet:
+		b, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, "<Error><Code>NoSuchKey</Code></Error>")
+			return
+		}
+		w.Write(b)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func testStorage(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	assert.NoError(t, s.Put(ctx, "docs/a.txt", strings.NewReader("hello"), 5, ""))
+	assert.NoError(t, s.Put(ctx, "/docs/b.txt", strings.NewReader(strings.Repeat("x", 25)), -1, "text/plain"))
+	assert.Equal(t, ErrInvalidKey, s.Put(ctx, "../a.txt", strings.NewReader(""), 0, ""))
+
+	r, err := s.Get(ctx, "docs/b.txt")
+	if assert.NoError(t, err) {
+		b, _ := ioutil.ReadAll(r)
+		r.Close()
+		assert.Equal(t, strings.Repeat("x", 25), string(b))
+	}
+
+	_, err = s.Get(ctx, "docs/c.txt")
+	assert.Equal(t, ErrNotFound, err)
+
+	objects, err := s.List(ctx, "docs/")
+	assert.NoError(t, err)
+	assert.Len(t, objects, 2)
+
+	assert.NoError(t, s.Delete(ctx, "docs/a.txt"))
+	assert.NoError(t, s.Delete(ctx, "docs/a.txt"))
+	objects, _ = s.List(ctx, "docs/")
+	assert.Len(t, objects, 1)
+}
+
+func TestLocal(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "storage")
+	defer os.RemoveAll(dir)
+
+	l := NewLocal(dir, "http://localhost/files", []byte("secret"))
+	testStorage(t, l)
+
+	u, err := l.SignedURL(context.Background(), "docs/b.txt", http.MethodGet, time.Minute)
+	assert.NoError(t, err)
+
+	pu, _ := url.Parse(u)
+	assert.Equal(t, "/files/docs/b.txt", pu.Path)
+
+	srv := http.StripPrefix("/files", l)
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, u, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, strings.Repeat("x", 25), rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, u, strings.NewReader("overwrite")))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	assert.Equal(t, ErrInvalidSignature, l.Verify(http.MethodGet, "docs/b.txt", url.Values{"expires": {"1"}}))
+}
+
+func TestS3(t *testing.T) {
+	srv := httptest.NewServer(&fakeS3{objects: map[string][]byte{}, uploads: map[string][][]byte{}})
+	defer srv.Close()
+
+	s := NewMinio(srv.URL, "bucket", "key", "secret")
+	s.PartSize = 10
+	testStorage(t, s)
+
+	u, err := s.SignedURL(context.Background(), "docs/b c.txt", http.MethodGet, time.Hour)
+	assert.NoError(t, err)
+	assert.Contains(t, u, srv.URL+"/bucket/docs/b%20c.txt?X-Amz-Algorithm=AWS4-HMAC-SHA256")
+
+	s.AccessKey = "invalid"
+	assert.Error(t, s.Put(context.Background(), "a.txt", strings.NewReader("a"), 1, ""))
+
+	assert.Equal(t, "https://bucket.s3.ap-southeast-1.amazonaws.com/a%2Bb.txt", func() string {
+		u, _ := NewS3("ap-southeast-1", "bucket", "", "").url("a+b.txt", nil)
+		return u.String()
+	}())
+}
+
+func TestUpload(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "storage")
+	defer os.RemoveAll(dir)
+	l := NewLocal(dir, "", nil)
+
+	body := new(bytes.Buffer)
+	mw := multipart.NewWriter(body)
+	mw.WriteField("name", "avatar")
+	fw, _ := mw.CreateFormFile("file", "avatar.PNG")
+	fw.Write([]byte("\x89PNG\r\n\x1a\n" + strings.Repeat("0", 100)))
+	fw, _ = mw.CreateFormFile("other", "note.txt")
+	fw.Write([]byte("note"))
+	mw.Close()
+
+	newContext := func() *rest.Context {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body.Bytes()))
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		return rest.New().NewContext(req, httptest.NewRecorder())
+	}
+
+	objects, err := Upload(newContext(), l, UploadConfig{Field: "file", AllowedTypes: []string{"image/*"}})
+	if assert.NoError(t, err) && assert.Len(t, objects, 1) {
+		assert.Equal(t, "image/png", objects[0].ContentType)
+		assert.Equal(t, int64(108), objects[0].Size)
+		assert.True(t, strings.HasSuffix(objects[0].Key, ".png"))
+	}
+
+	_, err = Upload(newContext(), l, UploadConfig{Field: "file", MaxSize: 50})
+	assert.Equal(t, rest.ErrStatusRequestEntityTooLarge, err)
+
+	_, err = Upload(newContext(), l, UploadConfig{AllowedTypes: []string{"image/png"}})
+	if assert.IsType(t, new(rest.HTTPError), err) {
+		assert.Equal(t, http.StatusUnsupportedMediaType, err.(*rest.HTTPError).Code)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/enigma-id/go/rest"
+)
+
+// UploadConfig defines the config of multipart upload helper.
+type UploadConfig struct {
+	// Field name of the file on the form, empty means all files are uploaded.
+	Field string
+
+	// MaxSize maximum size of each file in bytes, 0 means unlimited.
+	MaxSize int64
+
+	// AllowedTypes list of allowed content type, detected from the content
+	// rather than trusting the request, ex. image/png, image/*.
+	AllowedTypes []string
+
+	// Progress is called with the bytes read of the request and the content length.
+	// Optional.
+	Progress func(read, total int64)
+
+	// Key generates object key of the file,
+	// default is date prefixed random name with the file extension.
+	Key func(filename string) string
+}
+
+// Upload reads the multipart request and pipes each file part straight into the storage
+// without buffering the whole file into memory or temporary files.
+func Upload(c *rest.Context, s Storage, config UploadConfig) (objects []*Object, err error) {
+	if config.Key == nil {
+		config.Key = randomKey
+	}
+
+	var fields []string
+	if config.Field != "" {
+		fields = []string{config.Field}
+	}
+
+	err = c.Multipart(rest.MultipartConfig{
+		FileFields:   fields,
+		MaxPartSize:  config.MaxSize,
+		AllowedTypes: config.AllowedTypes,
+		Progress:     config.Progress,
+	}, func(p *rest.Part) error {
+		if !p.IsFile() {
+			return nil
+		}
+
+		o := &Object{Key: config.Key(p.FileName()), ContentType: p.ContentType, LastModified: time.Now()}
+		if err := s.Put(c.Request().Context(), o.Key, p, -1, p.ContentType); err != nil {
+			return err
+		}
+
+		o.Size = p.Size()
+		objects = append(objects, o)
+		return nil
+	})
+
+	return objects, err
+}
+
+func randomKey(filename string) string {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	return time.Now().Format("2006/01/02/") + hex.EncodeToString(b) + strings.ToLower(path.Ext(filename))
+}
@@ -0,0 +1,92 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package tenant
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/enigma-id/go/cache"
+)
+
+// ErrFlushNotSupported returned by Flush of tenant cache, flushing the
+// underlying cache would remove entries of all tenants.
+var ErrFlushNotSupported = errors.New("tenant: flush is not supported on tenant cache")
+
+// Cache wraps the cache so the keys are prefixed with "tenant:<id>:".
+type Cache struct {
+	cache.Cache
+	Prefix string
+}
+
+// NewCache returns cache scoped into the tenant.
+func NewCache(c cache.Cache, t *Tenant) *Cache {
+	return &Cache{Cache: c, Prefix: "tenant:" + t.ID + ":"}
+}
+
+// CacheFrom returns cache scoped into tenant of the context, using cache.Instance.
+func CacheFrom(ctx context.Context) (*Cache, error) {
+	t, ok := FromContext(ctx)
+	if !ok {
+		return nil, ErrNoTenant
+	}
+
+	return NewCache(cache.Instance, t), nil
+}
+
+// Get implement cache.Getter interfaces
+func (c *Cache) Get(key string, ptrValue interface{}) error {
+	return c.Cache.Get(c.Prefix+key, ptrValue)
+}
+
+// Set implement cache.Cache interfaces
+func (c *Cache) Set(key string, value interface{}, expires time.Duration) error {
+	return c.Cache.Set(c.Prefix+key, value, expires)
+}
+
+// GetMulti implement cache.Cache interfaces
+func (c *Cache) GetMulti(keys ...string) (cache.Getter, error) {
+	pk := make([]string, len(keys))
+	for i, k := range keys {
+		pk[i] = c.Prefix + k
+	}
+
+	g, err := c.Cache.GetMulti(pk...)
+	if err != nil {
+		return nil, err
+	}
+
+	return prefixedGetter{g, c.Prefix}, nil
+}
+
+// Delete implement cache.Cache interfaces
+func (c *Cache) Delete(key string) error {
+	return c.Cache.Delete(c.Prefix + key)
+}
+
+// Add implement cache.Cache interfaces
+func (c *Cache) Add(key string, value interface{}, expires time.Duration) error {
+	return c.Cache.Add(c.Prefix+key, value, expires)
+}
+
+// Replace implement cache.Cache interfaces
+func (c *Cache) Replace(key string, value interface{}, expires time.Duration) error {
+	return c.Cache.Replace(c.Prefix+key, value, expires)
+}
+
+// Flush implement cache.Cache interfaces, see ErrFlushNotSupported.
+func (c *Cache) Flush() error {
+	return ErrFlushNotSupported
+}
+
+type prefixedGetter struct {
+	cache.Getter
+	prefix string
+}
+
+func (g prefixedGetter) Get(key string, ptrValue interface{}) error {
+	return g.Getter.Get(g.prefix+key, ptrValue)
+}
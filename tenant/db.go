@@ -0,0 +1,129 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/enigma-id/go/db"
+)
+
+// OpenFunc opens database of the tenant, ex. using Tenant.DSN.
+type OpenFunc func(t *Tenant) (*db.DB, error)
+
+// Connections keeps database connection of each tenant,
+// for deployments using database per tenant.
+type Connections struct {
+	open OpenFunc
+
+	mu    sync.Mutex
+	conns map[string]*db.DB
+}
+
+// NewConnections creates connections using the open function.
+func NewConnections(open OpenFunc) *Connections {
+	return &Connections{open: open, conns: make(map[string]*db.DB)}
+}
+
+// Get returns database of tenant in the context, opened on the first use.
+func (c *Connections) Get(ctx context.Context) (*db.DB, error) {
+	t, ok := FromContext(ctx)
+	if !ok {
+		return nil, ErrNoTenant
+	}
+
+	return c.For(t)
+}
+
+// For returns database of the tenant, opened on the first use.
+func (c *Connections) For(t *Tenant) (*db.DB, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if d, ok := c.conns[t.ID]; ok {
+		return d, nil
+	}
+
+	d, err := c.open(t)
+	if err != nil {
+		return nil, err
+	}
+
+	c.conns[t.ID] = d
+	return d, nil
+}
+
+// Close closes database of the tenant, ex. when the tenant is removed.
+func (c *Connections) Close(t *Tenant) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if d, ok := c.conns[t.ID]; ok {
+		delete(c.conns, t.ID)
+		return d.Close()
+	}
+
+	return nil
+}
+
+// CloseAll closes all databases.
+func (c *Connections) CloseAll() (err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for id, d := range c.conns {
+		if e := d.Close(); e != nil {
+			err = e
+		}
+		delete(c.conns, id)
+	}
+
+	return
+}
+
+// SchemaSQL returns statement selecting the schema on the driver,
+// can be replaced for other drivers.
+var SchemaSQL = func(driver string, schema string) (string, error) {
+	switch driver {
+	case "postgres", "pgx":
+		return fmt.Sprintf(`SET LOCAL search_path TO "%s"`, schema), nil
+	case "mysql":
+		return fmt.Sprintf("USE `%s`", schema), nil
+	}
+
+	return "", fmt.Errorf("tenant: schema selection is not supported on %s", driver)
+}
+
+// InTx runs fn inside transaction of the database with schema of tenant
+// in the context selected, for deployments using schema per tenant.
+// Tenant without schema runs on the default schema. On mysql the schema stays
+// selected on the pooled connection, so queries of the tenant should always use InTx.
+func InTx(ctx context.Context, d *db.DB, fn db.TxFunc) error {
+	t, ok := FromContext(ctx)
+	if !ok {
+		return ErrNoTenant
+	}
+
+	return d.InTx(ctx, func(ctx context.Context, tx *db.Tx) error {
+		if t.Schema != "" {
+			if !ValidID(t.Schema) {
+				return fmt.Errorf("tenant: invalid schema %q", t.Schema)
+			}
+
+			q, err := SchemaSQL(d.Driver(), t.Schema)
+			if err != nil {
+				return err
+			}
+
+			if _, err = tx.Exec(ctx, q); err != nil {
+				return err
+			}
+		}
+
+		return fn(ctx, tx)
+	})
+}
@@ -0,0 +1,101 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package tenant
+
+import (
+	"net/http"
+
+	"github.com/enigma-id/go/rest"
+)
+
+// ContextKey of the tenant on rest.Context.
+const ContextKey = "tenant"
+
+// Config defines the config for tenant middleware.
+type Config struct {
+	// Skipper defines a function to skip middleware.
+	Skipper func(*rest.Context) bool
+
+	// Resolver returns tenant id of the request.
+	// Required.
+	Resolver Resolver
+
+	// Store finds the tenant.
+	// Required.
+	Store Store
+
+	// Optional allows request without tenant, otherwise 400 is returned.
+	Optional bool
+
+	// OnResolved is called after the tenant is resolved, ex. for checking
+	// the tenant is active or the user is member of the tenant.
+	OnResolved func(c *rest.Context, t *Tenant) error
+}
+
+// Errors
+var (
+	ErrTenantRequired = rest.NewHTTPError(http.StatusBadRequest, "tenant is required")
+	ErrUnknownTenant  = rest.NewHTTPError(http.StatusNotFound, "unknown tenant")
+)
+
+// Middleware returns middleware resolving the tenant using the resolvers,
+// the tenant is placed on rest.Context and request context.
+//
+//	r.Use(tenant.Middleware(store, tenant.FromSubdomain("example.com"), tenant.FromHeader("")))
+func Middleware(store Store, resolvers ...Resolver) rest.MiddlewareFunc {
+	return MiddlewareWithConfig(Config{Store: store, Resolver: Chain(resolvers...)})
+}
+
+// MiddlewareWithConfig returns tenant middleware with config.
+func MiddlewareWithConfig(config Config) rest.MiddlewareFunc {
+	if config.Store == nil || config.Resolver == nil {
+		panic("tenant: middleware requires store and resolver")
+	}
+
+	return func(next rest.HandlerFunc) rest.HandlerFunc {
+		return func(c *rest.Context) error {
+			if config.Skipper != nil && config.Skipper(c) {
+				return next(c)
+			}
+
+			id := config.Resolver(c)
+			if id == "" {
+				if config.Optional {
+					return next(c)
+				}
+				return ErrTenantRequired
+			}
+
+			if !ValidID(id) {
+				return ErrUnknownTenant
+			}
+
+			req := c.Request()
+			t, err := config.Store.Find(req.Context(), id)
+			if err == ErrNotFound {
+				return ErrUnknownTenant
+			} else if err != nil {
+				return err
+			}
+
+			if config.OnResolved != nil {
+				if err = config.OnResolved(c, t); err != nil {
+					return err
+				}
+			}
+
+			c.Set(ContextKey, t)
+			c.SetRequest(req.WithContext(WithTenant(req.Context(), t)))
+
+			return next(c)
+		}
+	}
+}
+
+// Get returns tenant of the request, nil when not resolved.
+func Get(c *rest.Context) *Tenant {
+	t, _ := c.Get(ContextKey).(*Tenant)
+	return t
+}
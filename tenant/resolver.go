@@ -0,0 +1,103 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package tenant
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/enigma-id/go/auth"
+	"github.com/enigma-id/go/rest"
+)
+
+// HeaderXTenantID is default header of the tenant id.
+const HeaderXTenantID = "X-Tenant-ID"
+
+// Resolver returns tenant id of the request, empty when not resolved.
+type Resolver func(c *rest.Context) string
+
+// FromSubdomain resolves tenant from subdomain of the base domain,
+// ex. acme.example.com with base domain example.com resolves acme.
+func FromSubdomain(baseDomain string) Resolver {
+	suffix := "." + strings.TrimPrefix(strings.ToLower(baseDomain), ".")
+
+	return func(c *rest.Context) string {
+		host := strings.ToLower(c.Request().Host)
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		if !strings.HasSuffix(host, suffix) {
+			return ""
+		}
+
+		sub := strings.TrimSuffix(host, suffix)
+		if strings.Contains(sub, ".") {
+			return ""
+		}
+
+		return sub
+	}
+}
+
+// FromHeader resolves tenant from the request header, default is X-Tenant-ID.
+func FromHeader(name string) Resolver {
+	if name == "" {
+		name = HeaderXTenantID
+	}
+
+	return func(c *rest.Context) string {
+		return c.Request().Header.Get(name)
+	}
+}
+
+// FromClaim resolves tenant from claim of the jwt set by mw.JWT,
+// so it should be registered after the JWT middleware.
+func FromClaim(claim string) Resolver {
+	return func(c *rest.Context) string {
+		t, ok := c.Get("user").(*jwt.Token)
+		if !ok {
+			return ""
+		}
+
+		if mc, ok := t.Claims.(jwt.MapClaims); ok {
+			switch v := mc[claim].(type) {
+			case string:
+				return v
+			case float64:
+				return fmt.Sprintf("%.0f", v)
+			}
+		}
+
+		return ""
+	}
+}
+
+// FromPrincipal resolves tenant from auth.Principal set by mw.JWT with ClaimsMapper,
+// so it should be registered after the JWT middleware.
+func FromPrincipal() Resolver {
+	return func(c *rest.Context) string {
+		if p := auth.Get(c); p != nil {
+			return p.TenantID
+		}
+
+		return ""
+	}
+}
+
+// Chain returns the first tenant resolved by the resolvers.
+func Chain(resolvers ...Resolver) Resolver {
+	return func(c *rest.Context) string {
+		for _, r := range resolvers {
+			if id := r(c); id != "" {
+				return id
+			}
+		}
+
+		return ""
+	}
+}
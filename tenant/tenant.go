@@ -0,0 +1,76 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package tenant
+
+import (
+	"context"
+	"errors"
+	"regexp"
+)
+
+var (
+	// ErrNotFound returned by store when the tenant is not exists.
+	ErrNotFound = errors.New("tenant: not found")
+
+	// ErrNoTenant returned when the context doesn't have tenant.
+	ErrNoTenant = errors.New("tenant: no tenant in context")
+
+	validID = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+)
+
+type (
+	// Tenant of the application.
+	Tenant struct {
+		ID     string                 `json:"id"`
+		Name   string                 `json:"name"`
+		Schema string                 `json:"schema,omitempty"`
+		DSN    string                 `json:"-"`
+		Meta   map[string]interface{} `json:"meta,omitempty"`
+	}
+
+	// Store finds the tenant by its id.
+	Store interface {
+		Find(ctx context.Context, id string) (*Tenant, error)
+	}
+
+	// StoreFunc is an adapter to use function as Store.
+	StoreFunc func(ctx context.Context, id string) (*Tenant, error)
+
+	// StaticStore is store of fixed tenants keyed by id.
+	StaticStore map[string]*Tenant
+
+	tenantKey struct{}
+)
+
+// Find calls f(ctx, id).
+func (f StoreFunc) Find(ctx context.Context, id string) (*Tenant, error) {
+	return f(ctx, id)
+}
+
+// Find returns the tenant of the id.
+func (s StaticStore) Find(_ context.Context, id string) (*Tenant, error) {
+	if t, ok := s[id]; ok {
+		return t, nil
+	}
+
+	return nil, ErrNotFound
+}
+
+// ValidID returns true when the id is safe to be used as key prefix
+// or schema name, only letters, numbers, _ and - are allowed.
+func ValidID(id string) bool {
+	return validID.MatchString(id)
+}
+
+// WithTenant returns context holding the tenant.
+func WithTenant(ctx context.Context, t *Tenant) context.Context {
+	return context.WithValue(ctx, tenantKey{}, t)
+}
+
+// FromContext returns tenant of the context.
+func FromContext(ctx context.Context) (*Tenant, bool) {
+	t, ok := ctx.Value(tenantKey{}).(*Tenant)
+	return t, ok && t != nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package tenant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/enigma-id/go/auth"
+	"github.com/enigma-id/go/cache"
+	"github.com/enigma-id/go/db"
+	"github.com/enigma-id/go/rest"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+var store = StaticStore{
+	"acme":   {ID: "acme", Name: "Acme", Schema: "acme"},
+	"globex": {ID: "globex", Name: "Globex"},
+}
+
+func TestMiddleware(t *testing.T) {
+	e := rest.New()
+	h := Middleware(store, FromSubdomain("example.com"), FromHeader(""), FromClaim("tenant"))(func(c *rest.Context) error {
+		ct, _ := FromContext(c.Request().Context())
+		assert.Equal(t, Get(c), ct)
+		return c.String(http.StatusOK, ct.Name)
+	})
+
+	serve := func(host string, header string, claim string) (int, string) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Host = host
+		if header != "" {
+			req.Header.Set(HeaderXTenantID, header)
+		}
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if claim != "" {
+			c.Set("user", &jwt.Token{Claims: jwt.MapClaims{"tenant": claim}})
+		}
+
+		if err := h(c); err != nil {
+			return err.(*rest.HTTPError).Code, ""
+		}
+		return rec.Code, rec.Body.String()
+	}
+
+	code, body := serve("acme.example.com:8080", "", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "Acme", body)
+
+	_, body = serve("api.other.com", "globex", "")
+	assert.Equal(t, "Globex", body)
+
+	_, body = serve("a.b.example.com", "", "acme")
+	assert.Equal(t, "Acme", body)
+
+	code, _ = serve("example.com", "", "")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = serve("unknown.example.com", "", "")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	code, _ = serve("", "../etc", "")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestFromPrincipal(t *testing.T) {
+	c := rest.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	assert.Empty(t, FromPrincipal()(c))
+
+	auth.Set(c, &auth.Principal{ID: "1", TenantID: "acme"})
+	assert.Equal(t, "acme", FromPrincipal()(c))
+}
+
+func TestCache(t *testing.T) {
+	mc := cache.NewMemory()
+	cache.Instance = mc
+
+	_, err := CacheFrom(context.Background())
+	assert.Equal(t, ErrNoTenant, err)
+
+	c, _ := CacheFrom(WithTenant(context.Background(), store["acme"]))
+	c.Set("user:1", "John", 0)
+
+	var v string
+	assert.NoError(t, mc.Get("tenant:acme:user:1", &v))
+	assert.Equal(t, "John", v)
+
+	assert.NoError(t, c.Get("user:1", &v))
+	assert.Equal(t, cache.ErrCacheMiss, NewCache(mc, store["globex"]).Get("user:1", &v))
+
+	g, _ := c.GetMulti("user:1")
+	v = ""
+	assert.NoError(t, g.Get("user:1", &v))
+	assert.Equal(t, "John", v)
+
+	assert.Equal(t, ErrFlushNotSupported, c.Flush())
+}
+
+func TestDB(t *testing.T) {
+	conns := NewConnections(func(t *Tenant) (*db.DB, error) {
+		return db.Open("sqlite3", "file:"+t.ID+"?mode=memory&cache=shared")
+	})
+	defer conns.CloseAll()
+
+	ctx := WithTenant(context.Background(), store["acme"])
+	d, err := conns.Get(ctx)
+	assert.NoError(t, err)
+
+	d2, _ := conns.Get(ctx)
+	assert.Equal(t, d, d2)
+
+	d3, _ := conns.For(store["globex"])
+	assert.NotEqual(t, d, d3)
+
+	_, err = conns.Get(context.Background())
+	assert.Equal(t, ErrNoTenant, err)
+
+	// sqlite doesn't support schema
+	assert.Error(t, InTx(ctx, d, func(ctx context.Context, tx *db.Tx) error { return nil }))
+
+	defer func(fn func(string, string) (string, error)) { SchemaSQL = fn }(SchemaSQL)
+	var selected string
+	SchemaSQL = func(driver string, schema string) (string, error) {
+		selected = schema
+		return "SELECT 1", nil
+	}
+
+	assert.NoError(t, InTx(ctx, d, func(ctx context.Context, tx *db.Tx) error { return nil }))
+	assert.Equal(t, "acme", selected)
+}
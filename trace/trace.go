@@ -0,0 +1,209 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package trace records a per-request trace of middleware, bind, validate,
+// cache and downstream calls, used for diagnosing slow requests.
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Kinds of the span.
+const (
+	KindMiddleware = "middleware"
+	KindHandler    = "handler"
+	KindBind       = "bind"
+	KindValidate   = "validate"
+	KindCache      = "cache"
+	KindHTTP       = "http"
+)
+
+type contextKey struct{}
+
+type (
+	// Trace collects spans of a request, it's safe for concurrent use.
+	Trace struct {
+		mu    sync.Mutex
+		start time.Time
+		spans []*Span
+	}
+
+	// Span is a step of the request, offset is relative to start of the trace.
+	Span struct {
+		Kind     string                 `json:"kind"`
+		Name     string                 `json:"name"`
+		Offset   time.Duration          `json:"offset"`
+		Duration time.Duration          `json:"duration"`
+		Attrs    map[string]interface{} `json:"attrs,omitempty"`
+
+		trace *Trace
+		start time.Time
+		done  bool
+	}
+)
+
+// New creates trace started now.
+func New() *Trace {
+	return &Trace{start: time.Now()}
+}
+
+// WithTrace returns copy of the context that carries the trace.
+func WithTrace(ctx context.Context, t *Trace) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns trace of the context, nil when tracing is off.
+func FromContext(ctx context.Context) *Trace {
+	if ctx == nil {
+		return nil
+	}
+	t, _ := ctx.Value(contextKey{}).(*Trace)
+	return t
+}
+
+// Start starts span on the trace of the context, it returns nil span
+// when tracing is off, which is safe to End.
+func Start(ctx context.Context, kind, name string) *Span {
+	return FromContext(ctx).Start(kind, name)
+}
+
+// Event records span without duration on the trace of the context.
+func Event(ctx context.Context, kind, name string, attrs map[string]interface{}) {
+	s := Start(ctx, kind, name)
+	for k, v := range attrs {
+		s.Set(k, v)
+	}
+	s.End()
+}
+
+// Start starts span on the trace.
+func (t *Trace) Start(kind, name string) *Span {
+	if t == nil {
+		return nil
+	}
+
+	now := time.Now()
+	s := &Span{Kind: kind, Name: name, Offset: now.Sub(t.start), trace: t, start: now}
+
+	t.mu.Lock()
+	t.spans = append(t.spans, s)
+	t.mu.Unlock()
+
+	return s
+}
+
+// Spans returns copy of the recorded spans, span that is not ended
+// yet has the duration so far.
+func (t *Trace) Spans() []Span {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	spans := make([]Span, len(t.spans))
+	for i, s := range t.spans {
+		spans[i] = Span{Kind: s.Kind, Name: s.Name, Offset: s.Offset, Duration: s.Duration}
+		if len(s.Attrs) > 0 {
+			spans[i].Attrs = make(map[string]interface{}, len(s.Attrs))
+			for k, v := range s.Attrs {
+				spans[i].Attrs[k] = v
+			}
+		}
+		if !s.done {
+			spans[i].Duration = time.Since(s.start)
+		}
+	}
+
+	return spans
+}
+
+// Elapsed returns duration since the trace started.
+func (t *Trace) Elapsed() time.Duration {
+	return time.Since(t.start)
+}
+
+// MarshalJSON writes the spans and the total duration, durations are in microseconds.
+func (t *Trace) MarshalJSON() ([]byte, error) {
+	type span struct {
+		Kind     string                 `json:"kind"`
+		Name     string                 `json:"name"`
+		Offset   int64                  `json:"offset_us"`
+		Duration int64                  `json:"duration_us"`
+		Attrs    map[string]interface{} `json:"attrs,omitempty"`
+	}
+
+	spans := t.Spans()
+	out := struct {
+		Total int64  `json:"total_us"`
+		Spans []span `json:"spans"`
+	}{Total: t.Elapsed().Microseconds(), Spans: make([]span, len(spans))}
+
+	for i, s := range spans {
+		out.Spans[i] = span{s.Kind, s.Name, s.Offset.Microseconds(), s.Duration.Microseconds(), s.Attrs}
+	}
+
+	return json.Marshal(out)
+}
+
+// Set sets attribute of the span.
+func (s *Span) Set(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+
+	s.trace.mu.Lock()
+	if s.Attrs == nil {
+		s.Attrs = make(map[string]interface{})
+	}
+	s.Attrs[key] = value
+	s.trace.mu.Unlock()
+}
+
+// End ends the span, the error if any is recorded as attribute.
+func (s *Span) End(err ...error) {
+	if s == nil {
+		return
+	}
+
+	if len(err) > 0 && err[0] != nil {
+		s.Set("error", err[0].Error())
+	}
+
+	s.trace.mu.Lock()
+	s.Duration = time.Since(s.start)
+	s.done = true
+	s.trace.mu.Unlock()
+}
+
+// Transport wraps the round tripper to record the downstream calls
+// on the trace of the request context, ex. client.WithMiddleware(trace.Transport).
+func Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return roundTripper{next}
+}
+
+type roundTripper struct {
+	next http.RoundTripper
+}
+
+func (rt roundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	s := Start(r.Context(), KindHTTP, r.Method+" "+r.URL.Host+r.URL.Path)
+	res, err := rt.next.RoundTrip(r)
+	if res != nil {
+		s.Set("status", res.StatusCode)
+	}
+	s.End(err)
+
+	return res, err
+}
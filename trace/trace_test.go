@@ -0,0 +1,65 @@
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrace(t *testing.T) {
+	ctx := context.Background()
+
+	// off, all noop
+	s := Start(ctx, KindBind, "bind")
+	assert.Nil(t, s)
+	s.Set("a", 1)
+	s.End(errors.New("x"))
+	Event(ctx, KindCache, "get", nil)
+
+	tr := New()
+	ctx = WithTrace(ctx, tr)
+	assert.Equal(t, tr, FromContext(ctx))
+
+	s = Start(ctx, KindBind, "bind")
+	open := Start(ctx, KindHandler, "handler")
+	s.End(errors.New("invalid"))
+	Event(ctx, KindCache, "users:1", map[string]interface{}{"hit": true})
+
+	spans := tr.Spans()
+	assert.Len(t, spans, 3)
+	assert.Equal(t, "bind", spans[0].Name)
+	assert.Equal(t, "invalid", spans[0].Attrs["error"])
+	assert.True(t, spans[1].Duration > 0)
+	assert.Equal(t, true, spans[2].Attrs["hit"])
+	open.End()
+
+	b, err := json.Marshal(tr)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"total_us"`)
+	assert.Contains(t, string(b), `"kind":"cache","name":"users:1"`)
+}
+
+func TestTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer srv.Close()
+
+	tr := New()
+	req, _ := http.NewRequestWithContext(WithTrace(context.Background(), tr), http.MethodGet, srv.URL+"/users", nil)
+	res, err := (&http.Client{Transport: Transport(nil)}).Do(req)
+	assert.NoError(t, err)
+	res.Body.Close()
+
+	spans := tr.Spans()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, KindHTTP, spans[0].Kind)
+		assert.Equal(t, "GET "+req.URL.Host+"/users", spans[0].Name)
+		assert.Equal(t, http.StatusTeapot, spans[0].Attrs["status"])
+	}
+}
@@ -13,6 +13,11 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
+// Level is the shared level of the loggers made by New, it can be
+// changed at runtime, ex. Level.SetLevel(zap.DebugLevel). The loggers
+// of dev mode have their own debug level, so they don't change it.
+var Level = zap.NewAtomicLevelAt(zap.InfoLevel)
+
 // New making new instances
 func New(name string, dev bool) (l *zap.Logger) {
 	l = mode(os.Getenv("APP_MODE") == "DEV" || dev)
@@ -22,7 +27,7 @@ func New(name string, dev bool) (l *zap.Logger) {
 
 func mode(isDev bool) (l *zap.Logger) {
 	cfg := zap.Config{
-		Level:            zap.NewAtomicLevelAt(zap.InfoLevel),
+		Level:            Level,
 		Development:      false,
 		Encoding:         "json",
 		OutputPaths:      []string{"stderr"},
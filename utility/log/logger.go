@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/enigma-id/go/utility"
 	"go.uber.org/zap"
 )
 
@@ -73,3 +74,9 @@ func Warnw(msg string, kv ...interface{}) {
 func Errorw(msg string, kv ...interface{}) {
 	Logger.Sugar().Errorw(msg, kv...)
 }
+
+// Redacted constructs a field with the value redacted by utility.Redact,
+// so sensitive data on the struct is never written into the logs.
+func Redacted(key string, value interface{}) zap.Field {
+	return zap.Any(key, utility.Redact(value))
+}
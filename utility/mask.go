@@ -0,0 +1,181 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package utility
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// MaskChar character used to mask sensitive values.
+var MaskChar = "*"
+
+// maxRedactDepth limit of nested value that will be traversed by Redact.
+const maxRedactDepth = 32
+
+// MaskEmail masks local part of the email address, leaving the
+// first and last character and the domain visible.
+// Ex.: john.doe@gmail.com => j******e@gmail.com
+func MaskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return MaskString(email, 1, 0)
+	}
+
+	local, domain := email[:at], email[at:]
+	if len(local) <= 2 {
+		return MaskString(local, 1, 0) + domain
+	}
+
+	return MaskString(local, 1, 1) + domain
+}
+
+// MaskPhone masks the phone number, leaving the first 4 digit
+// (operator prefix) and last 3 digit visible.
+// Ex.: 081234567890 => 0812*****890
+func MaskPhone(phone string) string {
+	if len(phone) < 8 {
+		return MaskString(phone, 0, 2)
+	}
+
+	return MaskString(phone, 4, 3)
+}
+
+// MaskPAN masks card number (primary account number), only the first 6
+// and last 4 digit are kept as allowed by PCI-DSS, any spaces or dashes are removed.
+// Ex.: 4111 1111 1111 1111 => 411111******1111
+func MaskPAN(pan string) string {
+	pan = strings.NewReplacer(" ", "", "-", "").Replace(pan)
+	if len(pan) < 13 {
+		return MaskString(pan, 0, 4)
+	}
+
+	return MaskString(pan, 6, 4)
+}
+
+// MaskString replaces characters of s with MaskChar except for the
+// first n and last m characters.
+func MaskString(s string, first, last int) string {
+	r := []rune(s)
+	if first+last >= len(r) {
+		first, last = 0, 0
+		if len(r) > 1 {
+			first = 1
+		}
+	}
+
+	return string(r[:first]) + strings.Repeat(MaskChar, len(r)-first-last) + string(r[len(r)-last:])
+}
+
+// Redact returns a copy of the value given with every struct field tagged
+// `redact:"true"` set into its zero value, fields tagged with `redact:"email"`,
+// `redact:"phone"` or `redact:"pan"` are masked instead.
+// Nested struct, pointer, slice and map are traversed, the original value
+// is never modified so its safe to be used before writing logs.
+//
+//	type User struct {
+//	  Name     string
+//	  Email    string `redact:"email"`
+//	  Password string `redact:"true"`
+//	}
+func Redact(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	return redactValue(reflect.ValueOf(value), 0).Interface()
+}
+
+func redactValue(v reflect.Value, depth int) reflect.Value {
+	if depth > maxRedactDepth {
+		return v
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		p := reflect.New(v.Elem().Type())
+		p.Elem().Set(redactValue(v.Elem(), depth+1))
+		return p
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		i := reflect.New(v.Type()).Elem()
+		i.Set(redactValue(v.Elem(), depth+1))
+		return i
+
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(time.Time{}) {
+			return v
+		}
+		s := reflect.New(v.Type()).Elem()
+		s.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			sf := v.Type().Field(i)
+			if sf.PkgPath != "" {
+				continue
+			}
+
+			f := s.Field(i)
+			switch tag := sf.Tag.Get("redact"); tag {
+			case "", "false":
+				f.Set(redactValue(f, depth+1))
+			default:
+				f.Set(redactField(f, tag))
+			}
+		}
+		return s
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		s := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			s.Index(i).Set(redactValue(v.Index(i), depth+1))
+		}
+		return s
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		m := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for _, k := range v.MapKeys() {
+			m.SetMapIndex(k, redactValue(v.MapIndex(k), depth+1))
+		}
+		return m
+	}
+
+	return v
+}
+
+// redactField blank or mask the field based on the redact tag.
+func redactField(f reflect.Value, tag string) reflect.Value {
+	if f.Kind() == reflect.String && f.Len() > 0 {
+		var masked string
+		switch tag {
+		case "email":
+			masked = MaskEmail(f.String())
+		case "phone":
+			masked = MaskPhone(f.String())
+		case "pan":
+			masked = MaskPAN(f.String())
+		}
+
+		if masked != "" {
+			s := reflect.New(f.Type()).Elem()
+			s.SetString(masked)
+			return s
+		}
+	}
+
+	return reflect.Zero(f.Type())
+}
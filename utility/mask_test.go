@@ -0,0 +1,86 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package utility_test
+
+import (
+	"testing"
+
+	"github.com/enigma-id/go/utility"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMask(t *testing.T) {
+	var tests = []struct {
+		fn       func(string) string
+		param    string
+		expected string
+	}{
+		{utility.MaskEmail, "john.doe@gmail.com", "j******e@gmail.com"},
+		{utility.MaskEmail, "jo@gmail.com", "j*@gmail.com"},
+		{utility.MaskEmail, "invalid", "i******"},
+		{utility.MaskPhone, "081234567890", "0812*****890"},
+		{utility.MaskPhone, "+6281234567890", "+628*******890"},
+		{utility.MaskPhone, "12345", "***45"},
+		{utility.MaskPAN, "4111 1111 1111 1111", "411111******1111"},
+		{utility.MaskPAN, "4111-1111", "****1111"},
+		{utility.MaskEmail, "", ""},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.fn(test.param), test.param)
+	}
+}
+
+type redactAddress struct {
+	Street string
+	Phone  string `redact:"phone"`
+}
+
+type redactUser struct {
+	Name      string
+	Email     string `redact:"email"`
+	Password  string `redact:"true"`
+	Pin       int    `redact:"true"`
+	Card      string `redact:"pan"`
+	Address   *redactAddress
+	Addresses []redactAddress
+	Meta      map[string]interface{}
+	private   string
+}
+
+func TestRedact(t *testing.T) {
+	u := &redactUser{
+		Name:      "John",
+		Email:     "john.doe@gmail.com",
+		Password:  "secret",
+		Pin:       1234,
+		Card:      "4111111111111111",
+		Address:   &redactAddress{Street: "Jl. Sudirman", Phone: "081234567890"},
+		Addresses: []redactAddress{{Street: "Jl. Thamrin", Phone: "081298765432"}},
+		Meta:      map[string]interface{}{"user": redactUser{Password: "other"}},
+		private:   "kept",
+	}
+
+	r, ok := utility.Redact(u).(*redactUser)
+	assert.True(t, ok)
+	assert.Equal(t, "John", r.Name)
+	assert.Equal(t, "j******e@gmail.com", r.Email)
+	assert.Equal(t, "", r.Password)
+	assert.Equal(t, 0, r.Pin)
+	assert.Equal(t, "411111******1111", r.Card)
+	assert.Equal(t, "0812*****890", r.Address.Phone)
+	assert.Equal(t, "0812*****432", r.Addresses[0].Phone)
+	assert.Equal(t, "", r.Meta["user"].(redactUser).Password)
+	assert.Equal(t, "kept", r.private)
+
+	// original value should not be modified
+	assert.Equal(t, "secret", u.Password)
+	assert.Equal(t, "081234567890", u.Address.Phone)
+	assert.Equal(t, "other", u.Meta["user"].(redactUser).Password)
+
+	assert.Nil(t, utility.Redact(nil))
+	assert.Equal(t, "plain", utility.Redact("plain"))
+}
@@ -0,0 +1,170 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package sigv4 signing http request with aws signature version 4,
+// it is used by the drivers talking into aws compatible api (ses, s3, minio, gcs interoperability)
+// without pulling the whole aws sdk.
+package sigv4
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	algorithm = "AWS4-HMAC-SHA256"
+	amzFormat = "20060102T150405Z"
+
+	// UnsignedPayload payload hash used when the body is not signed.
+	UnsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// Signer holds the credentials and scope used to sign the requests.
+type Signer struct {
+	AccessKey string
+	SecretKey string
+	Region    string
+	Service   string
+}
+
+// Sign adds authorization header into the request, payloadHash is hex encoded
+// sha256 of the body or UnsignedPayload.
+func (s *Signer) Sign(req *http.Request, payloadHash string, t time.Time) {
+	t = t.UTC()
+	req.Header.Set("X-Amz-Date", t.Format(amzFormat))
+	if s.Service == "s3" {
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+
+	headers, canonicalHeaders := s.headers(req)
+	canonical := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		headers,
+		payloadHash,
+	}, "\n")
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		algorithm, s.AccessKey, s.scope(t), headers, s.signature(t, canonical)))
+}
+
+// Presign returns url of the request with signature on the query string,
+// that can be used without credentials until expires.
+func (s *Signer) Presign(method string, u *url.URL, expires time.Duration, t time.Time) string {
+	t = t.UTC()
+	u = &url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path, RawPath: u.RawPath, RawQuery: u.RawQuery}
+
+	q := u.Query()
+	q.Set("X-Amz-Algorithm", algorithm)
+	q.Set("X-Amz-Credential", s.AccessKey+"/"+s.scope(t))
+	q.Set("X-Amz-Date", t.Format(amzFormat))
+	q.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	q.Set("X-Amz-SignedHeaders", "host")
+
+	canonical := strings.Join([]string{
+		method,
+		canonicalURI(u),
+		canonicalQuery(q),
+		"host:" + u.Host + "\n",
+		"host",
+		UnsignedPayload,
+	}, "\n")
+
+	q.Set("X-Amz-Signature", s.signature(t, canonical))
+	u.RawQuery = canonicalQuery(q)
+
+	return u.String()
+}
+
+func (s *Signer) scope(t time.Time) string {
+	return t.Format("20060102") + "/" + s.Region + "/" + s.Service + "/aws4_request"
+}
+
+func (s *Signer) signature(t time.Time, canonical string) string {
+	toSign := algorithm + "\n" + t.Format(amzFormat) + "\n" + s.scope(t) + "\n" + HashHex([]byte(canonical))
+
+	key := []byte("AWS4" + s.SecretKey)
+	for _, v := range []string{t.Format("20060102"), s.Region, s.Service, "aws4_request"} {
+		key = hmacSHA256(key, v)
+	}
+
+	return hex.EncodeToString(hmacSHA256(key, toSign))
+}
+
+// headers returns signed header names and canonical headers,
+// only host, content-type, content-md5 and x-amz-* are signed.
+func (s *Signer) headers(req *http.Request) (string, string) {
+	h := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		k = strings.ToLower(k)
+		if k == "content-type" || k == "content-md5" || strings.HasPrefix(k, "x-amz-") {
+			h[k] = strings.TrimSpace(strings.Join(v, ","))
+		}
+	}
+
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k + ":" + h[k] + "\n")
+	}
+
+	return strings.Join(keys, ";"), b.String()
+}
+
+func canonicalURI(u *url.URL) string {
+	p := u.EscapedPath()
+	if p == "" {
+		return "/"
+	}
+
+	return p
+}
+
+func canonicalQuery(q url.Values) string {
+	return strings.Replace(q.Encode(), "+", "%20", -1)
+}
+
+// EscapePath escapes the path as required by the canonical uri,
+// every characters except unreserved characters and slash are escaped.
+func EscapePath(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
+
+// HashHex returns hex encoded sha256 of the data.
+func HashHex(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,222 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package timeutil
+
+import (
+	"sync"
+	"time"
+)
+
+// Calendar holding list of public holidays used for
+// business day arithmetic.
+//
+// National holidays with fixed date and christian holidays derived from
+// easter are calculated automatically, holidays that follow lunar calendars
+// (idul fitri, idul adha, nyepi, waisak, imlek, etc) and cuti bersama
+// are decided each year by government decree (SKB 3 Menteri),
+// so it should be registered by using Add or Load.
+type Calendar struct {
+	mu       sync.RWMutex
+	holidays map[string]string
+	skipped  map[string]bool
+}
+
+// fixedHolidays national holidays that always on the same date.
+var fixedHolidays = map[string]string{
+	"01-01": "Tahun Baru Masehi",
+	"05-01": "Hari Buruh Internasional",
+	"06-01": "Hari Lahir Pancasila",
+	"08-17": "Hari Kemerdekaan Republik Indonesia",
+	"12-25": "Hari Raya Natal",
+}
+
+// DefaultCalendar calendar used by package level helpers.
+var DefaultCalendar = NewCalendar()
+
+// NewCalendar creates new calendar instances.
+func NewCalendar() *Calendar {
+	return &Calendar{
+		holidays: make(map[string]string),
+		skipped:  make(map[string]bool),
+	}
+}
+
+// Add registers a holiday on the date given.
+func (c *Calendar) Add(date time.Time, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	k := dateKey(date)
+	c.holidays[k] = name
+	delete(c.skipped, k)
+}
+
+// Load registers holidays from map with date formatted as
+// "2006-01-02" as the key, and the name of holiday as values.
+func (c *Calendar) Load(holidays map[string]string) (err error) {
+	for d, name := range holidays {
+		var t time.Time
+		if t, err = time.ParseInLocation("2006-01-02", d, DefaultLocation); err != nil {
+			return
+		}
+
+		c.Add(t, name)
+	}
+
+	return
+}
+
+// Remove mark the date as working day, even when the date
+// is one of calculated holidays.
+func (c *Calendar) Remove(date time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	k := dateKey(date)
+	delete(c.holidays, k)
+	c.skipped[k] = true
+}
+
+// Holiday returns name of the holiday and true if date is a holiday.
+func (c *Calendar) Holiday(date time.Time) (string, bool) {
+	k := dateKey(date)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.skipped[k] {
+		return "", false
+	}
+
+	if n, ok := c.holidays[k]; ok {
+		return n, true
+	}
+
+	if n, ok := fixedHolidays[date.Format("01-02")]; ok {
+		return n, true
+	}
+
+	easter := Easter(date.Year(), date.Location())
+	switch k {
+	case dateKey(easter.AddDate(0, 0, -2)):
+		return "Wafat Isa Almasih", true
+	case dateKey(easter):
+		return "Hari Paskah", true
+	case dateKey(easter.AddDate(0, 0, 39)):
+		return "Kenaikan Isa Almasih", true
+	}
+
+	return "", false
+}
+
+// IsHoliday returns true if date is registered as holiday.
+func (c *Calendar) IsHoliday(date time.Time) bool {
+	_, ok := c.Holiday(date)
+
+	return ok
+}
+
+// IsBusinessDay returns true if date is not weekend nor holiday.
+func (c *Calendar) IsBusinessDay(date time.Time) bool {
+	return !IsWeekend(date) && !c.IsHoliday(date)
+}
+
+// AddBusinessDays adds n business days into date, negative n
+// will substract the business days instead.
+func (c *Calendar) AddBusinessDays(date time.Time, n int) time.Time {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+
+	for n > 0 {
+		date = date.AddDate(0, 0, step)
+		if c.IsBusinessDay(date) {
+			n--
+		}
+	}
+
+	return date
+}
+
+// NextBusinessDay returns date when its a business day,
+// otherwise returns the next business day after date.
+func (c *Calendar) NextBusinessDay(date time.Time) time.Time {
+	for !c.IsBusinessDay(date) {
+		date = date.AddDate(0, 0, 1)
+	}
+
+	return date
+}
+
+// BusinessDaysBetween counts business days from start until end,
+// start date is excluded and end date is included.
+func (c *Calendar) BusinessDaysBetween(start, end time.Time) (n int) {
+	sign := 1
+	if end.Before(start) {
+		start, end = end, start
+		sign = -1
+	}
+
+	start = StartOfDay(start, start.Location())
+	end = StartOfDay(end, start.Location())
+	for d := start.AddDate(0, 0, 1); !d.After(end); d = d.AddDate(0, 0, 1) {
+		if c.IsBusinessDay(d) {
+			n++
+		}
+	}
+
+	return n * sign
+}
+
+// Easter returns easter sunday of the year, calculated
+// using anonymous gregorian algorithm (Meeus/Jones/Butcher).
+func Easter(year int, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = DefaultLocation
+	}
+
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := ((h + l - 7*m + 114) % 31) + 1
+
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, loc)
+}
+
+// IsHoliday returns true if date is holiday on the DefaultCalendar.
+func IsHoliday(date time.Time) bool {
+	return DefaultCalendar.IsHoliday(date)
+}
+
+// IsBusinessDay returns true if date is business day on the DefaultCalendar.
+func IsBusinessDay(date time.Time) bool {
+	return DefaultCalendar.IsBusinessDay(date)
+}
+
+// AddBusinessDays adds n business days into date using the DefaultCalendar.
+func AddBusinessDays(date time.Time, n int) time.Time {
+	return DefaultCalendar.AddBusinessDays(date, n)
+}
+
+// NextBusinessDay returns the nearest business day using the DefaultCalendar.
+func NextBusinessDay(date time.Time) time.Time {
+	return DefaultCalendar.NextBusinessDay(date)
+}
+
+// BusinessDaysBetween counts business days using the DefaultCalendar.
+func BusinessDaysBetween(start, end time.Time) int {
+	return DefaultCalendar.BusinessDaysBetween(start, end)
+}
@@ -0,0 +1,97 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package timeutil
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/enigma-id/go/utility/now"
+)
+
+// Period type of range periods.
+type Period string
+
+// Available periods
+const (
+	Daily   Period = "daily"
+	Weekly  Period = "weekly"
+	Monthly Period = "monthly"
+)
+
+// ParsePeriod parsing period from string, usually from query params.
+func ParsePeriod(s string) (Period, error) {
+	switch p := Period(s); p {
+	case Daily, Weekly, Monthly:
+		return p, nil
+	}
+
+	return "", fmt.Errorf("timeutil: unknown period %q", s)
+}
+
+// Start returns beginning of the period that contains t.
+func (p Period) Start(t time.Time, loc *time.Location) time.Time {
+	switch p {
+	case Weekly:
+		return StartOfWeek(t, loc)
+	case Monthly:
+		return StartOfMonth(t, loc)
+	}
+
+	return StartOfDay(t, loc)
+}
+
+// Next returns beginning of the next period after t.
+func (p Period) Next(t time.Time, loc *time.Location) time.Time {
+	s := p.Start(t, loc)
+	switch p {
+	case Weekly:
+		return s.AddDate(0, 0, 7)
+	case Monthly:
+		return s.AddDate(0, 1, 0)
+	}
+
+	return s.AddDate(0, 0, 1)
+}
+
+// Ranges generates list of time ranges between start and end
+// for each period, first and last range is clipped into
+// start and end so it can be used directly as query filter.
+func Ranges(start, end time.Time, p Period, loc *time.Location) (r []now.TimeRange) {
+	start = In(start, loc)
+	end = In(end, loc)
+	if end.Before(start) {
+		return
+	}
+
+	for s := start; !s.After(end); {
+		next := p.Next(s, loc)
+		e := next.Add(-time.Nanosecond)
+		if e.After(end) {
+			e = end
+		}
+
+		r = append(r, now.TimeRange{Start: s, End: e})
+		s = next
+	}
+
+	return
+}
+
+// DailyRanges generates daily ranges between start and end.
+func DailyRanges(start, end time.Time, loc *time.Location) []now.TimeRange {
+	return Ranges(start, end, Daily, loc)
+}
+
+// WeeklyRanges generates weekly ranges between start and end,
+// weeks are started on monday.
+func WeeklyRanges(start, end time.Time, loc *time.Location) []now.TimeRange {
+	return Ranges(start, end, Weekly, loc)
+}
+
+// MonthlyRanges generates monthly ranges between start and end.
+func MonthlyRanges(start, end time.Time, loc *time.Location) []now.TimeRange {
+	return Ranges(start, end, Monthly, loc)
+}
@@ -0,0 +1,101 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package timeutil
+
+import (
+	"strings"
+	"time"
+)
+
+// Indonesian time zones, indonesia doesn't observe daylight saving
+// so fixed zones are used to avoid relying on tzdata of the host.
+var (
+	// WIB Waktu Indonesia Barat (Sumatera, Jawa, Kalimantan Barat & Tengah).
+	WIB = time.FixedZone("WIB", 7*60*60)
+
+	// WITA Waktu Indonesia Tengah (Bali, Nusa Tenggara, Sulawesi, Kalimantan Timur & Selatan).
+	WITA = time.FixedZone("WITA", 8*60*60)
+
+	// WIT Waktu Indonesia Timur (Maluku, Papua).
+	WIT = time.FixedZone("WIT", 9*60*60)
+
+	// DefaultLocation location used by helpers when location is not given.
+	DefaultLocation = WIB
+)
+
+// Zone returns indonesian time zone by its abbreviation or
+// IANA name, unknown zone will fallback to DefaultLocation.
+func Zone(name string) *time.Location {
+	switch strings.ToUpper(name) {
+	case "WIB", "ASIA/JAKARTA", "ASIA/PONTIANAK":
+		return WIB
+	case "WITA", "ASIA/MAKASSAR":
+		return WITA
+	case "WIT", "ASIA/JAYAPURA":
+		return WIT
+	}
+
+	return DefaultLocation
+}
+
+// In converts the time into location given, or the DefaultLocation
+// when location is nil.
+func In(t time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = DefaultLocation
+	}
+
+	return t.In(loc)
+}
+
+// StartOfDay returns 00:00:00 of the day of t in the location given.
+func StartOfDay(t time.Time, loc *time.Location) time.Time {
+	t = In(t, loc)
+
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// EndOfDay returns the last nanosecond of the day of t in the location given.
+func EndOfDay(t time.Time, loc *time.Location) time.Time {
+	return StartOfDay(t, loc).AddDate(0, 0, 1).Add(-time.Nanosecond)
+}
+
+// StartOfWeek returns monday 00:00:00 of the week of t in the location given.
+func StartOfWeek(t time.Time, loc *time.Location) time.Time {
+	t = StartOfDay(t, loc)
+	weekday := int(t.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+
+	return t.AddDate(0, 0, 1-weekday)
+}
+
+// EndOfWeek returns the last nanosecond of sunday of the week of t.
+func EndOfWeek(t time.Time, loc *time.Location) time.Time {
+	return StartOfWeek(t, loc).AddDate(0, 0, 7).Add(-time.Nanosecond)
+}
+
+// StartOfMonth returns the first day 00:00:00 of the month of t.
+func StartOfMonth(t time.Time, loc *time.Location) time.Time {
+	t = In(t, loc)
+
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// EndOfMonth returns the last nanosecond of the month of t.
+func EndOfMonth(t time.Time, loc *time.Location) time.Time {
+	return StartOfMonth(t, loc).AddDate(0, 1, 0).Add(-time.Nanosecond)
+}
+
+// IsWeekend returns true when t is saturday or sunday.
+func IsWeekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
+
+// dateKey format the date as key of calendars.
+func dateKey(t time.Time) string {
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,89 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package timeutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var format = "2006-01-02 15:04:05.999999999 MST"
+
+func TestStartEndOfDay(t *testing.T) {
+	// 2019-03-10 20:00 UTC is already 11 march on WIB
+	n := time.Date(2019, 3, 10, 20, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "2019-03-11 00:00:00 WIB", StartOfDay(n, WIB).Format(format))
+	assert.Equal(t, "2019-03-11 23:59:59.999999999 WIB", EndOfDay(n, WIB).Format(format))
+	assert.Equal(t, "2019-03-11 00:00:00 WIT", StartOfDay(n, WIT).Format(format))
+	assert.Equal(t, "2019-03-11 00:00:00 WIB", StartOfDay(n, nil).Format(format))
+	assert.Equal(t, "2019-03-11 00:00:00 WIB", StartOfWeek(n, WIB).Format(format))
+	assert.Equal(t, "2019-03-01 00:00:00 WITA", StartOfMonth(n, WITA).Format(format))
+	assert.Equal(t, "2019-03-31 23:59:59.999999999 WITA", EndOfMonth(n, WITA).Format(format))
+
+	assert.Equal(t, WITA, Zone("Asia/Makassar"))
+	assert.Equal(t, WIT, Zone("wit"))
+	assert.Equal(t, WIB, Zone("unknown"))
+}
+
+func TestCalendar(t *testing.T) {
+	c := NewCalendar()
+
+	assert.True(t, c.IsHoliday(time.Date(2019, 8, 17, 0, 0, 0, 0, WIB)))
+	assert.True(t, c.IsHoliday(time.Date(2019, 4, 19, 0, 0, 0, 0, WIB)))
+	assert.True(t, c.IsHoliday(time.Date(2019, 5, 30, 0, 0, 0, 0, WIB)))
+	assert.False(t, c.IsHoliday(time.Date(2019, 6, 5, 0, 0, 0, 0, WIB)))
+
+	assert.NoError(t, c.Load(map[string]string{"2019-06-05": "Hari Raya Idul Fitri"}))
+	n, ok := c.Holiday(time.Date(2019, 6, 5, 0, 0, 0, 0, WIB))
+	assert.True(t, ok)
+	assert.Equal(t, "Hari Raya Idul Fitri", n)
+	assert.Error(t, c.Load(map[string]string{"05/06/2019": "Invalid"}))
+
+	c.Remove(time.Date(2019, 8, 17, 0, 0, 0, 0, WIB))
+	assert.False(t, c.IsHoliday(time.Date(2019, 8, 17, 0, 0, 0, 0, WIB)))
+
+	assert.Equal(t, "2019-04-21", dateKey(Easter(2019, WIB)))
+	assert.Equal(t, "2025-04-20", dateKey(Easter(2025, nil)))
+}
+
+func TestBusinessDays(t *testing.T) {
+	c := NewCalendar()
+
+	// thursday before good friday 2019
+	d := time.Date(2019, 4, 18, 10, 0, 0, 0, WIB)
+	assert.Equal(t, "2019-04-22", dateKey(c.AddBusinessDays(d, 1)))
+	assert.Equal(t, "2019-04-17", dateKey(c.AddBusinessDays(d, -1)))
+	assert.Equal(t, "2019-04-22", dateKey(c.NextBusinessDay(d.AddDate(0, 0, 1))))
+	assert.Equal(t, 1, c.BusinessDaysBetween(d, d.AddDate(0, 0, 4)))
+	assert.Equal(t, -1, c.BusinessDaysBetween(d.AddDate(0, 0, 4), d))
+	assert.True(t, IsBusinessDay(d))
+}
+
+func TestRanges(t *testing.T) {
+	start := time.Date(2019, 1, 30, 8, 0, 0, 0, WIB)
+	end := time.Date(2019, 3, 2, 8, 0, 0, 0, WIB)
+
+	r := MonthlyRanges(start, end, WIB)
+	if assert.Len(t, r, 3) {
+		assert.Equal(t, start, r[0].Start)
+		assert.Equal(t, "2019-01-31 23:59:59.999999999 WIB", r[0].End.Format(format))
+		assert.Equal(t, "2019-02-01 00:00:00 WIB", r[1].Start.Format(format))
+		assert.Equal(t, end, r[2].End)
+	}
+
+	assert.Len(t, DailyRanges(start, end, WIB), 32)
+	assert.Len(t, WeeklyRanges(start, end, WIB), 5)
+	assert.Len(t, Ranges(end, start, Daily, WIB), 0)
+
+	p, err := ParsePeriod("weekly")
+	assert.NoError(t, err)
+	assert.Equal(t, Weekly, p)
+
+	_, err = ParsePeriod("yearly")
+	assert.Error(t, err)
+}
@@ -6,17 +6,22 @@ package validation
 
 import (
 	"encoding/json"
+	"math"
+	"net"
 	"net/url"
 	"reflect"
-	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"unicode/utf8"
 
 	"github.com/enigma-id/go/utility"
 )
 
+// timezones caches the names of the time zones loaded.
+var timezones sync.Map
+
 // IsNotEmpty returns true if value is not nill
 func IsNotEmpty(value interface{}) bool {
 	if value == nil {
@@ -63,7 +68,7 @@ func IsNotEmpty(value interface{}) bool {
 		return !t.IsZero() && t != tt
 	}
 	v := reflect.ValueOf(value)
-	if v.Kind() == reflect.Slice {
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Map || v.Kind() == reflect.String {
 		return v.Len() > 0
 	}
 	return true
@@ -125,24 +130,56 @@ func IsEmail(value interface{}) bool {
 	return patternEmail.MatchString(utility.ToString(value))
 }
 
-// IsLatitude check if the value is an latitude.
+// IsLatitude check if the value is a latitude between -90 and 90,
+// as number or numeric string. Empty string is valid.
 func IsLatitude(value interface{}) bool {
-	str := utility.ToString(value)
+	return isCoordinate(value, 90)
+}
+
+// IsLongitude check if the value is a longitude between -180 and 180,
+// as number or numeric string. Empty string is valid.
+func IsLongitude(value interface{}) bool {
+	return isCoordinate(value, 180)
+}
+
+func isCoordinate(value interface{}, limit float64) bool {
+	rv := reflect.Indirect(reflect.ValueOf(value))
+	if !rv.IsValid() {
+		return true
+	}
+
+	str := decimalString(rv.Interface())
 	if !IsNotEmpty(str) {
 		return true
 	}
 
-	return patternLatitude.MatchString(utility.ToString(value))
+	f, err := strconv.ParseFloat(str, 64)
+	return err == nil && !math.IsNaN(f) && f >= -limit && f <= limit
 }
 
-// IsLongitude check if the value is an longitude.
-func IsLongitude(value interface{}) bool {
+// IsTimezone check if the value is a name of the IANA time zone database,
+// ex. Asia/Jakarta or UTC, the database of the system is used unless
+// time/tzdata is imported. Empty string is valid.
+func IsTimezone(value interface{}) bool {
 	str := utility.ToString(value)
 	if !IsNotEmpty(str) {
 		return true
 	}
+	if str == "Local" {
+		return false
+	}
+
+	if _, ok := timezones.Load(str); ok {
+		return true
+	}
 
-	return patternLongitude.MatchString(utility.ToString(value))
+	// only the valid names are cached, they're bounded by the database
+	_, err := time.LoadLocation(str)
+	if err == nil {
+		timezones.Store(str, true)
+	}
+
+	return err == nil
 }
 
 // IsURL check if the value is an URL.
@@ -167,6 +204,71 @@ func IsURL(value interface{}) bool {
 	return patternURL.MatchString(str)
 }
 
+// IsUUID check if the value is an UUID of any version.
+func IsUUID(value interface{}) bool {
+	str := utility.ToString(value)
+	if !IsNotEmpty(str) {
+		return true
+	}
+	return patternUUID.MatchString(str)
+}
+
+// IsUUID4 check if the value is an UUID version 4.
+func IsUUID4(value interface{}) bool {
+	str := utility.ToString(value)
+	if !IsNotEmpty(str) {
+		return true
+	}
+	return patternUUID4.MatchString(str)
+}
+
+// IsIP check if the value is an IPv4 or IPv6 address.
+func IsIP(value interface{}) bool {
+	str := utility.ToString(value)
+	if !IsNotEmpty(str) {
+		return true
+	}
+	return net.ParseIP(str) != nil
+}
+
+// IsIPv4 check if the value is an IPv4 address.
+func IsIPv4(value interface{}) bool {
+	str := utility.ToString(value)
+	if !IsNotEmpty(str) {
+		return true
+	}
+	ip := net.ParseIP(str)
+	return ip != nil && ip.To4() != nil && !strings.Contains(str, ":")
+}
+
+// IsIPv6 check if the value is an IPv6 address.
+func IsIPv6(value interface{}) bool {
+	str := utility.ToString(value)
+	if !IsNotEmpty(str) {
+		return true
+	}
+	return net.ParseIP(str) != nil && strings.Contains(str, ":")
+}
+
+// IsMAC check if the value is a MAC address (EUI-48, EUI-64 or 20-octet).
+func IsMAC(value interface{}) bool {
+	str := utility.ToString(value)
+	if !IsNotEmpty(str) {
+		return true
+	}
+	_, err := net.ParseMAC(str)
+	return err == nil
+}
+
+// IsHostname check if the value is a hostname as defined by RFC 1123.
+func IsHostname(value interface{}) bool {
+	str := utility.ToString(value)
+	if !IsNotEmpty(str) {
+		return true
+	}
+	return len(strings.TrimSuffix(str, ".")) <= 253 && patternHostname.MatchString(str)
+}
+
 // IsJSON check if the value is valid JSON (note: uses json.Unmarshal).
 func IsJSON(value interface{}) bool {
 	var js json.RawMessage
@@ -231,8 +333,8 @@ func IsMatches(value interface{}, pattern string) bool {
 	if !IsNotEmpty(str) {
 		return true
 	}
-	match, _ := regexp.MatchString(pattern, utility.ToString(value))
-	return match
+	re, err := compileRegex(pattern)
+	return err == nil && re.MatchString(str)
 }
 
 // IsSame check if the value is identicaly same with given param
@@ -21,8 +21,9 @@ const (
 	regexURLIP             string = `([1-9]\d?|1\d\d|2[01]\d|22[0-3])(\.(1?\d{1,2}|2[0-4]\d|25[0-5])){2}(?:\.([0-9]\d?|1\d\d|2[0-4]\d|25[0-4]))`
 	regexURLSubdomain      string = `((www\.)|([a-zA-Z0-9]([-\.][a-zA-Z0-9]+)*))`
 	regexURL                      = `^` + regexURLSchema + `?` + regexURLUsername + `?` + `((` + regexURLIP + `|(\[` + regexIP + `\])|(([a-zA-Z0-9]([a-zA-Z0-9-]+)?[a-zA-Z0-9]([-\.][a-zA-Z0-9]+)*)|(` + regexURLSubdomain + `?))?(([a-zA-Z\x{00a1}-\x{ffff}0-9]+-?-?)*[a-zA-Z\x{00a1}-\x{ffff}0-9]+)(?:\.([a-zA-Z\x{00a1}-\x{ffff}]{1,}))?))` + regexURLPort + `?` + regexURLPath + `?$`
-	regexLatitude          string = "^[-+]?([1-8]?\\d(\\.\\d+)?|90(\\.0+)?)$"
-	regexLongitude         string = "^[-+]?(180(\\.0+)?|((1[0-7]\\d)|([1-9]?\\d))(\\.\\d+)?)$"
+	regexUUID              string = "^(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$"
+	regexUUID4             string = "^(?i)[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$"
+	regexHostname          string = `^([a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]{0,61}[a-zA-Z0-9])(\.([a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]{0,61}[a-zA-Z0-9]))*\.?$`
 )
 
 var (
@@ -32,6 +33,7 @@ var (
 	patternAlphanumericSpace = regexp.MustCompile(regexAlphanumericSpace)
 	patternAlphaSpace        = regexp.MustCompile(regexAlphaSpace)
 	patternURL               = regexp.MustCompile(regexURL)
-	patternLatitude          = regexp.MustCompile(regexLatitude)
-	patternLongitude         = regexp.MustCompile(regexLongitude)
+	patternUUID              = regexp.MustCompile(regexUUID)
+	patternUUID4             = regexp.MustCompile(regexUUID4)
+	patternHostname          = regexp.MustCompile(regexHostname)
 )
@@ -5,6 +5,7 @@
 package validation_test
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -110,6 +111,44 @@ func TestIsNumeric(t *testing.T) {
 	}
 }
 
+func TestIsDecimal(t *testing.T) {
+	t.Parallel()
+
+	var tests = []struct {
+		param     interface{}
+		precision int
+		scale     int
+		expected  bool
+	}{
+		{"", 10, 2, true},
+		{"12345678.90", 10, 2, true},
+		{"123456789.90", 10, 2, false},
+		{"12.345", 10, 2, false},
+		{"12.340", 10, 2, true},
+		{"-0012.3", 4, 1, true},
+		{"+7", 1, 0, true},
+		{"7.5", 1, 0, false},
+		{"1e3", 10, 2, false},
+		{"NaN", 10, 2, false},
+		{"1,000.00", 10, 2, false},
+		{" 12.5 ", 10, 2, true},
+		{12.5, 10, 2, true},
+		{12.555, 10, 2, false},
+		{int64(12345), 5, 0, true},
+		{int64(123456), 5, 0, false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, validation.IsDecimal(test.param, test.precision, test.scale), "%v", test.param)
+	}
+
+	assert.True(t, validation.IsDecimalString("12.50"))
+	assert.False(t, validation.IsDecimalString("1e3"))
+	assert.False(t, validation.IsDecimalString("Inf"))
+	assert.True(t, validation.IsInteger("-12"))
+	assert.False(t, validation.IsInteger("12.0"))
+}
+
 func TestIsAlpha(t *testing.T) {
 	t.Parallel()
 
@@ -460,6 +499,107 @@ func TestIsURL(t *testing.T) {
 	}
 }
 
+func TestIsUUID(t *testing.T) {
+	t.Parallel()
+
+	var tests = []struct {
+		param    interface{}
+		expected bool
+		uuid4    bool
+	}{
+		{"", true, true},
+		{"a987fbc9-4bed-3078-cf07-9141ba07c9f3", true, false},
+		{"57B73598-8764-4AD0-A76A-679BB6640EB1", true, true},
+		{"625e63f3-58f5-40b7-83a1-a72ad31acffb", true, true},
+		{"625e63f3-58f5-40b7-c3a1-a72ad31acffb", true, false},
+		{"a987fbc9-4bed-3078-cf07-9141ba07c9f", false, false},
+		{"a987fbc94bed3078cf079141ba07c9f3", false, false},
+		{"xxxa987fbc9-4bed-3078-cf07-9141ba07c9f3", false, false},
+		{123, false, false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, validation.IsUUID(test.param), test.param)
+		assert.Equal(t, test.uuid4, validation.IsUUID4(test.param), test.param)
+	}
+}
+
+func TestIsIP(t *testing.T) {
+	t.Parallel()
+
+	var tests = []struct {
+		param interface{}
+		ip    bool
+		ipv4  bool
+		ipv6  bool
+	}{
+		{"", true, true, true},
+		{"127.0.0.1", true, true, false},
+		{"0.0.0.0", true, true, false},
+		{"255.255.255.255", true, true, false},
+		{"256.0.0.1", false, false, false},
+		{"1.2.3", false, false, false},
+		{"::1", true, false, true},
+		{"2001:db8::68", true, false, true},
+		{"::ffff:192.0.2.1", true, false, true},
+		{"2001:db8:::68", false, false, false},
+		{"localhost", false, false, false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.ip, validation.IsIP(test.param), test.param)
+		assert.Equal(t, test.ipv4, validation.IsIPv4(test.param), test.param)
+		assert.Equal(t, test.ipv6, validation.IsIPv6(test.param), test.param)
+	}
+}
+
+func TestIsMAC(t *testing.T) {
+	t.Parallel()
+
+	var tests = []struct {
+		param    interface{}
+		expected bool
+	}{
+		{"", true},
+		{"3D:F2:C9:A6:B3:4F", true},
+		{"3d-f2-c9-a6-b3-4f", true},
+		{"3df2.c9a6.b34f", true},
+		{"02:00:5e:10:00:00:00:01", true},
+		{"3D:F2:C9:A6:B3", false},
+		{"3D:F2:C9:A6:B3:4G", false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, validation.IsMAC(test.param), test.param)
+	}
+}
+
+func TestIsHostname(t *testing.T) {
+	t.Parallel()
+
+	var tests = []struct {
+		param    interface{}
+		expected bool
+	}{
+		{"", true},
+		{"localhost", true},
+		{"api.kora.id", true},
+		{"api.kora.id.", true},
+		{"db-01.internal", true},
+		{"123.example.com", true},
+		{"-api.kora.id", false},
+		{"api-.kora.id", false},
+		{"api..kora.id", false},
+		{"api_kora.id", false},
+		{strings.Repeat("a", 64) + ".id", false},
+		{strings.Repeat(strings.Repeat("a", 63)+".", 4) + "id", false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, validation.IsHostname(test.param), test.param)
+	}
+}
+
 func TestIsJSON(t *testing.T) {
 	t.Parallel()
 
@@ -796,3 +936,37 @@ func TestIsNotIn(t *testing.T) {
 		assert.Equal(t, test.expected, validation.IsNotIn(test.param1, test.param2...))
 	}
 }
+
+func TestNormalizePhone(t *testing.T) {
+	t.Parallel()
+
+	var tests = []struct {
+		param    string
+		region   string
+		expected string
+	}{
+		{"081234567890", "", "+6281234567890"},
+		{"0812-3456-789", "id", "+628123456789"},
+		{"+62 812 3456 7890", "id", "+6281234567890"},
+		{"6281234567890", "", "+6281234567890"},
+		{"(0812) 345.678", "", "+62812345678"},
+		{"+14155552671", "", "+14155552671"},
+		{"+14155552671", "id", ""},
+		{"14155552671", "", ""},
+		{"0212345678", "", ""},
+		{"0812345", "", ""},
+		{"08123456789012", "", ""},
+		{"0812abc67890", "", ""},
+		{"+", "", ""},
+		{"", "", ""},
+	}
+
+	for _, test := range tests {
+		n, ok := validation.NormalizePhone(test.param, test.region)
+		assert.Equal(t, test.expected, n, test.param)
+		assert.Equal(t, test.expected != "", ok, test.param)
+		if test.param != "" {
+			assert.Equal(t, ok, validation.IsPhone(test.param, test.region), test.param)
+		}
+	}
+}
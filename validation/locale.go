@@ -0,0 +1,182 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/enigma-id/go/i18n"
+)
+
+// MessagePrefix is the prefix of the rule messages in the catalogs,
+// ex. "validation.required", same keys used by the rest package.
+const MessagePrefix = "validation."
+
+// SetLocale sets default locale of the messages, empty locale
+// keeps the built-in english messages.
+func (v *Validator) SetLocale(locale string) {
+	v.locale = locale
+}
+
+// WithLocale returns copy of the validator using the locale,
+// for per call locale ex. v.WithLocale("id").Struct(o).
+func (v *Validator) WithLocale(locale string) *Validator {
+	c := *v
+	c.locale = locale
+	return &c
+}
+
+// LoadMessages loads json and yaml catalogs of the directory, locale is taken
+// from the file name (id.json, id.validation.yaml) and the rule messages
+// are read under "validation" key.
+func (v *Validator) LoadMessages(dir string) error {
+	return v.bundle().LoadDir(dir)
+}
+
+// AddMessages adds messages of the rules for the locale,
+// ex. {"required": ":attribute wajib diisi"}.
+func (v *Validator) AddMessages(locale string, messages map[string]string) {
+	m := make(map[string]interface{}, len(messages))
+	for k, e := range messages {
+		m[MessagePrefix+k] = e
+	}
+	v.bundle().Add(locale, m)
+}
+
+func (v *Validator) bundle() *i18n.Bundle {
+	if v.Bundle == nil {
+		return i18n.Default
+	}
+	return v.Bundle
+}
+
+// translate returns message of the first rule found in the locale, the :attribute
+// is kept as %s so it's filled with the field name just like the built-in ones.
+// Message is returned as is when the locale has no translation.
+func (v *Validator) translate(locale string, param string, e string, rules ...string) string {
+	if locale == "" {
+		return e
+	}
+
+	var msg *i18n.Message
+	for _, r := range rules {
+		if m, ok := v.bundle().Lookup(locale, MessagePrefix+r); ok {
+			msg = m
+			break
+		}
+	}
+	if msg == nil {
+		return e
+	}
+
+	text := msg.Format(locale, messageArgs("\x00", param))
+	if strings.Contains(text, "\x00") {
+		// the message goes through Sprintf with the field name
+		text = strings.Replace(strings.Replace(text, "%", "%%", -1), "\x00", "%s", -1)
+	}
+
+	return text
+}
+
+// Args returns the placeholders of the failure key (field.rule) to be
+// interpolated into the message: :attribute, :param, :values, :other,
+// and :min and :max of the rule with two params, ex. range:1,140.
+func (res *Response) Args(k string) map[string]interface{} {
+	return messageArgs(attributeOf(k), res.params[k])
+}
+
+func messageArgs(attr string, param string) map[string]interface{} {
+	args := map[string]interface{}{
+		"attribute": attr,
+		"param":     param,
+		"values":    strings.Replace(param, ",", ", ", -1),
+		"other":     humanize(param),
+	}
+	if p := strings.Split(param, ","); len(p) == 2 {
+		args["min"], args["max"] = convert(p[0]), convert(p[1])
+	}
+
+	return args
+}
+
+// attributeOf returns name of the field of the failure key, the index
+// of the slice is skipped, ex. "qty" of "items.0.qty.required".
+func attributeOf(k string) string {
+	segments := strings.Split(trimMessage(k), ".")
+	attr := segments[0]
+	for i := len(segments) - 1; i >= 0; i-- {
+		if _, err := strconv.Atoi(segments[i]); err != nil {
+			attr = segments[i]
+			break
+		}
+	}
+
+	return strings.Replace(attr, "_", " ", -1)
+}
+
+// localeOf returns locale of the call, locale of the meta take precedence.
+func (v *Validator) localeOf(m *Meta) string {
+	if m != nil && m.Locale != "" {
+		return m.Locale
+	}
+	return v.locale
+}
+
+// Indonesian messages of the built-in rules,
+// register using v.AddMessages("id", validation.Indonesian).
+var Indonesian = map[string]string{
+	"required":        ":attribute wajib diisi",
+	"excluded_if":     ":attribute harus dikosongkan",
+	"numeric":         ":attribute harus berupa angka",
+	"alpha":           ":attribute hanya boleh berisi huruf",
+	"alpha_num":       ":attribute hanya boleh berisi huruf dan angka",
+	"alpha_num_space": ":attribute hanya boleh berisi huruf, angka dan spasi",
+	"alpha_space":     ":attribute hanya boleh berisi huruf dan spasi",
+	"email":           ":attribute harus berupa alamat email yang valid",
+	"latitude":        ":attribute harus berupa latitude yang valid",
+	"longitude":       ":attribute harus berupa longitude yang valid",
+	"timezone":        ":attribute harus berupa zona waktu yang valid",
+	"url":             "Format :attribute tidak valid",
+	"json":            ":attribute harus berupa JSON yang valid",
+	"uuid":            ":attribute harus berupa UUID yang valid",
+	"uuid4":           ":attribute harus berupa UUID yang valid",
+	"ip":              ":attribute harus berupa alamat IP yang valid",
+	"ipv4":            ":attribute harus berupa alamat IPv4 yang valid",
+	"ipv6":            ":attribute harus berupa alamat IPv6 yang valid",
+	"mac":             ":attribute harus berupa alamat MAC yang valid",
+	"hostname":        ":attribute harus berupa hostname yang valid",
+	"phone":           ":attribute harus berupa nomor telepon yang valid",
+	"after_now":       ":attribute harus berupa tanggal setelah sekarang",
+	"before_now":      ":attribute harus berupa tanggal sebelum sekarang",
+	"age_gte":         "Usia minimal :param tahun",
+	"lte":             ":attribute tidak boleh lebih dari :param",
+	"gte":             ":attribute tidak boleh kurang dari :param",
+	"lt":              ":attribute harus kurang dari :param",
+	"gt":              ":attribute harus lebih dari :param",
+	"range":           ":attribute harus di antara :min dan :max",
+	"contains":        "Format :attribute tidak valid",
+	"match":           "Format :attribute tidak valid",
+	"same":            "Format :attribute tidak valid",
+	"in":              ":attribute yang dipilih tidak valid",
+	"not_in":          ":attribute yang dipilih tidak valid",
+	"max_items":       ":attribute tidak boleh lebih dari :param item",
+	"max_fields":      ":attribute tidak boleh lebih dari :param field",
+	"decimal":         ":attribute harus berupa desimal maksimal :min digit dan :max angka di belakang koma",
+	"multiple_of":     ":attribute harus kelipatan :param",
+	"divisible_by":    ":attribute harus habis dibagi :param",
+	"starts_with":     ":attribute harus diawali salah satu dari: :values",
+	"ends_with":       ":attribute harus diakhiri salah satu dari: :values",
+	"lowercase":       ":attribute harus huruf kecil",
+	"uppercase":       ":attribute harus huruf besar",
+	"ascii":           ":attribute hanya boleh berisi karakter ASCII",
+	"base64":          ":attribute harus berupa string base64 yang valid",
+	"hex":             ":attribute harus berupa string heksadesimal yang valid",
+	"iban":            ":attribute harus berupa IBAN yang valid",
+	"cc":              ":attribute harus berupa nomor kartu yang valid",
+	"same_field":      ":attribute dan :other harus sama",
+	"gte_field":       ":attribute harus lebih dari atau sama dengan :other",
+	"lte_field":       ":attribute harus kurang dari atau sama dengan :other",
+}
@@ -0,0 +1,81 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/enigma-id/go/i18n"
+	"github.com/enigma-id/go/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidator_Locale(t *testing.T) {
+	type address struct {
+		Street string `json:"street" valid:"required"`
+	}
+	type member struct {
+		FullName string  `json:"full_name" valid:"required"`
+		Age      int     `json:"age" valid:"range:17,60"`
+		Discount int     `json:"discount" valid:"lte:50"`
+		Type     string  `json:"type"`
+		Company  string  `json:"company" valid:"required_if:type,company"`
+		Address  address `json:"address" valid:"required"`
+	}
+
+	v := validation.New()
+	v.Bundle = i18n.New("en")
+	v.AddMessages("id", validation.Indonesian)
+	v.AddMessages("id", map[string]string{"lte": ":attribute maksimal :param%"})
+
+	o := member{Age: 10, Discount: 80, Type: "company"}
+
+	// built-in messages without locale
+	r := v.Struct(o)
+	assert.Equal(t, "The full name field is required", r.GetMessage("full_name.required"))
+
+	r = v.WithLocale("id").Struct(o)
+	assert.Equal(t, "full name wajib diisi", r.GetMessage("full_name.required"))
+	assert.Equal(t, "age harus di antara 17 dan 60", r.GetMessage("age.range"))
+	assert.Equal(t, "discount maksimal 50%", r.GetMessage("discount.lte"))
+	assert.Equal(t, "company wajib diisi", r.GetMessage("company.required_if"))
+	assert.Equal(t, "street wajib diisi", r.GetMessage("address.street.required"))
+
+	// the copy doesn't change the validator
+	assert.Equal(t, "The full name field is required", v.Struct(o).GetMessage("full_name.required"))
+
+	r = v.StructMeta(o, validation.Meta{Locale: "id"})
+	assert.Equal(t, "full name wajib diisi", r.GetMessage("full_name.required"))
+
+	v.SetLocale("id")
+	assert.Equal(t, "%s wajib diisi", v.Field("", "required").GetMessage("required"))
+
+	// unknown locale falls back to the built-in message
+	r = v.StructMeta(o, validation.Meta{Locale: "fr"})
+	assert.Equal(t, "The full name field is required", r.GetMessage("full_name.required"))
+}
+
+func TestValidator_LoadMessages(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "validation")
+	defer os.RemoveAll(dir)
+
+	ioutil.WriteFile(filepath.Join(dir, "id.yaml"), []byte("validation:\n  required: \":attribute wajib diisi\"\n  email: \":attribute bukan email\"\n"), 0644)
+
+	v := validation.New()
+	v.Bundle = i18n.New("en")
+	assert.NoError(t, v.LoadMessages(dir))
+	v.SetLocale("id")
+
+	r := v.Request(&Account{Email: "x"})
+	assert.Equal(t, "email bukan email", r.GetMessage("email.email"))
+	assert.Equal(t, "username wajib diisi", r.GetMessage("username.required"))
+	// custom messages of the request are kept
+	assert.Equal(t, "required", r.GetMessage("password.required"))
+
+	assert.Error(t, v.LoadMessages(filepath.Join(dir, "missing")))
+}
@@ -0,0 +1,88 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/enigma-id/go/utility"
+)
+
+// Phone is a phone number normalized into E.164 when it's unmarshaled
+// from json, invalid number is kept as is so the phone rule reports it.
+//
+//	Phone validation.Phone `json:"phone" valid:"required|phone:id"`
+type Phone string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (p *Phone) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	if n, ok := NormalizePhone(s, ""); ok {
+		s = n
+	}
+	*p = Phone(s)
+
+	return nil
+}
+
+// NormalizePhone returns the number in E.164 format (+628123456789), Indonesian
+// MSISDN written as 0812.., 62812.. or +62812.. are accepted, the other countries
+// must be written with + prefix. Spaces, dashes, dots and parentheses are ignored.
+// Region "id" only accepts the Indonesian numbers.
+func NormalizePhone(number string, region string) (string, bool) {
+	n := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+		return r
+	}, number)
+
+	international := strings.HasPrefix(n, "+")
+	n = strings.TrimPrefix(n, "+")
+	if n == "" || strings.IndexFunc(n, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return "", false
+	}
+
+	switch {
+	case !international && strings.HasPrefix(n, "0"):
+		n = "62" + n[1:]
+	case !international && !strings.HasPrefix(n, "62"):
+		return "", false
+	}
+
+	if strings.HasPrefix(n, "62") {
+		// mobile number, 8 followed by 8 to 11 digits
+		if nsn := n[2:]; nsn[0:1] != "8" || len(nsn) < 9 || len(nsn) > 12 {
+			return "", false
+		}
+	} else if strings.EqualFold(region, "id") || len(n) < 8 || len(n) > 15 {
+		return "", false
+	}
+
+	return "+" + n, true
+}
+
+// IsPhone check if the value is a phone number of the region, see NormalizePhone.
+func IsPhone(value interface{}, region string) bool {
+	str := utility.ToString(value)
+	if !IsNotEmpty(str) {
+		return true
+	}
+	_, ok := NormalizePhone(str, region)
+	return ok
+}
+
+func validPhone(value interface{}, param string) (v bool, m string) {
+	if v = IsPhone(value, param); !v {
+		m = "The %s must be a valid phone number"
+	}
+	return
+}
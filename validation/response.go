@@ -5,9 +5,9 @@
 package validation
 
 import (
-	"regexp"
 	"strings"
 
+	"github.com/enigma-id/go/i18n"
 	"github.com/enigma-id/go/utility"
 )
 
@@ -18,6 +18,10 @@ type Response struct {
 	FailMsg        map[string]string // failing error messages
 	customMessages map[string]string // custom messages
 	failureKeys    []string
+	err            error             // lookup error of the providers
+	params         map[string]string // parameters of the failing rules
+	brands         map[string]string // card brands detected by the cc rule
+	format         Format
 }
 
 // NewResponse create new instance responses
@@ -25,6 +29,19 @@ func NewResponse() *Response {
 	return &Response{Valid: true}
 }
 
+// Err returns error of the lookup of the rules registered by RegisterProvider,
+// the validation can't be trusted when it's not nil.
+func (res *Response) Err() error {
+	return res.err
+}
+
+// Brand returns the card brand detected by the cc rule of the field,
+// ex. Brand("payment.card_number") is "visa", the key is empty string
+// for the response of Validator.Field.
+func (res *Response) Brand(k string) string {
+	return res.brands[k]
+}
+
 // GetMessages is a map which contains all errors from validating a struct.
 func (res *Response) GetMessages() map[string]string {
 	return res.messages
@@ -64,19 +81,64 @@ func (res *Response) Error() string {
 
 func (res *Response) applyCustomMessage() {
 	for i := range res.FailMsg {
-		if c := res.customMessages[i]; c != "" {
+		if c, ok := res.customMessage(i); ok && c != "" {
+			if strings.Contains(c, ":") {
+				c = (&i18n.Message{Text: c}).Format("", res.Args(i))
+			}
 			res.Failure(i, c)
+		}
+	}
+}
+
+// customMessage returns custom message of the failure key, placeholders
+// of Args are interpolated by applyCustomMessage. The wildcard
+// in the custom key matches a single segment at any depth, an index of slice
+// or a key of map, ex. "orders.*.items.*.qty.required". The exact key is
+// preferred, then the key with the fewest wildcards.
+func (res *Response) customMessage(k string) (string, bool) {
+	if c, ok := res.customMessages[k]; ok {
+		return c, true
+	}
+
+	var (
+		msg   string
+		found bool
+		best  int
+	)
+	segments := strings.Split(k, ".")
+	for ck, c := range res.customMessages {
+		if !strings.Contains(ck, "*") {
 			continue
 		}
 
-		if IsMatches(i, "(\\.[0-9]+\\.[a-z]+\\.[a-z]*)$") {
-			re := regexp.MustCompile("[^a-z.]")
-			ix := re.ReplaceAllString(i, "*")
-			if c := res.customMessages[ix]; c != "" {
-				res.Failure(i, c)
-			}
+		n, ok := matchWildcard(strings.Split(ck, "."), segments)
+		if ok && (!found || n < best || n == best && c < msg) {
+			msg, found, best = c, true, n
 		}
 	}
+
+	return msg, found
+}
+
+// matchWildcard matches the segments of the key with the pattern,
+// it returns number of the wildcards used.
+func matchWildcard(pattern, segments []string) (int, bool) {
+	if len(pattern) != len(segments) {
+		return 0, false
+	}
+
+	var n int
+	for i, p := range pattern {
+		switch p {
+		case "*":
+			n++
+		case segments[i]:
+		default:
+			return 0, false
+		}
+	}
+
+	return n, true
 }
 
 func (res *Response) compile() *Response {
@@ -89,6 +151,27 @@ func (res *Response) compile() *Response {
 	return res
 }
 
+func (res *Response) brand(k string, b string) {
+	if res.brands == nil {
+		res.brands = make(map[string]string)
+	}
+	res.brands[k] = b
+}
+
+// mergeBrands copies the card brands of the child response under the name.
+func (res *Response) mergeBrands(name string, cr *Response) {
+	for k, b := range cr.brands {
+		switch {
+		case name == "":
+		case k == "":
+			k = name
+		default:
+			k = name + "." + k
+		}
+		res.brand(k, b)
+	}
+}
+
 func trimMessage(s string) string {
 	if idx := strings.LastIndex(s, "."); idx != -1 {
 		return s[:idx]
@@ -103,3 +186,17 @@ func SetError(field string, value string) *Response {
 
 	return o.compile()
 }
+
+// Translate replaces the failure messages using fn, fn receives the failure key
+// (field.rule) and current message. Custom messages from Messages() are kept as is.
+func (res *Response) Translate(fn func(k string, e string) string) {
+	for k, e := range res.FailMsg {
+		if _, ok := res.customMessage(k); ok {
+			continue
+		}
+
+		res.FailMsg[k] = fn(k, e)
+	}
+
+	res.compile()
+}
@@ -0,0 +1,113 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"strings"
+	"sync"
+)
+
+// codes is the catalog of the error codes by the rule, the codes of the
+// built-in rules are stable so the clients can rely on them.
+var codes = struct {
+	sync.RWMutex
+	rules map[string]string
+}{rules: map[string]string{
+	"required":         "VAL_REQUIRED",
+	"required_on":      "VAL_REQUIRED",
+	"required_if":      "VAL_REQUIRED_IF",
+	"required_unless":  "VAL_REQUIRED_UNLESS",
+	"required_with":    "VAL_REQUIRED_WITH",
+	"required_without": "VAL_REQUIRED_WITHOUT",
+	"excluded_if":      "VAL_EXCLUDED_IF",
+	"same_field":       "VAL_SAME_FIELD",
+	"gte_field":        "VAL_GTE_FIELD",
+	"lte_field":        "VAL_LTE_FIELD",
+	"numeric":          "VAL_NUMERIC",
+	"alpha":            "VAL_ALPHA",
+	"alpha_num":        "VAL_ALPHA_NUM",
+	"alpha_num_space":  "VAL_ALPHA_NUM_SPACE",
+	"alpha_space":      "VAL_ALPHA_SPACE",
+	"email":            "VAL_EMAIL",
+	"latitude":         "VAL_LATITUDE",
+	"longitude":        "VAL_LONGITUDE",
+	"timezone":         "VAL_TIMEZONE",
+	"url":              "VAL_URL",
+	"json":             "VAL_JSON",
+	"uuid":             "VAL_UUID",
+	"uuid4":            "VAL_UUID",
+	"ip":               "VAL_IP",
+	"ipv4":             "VAL_IPV4",
+	"ipv6":             "VAL_IPV6",
+	"mac":              "VAL_MAC",
+	"hostname":         "VAL_HOSTNAME",
+	"phone":            "VAL_PHONE",
+	"after_now":        "VAL_AFTER_NOW",
+	"before_now":       "VAL_BEFORE_NOW",
+	"age_gte":          "VAL_AGE_GTE",
+	"lte":              "VAL_LTE",
+	"gte":              "VAL_GTE",
+	"lt":               "VAL_LT",
+	"gt":               "VAL_GT",
+	"range":            "VAL_RANGE",
+	"contains":         "VAL_CONTAINS",
+	"match":            "VAL_MATCH",
+	"same":             "VAL_SAME",
+	"in":               "VAL_IN",
+	"not_in":           "VAL_NOT_IN",
+	"max_items":        "VAL_MAX_ITEMS",
+	"max_fields":       "VAL_MAX_FIELDS",
+	"decimal":          "VAL_DECIMAL",
+	"multiple_of":      "VAL_MULTIPLE_OF",
+	"divisible_by":     "VAL_MULTIPLE_OF",
+	"starts_with":      "VAL_STARTS_WITH",
+	"ends_with":        "VAL_ENDS_WITH",
+	"lowercase":        "VAL_LOWERCASE",
+	"uppercase":        "VAL_UPPERCASE",
+	"ascii":            "VAL_ASCII",
+	"base64":           "VAL_BASE64",
+	"hex":              "VAL_HEX",
+	"iban":             "VAL_IBAN",
+	"cc":               "VAL_CREDIT_CARD",
+}}
+
+// RegisterCode sets the error code of the rule, ex. the rule registered by
+// RegisterProvider or the failures set by Request.Validate. The rule
+// without registered code has "VAL_" followed by its upper cased name.
+//
+//	validation.RegisterCode("unique", "VAL_TAKEN")
+func RegisterCode(rule string, code string) {
+	codes.Lock()
+	codes.rules[rule] = code
+	codes.Unlock()
+}
+
+// Code returns the error code of the rule, ex. "VAL_EMAIL" of email.
+func Code(rule string) string {
+	codes.RLock()
+	c, ok := codes.rules[rule]
+	codes.RUnlock()
+	if ok {
+		return c
+	}
+
+	return "VAL_" + strings.ToUpper(rule)
+}
+
+// GetCodes returns the error codes of the failures by the field, same keys
+// as GetErrors, so the clients can localize the messages themselves.
+// The failure set without rule, ex. SetError("name", msg), has no code.
+func (res *Response) GetCodes() map[string]string {
+	c := make(map[string]string)
+	for k := range res.GetMessages() {
+		i := strings.LastIndex(k, ".")
+		if i < 0 {
+			continue
+		}
+		c[k[:i]] = Code(k[i+1:])
+	}
+
+	return c
+}
@@ -0,0 +1,98 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"sort"
+	"strings"
+)
+
+// Format is the rendering of the failures by Response.Format.
+type Format int
+
+const (
+	// FormatMap renders the message by the field, same as GetErrors,
+	// ex. {"email": "The email must be a valid email address"}.
+	FormatMap Format = iota
+
+	// FormatRules renders the message by the field and the rule, same as GetMessages,
+	// ex. {"email.email": "The email must be a valid email address"}.
+	FormatRules
+
+	// FormatList renders list of FieldError sorted by the field.
+	FormatList
+)
+
+// FieldError is a failure of the FormatList.
+type FieldError struct {
+	Field   string   `json:"field"`
+	Rule    string   `json:"rule"`
+	Params  []string `json:"params,omitempty"`
+	Message string   `json:"message"`
+}
+
+// ErrorFormat sets the format of the responses rendered by Response.Output.
+func ErrorFormat(f Format) Option {
+	return func(v *Validator) {
+		v.format = f
+	}
+}
+
+// Format renders the failures in the format, ex. as response of the clients
+// requiring a different error envelope.
+func (res *Response) Format(f Format) interface{} {
+	switch f {
+	case FormatRules:
+		return res.GetMessages()
+	case FormatList:
+		return res.List()
+	}
+
+	return res.GetErrors()
+}
+
+// Output renders the failures in the format set by ErrorFormat option.
+func (res *Response) Output() interface{} {
+	return res.Format(res.format)
+}
+
+// List returns the failures sorted by the field, the params are
+// the parameters of the failing rule, ex. ["8"] of gte:8.
+func (res *Response) List() []FieldError {
+	keys := make([]string, 0, len(res.GetMessages()))
+	for k := range res.GetMessages() {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	list := make([]FieldError, 0, len(keys))
+	for _, k := range keys {
+		fe := FieldError{Field: trimMessage(k), Rule: k[strings.LastIndex(k, ".")+1:], Message: res.messages[k]}
+		if p := res.params[k]; p != "" {
+			fe.Params = strings.Split(p, ",")
+		}
+		list = append(list, fe)
+	}
+
+	return list
+}
+
+// param records the parameter of the failing rule.
+func (res *Response) param(k string, p string) {
+	if p == "" {
+		return
+	}
+	if res.params == nil {
+		res.params = make(map[string]string)
+	}
+	res.params[k] = p
+}
+
+// mergeParams copies the rule parameters of the child response under the prefix.
+func (res *Response) mergeParams(prefix string, cr *Response) {
+	for k, p := range cr.params {
+		res.param(prefix+k, p)
+	}
+}
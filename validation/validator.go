@@ -5,11 +5,14 @@
 package validation
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/enigma-id/go/i18n"
 	"github.com/enigma-id/go/utility"
 )
 
@@ -18,28 +21,115 @@ type (
 	Validator struct {
 		TagName      string
 		ValidatorFns map[string]validatorFn
+
+		// Bundle holding the translated messages, i18n.Default when nil.
+		Bundle *i18n.Bundle
+
+		locale    string
+		failFast  bool
+		jsonKeys  bool
+		format    Format
+		structs   *structValidations
+		providers map[string]provider
+		values    map[string]func() []string
+		ctxRules  map[string]ContextRuleFunc
 	}
 
+	// Option configures the Validator.
+	Option func(*Validator)
+
 	// Request interface validation requests
 	Request interface {
 		Validate() *Response
 		Messages() map[string]string
 	}
+
+	// Meta is the metadata of the http request being validated,
+	// used by conditional rules like required_on and the messages locale.
+	Meta struct {
+		Method   string
+		Route    string
+		Locale   string
+		Timezone *time.Location
+
+		// Context of the rules registered by RegisterProvider and
+		// RegisterContextRule, and of ContextRequest.
+		Context context.Context
+
+		// Scenario selects the rules having `;on=` option, ex. "create" or "update".
+		Scenario string
+
+		fields *fieldFilter
+	}
 )
 
 // Field validates a value based on the provided
 // tags and returns validator response
 func (v *Validator) Field(value interface{}, tag string) (res *Response) {
-	tags, err := fetchTag(tag, v.ValidatorFns)
+	return v.field(value, tag, nil, reflect.Value{})
+}
+
+// field validates the value, parent is the struct of the field
+// used by the conditional rules.
+func (v *Validator) field(value interface{}, tag string, m *Meta, parent reflect.Value) (res *Response) {
+	tags, err := v.rules(tag)
 	if err != nil {
 		return &Response{Valid: true}
 	}
 
-	res = &Response{Valid: true}
+	res = &Response{Valid: true, format: v.format}
 	var e string
 	for _, t := range tags {
-		if res.Valid, e = t.Fn(value, t.Param); !res.Valid {
-			res.Failure(t.Name, e)
+		t.Fn = v.ValidatorFns[t.Name]
+		if len(t.On) > 0 && !m.scenario(t.On) {
+			continue
+		}
+
+		if fn, ok := dateRules[t.Name]; ok {
+			t.Fn = m.dateRule(fn)
+		}
+		if p, ok := v.providers[t.Name]; ok {
+			t.Fn = p.rule(m, res)
+		}
+		if fn, ok := v.ctxRules[t.Name]; ok {
+			t.Fn = fn.bind(m.ctx())
+		}
+		if t.Name == "cc" {
+			t.Fn = cardRule(res)
+		}
+		if (t.Name == "in" || t.Name == "not_in") && strings.HasPrefix(t.Param, "@") {
+			t.Fn, t.Param = v.valuesRule(t.Name, t.Param[1:])
+		}
+
+		if t.Name == "each" {
+			if !v.each(value, t.Param, m, parent, res) {
+				break
+			}
+			continue
+		}
+
+		rule := t.Name
+		if t.Name == "required_on" {
+			if m.on(t.Param) {
+				t.Fn, rule = validRequired, "required"
+			} else if !IsNotEmpty(value) {
+				// optional on the other methods
+				break
+			}
+		}
+		if required(t, parent) {
+			t.Fn, rule = validRequired, "required"
+		}
+		if excluded(t, parent) {
+			t.Fn = validExcluded
+		}
+
+		if res.Valid, e = t.Fn(value, t.Param); res.Valid {
+			res.Valid, e = compareField(t, value, parent)
+		}
+		if !res.Valid {
+			res.Failure(t.Name, v.translate(v.localeOf(m), t.Param, e, t.Name, rule))
+			res.param(t.Name, t.Param)
 			break
 		}
 	}
@@ -51,13 +141,31 @@ func (v *Validator) Field(value interface{}, tag string) (res *Response) {
 // on 'valid' tags and returns errors found indexed
 // by the field name.
 func (v *Validator) Struct(object interface{}) (res *Response) {
+	return v.structOf(object, nil)
+}
+
+// StructMeta same as Struct with metadata of the request,
+// ex. `valid:"required_on:POST"` is required only on POST.
+func (v *Validator) StructMeta(object interface{}, m Meta) (res *Response) {
+	return v.structOf(object, &m)
+}
+
+// StructScenario same as Struct, the rules having `;on=` option are applied
+// only on the scenario, so the same struct is used on create and update, ex.
+//
+//	Email string `valid:"required;on=create|email"`
+func (v *Validator) StructScenario(object interface{}, scenario string) (res *Response) {
+	return v.structOf(object, &Meta{Scenario: scenario})
+}
+
+func (v *Validator) structOf(object interface{}, m *Meta) (res *Response) {
 	iVal := reflect.ValueOf(object)
 	iType := reflect.TypeOf(object)
 
 	// when object is pointer,
 	// we should run validation for the real struct
 	if iVal.Kind() == reflect.Ptr && !iVal.IsNil() {
-		return v.Struct(iVal.Elem().Interface())
+		return v.structOf(iVal.Elem().Interface(), m)
 	}
 
 	// the interface is not struct
@@ -65,32 +173,36 @@ func (v *Validator) Struct(object interface{}) (res *Response) {
 		return &Response{}
 	}
 
-	res = &Response{Valid: true}
+	res = &Response{Valid: true, format: v.format}
 
-	nf := iVal.NumField()
-	for i := 0; i < nf; i++ {
-		field := iVal.Field(i)
-		fType := iType.Field(i)
+	fields := v.fields(iType)
+	for i := 0; i < len(fields) && !v.stop(res); i++ {
+		f := &fields[i]
+		field := iVal.Field(f.index)
+		fname, fTag := f.name, f.tag
 
-		fname := fType.Tag.Get("json")
-		if fname == "" {
-			fname = utility.ToUnderscore(fType.Name)
+		if !m.include(fname, f.goName) {
+			continue
 		}
+		sm := m.sub(fname, f.goName)
+
+		if r := v.limit(field, fTag, m); r != nil {
+			mergeResponse(fname, r, res)
 
-		fTag := fType.Tag.Get(v.TagName)
-		if fTag == "" || fTag == "-" {
 			continue
 		}
 
-		if field.Type() != reflect.TypeOf(time.Time{}) {
+		if !f.isTime {
 			if isPointer(field) || isStruct(field) {
-				if r, ok := v.validRequest(field.Interface()); ok && !r.Valid {
+				if r, ok := v.validRequest(field.Interface(), sm); ok && !r.Valid {
 					mergeResponse(fname, r, res)
 
 					continue
 				}
 
-				if r := v.Struct(field.Interface()); !r.Valid {
+				r := v.structOf(field.Interface(), sm)
+				res.mergeBrands(fname, r)
+				if !r.Valid {
 					mergeResponse(fname, r, res)
 				}
 
@@ -98,14 +210,16 @@ func (v *Validator) Struct(object interface{}) (res *Response) {
 			}
 
 			if isSlice(field) {
-				for i := 0; i < field.Len(); i++ {
+				for i := 0; i < field.Len() && !v.stop(res); i++ {
 					if isPointer(field.Index(i)) || isStruct(field.Index(i)) {
-						if r, ok := v.validRequest(field.Interface()); ok && !r.Valid {
+						if r, ok := v.validRequest(field.Interface(), sm); ok && !r.Valid {
 							mergeResponse(fmt.Sprintf("%s.%d", fname, i), r, res)
 
 							continue
 						}
-						if r := v.Struct(field.Index(i).Interface()); !r.Valid {
+						r := v.structOf(field.Index(i).Interface(), sm)
+						res.mergeBrands(fmt.Sprintf("%s.%d", fname, i), r)
+						if !r.Valid {
 							mergeResponse(fmt.Sprintf("%s.%d", fname, i), r, res)
 						}
 					}
@@ -113,36 +227,119 @@ func (v *Validator) Struct(object interface{}) (res *Response) {
 
 				continue
 			}
+
+			if isMap(field) {
+				v.mapOf(fname, fTag, field, sm, iVal, res)
+
+				continue
+			}
 		}
 
 		// run the validation for struct field
-		if r := v.Field(field.Interface(), fTag); !r.Valid {
+		r := v.field(field.Interface(), fTag, m, iVal)
+		res.mergeBrands(fname, r)
+		if !r.Valid {
 			mergeResponse(fname, r, res)
 		}
 	}
+
+	if iVal.Kind() == reflect.Struct {
+		v.validStruct(iVal, res)
+	}
+
 	return
 }
 
+// mapOf validates each value of the map, struct values are validated
+// by their own tags and the others by the tag of the map field.
+// Failures are keyed by the map key, ex. `contacts.home.email`.
+func (v *Validator) mapOf(fname string, tag string, field reflect.Value, m *Meta, parent reflect.Value, res *Response) {
+	keys := field.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+	for _, k := range keys {
+		if v.stop(res) {
+			return
+		}
+
+		name := fmt.Sprintf("%s.%s", fname, k.String())
+		value := field.MapIndex(k)
+		if value.Kind() == reflect.Interface && !value.IsNil() {
+			value = value.Elem()
+		}
+
+		if isPointer(value) || isStruct(value) {
+			if r, ok := v.validRequest(value.Interface(), m); ok && !r.Valid {
+				mergeResponse(name, r, res)
+
+				continue
+			}
+			if r := v.structOf(value.Interface(), m); !r.Valid {
+				mergeResponse(name, r, res)
+			}
+
+			continue
+		}
+
+		if r := v.field(value.Interface(), tag, m, parent); !r.Valid {
+			if r.err != nil {
+				res.err = r.err
+			}
+
+			// the attribute of the message is the map field
+			for rule, e := range r.compile().GetMessages() {
+				if IsContains(e, "%s") {
+					e = fmt.Sprintf(e, strings.Replace(fname, "_", " ", -1))
+				}
+				res.Failure(name+"."+rule, e)
+			}
+			res.mergeParams(name+".", r)
+		}
+	}
+}
+
 // Request same as Validation.Struct but, this
 // should be implement an ValidationRequest interfaces
 // so we can do some custom validation and custome error messages.
 func (v *Validator) Request(object Request) (res *Response) {
+	return v.request(object, nil)
+}
+
+// RequestMeta same as Request with metadata of the request.
+func (v *Validator) RequestMeta(object Request, m Meta) (res *Response) {
+	return v.request(object, &m)
+}
+
+func (v *Validator) request(object Request, m *Meta) (res *Response) {
 	res = &Response{
 		Valid:          true,
 		customMessages: object.Messages(),
+		format:         v.format,
 	}
 
 	// run as struct validation
-	if os := v.Struct(object); !os.Valid {
+	os := v.structOf(object, m)
+	res.mergeBrands("", os)
+	if !os.Valid {
 		for k, e := range os.GetMessages() {
 			res.Failure(k, e)
 		}
+		res.mergeParams("", os)
+		res.err = os.err
 	}
 
-	// run custom validation
-	if or := object.Validate(); or != nil && !or.Valid {
-		for x, y := range or.GetMessages() {
-			res.Failure(x, y)
+	// run custom validation, unless it already fails on fail fast
+	if !v.stop(res) {
+		var or *Response
+		if cr, ok := object.(ContextRequest); ok {
+			or = cr.ValidateContext(m.ctx())
+		} else {
+			or = object.Validate()
+		}
+		if or != nil && !or.Valid {
+			for x, y := range or.GetMessages() {
+				res.Failure(x, y)
+			}
 		}
 	}
 
@@ -152,17 +349,81 @@ func (v *Validator) Request(object Request) (res *Response) {
 	return
 }
 
-func (v *Validator) validRequest(object interface{}) (r *Response, valid bool) {
+func (v *Validator) validRequest(object interface{}, m *Meta) (r *Response, valid bool) {
 	if oq, ok := object.(Request); ok {
 		valid = true
-		r = v.Request(oq)
+		r = v.request(oq, m)
 	}
 
 	return
 }
 
+// fieldName returns the key of the field in the failures.
+func (v *Validator) fieldName(f reflect.StructField) string {
+	tag := f.Tag.Get("json")
+	if !v.jsonKeys {
+		if tag == "" {
+			return utility.ToUnderscore(f.Name)
+		}
+		return tag
+	}
+
+	// same name as encoding/json
+	name := strings.Split(tag, ",")[0]
+	switch {
+	case tag == "-":
+		// not in json, ex. bound from the path
+		return utility.ToUnderscore(f.Name)
+	case name == "":
+		return f.Name
+	}
+
+	return name
+}
+
+// stop returns true when the validation should stop on fail fast mode.
+func (v *Validator) stop(res *Response) bool {
+	return v.failFast && !res.Valid
+}
+
+// on returns true when the method or the route of the request
+// is in the comma separated list.
+func (m *Meta) on(list string) bool {
+	if m == nil {
+		return false
+	}
+
+	for _, p := range strings.Split(list, ",") {
+		p = strings.TrimSpace(p)
+		if strings.EqualFold(p, m.Method) || (m.Route != "" && p == m.Route) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// scenario returns true when the scenario, method or route
+// of the request is one of the scenarios of the rule.
+func (m *Meta) scenario(on []string) bool {
+	if m == nil {
+		return false
+	}
+
+	for _, sc := range on {
+		if (m.Scenario != "" && sc == m.Scenario) || strings.EqualFold(sc, m.Method) || (m.Route != "" && sc == m.Route) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func mergeResponse(name string, cr *Response, pr *Response) {
 	cr.compile()
+	if cr.err != nil {
+		pr.err = cr.err
+	}
 
 	for k, e := range cr.GetMessages() {
 		if IsContains(e, "%s") {
@@ -171,6 +432,7 @@ func mergeResponse(name string, cr *Response, pr *Response) {
 
 		pr.Failure(name+"."+k, e)
 	}
+	pr.mergeParams(name+".", cr)
 }
 
 func isPointer(f reflect.Value) bool {
@@ -189,34 +451,97 @@ func isSlice(f reflect.Value) bool {
 	return false
 }
 
+// isMap returns true when the map has string keys and at least one value,
+// empty map is validated by the tag of the field itself (ex. required).
+func isMap(f reflect.Value) bool {
+	return f.Kind() == reflect.Map && f.Type().Key().Kind() == reflect.String && f.Len() > 0
+}
+
 var tagsFn = map[string]validatorFn{
-	"required":        validRequired,
-	"numeric":         validNumeric,
-	"alpha":           validAlpha,
-	"alpha_num":       validAlphaNum,
-	"alpha_num_space": validAlphaNumSpace,
-	"alpha_space":     validAlphaSpace,
-	"email":           validEmail,
-	"latitude":        validLatitude,
-	"longitude":       validLongitude,
-	"url":             validURL,
-	"json":            validJSON,
-	"lte":             validLte,
-	"gte":             validGte,
-	"lt":              validLt,
-	"gt":              validGt,
-	"range":           validRange,
-	"contains":        validContains,
-	"match":           validMatch,
-	"same":            validSame,
-	"in":              validIn,
-	"not_in":          validNotIn,
+	"required":         validRequired,
+	"required_on":      validRequiredOn,
+	"each":             validEach,
+	"required_if":      validConditional,
+	"required_unless":  validConditional,
+	"required_with":    validConditional,
+	"required_without": validConditional,
+	"excluded_if":      validConditional,
+	"same_field":       validConditional,
+	"gte_field":        validConditional,
+	"lte_field":        validConditional,
+	"numeric":          validNumeric,
+	"alpha":            validAlpha,
+	"alpha_num":        validAlphaNum,
+	"alpha_num_space":  validAlphaNumSpace,
+	"alpha_space":      validAlphaSpace,
+	"email":            validEmail,
+	"latitude":         validLatitude,
+	"longitude":        validLongitude,
+	"timezone":         validTimezone,
+	"url":              validURL,
+	"json":             validJSON,
+	"uuid":             validUUID,
+	"uuid4":            validUUID4,
+	"ip":               validIP,
+	"ipv4":             validIPv4,
+	"ipv6":             validIPv6,
+	"mac":              validMAC,
+	"hostname":         validHostname,
+	"phone":            validPhone,
+	"after_now":        validAfterNow,
+	"before_now":       validBeforeNow,
+	"age_gte":          validAgeGte,
+	"lte":              validLte,
+	"gte":              validGte,
+	"lt":               validLt,
+	"gt":               validGt,
+	"range":            validRange,
+	"contains":         validContains,
+	"match":            validMatch,
+	"same":             validSame,
+	"in":               validIn,
+	"not_in":           validNotIn,
+	"max_items":        validMaxItems,
+	"max_fields":       validMaxFields,
+	"decimal":          validDecimal,
+	"multiple_of":      validMultipleOf,
+	"divisible_by":     validMultipleOf,
+	"starts_with":      validStartsWith,
+	"ends_with":        validEndsWith,
+	"lowercase":        validLowercase,
+	"uppercase":        validUppercase,
+	"ascii":            validASCII,
+	"base64":           validBase64,
+	"hex":              validHex,
+	"iban":             validIBAN,
+	"cc":               validCreditCard,
+}
+
+// FailFast stops validating the struct at the first failing field,
+// the response only contains the first failure.
+func FailFast() Option {
+	return func(v *Validator) {
+		v.failFast = true
+	}
+}
+
+// JSONKeys keys the failures by json name of the fields, the same name
+// as encoding/json, instead of the json tag as is or snake cased field name.
+func JSONKeys() Option {
+	return func(v *Validator) {
+		v.jsonKeys = true
+	}
 }
 
 // New creates a new Validation instances.
-func New() *Validator {
-	return &Validator{
+func New(opts ...Option) *Validator {
+	v := &Validator{
 		TagName:      "valid",
 		ValidatorFns: tagsFn,
 	}
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
 }
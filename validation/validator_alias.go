@@ -0,0 +1,102 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// maxAliasDepth limits the alias referencing other alias.
+const maxAliasDepth = 8
+
+// aliases holds the rules by the alias name.
+var aliases = struct {
+	sync.RWMutex
+	rules map[string]string
+}{rules: make(map[string]string)}
+
+// RegisterAlias registers the rules under the name, so the bundle of rules
+// is defined once and referenced by a single token of the tag, into every
+// validator including the one used by rest binder. The alias can reference
+// other alias, the scenarios of the token apply to the rules of the alias
+// that don't have their own. It panics when the rules can't be parsed.
+//
+//	validation.RegisterAlias("password_std", "required|gte:8|lte:64")
+//
+//	type Signup struct {
+//		Password string `json:"password" valid:"password_std"`
+//	}
+func RegisterAlias(name string, rules string) {
+	if name == "" || strings.ContainsAny(name, "|:; ") {
+		panic(fmt.Sprintf("validation: invalid alias name %q", name))
+	}
+	if _, err := splitTag(rules); err != nil {
+		panic(fmt.Sprintf("validation: invalid rules of alias %s, %s", name, err.Error()))
+	}
+
+	aliases.Lock()
+	aliases.rules[name] = rules
+	aliases.Unlock()
+
+	// the parsed tags may hold the previous rules of the alias
+	tagCache.Range(func(k, _ interface{}) bool {
+		tagCache.Delete(k)
+		return true
+	})
+}
+
+// expandAliases replaces the alias tokens with their rules.
+func expandAliases(tags []validatorTag, depth int) ([]validatorTag, error) {
+	var expanded []validatorTag
+	for i, t := range tags {
+		rules, ok := alias(t.Name)
+		if !ok {
+			if expanded != nil {
+				expanded = append(expanded, t)
+			}
+			continue
+		}
+
+		if t.Param != "" {
+			return nil, fmt.Errorf("alias %s doesn't take param", t.Name)
+		}
+		if depth >= maxAliasDepth {
+			return nil, fmt.Errorf("alias %s is recursive", t.Name)
+		}
+
+		at, err := splitTag(rules)
+		if err == nil {
+			at, err = expandAliases(at, depth+1)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if expanded == nil {
+			expanded = append(make([]validatorTag, 0, len(tags)+len(at)), tags[:i]...)
+		}
+		for _, r := range at {
+			if len(r.On) == 0 {
+				r.On = t.On
+			}
+			expanded = append(expanded, r)
+		}
+	}
+
+	if expanded == nil {
+		return tags, nil
+	}
+	return expanded, nil
+}
+
+func alias(name string) (string, bool) {
+	aliases.RLock()
+	defer aliases.RUnlock()
+
+	rules, ok := aliases.rules[name]
+	return rules, ok
+}
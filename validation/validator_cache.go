@@ -0,0 +1,97 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+var (
+	// tagCache holds the parsed rules by the tag.
+	tagCache sync.Map
+
+	// structCache holds the validated fields by structKey.
+	structCache sync.Map
+
+	timeType = reflect.TypeOf(time.Time{})
+)
+
+type (
+	parsedTag struct {
+		tags []validatorTag
+		err  error
+	}
+
+	// structKey is the type with the options changing the fields.
+	structKey struct {
+		t        reflect.Type
+		tagName  string
+		jsonKeys bool
+	}
+
+	// structField is a field of the struct having the validation tag.
+	structField struct {
+		index  int
+		name   string // key of the failures
+		goName string
+		tag    string
+		isTime bool
+	}
+)
+
+// rules returns the parsed rules of the tag, the rules are shared
+// so the caller must not modify them, the Fn is resolved by the caller
+// since ValidatorFns can be changed.
+func (v *Validator) rules(tag string) ([]validatorTag, error) {
+	var p *parsedTag
+	if c, ok := tagCache.Load(tag); ok {
+		p = c.(*parsedTag)
+	} else {
+		tags, err := parseTag(tag)
+		p = &parsedTag{tags, err}
+		tagCache.Store(tag, p)
+	}
+
+	if p.err != nil {
+		return nil, p.err
+	}
+	for _, t := range p.tags {
+		if _, ok := v.ValidatorFns[t.Name]; !ok {
+			return nil, errUnknownTag(t.Name)
+		}
+	}
+
+	return p.tags, nil
+}
+
+// fields returns the fields of the struct type having the validation tag.
+func (v *Validator) fields(t reflect.Type) []structField {
+	k := structKey{t, v.TagName, v.jsonKeys}
+	if c, ok := structCache.Load(k); ok {
+		return c.([]structField)
+	}
+
+	fields := make([]structField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get(v.TagName)
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		fields = append(fields, structField{
+			index:  i,
+			name:   v.fieldName(f),
+			goName: f.Name,
+			tag:    tag,
+			isTime: f.Type == timeType,
+		})
+	}
+
+	structCache.Store(k, fields)
+	return fields
+}
@@ -0,0 +1,118 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/enigma-id/go/utility"
+)
+
+// conditional rules that depend on the other fields of the struct,
+// the field is required when the condition is met.
+var conditionals = map[string]func(parent reflect.Value, params []string) bool{
+	// required_if:type,company,foundation
+	"required_if": func(parent reflect.Value, p []string) bool {
+		v, ok := sibling(parent, p[0])
+		return ok && IsNotEmpty(v) && IsIn(v, p[1:]...)
+	},
+	// required_unless:type,personal
+	"required_unless": func(parent reflect.Value, p []string) bool {
+		v, ok := sibling(parent, p[0])
+		return ok && !(IsNotEmpty(v) && IsIn(v, p[1:]...))
+	},
+	// required_with:email,phone
+	"required_with": func(parent reflect.Value, p []string) bool {
+		for _, name := range p {
+			if v, ok := sibling(parent, name); ok && IsNotEmpty(v) {
+				return true
+			}
+		}
+		return false
+	},
+	// required_without:email,phone
+	"required_without": func(parent reflect.Value, p []string) bool {
+		for _, name := range p {
+			if v, ok := sibling(parent, name); ok && !IsNotEmpty(v) {
+				return true
+			}
+		}
+		return false
+	},
+}
+
+// exclusions are the conditional rules that the field must be empty
+// when the condition is met, ex. mutually exclusive fields.
+var exclusions = map[string]func(parent reflect.Value, params []string) bool{
+	// excluded_if:payment,cod
+	"excluded_if": conditionals["required_if"],
+}
+
+// required returns true when the conditional rule is met.
+func required(t validatorTag, parent reflect.Value) bool {
+	return met(conditionals, t, parent)
+}
+
+// excluded returns true when the field must be empty.
+func excluded(t validatorTag, parent reflect.Value) bool {
+	return met(exclusions, t, parent)
+}
+
+func met(rules map[string]func(reflect.Value, []string) bool, t validatorTag, parent reflect.Value) bool {
+	fn, ok := rules[t.Name]
+	if !ok || !parent.IsValid() {
+		return false
+	}
+
+	p := strings.Split(t.Param, ",")
+	for i := range p {
+		p[i] = strings.TrimSpace(p[i])
+	}
+
+	return p[0] != "" && fn(parent, p)
+}
+
+// sibling returns value of the field of the struct by its json name,
+// snake case name or the field name itself, nil pointer is returned as nil.
+func sibling(parent reflect.Value, name string) (interface{}, bool) {
+	t := parent.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		if strings.Split(sf.Tag.Get("json"), ",")[0] != name && utility.ToUnderscore(sf.Name) != name && sf.Name != name {
+			continue
+		}
+
+		f := parent.Field(i)
+		for f.Kind() == reflect.Ptr {
+			if f.IsNil() {
+				return nil, true
+			}
+			f = f.Elem()
+		}
+
+		return f.Interface(), true
+	}
+
+	return nil, false
+}
+
+// validExcluded fails the value that is not empty, zero value
+// like false or 0 is empty as well.
+func validExcluded(value interface{}, _ string) (v bool, m string) {
+	if v = value == nil || reflect.ValueOf(value).IsZero() || !IsNotEmpty(value); !v {
+		m = "The %s field must be empty"
+	}
+	return
+}
+
+// validConditional is evaluated by the validator, since it depends on the other fields.
+func validConditional(value interface{}, _ string) (v bool, m string) {
+	return true, ""
+}
@@ -0,0 +1,82 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+)
+
+type (
+	// ContextRequest is the Request validated with the context, ValidateContext
+	// is called instead of Validate, ex. to respect the deadline of the request
+	// or to read the tenant of the request.
+	ContextRequest interface {
+		Request
+		ValidateContext(ctx context.Context) *Response
+	}
+
+	// ContextRuleFunc validates the value using the context of the validation,
+	// it returns the message holding %s for the field name when it's invalid.
+	ContextRuleFunc func(ctx context.Context, value interface{}, param string) (bool, string)
+)
+
+// StructCtx same as Struct, the context is passed into the rules registered
+// by RegisterContextRule and RegisterProvider, and into ContextRequest.
+func (v *Validator) StructCtx(ctx context.Context, object interface{}) (res *Response) {
+	return v.structOf(object, &Meta{Context: ctx})
+}
+
+// RequestCtx same as Request with the context, see StructCtx.
+func (v *Validator) RequestCtx(ctx context.Context, object Request) (res *Response) {
+	return v.request(object, &Meta{Context: ctx})
+}
+
+// RegisterContextRule registers the rule receiving the context of the validation,
+// ex. Meta.Context passed by rest binder, so the rule can read request-scoped
+// values. Unlike RegisterProvider the empty value is passed into the rule.
+//
+//	v.RegisterContextRule("tenant_code", func(ctx context.Context, value interface{}, param string) (bool, string) {
+//		if !strings.HasPrefix(utility.ToString(value), tenant.FromContext(ctx).Prefix) {
+//			return false, "The %s must belong to the tenant"
+//		}
+//		return true, ""
+//	})
+func (v *Validator) RegisterContextRule(name string, fn ContextRuleFunc) {
+	fns := make(map[string]validatorFn, len(v.ValidatorFns)+1)
+	for k, f := range v.ValidatorFns {
+		fns[k] = f
+	}
+	fns[name] = validProvider
+	v.ValidatorFns = fns
+
+	rules := make(map[string]ContextRuleFunc, len(v.ctxRules)+1)
+	for k, f := range v.ctxRules {
+		rules[k] = f
+	}
+	rules[name] = fn
+	v.ctxRules = rules
+}
+
+// ContextRule returns option registering the rule, see RegisterContextRule.
+func ContextRule(name string, fn ContextRuleFunc) Option {
+	return func(v *Validator) {
+		v.RegisterContextRule(name, fn)
+	}
+}
+
+// ctx returns context of the meta, background when it's not set.
+func (m *Meta) ctx() context.Context {
+	if m != nil && m.Context != nil {
+		return m.Context
+	}
+	return context.Background()
+}
+
+// bind returns validator function of the rule with the context.
+func (fn ContextRuleFunc) bind(ctx context.Context) validatorFn {
+	return func(value interface{}, param string) (bool, string) {
+		return fn(ctx, value, param)
+	}
+}
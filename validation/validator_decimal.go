@@ -0,0 +1,140 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/enigma-id/go/utility"
+)
+
+// patternDecimal is plain decimal number, without exponent, hex or NaN.
+var patternDecimal = regexp.MustCompile(`^[+-]?([0-9]+)(?:\.([0-9]+))?$`)
+
+// decimalString returns the value as decimal string, float is formatted
+// with the shortest representation, ex. 12.5 of 12.50.
+func decimalString(value interface{}) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case json.Number:
+		return v.String()
+	}
+
+	return strings.TrimSpace(utility.ToString(value))
+}
+
+// IsDecimal check if the value is plain decimal number having at most precision
+// digits and scale digits after the point, same as DECIMAL(precision, scale)
+// of the database, ex. IsDecimal("12345678.90", 10, 2). Empty string is valid.
+func IsDecimal(value interface{}, precision, scale int) bool {
+	str := decimalString(value)
+	if !IsNotEmpty(str) {
+		return true
+	}
+
+	m := patternDecimal.FindStringSubmatch(str)
+	if m == nil {
+		return false
+	}
+
+	integer := strings.TrimLeft(m[1], "0")
+	fraction := strings.TrimRight(m[2], "0")
+
+	return len(fraction) <= scale && len(integer) <= precision-scale
+}
+
+// IsDecimalString check if the value is plain decimal number,
+// ex. "12.50" but not "1e3" or "NaN". Empty string is valid.
+func IsDecimalString(value interface{}) bool {
+	str := decimalString(value)
+	return !IsNotEmpty(str) || patternDecimal.MatchString(str)
+}
+
+// IsInteger check if the value is whole number, ex. "12" but not "12.0". Empty string is valid.
+func IsInteger(value interface{}) bool {
+	str := decimalString(value)
+	if !IsNotEmpty(str) {
+		return true
+	}
+
+	m := patternDecimal.FindStringSubmatch(str)
+	return m != nil && m[2] == ""
+}
+
+// validDecimal validates `decimal:10,2`, the precision and the scale,
+// `decimal:10` has no decimal places.
+func validDecimal(value interface{}, param string) (v bool, m string) {
+	p := strings.SplitN(param, ",", 2)
+	precision, err := strconv.Atoi(strings.TrimSpace(p[0]))
+	scale := 0
+	if err == nil && len(p) == 2 {
+		scale, err = strconv.Atoi(strings.TrimSpace(p[1]))
+	}
+
+	if v = err == nil && scale <= precision && IsDecimal(value, precision, scale); !v {
+		m = fmt.Sprintf("The %s must be a decimal of at most %d digits and %d decimal places", "%s", precision, scale)
+	}
+	return
+}
+
+// multipleEpsilon is the tolerance of the quotient of the floats,
+// ex. 0.3 is multiple of 0.1 despite the rounding.
+const multipleEpsilon = 1e-9
+
+// IsMultipleOf check if the number is multiple of n, ex. quantity sold in pack
+// of 6 or amount in increments of 100. The integers are checked exactly,
+// the floats with a small tolerance. Empty string is valid.
+func IsMultipleOf(value interface{}, n float64) bool {
+	if n == 0 || math.IsNaN(n) || math.IsInf(n, 0) {
+		return false
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(value))
+	if !rv.IsValid() {
+		return true
+	}
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n == math.Trunc(n) && math.Abs(n) <= math.MaxInt64 {
+			return rv.Int()%int64(n) == 0
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n == math.Trunc(n) && math.Abs(n) <= math.MaxInt64 {
+			return rv.Uint()%uint64(math.Abs(n)) == 0
+		}
+	}
+
+	str := decimalString(rv.Interface())
+	if !IsNotEmpty(str) {
+		return true
+	}
+
+	f, err := strconv.ParseFloat(str, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return false
+	}
+
+	q := f / n
+	return math.Abs(q-math.Round(q)) <= multipleEpsilon*math.Max(1, math.Abs(q))
+}
+
+// validMultipleOf validates `multiple_of:100` or `multiple_of:0.25`.
+func validMultipleOf(value interface{}, param string) (v bool, m string) {
+	n, err := strconv.ParseFloat(strings.TrimSpace(param), 64)
+	if v = err == nil && IsMultipleOf(value, n); !v {
+		m = fmt.Sprintf("The %s must be a multiple of %s", "%s", strings.TrimSpace(param))
+	}
+	return
+}
@@ -0,0 +1,70 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"reflect"
+)
+
+type (
+	// Rule is a rule of the tag with the aliases expanded,
+	// ex. {Name: "range", Param: "1,140"} of `range:1,140`.
+	Rule struct {
+		Name  string
+		Param string
+
+		// On is the scenarios of the rule, empty when it's always applied.
+		On []string
+	}
+
+	// FieldRules is the rules of a field of the struct, see Describe.
+	FieldRules struct {
+		// Name is the key of the failures of the field.
+		Name string
+
+		// Field is the name of the struct field.
+		Field string
+
+		Rules []Rule
+	}
+)
+
+// Rules returns the rules of the tag, ex. to document the constraints
+// of the field. It fails on the unknown rule like Precompile.
+func (v *Validator) Rules(tag string) ([]Rule, error) {
+	tags, err := v.rules(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]Rule, len(tags))
+	for i, t := range tags {
+		rules[i] = Rule{Name: t.Name, Param: t.Param, On: t.On}
+	}
+
+	return rules, nil
+}
+
+// Describe returns the rules of the fields of the struct having the tag,
+// the nested structs are not included, describe their type instead.
+// The field with invalid tag has no rules.
+func (v *Validator) Describe(object interface{}) []FieldRules {
+	t := reflect.TypeOf(object)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := v.fields(t)
+	desc := make([]FieldRules, len(fields))
+	for i, f := range fields {
+		desc[i] = FieldRules{Name: f.name, Field: f.goName}
+		desc[i].Rules, _ = v.Rules(f.tag)
+	}
+
+	return desc
+}
@@ -0,0 +1,49 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// each validates elements of the slice or array using the rule,
+// ex. `valid:"required|each:email"`, failures are keyed by the index
+// of the element (0.email). It returns false when any element fails.
+func (v *Validator) each(value interface{}, rule string, m *Meta, parent reflect.Value, res *Response) bool {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return true
+	}
+
+	valid := true
+	for i := 0; i < rv.Len(); i++ {
+		r := v.field(rv.Index(i).Interface(), rule, m, parent)
+		if r.Valid {
+			continue
+		}
+
+		for k, e := range r.FailMsg {
+			res.Failure(fmt.Sprintf("%d.%s", i, k), e)
+		}
+		res.mergeParams(fmt.Sprintf("%d.", i), r)
+		if r.err != nil {
+			res.err = r.err
+		}
+		if valid = false; v.failFast {
+			break
+		}
+	}
+
+	return valid
+}
+
+// validEach is evaluated by the validator, since it validates the elements.
+func validEach(value interface{}, _ string) (v bool, m string) {
+	return true, ""
+}
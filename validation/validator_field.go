@@ -0,0 +1,118 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/enigma-id/go/utility"
+)
+
+// comparisons of the rules that compare the value against the other
+// field of the struct, ex. `valid:"gte_field:StartDate"`.
+var comparisons = map[string]struct {
+	fn  func(c int) bool
+	msg string
+}{
+	"same_field": {func(c int) bool { return c == 0 }, "The %%s and %s must match"},
+	"gte_field":  {func(c int) bool { return c >= 0 }, "The %%s must be greater than or equal to %s"},
+	"lte_field":  {func(c int) bool { return c <= 0 }, "The %%s must be less than or equal to %s"},
+}
+
+// compareField validates the value of the field comparison rule,
+// empty value or empty other field are skipped like the other rules.
+func compareField(t validatorTag, value interface{}, parent reflect.Value) (bool, string) {
+	r, ok := comparisons[t.Name]
+	if !ok || !parent.IsValid() {
+		return true, ""
+	}
+
+	other, found := sibling(parent, t.Param)
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return true, ""
+		}
+		value = rv.Elem().Interface()
+	}
+	if !found || !IsNotEmpty(value) || !IsNotEmpty(other) {
+		return true, ""
+	}
+
+	if c, ok := compare(value, other); ok && r.fn(c) {
+		return true, ""
+	}
+
+	return false, fmt.Sprintf(r.msg, humanize(t.Param))
+}
+
+// humanize returns name of the field as written in the messages,
+// ex. StartDate and start_date are "start date".
+func humanize(name string) string {
+	return strings.Join(strings.FieldsFunc(utility.ToUnderscore(name), func(r rune) bool { return r == '_' }), " ")
+}
+
+// compare returns -1, 0 or 1 comparing a to b, time compared chronologically,
+// numbers and numeric strings by its value and the other strings lexically.
+func compare(a, b interface{}) (int, bool) {
+	av, bv := reflect.Indirect(reflect.ValueOf(a)), reflect.Indirect(reflect.ValueOf(b))
+	if !av.IsValid() || !bv.IsValid() {
+		return 0, false
+	}
+
+	if at, ok := av.Interface().(time.Time); ok {
+		bt, ok := bv.Interface().(time.Time)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case at.Before(bt):
+			return -1, true
+		case at.After(bt):
+			return 1, true
+		}
+		return 0, true
+	}
+
+	af, aok := number(av)
+	bf, bok := number(bv)
+	if aok && bok {
+		switch {
+		case af < bf:
+			return -1, true
+		case af > bf:
+			return 1, true
+		}
+		return 0, true
+	}
+
+	if av.Kind() == reflect.String && bv.Kind() == reflect.String {
+		return strings.Compare(av.String(), bv.String()), true
+	}
+
+	if av.Kind() == reflect.Bool && bv.Kind() == reflect.Bool && av.Bool() == bv.Bool() {
+		return 0, true
+	}
+
+	return 0, false
+}
+
+func number(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.String:
+		f, err := strconv.ParseFloat(v.String(), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -19,8 +19,24 @@ func validRequired(value interface{}, _ string) (v bool, m string) {
 	return
 }
 
-func validNumeric(value interface{}, _ string) (v bool, m string) {
-	if v = IsNumeric(value); !v {
+// validRequiredOn is evaluated by the validator, since it depends on the request metadata.
+func validRequiredOn(value interface{}, _ string) (v bool, m string) {
+	return true, ""
+}
+
+// validNumeric validates any number parsed as float, `numeric:decimal` only
+// accepts plain decimal like "12.50" and `numeric:integer` the whole number.
+func validNumeric(value interface{}, param string) (v bool, m string) {
+	switch param {
+	case "decimal":
+		v = IsDecimalString(value)
+	case "integer":
+		v = IsInteger(value)
+	default:
+		v = IsNumeric(value)
+	}
+
+	if !v {
 		m = "The %s must be a number"
 	}
 	return
@@ -75,6 +91,13 @@ func validLongitude(value interface{}, _ string) (v bool, m string) {
 	return
 }
 
+func validTimezone(value interface{}, _ string) (v bool, m string) {
+	if v = IsTimezone(value); !v {
+		m = "The %s must be a valid timezone."
+	}
+	return
+}
+
 func validURL(value interface{}, _ string) (v bool, m string) {
 	if v = IsURL(value); !v {
 		m = "The %s format is invalid"
@@ -82,6 +105,55 @@ func validURL(value interface{}, _ string) (v bool, m string) {
 	return
 }
 
+func validUUID(value interface{}, _ string) (v bool, m string) {
+	if v = IsUUID(value); !v {
+		m = "The %s must be a valid UUID"
+	}
+	return
+}
+
+func validUUID4(value interface{}, _ string) (v bool, m string) {
+	if v = IsUUID4(value); !v {
+		m = "The %s must be a valid UUID"
+	}
+	return
+}
+
+func validIP(value interface{}, _ string) (v bool, m string) {
+	if v = IsIP(value); !v {
+		m = "The %s must be a valid IP address"
+	}
+	return
+}
+
+func validIPv4(value interface{}, _ string) (v bool, m string) {
+	if v = IsIPv4(value); !v {
+		m = "The %s must be a valid IPv4 address"
+	}
+	return
+}
+
+func validIPv6(value interface{}, _ string) (v bool, m string) {
+	if v = IsIPv6(value); !v {
+		m = "The %s must be a valid IPv6 address"
+	}
+	return
+}
+
+func validMAC(value interface{}, _ string) (v bool, m string) {
+	if v = IsMAC(value); !v {
+		m = "The %s must be a valid MAC address"
+	}
+	return
+}
+
+func validHostname(value interface{}, _ string) (v bool, m string) {
+	if v = IsHostname(value); !v {
+		m = "The %s must be a valid hostname"
+	}
+	return
+}
+
 func validJSON(value interface{}, _ string) (v bool, m string) {
 	if v = IsJSON(value); !v {
 		m = "The %s must be a valid JSON string"
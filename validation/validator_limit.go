@@ -0,0 +1,96 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// limitRules are checked before the slice, map or struct is traversed,
+// so the oversized request fails with a single error.
+var limitRules = map[string]bool{
+	"max_items":  true,
+	"max_fields": true,
+}
+
+// limit validates the limit rules of the tag, it returns nil when they pass.
+func (v *Validator) limit(value reflect.Value, tag string, m *Meta) *Response {
+	tags, err := v.rules(tag)
+	if err != nil {
+		return nil
+	}
+
+	for _, t := range tags {
+		if !limitRules[t.Name] || (len(t.On) > 0 && !m.scenario(t.On)) {
+			continue
+		}
+
+		if ok, e := v.ValidatorFns[t.Name](value.Interface(), t.Param); !ok {
+			res := &Response{format: v.format}
+			res.Failure(t.Name, v.translate(v.localeOf(m), t.Param, e, t.Name, t.Name))
+			res.param(t.Name, t.Param)
+			return res
+		}
+	}
+
+	return nil
+}
+
+// validMaxItems checks total elements of the slices and arrays
+// including the nested ones, ex. `valid:"max_items:1000"`.
+func validMaxItems(value interface{}, param string) (bool, string) {
+	max, err := strconv.Atoi(param)
+	return err == nil && count(reflect.ValueOf(value), false, max, 0) <= max, "The %s may not have more than " + param + " items"
+}
+
+// validMaxFields checks total keys of the maps including
+// the nested ones, ex. free form json object.
+func validMaxFields(value interface{}, param string) (bool, string) {
+	max, err := strconv.Atoi(param)
+	return err == nil && count(reflect.ValueOf(value), true, max, 0) <= max, "The %s may not have more than " + param + " fields"
+}
+
+// count counts the elements or the map keys, it stops once the max is exceeded.
+func count(rv reflect.Value, keys bool, max int, depth int) (n int) {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return 0
+		}
+		rv = rv.Elem()
+	}
+	if depth > 64 {
+		return 0
+	}
+
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return 0
+		}
+		if !keys {
+			n = rv.Len()
+		}
+		for i := 0; i < rv.Len() && n <= max; i++ {
+			n += count(rv.Index(i), keys, max-n, depth+1)
+		}
+	case reflect.Map:
+		if keys {
+			n = rv.Len()
+		}
+		for it := rv.MapRange(); it.Next() && n <= max; {
+			n += count(it.Value(), keys, max-n, depth+1)
+		}
+	case reflect.Struct:
+		if rv.Type() == timeType {
+			return 0
+		}
+		for i := 0; i < rv.NumField() && n <= max; i++ {
+			n += count(rv.Field(i), keys, max-n, depth+1)
+		}
+	}
+
+	return n
+}
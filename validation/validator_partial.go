@@ -0,0 +1,93 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import "strings"
+
+// fieldFilter holds the fields validated by StructPartial
+// or skipped by StructExcept.
+type fieldFilter struct {
+	fields map[string]bool
+	except bool
+}
+
+// StructPartial same as Struct but only the fields are validated, ex. on PATCH
+// only the fields present in the request. The field is the key of the failure
+// or the struct field name, nested field is separated by dot, ex. "address.city",
+// and applies to every element of the slice. Struct level validations still run.
+func (v *Validator) StructPartial(object interface{}, fields ...string) (res *Response) {
+	return v.structOf(object, &Meta{fields: newFieldFilter(fields, false)})
+}
+
+// StructExcept same as Struct but the fields are not validated,
+// the fields are named the same as StructPartial.
+func (v *Validator) StructExcept(object interface{}, fields ...string) (res *Response) {
+	return v.structOf(object, &Meta{fields: newFieldFilter(fields, true)})
+}
+
+func newFieldFilter(fields []string, except bool) *fieldFilter {
+	f := &fieldFilter{fields: make(map[string]bool, len(fields)), except: except}
+	for _, n := range fields {
+		f.fields[n] = true
+	}
+
+	return f
+}
+
+// include returns true when the field with any of the names should be validated.
+func (m *Meta) include(names ...string) bool {
+	if m == nil || m.fields == nil {
+		return true
+	}
+
+	for _, n := range names {
+		if m.fields.fields[n] {
+			return !m.fields.except
+		}
+		if !m.fields.except && m.fields.nested(n) != nil {
+			return true
+		}
+	}
+
+	return m.fields.except
+}
+
+// sub returns the meta of the struct field, the filter is narrowed
+// into the nested fields.
+func (m *Meta) sub(names ...string) *Meta {
+	if m == nil || m.fields == nil {
+		return m
+	}
+
+	c := *m
+	c.fields = nil
+	for _, n := range names {
+		if m.fields.fields[n] {
+			// the whole field on partial
+			return &c
+		}
+		if f := m.fields.nested(n); f != nil {
+			c.fields = f
+			return &c
+		}
+	}
+
+	return &c
+}
+
+// nested returns the filter of the fields under the name, nil when there is none.
+func (f *fieldFilter) nested(name string) *fieldFilter {
+	var n *fieldFilter
+	for k := range f.fields {
+		if strings.HasPrefix(k, name+".") {
+			if n == nil {
+				n = &fieldFilter{fields: make(map[string]bool), except: f.except}
+			}
+			n.fields[k[len(name)+1:]] = true
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,219 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"encoding/base64"
+	"math/big"
+	"strings"
+
+	"github.com/enigma-id/go/utility"
+)
+
+// Card brands detected by CardBrand.
+const (
+	BrandVisa       = "visa"
+	BrandMastercard = "mastercard"
+	BrandAmex       = "amex"
+	BrandDiscover   = "discover"
+	BrandJCB        = "jcb"
+	BrandDiners     = "diners"
+	BrandUnionPay   = "unionpay"
+)
+
+// cardBrand is the prefix ranges and lengths of the card numbers of a brand.
+type cardBrand struct {
+	name     string
+	prefixes [][2]int // inclusive range of the prefix, of the same digits
+	lengths  []int
+}
+
+// cardBrands in the order they are detected, narrower prefixes first.
+var cardBrands = []cardBrand{
+	{BrandAmex, [][2]int{{34, 34}, {37, 37}}, []int{15}},
+	{BrandDiners, [][2]int{{300, 305}, {36, 36}, {38, 39}}, []int{14, 15, 16, 17, 18, 19}},
+	{BrandJCB, [][2]int{{3528, 3589}}, []int{16, 17, 18, 19}},
+	{BrandDiscover, [][2]int{{6011, 6011}, {644, 649}, {65, 65}}, []int{16, 17, 18, 19}},
+	{BrandUnionPay, [][2]int{{62, 62}}, []int{16, 17, 18, 19}},
+	{BrandMastercard, [][2]int{{51, 55}, {2221, 2720}}, []int{16}},
+	{BrandVisa, [][2]int{{4, 4}}, []int{13, 16, 19}},
+}
+
+// IsBase64 check if the value is a padded standard base64 string. Empty string is valid.
+func IsBase64(value interface{}) bool {
+	str := utility.ToString(value)
+	if !IsNotEmpty(str) {
+		return true
+	}
+
+	_, err := base64.StdEncoding.DecodeString(str)
+	return err == nil
+}
+
+// IsHex check if the value is a hexadecimal string, optionally prefixed by 0x. Empty string is valid.
+func IsHex(value interface{}) bool {
+	str := utility.ToString(value)
+	if !IsNotEmpty(str) {
+		return true
+	}
+
+	if len(str) > 2 && (str[:2] == "0x" || str[:2] == "0X") {
+		str = str[2:]
+	}
+	for _, r := range str {
+		if !('0' <= r && r <= '9' || 'a' <= r && r <= 'f' || 'A' <= r && r <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// IsIBAN check if the value is an IBAN with valid check digits,
+// spaces are ignored, ex. "GB82 WEST 1234 5698 7654 32". Empty string is valid.
+func IsIBAN(value interface{}) bool {
+	str := utility.ToString(value)
+	if !IsNotEmpty(str) {
+		return true
+	}
+
+	str = strings.ToUpper(strings.Replace(str, " ", "", -1))
+	if len(str) < 15 || len(str) > 34 {
+		return false
+	}
+	for i, r := range str {
+		letter, digit := 'A' <= r && r <= 'Z', '0' <= r && r <= '9'
+		if (i < 2 && !letter) || (i >= 2 && i < 4 && !digit) || (!letter && !digit) {
+			return false
+		}
+	}
+
+	// country and check digits are moved to the end, letters
+	// are replaced by 10-35 and the number mod 97 must be 1
+	var b strings.Builder
+	for _, r := range str[4:] + str[:4] {
+		if r >= 'A' {
+			b.WriteString(utility.ToString(int(r-'A') + 10))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	n, ok := new(big.Int).SetString(b.String(), 10)
+
+	return ok && n.Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+// IsCreditCard check if the value is a card number of a known brand with valid
+// Luhn checksum, spaces and dashes are ignored. Empty string is valid.
+func IsCreditCard(value interface{}) bool {
+	str := utility.ToString(value)
+	if !IsNotEmpty(str) {
+		return true
+	}
+	return CardBrand(str) != ""
+}
+
+// CardBrand returns the brand of the card number, ex. "visa", or
+// empty string when it's not a valid card number.
+func CardBrand(value interface{}) string {
+	num := strings.NewReplacer(" ", "", "-", "").Replace(utility.ToString(value))
+	if !luhn(num) {
+		return ""
+	}
+
+	for _, b := range cardBrands {
+		if b.match(num) {
+			return b.name
+		}
+	}
+	return ""
+}
+
+func (b cardBrand) match(num string) bool {
+	ok := false
+	for _, l := range b.lengths {
+		ok = ok || len(num) == l
+	}
+	if !ok {
+		return false
+	}
+
+	for _, p := range b.prefixes {
+		n := len(utility.ToString(p[0]))
+		if prefix := utility.ToInt(num[:n]); prefix >= p[0] && prefix <= p[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// luhn checks the digits of the number by Luhn algorithm.
+func luhn(num string) bool {
+	if len(num) < 12 {
+		return false
+	}
+
+	sum := 0
+	for i := range num {
+		d := int(num[len(num)-1-i] - '0')
+		if d < 0 || d > 9 {
+			return false
+		}
+		if i%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+func validBase64(value interface{}, _ string) (v bool, m string) {
+	if v = IsBase64(value); !v {
+		m = "The %s must be a valid base64 string"
+	}
+	return
+}
+
+func validHex(value interface{}, _ string) (v bool, m string) {
+	if v = IsHex(value); !v {
+		m = "The %s must be a valid hexadecimal string"
+	}
+	return
+}
+
+func validIBAN(value interface{}, _ string) (v bool, m string) {
+	if v = IsIBAN(value); !v {
+		m = "The %s must be a valid IBAN"
+	}
+	return
+}
+
+func validCreditCard(value interface{}, param string) (v bool, m string) {
+	return cardRule(nil)(value, param)
+}
+
+// cardRule returns the cc rule that keeps the detected brand on the response,
+// the param limits the accepted brands, ex. `cc:visa,mastercard`.
+func cardRule(res *Response) validatorFn {
+	return func(value interface{}, param string) (bool, string) {
+		if !IsNotEmpty(value) {
+			return true, ""
+		}
+
+		brand := CardBrand(value)
+		if brand == "" {
+			return false, "The %s must be a valid card number"
+		}
+		if param != "" && !IsIn(brand, strings.Split(strings.ToLower(strings.Replace(param, " ", "", -1)), ",")...) {
+			return false, "The %s must be a card of the following: " + listParam(param)
+		}
+
+		if res != nil {
+			res.brand("", brand)
+		}
+		return true, ""
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"strings"
+)
+
+type (
+	// ProviderFunc validates the value using external lookup, ex. database,
+	// params are the comma separated parameter of the rule. Error of the
+	// lookup fails the validation and it's returned by Response.Err.
+	ProviderFunc func(ctx context.Context, value interface{}, params []string) (bool, error)
+
+	// provider is the rule registered with RegisterProvider.
+	provider struct {
+		fn      ProviderFunc
+		message string
+	}
+)
+
+// RegisterProvider registers the rule that needs external lookup, the message
+// holds %s for the field name. The context is taken from Meta.Context, ex.
+// the request context passed by rest binder.
+//
+//	v.RegisterProvider("unique", "The %s has already been taken", func(ctx context.Context, value interface{}, p []string) (bool, error) {
+//		// unique:users,email
+//		return users.NotExists(ctx, p[0], p[1], value)
+//	})
+func (v *Validator) RegisterProvider(name string, message string, fn ProviderFunc) {
+	fns := make(map[string]validatorFn, len(v.ValidatorFns)+1)
+	for k, f := range v.ValidatorFns {
+		fns[k] = f
+	}
+	fns[name] = validProvider
+	v.ValidatorFns = fns
+
+	providers := make(map[string]provider, len(v.providers)+1)
+	for k, p := range v.providers {
+		providers[k] = p
+	}
+	providers[name] = provider{fn: fn, message: message}
+	v.providers = providers
+}
+
+// Provider returns option registering the rule, see RegisterProvider.
+// It's used to register the rule into validator of rest binder, ex.
+//
+//	rest.ValidatorOptions = append(rest.ValidatorOptions, validation.Provider("unique", msg, unique))
+func Provider(name string, message string, fn ProviderFunc) Option {
+	return func(v *Validator) {
+		v.RegisterProvider(name, message, fn)
+	}
+}
+
+// RegisterValues registers the source of the allowed values of `in:@name`
+// and `not_in:@name`, so the reference data can change without recompiling
+// the tags. The fn is called on each validation, cache it when it's costly.
+//
+//	v.RegisterValues("currencies", func() []string {
+//		return currencies.Codes()
+//	})
+//
+//	Currency string `valid:"required|in:@currencies"`
+func (v *Validator) RegisterValues(name string, fn func() []string) {
+	values := make(map[string]func() []string, len(v.values)+1)
+	for k, f := range v.values {
+		values[k] = f
+	}
+	values[name] = fn
+	v.values = values
+}
+
+// Values returns option registering the source of the values, see RegisterValues.
+func Values(name string, fn func() []string) Option {
+	return func(v *Validator) {
+		v.RegisterValues(name, fn)
+	}
+}
+
+// valuesRule returns in or not_in rule of the registered values source and
+// the values as the param of the message, source that is not registered
+// has no values.
+func (v *Validator) valuesRule(name, source string) (validatorFn, string) {
+	var values []string
+	if fn, ok := v.values[source]; ok {
+		values = fn()
+	}
+
+	return func(value interface{}, _ string) (ok bool, m string) {
+		if name == "not_in" {
+			ok = IsNotIn(value, values...)
+		} else {
+			ok = IsIn(value, values...)
+		}
+		if !ok {
+			m = "The selected %s is invalid"
+		}
+		return
+	}, strings.Join(values, ",")
+}
+
+// rule returns validator function of the provider using context of the meta,
+// error of the lookup is kept on the response.
+func (p provider) rule(m *Meta, res *Response) validatorFn {
+	ctx := m.ctx()
+	return func(value interface{}, param string) (bool, string) {
+		if !IsNotEmpty(value) {
+			return true, ""
+		}
+
+		params := strings.Split(param, ",")
+		for i := range params {
+			params[i] = strings.TrimSpace(params[i])
+		}
+
+		ok, err := p.fn(ctx, value, params)
+		if err != nil {
+			res.err = err
+			return false, "The %s could not be validated"
+		}
+		if !ok {
+			return false, p.message
+		}
+
+		return true, ""
+	}
+}
+
+// validProvider is evaluated by the validator, since it needs the context.
+func validProvider(value interface{}, _ string) (v bool, m string) {
+	return true, ""
+}
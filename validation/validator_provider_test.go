@@ -0,0 +1,134 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/enigma-id/go/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+type tenantKey struct{}
+
+func TestValidator_RegisterProvider(t *testing.T) {
+	type signup struct {
+		Email string `json:"email" valid:"required|email|unique:users,email"`
+		Code  string `json:"code" valid:"unique:users, referral"`
+	}
+
+	taken := map[string]bool{"users.email.a@b.co": true}
+	var tenant interface{}
+	unique := func(ctx context.Context, value interface{}, p []string) (bool, error) {
+		tenant = ctx.Value(tenantKey{})
+		if value == "down" {
+			return false, errors.New("database is down")
+		}
+		return !taken[p[0]+"."+p[1]+"."+value.(string)], nil
+	}
+
+	v := validation.New(validation.Provider("unique", "The %s has already been taken", unique))
+	assert.True(t, v.Struct(signup{Email: "c@d.co"}).Valid)
+
+	r := v.Struct(signup{Email: "a@b.co"})
+	assert.Equal(t, map[string]string{"email": "The email has already been taken"}, r.GetErrors())
+	assert.NoError(t, r.Err())
+
+	// format is checked before the lookup
+	assert.Equal(t, "The email must be a valid email address", v.Struct(signup{Email: "x"}).GetMessage("email.email"))
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	r = v.StructMeta(signup{Email: "c@d.co", Code: "down"}, validation.Meta{Context: ctx})
+	assert.EqualError(t, r.Err(), "database is down")
+	assert.Equal(t, "The code could not be validated", r.GetMessage("code.unique"))
+	assert.Equal(t, "acme", tenant)
+
+	// not registered on the other validators
+	assert.True(t, validation.New().Struct(signup{Email: "a@b.co"}).Valid)
+}
+
+func TestValidator_RegisterValues(t *testing.T) {
+	type payment struct {
+		Currency string   `json:"currency" valid:"required|in:@currencies"`
+		Country  string   `json:"country" valid:"not_in:@embargoed"`
+		Methods  []string `json:"methods" valid:"required|each:in:@methods"`
+	}
+
+	currencies := []string{"IDR", "USD"}
+	v := validation.New(
+		validation.Values("currencies", func() []string { return currencies }),
+		validation.Values("embargoed", func() []string { return []string{"KP"} }),
+	)
+	v.RegisterValues("methods", func() []string { return []string{"card", "transfer"} })
+
+	assert.True(t, v.Struct(payment{Currency: "IDR", Country: "ID", Methods: []string{"card"}}).Valid)
+
+	r := v.Struct(payment{Currency: "SGD", Country: "KP", Methods: []string{"card", "cash"}})
+	assert.Equal(t, map[string]string{
+		"currency":  "The selected currency is invalid",
+		"country":   "The selected country is invalid",
+		"methods.1": "The selected methods is invalid",
+	}, r.GetErrors())
+	assert.Equal(t, "IDR, USD", r.Args("currency.in")["values"])
+
+	// reference data changes without recompiling the tags
+	currencies = append(currencies, "SGD")
+	assert.True(t, v.Struct(payment{Currency: "SGD", Methods: []string{"card"}}).Valid)
+
+	// source that is not registered has no values
+	assert.False(t, validation.New().Struct(payment{Currency: "IDR", Methods: []string{"card"}}).Valid)
+}
+
+type transfer struct {
+	Account string `json:"account" valid:"required|tenant_account"`
+	Amount  int    `json:"amount" valid:"gt:0"`
+}
+
+func (t transfer) Messages() map[string]string { return nil }
+
+func (t transfer) Validate() *validation.Response {
+	return validation.SetError("amount.limit", "The amount exceeds the limit")
+}
+
+func (t transfer) ValidateContext(ctx context.Context) *validation.Response {
+	if ctx.Err() != nil {
+		return validation.SetError("account.deadline", "The account could not be validated")
+	}
+	if ctx.Value(tenantKey{}) == "acme" && t.Amount > 100 {
+		return validation.SetError("amount.limit", "The amount exceeds the limit of acme")
+	}
+	return nil
+}
+
+func TestValidator_Context(t *testing.T) {
+	rule := func(ctx context.Context, value interface{}, _ string) (bool, string) {
+		tenant, _ := ctx.Value(tenantKey{}).(string)
+		if tenant == "" || value.(string)[:len(tenant)] != tenant {
+			return false, "The %s must belong to the tenant"
+		}
+		return true, ""
+	}
+	v := validation.New(validation.ContextRule("tenant_account", rule))
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	assert.True(t, v.StructCtx(ctx, transfer{Account: "acme-1", Amount: 500}).Valid)
+	assert.Equal(t, map[string]string{"account": "The account must belong to the tenant"}, v.StructCtx(ctx, transfer{Account: "umbrella-1", Amount: 1}).GetErrors())
+	assert.False(t, v.Struct(transfer{Account: "acme-1", Amount: 1}).Valid)
+
+	// ValidateContext is called instead of Validate
+	r := v.RequestCtx(ctx, transfer{Account: "acme-1", Amount: 500})
+	assert.Equal(t, map[string]string{"amount": "The amount exceeds the limit of acme"}, r.GetErrors())
+	assert.True(t, v.RequestCtx(ctx, transfer{Account: "acme-1", Amount: 50}).Valid)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	r = v.RequestMeta(transfer{Account: "acme-1", Amount: 50}, validation.Meta{Context: canceled})
+	assert.Equal(t, "The account could not be validated", r.GetMessage("account.deadline"))
+
+	// not registered on the other validators
+	assert.Error(t, validation.New().Precompile(transfer{}))
+}
@@ -0,0 +1,125 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"container/list"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sync"
+)
+
+// RegexCacheSize is the number of compiled patterns of the match rule kept
+// in the cache, the least recently used pattern is evicted.
+var RegexCacheSize = 512
+
+var regexes = &regexCache{ll: list.New(), items: make(map[string]*list.Element)}
+
+type (
+	regexCache struct {
+		mu    sync.Mutex
+		ll    *list.List
+		items map[string]*list.Element
+	}
+
+	regexEntry struct {
+		pattern string
+		re      *regexp.Regexp
+		err     error
+	}
+)
+
+// compileRegex returns the compiled pattern from the cache.
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	c := regexes
+	c.mu.Lock()
+	if el, ok := c.items[pattern]; ok {
+		c.ll.MoveToFront(el)
+		c.mu.Unlock()
+
+		e := el.Value.(*regexEntry)
+		return e.re, e.err
+	}
+	c.mu.Unlock()
+
+	re, err := regexp.Compile(pattern)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.items[pattern]; !ok {
+		c.items[pattern] = c.ll.PushFront(&regexEntry{pattern, re, err})
+		for c.ll.Len() > RegexCacheSize && c.ll.Len() > 1 {
+			el := c.ll.Back()
+			c.ll.Remove(el)
+			delete(c.items, el.Value.(*regexEntry).pattern)
+		}
+	}
+
+	return re, err
+}
+
+// Precompile checks the rules of the struct types and compiles the patterns
+// of the match rules, call it on startup so invalid tag fails there instead
+// of silently failing the requests, ex. mw.ValidateBody calls it on the request.
+func (v *Validator) Precompile(objects ...interface{}) error {
+	seen := make(map[reflect.Type]bool)
+	for _, o := range objects {
+		if err := v.precompile(reflect.TypeOf(o), seen); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *Validator) precompile(t reflect.Type, seen map[reflect.Type]bool) error {
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || t == timeType || seen[t] {
+		return nil
+	}
+	seen[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if tag := f.Tag.Get(v.TagName); tag != "" && tag != "-" {
+			if err := v.checkTag(tag); err != nil {
+				return fmt.Errorf("validation: invalid tag of %s.%s, %s", t.Name(), f.Name, err.Error())
+			}
+		}
+
+		if err := v.precompile(f.Type, seen); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *Validator) checkTag(tag string) error {
+	tags, err := v.rules(tag)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tags {
+		name, param := t.Name, t.Param
+		if name == "each" {
+			if err := v.checkTag(param); err != nil {
+				return err
+			}
+			continue
+		}
+		if name == "match" {
+			if _, err := compileRegex(param); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
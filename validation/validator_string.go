@@ -0,0 +1,105 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/enigma-id/go/utility"
+)
+
+// IsStartsWith check if the value starts with one of the prefixes. Empty string is valid.
+func IsStartsWith(value interface{}, prefixes ...string) bool {
+	str := utility.ToString(value)
+	if !IsNotEmpty(str) {
+		return true
+	}
+
+	for _, p := range prefixes {
+		if strings.HasPrefix(str, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEndsWith check if the value ends with one of the suffixes. Empty string is valid.
+func IsEndsWith(value interface{}, suffixes ...string) bool {
+	str := utility.ToString(value)
+	if !IsNotEmpty(str) {
+		return true
+	}
+
+	for _, s := range suffixes {
+		if strings.HasSuffix(str, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsLowercase check if the value has no upper case letters. Empty string is valid.
+func IsLowercase(value interface{}) bool {
+	str := utility.ToString(value)
+	return str == strings.ToLower(str)
+}
+
+// IsUppercase check if the value has no lower case letters. Empty string is valid.
+func IsUppercase(value interface{}) bool {
+	str := utility.ToString(value)
+	return str == strings.ToUpper(str)
+}
+
+// IsASCII check if the value contains only ASCII characters. Empty string is valid.
+func IsASCII(value interface{}) bool {
+	for _, r := range utility.ToString(value) {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
+func validStartsWith(value interface{}, param string) (v bool, m string) {
+	if v = IsStartsWith(value, strings.Split(param, ",")...); !v {
+		m = "The %s must start with one of the following: " + listParam(param)
+	}
+	return
+}
+
+func validEndsWith(value interface{}, param string) (v bool, m string) {
+	if v = IsEndsWith(value, strings.Split(param, ",")...); !v {
+		m = "The %s must end with one of the following: " + listParam(param)
+	}
+	return
+}
+
+func validLowercase(value interface{}, _ string) (v bool, m string) {
+	if v = IsLowercase(value); !v {
+		m = "The %s must be lowercase"
+	}
+	return
+}
+
+func validUppercase(value interface{}, _ string) (v bool, m string) {
+	if v = IsUppercase(value); !v {
+		m = "The %s must be uppercase"
+	}
+	return
+}
+
+func validASCII(value interface{}, _ string) (v bool, m string) {
+	if v = IsASCII(value); !v {
+		m = "The %s may only contain ASCII characters"
+	}
+	return
+}
+
+// listParam returns the comma separated param to be put into the message,
+// the message goes through Sprintf with the field name.
+func listParam(param string) string {
+	return strings.Replace(strings.Replace(param, "%", "%%", -1), ",", ", ", -1)
+}
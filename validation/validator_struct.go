@@ -0,0 +1,90 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"reflect"
+	"sync"
+)
+
+// StructValidationFunc validates the struct after its fields are validated,
+// used for cross fields invariants of the structs we don't own. The failures
+// are keyed just like Request.Validate, ex. SetError("end_at.after", "...").
+type StructValidationFunc func(object interface{}) *Response
+
+// structValidations holding the struct validations by the struct type.
+type structValidations struct {
+	mu  sync.RWMutex
+	fns map[reflect.Type][]StructValidationFunc
+}
+
+// global struct validations used by every validator.
+var globalStructs = &structValidations{}
+
+// RegisterStructValidation registers the struct validation of the types into
+// every validator, including the one used by rest binder.
+//
+//	validation.RegisterStructValidation(func(o interface{}) *validation.Response {
+//		p := o.(billing.Period)
+//		if !p.End.After(p.Start) {
+//			return validation.SetError("end.after", "The end must be after the start")
+//		}
+//		return nil
+//	}, billing.Period{})
+func RegisterStructValidation(fn StructValidationFunc, types ...interface{}) {
+	globalStructs.register(fn, types)
+}
+
+// RegisterStructValidation registers the struct validation of the types,
+// pointer or value of the struct can be passed as the type.
+func (v *Validator) RegisterStructValidation(fn StructValidationFunc, types ...interface{}) {
+	if v.structs == nil {
+		v.structs = &structValidations{}
+	}
+	v.structs.register(fn, types)
+}
+
+func (s *structValidations) register(fn StructValidationFunc, types []interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fns == nil {
+		s.fns = make(map[reflect.Type][]StructValidationFunc)
+	}
+	for _, o := range types {
+		t := reflect.TypeOf(o)
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		s.fns[t] = append(s.fns[t], fn)
+	}
+}
+
+func (s *structValidations) get(t reflect.Type) []StructValidationFunc {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.fns[t]
+}
+
+// validStruct runs the struct validations of the struct value.
+func (v *Validator) validStruct(object reflect.Value, res *Response) {
+	fns := append(globalStructs.get(object.Type()), v.structs.get(object.Type())...)
+	for _, fn := range fns {
+		if v.stop(res) {
+			return
+		}
+
+		if r := fn(object.Interface()); r != nil && !r.Valid {
+			for k, e := range r.compile().GetMessages() {
+				res.Failure(k, e)
+			}
+		}
+	}
+}
@@ -14,9 +14,23 @@ type validatorTag struct {
 	Name  string
 	Param string
 	Fn    validatorFn
+
+	// On is the scenarios of the rule, ex. `required;on=create,update`,
+	// the rule is applied on every scenario when it's empty.
+	On []string
+}
+
+// parseTag splits the tag into the rules with the aliases expanded,
+// the Fn is not resolved.
+func parseTag(tag string) (vt []validatorTag, e error) {
+	if vt, e = splitTag(tag); e == nil {
+		vt, e = expandAliases(vt, 0)
+	}
+	return
 }
 
-func fetchTag(tag string, tFn map[string]validatorFn) (vt []validatorTag, e error) {
+// splitTag splits the tag into the rules.
+func splitTag(tag string) (vt []validatorTag, e error) {
 	if tag == "-" {
 		e = errors.New("tag skipped")
 		return
@@ -27,6 +41,15 @@ func fetchTag(tag string, tFn map[string]validatorFn) (vt []validatorTag, e erro
 
 	for _, i := range tl {
 		t := validatorTag{}
+		if x := strings.LastIndex(i, ";on="); x != -1 {
+			for _, sc := range strings.Split(i[x+4:], ",") {
+				if sc = strings.TrimSpace(sc); sc != "" {
+					t.On = append(t.On, sc)
+				}
+			}
+			i = i[:x]
+		}
+
 		p := strings.SplitN(i, ":", 2)
 
 		if t.Name = strings.Trim(p[0], " "); t.Name == "" {
@@ -38,14 +61,12 @@ func fetchTag(tag string, tFn map[string]validatorFn) (vt []validatorTag, e erro
 			t.Param = strings.Trim(p[1], " ")
 		}
 
-		var found bool
-		if t.Fn, found = tFn[t.Name]; !found {
-			e = fmt.Errorf("cannot find any tag function with name %s", t.Name)
-			break
-		}
-
 		vt = append(vt, t)
 	}
 
 	return
 }
+
+func errUnknownTag(name string) error {
+	return fmt.Errorf("cannot find any tag function with name %s", name)
+}
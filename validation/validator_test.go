@@ -5,8 +5,10 @@
 package validation_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"testing"
 	"time"
 
@@ -27,8 +29,9 @@ func TestValidator_Field(t *testing.T) {
 	r = v.Field(nil, "nonexistingtag:1")
 	assert.True(t, r.Valid)
 
-	r = v.Field("5398228707871528", "cc")
+	r = v.Field("5398228707871527", "cc")
 	assert.True(t, r.Valid)
+	assert.Equal(t, validation.BrandMastercard, r.Brand(""))
 
 	var tests = []struct {
 		value    interface{}
@@ -39,7 +42,67 @@ func TestValidator_Field(t *testing.T) {
 		{nil, "required", false},
 		{0, "numeric", true},
 		{"abcd", "numeric", false},
+		{"12.50", "numeric:decimal", true},
+		{"1e3", "numeric", true},
+		{"1e3", "numeric:decimal", false},
+		{"12.50", "numeric:integer", false},
+		{"1250", "numeric:integer", true},
+		{"1250.75", "decimal:6,2", true},
+		{"1250.755", "decimal:6,2", false},
+		{"99999.00", "decimal:6,2", false},
+		{"12", "decimal:2", true},
+		{"12.5", "decimal:2", false},
+		{"12.5", "decimal:x", false},
+		{12, "multiple_of:6", true},
+		{10, "multiple_of:6", false},
+		{uint(300), "multiple_of:100", true},
+		{int64(-200), "multiple_of:100", true},
+		{"aGVsbG8=", "base64", true},
+		{"aGVsbG8", "base64", false},
+		{"0x1fA9", "hex", true},
+		{"1g", "hex", false},
+		{"GB82 WEST 1234 5698 7654 32", "iban", true},
+		{"GB82WEST12345698765433", "iban", false},
+		{"ID12", "iban", false},
+		{"4111 1111 1111 1111", "cc", true},
+		{"5398228707871528", "cc", false},
+		{"378282246310005", "cc:visa,mastercard", false},
+		{"4111-1111-1111-1111", "cc:Visa,mastercard", true},
+		{0.3, "multiple_of:0.1", true},
+		{1.05, "multiple_of:0.25", false},
+		{"2500", "multiple_of:100", true},
+		{"2550", "divisible_by:100", false},
+		{"", "multiple_of:100", true},
+		{"abc", "multiple_of:100", false},
+		{12, "multiple_of:0", false},
+		{12, "multiple_of:x", false},
 		{0, "required|numeric", false},
+		{"INV-001", "starts_with:INV-,PO-", true},
+		{"SO-001", "starts_with:INV-,PO-", false},
+		{"", "starts_with:INV-", true},
+		{"report.pdf", "ends_with:.pdf,.csv", true},
+		{"report.exe", "ends_with:.pdf,.csv", false},
+		{"john_doe-1", "lowercase", true},
+		{"John", "lowercase", false},
+		{"ID-01", "uppercase", true},
+		{"Id", "uppercase", false},
+		{"hello, world!", "ascii", true},
+		{"héllo", "ascii", false},
+		{-6.2, "latitude", true},
+		{"90.1", "latitude", false},
+		{-91, "latitude", false},
+		{0.0000001, "latitude", true},
+		{"NaN", "latitude", false},
+		{"", "latitude", true},
+		{106.8, "longitude", true},
+		{"-180", "longitude", true},
+		{180.5, "longitude", false},
+		{"east", "longitude", false},
+		{"Asia/Jakarta", "timezone", true},
+		{"UTC", "timezone", true},
+		{"Asia/Bandung", "timezone", false},
+		{"Local", "timezone", false},
+		{"../../etc/passwd", "timezone", false},
 		{"abcd", "alpha", true},
 		{"abcd123", "alpha", false},
 		{"abcd", "alpha_num", true},
@@ -337,3 +400,882 @@ func TestSetError(t *testing.T) {
 	e := validation.SetError("email", "email is not valid")
 	assert.Equal(t, "email is not valid", e.GetMessage("email"))
 }
+
+func TestValidator_RequiredOn(t *testing.T) {
+	type product struct {
+		Name  string `json:"name" valid:"required_on:POST|alpha_space"`
+		Price int    `json:"price" valid:"required_on:POST,PUT|gt:0"`
+		Code  string `json:"code" valid:"required_on:/products/import"`
+	}
+
+	v := validation.New()
+
+	r := v.StructMeta(&product{}, validation.Meta{Method: "POST"})
+	assert.False(t, r.Valid)
+	assert.Equal(t, "The name field is required", r.GetMessage("name.required_on"))
+	assert.Equal(t, "The price field is required", r.GetMessage("price.required_on"))
+	assert.Empty(t, r.GetMessage("code.required_on"))
+
+	// optional on partial update, but still validated when present
+	r = v.StructMeta(&product{}, validation.Meta{Method: "PATCH"})
+	assert.True(t, r.Valid)
+
+	r = v.StructMeta(&product{Name: "Kopi 123", Price: -1}, validation.Meta{Method: "patch"})
+	assert.False(t, r.Valid)
+	assert.NotEmpty(t, r.GetMessage("name.alpha_space"))
+	assert.NotEmpty(t, r.GetMessage("price.gt"))
+
+	r = v.StructMeta(&product{Name: "Kopi", Price: 1}, validation.Meta{Method: "POST", Route: "/products/import"})
+	assert.NotEmpty(t, r.GetMessage("code.required_on"))
+
+	// without metadata the rule never applies
+	assert.True(t, v.Struct(&product{}).Valid)
+}
+
+func TestValidator_RequiredConditional(t *testing.T) {
+	type customer struct {
+		Type        string  `json:"type"`
+		CompanyName string  `json:"company_name" valid:"required_if:type,company,foundation"`
+		NIK         string  `json:"nik" valid:"required_unless:type,company,foundation|numeric"`
+		Email       string  `json:"email" valid:"email"`
+		Phone       *string `json:"phone"`
+		Password    string  `json:"password" valid:"required_with:email,phone"`
+		TaxID       string  `json:"tax_id" valid:"required_if:IsTaxable,true"`
+		IsTaxable   bool
+	}
+
+	v := validation.New()
+
+	r := v.Struct(&customer{Type: "company"})
+	assert.Equal(t, "The company name field is required", r.GetMessage("company_name.required_if"))
+	assert.Empty(t, r.GetMessage("nik.required_unless"))
+	assert.Empty(t, r.GetMessage("password.required_with"))
+
+	r = v.Struct(&customer{Type: "personal"})
+	assert.Empty(t, r.GetMessage("company_name.required_if"))
+	assert.Equal(t, "The nik field is required", r.GetMessage("nik.required_unless"))
+
+	r = v.Struct(customer{Type: "personal", NIK: "abc"})
+	assert.NotEmpty(t, r.GetMessage("nik.numeric"))
+
+	r = v.Struct(&customer{NIK: "3171", Email: "jon@kora.id"})
+	assert.Equal(t, "The password field is required", r.GetMessage("password.required_with"))
+
+	phone := "08123"
+	r = v.Struct(&customer{NIK: "3171", Phone: &phone})
+	assert.NotEmpty(t, r.GetMessage("password.required_with"))
+
+	r = v.Struct(&customer{NIK: "3171", IsTaxable: true})
+	assert.NotEmpty(t, r.GetMessage("tax_id.required_if"))
+
+	assert.True(t, v.Struct(&customer{NIK: "3171"}).Valid)
+
+	// no sibling to compare on single field
+	assert.True(t, v.Field("", "required_if:type,company").Valid)
+}
+
+func TestValidator_RequiredWithoutExcludedIf(t *testing.T) {
+	type order struct {
+		Payment    string `json:"payment"`
+		CustomerID int    `json:"customer_id" valid:"required_without:guest_email"`
+		GuestEmail string `json:"guest_email" valid:"required_without:customer_id|excluded_if:customer_id,0|email"`
+		CardToken  string `json:"card_token" valid:"excluded_if:payment,cod,transfer"`
+		Insured    bool   `json:"insured" valid:"excluded_if:payment,cod"`
+	}
+
+	v := validation.New()
+
+	r := v.Struct(order{})
+	assert.Equal(t, "The customer id field is required", r.GetMessage("customer_id.required_without"))
+	assert.Equal(t, "The guest email field is required", r.GetMessage("guest_email.required_without"))
+
+	assert.True(t, v.Struct(order{CustomerID: 1}).Valid)
+	assert.True(t, v.Struct(order{GuestEmail: "jon@kora.id"}).Valid)
+
+	r = v.Struct(order{CustomerID: 1, Payment: "cod", CardToken: "tok_1", Insured: true})
+	assert.Equal(t, map[string]string{
+		"card_token": "The card token field must be empty",
+		"insured":    "The insured field must be empty",
+	}, r.GetErrors())
+	assert.True(t, v.Struct(order{CustomerID: 1, Payment: "card", CardToken: "tok_1", Insured: true}).Valid)
+	assert.True(t, v.Struct(order{CustomerID: 1, Payment: "cod"}).Valid)
+
+	assert.Equal(t, ":attribute harus dikosongkan", validation.Indonesian["excluded_if"])
+}
+
+func TestValidator_FieldComparison(t *testing.T) {
+	type register struct {
+		Password             string    `json:"password" valid:"required"`
+		PasswordConfirmation string    `json:"password_confirmation" valid:"required|same_field:Password"`
+		StartDate            time.Time `json:"start_date"`
+		EndDate              time.Time `json:"end_date" valid:"gte_field:StartDate"`
+		MinPrice             float64   `json:"min_price"`
+		MaxPrice             int       `json:"max_price" valid:"gte_field:min_price"`
+		Limit                *int      `json:"limit"`
+		From                 string    `json:"from"`
+		Until                string    `json:"until" valid:"gte_field:from"`
+		Discount             int       `json:"discount" valid:"lte_field:limit"`
+	}
+
+	v := validation.New()
+	now := time.Now()
+	limit := 100
+
+	r := v.Struct(register{
+		Password: "secret", PasswordConfirmation: "secreT",
+		StartDate: now, EndDate: now.Add(-time.Hour),
+		MinPrice: 150.5, MaxPrice: 100, Limit: &limit,
+		From: "2019-02-01", Until: "2019-01-31",
+		Discount: 101,
+	})
+	assert.False(t, r.Valid)
+	assert.Equal(t, "The password confirmation and password must match", r.GetMessage("password_confirmation.same_field"))
+	assert.Equal(t, "The end date must be greater than or equal to start date", r.GetMessage("end_date.gte_field"))
+	assert.Equal(t, "The max price must be greater than or equal to min price", r.GetMessage("max_price.gte_field"))
+	assert.NotEmpty(t, r.GetMessage("until.gte_field"))
+	assert.Equal(t, "The discount must be less than or equal to limit", r.GetMessage("discount.lte_field"))
+
+	r = v.Struct(&register{
+		Password: "secret", PasswordConfirmation: "secret",
+		StartDate: now, EndDate: now,
+		MinPrice: 99.5, MaxPrice: 100, Limit: &limit,
+		From: "2019-01-31", Until: "2019-02-01",
+		Discount: 100,
+	})
+	assert.True(t, r.Valid, r.Error())
+
+	// empty values are skipped
+	r = v.Struct(&register{Password: "secret", PasswordConfirmation: "secret", Discount: 10})
+	assert.True(t, r.Valid, r.Error())
+
+	// no sibling to compare on single field
+	assert.True(t, v.Field("secret", "same_field:Password").Valid)
+}
+
+func TestValidator_NetworkRules(t *testing.T) {
+	type server struct {
+		ID       string `json:"id" valid:"required|uuid4"`
+		Address  string `json:"address" valid:"ip"`
+		Gateway  string `json:"gateway" valid:"ipv4"`
+		Address6 string `json:"address6" valid:"ipv6"`
+		MAC      string `json:"mac" valid:"mac"`
+		Hostname string `json:"hostname" valid:"required|hostname"`
+		TraceID  string `json:"trace_id" valid:"uuid"`
+	}
+
+	v := validation.New()
+	r := v.Struct(server{
+		ID: "625e63f3-58f5-40b7-83a1-a72ad31acffb", Address: "10.0.0.2", Gateway: "10.0.0.1",
+		Address6: "fe80::1", MAC: "3d:f2:c9:a6:b3:4f", Hostname: "db-01.internal",
+	})
+	assert.True(t, r.Valid, r.Error())
+
+	r = v.Struct(server{
+		ID: "a987fbc9-4bed-3078-cf07-9141ba07c9f3", Address: "10.0.0", Gateway: "::1",
+		Address6: "10.0.0.1", MAC: "3d:f2", Hostname: "db_01", TraceID: "x",
+	})
+	assert.Equal(t, "The id must be a valid UUID", r.GetMessage("id.uuid4"))
+	assert.Equal(t, "The address must be a valid IP address", r.GetMessage("address.ip"))
+	assert.Equal(t, "The gateway must be a valid IPv4 address", r.GetMessage("gateway.ipv4"))
+	assert.Equal(t, "The address6 must be a valid IPv6 address", r.GetMessage("address6.ipv6"))
+	assert.Equal(t, "The mac must be a valid MAC address", r.GetMessage("mac.mac"))
+	assert.Equal(t, "The hostname must be a valid hostname", r.GetMessage("hostname.hostname"))
+	assert.Equal(t, "The trace id must be a valid UUID", r.GetMessage("trace_id.uuid"))
+}
+
+func TestValidator_Phone(t *testing.T) {
+	type contact struct {
+		Phone  validation.Phone `json:"phone" valid:"required|phone:id"`
+		Office string           `json:"office" valid:"phone"`
+	}
+
+	var c contact
+	assert.NoError(t, json.Unmarshal([]byte(`{"phone": "0812-3456-7890", "office": "+14155552671"}`), &c))
+	assert.Equal(t, validation.Phone("+6281234567890"), c.Phone)
+
+	v := validation.New()
+	assert.True(t, v.Struct(c).Valid)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"phone": "+14155552671", "office": "021"}`), &c))
+	r := v.Struct(c)
+	assert.Equal(t, "The phone must be a valid phone number", r.GetMessage("phone.phone"))
+	assert.NotEmpty(t, r.GetMessage("office.phone"))
+
+	r = v.Struct(contact{})
+	assert.Equal(t, "The phone field is required", r.GetMessage("phone.required"))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"phone": 812}`), &c))
+}
+
+func TestValidator_Map(t *testing.T) {
+	type section struct {
+		Title string `json:"title" valid:"required"`
+		Order int    `json:"order" valid:"gte:1"`
+	}
+	type form struct {
+		Sections map[string]section     `json:"sections" valid:"required"`
+		Pointers map[string]*section    `json:"pointers" valid:"required"`
+		Contacts map[string]string      `json:"contacts" valid:"required|email"`
+		Extra    map[string]interface{} `json:"extra" valid:"numeric"`
+	}
+
+	v := validation.New()
+	assert.True(t, v.Struct(form{
+		Sections: map[string]section{"intro": {Title: "Intro", Order: 1}},
+		Pointers: map[string]*section{"outro": {Title: "Outro", Order: 2}},
+		Contacts: map[string]string{"home": "home@example.com"},
+		Extra:    map[string]interface{}{"weight": "12"},
+	}).Valid)
+
+	r := v.Struct(form{
+		Sections: map[string]section{"intro": {Order: 1}, "body": {Title: "Body"}},
+		Pointers: map[string]*section{"outro": {Order: 2}},
+		Contacts: map[string]string{"home": "home@example.com", "work": "work"},
+		Extra:    map[string]interface{}{"weight": "heavy"},
+	})
+	assert.False(t, r.Valid)
+	assert.Equal(t, "The title field is required", r.GetMessage("sections.intro.title.required"))
+	assert.NotEmpty(t, r.GetMessage("sections.body.order.gte"))
+	assert.NotEmpty(t, r.GetMessage("pointers.outro.title.required"))
+	assert.Equal(t, "The contacts must be a valid email address", r.GetMessage("contacts.work.email"))
+	assert.Empty(t, r.GetMessage("contacts.home.email"))
+	assert.NotEmpty(t, r.GetMessage("extra.weight.numeric"))
+
+	r = v.Struct(form{})
+	assert.NotEmpty(t, r.GetMessage("sections.required"))
+	assert.Equal(t, "The contacts field is required", r.GetMessage("contacts.required"))
+}
+
+func TestValidator_Scenario(t *testing.T) {
+	type user struct {
+		Email    string `json:"email" valid:"required;on=create|email"`
+		Password string `json:"password" valid:"required;on=create,reset|gte:8;on=create,update,reset"`
+		Role     string `json:"role" valid:"in:admin,staff"`
+	}
+
+	v := validation.New()
+
+	r := v.StructScenario(user{Role: "guest"}, "create")
+	assert.NotEmpty(t, r.GetMessage("email.required"))
+	assert.NotEmpty(t, r.GetMessage("password.required"))
+	assert.NotEmpty(t, r.GetMessage("role.in"))
+
+	r = v.StructScenario(user{Email: "x", Password: "long enough"}, "update")
+	assert.Equal(t, map[string]string{"email": "The email must be a valid email address"}, r.GetErrors())
+
+	r = v.StructScenario(user{Password: "short"}, "update")
+	assert.NotEmpty(t, r.GetMessage("password.gte"))
+	assert.True(t, v.StructScenario(user{Password: "long enough"}, "update").Valid)
+
+	// scenario rules are skipped without scenario
+	assert.True(t, v.Struct(user{}).Valid)
+
+	// method of the request is also a scenario
+	r = v.StructMeta(user{}, validation.Meta{Method: "POST", Scenario: "reset"})
+	assert.Empty(t, r.GetMessage("email.required"))
+	assert.NotEmpty(t, r.GetMessage("password.required"))
+}
+
+type failFastRequest struct {
+	Name  string `json:"name" valid:"required"`
+	Email string `json:"email" valid:"required|email"`
+	calls *int
+}
+
+func (r failFastRequest) Validate() *validation.Response {
+	*r.calls++
+	return validation.SetError("name.custom", "invalid")
+}
+
+func (r failFastRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+func TestValidator_FailFast(t *testing.T) {
+	type item struct {
+		SKU string `json:"sku" valid:"required"`
+		Qty int    `json:"qty" valid:"gt:0"`
+	}
+	type order struct {
+		Code  string `json:"code" valid:"required"`
+		Items []item `json:"items" valid:"required"`
+		Note  string `json:"note" valid:"required"`
+	}
+
+	o := order{Code: "A1", Items: []item{{SKU: "x", Qty: 1}, {}, {}}}
+
+	r := validation.New().Struct(o)
+	assert.Len(t, r.GetMessages(), 5)
+
+	r = validation.New(validation.FailFast()).Struct(o)
+	assert.Equal(t, map[string]string{"items.1.sku.required": "The sku field is required"}, r.GetMessages())
+
+	var calls int
+	r = validation.New(validation.FailFast()).Request(failFastRequest{calls: &calls})
+	assert.Len(t, r.GetMessages(), 1)
+	assert.NotEmpty(t, r.GetMessage("name.required"))
+	assert.Equal(t, 0, calls)
+
+	r = validation.New(validation.FailFast()).Request(failFastRequest{Name: "a", Email: "a@b.co", calls: &calls})
+	assert.Equal(t, "invalid", r.GetMessage("name.custom"))
+	assert.Equal(t, 1, calls)
+}
+
+func TestValidator_Each(t *testing.T) {
+	type invite struct {
+		Emails []string  `json:"emails" valid:"required|each:email"`
+		Scores []int     `json:"scores" valid:"each:gte:1|each:lte:5"`
+		Tags   [2]string `json:"tags" valid:"each:in:go,rust"`
+	}
+
+	v := validation.New()
+	assert.True(t, v.Struct(invite{Emails: []string{"a@b.co"}, Scores: []int{1, 5}, Tags: [2]string{"go", "rust"}}).Valid)
+
+	r := v.Struct(invite{Emails: []string{"a@b.co", "x", "y"}, Scores: []int{0, 9}, Tags: [2]string{"go", "java"}})
+	assert.Equal(t, map[string]string{
+		"emails.1.email": "The emails must be a valid email address",
+		"emails.2.email": "The emails must be a valid email address",
+		"scores.0.gte":   "The scores should be greater than 1",
+		"tags.1.in":      "The selected tags is invalid",
+	}, r.GetMessages())
+
+	r = v.Struct(invite{})
+	assert.Equal(t, map[string]string{"emails": "The emails field is required"}, r.GetErrors())
+
+	r = validation.New(validation.FailFast()).Struct(invite{Emails: []string{"x", "y"}})
+	assert.Len(t, r.GetMessages(), 1)
+}
+
+func TestValidator_DateRules(t *testing.T) {
+	type member struct {
+		Birthday  string    `json:"birthday" valid:"required|before_now|age_gte:18"`
+		ExpiredAt time.Time `json:"expired_at" valid:"after_now"`
+		StartAt   string    `json:"start_at" valid:"after_now"`
+	}
+
+	jakarta := time.FixedZone("WIB", 7*3600)
+	now := time.Now().In(jakarta)
+	adult := now.AddDate(-18, 0, 0).Format("2006-01-02")
+	teen := now.AddDate(-18, 0, 1).Format("2006-01-02")
+
+	v := validation.New()
+	meta := validation.Meta{Timezone: jakarta}
+
+	assert.True(t, v.StructMeta(member{
+		Birthday:  adult,
+		ExpiredAt: time.Now().Add(time.Hour),
+		StartAt:   now.AddDate(0, 0, 1).Format("2006-01-02"),
+	}, meta).Valid)
+
+	r := v.StructMeta(member{
+		Birthday:  teen,
+		ExpiredAt: time.Now().Add(-time.Hour),
+		StartAt:   now.Format("2006-01-02"),
+	}, meta)
+	assert.Equal(t, map[string]string{
+		"birthday.age_gte":     "The birthday must be at least 18 years old",
+		"expired_at.after_now": "The expired at must be a date after now",
+		"start_at.after_now":   "The start at must be a date after now",
+	}, r.GetMessages())
+
+	// time of the day is compared in the timezone
+	r = v.StructMeta(member{Birthday: adult, StartAt: now.Add(time.Minute).Format("2006-01-02 15:04:05")}, meta)
+	assert.True(t, r.Valid)
+	r = v.StructMeta(member{Birthday: adult, StartAt: now.Add(-time.Minute).Format("2006-01-02 15:04:05")}, meta)
+	assert.NotEmpty(t, r.GetMessage("start_at.after_now"))
+
+	r = v.Struct(member{Birthday: "tomorrow"})
+	assert.Equal(t, "The birthday is not a valid date", r.GetMessage("birthday.before_now"))
+	r = v.Struct(member{Birthday: now.AddDate(0, 0, 2).Format(time.RFC3339)})
+	assert.Equal(t, "The birthday must be a date before now", r.GetMessage("birthday.before_now"))
+}
+
+func TestAge(t *testing.T) {
+	now := time.Date(2024, 3, 10, 8, 0, 0, 0, time.UTC)
+	assert.Equal(t, 18, validation.Age(time.Date(2006, 3, 10, 0, 0, 0, 0, time.UTC), now))
+	assert.Equal(t, 17, validation.Age(time.Date(2006, 3, 11, 0, 0, 0, 0, time.UTC), now))
+	assert.Equal(t, 18, validation.Age(time.Date(2006, 2, 28, 0, 0, 0, 0, time.UTC), now))
+}
+
+type period struct {
+	Start time.Time `json:"start" valid:"required"`
+	End   time.Time `json:"end"`
+}
+
+func TestValidator_RegisterStructValidation(t *testing.T) {
+	type booking struct {
+		Room   string `json:"room" valid:"required"`
+		Period period `json:"period" valid:"required"`
+		Guests int    `json:"guests"`
+	}
+
+	v := validation.New()
+	v.RegisterStructValidation(func(o interface{}) *validation.Response {
+		p := o.(period)
+		if !p.End.After(p.Start) {
+			return validation.SetError("end.after", "The end must be after the start")
+		}
+		return nil
+	}, &period{})
+	v.RegisterStructValidation(func(o interface{}) *validation.Response {
+		if b := o.(booking); b.Guests > 2 && b.Room == "single" {
+			return validation.SetError("guests.capacity", "The room is too small")
+		}
+		return nil
+	}, booking{})
+
+	now := time.Now()
+	assert.True(t, v.Struct(booking{Room: "single", Period: period{Start: now, End: now.Add(time.Hour)}}).Valid)
+
+	r := v.Struct(&booking{Room: "single", Guests: 3, Period: period{Start: now, End: now}})
+	assert.Equal(t, map[string]string{
+		"period.end.after": "The end must be after the start",
+		"guests.capacity":  "The room is too small",
+	}, r.GetMessages())
+
+	// not registered on other validators
+	assert.True(t, validation.New().Struct(period{Start: now}).Valid)
+
+	r = validation.New(validation.FailFast()).Struct(booking{Period: period{Start: now}})
+	assert.Len(t, r.GetMessages(), 1)
+}
+
+func TestRegisterStructValidation(t *testing.T) {
+	type coupon struct {
+		Code     string `json:"code"`
+		Discount int    `json:"discount"`
+	}
+
+	validation.RegisterStructValidation(func(o interface{}) *validation.Response {
+		if c := o.(coupon); c.Code == "" && c.Discount > 0 {
+			return validation.SetError("code.required_with", "The code is required for the discount")
+		}
+		return nil
+	}, coupon{})
+
+	assert.True(t, validation.New().Struct(coupon{}).Valid)
+	assert.Equal(t, "The code is required for the discount", validation.New().Struct(coupon{Discount: 5}).GetMessage("code.required_with"))
+}
+
+func TestValidator_JSONKeys(t *testing.T) {
+	type address struct {
+		PostalCode string `json:"postalCode,omitempty" valid:"required"`
+	}
+	type customer struct {
+		CreatedAt string  `json:"created_at,omitempty" valid:"required"`
+		FullName  string  `json:",omitempty" valid:"required"`
+		Internal  string  `json:"-" valid:"required"`
+		Address   address `json:"address" valid:"required"`
+	}
+
+	r := validation.New(validation.JSONKeys()).Struct(customer{})
+	assert.Equal(t, map[string]string{
+		"created_at":         "The created at field is required",
+		"FullName":           "The FullName field is required",
+		"internal":           "The internal field is required",
+		"address.postalCode": "The postalCode field is required",
+	}, r.GetErrors())
+}
+
+func TestValidator_StructPartial(t *testing.T) {
+	type address struct {
+		City    string `json:"city" valid:"required"`
+		ZipCode string `json:"zip_code" valid:"required|numeric"`
+	}
+	type item struct {
+		SKU string `json:"sku" valid:"required"`
+		Qty int    `json:"qty" valid:"required"`
+	}
+	type user struct {
+		Name    string  `json:"name" valid:"required"`
+		Email   string  `json:"email" valid:"required|email"`
+		Address address `json:"address" valid:"required"`
+		Items   []item  `json:"items" valid:"required"`
+	}
+
+	v := validation.New()
+	u := user{Email: "x", Address: address{ZipCode: "abc"}, Items: []item{{}}}
+
+	r := v.StructPartial(u, "email")
+	assert.Equal(t, []string{"email"}, keys(r.GetErrors()))
+
+	r = v.StructPartial(u, "address.zip_code", "Items.qty")
+	assert.Equal(t, []string{"address.zip_code", "items.0.qty"}, keys(r.GetErrors()))
+
+	r = v.StructPartial(u, "address")
+	assert.Equal(t, []string{"address.city", "address.zip_code"}, keys(r.GetErrors()))
+
+	r = v.StructExcept(u, "email", "address.city", "items")
+	assert.Equal(t, []string{"address.zip_code", "name"}, keys(r.GetErrors()))
+
+	assert.True(t, v.StructPartial(u, "unknown").Valid)
+	assert.True(t, v.StructPartial(&user{Name: "kora"}, "name").Valid)
+}
+
+func keys(m map[string]string) []string {
+	var k []string
+	for n := range m {
+		k = append(k, n)
+	}
+	sort.Strings(k)
+	return k
+}
+
+func TestResponse_Format(t *testing.T) {
+	type item struct {
+		Tags []string `json:"tags" valid:"each:alpha"`
+	}
+	type user struct {
+		Email    string `json:"email" valid:"required|email"`
+		Password string `json:"password" valid:"gte:8"`
+		Role     string `json:"role" valid:"in:admin,staff"`
+		Item     item   `json:"item" valid:"required"`
+	}
+
+	u := user{Email: "x", Password: "short", Role: "guest", Item: item{Tags: []string{"ok", "n0"}}}
+	r := validation.New().Struct(u)
+
+	assert.Equal(t, r.GetErrors(), r.Format(validation.FormatMap))
+	assert.Equal(t, r.GetMessages(), r.Format(validation.FormatRules))
+	assert.Equal(t, []validation.FieldError{
+		{Field: "email", Rule: "email", Message: r.GetMessage("email.email")},
+		{Field: "item.tags.1", Rule: "alpha", Message: r.GetMessage("item.tags.1.alpha")},
+		{Field: "password", Rule: "gte", Params: []string{"8"}, Message: r.GetMessage("password.gte")},
+		{Field: "role", Rule: "in", Params: []string{"admin", "staff"}, Message: r.GetMessage("role.in")},
+	}, r.Format(validation.FormatList))
+
+	b, _ := json.Marshal(r.List()[2])
+	assert.Equal(t, `{"field":"password","rule":"gte","params":["8"],"message":"`+r.GetMessage("password.gte")+`"}`, string(b))
+
+	// the format of the validator
+	assert.Equal(t, r.GetErrors(), r.Output())
+	r = validation.New(validation.ErrorFormat(validation.FormatList)).Struct(u)
+	assert.Len(t, r.Output(), 4)
+}
+
+func TestValidator_Cache(t *testing.T) {
+	type user struct {
+		FullName string `json:"fullName,omitempty" valid:"required"`
+		Email    string `json:"email" valid:"required|email"`
+	}
+
+	// the fields are cached by the options
+	assert.NotEmpty(t, validation.New().Struct(user{}).GetMessage("fullName,omitempty.required"))
+	assert.NotEmpty(t, validation.New(validation.JSONKeys()).Struct(user{}).GetMessage("fullName.required"))
+
+	// the rules are resolved by the validator functions
+	v := validation.New()
+	assert.False(t, v.Struct(user{FullName: "kora", Email: "x"}).Valid)
+	v.RegisterProvider("email", "The %s is not registered", func(ctx context.Context, value interface{}, params []string) (bool, error) {
+		return true, nil
+	})
+	assert.True(t, v.Struct(user{FullName: "kora", Email: "x"}).Valid)
+}
+
+type benchAddress struct {
+	City    string `json:"city" valid:"required"`
+	ZipCode string `json:"zip_code" valid:"required|numeric|len:5"`
+}
+
+type benchRequest struct {
+	Name     string       `json:"name" valid:"required|gte:3"`
+	Email    string       `json:"email" valid:"required|email"`
+	Age      int          `json:"age" valid:"gte:17"`
+	Role     string       `json:"role" valid:"in:admin,staff,guest"`
+	Tags     []string     `json:"tags" valid:"each:alpha"`
+	Address  benchAddress `json:"address" valid:"required"`
+	Internal string       `json:"internal"`
+}
+
+func BenchmarkValidator_Struct(b *testing.B) {
+	v := validation.New()
+	r := benchRequest{Name: "kora", Email: "dev@kora.id", Age: 20, Role: "staff", Tags: []string{"a", "b"}, Address: benchAddress{"Jakarta", "12345"}}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		v.Struct(r)
+	}
+}
+
+func BenchmarkValidator_StructInvalid(b *testing.B) {
+	v := validation.New()
+	r := benchRequest{Email: "x", Age: 10, Role: "root", Tags: []string{"1"}}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		v.Struct(r)
+	}
+}
+
+func BenchmarkValidator_Field(b *testing.B) {
+	v := validation.New()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		v.Field("dev@kora.id", "required|email")
+	}
+}
+
+func TestValidator_Limits(t *testing.T) {
+	type item struct {
+		SKU  string   `json:"sku" valid:"required"`
+		Tags []string `json:"tags"`
+	}
+	type batch struct {
+		Items []item                    `json:"items" valid:"required|max_items:4"`
+		Meta  map[string]interface{}    `json:"meta" valid:"max_fields:3"`
+		Codes []string                  `json:"codes" valid:"max_items:2|each:alpha"`
+		Extra map[string]map[string]int `json:"extra" valid:"max_items:2"`
+	}
+
+	v := validation.New()
+
+	// within the limits, the elements are validated
+	r := v.Struct(batch{Items: []item{{}, {SKU: "a"}}, Meta: map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": 2}}})
+	assert.Equal(t, []string{"items.0.sku"}, keys(r.GetErrors()))
+
+	// nested elements are counted, the elements are not validated
+	r = v.Struct(batch{Items: []item{{}, {SKU: "a", Tags: []string{"x", "y", "z"}}}})
+	assert.Equal(t, map[string]string{"items": "The items may not have more than 4 items"}, r.GetErrors())
+
+	r = v.Struct(batch{
+		Items: []item{{SKU: "a"}},
+		Meta:  map[string]interface{}{"a": 1, "b": []interface{}{map[string]interface{}{"c": 1, "d": 2, "e": 3}}},
+		Codes: []string{"a", "1", "c"},
+	})
+	assert.Equal(t, []string{"codes", "meta"}, keys(r.GetErrors()))
+	assert.NotEmpty(t, r.GetMessage("codes.max_items"))
+	assert.Equal(t, "The meta may not have more than 3 fields", r.GetMessage("meta.max_fields"))
+
+	assert.True(t, v.Field([]int{1, 2}, "max_items:2").Valid)
+	assert.False(t, v.Field([]int{1, 2, 3}, "max_items:2").Valid)
+}
+
+func TestValidator_Precompile(t *testing.T) {
+	type phone struct {
+		Number string `valid:"match:^\\+?[0-9]+$"`
+	}
+	type user struct {
+		Code   string            `valid:"required|match:^[A-Z]{3}$"`
+		Phones []phone           `valid:"required"`
+		Tags   map[string]string `valid:"each:match:^[a-z]+$"`
+	}
+	type invalid struct {
+		Phones []phone
+		Code   string `valid:"match:^([A-Z]+$"`
+	}
+	type unknown struct {
+		Code string `valid:"required|unknown_rule"`
+	}
+
+	v := validation.New()
+	assert.NoError(t, v.Precompile(user{}, &user{}, []user{}))
+
+	err := v.Precompile(&invalid{})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid.Code")
+	}
+	assert.Error(t, v.Precompile(unknown{}))
+
+	// the compiled pattern is reused
+	assert.True(t, v.Field("ABC", "match:^[A-Z]{3}$").Valid)
+	assert.False(t, v.Field("abc", "match:^[A-Z]{3}$").Valid)
+	assert.False(t, v.Field("abc", "match:^([A-Z]+$").Valid)
+}
+
+func BenchmarkValidator_Match(b *testing.B) {
+	v := validation.New()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		v.Field("INV-2018-0001", "match:^INV-[0-9]{4}-[0-9]{4}$")
+	}
+}
+
+type wildcardItem struct {
+	SKU string `json:"sku" valid:"required"`
+	Qty int    `json:"qty" valid:"required|gte:1"`
+}
+
+type wildcardOrder struct {
+	Items []wildcardItem `json:"items" valid:"required"`
+}
+
+type wildcardCart struct {
+	Orders   []wildcardOrder         `json:"orders" valid:"required"`
+	Sections map[string]wildcardItem `json:"sections" valid:"required"`
+	Notes    []string                `json:"notes" valid:"each:alpha_space"`
+}
+
+func (wildcardCart) Validate() *validation.Response {
+	return nil
+}
+
+func (wildcardCart) Messages() map[string]string {
+	return map[string]string{
+		"orders.*.items.*.qty.required": "qty is required",
+		"orders.*.items.*.qty.gte":      "qty is too small",
+		"orders.0.items.*.sku.required": "first order sku is required",
+		"orders.*.items.*.sku.required": "sku is required",
+		"sections.*.qty.gte":            "section qty is too small",
+		"notes.*.alpha_space":           "note is invalid",
+	}
+}
+
+func TestValidator_NestedWildcardMessages(t *testing.T) {
+	r := validation.New().Request(wildcardCart{
+		Orders: []wildcardOrder{
+			{Items: []wildcardItem{{SKU: "A", Qty: 1}, {Qty: 0}}},
+			{Items: []wildcardItem{{SKU: "B", Qty: -1}, {Qty: 2}}},
+		},
+		Sections: map[string]wildcardItem{"promo_2": {SKU: "C", Qty: -1}},
+		Notes:    []string{"ok", "100%"},
+	})
+
+	assert.False(t, r.Valid)
+	assert.Equal(t, "qty is required", r.GetMessage("orders.0.items.1.qty.required"))
+	assert.Equal(t, "qty is too small", r.GetMessage("orders.1.items.0.qty.gte"))
+	assert.Equal(t, "first order sku is required", r.GetMessage("orders.0.items.1.sku.required"))
+	assert.Equal(t, "sku is required", r.GetMessage("orders.1.items.1.sku.required"))
+	assert.Equal(t, "section qty is too small", r.GetMessage("sections.promo_2.qty.gte"))
+	assert.Equal(t, "note is invalid", r.GetMessage("notes.1.alpha_space"))
+}
+
+type placeholderTag struct {
+	Name string `json:"name" valid:"in:go,rust"`
+}
+
+type placeholderSignup struct {
+	FullName string           `json:"full_name" valid:"required"`
+	Password string           `json:"password" valid:"gte:8"`
+	Age      int              `json:"age" valid:"range:17,60"`
+	Tags     []placeholderTag `json:"tags" valid:"required"`
+}
+
+func (placeholderSignup) Validate() *validation.Response {
+	return nil
+}
+
+func (placeholderSignup) Messages() map[string]string {
+	return map[string]string{
+		"full_name.required": "The :attribute is mandatory",
+		"password.gte":       "The :attribute must be at least :param characters",
+		"age.range":          ":attribute between :min and :max",
+		"tags.*.name.in":     "The :attribute must be one of :values",
+	}
+}
+
+func TestValidator_MessagePlaceholders(t *testing.T) {
+	r := validation.New().Request(placeholderSignup{Password: "secret", Age: 12, Tags: []placeholderTag{{Name: "java"}}})
+	assert.Equal(t, map[string]string{
+		"full_name.required": "The full name is mandatory",
+		"password.gte":       "The password must be at least 8 characters",
+		"age.range":          "age between 17 and 60",
+		"tags.0.name.in":     "The name must be one of go, rust",
+	}, r.GetMessages())
+
+	assert.Equal(t, "password", r.Args("password.gte")["attribute"])
+	assert.Equal(t, 60, r.Args("age.range")["max"])
+}
+
+func TestRegisterAlias(t *testing.T) {
+	validation.RegisterAlias("test_password", "required|gte:8|lte:16")
+	validation.RegisterAlias("test_account", "test_password|alpha_num")
+
+	type signup struct {
+		Password string `json:"password" valid:"test_password"`
+		Account  string `json:"account" valid:"test_account;on=create"`
+		Username string `json:"username" valid:"test_account|in:administrator,superuser"`
+	}
+
+	v := validation.New()
+	assert.True(t, v.Struct(signup{Password: "secret123", Account: "abcdefgh", Username: "administrator"}).Valid)
+
+	r := v.StructScenario(signup{Password: "secret", Account: "abc-defgh", Username: "superusers"}, "create")
+	assert.Equal(t, []string{"account.alpha_num", "password.gte", "username.in"}, keys(r.GetMessages()))
+
+	r = v.StructScenario(signup{Password: "secret123", Account: "abc", Username: "administrator"}, "update")
+	assert.True(t, r.Valid)
+
+	// the parsed tags are updated
+	validation.RegisterAlias("test_password", "required|gte:4")
+	assert.True(t, v.Struct(signup{Password: "secret", Account: "abcdefgh", Username: "administrator"}).Valid)
+
+	assert.Error(t, v.Precompile(struct {
+		Name string `valid:"test_password:8"`
+	}{}))
+	validation.RegisterAlias("test_loop", "test_loop")
+	assert.Error(t, v.Precompile(struct {
+		Name string `valid:"test_loop"`
+	}{}))
+	validation.RegisterAlias("test_unknown", "required|unknown_rule")
+	assert.Error(t, v.Precompile(struct {
+		Name string `valid:"test_unknown"`
+	}{}))
+
+	assert.Panics(t, func() { validation.RegisterAlias("bad|name", "required") })
+	assert.Panics(t, func() { validation.RegisterAlias("test_empty", "required||gte:1") })
+}
+
+func TestValidator_CardBrand(t *testing.T) {
+	t.Parallel()
+
+	type card struct {
+		Number string `json:"number" valid:"required|cc:visa,mastercard"`
+	}
+	type payment struct {
+		Card   card   `json:"card" valid:"required"`
+		Backup string `json:"backup" valid:"cc"`
+		IBAN   string `json:"iban" valid:"iban"`
+	}
+
+	v := validation.New()
+	r := v.Struct(payment{Card: card{Number: "4111111111111111"}, Backup: "378282246310005"})
+	assert.True(t, r.Valid)
+	assert.Equal(t, validation.BrandVisa, r.Brand("card.number"))
+	assert.Equal(t, validation.BrandAmex, r.Brand("backup"))
+
+	r = v.Struct(payment{Card: card{Number: "378282246310005"}, IBAN: "DE89370400440532013001"})
+	assert.Equal(t, []string{"card.number.cc", "iban.iban"}, keys(r.GetMessages()))
+	assert.Equal(t, "The number must be a card of the following: visa, mastercard", r.GetMessage("card.number.cc"))
+	assert.Equal(t, "", r.Brand("card.number"))
+
+	for number, brand := range map[string]string{
+		"4012888888881881":    validation.BrandVisa,
+		"2223003122003222":    validation.BrandMastercard,
+		"371449635398431":     validation.BrandAmex,
+		"6011111111111117":    validation.BrandDiscover,
+		"3530111333300000":    validation.BrandJCB,
+		"36227206271667":      validation.BrandDiners,
+		"6200000000000005":    validation.BrandUnionPay,
+		"4111111111111112":    "",
+		"1234567812345670":    "",
+		"4111 1111 1111 1111": validation.BrandVisa,
+	} {
+		assert.Equal(t, brand, validation.CardBrand(number), number)
+	}
+}
+
+func TestResponse_GetCodes(t *testing.T) {
+	type order struct {
+		Email string   `json:"email" valid:"required|email"`
+		Qty   int      `json:"qty" valid:"divisible_by:6"`
+		Tags  []string `json:"tags" valid:"each:alpha"`
+		Code  string   `json:"code" valid:"test_voucher"`
+	}
+
+	validation.RegisterCode("test_voucher", "VAL_VOUCHER")
+	v := validation.New(validation.Provider("test_voucher", "The %s is invalid", func(_ context.Context, _ interface{}, _ []string) (bool, error) {
+		return false, nil
+	}))
+
+	r := v.Struct(order{Qty: 4, Tags: []string{"ok", "n0"}, Code: "x"})
+	assert.Equal(t, map[string]string{
+		"email":  "VAL_REQUIRED",
+		"qty":    "VAL_MULTIPLE_OF",
+		"tags.1": "VAL_ALPHA",
+		"code":   "VAL_VOUCHER",
+	}, r.GetCodes())
+	assert.Equal(t, "VAL_CUSTOM_RULE", validation.Code("custom_rule"))
+	assert.Empty(t, validation.SetError("name", "name is required").GetCodes())
+}
@@ -0,0 +1,124 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"strconv"
+	"time"
+)
+
+// dateFn validates the date against now, date without time
+// is compared by the day of now in the timezone.
+type dateFn func(t time.Time, dateOnly bool, param string, now time.Time) (bool, string)
+
+// dateRules compare the value against current time in timezone of the request.
+var dateRules = map[string]dateFn{
+	// after_now
+	"after_now": func(t time.Time, dateOnly bool, _ string, now time.Time) (bool, string) {
+		if dateOnly {
+			now = day(now)
+		}
+		return t.After(now), "The %s must be a date after now"
+	},
+	// before_now
+	"before_now": func(t time.Time, dateOnly bool, _ string, now time.Time) (bool, string) {
+		if dateOnly {
+			now = day(now)
+		}
+		return t.Before(now), "The %s must be a date before now"
+	},
+	// age_gte:18
+	"age_gte": func(t time.Time, _ bool, param string, now time.Time) (bool, string) {
+		n, err := strconv.Atoi(param)
+		return err == nil && Age(t, now) >= n, "The %s must be at least " + param + " years old"
+	},
+}
+
+// date layouts of the string values, layout without zone
+// is parsed in timezone of the request.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// dateRule returns validator function of the rule using timezone of the meta.
+func (m *Meta) dateRule(fn dateFn) validatorFn {
+	loc := time.Local
+	if m != nil && m.Timezone != nil {
+		loc = m.Timezone
+	}
+
+	return func(value interface{}, param string) (bool, string) {
+		if !IsNotEmpty(value) {
+			return true, ""
+		}
+
+		t, dateOnly, ok := parseDate(value, loc)
+		if !ok {
+			return false, "The %s is not a valid date"
+		}
+
+		if ok, e := fn(t, dateOnly, param, time.Now().In(loc)); !ok {
+			return false, e
+		}
+
+		return true, ""
+	}
+}
+
+// Age returns the age in years of the birth date at now,
+// the birthday is counted on the day in timezone of now.
+func Age(birth time.Time, now time.Time) int {
+	birth = birth.In(now.Location())
+
+	age := now.Year() - birth.Year()
+	if now.Month() < birth.Month() || (now.Month() == birth.Month() && now.Day() < birth.Day()) {
+		age--
+	}
+
+	return age
+}
+
+// parseDate returns time of the value, date without time is returned
+// as midnight in the location with dateOnly true.
+func parseDate(value interface{}, loc *time.Location) (t time.Time, dateOnly bool, ok bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, false, true
+	case *time.Time:
+		if v != nil {
+			return *v, false, true
+		}
+	case string:
+		if t, err := time.ParseInLocation("2006-01-02", v, loc); err == nil {
+			return t, true, true
+		}
+		for _, layout := range dateLayouts {
+			if t, err := time.ParseInLocation(layout, v, loc); err == nil {
+				return t, false, true
+			}
+		}
+	}
+
+	return time.Time{}, false, false
+}
+
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func validAfterNow(value interface{}, param string) (bool, string) {
+	return (*Meta)(nil).dateRule(dateRules["after_now"])(value, param)
+}
+
+func validBeforeNow(value interface{}, param string) (bool, string) {
+	return (*Meta)(nil).dateRule(dateRules["before_now"])(value, param)
+}
+
+func validAgeGte(value interface{}, param string) (bool, string) {
+	return (*Meta)(nil).dateRule(dateRules["age_gte"])(value, param)
+}
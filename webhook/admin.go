@@ -0,0 +1,47 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/enigma-id/go/rest"
+)
+
+// Route implements rest.RouteHandlers, it registers the delivery logs endpoint,
+// so it can be mounted into the admin group:
+//
+//	GET /webhooks/:endpoint/deliveries?status=dead&event_id=...&limit=50
+func (d *Dispatcher) Route(g *rest.Group) {
+	g.GET("/webhooks/:endpoint/deliveries", d.deliveries)
+}
+
+func (d *Dispatcher) deliveries(c *rest.Context) error {
+	if d.Log == nil {
+		return rest.NewHTTPError(http.StatusNotFound, "webhook: delivery log is not configured")
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+
+	ds, err := d.Log.Deliveries(c.Request().Context(), Query{
+		EndpointID: c.Param("endpoint"),
+		EventID:    c.QueryParam("event_id"),
+		Status:     c.QueryParam("status"),
+		Limit:      limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	if ds == nil {
+		ds = []*Delivery{}
+	}
+
+	return c.JSON(http.StatusOK, ds)
+}
@@ -0,0 +1,34 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/enigma-id/go/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoute(t *testing.T) {
+	log := NewMemoryLog(10)
+	log.Save(context.Background(), &Delivery{ID: "d1", EndpointID: "ep1", Status: StatusFailed})
+	log.Save(context.Background(), &Delivery{ID: "d2", EndpointID: "ep1", Status: StatusSuccess})
+
+	e := rest.New()
+	New(NewMemoryEndpoints(), nil, WithLog(log)).Route(e.Group("/_admin"))
+
+	request := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	rec := request("/_admin/webhooks/ep1/deliveries?status=failed")
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		assert.Contains(t, rec.Body.String(), `"id":"d1"`)
+		assert.NotContains(t, rec.Body.String(), `"id":"d2"`)
+	}
+
+	assert.Equal(t, "[]", request("/_admin/webhooks/ep2/deliveries").Body.String())
+}
@@ -0,0 +1,105 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var (
+	// ErrInsecureURL returned when the endpoint url isn't absolute https url.
+	ErrInsecureURL = errors.New("webhook: endpoint url must be https")
+	// ErrForbiddenAddress returned by the dialer of the dispatcher when the
+	// endpoint resolves into loopback, private or link-local address.
+	ErrForbiddenAddress = errors.New("webhook: endpoint address is not allowed")
+)
+
+// forbiddenNets are the networks that are not reachable by the customer
+// endpoints, ex. the metadata service at 169.254.169.254.
+var forbiddenNets = parseCIDRs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"::/128",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+)
+
+// Validate checks url of the endpoint, it should be called
+// where the endpoint is registered.
+func (ep *Endpoint) Validate() error {
+	u, err := url.Parse(ep.URL)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return ErrInsecureURL
+	}
+
+	return nil
+}
+
+// allowed returns false when ip is in the forbidden networks.
+func allowed(ip net.IP) bool {
+	if ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, n := range forbiddenNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// guardedTransport returns transport that refuses to connect into the
+// forbidden networks, the address is checked after it's resolved so the
+// endpoint can't point its hostname into them. Proxy is not used since
+// the dialed address would be the proxy.
+func guardedTransport() http.RoundTripper {
+	d := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !allowed(ip) {
+				return ErrForbiddenAddress
+			}
+			return nil
+		},
+	}
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = nil
+	t.DialContext = d.DialContext
+
+	return t
+}
+
+func parseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, len(cidrs))
+	for i, c := range cidrs {
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			panic(err)
+		}
+		nets[i] = n
+	}
+
+	return nets
+}
@@ -0,0 +1,63 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// limiter is token bucket of the endpoint, the bucket size is the rate.
+type limiter struct {
+	mu     sync.Mutex
+	rate   int
+	tokens float64
+	last   time.Time
+}
+
+func (l *limiter) setRate(rate int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.rate != rate {
+		l.rate, l.tokens, l.last = rate, float64(rate), time.Now()
+	}
+}
+
+// wait blocks until a token is available, unlimited when the rate is zero.
+func (l *limiter) wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		if l.rate <= 0 {
+			l.mu.Unlock()
+			return nil
+		}
+
+		now := time.Now()
+		l.tokens += now.Sub(l.last).Seconds() * float64(l.rate)
+		if l.tokens > float64(l.rate) {
+			l.tokens = float64(l.rate)
+		}
+		l.last = now
+
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+
+		d := time.Duration((1 - l.tokens) / float64(l.rate) * float64(time.Second))
+		l.mu.Unlock()
+
+		t := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
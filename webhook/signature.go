@@ -0,0 +1,63 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers of the delivery request.
+const (
+	HeaderID        = "Webhook-Id"
+	HeaderTimestamp = "Webhook-Timestamp"
+	HeaderSignature = "Webhook-Signature"
+	HeaderEvent     = "Webhook-Event"
+)
+
+// ErrInvalidSignature returned by Verify when the signature doesn't match or expired.
+var ErrInvalidSignature = errors.New("webhook: invalid signature")
+
+// Sign returns signature of the delivery, formatted as "v1=<hex of hmac sha256>"
+// of the "<id>.<timestamp>.<body>".
+func Sign(secret, id string, timestamp int64, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(id + "." + strconv.FormatInt(timestamp, 10) + "."))
+	h.Write(body)
+
+	return "v1=" + hex.EncodeToString(h.Sum(nil))
+}
+
+// Verify validates the signature headers of the delivery, it can be used by
+// the receiver. The timestamp should be within the tolerance to prevent replay,
+// zero tolerance disables the check. Signature header may contain multiple
+// signatures separated by space.
+func Verify(secret string, h http.Header, body []byte, tolerance time.Duration) error {
+	ts, err := strconv.ParseInt(h.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	if tolerance > 0 {
+		if d := time.Since(time.Unix(ts, 0)); d > tolerance || d < -tolerance {
+			return ErrInvalidSignature
+		}
+	}
+
+	expected := Sign(secret, h.Get(HeaderID), ts, body)
+	for _, s := range strings.Fields(h.Get(HeaderSignature)) {
+		if hmac.Equal([]byte(s), []byte(expected)) {
+			return nil
+		}
+	}
+
+	return ErrInvalidSignature
+}
@@ -0,0 +1,178 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+type (
+	// Endpoint is customer registered url receiving the events.
+	Endpoint struct {
+		ID     string   `json:"id"`
+		URL    string   `json:"url"`
+		Secret string   `json:"-"`
+		Events []string `json:"events"`
+
+		// RateLimit is maximum deliveries per second, zero is unlimited.
+		RateLimit int  `json:"rate_limit"`
+		Disabled  bool `json:"disabled"`
+	}
+
+	// EndpointStore looks up the registered endpoints, ex. backed by the
+	// database of the customers. The endpoint should be checked by
+	// Endpoint.Validate when it's registered.
+	EndpointStore interface {
+		// Endpoint returns nil when the endpoint is not found.
+		Endpoint(ctx context.Context, id string) (*Endpoint, error)
+		// Endpoints returns enabled endpoints subscribing the event type.
+		Endpoints(ctx context.Context, event string) ([]*Endpoint, error)
+	}
+
+	// Query of the delivery logs.
+	Query struct {
+		EndpointID string
+		EventID    string
+		Status     string
+		Limit      int
+	}
+
+	// LogStore stores the delivery logs.
+	LogStore interface {
+		Save(ctx context.Context, d *Delivery) error
+		// Deliveries returns the latest deliveries first.
+		Deliveries(ctx context.Context, q Query) ([]*Delivery, error)
+	}
+
+	// MemoryEndpoints is in memory EndpointStore.
+	MemoryEndpoints struct {
+		mu        sync.RWMutex
+		endpoints map[string]*Endpoint
+	}
+
+	// MemoryLog is in memory LogStore that keeps the latest
+	// deliveries of each endpoint.
+	MemoryLog struct {
+		mu         sync.RWMutex
+		size       int
+		deliveries map[string][]*Delivery
+	}
+)
+
+// Subscribes returns true when the endpoint subscribes the event type,
+// "*" subscribes all events and "order.*" subscribes events prefixed by "order.".
+func (ep *Endpoint) Subscribes(event string) bool {
+	for _, e := range ep.Events {
+		if e == "*" || e == event || (strings.HasSuffix(e, ".*") && strings.HasPrefix(event, e[:len(e)-1])) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewMemoryEndpoints creates in memory endpoint store,
+// it panics when url of the endpoint is invalid.
+func NewMemoryEndpoints(eps ...*Endpoint) *MemoryEndpoints {
+	s := &MemoryEndpoints{endpoints: make(map[string]*Endpoint)}
+	for _, ep := range eps {
+		if err := s.Add(ep); err != nil {
+			panic(err)
+		}
+	}
+
+	return s
+}
+
+// Add registers or replaces the endpoint, see Endpoint.Validate.
+func (s *MemoryEndpoints) Add(ep *Endpoint) error {
+	if err := ep.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.endpoints[ep.ID] = ep
+
+	return nil
+}
+
+// Remove deletes the endpoint.
+func (s *MemoryEndpoints) Remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.endpoints, id)
+}
+
+// Endpoint implements EndpointStore interfaces.
+func (s *MemoryEndpoints) Endpoint(_ context.Context, id string) (*Endpoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.endpoints[id], nil
+}
+
+// Endpoints implements EndpointStore interfaces.
+func (s *MemoryEndpoints) Endpoints(_ context.Context, event string) (eps []*Endpoint, _ error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, ep := range s.endpoints {
+		if !ep.Disabled && ep.Subscribes(event) {
+			eps = append(eps, ep)
+		}
+	}
+
+	return eps, nil
+}
+
+// NewMemoryLog creates in memory log that keeps size of latest deliveries per endpoint.
+func NewMemoryLog(size int) *MemoryLog {
+	if size <= 0 {
+		size = 100
+	}
+
+	return &MemoryLog{size: size, deliveries: make(map[string][]*Delivery)}
+}
+
+// Save implements LogStore interfaces.
+func (l *MemoryLog) Save(_ context.Context, d *Delivery) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	c := *d
+	ds := append(l.deliveries[d.EndpointID], &c)
+	if len(ds) > l.size {
+		ds = ds[len(ds)-l.size:]
+	}
+	l.deliveries[d.EndpointID] = ds
+
+	return nil
+}
+
+// Deliveries implements LogStore interfaces, EndpointID of the query is required.
+func (l *MemoryLog) Deliveries(_ context.Context, q Query) (res []*Delivery, _ error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	ds := l.deliveries[q.EndpointID]
+	for i := len(ds) - 1; i >= 0; i-- {
+		if q.Limit > 0 && len(res) >= q.Limit {
+			break
+		}
+
+		d := ds[i]
+		if (q.Status == "" || d.Status == q.Status) && (q.EventID == "" || d.EventID == q.EventID) {
+			c := *d
+			res = append(res, &c)
+		}
+	}
+
+	return res, nil
+}
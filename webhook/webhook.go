@@ -0,0 +1,358 @@
+// Copyright 2018 Kora ID. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/enigma-id/go/client"
+	"github.com/enigma-id/go/queue"
+)
+
+// Queue topics of the deliveries.
+const (
+	Topic           = "webhook.deliver"
+	DeadLetterTopic = "webhook.dlq"
+)
+
+// Delivery states
+const (
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+	StatusDead    = "dead"
+)
+
+// ErrUnknownEndpoint returned when the endpoint of the delivery is not found.
+var ErrUnknownEndpoint = errors.New("webhook: unknown endpoint")
+
+type (
+	// Event is the payload sent into the endpoints.
+	Event struct {
+		ID         string          `json:"id"`
+		Type       string          `json:"type"`
+		OccurredAt time.Time       `json:"occurred_at"`
+		Data       json.RawMessage `json:"data"`
+	}
+
+	// Delivery is the log of each delivery attempt.
+	Delivery struct {
+		ID         string        `json:"id"`
+		EventID    string        `json:"event_id"`
+		EventType  string        `json:"event_type"`
+		EndpointID string        `json:"endpoint_id"`
+		URL        string        `json:"url"`
+		Attempt    int           `json:"attempt"`
+		Status     string        `json:"status"`
+		StatusCode int           `json:"status_code,omitempty"`
+		Error      string        `json:"error,omitempty"`
+		Duration   time.Duration `json:"duration"`
+		CreatedAt  time.Time     `json:"created_at"`
+	}
+
+	// RetryPolicy of the delivery, failed delivery is retried with exponential
+	// backoff then sent into the dead letter queue when still failing.
+	RetryPolicy struct {
+		MaxAttempts int
+		MinBackoff  time.Duration
+		MaxBackoff  time.Duration
+	}
+
+	// Option configures the dispatcher.
+	Option func(*Dispatcher)
+
+	// Dispatcher sends the events into the endpoints subscribing the event type,
+	// each delivery is enqueued and sent by the queue worker.
+	Dispatcher struct {
+		Endpoints EndpointStore
+		Queue     queue.Queue
+		Client    *client.Client
+		Log       LogStore
+		Retry     RetryPolicy
+
+		// DeadLetter queue where the job is enqueued into DeadLetterTopic
+		// after the last attempt, so it can be redelivered later.
+		DeadLetter queue.Queue
+
+		private  bool
+		mu       sync.Mutex
+		limiters map[string]*limiter
+	}
+
+	// job is the queued delivery.
+	job struct {
+		EndpointID string `json:"endpoint_id"`
+		Event      *Event `json:"event"`
+		Attempt    int    `json:"attempt,omitempty"`
+	}
+)
+
+// DefaultRetry retries 5 times with backoff between 1s and 1m.
+var DefaultRetry = RetryPolicy{
+	MaxAttempts: 5,
+	MinBackoff:  time.Second,
+	MaxBackoff:  time.Minute,
+}
+
+// WithClient sets http client used to send the events.
+func WithClient(c *client.Client) Option {
+	return func(d *Dispatcher) {
+		d.Client = c
+	}
+}
+
+// WithLog sets store of the delivery logs.
+func WithLog(l LogStore) Option {
+	return func(d *Dispatcher) {
+		d.Log = l
+	}
+}
+
+// WithRetry sets the retry policy.
+func WithRetry(p RetryPolicy) Option {
+	return func(d *Dispatcher) {
+		d.Retry = p
+	}
+}
+
+// WithPrivateNetwork allows the default client to send into loopback,
+// private and link-local addresses, ex. on the tests or internal endpoints.
+func WithPrivateNetwork() Option {
+	return func(d *Dispatcher) {
+		d.private = true
+	}
+}
+
+// WithDeadLetter sets dead letter queue of the failed deliveries.
+func WithDeadLetter(q queue.Queue) Option {
+	return func(d *Dispatcher) {
+		d.DeadLetter = q
+	}
+}
+
+// New creates dispatcher of the endpoints, the handler of Topic
+// is registered when the queue is queue.Memory. The default client doesn't
+// connect into loopback, private and link-local addresses, unless
+// WithPrivateNetwork is set. The client of WithClient is used as is.
+//
+//	q := queue.NewMemory(4)
+//	d := webhook.New(endpoints, q, webhook.WithLog(webhook.NewMemoryLog(100)))
+//	err := d.Dispatch(ctx, "order.paid", order)
+func New(endpoints EndpointStore, q queue.Queue, opts ...Option) *Dispatcher {
+	d := &Dispatcher{
+		Endpoints: endpoints,
+		Queue:     q,
+		Retry:     DefaultRetry,
+		limiters:  make(map[string]*limiter),
+	}
+
+	for _, o := range opts {
+		o(d)
+	}
+
+	if d.Client == nil {
+		co := []client.Option{client.WithTimeout(10 * time.Second)}
+		if !d.private {
+			co = append(co, client.WithTransport(guardedTransport()))
+		}
+		d.Client = client.New(co...)
+	}
+
+	if m, ok := q.(*queue.Memory); ok {
+		m.Handle(Topic, d.Handle)
+	}
+
+	return d
+}
+
+// Dispatch enqueues delivery of the event into every endpoint subscribing the event type.
+func (d *Dispatcher) Dispatch(ctx context.Context, typ string, data interface{}) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	e := &Event{ID: newID(), Type: typ, OccurredAt: time.Now().UTC(), Data: b}
+
+	eps, err := d.Endpoints.Endpoints(ctx, typ)
+	if err != nil {
+		return err
+	}
+
+	for _, ep := range eps {
+		p, err := json.Marshal(&job{EndpointID: ep.ID, Event: e})
+		if err != nil {
+			return err
+		}
+
+		if err = d.Queue.Enqueue(Topic, p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Handle sends the queued delivery, it's the queue handler of Topic. Failed
+// attempt is retried by enqueueing the job at the backoff (immediately when
+// the queue isn't queue.Delayer), after the last attempt the job is enqueued
+// into the dead letter queue. The failed delivery isn't returned as error,
+// so the queue doesn't redeliver the attempt that is already logged.
+func (d *Dispatcher) Handle(ctx context.Context, payload []byte) (err error) {
+	j := new(job)
+	if err = json.Unmarshal(payload, j); err != nil {
+		return fmt.Errorf("webhook: invalid queued delivery: %v", err)
+	}
+
+	ep, err := d.Endpoints.Endpoint(ctx, j.EndpointID)
+	if err != nil {
+		return err
+	}
+	if ep == nil || ep.Disabled {
+		return nil
+	}
+
+	body, err := json.Marshal(j.Event)
+	if err != nil {
+		return err
+	}
+
+	attempts := d.Retry.MaxAttempts
+	if attempts <= 0 {
+		attempts = 1
+	}
+	if j.Attempt < 1 {
+		j.Attempt = 1
+	}
+	last := j.Attempt >= attempts
+
+	if err = d.limiter(ep).wait(ctx); err != nil {
+		return err
+	}
+	if err = d.send(ctx, ep, j.Event, body, j.Attempt, last); err == nil {
+		return nil
+	}
+
+	if !last {
+		return d.retry(j)
+	}
+	if d.DeadLetter == nil {
+		return nil
+	}
+
+	// redelivered job starts from the first attempt
+	j.Attempt = 0
+	p, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+
+	return d.DeadLetter.Enqueue(DeadLetterTopic, p)
+}
+
+// retry enqueues the next attempt of the job after the backoff.
+func (d *Dispatcher) retry(j *job) error {
+	at := time.Now().Add(d.Retry.backoff(j.Attempt))
+	j.Attempt++
+
+	p, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+
+	if q, ok := d.Queue.(queue.Delayer); ok {
+		return q.EnqueueAt(Topic, p, at)
+	}
+
+	return d.Queue.Enqueue(Topic, p)
+}
+
+// send posts the event into the endpoint and logs the attempt,
+// the failed last attempt is logged as dead.
+func (d *Dispatcher) send(ctx context.Context, ep *Endpoint, e *Event, body []byte, attempt int, last bool) error {
+	dl := &Delivery{
+		ID:         newID(),
+		EventID:    e.ID,
+		EventType:  e.Type,
+		EndpointID: ep.ID,
+		URL:        ep.URL,
+		Attempt:    attempt,
+		Status:     StatusSuccess,
+		CreatedAt:  time.Now(),
+	}
+
+	ts := dl.CreatedAt.Unix()
+	res, err := d.Client.Post(ep.URL).
+		Context(ctx).
+		Header(HeaderID, e.ID).
+		Header(HeaderTimestamp, strconv.FormatInt(ts, 10)).
+		Header(HeaderSignature, Sign(ep.Secret, e.ID, ts, body)).
+		Header(HeaderEvent, e.Type).
+		Body(body, "application/json").
+		Do()
+	dl.Duration = time.Since(dl.CreatedAt)
+
+	if err == nil {
+		dl.StatusCode = res.StatusCode
+		if !res.IsSuccess() {
+			err = fmt.Errorf("webhook: endpoint responds %d", res.StatusCode)
+		}
+	}
+
+	if err != nil {
+		dl.Status, dl.Error = StatusFailed, err.Error()
+		if last {
+			dl.Status = StatusDead
+		}
+	}
+
+	d.save(ctx, dl)
+
+	return err
+}
+
+func (d *Dispatcher) save(ctx context.Context, dl *Delivery) {
+	if d.Log != nil {
+		d.Log.Save(ctx, dl)
+	}
+}
+
+// limiter returns rate limiter of the endpoint.
+func (d *Dispatcher) limiter(ep *Endpoint) *limiter {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	l, ok := d.limiters[ep.ID]
+	if !ok {
+		l = &limiter{}
+		d.limiters[ep.ID] = l
+	}
+	l.setRate(ep.RateLimit)
+
+	return l
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	b := p.MinBackoff << uint(attempt-1)
+	if b <= 0 || (p.MaxBackoff > 0 && b > p.MaxBackoff) {
+		b = p.MaxBackoff
+	}
+
+	return b
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,244 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/enigma-id/go/client"
+	"github.com/enigma-id/go/queue"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDispatch(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []*Event
+		failures int32
+	)
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if err := Verify("s3cr3t", r.Header, body, time.Minute); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		// first attempt fails
+		if atomic.AddInt32(&failures, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		e := new(Event)
+		json.Unmarshal(body, e)
+		mu.Lock()
+		received = append(received, e)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	eps := NewMemoryEndpoints(
+		&Endpoint{ID: "ep1", URL: srv.URL, Secret: "s3cr3t", Events: []string{"order.*"}},
+		&Endpoint{ID: "ep2", URL: srv.URL, Secret: "s3cr3t", Events: []string{"user.created"}},
+	)
+	log := NewMemoryLog(10)
+	q := queue.NewMemory(1)
+	d := New(eps, q, WithLog(log), WithClient(tlsClient(srv)), WithRetry(RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}))
+
+	assert.NoError(t, d.Dispatch(context.Background(), "order.paid", map[string]int{"id": 12}))
+	// the retry is enqueued at the backoff
+	assert.Eventually(t, func() bool {
+		ds, _ := log.Deliveries(context.Background(), Query{EndpointID: "ep1"})
+		return len(ds) == 2
+	}, time.Second, 5*time.Millisecond)
+	q.Close()
+
+	if assert.Len(t, received, 1) {
+		assert.Equal(t, "order.paid", received[0].Type)
+		assert.JSONEq(t, `{"id":12}`, string(received[0].Data))
+	}
+
+	ds, _ := log.Deliveries(context.Background(), Query{EndpointID: "ep1"})
+	if assert.Len(t, ds, 2) {
+		assert.Equal(t, StatusSuccess, ds[0].Status)
+		assert.Equal(t, 2, ds[0].Attempt)
+		assert.Equal(t, StatusFailed, ds[1].Status)
+		assert.Equal(t, http.StatusBadGateway, ds[1].StatusCode)
+	}
+}
+
+func TestDeadLetter(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	var dead []byte
+	dlq := queue.NewMemory(1)
+	dlq.Handle(DeadLetterTopic, func(ctx context.Context, payload []byte) error {
+		dead = payload
+		return nil
+	})
+
+	log := NewMemoryLog(10)
+	q := queue.NewMemory(1)
+	d := New(NewMemoryEndpoints(&Endpoint{ID: "ep1", URL: srv.URL, Events: []string{"*"}}), q,
+		WithLog(log), WithClient(tlsClient(srv)), WithDeadLetter(dlq), WithRetry(RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond}))
+
+	assert.NoError(t, d.Dispatch(context.Background(), "order.paid", nil))
+	assert.Eventually(t, func() bool {
+		ds, _ := log.Deliveries(context.Background(), Query{EndpointID: "ep1", Status: StatusDead})
+		return len(ds) == 1
+	}, time.Second, 5*time.Millisecond)
+	q.Close()
+	dlq.Close()
+
+	assert.Contains(t, string(dead), `"endpoint_id":"ep1"`)
+	assert.NotContains(t, string(dead), `"attempt"`)
+
+	ds, _ := log.Deliveries(context.Background(), Query{EndpointID: "ep1", Status: StatusDead})
+	if assert.Len(t, ds, 1) {
+		assert.Equal(t, 2, ds[0].Attempt)
+	}
+
+	// each attempt is logged once
+	ds, _ = log.Deliveries(context.Background(), Query{EndpointID: "ep1"})
+	assert.Len(t, ds, 2)
+}
+
+func TestHandle(t *testing.T) {
+	var posts int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&posts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	log := NewMemoryLog(10)
+	q := queue.NewMemory(1)
+	defer q.Close()
+	d := New(NewMemoryEndpoints(&Endpoint{ID: "ep1", URL: srv.URL, Events: []string{"*"}}), q,
+		WithLog(log), WithClient(tlsClient(srv)), WithRetry(RetryPolicy{MaxAttempts: 3, MinBackoff: time.Hour}))
+
+	// each call sends one attempt, the retry is scheduled into the queue
+	payload, _ := json.Marshal(&job{EndpointID: "ep1", Event: &Event{ID: "evt1", Type: "order.paid"}})
+	assert.NoError(t, d.Handle(context.Background(), payload))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&posts))
+
+	// the failed last attempt isn't returned, so the queue doesn't redeliver it
+	payload, _ = json.Marshal(&job{EndpointID: "ep1", Event: &Event{ID: "evt1", Type: "order.paid"}, Attempt: 3})
+	assert.NoError(t, d.Handle(context.Background(), payload))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&posts))
+
+	ds, _ := log.Deliveries(context.Background(), Query{EndpointID: "ep1"})
+	if assert.Len(t, ds, 2) {
+		assert.Equal(t, StatusDead, ds[0].Status)
+		assert.Equal(t, 3, ds[0].Attempt)
+		assert.Equal(t, StatusFailed, ds[1].Status)
+		assert.Equal(t, 1, ds[1].Attempt)
+	}
+}
+
+func TestGuard(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	payload, _ := json.Marshal(&job{EndpointID: "ep1", Event: &Event{ID: "evt1", Type: "order.paid"}})
+	deliver := func(opts ...Option) *Delivery {
+		log := NewMemoryLog(10)
+		q := queue.NewMemory(1)
+		defer q.Close()
+		opts = append(opts, WithLog(log), WithRetry(RetryPolicy{MaxAttempts: 1}))
+		d := New(NewMemoryEndpoints(&Endpoint{ID: "ep1", URL: srv.URL, Events: []string{"*"}}), q, opts...)
+		assert.NoError(t, d.Handle(context.Background(), payload))
+
+		ds, _ := log.Deliveries(context.Background(), Query{EndpointID: "ep1"})
+		if !assert.Len(t, ds, 1) {
+			return &Delivery{}
+		}
+		return ds[0]
+	}
+
+	// loopback is refused by the default client
+	assert.Contains(t, deliver().Error, ErrForbiddenAddress.Error())
+	// it's dialed when allowed, the certificate of the test server isn't trusted
+	assert.NotContains(t, deliver(WithPrivateNetwork()).Error, ErrForbiddenAddress.Error())
+	assert.Equal(t, StatusSuccess, deliver(WithClient(tlsClient(srv))).Status)
+
+	for _, ip := range []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "0.0.0.0", "::1", "fe80::1", "fd00::1", "::ffff:127.0.0.1"} {
+		assert.False(t, allowed(net.ParseIP(ip)), ip)
+	}
+	assert.True(t, allowed(net.ParseIP("93.184.216.34")))
+	assert.True(t, allowed(net.ParseIP("2606:2800:220:1::1")))
+}
+
+func TestEndpointValidate(t *testing.T) {
+	assert.NoError(t, (&Endpoint{URL: "https://example.com/hook"}).Validate())
+	assert.Equal(t, ErrInsecureURL, (&Endpoint{URL: "http://example.com/hook"}).Validate())
+	assert.Equal(t, ErrInsecureURL, (&Endpoint{URL: "https:///hook"}).Validate())
+	assert.Equal(t, ErrInsecureURL, (&Endpoint{URL: "file:///etc/passwd"}).Validate())
+
+	s := NewMemoryEndpoints()
+	assert.Equal(t, ErrInsecureURL, s.Add(&Endpoint{ID: "ep1", URL: "http://example.com/hook"}))
+	ep, _ := s.Endpoint(context.Background(), "ep1")
+	assert.Nil(t, ep)
+	assert.Panics(t, func() { NewMemoryEndpoints(&Endpoint{ID: "ep1", URL: "example.com"}) })
+}
+
+// tlsClient trusts the certificate of the test server.
+func tlsClient(srv *httptest.Server) *client.Client {
+	return client.New(client.WithTransport(srv.Client().Transport))
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	ts := time.Now().Unix()
+	h := http.Header{}
+	h.Set(HeaderID, "evt1")
+	h.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	h.Set(HeaderSignature, "v1=old "+Sign("secret", "evt1", ts, body))
+
+	assert.NoError(t, Verify("secret", h, body, time.Minute))
+	assert.Equal(t, ErrInvalidSignature, Verify("other", h, body, time.Minute))
+	assert.Equal(t, ErrInvalidSignature, Verify("secret", h, []byte(`{"id":"2"}`), time.Minute))
+
+	old := time.Now().Add(-time.Hour).Unix()
+	h.Set(HeaderTimestamp, strconv.FormatInt(old, 10))
+	h.Set(HeaderSignature, Sign("secret", "evt1", old, body))
+	assert.Equal(t, ErrInvalidSignature, Verify("secret", h, body, time.Minute))
+	assert.NoError(t, Verify("secret", h, body, 0))
+}
+
+func TestLimiter(t *testing.T) {
+	l := &limiter{}
+	l.setRate(20)
+
+	start := time.Now()
+	for i := 0; i < 25; i++ {
+		assert.NoError(t, l.wait(context.Background()))
+	}
+	assert.True(t, time.Since(start) >= 200*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	l.setRate(1)
+	l.wait(ctx)
+	assert.Equal(t, context.Canceled, l.wait(ctx))
+}
+
+func TestSubscribes(t *testing.T) {
+	ep := &Endpoint{Events: []string{"order.*", "user.created"}}
+	assert.True(t, ep.Subscribes("order.paid"))
+	assert.True(t, ep.Subscribes("user.created"))
+	assert.False(t, ep.Subscribes("user.deleted"))
+	assert.False(t, ep.Subscribes("orders"))
+}